@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: developer/v1/developer_internal.proto
+
+package developerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Scope         string                 `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	ClientIp      string                 `protobuf:"bytes,3,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	Origin        string                 `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyAPIKeyRequest) Reset() {
+	*x = VerifyAPIKeyRequest{}
+	mi := &file_developer_v1_developer_internal_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyAPIKeyRequest) ProtoMessage() {}
+
+func (x *VerifyAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_developer_v1_developer_internal_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_developer_v1_developer_internal_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyAPIKeyRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *VerifyAPIKeyRequest) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *VerifyAPIKeyRequest) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
+func (x *VerifyAPIKeyRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+type VerifyAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	DeveloperId   string                 `protobuf:"bytes,3,opt,name=developer_id,json=developerId,proto3" json:"developer_id,omitempty"`
+	Tier          string                 `protobuf:"bytes,4,opt,name=tier,proto3" json:"tier,omitempty"`
+	Scopes        []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	DailyQuota    int64                  `protobuf:"varint,7,opt,name=daily_quota,json=dailyQuota,proto3" json:"daily_quota,omitempty"`
+	DailyUsage    int64                  `protobuf:"varint,8,opt,name=daily_usage,json=dailyUsage,proto3" json:"daily_usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyAPIKeyResponse) Reset() {
+	*x = VerifyAPIKeyResponse{}
+	mi := &file_developer_v1_developer_internal_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyAPIKeyResponse) ProtoMessage() {}
+
+func (x *VerifyAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_developer_v1_developer_internal_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_developer_v1_developer_internal_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifyAPIKeyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyAPIKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *VerifyAPIKeyResponse) GetDeveloperId() string {
+	if x != nil {
+		return x.DeveloperId
+	}
+	return ""
+}
+
+func (x *VerifyAPIKeyResponse) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *VerifyAPIKeyResponse) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *VerifyAPIKeyResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *VerifyAPIKeyResponse) GetDailyQuota() int64 {
+	if x != nil {
+		return x.DailyQuota
+	}
+	return 0
+}
+
+func (x *VerifyAPIKeyResponse) GetDailyUsage() int64 {
+	if x != nil {
+		return x.DailyUsage
+	}
+	return 0
+}
+
+var File_developer_v1_developer_internal_proto protoreflect.FileDescriptor
+
+const file_developer_v1_developer_internal_proto_rawDesc = "" +
+	"\n" +
+	"%developer/v1/developer_internal.proto\x12\x17viralforge.developer.v1\"y\n" +
+	"\x13VerifyAPIKeyRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x14\n" +
+	"\x05scope\x18\x02 \x01(\tR\x05scope\x12\x1b\n" +
+	"\tclient_ip\x18\x03 \x01(\tR\bclientIp\x12\x16\n" +
+	"\x06origin\x18\x04 \x01(\tR\x06origin\"\xf3\x01\n" +
+	"\x14VerifyAPIKeyResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12!\n" +
+	"\fdeveloper_id\x18\x03 \x01(\tR\vdeveloperId\x12\x12\n" +
+	"\x04tier\x18\x04 \x01(\tR\x04tier\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\x12\x1f\n" +
+	"\vdaily_quota\x18\a \x01(\x03R\n" +
+	"dailyQuota\x12\x1f\n" +
+	"\vdaily_usage\x18\b \x01(\x03R\n" +
+	"dailyUsage2\x87\x01\n" +
+	"\x18DeveloperInternalService\x12k\n" +
+	"\fVerifyAPIKey\x12,.viralforge.developer.v1.VerifyAPIKeyRequest\x1a-.viralforge.developer.v1.VerifyAPIKeyResponseBEZCgithub.com/viralforge/mesh/contracts/proto/developer/v1;developerv1b\x06proto3"
+
+var (
+	file_developer_v1_developer_internal_proto_rawDescOnce sync.Once
+	file_developer_v1_developer_internal_proto_rawDescData []byte
+)
+
+func file_developer_v1_developer_internal_proto_rawDescGZIP() []byte {
+	file_developer_v1_developer_internal_proto_rawDescOnce.Do(func() {
+		file_developer_v1_developer_internal_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_developer_v1_developer_internal_proto_rawDesc), len(file_developer_v1_developer_internal_proto_rawDesc)))
+	})
+	return file_developer_v1_developer_internal_proto_rawDescData
+}
+
+var file_developer_v1_developer_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_developer_v1_developer_internal_proto_goTypes = []any{
+	(*VerifyAPIKeyRequest)(nil),  // 0: viralforge.developer.v1.VerifyAPIKeyRequest
+	(*VerifyAPIKeyResponse)(nil), // 1: viralforge.developer.v1.VerifyAPIKeyResponse
+}
+var file_developer_v1_developer_internal_proto_depIdxs = []int32{
+	0, // 0: viralforge.developer.v1.DeveloperInternalService.VerifyAPIKey:input_type -> viralforge.developer.v1.VerifyAPIKeyRequest
+	1, // 1: viralforge.developer.v1.DeveloperInternalService.VerifyAPIKey:output_type -> viralforge.developer.v1.VerifyAPIKeyResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_developer_v1_developer_internal_proto_init() }
+func file_developer_v1_developer_internal_proto_init() {
+	if File_developer_v1_developer_internal_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_developer_v1_developer_internal_proto_rawDesc), len(file_developer_v1_developer_internal_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_developer_v1_developer_internal_proto_goTypes,
+		DependencyIndexes: file_developer_v1_developer_internal_proto_depIdxs,
+		MessageInfos:      file_developer_v1_developer_internal_proto_msgTypes,
+	}.Build()
+	File_developer_v1_developer_internal_proto = out.File
+	file_developer_v1_developer_internal_proto_goTypes = nil
+	file_developer_v1_developer_internal_proto_depIdxs = nil
+}
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ClientConfig describes a client connection. Retries are performed by the
+// gRPC channel for the codes in RetryableCodes; callers that need circuit
+// breaking or hedging add resiliency interceptors through DialOptions.
+type ClientConfig struct {
+	// Target is a gRPC target such as "dns:///m01-auth:9090". Use the dns
+	// scheme so round_robin balances across every resolved address.
+	Target string
+	// LoadBalancing is the channel load-balancing policy; defaults to
+	// round_robin.
+	LoadBalancing string
+	// MaxAttempts includes the first call; 1 disables retries. Defaults to 3.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RetryableCodes defaults to Unavailable.
+	RetryableCodes []codes.Code
+	// TLS enables mTLS with hot-reloaded files; ServerName overrides the name
+	// verified against the server certificate.
+	TLS        *TLSConfig
+	ServerName string
+	// DialOptions are appended to the options built from this config.
+	DialOptions []grpc.DialOption
+}
+
+func (c ClientConfig) withDefaults() ClientConfig {
+	if c.LoadBalancing == "" {
+		c.LoadBalancing = "round_robin"
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 100 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 2 * time.Second
+	}
+	if len(c.RetryableCodes) == 0 {
+		c.RetryableCodes = []codes.Code{codes.Unavailable}
+	}
+	return c
+}
+
+// ServiceConfigJSON renders the load-balancing and retry policy of cfg as a
+// gRPC service config applied to every method.
+func ServiceConfigJSON(cfg ClientConfig) string {
+	cfg = cfg.withDefaults()
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []map[string]string `json:"name"`
+		RetryPolicy *retryPolicy        `json:"retryPolicy,omitempty"`
+	}
+	method := methodConfig{Name: []map[string]string{{}}}
+	if cfg.MaxAttempts > 1 {
+		statusCodes := make([]string, 0, len(cfg.RetryableCodes))
+		for _, code := range cfg.RetryableCodes {
+			statusCodes = append(statusCodes, codeName(code))
+		}
+		method.RetryPolicy = &retryPolicy{
+			MaxAttempts:          cfg.MaxAttempts,
+			InitialBackoff:       seconds(cfg.InitialBackoff),
+			MaxBackoff:           seconds(cfg.MaxBackoff),
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: statusCodes,
+		}
+	}
+	raw, _ := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]any{{cfg.LoadBalancing: map[string]any{}}},
+		"methodConfig":        []methodConfig{method},
+	})
+	return string(raw)
+}
+
+// Dial creates a lazily connecting client with tracing, request ID
+// propagation, retries and load balancing. The returned connection is safe
+// for concurrent use and must be closed by the caller.
+func Dial(cfg ClientConfig) (*grpc.ClientConn, error) {
+	cfg = cfg.withDefaults()
+	if strings.TrimSpace(cfg.Target) == "" {
+		return nil, fmt.Errorf("grpc dial: target is required")
+	}
+	creds := insecure.NewCredentials()
+	if cfg.TLS != nil {
+		certs, err := NewCertReloader(*cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(certs.ClientTLSConfig(cfg.ServerName))
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(ServiceConfigJSON(cfg)),
+		grpc.WithChainUnaryInterceptor(UnaryClientRequestID()),
+		grpc.WithChainStreamInterceptor(StreamClientRequestID()),
+	}
+	opts = append(opts, observability.DialOptions()...)
+	return grpc.NewClient(cfg.Target, append(opts, cfg.DialOptions...)...)
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+// codeName returns the upper snake case name the service config expects.
+func codeName(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
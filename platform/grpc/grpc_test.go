@@ -0,0 +1,256 @@
+package grpc_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	platformgrpc "github.com/viralforge/mesh/platform/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// echoDesc is a hand-written service reusing the health messages: "panic"
+// panics, "whoami" returns SERVING only for the authenticated subject.
+var echoDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Check",
+		Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(healthpb.HealthCheckRequest)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				switch req.(*healthpb.HealthCheckRequest).GetService() {
+				case "panic":
+					panic("boom")
+				case "whoami":
+					if ctx.Value(subjectKey{}) != "svc-a" {
+						return nil, status.Error(codes.Internal, "missing subject")
+					}
+				}
+				return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+			}
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test.Echo/Check"}, handler)
+		},
+	}},
+}
+
+type subjectKey struct{}
+
+func startServer(t *testing.T, cfg platformgrpc.ServerConfig) (*platformgrpc.Server, *grpc.ClientConn) {
+	t.Helper()
+	cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	server, err := platformgrpc.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	server.GRPC().RegisterService(&echoDesc, struct{}{})
+	lis := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = server.Serve(ctx, lis)
+		close(done)
+	}()
+	conn, err := platformgrpc.Dial(platformgrpc.ClientConfig{
+		Target: "passthrough:///bufnet",
+		DialOptions: []grpc.DialOption{grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		})},
+	})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		cancel()
+		<-done
+	})
+	return server, conn
+}
+
+func TestHealthFollowsReadiness(t *testing.T) {
+	var failing atomic.Bool
+	server, conn := startServer(t, platformgrpc.ServerConfig{
+		ServiceName: "test",
+		Readiness: func(context.Context) error {
+			if failing.Load() {
+				return errors.New("db down")
+			}
+			return nil
+		},
+		ReadinessInterval: 10 * time.Millisecond,
+	})
+	client := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+	for _, service := range []string{"", "test.Echo"} {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("expected %q SERVING, got %v err=%v", service, resp.GetStatus(), err)
+		}
+	}
+
+	failing.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for server.Ready() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "test.Echo"})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING after readiness failure, got %v err=%v", resp.GetStatus(), err)
+	}
+}
+
+func TestReflectionListsServicesWhenEnabled(t *testing.T) {
+	_, conn := startServer(t, platformgrpc.ServerConfig{ServiceName: "test", Reflection: true})
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("reflection stream: %v", err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+	var names []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		names = append(names, svc.GetName())
+	}
+	if joined := strings.Join(names, ","); !strings.Contains(joined, "test.Echo") || !strings.Contains(joined, "grpc.health.v1.Health") {
+		t.Fatalf("unexpected services: %v", names)
+	}
+}
+
+func TestInterceptorsAuthenticateRecoverAndTagRequests(t *testing.T) {
+	_, conn := startServer(t, platformgrpc.ServerConfig{
+		ServiceName: "test",
+		Authenticator: func(ctx context.Context, _ string) (context.Context, error) {
+			if platformgrpc.BearerToken(ctx) != "good" {
+				return ctx, errors.New("bad token")
+			}
+			return context.WithValue(ctx, subjectKey{}, "svc-a"), nil
+		},
+	})
+	call := func(ctx context.Context, service string, header *metadata.MD) error {
+		return conn.Invoke(ctx, "/test.Echo/Check", &healthpb.HealthCheckRequest{Service: service}, new(healthpb.HealthCheckResponse), grpc.Header(header))
+	}
+
+	var header metadata.MD
+	if err := call(context.Background(), "whoami", &header); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	authed := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer good")
+	if err := call(platformgrpc.ContextWithRequestID(authed, "req-42"), "whoami", &header); err != nil {
+		t.Fatalf("authenticated call: %v", err)
+	}
+	if got := header.Get(platformgrpc.RequestIDHeader); len(got) != 1 || got[0] != "req-42" {
+		t.Fatalf("expected propagated request id, got %v", got)
+	}
+
+	if err := call(authed, "panic", &header); status.Code(err) != codes.Internal {
+		t.Fatalf("expected panic to map to Internal, got %v", err)
+	}
+	if got := header.Get(platformgrpc.RequestIDHeader); len(got) != 1 || got[0] == "" {
+		t.Fatalf("expected generated request id, got %v", got)
+	}
+
+	health := healthpb.NewHealthClient(conn)
+	if _, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("health must not require auth: %v", err)
+	}
+}
+
+func TestServiceConfigJSONRetriesAndBalances(t *testing.T) {
+	raw := platformgrpc.ServiceConfigJSON(platformgrpc.ClientConfig{
+		MaxAttempts:    4,
+		RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+	})
+	for _, want := range []string{`"round_robin"`, `"maxAttempts":4`, `"UNAVAILABLE"`, `"RESOURCE_EXHAUSTED"`, `"initialBackoff":"0.100s"`} {
+		if !strings.Contains(raw, want) {
+			t.Fatalf("service config %s missing %s", raw, want)
+		}
+	}
+}
+
+func TestCertReloaderPicksUpRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeSelfSigned(t, certFile, keyFile, "first")
+	reloader, err := platformgrpc.NewCertReloader(platformgrpc.TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	first := reloader.Certificate().Certificate[0]
+
+	writeSelfSigned(t, certFile, keyFile, "second")
+	future := time.Now().Add(time.Minute)
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.Chtimes(path, future, future); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	second := reloader.Certificate().Certificate[0]
+	if bytes.Equal(first, second) || reloader.LastError() != nil {
+		t.Fatalf("expected rotated certificate, err=%v", reloader.LastError())
+	}
+	leaf, err := x509.ParseCertificate(second)
+	if err != nil || leaf.Subject.CommonName != "second" {
+		t.Fatalf("unexpected certificate %v err=%v", leaf.Subject, err)
+	}
+}
+
+func writeSelfSigned(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the metadata key carrying the request ID, matching the
+// X-Request-Id HTTP header used by the REST surfaces.
+const RequestIDHeader = "x-request-id"
+
+// Authenticator verifies the caller of fullMethod and returns the context the
+// handler runs with, typically enriched with the caller identity. Errors
+// without a gRPC status are reported as Unauthenticated.
+type Authenticator func(ctx context.Context, fullMethod string) (context.Context, error)
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID assigned by the server
+// interceptor or propagated by the client interceptor.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextWithRequestID sets the request ID outgoing calls propagate.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// BearerToken returns the token of the "authorization: Bearer ..." metadata.
+func BearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// interceptor applies the per-call policy shared by unary and stream calls.
+type interceptor struct {
+	cfg    ServerConfig
+	public map[string]bool
+}
+
+func newInterceptor(cfg ServerConfig) *interceptor {
+	public := map[string]bool{}
+	for _, method := range cfg.PublicMethods {
+		public[method] = true
+	}
+	return &interceptor{cfg: cfg, public: public}
+}
+
+func (i *interceptor) withRequestID(ctx context.Context) context.Context {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 {
+			id = strings.TrimSpace(values[0])
+		}
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+	return ContextWithRequestID(ctx, id)
+}
+
+func (i *interceptor) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok && i.cfg.DefaultTimeout > 0:
+		return context.WithTimeout(ctx, i.cfg.DefaultTimeout)
+	case i.cfg.MaxTimeout > 0 && (!ok || time.Until(deadline) > i.cfg.MaxTimeout):
+		return context.WithTimeout(ctx, i.cfg.MaxTimeout)
+	}
+	return ctx, func() {}
+}
+
+func (i *interceptor) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if i.cfg.Authenticator == nil || i.public[fullMethod] || isInfrastructureMethod(fullMethod) {
+		return ctx, nil
+	}
+	authed, err := i.cfg.Authenticator(ctx, fullMethod)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return ctx, err
+		}
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return authed, nil
+}
+
+func (i *interceptor) recovered(ctx context.Context, fullMethod string, r any) error {
+	i.cfg.Logger.ErrorContext(ctx, "grpc handler panic",
+		"service", i.cfg.ServiceName,
+		"method", fullMethod,
+		"request_id", RequestIDFromContext(ctx),
+		"panic", r,
+		"stack", string(debug.Stack()),
+	)
+	return status.Error(codes.Internal, "internal error")
+}
+
+func (i *interceptor) log(ctx context.Context, fullMethod string, start time.Time, err error) {
+	if isInfrastructureMethod(fullMethod) {
+		return
+	}
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition:
+	default:
+		level = slog.LevelError
+	}
+	i.cfg.Logger.Log(ctx, level, "grpc call",
+		"service", i.cfg.ServiceName,
+		"method", fullMethod,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", RequestIDFromContext(ctx),
+	)
+}
+
+// UnaryClientRequestID forwards the request ID of ctx, when present, as
+// x-request-id metadata.
+func UnaryClientRequestID() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientRequestID is the streaming counterpart of UnaryClientRequestID.
+func StreamClientRequestID() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(RequestIDHeader)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, RequestIDHeader, id)
+}
+
+func isInfrastructureMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.") || strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,230 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server is a gRPC server with health, reflection and the shared interceptor
+// chain installed. Register services on GRPC() before calling Serve.
+type Server struct {
+	cfg    ServerConfig
+	grpc   *grpc.Server
+	health *health.Server
+
+	mu    sync.Mutex
+	ready bool
+}
+
+// NewServer builds the server described by cfg. Interceptors run in the
+// order recovery and request ID, tracing and RED metrics, deadline,
+// authentication, handler; the access log is written last.
+func NewServer(cfg ServerConfig) (*Server, error) {
+	cfg = cfg.withDefaults()
+	s := &Server{cfg: cfg, health: health.NewServer()}
+	chain := newInterceptor(cfg)
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(outerUnary(chain), observability.UnaryServerInterceptor(), innerUnary(chain)),
+		grpc.ChainStreamInterceptor(outerStream(chain), observability.StreamServerInterceptor(), innerStream(chain)),
+	}
+	if cfg.TLS != nil {
+		certs, err := NewCertReloader(*cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.ServerTLSConfig())))
+	}
+	s.grpc = grpc.NewServer(append(opts, cfg.ServerOptions...)...)
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	if cfg.Reflection {
+		reflection.Register(s.grpc)
+	}
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return s, nil
+}
+
+// GRPC returns the underlying server for service registration.
+func (s *Server) GRPC() *grpc.Server { return s.grpc }
+
+// SetReady reports the server and every registered service as SERVING or
+// NOT_SERVING.
+func (s *Server) SetReady(ready bool) {
+	s.mu.Lock()
+	s.ready = ready
+	s.mu.Unlock()
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus("", status)
+	for name := range s.grpc.GetServiceInfo() {
+		if name != healthpb.Health_ServiceDesc.ServiceName {
+			s.health.SetServingStatus(name, status)
+		}
+	}
+}
+
+// Ready reports the last readiness passed to SetReady.
+func (s *Server) Ready() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ready
+}
+
+// ListenAndServe listens on cfg.Addr and serves until ctx is done or the
+// process receives SIGINT or SIGTERM.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves on lis until ctx is done, then drains: health turns
+// NOT_SERVING, the server waits DrainDelay and stops gracefully within
+// ShutdownTimeout.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if s.cfg.Readiness == nil {
+		s.SetReady(true)
+	} else {
+		s.checkReadiness(runCtx)
+		go s.watchReadiness(runCtx)
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.grpc.Serve(lis) }()
+	s.cfg.Logger.InfoContext(ctx, "grpc server started", "service", s.cfg.ServiceName, "addr", lis.Addr().String(), "reflection", s.cfg.Reflection)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, grpc.ErrServerStopped) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+	cancel()
+	s.Shutdown()
+	return nil
+}
+
+// Shutdown drains and stops the server.
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	s.ready = false
+	s.mu.Unlock()
+	s.health.Shutdown()
+	if s.cfg.DrainDelay > 0 {
+		time.Sleep(s.cfg.DrainDelay)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.cfg.ShutdownTimeout):
+		s.grpc.Stop()
+		<-done
+	}
+	s.cfg.Logger.Info("grpc server stopped", "service", s.cfg.ServiceName)
+}
+
+func (s *Server) watchReadiness(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ReadinessInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkReadiness(ctx)
+		}
+	}
+}
+
+func (s *Server) checkReadiness(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ReadinessInterval)
+	defer cancel()
+	err := s.cfg.Readiness(ctx)
+	if ready := err == nil; ready != s.Ready() {
+		if err != nil {
+			s.cfg.Logger.Warn("grpc server not ready", "service", s.cfg.ServiceName, "error", err)
+		}
+		s.SetReady(ready)
+	}
+}
+
+// The interceptor policy is split around the observability interceptor so
+// spans carry the request ID and see recovered panics as Internal, while
+// deadline and auth failures are still traced.
+func outerUnary(i *interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+		ctx = i.withRequestID(ctx)
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ctx, info.FullMethod, r)
+			}
+			i.log(ctx, info.FullMethod, start, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func innerUnary(i *interceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := i.withDeadline(ctx)
+		defer cancel()
+		ctx, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func outerStream(i *interceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		ctx := i.withRequestID(ss.Context())
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ctx, info.FullMethod, r)
+			}
+			i.log(ctx, info.FullMethod, start, err)
+		}()
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func innerStream(i *interceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := i.withDeadline(ss.Context())
+		defer cancel()
+		ctx, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,56 @@
+package observability
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceparentKey = "traceparent"
+
+// TraceParent returns the W3C traceparent of the span in ctx, suitable for
+// the TraceID field of an EventEnvelope. fallback is returned when ctx
+// carries no valid span.
+func TraceParent(ctx context.Context, fallback string) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if tp := carrier.Get(traceparentKey); tp != "" {
+		return tp
+	}
+	return fallback
+}
+
+// ContextWithTraceParent returns ctx continuing the trace encoded in an
+// EventEnvelope TraceID. Values that are not a W3C traceparent, such as
+// legacy request IDs, leave ctx unchanged.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	traceParent = strings.TrimSpace(traceParent)
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceparentKey: traceParent})
+}
+
+// StartEventSpan opens a consumer span for an event whose envelope carries
+// traceID. Callers must finish the span with EndEventSpan.
+func StartEventSpan(ctx context.Context, eventType, traceID string) (context.Context, trace.Span) {
+	ctx = ContextWithTraceParent(ctx, traceID)
+	attrs := []attribute.KeyValue{attribute.String("messaging.operation.type", "process"), attribute.String("messaging.destination.name", eventType)}
+	if !trace.SpanContextFromContext(ctx).IsRemote() && traceID != "" {
+		attrs = append(attrs, attribute.String("messaging.envelope.trace_id", traceID))
+	}
+	return tracer().Start(ctx, "process "+eventType, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...))
+}
+
+// EndEventSpan records err on span and ends it.
+func EndEventSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,137 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPMiddleware returns server middleware that continues the caller's
+// traceparent, opens a server span named after the chi route pattern and
+// records RED metrics. It may wrap a chi router or http.ServeMux from the
+// outside or be installed with Router.Use.
+func HTTPMiddleware(service string) func(http.Handler) http.Handler {
+	red := newREDMetrics("http.server")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			// Seed a chi routing context when wrapping the router from the
+			// outside so that the matched pattern is visible after serving.
+			rctx := chi.RouteContext(ctx)
+			if rctx == nil {
+				rctx = chi.NewRouteContext()
+				ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+			}
+
+			ctx, span := tracer().Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+				attribute.String("service.name", service),
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			))
+			defer span.End()
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			req := r.WithContext(ctx)
+			next.ServeHTTP(rec, req)
+
+			route := rctx.RoutePattern()
+			if route == "" {
+				// net/http.ServeMux records its match on the request itself.
+				route = req.Pattern
+			}
+			if route == "" {
+				route = "unmatched"
+			}
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route), attribute.Int("http.response.status_code", rec.status))
+			if rec.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rec.status))
+			}
+			red.record(ctx, start, rec.status >= http.StatusInternalServerError,
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("http.response.status_code", strconv.Itoa(rec.status)),
+			)
+		})
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// Transport is an http.RoundTripper that opens client spans, records RED
+// metrics and injects traceparent into outbound requests.
+type Transport struct {
+	Base http.RoundTripper
+	red  *redMetrics
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil).
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, red: newREDMetrics("http.client")}
+}
+
+// WrapClient returns a shallow copy of client with an instrumented transport.
+func WrapClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	wrapped := *client
+	wrapped.Transport = NewTransport(client.Transport)
+	return &wrapped
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	ctx, span := tracer().Start(req.Context(), req.Method+" "+req.URL.Host, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.String("url.full", req.URL.Redacted()),
+	))
+	defer span.End()
+
+	out := req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(out.Header))
+	resp, err := t.Base.RoundTrip(out)
+	status := "error"
+	failed := err != nil
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		status = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			failed = true
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+	}
+	t.red.record(ctx, start, failed,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.String("http.response.status_code", status),
+	)
+	return resp, err
+}
@@ -1,3 +1,244 @@
+// Package observability contains shared technical primitives for mesh services.
+//
+// Setup configures OpenTelemetry tracing and metrics for a service and
+// installs the W3C trace-context propagator. The package then provides chi
+// middleware, net/http client transports and gRPC interceptors that create
+// spans and RED metrics, helpers that carry traceparent through event
+// envelopes, and a log/slog handler that stamps trace and span IDs on every
+// record.
 package observability
 
-// Package observability contains shared technical primitives for mesh services.
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/viralforge/mesh/platform/observability"
+
+// Exporter selects where a signal is sent.
+type Exporter string
+
+const (
+	ExporterNone   Exporter = "none"
+	ExporterOTLP   Exporter = "otlp"
+	ExporterStdout Exporter = "console"
+)
+
+// Config describes the telemetry pipeline of one service.
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+	// TracesExporter sends spans to the OTLP endpoint of M80 Tracing or stdout.
+	TracesExporter Exporter
+	// MetricsExporter sends metrics to the OTLP endpoint of M79 Monitoring or stdout.
+	MetricsExporter Exporter
+	// TracesEndpoint and MetricsEndpoint are OTLP/gRPC host:port targets. Empty
+	// values defer to the standard OTEL_EXPORTER_OTLP_* environment variables.
+	TracesEndpoint  string
+	MetricsEndpoint string
+	// Insecure disables TLS to the collector. It is opt-in through
+	// OTEL_EXPORTER_OTLP_INSECURE=true.
+	Insecure bool
+	// SampleRatio is the fraction of new root traces that are sampled.
+	SampleRatio    float64
+	MetricInterval time.Duration
+}
+
+// ConfigFromEnv builds a Config from the standard OTEL_* environment
+// variables. Exporting is disabled unless an exporter or OTLP endpoint is set.
+// An empty serviceVersion falls back to SERVICE_VERSION.
+func ConfigFromEnv(serviceName, serviceVersion string) Config {
+	if serviceVersion == "" {
+		serviceVersion = os.Getenv("SERVICE_VERSION")
+	}
+	cfg := Config{
+		ServiceName:     serviceName,
+		ServiceVersion:  serviceVersion,
+		Environment:     os.Getenv("DEPLOYMENT_ENVIRONMENT"),
+		TracesExporter:  ExporterNone,
+		MetricsExporter: ExporterNone,
+		TracesEndpoint:  os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		MetricsEndpoint: os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"),
+		Insecure:        strings.EqualFold(strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_INSECURE")), "true"),
+		SampleRatio:     1,
+		MetricInterval:  30 * time.Second,
+	}
+	if endpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); endpoint != "" {
+		cfg.TracesExporter = ExporterOTLP
+		cfg.MetricsExporter = ExporterOTLP
+	}
+	if cfg.TracesEndpoint != "" {
+		cfg.TracesExporter = ExporterOTLP
+	}
+	if cfg.MetricsEndpoint != "" {
+		cfg.MetricsExporter = ExporterOTLP
+	}
+	if raw := strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER")); raw != "" {
+		cfg.TracesExporter = Exporter(strings.ToLower(raw))
+	}
+	if raw := strings.TrimSpace(os.Getenv("OTEL_METRICS_EXPORTER")); raw != "" {
+		cfg.MetricsExporter = Exporter(strings.ToLower(raw))
+	}
+	if raw := strings.TrimSpace(os.Getenv("OTEL_TRACES_SAMPLER_ARG")); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil && v >= 0 && v <= 1 {
+			cfg.SampleRatio = v
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("OTEL_METRIC_EXPORT_INTERVAL")); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			cfg.MetricInterval = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return cfg
+}
+
+// Telemetry owns the providers created by Setup.
+type Telemetry struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	tracer         trace.Tracer
+	meter          metric.Meter
+}
+
+// Setup installs global tracer and meter providers and the W3C
+// trace-context propagator. Callers must Shutdown the result to flush
+// pending telemetry.
+func Setup(ctx context.Context, cfg Config) (*Telemetry, error) {
+	if strings.TrimSpace(cfg.ServiceName) == "" {
+		return nil, errors.New("observability: service name is required")
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironmentName(cfg.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("observability: build resource: %w", err)
+	}
+
+	traceOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+	spanExporter, err := newSpanExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if spanExporter != nil {
+		traceOpts = append(traceOpts, sdktrace.WithBatcher(spanExporter))
+	}
+	tp := sdktrace.NewTracerProvider(traceOpts...)
+
+	meterOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	metricExporter, err := newMetricExporter(ctx, cfg)
+	if err != nil {
+		_ = tp.Shutdown(ctx)
+		return nil, err
+	}
+	if metricExporter != nil {
+		interval := cfg.MetricInterval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		meterOpts = append(meterOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(interval))))
+	}
+	mp := sdkmetric.NewMeterProvider(meterOpts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(Propagator())
+
+	return &Telemetry{
+		tracerProvider: tp,
+		meterProvider:  mp,
+		tracer:         tp.Tracer(instrumentationName),
+		meter:          mp.Meter(instrumentationName),
+	}, nil
+}
+
+// Propagator returns the W3C trace-context and baggage propagator used by mesh.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Tracer returns the service tracer.
+func (t *Telemetry) Tracer() trace.Tracer { return t.tracer }
+
+// Meter returns the service meter.
+func (t *Telemetry) Meter() metric.Meter { return t.meter }
+
+// Shutdown flushes and stops the tracer and meter providers.
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return errors.Join(t.tracerProvider.Shutdown(ctx), t.meterProvider.Shutdown(ctx))
+}
+
+func newSpanExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.TracesExporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterStdout, "stdout":
+		return stdouttrace.New()
+	case ExporterOTLP:
+		var opts []otlptracegrpc.Option
+		if cfg.TracesEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(stripScheme(cfg.TracesEndpoint)))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("observability: unsupported traces exporter %q", cfg.TracesExporter)
+	}
+}
+
+func newMetricExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	switch cfg.MetricsExporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterStdout, "stdout":
+		return stdoutmetric.New()
+	case ExporterOTLP:
+		var opts []otlpmetricgrpc.Option
+		if cfg.MetricsEndpoint != "" {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(stripScheme(cfg.MetricsEndpoint)))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("observability: unsupported metrics exporter %q", cfg.MetricsExporter)
+	}
+}
+
+func stripScheme(endpoint string) string {
+	endpoint = strings.TrimSpace(endpoint)
+	for _, prefix := range []string{"http://", "https://", "grpc://"} {
+		endpoint = strings.TrimPrefix(endpoint, prefix)
+	}
+	return endpoint
+}
@@ -0,0 +1,116 @@
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/viralforge/mesh/platform/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setup(t *testing.T) {
+	t.Helper()
+	telemetry, err := observability.Setup(context.Background(), observability.Config{ServiceName: "test-service", SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	t.Cleanup(func() { _ = telemetry.Shutdown(context.Background()) })
+}
+
+func TestHTTPMiddlewareContinuesTraceParent(t *testing.T) {
+	setup(t)
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var seen trace.SpanContext
+	router := chi.NewRouter()
+	router.Get("/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		seen = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := observability.HTTPMiddleware("test-service")(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/items/42", nil)
+	req.Header.Set("traceparent", parent)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected routed request, got %d", rec.Code)
+	}
+	if seen.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected inbound trace to continue, got %s", seen.TraceID())
+	}
+}
+
+func TestTransportInjectsTraceParent(t *testing.T) {
+	setup(t)
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := observability.StartEventSpan(context.Background(), "cache.invalidated", "")
+	defer span.End()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := observability.WrapClient(server.Client()).Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if !strings.Contains(got, span.SpanContext().TraceID().String()) {
+		t.Fatalf("expected traceparent with trace %s, got %q", span.SpanContext().TraceID(), got)
+	}
+}
+
+func TestEnvelopeTraceParentRoundTrip(t *testing.T) {
+	setup(t)
+	ctx, producer := observability.StartEventSpan(context.Background(), "team.created", "")
+	traceParent := observability.TraceParent(ctx, "req-1")
+	producer.End()
+
+	_, consumer := observability.StartEventSpan(context.Background(), "team.created", traceParent)
+	defer consumer.End()
+	if consumer.SpanContext().TraceID() != producer.SpanContext().TraceID() {
+		t.Fatalf("expected consumer span to join producer trace")
+	}
+	if got := observability.TraceParent(context.Background(), "req-1"); got != "req-1" {
+		t.Fatalf("expected fallback without span, got %q", got)
+	}
+}
+
+func TestLogHandlerAddsTraceIDs(t *testing.T) {
+	setup(t)
+	var buf bytes.Buffer
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+	ctx, span := observability.StartEventSpan(context.Background(), "team.created", "")
+	logger.InfoContext(ctx, "handled")
+	span.End()
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log: %v", err)
+	}
+	if record["trace_id"] != span.SpanContext().TraceID().String() || record["span_id"] != span.SpanContext().SpanID().String() {
+		t.Fatalf("expected trace and span ids in %v", record)
+	}
+}
+
+func TestConfigFromEnvDefaultsToTLS(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "collector:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	if cfg := observability.ConfigFromEnv("test-service", "v1"); cfg.Insecure {
+		t.Fatalf("expected TLS by default")
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	if cfg := observability.ConfigFromEnv("test-service", "v1"); !cfg.Insecure {
+		t.Fatalf("expected insecure transport when opted in")
+	}
+}
@@ -0,0 +1,41 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redMetrics records rate, errors and duration for one kind of operation.
+// Instruments come from the global meter provider, which forwards to the
+// provider installed by Setup even when created before it.
+type redMetrics struct {
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+func newREDMetrics(prefix string) *redMetrics {
+	meter := otel.GetMeterProvider().Meter(instrumentationName)
+	requests, _ := meter.Int64Counter(prefix+".requests", metric.WithDescription("Number of "+prefix+" operations."))
+	errs, _ := meter.Int64Counter(prefix+".errors", metric.WithDescription("Number of failed "+prefix+" operations."))
+	duration, _ := meter.Float64Histogram(prefix+".duration", metric.WithUnit("s"), metric.WithDescription("Duration of "+prefix+" operations."))
+	return &redMetrics{requests: requests, errors: errs, duration: duration}
+}
+
+func (m *redMetrics) record(ctx context.Context, start time.Time, failed bool, attrs ...attribute.KeyValue) {
+	opt := metric.WithAttributes(attrs...)
+	m.requests.Add(ctx, 1, opt)
+	if failed {
+		m.errors.Add(ctx, 1, opt)
+	}
+	m.duration.Record(ctx, time.Since(start).Seconds(), opt)
+}
+
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(instrumentationName)
+}
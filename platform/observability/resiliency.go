@@ -0,0 +1,28 @@
+package observability
+
+import (
+	"context"
+
+	"github.com/viralforge/mesh/platform/resiliency"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ResiliencyObserver counts resiliency policy events on the
+// resiliency.events instrument, labelled by kind and policy and, for
+// breaker transitions, by the from and to states.
+func ResiliencyObserver() resiliency.Observer {
+	meter := otel.GetMeterProvider().Meter(instrumentationName)
+	events, _ := meter.Int64Counter("resiliency.events", metric.WithDescription("Number of resiliency policy events."))
+	return resiliency.ObserverFunc(func(e resiliency.Event) {
+		attrs := []attribute.KeyValue{
+			attribute.String("kind", string(e.Kind)),
+			attribute.String("policy", e.Policy),
+		}
+		if e.Kind == resiliency.EventBreakerTransition {
+			attrs = append(attrs, attribute.String("from", string(e.From)), attribute.String("to", string(e.To)))
+		}
+		events.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+	})
+}
@@ -0,0 +1,170 @@
+package resiliency
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerConfig tunes a CircuitBreaker. Zero values fall back to defaults.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before probing.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of concurrent probe calls allowed while half-open.
+	HalfOpenProbes int
+	// SuccessThreshold is the number of probe successes needed to close the circuit.
+	SuccessThreshold int
+}
+
+func (c BreakerConfig) withDefaults() BreakerConfig {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = 5
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+	if c.HalfOpenProbes <= 0 {
+		c.HalfOpenProbes = 1
+	}
+	if c.SuccessThreshold <= 0 {
+		c.SuccessThreshold = 1
+	}
+	return c
+}
+
+// CircuitBreaker fails fast while a dependency is unhealthy and lets a
+// bounded number of probe calls through once the open timeout elapses.
+type CircuitBreaker struct {
+	name     string
+	cfg      BreakerConfig
+	observer Observer
+	now      func() time.Time
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	successes int
+	probes    int
+	openedAt  time.Time
+	// generation changes on every transition so that calls admitted in an
+	// earlier state do not skew the counters of the current one.
+	generation uint64
+}
+
+func NewCircuitBreaker(name string, cfg BreakerConfig, observer Observer) *CircuitBreaker {
+	if observer == nil {
+		observer = nopObserver{}
+	}
+	return &CircuitBreaker{
+		name:     name,
+		cfg:      cfg.withDefaults(),
+		observer: observer,
+		now:      time.Now,
+		state:    BreakerClosed,
+	}
+}
+
+// State reports the current state, moving an expired open circuit to half-open.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.maybeHalfOpenLocked()
+	return b.state
+}
+
+// Allow reserves a call. On success the caller must invoke done exactly once
+// with the attempt outcome.
+func (b *CircuitBreaker) Allow() (done func(Outcome), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.maybeHalfOpenLocked()
+	switch b.state {
+	case BreakerOpen:
+		b.observer.Observe(Event{Kind: EventBreakerRejected, Policy: b.name})
+		return nil, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			b.observer.Observe(Event{Kind: EventBreakerRejected, Policy: b.name})
+			return nil, ErrCircuitOpen
+		}
+		b.probes++
+		return b.doneFunc(true), nil
+	default:
+		return b.doneFunc(false), nil
+	}
+}
+
+func (b *CircuitBreaker) doneFunc(probe bool) func(Outcome) {
+	var once sync.Once
+	generation := b.generation
+	return func(outcome Outcome) {
+		once.Do(func() { b.record(generation, probe, outcome) })
+	}
+}
+
+func (b *CircuitBreaker) record(generation uint64, probe bool, outcome Outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+	if probe {
+		b.probes--
+	}
+	switch outcome {
+	case OutcomeIgnored:
+		return
+	case OutcomeSuccess:
+		switch b.state {
+		case BreakerHalfOpen:
+			b.successes++
+			if b.successes >= b.cfg.SuccessThreshold {
+				b.transitionLocked(BreakerClosed)
+			}
+		case BreakerClosed:
+			b.failures = 0
+		}
+	default:
+		switch b.state {
+		case BreakerHalfOpen:
+			b.transitionLocked(BreakerOpen)
+		case BreakerClosed:
+			b.failures++
+			if b.failures >= b.cfg.FailureThreshold {
+				b.transitionLocked(BreakerOpen)
+			}
+		}
+	}
+}
+
+func (b *CircuitBreaker) maybeHalfOpenLocked() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.transitionLocked(BreakerHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) transitionLocked(to BreakerState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	b.generation++
+	b.failures = 0
+	b.successes = 0
+	b.probes = 0
+	if to == BreakerOpen {
+		b.openedAt = b.now()
+	}
+	b.observer.Observe(Event{Kind: EventBreakerTransition, Policy: b.name, From: from, To: to})
+}
@@ -0,0 +1,68 @@
+package resiliency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bulkhead caps the number of concurrent calls to a dependency so that a
+// slow dependency cannot exhaust the caller's goroutines and connections.
+type Bulkhead struct {
+	name     string
+	slots    chan struct{}
+	maxWait  time.Duration
+	observer Observer
+}
+
+// NewBulkhead allows maxConcurrent in-flight calls; callers wait up to
+// maxWait for a slot (zero means fail immediately when full).
+func NewBulkhead(name string, maxConcurrent int, maxWait time.Duration, observer Observer) *Bulkhead {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	if observer == nil {
+		observer = nopObserver{}
+	}
+	return &Bulkhead{
+		name:     name,
+		slots:    make(chan struct{}, maxConcurrent),
+		maxWait:  maxWait,
+		observer: observer,
+	}
+}
+
+// Acquire reserves a slot. On success the caller must invoke release exactly once.
+func (b *Bulkhead) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.acquired(), nil
+	default:
+	}
+	if b.maxWait <= 0 {
+		b.observer.Observe(Event{Kind: EventBulkheadRejected, Policy: b.name})
+		return nil, ErrBulkheadFull
+	}
+	timer := time.NewTimer(b.maxWait)
+	defer timer.Stop()
+	select {
+	case b.slots <- struct{}{}:
+		return b.acquired(), nil
+	case <-timer.C:
+		b.observer.Observe(Event{Kind: EventBulkheadRejected, Policy: b.name})
+		return nil, ErrBulkheadFull
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InFlight returns the number of occupied slots.
+func (b *Bulkhead) InFlight() int { return len(b.slots) }
+
+func (b *Bulkhead) acquired() func() {
+	b.observer.Observe(Event{Kind: EventBulkheadAcquired, Policy: b.name})
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-b.slots })
+	}
+}
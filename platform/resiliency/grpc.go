@@ -0,0 +1,153 @@
+package resiliency
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// GRPCClassifier maps gRPC status codes to outcomes: transient codes are
+// retried, server faults count against the breaker and caller errors are
+// ignored.
+func GRPCClassifier(err error) Outcome {
+	if err == nil {
+		return OutcomeSuccess
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return DefaultClassifier(err)
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return OutcomeRetryable
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return OutcomeFailure
+	default:
+		return OutcomeIgnored
+	}
+}
+
+// UnaryClientInterceptor applies policy to unary calls. Methods listed in
+// idempotent (full method names, e.g. "/auth.v1.AuthInternalService/GetPublicKeys")
+// are retried and hedged; other methods only pass through the bulkhead,
+// circuit breaker and timeouts.
+func UnaryClientInterceptor(policy *Policy, idempotent ...string) grpc.UnaryClientInterceptor {
+	safe := methodSet(idempotent)
+	noRetry := policy.withoutRetries()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		p := noRetry
+		h := hooks[proto.Message]{classify: GRPCClassifier}
+		if safe[method] {
+			p = policy
+			h.hedge = true
+		}
+		msg, hedgeable := reply.(proto.Message)
+		if !hedgeable {
+			h.hedge = false
+		}
+		if !h.hedge {
+			_, err := run(ctx, p, h, func(ctx context.Context) (proto.Message, error) {
+				return nil, invoker(ctx, method, req, reply, cc, opts...)
+			})
+			return err
+		}
+		// Hedged attempts run concurrently, so each decodes into its own
+		// copy and the winner is merged into reply.
+		winner, err := run(ctx, p, h, func(ctx context.Context) (proto.Message, error) {
+			out := proto.Clone(msg)
+			proto.Reset(out)
+			return out, invoker(ctx, method, req, out, cc, opts...)
+		})
+		if err == nil && winner != nil {
+			proto.Reset(msg)
+			proto.Merge(msg, winner)
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor applies policy to stream establishment. Streams of
+// methods listed in idempotent are re-established on transient failures.
+// A bulkhead slot is held for the lifetime of the stream; policy timeouts are
+// not applied because streams are expected to outlive them.
+func StreamClientInterceptor(policy *Policy, idempotent ...string) grpc.StreamClientInterceptor {
+	safe := methodSet(idempotent)
+	retrying := *policy
+	retrying.bulkhead = nil
+	retrying.timeout = 0
+	retrying.attemptTimeout = 0
+	noRetry := retrying.withoutRetries()
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		release := func() {}
+		if policy.bulkhead != nil {
+			var err error
+			if release, err = policy.bulkhead.Acquire(ctx); err != nil {
+				return nil, err
+			}
+		}
+		p := noRetry
+		if safe[method] {
+			p = &retrying
+		}
+		h := hooks[grpc.ClientStream]{
+			classify: GRPCClassifier,
+			keep: func(stream grpc.ClientStream, cancel func()) grpc.ClientStream {
+				if stream == nil {
+					cancel()
+					return nil
+				}
+				return newReleasingStream(ctx, stream, func() {
+					cancel()
+					release()
+				})
+			},
+		}
+		stream, err := run(ctx, p, h, func(actx context.Context) (grpc.ClientStream, error) {
+			return streamer(actx, desc, cc, method, opts...)
+		})
+		if err != nil {
+			release()
+			return nil, err
+		}
+		return stream, nil
+	}
+}
+
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		set[m] = true
+	}
+	return set
+}
+
+// releasingStream runs release once the stream terminates.
+type releasingStream struct {
+	grpc.ClientStream
+	once    sync.Once
+	release func()
+	stop    func() bool
+}
+
+func newReleasingStream(ctx context.Context, stream grpc.ClientStream, release func()) *releasingStream {
+	s := &releasingStream{ClientStream: stream, release: release}
+	s.stop = context.AfterFunc(ctx, s.finish)
+	return s
+}
+
+func (s *releasingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.stop()
+		s.finish()
+	}
+	return err
+}
+
+func (s *releasingStream) finish() {
+	s.once.Do(s.release)
+}
@@ -0,0 +1,184 @@
+package resiliency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeoutHeader carries the caller's remaining deadline, in milliseconds,
+// across REST hops. gRPC propagates deadlines natively.
+const TimeoutHeader = "X-Request-Timeout-Ms"
+
+// statusError reports a response status worth retrying or counting against
+// the circuit breaker.
+type statusError struct {
+	resp *http.Response
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("resiliency: upstream responded %d", e.resp.StatusCode)
+}
+
+// Transport is an http.RoundTripper that applies a Policy to every request.
+// Requests are retried only when they are idempotent (safe methods, PUT,
+// DELETE or carrying an Idempotency-Key) and their body can be replayed;
+// GET and HEAD requests are additionally hedged.
+type Transport struct {
+	Base   http.RoundTripper
+	Policy *Policy
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil) with policy.
+func NewTransport(base http.RoundTripper, policy *Policy) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Policy: policy}
+}
+
+// WrapClient returns a shallow copy of client whose transport applies policy.
+func WrapClient(client *http.Client, policy *Policy) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	wrapped := *client
+	wrapped.Transport = NewTransport(client.Transport, policy)
+	return &wrapped
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	h := hooks[*http.Response]{
+		classify: classifyHTTP(t.Policy.classify),
+		hedge:    replayable && (req.Method == http.MethodGet || req.Method == http.MethodHead),
+		keep:     keepResponse,
+		discard:  discardResponse,
+	}
+	policy := t.Policy
+	if !replayable || !isIdempotent(req) {
+		policy = policy.withoutRetries()
+	}
+
+	first := true
+	var mu sync.Mutex
+	resp, err := run(req.Context(), policy, h, func(ctx context.Context) (*http.Response, error) {
+		attempt := req.Clone(ctx)
+		mu.Lock()
+		reuse := first
+		first = false
+		mu.Unlock()
+		if !reuse && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, Permanent(err)
+			}
+			attempt.Body = body
+		}
+		setTimeoutHeader(ctx, attempt.Header)
+		resp, err := t.Base.RoundTrip(attempt)
+		if err != nil {
+			return nil, err
+		}
+		if isRetryableStatus(resp.StatusCode) {
+			return resp, &statusError{resp: resp}
+		}
+		return resp, nil
+	})
+	var se *statusError
+	if errors.As(err, &se) && resp != nil {
+		return resp, nil
+	}
+	return resp, err
+}
+
+func (p *Policy) withoutRetries() *Policy {
+	clone := *p
+	clone.retry.MaxAttempts = 1
+	return &clone
+}
+
+func classifyHTTP(base Classifier) Classifier {
+	return func(err error) Outcome {
+		var se *statusError
+		if errors.As(err, &se) {
+			return OutcomeRetryable
+		}
+		return base(err)
+	}
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return strings.TrimSpace(req.Header.Get("Idempotency-Key")) != ""
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func keepResponse(resp *http.Response, release func()) *http.Response {
+	if resp == nil || resp.Body == nil {
+		release()
+		return resp
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp
+}
+
+func discardResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		_ = resp.Body.Close()
+	}
+}
+
+// releasingBody frees the attempt context once the caller closes the body.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+func setTimeoutHeader(ctx context.Context, header http.Header) {
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline).Milliseconds()
+		if remaining < 1 {
+			remaining = 1
+		}
+		header.Set(TimeoutHeader, strconv.FormatInt(remaining, 10))
+	}
+}
+
+// PropagateDeadline is server middleware that applies the deadline carried
+// in TimeoutHeader to the request context.
+func PropagateDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := strings.TrimSpace(r.Header.Get(TimeoutHeader))
+		if ms, err := strconv.ParseInt(raw, 10, 64); err == nil && ms > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), time.Duration(ms)*time.Millisecond)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,113 @@
+package resiliency
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// EventKind names a resiliency state transition.
+type EventKind string
+
+const (
+	EventBreakerTransition    EventKind = "breaker_transition"
+	EventBreakerRejected      EventKind = "breaker_rejected"
+	EventRetry                EventKind = "retry"
+	EventRetryBudgetExhausted EventKind = "retry_budget_exhausted"
+	EventBulkheadAcquired     EventKind = "bulkhead_acquired"
+	EventBulkheadRejected     EventKind = "bulkhead_rejected"
+	EventHedge                EventKind = "hedge"
+	EventTimeout              EventKind = "timeout"
+)
+
+// Event describes one state transition of a policy component.
+type Event struct {
+	Kind    EventKind
+	Policy  string
+	From    BreakerState
+	To      BreakerState
+	Attempt int
+	Err     error
+}
+
+// Observer receives every resiliency event. Implementations must be safe
+// for concurrent use.
+type Observer interface {
+	Observe(Event)
+}
+
+// ObserverFunc adapts a function to Observer.
+type ObserverFunc func(Event)
+
+func (f ObserverFunc) Observe(e Event) { f(e) }
+
+type nopObserver struct{}
+
+func (nopObserver) Observe(Event) {}
+
+// Counters is an in-memory Observer that aggregates events into counters
+// and renders them in the Prometheus text format.
+type Counters struct {
+	mu     sync.Mutex
+	values map[counterKey]int64
+}
+
+type counterKey struct {
+	kind   EventKind
+	policy string
+	from   BreakerState
+	to     BreakerState
+}
+
+func NewCounters() *Counters {
+	return &Counters{values: make(map[counterKey]int64)}
+}
+
+func (c *Counters) Observe(e Event) {
+	key := counterKey{kind: e.Kind, policy: e.Policy}
+	if e.Kind == EventBreakerTransition {
+		key.from, key.to = e.From, e.To
+	}
+	c.mu.Lock()
+	c.values[key]++
+	c.mu.Unlock()
+}
+
+// Count returns the number of events of kind observed for policy.
+func (c *Counters) Count(kind EventKind, policy string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total int64
+	for key, v := range c.values {
+		if key.kind == kind && key.policy == policy {
+			total += v
+		}
+	}
+	return total
+}
+
+// WritePrometheus renders all counters as resiliency_events_total samples.
+func (c *Counters) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	lines := make([]string, 0, len(c.values))
+	for key, v := range c.values {
+		labels := fmt.Sprintf("kind=%q,policy=%q", key.kind, key.policy)
+		if key.kind == EventBreakerTransition {
+			labels += fmt.Sprintf(",from=%q,to=%q", key.from, key.to)
+		}
+		lines = append(lines, fmt.Sprintf("resiliency_events_total{%s} %d\n", labels, v))
+	}
+	c.mu.Unlock()
+	sort.Strings(lines)
+
+	if _, err := io.WriteString(w, "# TYPE resiliency_events_total counter\n"); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
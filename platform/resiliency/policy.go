@@ -0,0 +1,295 @@
+package resiliency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HedgeConfig tunes hedged requests for idempotent reads.
+type HedgeConfig struct {
+	// Delay is how long to wait for an attempt before launching a hedge.
+	// Zero disables hedging.
+	Delay time.Duration
+	// MaxHedges is the number of extra in-flight copies allowed per attempt.
+	MaxHedges int
+}
+
+// PolicyConfig assembles the components of a Policy. Optional components
+// are disabled by their zero value.
+type PolicyConfig struct {
+	// Name labels emitted events, usually the dependency name (e.g. "M18").
+	Name string
+	// Timeout bounds the whole call, including retries and backoff.
+	Timeout time.Duration
+	// AttemptTimeout bounds each individual attempt.
+	AttemptTimeout time.Duration
+	// Retry configures retries; MaxAttempts of 1 disables them.
+	Retry RetryConfig
+	// Breaker enables a circuit breaker shared by every call of the policy.
+	Breaker *BreakerConfig
+	// MaxConcurrent enables a bulkhead with that many slots.
+	MaxConcurrent int
+	// MaxWait is how long a call may queue for a bulkhead slot.
+	MaxWait time.Duration
+	// Hedge enables hedged attempts for calls made through Read.
+	Hedge HedgeConfig
+	// Classifier overrides DefaultClassifier.
+	Classifier Classifier
+	// Observer receives every event; nil discards them.
+	Observer Observer
+}
+
+// Policy composes bulkhead, retries, circuit breaker, timeouts and hedging
+// around outbound calls. A Policy is safe for concurrent use and should be
+// shared by every call to the same dependency.
+type Policy struct {
+	name           string
+	timeout        time.Duration
+	attemptTimeout time.Duration
+	retry          RetryConfig
+	hedge          HedgeConfig
+	classify       Classifier
+	observer       Observer
+	breaker        *CircuitBreaker
+	bulkhead       *Bulkhead
+}
+
+func NewPolicy(cfg PolicyConfig) *Policy {
+	observer := cfg.Observer
+	if observer == nil {
+		observer = nopObserver{}
+	}
+	classify := cfg.Classifier
+	if classify == nil {
+		classify = DefaultClassifier
+	}
+	hedge := cfg.Hedge
+	if hedge.Delay > 0 && hedge.MaxHedges <= 0 {
+		hedge.MaxHedges = 1
+	}
+	p := &Policy{
+		name:           cfg.Name,
+		timeout:        cfg.Timeout,
+		attemptTimeout: cfg.AttemptTimeout,
+		retry:          cfg.Retry.withDefaults(),
+		hedge:          hedge,
+		classify:       classify,
+		observer:       observer,
+	}
+	if cfg.Breaker != nil {
+		p.breaker = NewCircuitBreaker(cfg.Name, *cfg.Breaker, observer)
+	}
+	if cfg.MaxConcurrent > 0 {
+		p.bulkhead = NewBulkhead(cfg.Name, cfg.MaxConcurrent, cfg.MaxWait, observer)
+	}
+	return p
+}
+
+func (p *Policy) Name() string { return p.name }
+
+// Breaker returns the policy circuit breaker, or nil when disabled.
+func (p *Policy) Breaker() *CircuitBreaker { return p.breaker }
+
+// Bulkhead returns the policy bulkhead, or nil when disabled.
+func (p *Policy) Bulkhead() *Bulkhead { return p.bulkhead }
+
+// Execute runs fn under the policy. fn may be invoked several times and
+// must honour the context it receives.
+func (p *Policy) Execute(ctx context.Context, fn func(context.Context) error) error {
+	_, err := run(ctx, p, hooks[struct{}]{}, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// Read runs an idempotent read under the policy, additionally hedging slow
+// attempts when the policy enables it. The first acceptable result wins and
+// the remaining attempts are cancelled.
+func Read[T any](ctx context.Context, p *Policy, fn func(context.Context) (T, error)) (T, error) {
+	return run(ctx, p, hooks[T]{hedge: true}, fn)
+}
+
+// hooks customise how run treats attempt results.
+type hooks[T any] struct {
+	classify Classifier
+	hedge    bool
+	// keep takes ownership of the returned result; release must be called
+	// once the result is no longer in use. Nil releases immediately.
+	keep func(v T, release func()) T
+	// discard disposes of results that are not returned to the caller.
+	discard func(v T)
+}
+
+type result[T any] struct {
+	val    T
+	err    error
+	has    bool
+	cancel func()
+}
+
+func run[T any](ctx context.Context, p *Policy, h hooks[T], fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if h.classify == nil {
+		h.classify = p.classify
+	}
+	cancelAll := func() {}
+	if p.timeout > 0 {
+		ctx, cancelAll = context.WithTimeout(ctx, p.timeout)
+	}
+	if p.bulkhead != nil {
+		release, err := p.bulkhead.Acquire(ctx)
+		if err != nil {
+			cancelAll()
+			return zero, err
+		}
+		defer release()
+	}
+
+	finish := func(r result[T], err error) (T, error) {
+		if !r.has {
+			cancelAll()
+			return zero, err
+		}
+		release := func() {
+			r.cancel()
+			cancelAll()
+		}
+		if h.keep == nil {
+			release()
+			return r.val, err
+		}
+		return h.keep(r.val, release), err
+	}
+
+	budget := p.retry.Budget
+	if budget != nil {
+		budget.Deposit()
+	}
+	for n := 1; ; n++ {
+		r := attempt(ctx, p, h, fn)
+		if h.classify(r.err) != OutcomeRetryable || n >= p.retry.MaxAttempts || ctx.Err() != nil {
+			return finish(r, r.err)
+		}
+		if budget != nil && !budget.Withdraw() {
+			p.observer.Observe(Event{Kind: EventRetryBudgetExhausted, Policy: p.name, Attempt: n, Err: r.err})
+			return finish(r, fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, r.err))
+		}
+		timer := time.NewTimer(p.retry.Backoff(n))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return finish(r, r.err)
+		case <-timer.C:
+		}
+		dispose(h, r)
+		p.observer.Observe(Event{Kind: EventRetry, Policy: p.name, Attempt: n + 1, Err: r.err})
+	}
+}
+
+// attempt runs one logical attempt, hedging it when enabled.
+func attempt[T any](ctx context.Context, p *Policy, h hooks[T], fn func(context.Context) (T, error)) result[T] {
+	if !h.hedge || p.hedge.Delay <= 0 {
+		return single(ctx, p, h, fn)
+	}
+
+	type launched struct {
+		idx int
+		res result[T]
+	}
+	results := make(chan launched, p.hedge.MaxHedges+1)
+	var cancels []context.CancelFunc
+	launch := func() {
+		hctx, cancel := context.WithCancel(ctx)
+		idx := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			r := single(hctx, p, h, fn)
+			inner := r.cancel
+			r.cancel = func() {
+				inner()
+				cancel()
+			}
+			results <- launched{idx: idx, res: r}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(p.hedge.Delay)
+	defer timer.Stop()
+	var last result[T]
+	pending := 1
+	for pending > 0 {
+		select {
+		case l := <-results:
+			pending--
+			if h.classify(l.res.err) == OutcomeRetryable {
+				dispose(h, last)
+				last = l.res
+				if pending == 0 && len(cancels) <= p.hedge.MaxHedges && ctx.Err() == nil {
+					p.observer.Observe(Event{Kind: EventHedge, Policy: p.name, Attempt: len(cancels) + 1})
+					launch()
+					pending++
+				}
+				continue
+			}
+			for i, cancel := range cancels {
+				if i != l.idx {
+					cancel()
+				}
+			}
+			dispose(h, last)
+			go func(n int) {
+				for ; n > 0; n-- {
+					dispose(h, (<-results).res)
+				}
+			}(pending)
+			return l.res
+		case <-timer.C:
+			if len(cancels) <= p.hedge.MaxHedges {
+				p.observer.Observe(Event{Kind: EventHedge, Policy: p.name, Attempt: len(cancels) + 1})
+				launch()
+				pending++
+				timer.Reset(p.hedge.Delay)
+			}
+		}
+	}
+	return last
+}
+
+// single runs fn once behind the circuit breaker and attempt timeout.
+func single[T any](ctx context.Context, p *Policy, h hooks[T], fn func(context.Context) (T, error)) result[T] {
+	done := func(Outcome) {}
+	if p.breaker != nil {
+		var err error
+		if done, err = p.breaker.Allow(); err != nil {
+			return result[T]{err: err, cancel: func() {}}
+		}
+	}
+	var (
+		actx   context.Context
+		cancel context.CancelFunc
+	)
+	if p.attemptTimeout > 0 {
+		actx, cancel = context.WithTimeout(ctx, p.attemptTimeout)
+	} else {
+		actx, cancel = context.WithCancel(ctx)
+	}
+	val, err := fn(actx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		p.observer.Observe(Event{Kind: EventTimeout, Policy: p.name, Err: err})
+	}
+	done(h.classify(err))
+	return result[T]{val: val, err: err, has: true, cancel: cancel}
+}
+
+// dispose releases a result that will not be handed to the caller.
+func dispose[T any](h hooks[T], r result[T]) {
+	if r.has && h.discard != nil {
+		h.discard(r.val)
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
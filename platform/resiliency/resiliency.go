@@ -1,3 +1,70 @@
+// Package resiliency contains shared technical primitives for mesh services.
+//
+// It protects outbound calls with circuit breakers, jittered retries bounded
+// by a retry budget, concurrency bulkheads, deadline propagation and hedged
+// requests for idempotent reads. A Policy composes these pieces and is applied
+// through the net/http transport wrapper or the gRPC client interceptors.
 package resiliency
 
-// Package resiliency contains shared technical primitives for mesh services.
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrCircuitOpen is returned when a circuit breaker rejects a call.
+	ErrCircuitOpen = errors.New("resiliency: circuit breaker is open")
+	// ErrBulkheadFull is returned when no bulkhead slot frees up in time.
+	ErrBulkheadFull = errors.New("resiliency: bulkhead is full")
+	// ErrRetryBudgetExhausted wraps the last error when a retry was denied by the budget.
+	ErrRetryBudgetExhausted = errors.New("resiliency: retry budget exhausted")
+)
+
+// Outcome classifies the result of a single attempt.
+type Outcome int
+
+const (
+	// OutcomeSuccess is a healthy response from the dependency.
+	OutcomeSuccess Outcome = iota
+	// OutcomeRetryable is a dependency failure that is safe to retry.
+	OutcomeRetryable
+	// OutcomeFailure is a dependency failure that must not be retried.
+	OutcomeFailure
+	// OutcomeIgnored is a caller-side error; it is neither retried nor
+	// counted against the circuit breaker.
+	OutcomeIgnored
+)
+
+// Classifier maps an attempt error to an Outcome.
+type Classifier func(error) Outcome
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as a failure that should not be retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// DefaultClassifier retries every error except cancellations, local
+// rejections and errors marked with Permanent.
+func DefaultClassifier(err error) Outcome {
+	var permanent permanentError
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, ErrCircuitOpen),
+		errors.Is(err, ErrBulkheadFull):
+		return OutcomeIgnored
+	case errors.As(err, &permanent):
+		return OutcomeFailure
+	default:
+		return OutcomeRetryable
+	}
+}
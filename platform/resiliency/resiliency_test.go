@@ -0,0 +1,166 @@
+package resiliency_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/viralforge/mesh/platform/resiliency"
+)
+
+var errUpstream = errors.New("upstream failed")
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	counters := resiliency.NewCounters()
+	breaker := resiliency.NewCircuitBreaker("m18", resiliency.BreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      20 * time.Millisecond,
+	}, counters)
+
+	for i := 0; i < 2; i++ {
+		done, err := breaker.Allow()
+		if err != nil {
+			t.Fatalf("allow %d: %v", i, err)
+		}
+		done(resiliency.OutcomeRetryable)
+	}
+	if breaker.State() != resiliency.BreakerOpen {
+		t.Fatalf("expected open breaker, got %s", breaker.State())
+	}
+	if _, err := breaker.Allow(); !errors.Is(err, resiliency.ErrCircuitOpen) {
+		t.Fatalf("expected circuit open, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	probe, err := breaker.Allow()
+	if err != nil {
+		t.Fatalf("expected half-open probe, got %v", err)
+	}
+	if _, err := breaker.Allow(); !errors.Is(err, resiliency.ErrCircuitOpen) {
+		t.Fatalf("expected second probe to be rejected, got %v", err)
+	}
+	probe(resiliency.OutcomeSuccess)
+	if breaker.State() != resiliency.BreakerClosed {
+		t.Fatalf("expected closed breaker, got %s", breaker.State())
+	}
+	if got := counters.Count(resiliency.EventBreakerTransition, "m18"); got != 3 {
+		t.Fatalf("expected 3 transitions, got %d", got)
+	}
+}
+
+func TestPolicyRetriesWithinBudget(t *testing.T) {
+	counters := resiliency.NewCounters()
+	policy := resiliency.NewPolicy(resiliency.PolicyConfig{
+		Name:     "m54",
+		Retry:    resiliency.RetryConfig{MaxAttempts: 5, InitialBackoff: time.Millisecond, Budget: resiliency.NewRetryBudget(0, 1)},
+		Observer: counters,
+	})
+
+	var calls int32
+	err := policy.Execute(context.Background(), func(context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return errUpstream
+	})
+	if !errors.Is(err, resiliency.ErrRetryBudgetExhausted) || !errors.Is(err, errUpstream) {
+		t.Fatalf("expected budget exhaustion wrapping upstream error, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected first attempt plus one budgeted retry, got %d", calls)
+	}
+
+	calls = 0
+	err = policy.Execute(context.Background(), func(context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return resiliency.Permanent(errUpstream)
+	})
+	if !errors.Is(err, errUpstream) || calls != 1 {
+		t.Fatalf("expected permanent error without retry, got %v after %d calls", err, calls)
+	}
+}
+
+func TestBulkheadRejectsWhenFull(t *testing.T) {
+	bulkhead := resiliency.NewBulkhead("m18", 1, 0, nil)
+	release, err := bulkhead.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if _, err := bulkhead.Acquire(context.Background()); !errors.Is(err, resiliency.ErrBulkheadFull) {
+		t.Fatalf("expected bulkhead full, got %v", err)
+	}
+	release()
+	release()
+	if bulkhead.InFlight() != 0 {
+		t.Fatalf("expected empty bulkhead, got %d", bulkhead.InFlight())
+	}
+}
+
+func TestReadHedgesSlowAttempt(t *testing.T) {
+	counters := resiliency.NewCounters()
+	policy := resiliency.NewPolicy(resiliency.PolicyConfig{
+		Name:     "m54",
+		Hedge:    resiliency.HedgeConfig{Delay: 10 * time.Millisecond},
+		Observer: counters,
+	})
+
+	var calls int32
+	got, err := resiliency.Read(context.Background(), policy, func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "hedged", nil
+	})
+	if err != nil || got != "hedged" {
+		t.Fatalf("expected hedged result, got %q, %v", got, err)
+	}
+	if counters.Count(resiliency.EventHedge, "m54") != 1 {
+		t.Fatalf("expected one hedge event")
+	}
+}
+
+func TestTransportRetriesIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(resiliency.TimeoutHeader) == "" {
+			t.Errorf("expected propagated deadline header")
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := resiliency.WrapClient(server.Client(), resiliency.NewPolicy(resiliency.PolicyConfig{
+		Name:    "m18",
+		Timeout: time.Second,
+		Retry:   resiliency.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}))
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("expected ok after retries, got %d %q", resp.StatusCode, body)
+	}
+
+	calls = 0
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 1 {
+		t.Fatalf("expected non-idempotent POST to pass through once, got %d after %d calls", resp.StatusCode, calls)
+	}
+}
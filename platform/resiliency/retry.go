@@ -0,0 +1,114 @@
+package resiliency
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// RetryConfig tunes jittered exponential retries. Zero values fall back to defaults.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the first backoff interval.
+	InitialBackoff time.Duration
+	// MaxBackoff caps every backoff interval.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff bound after each attempt.
+	Multiplier float64
+	// Budget bounds retries relative to overall traffic. Nil disables the budget.
+	Budget *RetryBudget
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 50 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 2 * time.Second
+	}
+	if c.Multiplier < 1 {
+		c.Multiplier = 2
+	}
+	return c
+}
+
+// Backoff returns the full-jitter delay before the given retry (1-based).
+func (c RetryConfig) Backoff(retry int) time.Duration {
+	c = c.withDefaults()
+	bound := float64(c.InitialBackoff) * math.Pow(c.Multiplier, float64(retry-1))
+	if bound > float64(c.MaxBackoff) {
+		bound = float64(c.MaxBackoff)
+	}
+	if bound < 1 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(bound)) + 1)
+}
+
+// RetryBudget limits retries to a fraction of first attempts so that a
+// failing dependency is not hit by a retry storm. A small per-second reserve
+// keeps low-traffic callers able to retry.
+type RetryBudget struct {
+	ratio        float64
+	minPerSecond float64
+	maxTokens    float64
+	now          func() time.Time
+
+	mu         sync.Mutex
+	tokens     float64
+	reserve    float64
+	lastRefill time.Time
+}
+
+// NewRetryBudget allows ratio retries per request (e.g. 0.2 for 20%) plus
+// minPerSecond retries regardless of traffic.
+func NewRetryBudget(ratio float64, minPerSecond int) *RetryBudget {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if minPerSecond < 0 {
+		minPerSecond = 0
+	}
+	maxTokens := math.Max(ratio*100, 1)
+	return &RetryBudget{
+		ratio:        ratio,
+		minPerSecond: float64(minPerSecond),
+		maxTokens:    maxTokens,
+		now:          time.Now,
+		reserve:      float64(minPerSecond),
+		lastRefill:   time.Now(),
+	}
+}
+
+// Deposit credits the budget for one first attempt.
+func (b *RetryBudget) Deposit() {
+	b.mu.Lock()
+	b.tokens = math.Min(b.tokens+b.ratio, b.maxTokens)
+	b.mu.Unlock()
+}
+
+// Withdraw reports whether a retry may proceed, consuming one token if so.
+func (b *RetryBudget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if elapsed := now.Sub(b.lastRefill).Seconds(); elapsed > 0 {
+		b.reserve = math.Min(b.reserve+elapsed*b.minPerSecond, b.minPerSecond)
+		b.lastRefill = now
+	}
+	switch {
+	case b.tokens >= 1:
+		b.tokens--
+		return true
+	case b.reserve >= 1:
+		b.reserve--
+		return true
+	default:
+		return false
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/redis/go-redis/v9 v9.6.1
 	github.com/viralforge/mesh/contracts v0.0.0
+	github.com/viralforge/mesh/platform v0.0.0
 	golang.org/x/crypto v0.31.0
 	google.golang.org/grpc v1.66.2
 	gopkg.in/yaml.v3 v3.0.1
@@ -35,3 +36,4 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
 )
+replace github.com/viralforge/mesh/platform => ../../../platform
@@ -12,10 +12,8 @@ import (
 	"syscall"
 	"time"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
-	healthpb "google.golang.org/grpc/health/grpc_health_v1"
-
+	platformgrpc "github.com/viralforge/mesh/platform/grpc"
+	"github.com/viralforge/mesh/platform/observability"
 	cacheadapter "github.com/viralforge/mesh/services/core-platform/M01-authentication-service/internal/adapters/cache"
 	eventadapter "github.com/viralforge/mesh/services/core-platform/M01-authentication-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/core-platform/M01-authentication-service/internal/adapters/grpc"
@@ -30,8 +28,9 @@ import (
 type Runtime struct {
 	cfg         Config
 	logger      *slog.Logger
+	telemetry   *observability.Telemetry
 	httpServer  *http.Server
-	grpcServer  *grpc.Server
+	grpcServer  *platformgrpc.Server
 	grpcLis     net.Listener
 	outbox      *eventadapter.OutboxWorker
 	oidcRefresh *eventadapter.OIDCTokenRefreshWorker
@@ -46,7 +45,11 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 		return nil, err
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).
 		With("service", "M01-Authentication-Service")
 	slog.SetDefault(logger)
 	logger.InfoContext(ctx, "runtime bootstrap started",
@@ -165,15 +168,20 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	router := httpadapter.NewRouter(handler)
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler:           router,
+		Handler:           observability.HTTPMiddleware(cfg.ServiceID)(router),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	grpcServer := grpc.NewServer()
-	healthSrv := health.NewServer()
-	healthpb.RegisterHealthServer(grpcServer, healthSrv)
-	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-	grpcadapter.Register(grpcServer, grpcadapter.NewAuthInternalServer(svc))
+	grpcCfg := platformgrpc.ServerConfigFromEnv(cfg.ServiceID, cfg.GRPCPort)
+	grpcCfg.Logger = logger
+	grpcCfg.Readiness = sqlDB.PingContext
+	grpcServer, err := platformgrpc.NewServer(grpcCfg)
+	if err != nil {
+		_ = sqlDB.Close()
+		_ = redisClient.Close()
+		return nil, fmt.Errorf("init gRPC server: %w", err)
+	}
+	grpcadapter.Register(grpcServer.GRPC(), grpcadapter.NewAuthInternalServer(svc))
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
@@ -209,6 +217,7 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	return &Runtime{
 		cfg:         cfg,
 		logger:      logger,
+		telemetry:   telemetry,
 		httpServer:  httpServer,
 		grpcServer:  grpcServer,
 		grpcLis:     lis,
@@ -226,8 +235,11 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 
 	errCh := make(chan error, 2)
+	grpcCtx, stopGRPC := context.WithCancel(ctx)
+	grpcDone := make(chan struct{})
 	go func() {
 		r.logger.InfoContext(ctx, "http server started",
 			"module", "bootstrap",
@@ -241,14 +253,10 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 		}
 	}()
 	go func() {
-		r.logger.InfoContext(ctx, "grpc server started",
-			"module", "bootstrap",
-			"layer", "runtime",
-			"operation", "grpc_server_start",
-			"outcome", "success",
-			"addr", r.grpcLis.Addr().String(),
-		)
-		if err := r.grpcServer.Serve(r.grpcLis); err != nil {
+		defer close(grpcDone)
+		// Serve drains on cancellation: health turns NOT_SERVING before the
+		// graceful stop.
+		if err := r.grpcServer.Serve(grpcCtx, r.grpcLis); err != nil {
 			errCh <- fmt.Errorf("grpc server: %w", err)
 		}
 	}()
@@ -274,7 +282,8 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	_ = r.httpServer.Shutdown(shutdownCtx)
-	r.grpcServer.GracefulStop()
+	stopGRPC()
+	<-grpcDone
 	r.cleanupFn(shutdownCtx)
 	return nil
 }
@@ -284,6 +293,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 
 	r.logger.InfoContext(ctx, "outbox worker started",
 		"module", "bootstrap",
@@ -329,3 +339,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 	r.cleanupFn(shutdownCtx)
 	return runErr
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/redis/go-redis/v9 v9.6.1
 	github.com/segmentio/kafka-go v0.4.50
 	github.com/viralforge/mesh/contracts v0.0.0
+	github.com/viralforge/mesh/platform v0.0.0
 	google.golang.org/grpc v1.66.2
 	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/postgres v1.6.0
@@ -37,3 +38,4 @@ require (
 )
 
 replace github.com/viralforge/mesh/contracts => ../../../contracts
+replace github.com/viralforge/mesh/platform => ../../../platform
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/core-platform/M97-team-service/internal/contracts"
 	"github.com/viralforge/mesh/services/core-platform/M97-team-service/internal/domain"
 	"github.com/viralforge/mesh/services/core-platform/M97-team-service/internal/ports"
@@ -99,7 +100,7 @@ func (s *Service) enqueueEvent(ctx context.Context, eventType, traceID string, d
 		PartitionKeyPath: domain.CanonicalPartitionKeyPath(eventType),
 		PartitionKey:     teamID,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          traceID,
+		TraceID:          observability.TraceParent(ctx, traceID),
 		SchemaVersion:    "v1",
 		Data:             b,
 	}
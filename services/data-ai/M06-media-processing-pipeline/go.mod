@@ -8,6 +8,7 @@ require (
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/google/uuid v1.6.0
 	github.com/viralforge/mesh/contracts v0.0.0
+	github.com/viralforge/mesh/platform v0.0.0
 	google.golang.org/grpc v1.75.1
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -21,3 +22,4 @@ require (
 )
 
 replace github.com/viralforge/mesh/contracts => ../../../contracts
+replace github.com/viralforge/mesh/platform => ../../../platform
@@ -11,6 +11,10 @@ import (
 )
 
 func (h *Handler) createUpload(w http.ResponseWriter, r *http.Request) {
+	if isTusRequest(r) {
+		tusMiddleware(http.HandlerFunc(h.tusCreate)).ServeHTTP(w, r)
+		return
+	}
 	actor := actorFromContext(r.Context())
 	var req contracts.UploadRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -23,6 +27,8 @@ func (h *Handler) createUpload(w http.ResponseWriter, r *http.Request) {
 		MIMEType:       strings.TrimSpace(req.MIMEType),
 		FileSize:       req.FileSize,
 		ChecksumSHA256: strings.TrimSpace(req.ChecksumSHA256),
+
+		ThumbnailPositions: req.ThumbnailPositions,
 	})
 	if err != nil {
 		status, code := mapDomainError(err)
@@ -55,3 +61,15 @@ func (h *Handler) retryAsset(w http.ResponseWriter, r *http.Request) {
 	}
 	writeSuccess(w, http.StatusOK, "", out)
 }
+
+func (h *Handler) cancelJob(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	jobID := chi.URLParam(r, "job_id")
+	out, err := h.service.CancelJob(r.Context(), actor, jobID)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", out)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/contracts"
@@ -22,6 +23,10 @@ func writeError(w http.ResponseWriter, status int, code, message, requestID stri
 	writeJSON(w, status, contracts.ErrorResponse{Status: "error", Error: contracts.ErrorPayload{Code: code, Message: message, RequestID: requestID}})
 }
 
+// statusChecksumMismatch is the status the tus checksum extension defines
+// for a chunk that does not match its Upload-Checksum.
+const statusChecksumMismatch = 460
+
 func mapDomainError(err error) (int, string) {
 	switch err {
 	case nil:
@@ -42,7 +47,24 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusConflict, "idempotency_in_flight"
 	case domain.ErrIdempotencyConflict, domain.ErrConflict:
 		return http.StatusConflict, "conflict"
-	default:
-		return http.StatusInternalServerError, "internal_error"
+	case domain.ErrUploadOffsetMismatch:
+		return http.StatusConflict, "upload_offset_mismatch"
+	case domain.ErrChecksumMismatch:
+		return statusChecksumMismatch, "checksum_mismatch"
+	case domain.ErrUnsupportedChecksum:
+		return http.StatusBadRequest, "unsupported_checksum_algorithm"
+	case domain.ErrUnsupportedMedia:
+		return http.StatusUnsupportedMediaType, "unsupported_media"
+	case domain.ErrCorruptMedia:
+		return http.StatusUnprocessableEntity, "corrupt_media"
+	case domain.ErrTruncatedMedia:
+		return http.StatusUnprocessableEntity, "truncated_media"
+	}
+	// Upload verification wraps its sentinel errors with details.
+	for _, sentinel := range []error{domain.ErrChecksumMismatch, domain.ErrUnsupportedMedia, domain.ErrCorruptMedia, domain.ErrTruncatedMedia, domain.ErrPayloadTooLarge} {
+		if errors.Is(err, sentinel) {
+			return mapDomainError(sentinel)
+		}
 	}
+	return http.StatusInternalServerError, "internal_error"
 }
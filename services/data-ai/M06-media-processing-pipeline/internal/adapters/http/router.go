@@ -28,6 +28,15 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Post("/media/uploads", handler.createUpload)
 			r.Get("/media/assets/{asset_id}", handler.getAsset)
 			r.Post("/media/assets/{asset_id}/retry", handler.retryAsset)
+			r.Post("/media/jobs/{job_id}/cancel", handler.cancelJob)
+		})
+		r.Options("/media/uploads", handler.tusOptions)
+		r.Options("/media/uploads/{asset_id}", handler.tusOptions)
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware, tusMiddleware)
+			r.Head("/media/uploads/{asset_id}", handler.tusHead)
+			r.Patch("/media/uploads/{asset_id}", handler.tusPatch)
+			r.Delete("/media/uploads/{asset_id}", handler.tusDelete)
 		})
 	})
 	return r
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/application"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+)
+
+// The upload endpoints speak the tus resumable upload protocol 1.0.0 with
+// the creation, termination and checksum extensions, so stock tus clients
+// can send files in chunks and resume after a dropped connection.
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,termination,checksum"
+	tusContentType = "application/offset+octet-stream"
+)
+
+func (h *Handler) tusOptions(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(domain.MaxUploadBytes, 10))
+	w.Header().Set("Tus-Checksum-Algorithm", strings.Join(application.SupportedChecksumAlgorithms, ","))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tusCreate registers an upload from tus creation headers. Upload-Metadata
+// carries submission_id, filename, filetype and, optionally,
+// checksum_sha256 of the whole file.
+func (h *Handler) tusCreate(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	length, err := strconv.ParseInt(strings.TrimSpace(r.Header.Get("Upload-Length")), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Upload-Length is required", requestIDFromContext(r.Context()))
+		return
+	}
+	meta, err := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	out, err := h.service.CreateUpload(r.Context(), actor, application.CreateUploadInput{
+		SubmissionID:   meta["submission_id"],
+		FileName:       meta["filename"],
+		MIMEType:       meta["filetype"],
+		FileSize:       length,
+		ChecksumSHA256: meta["checksum_sha256"],
+	})
+	if err != nil {
+		writeTusError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", out.UploadURL)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) tusHead(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	out, err := h.service.GetUploadProgress(r.Context(), actor, chi.URLParam(r, "asset_id"))
+	if err != nil {
+		writeTusError(w, r, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(out.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(out.Length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) tusPatch(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	if !strings.EqualFold(strings.TrimSpace(r.Header.Get("Content-Type")), tusContentType) {
+		writeError(w, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be "+tusContentType, requestIDFromContext(r.Context()))
+		return
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(r.Header.Get("Upload-Offset")), 10, 64)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Upload-Offset is required", requestIDFromContext(r.Context()))
+		return
+	}
+	input := application.UploadChunkInput{AssetID: chi.URLParam(r, "asset_id"), Offset: offset, Body: r.Body}
+	if raw := strings.TrimSpace(r.Header.Get("Upload-Checksum")); raw != "" {
+		algorithm, encoded, _ := strings.Cut(raw, " ")
+		sum, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || algorithm == "" {
+			writeError(w, http.StatusBadRequest, "invalid_input", "Upload-Checksum must be an algorithm and a base64 digest", requestIDFromContext(r.Context()))
+			return
+		}
+		input.ChecksumAlgorithm, input.Checksum = algorithm, sum
+	}
+	out, err := h.service.AppendUpload(r.Context(), actor, input)
+	if out.AssetID != "" {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(out.Offset, 10))
+	}
+	if err != nil {
+		writeTusError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) tusDelete(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	if err := h.service.TerminateUpload(r.Context(), actor, chi.URLParam(r, "asset_id")); err != nil {
+		writeTusError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTusError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := mapDomainError(err)
+	writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+}
+
+// tusMiddleware rejects requests for other protocol versions and marks
+// every response with the version spoken here.
+func tusMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+		if r.Method != http.MethodOptions && strings.TrimSpace(r.Header.Get("Tus-Resumable")) != tusVersion {
+			w.Header().Set("Tus-Version", tusVersion)
+			writeError(w, http.StatusPreconditionFailed, "unsupported_tus_version", "Tus-Resumable must be "+tusVersion, requestIDFromContext(r.Context()))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isTusRequest tells tus creation requests apart from JSON upload
+// registrations, which share POST /v1/media/uploads.
+func isTusRequest(r *http.Request) bool {
+	return strings.TrimSpace(r.Header.Get("Tus-Resumable")) != ""
+}
+
+func parseTusMetadata(raw string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, errors.New("Upload-Metadata values must be base64 encoded")
+		}
+		out[key] = strings.TrimSpace(string(value))
+	}
+	return out, nil
+}
@@ -38,15 +38,15 @@ type AssetRepository struct {
 	byChecksum map[string]string
 }
 
-func submissionChecksumKey(submissionID, checksum string) string {
-	return submissionID + "::" + checksum
+func submissionChecksumKey(submissionID, checksum, uploadedBy string) string {
+	return submissionID + "::" + checksum + "::" + uploadedBy
 }
 
 func (r *AssetRepository) Create(_ context.Context, asset domain.MediaAsset) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.records[asset.AssetID] = asset
-	r.byChecksum[submissionChecksumKey(asset.SubmissionID, asset.ChecksumSHA256)] = asset.AssetID
+	r.byChecksum[submissionChecksumKey(asset.SubmissionID, asset.ChecksumSHA256, asset.UploadedBy)] = asset.AssetID
 	return nil
 }
 
@@ -60,10 +60,10 @@ func (r *AssetRepository) GetByID(_ context.Context, assetID string) (domain.Med
 	return asset, nil
 }
 
-func (r *AssetRepository) GetBySubmissionAndChecksum(_ context.Context, submissionID, checksum string) (domain.MediaAsset, error) {
+func (r *AssetRepository) GetBySubmissionAndChecksum(_ context.Context, submissionID, checksum, uploadedBy string) (domain.MediaAsset, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	assetID, ok := r.byChecksum[submissionChecksumKey(submissionID, checksum)]
+	assetID, ok := r.byChecksum[submissionChecksumKey(submissionID, checksum, uploadedBy)]
 	if !ok {
 		return domain.MediaAsset{}, domain.ErrNotFound
 	}
@@ -78,7 +78,7 @@ func (r *AssetRepository) Update(_ context.Context, asset domain.MediaAsset) err
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.records[asset.AssetID] = asset
-	r.byChecksum[submissionChecksumKey(asset.SubmissionID, asset.ChecksumSHA256)] = asset.AssetID
+	r.byChecksum[submissionChecksumKey(asset.SubmissionID, asset.ChecksumSHA256, asset.UploadedBy)] = asset.AssetID
 	return nil
 }
 
@@ -131,11 +131,12 @@ func (r *JobRepository) ListByAsset(_ context.Context, assetID string) ([]domain
 	return out, nil
 }
 
+// ListFailedByAsset includes cancelled jobs, which are retried the same way.
 func (r *JobRepository) ListFailedByAsset(ctx context.Context, assetID string) ([]domain.MediaJob, error) {
 	jobs, _ := r.ListByAsset(ctx, assetID)
 	out := make([]domain.MediaJob, 0, len(jobs))
 	for _, job := range jobs {
-		if job.Status == domain.JobStatusFailed {
+		if job.Status == domain.JobStatusFailed || job.Status == domain.JobStatusCancelled {
 			out = append(out, job)
 		}
 	}
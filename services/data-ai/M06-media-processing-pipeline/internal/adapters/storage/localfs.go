@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+)
+
+// LocalFS keeps media below a directory that a CDN or static file server
+// publishes at baseURL. Keys are slash-separated paths below the root and
+// can never escape it.
+type LocalFS struct {
+	root    string
+	baseURL string
+}
+
+func NewLocalFS(root, baseURL string) *LocalFS {
+	return &LocalFS{root: root, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *LocalFS) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Put writes to a temporary file and renames it into place, so a reader
+// never sees a partially written output.
+func (s *LocalFS) Put(_ context.Context, key string, body io.Reader, _ string) (string, error) {
+	target := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", err
+	}
+	return publicURL(s.baseURL, key), nil
+}
+
+func (s *LocalFS) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(cleanKey(key)))
+}
+
+func cleanKey(key string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.TrimSpace(key)), "/")
+}
+
+func publicURL(baseURL, key string) string {
+	return baseURL + "/" + cleanKey(key)
+}
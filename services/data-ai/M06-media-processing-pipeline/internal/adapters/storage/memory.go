@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+)
+
+// Memory is an in-process blob store for tests and local runs.
+type Memory struct {
+	mu      sync.RWMutex
+	baseURL string
+	objects map[string][]byte
+}
+
+func NewMemory(baseURL string) *Memory {
+	return &Memory{baseURL: strings.TrimRight(baseURL, "/"), objects: map[string][]byte{}}
+}
+
+func (m *Memory) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, ok := m.objects[cleanKey(key)]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(blob)), nil
+}
+
+func (m *Memory) Put(_ context.Context, key string, body io.Reader, _ string) (string, error) {
+	blob, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[cleanKey(key)] = blob
+	return publicURL(m.baseURL, key), nil
+}
+
+// Object returns a stored blob, for assertions in tests.
+func (m *Memory) Object(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, ok := m.objects[cleanKey(key)]
+	return bytes.Clone(blob), ok
+}
@@ -0,0 +1,117 @@
+package transcoder
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/ports"
+)
+
+// FakeSource is the source format understood by Fake: a JSON document
+// describing the video. Real MP4 and QuickTime files are described by their
+// container boxes; anything else probes as corrupt. A stalling source
+// renders until the job is cancelled or times out.
+type FakeSource struct {
+	domain.MediaInfo
+	Stall bool `json:"stall,omitempty"`
+}
+
+func (s FakeSource) Bytes() []byte {
+	blob, _ := json.Marshal(s)
+	return blob
+}
+
+// FakeOutput is what Fake writes in place of a video or image: a record of
+// what ffmpeg would have been asked to produce.
+type FakeOutput struct {
+	Kind         string               `json:"kind"`
+	Width        int                  `json:"width"`
+	Height       int                  `json:"height"`
+	Crop         domain.Rect          `json:"crop"`
+	Watermark    *ports.WatermarkSpec `json:"watermark,omitempty"`
+	AtSeconds    float64              `json:"at_seconds,omitempty"`
+	Limits       ports.ResourceLimits `json:"limits"`
+	SourceSHA256 string               `json:"source_sha256"`
+}
+
+// Fake is a deterministic Transcoder for tests: the same source and spec
+// always produce the same bytes and progress reports.
+type Fake struct{}
+
+func NewFake() *Fake { return &Fake{} }
+
+func (f *Fake) Probe(ctx context.Context, source string) (domain.MediaInfo, error) {
+	src, _, err := readFakeSource(source)
+	if err != nil {
+		return domain.MediaInfo{}, err
+	}
+	return src.MediaInfo, ctx.Err()
+}
+
+func (f *Fake) Render(ctx context.Context, spec ports.RenderSpec, progress func(float64)) error {
+	src, sum, err := readFakeSource(spec.Source)
+	if err != nil {
+		return err
+	}
+	for _, step := range []float64{0.25, 0.5, 0.75} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(step)
+		}
+		if src.Stall && step == 0.5 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+	if err := writeFakeOutput(spec.Output, FakeOutput{Kind: "video", Width: spec.Width, Height: spec.Height, Crop: spec.Crop, Watermark: spec.Watermark, Limits: spec.Limits, SourceSHA256: sum}); err != nil {
+		return err
+	}
+	if progress != nil {
+		progress(1)
+	}
+	return nil
+}
+
+func (f *Fake) Thumbnail(ctx context.Context, spec ports.ThumbnailSpec) error {
+	_, sum, err := readFakeSource(spec.Source)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeFakeOutput(spec.Output, FakeOutput{Kind: "image", Width: spec.Width, Height: spec.Height, Crop: spec.Crop, AtSeconds: spec.AtSeconds, Limits: spec.Limits, SourceSHA256: sum})
+}
+
+func readFakeSource(path string) (FakeSource, string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return FakeSource{}, "", err
+	}
+	sum := sha256.Sum256(raw)
+	var src FakeSource
+	if err := json.Unmarshal(raw, &src); err != nil {
+		info, inspectErr := domain.InspectContainer(bytes.NewReader(raw), int64(len(raw)))
+		if inspectErr != nil {
+			return FakeSource{}, "", fmt.Errorf("fake: invalid data found when processing input: %w", err)
+		}
+		src.MediaInfo = domain.MediaInfo{Width: info.Width, Height: info.Height, DurationSeconds: info.DurationSeconds, VideoCodec: info.VideoCodec, HasAudio: info.AudioCodec != ""}
+	}
+	return src, hex.EncodeToString(sum[:]), nil
+}
+
+func writeFakeOutput(path string, out FakeOutput) error {
+	blob, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, blob, 0o644)
+}
@@ -0,0 +1,296 @@
+package transcoder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/ports"
+)
+
+type Config struct {
+	FFmpegPath  string
+	FFprobePath string
+	// FontFile is passed to drawtext for watermarks; empty relies on the
+	// fontconfig default of the ffmpeg build.
+	FontFile string
+}
+
+// FFmpeg runs ffmpeg and ffprobe as subprocesses. Every invocation is
+// bound to its context, so a job timeout or cancellation kills the process.
+type FFmpeg struct {
+	cfg Config
+}
+
+func NewFFmpeg(cfg Config) *FFmpeg {
+	if cfg.FFmpegPath == "" {
+		cfg.FFmpegPath = "ffmpeg"
+	}
+	if cfg.FFprobePath == "" {
+		cfg.FFprobePath = "ffprobe"
+	}
+	return &FFmpeg{cfg: cfg}
+}
+
+type probeOutput struct {
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		Duration  string `json:"duration"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+func (f *FFmpeg) Probe(ctx context.Context, source string) (domain.MediaInfo, error) {
+	var out strings.Builder
+	if err := f.run(ctx, f.cfg.FFprobePath, []string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams", "-i", source}, &out); err != nil {
+		return domain.MediaInfo{}, err
+	}
+	var parsed probeOutput
+	if err := json.Unmarshal([]byte(out.String()), &parsed); err != nil {
+		return domain.MediaInfo{}, fmt.Errorf("ffprobe: %w", err)
+	}
+	info := domain.MediaInfo{}
+	for _, stream := range parsed.Streams {
+		switch stream.CodecType {
+		case "video":
+			if info.VideoCodec == "" {
+				info.VideoCodec = stream.CodecName
+				info.Width, info.Height = stream.Width, stream.Height
+				info.DurationSeconds, _ = strconv.ParseFloat(stream.Duration, 64)
+			}
+		case "audio":
+			info.HasAudio = true
+		}
+	}
+	if d, err := strconv.ParseFloat(parsed.Format.Duration, 64); err == nil && d > 0 {
+		info.DurationSeconds = d
+	}
+	if info.VideoCodec == "" {
+		return domain.MediaInfo{}, fmt.Errorf("ffprobe: no video stream")
+	}
+	info.Content = f.detectContent(ctx, source, info)
+	return info, nil
+}
+
+var cropLine = regexp.MustCompile(`crop=(\d+):(\d+):(\d+):(\d+)`)
+
+// detectContent samples a few seconds from the middle of the source with
+// cropdetect to find letterbox or pillarbox bars. Failures are not fatal:
+// the whole frame is then treated as picture.
+func (f *FFmpeg) detectContent(ctx context.Context, source string, info domain.MediaInfo) domain.Rect {
+	start := info.DurationSeconds / 2
+	if start > 2 {
+		start -= 1
+	}
+	stderr := &tailBuffer{max: 16 << 10}
+	cmd := exec.CommandContext(ctx, f.cfg.FFmpegPath, "-nostdin", "-hide_banner", "-loglevel", "info", "-threads", "1",
+		"-ss", formatSeconds(start), "-i", source, "-t", "2", "-an", "-vf", "cropdetect=limit=24:round=2:reset=0", "-f", "null", "-")
+	cmd.Stderr = stderr
+	cmd.WaitDelay = 5 * time.Second
+	if err := cmd.Run(); err != nil {
+		return domain.Rect{}
+	}
+	matches := cropLine.FindAllStringSubmatch(stderr.String(), -1)
+	if len(matches) == 0 {
+		return domain.Rect{}
+	}
+	last := matches[len(matches)-1]
+	vals := make([]int, 4)
+	for i := range vals {
+		vals[i], _ = strconv.Atoi(last[i+1])
+	}
+	rect := domain.Rect{Width: vals[0], Height: vals[1], X: vals[2], Y: vals[3]}
+	// Mostly black samples make cropdetect report tiny windows; only trust
+	// it when at least half of each dimension is picture.
+	if rect.Width*2 < info.Width || rect.Height*2 < info.Height || rect.X+rect.Width > info.Width || rect.Y+rect.Height > info.Height {
+		return domain.Rect{}
+	}
+	if rect.Width == info.Width && rect.Height == info.Height {
+		return domain.Rect{}
+	}
+	return rect
+}
+
+func (f *FFmpeg) Render(ctx context.Context, spec ports.RenderSpec, progress func(float64)) error {
+	filters := frameFilters(spec.Crop, spec.Width, spec.Height)
+	if spec.Watermark != nil {
+		textFile := spec.Output + ".watermark.txt"
+		if err := os.WriteFile(textFile, []byte(spec.Watermark.Text), 0o600); err != nil {
+			return err
+		}
+		defer os.Remove(textFile)
+		filters = append(filters, f.drawtext(textFile, *spec.Watermark))
+	}
+	args := []string{"-nostdin", "-hide_banner", "-loglevel", "error", "-y"}
+	args = append(args, threadArgs(spec.Limits)...)
+	args = append(args, "-i", spec.Source)
+	if spec.Limits.MaxDurationSeconds > 0 {
+		args = append(args, "-t", formatSeconds(spec.Limits.MaxDurationSeconds))
+	}
+	args = append(args,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", strings.Join(filters, ","),
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-b:a", "128k",
+		"-movflags", "+faststart",
+	)
+	if spec.Limits.Threads > 0 {
+		args = append(args, "-threads", strconv.Itoa(spec.Limits.Threads))
+	}
+	if spec.Limits.MaxOutputBytes > 0 {
+		args = append(args, "-fs", strconv.FormatInt(spec.Limits.MaxOutputBytes, 10))
+	}
+	args = append(args, "-progress", "pipe:1", "-nostats", spec.Output)
+
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		readProgress(reader, spec.DurationSeconds, progress)
+	}()
+	err := f.run(ctx, f.cfg.FFmpegPath, args, writer)
+	_ = writer.Close()
+	<-done
+	return err
+}
+
+func (f *FFmpeg) Thumbnail(ctx context.Context, spec ports.ThumbnailSpec) error {
+	args := []string{"-nostdin", "-hide_banner", "-loglevel", "error", "-y"}
+	args = append(args, threadArgs(spec.Limits)...)
+	args = append(args,
+		"-ss", formatSeconds(spec.AtSeconds), "-i", spec.Source,
+		"-frames:v", "1", "-an",
+		"-vf", strings.Join(frameFilters(spec.Crop, spec.Width, spec.Height), ","),
+		"-q:v", "3", spec.Output,
+	)
+	return f.run(ctx, f.cfg.FFmpegPath, args, nil)
+}
+
+func (f *FFmpeg) drawtext(textFile string, mark ports.WatermarkSpec) string {
+	x, y := "w-tw-h/30", "h-th-h/30"
+	switch mark.Placement {
+	case "top-left":
+		x, y = "h/30", "h/30"
+	case "top-right":
+		y = "h/30"
+	case "bottom-left":
+		x = "h/30"
+	case "center":
+		x, y = "(w-tw)/2", "(h-th)/2"
+	}
+	opacity := mark.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = 0.25
+	}
+	opts := []string{
+		"textfile=" + escapeFilterValue(textFile),
+		"fontsize=h/24",
+		fmt.Sprintf("fontcolor=white@%.2f", opacity),
+		fmt.Sprintf("shadowcolor=black@%.2f", opacity),
+		"shadowx=2", "shadowy=2",
+		"x=" + x, "y=" + y,
+	}
+	if f.cfg.FontFile != "" {
+		opts = append(opts, "fontfile="+escapeFilterValue(f.cfg.FontFile))
+	}
+	return "drawtext=" + strings.Join(opts, ":")
+}
+
+// run executes a tool and keeps only the end of its stderr for errors, so a
+// source that makes ffmpeg log endlessly cannot exhaust memory.
+func (f *FFmpeg) run(ctx context.Context, name string, args []string, stdout io.Writer) error {
+	stderr := &tailBuffer{max: 4 << 10}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = 5 * time.Second
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%s: %w: %s", filepath.Base(name), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// readProgress follows ffmpeg's -progress key=value stream and reports the
+// encoded position as a fraction of the source duration.
+func readProgress(r io.Reader, durationSeconds float64, progress func(float64)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || progress == nil {
+			continue
+		}
+		switch key {
+		case "out_time_us", "out_time_ms":
+			// Both keys carry microseconds.
+			us, err := strconv.ParseFloat(value, 64)
+			if err == nil && durationSeconds > 0 && us >= 0 {
+				progress(min(us/1e6/durationSeconds, 1))
+			}
+		case "progress":
+			if value == "end" {
+				progress(1)
+			}
+		}
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
+func frameFilters(crop domain.Rect, width, height int) []string {
+	filters := []string{}
+	if !crop.IsZero() {
+		filters = append(filters, fmt.Sprintf("crop=%d:%d:%d:%d", crop.Width, crop.Height, crop.X, crop.Y))
+	}
+	return append(filters, fmt.Sprintf("scale=%d:%d", width, height), "setsar=1")
+}
+
+func threadArgs(limits ports.ResourceLimits) []string {
+	if limits.Threads <= 0 {
+		return nil
+	}
+	n := strconv.Itoa(limits.Threads)
+	return []string{"-threads", n, "-filter_threads", n}
+}
+
+func formatSeconds(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// escapeFilterValue escapes a value embedded in a filtergraph option.
+func escapeFilterValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`, `,`, `\,`, `;`, `\;`, `[`, `\[`, `]`, `\]`).Replace(v)
+}
+
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.max:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.buf)
+}
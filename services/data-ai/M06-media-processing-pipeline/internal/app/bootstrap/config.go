@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,6 +22,23 @@ type Config struct {
 	EventDedupTTLHours  int
 	WorkerPollSeconds   int
 	FullPipelineEnabled bool
+
+	JobTimeoutSeconds  int
+	TranscodeThreads   int
+	MaxOutputMB        int
+	MaxSourceSeconds   int
+	ThumbnailPositions []string
+	WorkDir            string
+
+	FFmpegPath        string
+	FFprobePath       string
+	WatermarkFontFile string
+
+	BlobRoot      string
+	PublicBaseURL string
+
+	UploadDir     string
+	UploadBaseURL string
 }
 
 type configFile struct {
@@ -32,10 +51,13 @@ type configFile struct {
 		CampaignGRPCURL string `yaml:"campaign_grpc_url"`
 	} `yaml:"dependencies"`
 	Pipeline struct {
-		IdempotencyTTLHours int   `yaml:"idempotency_ttl_hours"`
-		EventDedupTTLHours  int   `yaml:"event_dedup_ttl_hours"`
-		WorkerPollSeconds   int   `yaml:"worker_poll_seconds"`
-		FullPipelineEnabled *bool `yaml:"full_pipeline_enabled"`
+		IdempotencyTTLHours int      `yaml:"idempotency_ttl_hours"`
+		EventDedupTTLHours  int      `yaml:"event_dedup_ttl_hours"`
+		WorkerPollSeconds   int      `yaml:"worker_poll_seconds"`
+		FullPipelineEnabled *bool    `yaml:"full_pipeline_enabled"`
+		JobTimeoutSeconds   int      `yaml:"job_timeout_seconds"`
+		TranscodeThreads    int      `yaml:"transcode_threads"`
+		ThumbnailPositions  []string `yaml:"thumbnail_positions"`
 	} `yaml:"pipeline"`
 }
 
@@ -48,6 +70,16 @@ func LoadConfig(path string) (Config, error) {
 		EventDedupTTLHours:  168,
 		WorkerPollSeconds:   2,
 		FullPipelineEnabled: true,
+		JobTimeoutSeconds:   600,
+		TranscodeThreads:    2,
+		MaxOutputMB:         2048,
+		MaxSourceSeconds:    3600,
+		ThumbnailPositions:  []string{"10%", "50%", "90%"},
+		FFmpegPath:          "ffmpeg",
+		FFprobePath:         "ffprobe",
+		BlobRoot:            "data/media",
+		PublicBaseURL:       "https://cdn.viralforge",
+		UploadDir:           "data/uploads",
 	}
 	raw, err := os.ReadFile(path)
 	if err == nil {
@@ -79,6 +111,15 @@ func LoadConfig(path string) (Config, error) {
 		if f.Pipeline.FullPipelineEnabled != nil {
 			cfg.FullPipelineEnabled = *f.Pipeline.FullPipelineEnabled
 		}
+		if f.Pipeline.JobTimeoutSeconds > 0 {
+			cfg.JobTimeoutSeconds = f.Pipeline.JobTimeoutSeconds
+		}
+		if f.Pipeline.TranscodeThreads > 0 {
+			cfg.TranscodeThreads = f.Pipeline.TranscodeThreads
+		}
+		if len(f.Pipeline.ThumbnailPositions) > 0 {
+			cfg.ThumbnailPositions = f.Pipeline.ThumbnailPositions
+		}
 	}
 	cfg.CampaignGRPCURL = envOrDefault("CAMPAIGN_GRPC_URL", cfg.CampaignGRPCURL)
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
@@ -87,6 +128,27 @@ func LoadConfig(path string) (Config, error) {
 	cfg.EventDedupTTLHours = envInt("EVENT_DEDUP_TTL_HOURS", cfg.EventDedupTTLHours)
 	cfg.WorkerPollSeconds = envInt("WORKER_POLL_SECONDS", cfg.WorkerPollSeconds)
 	cfg.FullPipelineEnabled = envBool("FULL_PIPELINE_ENABLED", cfg.FullPipelineEnabled)
+	cfg.JobTimeoutSeconds = envInt("MEDIA_JOB_TIMEOUT_SECONDS", cfg.JobTimeoutSeconds)
+	cfg.TranscodeThreads = envInt("MEDIA_TRANSCODE_THREADS", cfg.TranscodeThreads)
+	cfg.MaxOutputMB = envInt("MEDIA_MAX_OUTPUT_MB", cfg.MaxOutputMB)
+	cfg.MaxSourceSeconds = envInt("MEDIA_MAX_SOURCE_SECONDS", cfg.MaxSourceSeconds)
+	if raw := os.Getenv("MEDIA_THUMBNAIL_POSITIONS"); raw != "" {
+		cfg.ThumbnailPositions = strings.Split(raw, ",")
+	}
+	cfg.WorkDir = envOrDefault("MEDIA_WORK_DIR", cfg.WorkDir)
+	cfg.FFmpegPath = envOrDefault("FFMPEG_PATH", cfg.FFmpegPath)
+	cfg.FFprobePath = envOrDefault("FFPROBE_PATH", cfg.FFprobePath)
+	cfg.WatermarkFontFile = envOrDefault("WATERMARK_FONT_FILE", cfg.WatermarkFontFile)
+	cfg.BlobRoot = envOrDefault("MEDIA_BLOB_ROOT", cfg.BlobRoot)
+	cfg.PublicBaseURL = envOrDefault("MEDIA_PUBLIC_BASE_URL", cfg.PublicBaseURL)
+	cfg.UploadDir = envOrDefault("MEDIA_UPLOAD_DIR", cfg.UploadDir)
+	cfg.UploadBaseURL = envOrDefault("MEDIA_UPLOAD_BASE_URL", cfg.UploadBaseURL)
+	for i, pos := range cfg.ThumbnailPositions {
+		cfg.ThumbnailPositions[i] = strings.TrimSpace(pos)
+		if _, err := domain.ParseThumbnailPosition(cfg.ThumbnailPositions[i], 0); err != nil {
+			return Config{}, fmt.Errorf("invalid thumbnail position %q", pos)
+		}
+	}
 	return cfg, nil
 }
 
@@ -135,3 +197,7 @@ func (c Config) EventDedupTTL() time.Duration {
 func (c Config) WorkerPollInterval() time.Duration {
 	return time.Duration(c.WorkerPollSeconds) * time.Second
 }
+
+func (c Config) JobTimeout() time.Duration {
+	return time.Duration(c.JobTimeoutSeconds) * time.Second
+}
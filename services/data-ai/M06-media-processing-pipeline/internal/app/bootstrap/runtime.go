@@ -12,10 +12,13 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/http"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/storage"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/transcoder"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/application"
 	"google.golang.org/grpc"
 )
@@ -23,6 +26,7 @@ import (
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
@@ -34,7 +38,11 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 
 	repos := postgres.NewRepositories()
@@ -48,18 +56,29 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 			EventDedupTTL:       cfg.EventDedupTTL(),
 			QueuePollInterval:   cfg.WorkerPollInterval(),
 			FullPipelineEnabled: cfg.FullPipelineEnabled,
+			UploadBaseURL:       cfg.UploadBaseURL,
+			JobTimeout:          cfg.JobTimeout(),
+			WorkDir:             cfg.WorkDir,
+			TranscodeThreads:    cfg.TranscodeThreads,
+			MaxOutputBytes:      int64(cfg.MaxOutputMB) << 20,
+			MaxSourceSeconds:    float64(cfg.MaxSourceSeconds),
+			ThumbnailPositions:  cfg.ThumbnailPositions,
 		},
 		Assets: repos.Assets, Jobs: repos.Jobs, Outputs: repos.Outputs, Thumbnails: repos.Thumbnails, Watermarks: repos.Watermarks, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
 		Campaign: grpcadapter.NewCampaignClient(cfg.CampaignGRPCURL),
 		Queue:    queue,
 		DLQ:      dlq,
+
+		Transcoder: transcoder.NewFFmpeg(transcoder.Config{FFmpegPath: cfg.FFmpegPath, FFprobePath: cfg.FFprobePath, FontFile: cfg.WatermarkFontFile}),
+		Blobs:      storage.NewLocalFS(cfg.BlobRoot, cfg.PublicBaseURL),
+		Uploads:    storage.NewLocalUploads(cfg.UploadDir),
 	})
 
 	handler := httpadapter.NewHandler(service)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewMediaInternalServer(service))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
@@ -68,12 +87,13 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	worker := eventadapter.NewWorker(logger, service, cfg.WorkerPollInterval())
 
 	_ = ctx
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
 
 	go func() {
@@ -102,6 +122,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -115,3 +136,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
@@ -29,6 +29,9 @@ func (s *Service) CreateUpload(ctx context.Context, actor Actor, input CreateUpl
 		FileSize:       input.FileSize,
 		ChecksumSHA256: strings.TrimSpace(input.ChecksumSHA256),
 	}
+	for _, pos := range input.ThumbnailPositions {
+		candidate.ThumbnailPositions = append(candidate.ThumbnailPositions, strings.TrimSpace(pos))
+	}
 	if err := domain.ValidateUploadInput(candidate); err != nil {
 		return UploadResult{}, err
 	}
@@ -42,9 +45,12 @@ func (s *Service) CreateUpload(ctx context.Context, actor Actor, input CreateUpl
 		return UploadResult{}, err
 	}
 
+	// The canonical key names only the submission and file, so it is stored
+	// per uploader to keep one user from replaying another's upload.
+	idempotencyKey := actor.SubjectID + "|" + actor.IdempotencyKey
 	now := s.nowFn()
 	requestHash := hashPayload(candidate)
-	rec, err := s.idempotency.Get(ctx, actor.IdempotencyKey, now)
+	rec, err := s.idempotency.Get(ctx, idempotencyKey, now)
 	if err != nil {
 		return UploadResult{}, err
 	}
@@ -59,15 +65,23 @@ func (s *Service) CreateUpload(ctx context.Context, actor Actor, input CreateUpl
 		if err := json.Unmarshal(rec.ResponseBody, &out); err != nil {
 			return UploadResult{}, err
 		}
-		return out, nil
+		// Replaying an upload that has since been rejected or terminated
+		// would hand back a dead URL, so the key is freed for a new one.
+		prior, err := s.assets.GetByID(ctx, out.AssetID)
+		if err != nil || prior.Status != domain.AssetStatusFailed {
+			return out, nil
+		}
+		if err := s.idempotency.Release(ctx, idempotencyKey); err != nil {
+			return UploadResult{}, err
+		}
 	}
-	if err := s.idempotency.Reserve(ctx, actor.IdempotencyKey, requestHash, now.Add(s.cfg.IdempotencyTTL)); err != nil {
+	if err := s.idempotency.Reserve(ctx, idempotencyKey, requestHash, now.Add(s.cfg.IdempotencyTTL)); err != nil {
 		return UploadResult{}, err
 	}
 	completed := false
 	defer func() {
 		if !completed {
-			_ = s.idempotency.Release(ctx, actor.IdempotencyKey)
+			_ = s.idempotency.Release(ctx, idempotencyKey)
 		}
 	}()
 
@@ -78,14 +92,16 @@ func (s *Service) CreateUpload(ctx context.Context, actor Actor, input CreateUpl
 	if err != nil {
 		approval = domain.ApprovalStatusPending
 	}
-	existing, err := s.assets.GetBySubmissionAndChecksum(ctx, candidate.SubmissionID, checksum)
-	if err == nil {
-		out := UploadResult{AssetID: existing.AssetID, UploadURL: signedUploadURL(existing.AssetID), ExpiresIn: 3600}
+	// A retry of the same file by the same uploader resumes its upload; a
+	// failed one (rejected or terminated) starts over under a new asset.
+	existing, err := s.assets.GetBySubmissionAndChecksum(ctx, candidate.SubmissionID, checksum, actor.SubjectID)
+	if err == nil && existing.Status != domain.AssetStatusFailed {
+		out := UploadResult{AssetID: existing.AssetID, UploadURL: s.uploadURL(existing.AssetID), ExpiresIn: 3600}
 		payload, marshalErr := json.Marshal(out)
 		if marshalErr != nil {
 			return UploadResult{}, marshalErr
 		}
-		if err := s.idempotency.Complete(ctx, actor.IdempotencyKey, 201, payload, s.nowFn()); err != nil {
+		if err := s.idempotency.Complete(ctx, idempotencyKey, 201, payload, s.nowFn()); err != nil {
 			return UploadResult{}, err
 		}
 		completed = true
@@ -97,42 +113,31 @@ func (s *Service) CreateUpload(ctx context.Context, actor Actor, input CreateUpl
 
 	assetID := uuid.NewString()
 	asset := domain.MediaAsset{
-		AssetID:          assetID,
-		SubmissionID:     candidate.SubmissionID,
-		OriginalFilename: candidate.FileName,
-		MIMEType:         candidate.MIMEType,
-		FileSize:         candidate.FileSize,
-		SourceS3URL:      fmt.Sprintf("s3://media-raw/%s", assetID),
-		Status:           domain.AssetStatusProcessing,
-		ApprovalStatus:   domain.NormalizeApprovalStatus(string(approval)),
-		ChecksumSHA256:   checksum,
-		CreatedAt:        now,
-		UpdatedAt:        now,
-	}
+		AssetID:            assetID,
+		SubmissionID:       candidate.SubmissionID,
+		OriginalFilename:   candidate.FileName,
+		MIMEType:           candidate.MIMEType,
+		FileSize:           candidate.FileSize,
+		SourceS3URL:        fmt.Sprintf("s3://media-raw/%s", assetID),
+		Status:             domain.AssetStatusAwaitingUpload,
+		ApprovalStatus:     domain.NormalizeApprovalStatus(string(approval)),
+		ChecksumSHA256:     checksum,
+		ChecksumDeclared:   strings.TrimSpace(input.ChecksumSHA256) != "",
+		UploadedBy:         actor.SubjectID,
+		ThumbnailPositions: candidate.ThumbnailPositions,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	// Jobs are created once the bytes have arrived and been verified.
 	if err := s.assets.Create(ctx, asset); err != nil {
 		return UploadResult{}, err
 	}
-	jobs := domain.NewDefaultJobs(asset.AssetID, now)
-	if err := s.jobs.CreateMany(ctx, jobs); err != nil {
-		return UploadResult{}, err
-	}
-	for _, job := range jobs {
-		if err := s.queue.Enqueue(ctx, job.JobID); err != nil {
-			asset.Status = domain.AssetStatusFailed
-			asset.LastErrorCode = "queue_unavailable"
-			asset.LastErrorMessage = err.Error()
-			asset.UpdatedAt = s.nowFn()
-			_ = s.assets.Update(ctx, asset)
-			_ = s.campaign.UpdateMediaStatus(ctx, asset.SubmissionID, asset.AssetID, string(asset.Status), asset.LastErrorCode)
-			return UploadResult{}, err
-		}
-	}
-	out := UploadResult{AssetID: asset.AssetID, UploadURL: signedUploadURL(asset.AssetID), ExpiresIn: 3600}
+	out := UploadResult{AssetID: asset.AssetID, UploadURL: s.uploadURL(asset.AssetID), ExpiresIn: 3600}
 	payload, err := json.Marshal(out)
 	if err != nil {
 		return UploadResult{}, err
 	}
-	if err := s.idempotency.Complete(ctx, actor.IdempotencyKey, 201, payload, s.nowFn()); err != nil {
+	if err := s.idempotency.Complete(ctx, idempotencyKey, 201, payload, s.nowFn()); err != nil {
 		return UploadResult{}, err
 	}
 	completed = true
@@ -163,11 +168,20 @@ func (s *Service) GetAssetStatus(ctx context.Context, actor Actor, assetID strin
 	for _, item := range thumbs {
 		thumbRows = append(thumbRows, contracts.ThumbnailDTO{Position: item.Position, AspectRatio: string(item.AspectRatio), URL: item.S3URL})
 	}
+	jobs, err := s.jobs.ListByAsset(ctx, asset.AssetID)
+	if err != nil {
+		return contracts.AssetStatusResponse{}, err
+	}
+	jobRows := make([]contracts.JobDTO, 0, len(jobs))
+	for _, job := range jobs {
+		jobRows = append(jobRows, jobDTO(job))
+	}
 	return contracts.AssetStatusResponse{
 		AssetID:    asset.AssetID,
 		Status:     string(asset.Status),
 		Outputs:    outRows,
 		Thumbnails: thumbRows,
+		Jobs:       jobRows,
 		ErrorCode:  asset.LastErrorCode,
 		Error:      asset.LastErrorMessage,
 	}, nil
@@ -295,7 +309,7 @@ func (s *Service) GetAssetMetadata(ctx context.Context, assetID string) (Metadat
 	if err != nil {
 		return MetadataResult{}, err
 	}
-	return MetadataResult{
+	out := MetadataResult{
 		AssetID:         asset.AssetID,
 		ContentType:     asset.MIMEType,
 		FileSizeBytes:   asset.FileSize,
@@ -303,11 +317,19 @@ func (s *Service) GetAssetMetadata(ctx context.Context, assetID string) (Metadat
 		Height:          1080,
 		DurationSeconds: float64(asset.DurationSeconds),
 		Codec:           "h264",
-	}, nil
+	}
+	// Probed values replace the defaults once a job has read the source.
+	if asset.Width > 0 && asset.Height > 0 {
+		out.Width, out.Height = int32(asset.Width), int32(asset.Height)
+	}
+	if asset.VideoCodec != "" {
+		out.Codec = asset.VideoCodec
+	}
+	return out, nil
 }
 
-func signedUploadURL(assetID string) string {
-	return fmt.Sprintf("https://s3.aws.com/upload/%s", assetID)
+func (s *Service) uploadURL(assetID string) string {
+	return strings.TrimRight(s.cfg.UploadBaseURL, "/") + "/v1/media/uploads/" + assetID
 }
 
 func hashPayload(value interface{}) string {
@@ -2,11 +2,9 @@ package application
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"strings"
 
-	"github.com/google/uuid"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/contracts"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
 )
@@ -23,6 +21,10 @@ func (s *Service) ProcessNextJob(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// Jobs cancelled while queued, or queued twice by a retry, are dropped.
+	if job.Status != domain.JobStatusQueued {
+		return nil
+	}
 	asset, err := s.assets.GetByID(ctx, job.AssetID)
 	if err != nil {
 		return err
@@ -32,6 +34,7 @@ func (s *Service) ProcessNextJob(ctx context.Context) error {
 	job.Status = domain.JobStatusProcessing
 	job.Attempts++
 	job.StartedAt = started
+	job.Progress = 0
 	if err := s.jobs.Update(ctx, job); err != nil {
 		return err
 	}
@@ -39,53 +42,39 @@ func (s *Service) ProcessNextJob(ctx context.Context) error {
 	if strings.ToLower(strings.TrimSpace(asset.MIMEType)) != "video/mp4" && strings.ToLower(strings.TrimSpace(asset.MIMEType)) != "video/quicktime" {
 		return s.failJob(ctx, asset, job, "unsupported_codec")
 	}
-
-	switch job.JobType {
-	case domain.JobTypeTranscode1080:
-		err = s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: domain.Profile1080, AspectRatio: domain.Aspect169, S3URL: fmt.Sprintf("https://cdn.viralforge/media/%s/1080p.mp4", asset.AssetID), CreatedAt: s.nowFn()})
-	case domain.JobTypeTranscode720:
-		err = s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: domain.Profile720, AspectRatio: domain.Aspect169, S3URL: fmt.Sprintf("https://cdn.viralforge/media/%s/720p.mp4", asset.AssetID), CreatedAt: s.nowFn()})
-	case domain.JobTypeAspect916:
-		err = s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: domain.Profile1080, AspectRatio: domain.Aspect916, S3URL: fmt.Sprintf("https://cdn.viralforge/media/%s/9x16-1080p.mp4", asset.AssetID), CreatedAt: s.nowFn()})
-		if err == nil {
-			err = s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: domain.Profile720, AspectRatio: domain.Aspect916, S3URL: fmt.Sprintf("https://cdn.viralforge/media/%s/9x16-720p.mp4", asset.AssetID), CreatedAt: s.nowFn()})
-		}
-	case domain.JobTypeAspect11:
-		err = s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: domain.Profile1080, AspectRatio: domain.Aspect11, S3URL: fmt.Sprintf("https://cdn.viralforge/media/%s/1x1-1080p.mp4", asset.AssetID), CreatedAt: s.nowFn()})
-		if err == nil {
-			err = s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: domain.Profile720, AspectRatio: domain.Aspect11, S3URL: fmt.Sprintf("https://cdn.viralforge/media/%s/1x1-720p.mp4", asset.AssetID), CreatedAt: s.nowFn()})
-		}
-	case domain.JobTypeThumbnails:
-		for _, ratio := range []domain.AspectRatio{domain.Aspect169, domain.Aspect916, domain.Aspect11} {
-			for _, pos := range []string{"10%", "50%", "90%"} {
-				err = s.thumbnails.Upsert(ctx, domain.MediaThumbnail{
-					ThumbnailID: uuid.NewString(),
-					AssetID:     asset.AssetID,
-					Position:    pos,
-					AspectRatio: ratio,
-					S3URL:       fmt.Sprintf("https://cdn.viralforge/thumbnails/%s/%s/%s.jpg", asset.AssetID, strings.ReplaceAll(string(ratio), ":", "x"), strings.TrimSuffix(pos, "%")),
-					CreatedAt:   s.nowFn(),
-				})
-				if err != nil {
-					break
-				}
-			}
-			if err != nil {
-				break
-			}
-		}
-	case domain.JobTypeWatermark:
-		if asset.ApprovalStatus == domain.ApprovalStatusApproved {
-			err = s.watermarks.Upsert(ctx, domain.WatermarkRecord{WatermarkID: uuid.NewString(), AssetID: asset.AssetID, WatermarkText: fmt.Sprintf("wmk_%s", asset.SubmissionID), Placement: "bottom-right", Opacity: 0.25, AppliedAt: s.nowFn()})
-		}
-	default:
+	if !isKnownJobType(job.JobType) {
 		return s.failJob(ctx, asset, job, "unsupported_job_type")
 	}
+	if s.transcoder == nil || s.blobs == nil {
+		return s.failJob(ctx, asset, job, "transcoder_unavailable")
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, s.cfg.JobTimeout)
+	defer cancel()
+	s.trackRunning(job.JobID, cancel)
+	defer s.untrackRunning(job.JobID)
+	progress := &progressReporter{service: s, ctx: context.WithoutCancel(ctx), jobID: job.JobID, cancel: cancel}
+	runErr := s.runJob(jobCtx, &asset, job, progress.report)
+
+	// Bookkeeping continues even when the worker is being stopped.
+	ctx = context.WithoutCancel(ctx)
+	latest, err := s.jobs.GetByID(ctx, job.JobID)
 	if err != nil {
-		return s.failJob(ctx, asset, job, err.Error())
+		return err
+	}
+	if latest.Status == domain.JobStatusCancelled {
+		if asset, err = s.assets.GetByID(ctx, asset.AssetID); err != nil {
+			return err
+		}
+		return s.refreshAssetStatus(ctx, asset)
+	}
+	job.Progress = latest.Progress
+	if runErr != nil {
+		return s.failJob(ctx, asset, job, jobFailureReason(runErr, jobCtx))
 	}
 
 	job.Status = domain.JobStatusCompleted
+	job.Progress = 1
 	job.FinishedAt = s.nowFn()
 	job.ErrorMessage = ""
 	if err := s.jobs.Update(ctx, job); err != nil {
@@ -94,12 +83,53 @@ func (s *Service) ProcessNextJob(ctx context.Context) error {
 	return s.refreshAssetStatus(ctx, asset)
 }
 
+// CancelJob stops a queued or running job. A running job on this instance
+// is interrupted at once; one running elsewhere stops at its next progress
+// report. Cancelled jobs fail their asset and can be restarted with
+// RetryAsset.
+func (s *Service) CancelJob(ctx context.Context, actor Actor, jobID string) (contracts.JobDTO, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return contracts.JobDTO{}, domain.ErrUnauthorized
+	}
+	if strings.ToLower(strings.TrimSpace(actor.Role)) != "admin" {
+		return contracts.JobDTO{}, domain.ErrForbidden
+	}
+	job, err := s.jobs.GetByID(ctx, strings.TrimSpace(jobID))
+	if err != nil {
+		return contracts.JobDTO{}, err
+	}
+	if job.Status == domain.JobStatusCancelled {
+		return jobDTO(job), nil
+	}
+	if domain.IsTerminal(job.Status) {
+		return contracts.JobDTO{}, domain.ErrConflict
+	}
+	job.Status = domain.JobStatusCancelled
+	job.ErrorMessage = "cancelled"
+	job.FinishedAt = s.nowFn()
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return contracts.JobDTO{}, err
+	}
+	s.cancelRunning(job.JobID)
+	asset, err := s.assets.GetByID(ctx, job.AssetID)
+	if err != nil {
+		return contracts.JobDTO{}, err
+	}
+	asset.LastErrorCode = "job_cancelled"
+	asset.LastErrorMessage = "job " + job.JobID + " cancelled by " + actor.SubjectID
+	if err := s.refreshAssetStatus(ctx, asset); err != nil {
+		return contracts.JobDTO{}, err
+	}
+	return jobDTO(job), nil
+}
+
 func (s *Service) failJob(ctx context.Context, asset domain.MediaAsset, job domain.MediaJob, reason string) error {
 	now := s.nowFn()
 	retryable := domain.IsRetryableJobFailure(reason)
 	if retryable && job.Attempts < domain.MaxJobAttempts {
 		job.Status = domain.JobStatusQueued
 		job.ErrorMessage = reason
+		job.Progress = 0
 		job.QueuedAt = now
 		job.FinishedAt = now
 		if err := s.jobs.Update(ctx, job); err != nil {
@@ -151,7 +181,7 @@ func (s *Service) refreshAssetStatus(ctx context.Context, asset domain.MediaAsse
 	hasFailed := false
 	allCompleted := len(jobs) > 0
 	for _, job := range jobs {
-		if job.Status == domain.JobStatusFailed {
+		if job.Status == domain.JobStatusFailed || job.Status == domain.JobStatusCancelled {
 			hasFailed = true
 		}
 		if job.Status != domain.JobStatusCompleted {
@@ -0,0 +1,320 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/contracts"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/ports"
+)
+
+// rendition is one output video a job produces.
+type rendition struct {
+	profile   domain.OutputProfile
+	aspect    domain.AspectRatio
+	watermark *ports.WatermarkSpec
+}
+
+var jobRenditions = map[domain.JobType][]rendition{
+	domain.JobTypeTranscode1080: {{profile: domain.Profile1080, aspect: domain.Aspect169}},
+	domain.JobTypeTranscode720:  {{profile: domain.Profile720, aspect: domain.Aspect169}},
+	domain.JobTypeAspect916:     {{profile: domain.Profile1080, aspect: domain.Aspect916}, {profile: domain.Profile720, aspect: domain.Aspect916}},
+	domain.JobTypeAspect11:      {{profile: domain.Profile1080, aspect: domain.Aspect11}, {profile: domain.Profile720, aspect: domain.Aspect11}},
+}
+
+var thumbnailAspects = []domain.AspectRatio{domain.Aspect169, domain.Aspect916, domain.Aspect11}
+
+func isKnownJobType(jobType domain.JobType) bool {
+	_, ok := jobRenditions[jobType]
+	return ok || jobType == domain.JobTypeThumbnails || jobType == domain.JobTypeWatermark
+}
+
+// jobError marks a failure with a specific reason; anything else a job
+// returns is reported as transcode_failed.
+type jobError struct {
+	reason string
+	err    error
+}
+
+func (e *jobError) Error() string {
+	if e.err == nil {
+		return e.reason
+	}
+	return e.reason + ": " + e.err.Error()
+}
+
+func (e *jobError) Unwrap() error { return e.err }
+
+func jobFailureReason(err error, jobCtx context.Context) string {
+	var failure *jobError
+	switch {
+	case errors.As(err, &failure):
+		return failure.reason
+	case errors.Is(jobCtx.Err(), context.DeadlineExceeded):
+		return "job_timeout"
+	case jobCtx.Err() != nil:
+		return "worker_stopped"
+	case errors.Is(err, domain.ErrNotFound):
+		return "source_missing"
+	default:
+		return "transcode_failed"
+	}
+}
+
+// runJob downloads the source into a scratch directory, probes it and
+// produces the job's outputs. Everything it starts is bound to ctx.
+func (s *Service) runJob(ctx context.Context, asset *domain.MediaAsset, job domain.MediaJob, report func(float64)) error {
+	var watermark *ports.WatermarkSpec
+	if job.JobType == domain.JobTypeWatermark {
+		if asset.ApprovalStatus != domain.ApprovalStatusApproved {
+			return nil
+		}
+		watermark = &ports.WatermarkSpec{Text: fmt.Sprintf("wmk_%s", asset.SubmissionID), Placement: "bottom-right", Opacity: 0.25}
+	}
+
+	workDir, err := os.MkdirTemp(s.cfg.WorkDir, "m06-job-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+	source := filepath.Join(workDir, "source")
+	if err := s.fetchSource(ctx, *asset, source); err != nil {
+		return err
+	}
+	info, err := s.transcoder.Probe(ctx, source)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return &jobError{reason: "corrupt_source", err: err}
+	}
+	if reason := domain.ValidateSource(info, domain.SourceLimits{MaxPixels: s.cfg.MaxSourcePixels, MaxDurationSeconds: s.cfg.MaxSourceSeconds}); reason != "" {
+		return &jobError{reason: reason}
+	}
+	asset.Width, asset.Height, asset.VideoCodec = info.Width, info.Height, info.VideoCodec
+	asset.DurationSeconds = int(math.Round(info.DurationSeconds))
+	if err := s.assets.Update(ctx, *asset); err != nil {
+		return err
+	}
+
+	switch job.JobType {
+	case domain.JobTypeThumbnails:
+		return s.renderThumbnails(ctx, *asset, info, source, workDir, report)
+	case domain.JobTypeWatermark:
+		if err := s.renderOutput(ctx, *asset, info, source, workDir, rendition{profile: domain.ProfileWatermarked1080, aspect: domain.Aspect169, watermark: watermark}, report); err != nil {
+			return err
+		}
+		return s.watermarks.Upsert(ctx, domain.WatermarkRecord{WatermarkID: uuid.NewString(), AssetID: asset.AssetID, WatermarkText: watermark.Text, Placement: watermark.Placement, Opacity: watermark.Opacity, AppliedAt: s.nowFn()})
+	}
+	renditions := jobRenditions[job.JobType]
+	for i, r := range renditions {
+		step := func(fraction float64) { report((float64(i) + fraction) / float64(len(renditions))) }
+		if err := s.renderOutput(ctx, *asset, info, source, workDir, r, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) fetchSource(ctx context.Context, asset domain.MediaAsset, dst string) error {
+	body, err := s.blobs.Open(ctx, sourceKey(asset))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(body, domain.MaxUploadBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n > domain.MaxUploadBytes {
+		return &jobError{reason: "source_too_large"}
+	}
+	return nil
+}
+
+func (s *Service) renderOutput(ctx context.Context, asset domain.MediaAsset, info domain.MediaInfo, source, workDir string, r rendition, report func(float64)) error {
+	width, height := domain.RenditionSize(r.profile, r.aspect)
+	name := renditionName(r.profile, r.aspect) + ".mp4"
+	out := filepath.Join(workDir, name)
+	err := s.transcoder.Render(ctx, ports.RenderSpec{
+		Source:          source,
+		Output:          out,
+		Width:           width,
+		Height:          height,
+		Crop:            domain.CropFor(info, r.aspect),
+		Watermark:       r.watermark,
+		DurationSeconds: info.DurationSeconds,
+		Limits:          s.resourceLimits(),
+	}, report)
+	if err != nil {
+		return err
+	}
+	url, err := s.upload(ctx, out, "media/"+asset.AssetID+"/"+name, "video/mp4")
+	if err != nil {
+		return err
+	}
+	return s.outputs.Upsert(ctx, domain.MediaOutput{OutputID: uuid.NewString(), AssetID: asset.AssetID, Profile: r.profile, AspectRatio: r.aspect, S3URL: url, CreatedAt: s.nowFn()})
+}
+
+// renderThumbnails grabs a frame at each requested position for every
+// aspect ratio, cropped the same way as the matching video rendition.
+func (s *Service) renderThumbnails(ctx context.Context, asset domain.MediaAsset, info domain.MediaInfo, source, workDir string, report func(float64)) error {
+	positions := asset.ThumbnailPositions
+	if len(positions) == 0 {
+		positions = s.cfg.ThumbnailPositions
+	}
+	total := len(positions) * len(thumbnailAspects)
+	done := 0
+	for _, ratio := range thumbnailAspects {
+		width, height := domain.RenditionSize(domain.Profile720, ratio)
+		crop := domain.CropFor(info, ratio)
+		for _, pos := range positions {
+			at, err := domain.ParseThumbnailPosition(pos, info.DurationSeconds)
+			if err != nil {
+				return &jobError{reason: "invalid_thumbnail_position", err: err}
+			}
+			name := strings.ReplaceAll(string(ratio), ":", "x") + "-" + positionSlug(pos) + ".jpg"
+			out := filepath.Join(workDir, name)
+			if err := s.transcoder.Thumbnail(ctx, ports.ThumbnailSpec{Source: source, Output: out, AtSeconds: at, Width: width, Height: height, Crop: crop, Limits: s.resourceLimits()}); err != nil {
+				return err
+			}
+			key := fmt.Sprintf("thumbnails/%s/%s/%s.jpg", asset.AssetID, strings.ReplaceAll(string(ratio), ":", "x"), positionSlug(pos))
+			url, err := s.upload(ctx, out, key, "image/jpeg")
+			if err != nil {
+				return err
+			}
+			if err := s.thumbnails.Upsert(ctx, domain.MediaThumbnail{ThumbnailID: uuid.NewString(), AssetID: asset.AssetID, Position: pos, AspectRatio: ratio, S3URL: url, CreatedAt: s.nowFn()}); err != nil {
+				return err
+			}
+			done++
+			report(float64(done) / float64(total))
+		}
+	}
+	return nil
+}
+
+// upload refuses outputs that reached the size cap: ffmpeg stops writing
+// there, so such a file is truncated rather than complete.
+func (s *Service) upload(ctx context.Context, path, key, contentType string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if stat.Size() >= s.cfg.MaxOutputBytes {
+		return "", &jobError{reason: "output_too_large"}
+	}
+	return s.blobs.Put(ctx, key, f, contentType)
+}
+
+func (s *Service) resourceLimits() ports.ResourceLimits {
+	return ports.ResourceLimits{Threads: s.cfg.TranscodeThreads, MaxOutputBytes: s.cfg.MaxOutputBytes, MaxDurationSeconds: s.cfg.MaxSourceSeconds}
+}
+
+func (s *Service) trackRunning(jobID string, cancel context.CancelFunc) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	s.running[jobID] = cancel
+}
+
+func (s *Service) untrackRunning(jobID string) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	delete(s.running, jobID)
+}
+
+func (s *Service) cancelRunning(jobID string) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	if cancel, ok := s.running[jobID]; ok {
+		cancel()
+	}
+}
+
+// progressStep is how far a job advances between progress writes.
+const progressStep = 0.05
+
+// progressReporter persists job progress without rewriting the job for
+// every transcoder status line. Each write re-reads the job, so a
+// cancellation recorded by another instance stops the job here.
+type progressReporter struct {
+	service *Service
+	ctx     context.Context
+	jobID   string
+	cancel  context.CancelFunc
+
+	mu   sync.Mutex
+	last float64
+}
+
+func (p *progressReporter) report(fraction float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fraction = math.Min(math.Max(fraction, 0), 1)
+	if fraction < p.last+progressStep && fraction < 1 {
+		return
+	}
+	job, err := p.service.jobs.GetByID(p.ctx, p.jobID)
+	if err != nil {
+		return
+	}
+	if job.Status == domain.JobStatusCancelled {
+		p.cancel()
+		return
+	}
+	p.last = fraction
+	job.Progress = math.Round(fraction*100) / 100
+	_ = p.service.jobs.Update(p.ctx, job)
+}
+
+// sourceKey maps the raw upload location (s3://media-raw/{asset_id}) to its
+// blob store key (media-raw/{asset_id}).
+func sourceKey(asset domain.MediaAsset) string {
+	if key := strings.TrimPrefix(strings.TrimSpace(asset.SourceS3URL), "s3://"); key != "" {
+		return key
+	}
+	return "media-raw/" + asset.AssetID
+}
+
+func renditionName(profile domain.OutputProfile, aspect domain.AspectRatio) string {
+	name := strings.ReplaceAll(string(profile), "_", "-")
+	if aspect != domain.Aspect169 {
+		name = strings.ReplaceAll(string(aspect), ":", "x") + "-" + name
+	}
+	return name
+}
+
+func positionSlug(pos string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, strings.ReplaceAll(strings.ToLower(strings.TrimSpace(pos)), "%", "pct"))
+}
+
+func jobDTO(job domain.MediaJob) contracts.JobDTO {
+	return contracts.JobDTO{JobID: job.JobID, JobType: string(job.JobType), Status: string(job.Status), Attempts: job.Attempts, Progress: job.Progress, Error: job.ErrorMessage}
+}
@@ -1,8 +1,12 @@
 package application
 
 import (
+	"context"
+	"io"
+	"sync"
 	"time"
 
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/ports"
 )
 
@@ -12,6 +16,20 @@ type Config struct {
 	EventDedupTTL       time.Duration
 	QueuePollInterval   time.Duration
 	FullPipelineEnabled bool
+	// UploadBaseURL prefixes the resumable upload URLs handed to clients;
+	// empty yields URLs relative to this service.
+	UploadBaseURL string
+
+	// JobTimeout bounds one job from fetching the source to the last
+	// upload; a source that makes the transcoder hang fails as job_timeout.
+	JobTimeout time.Duration
+	// WorkDir holds per-job scratch directories; empty means os.TempDir.
+	WorkDir            string
+	TranscodeThreads   int
+	MaxOutputBytes     int64
+	MaxSourceSeconds   float64
+	MaxSourcePixels    int
+	ThumbnailPositions []string
 }
 
 type Actor struct {
@@ -27,6 +45,26 @@ type CreateUploadInput struct {
 	MIMEType       string
 	FileSize       int64
 	ChecksumSHA256 string
+
+	ThumbnailPositions []string
+}
+
+type UploadChunkInput struct {
+	AssetID string
+	Offset  int64
+	Body    io.Reader
+	// ChecksumAlgorithm and Checksum verify this chunk alone; both are
+	// empty when the client sent no checksum.
+	ChecksumAlgorithm string
+	Checksum          []byte
+}
+
+type UploadProgress struct {
+	AssetID  string
+	Offset   int64
+	Length   int64
+	Complete bool
+	Status   string
 }
 
 type RetryAssetInput struct {
@@ -69,6 +107,16 @@ type Service struct {
 	queue    ports.JobQueue
 	dlq      ports.DLQPublisher
 	nowFn    func() time.Time
+
+	transcoder ports.Transcoder
+	blobs      ports.BlobStore
+
+	runningMu sync.Mutex
+	running   map[string]context.CancelFunc
+
+	uploads     ports.UploadStore
+	uploadMu    sync.Mutex
+	uploadLocks map[string]*uploadLock
 }
 
 type Dependencies struct {
@@ -85,6 +133,10 @@ type Dependencies struct {
 	Campaign ports.CampaignReader
 	Queue    ports.JobQueue
 	DLQ      ports.DLQPublisher
+
+	Transcoder ports.Transcoder
+	Blobs      ports.BlobStore
+	Uploads    ports.UploadStore
 }
 
 func NewService(deps Dependencies) *Service {
@@ -101,6 +153,24 @@ func NewService(deps Dependencies) *Service {
 	if cfg.QueuePollInterval <= 0 {
 		cfg.QueuePollInterval = 2 * time.Second
 	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = 10 * time.Minute
+	}
+	if cfg.TranscodeThreads <= 0 {
+		cfg.TranscodeThreads = 2
+	}
+	if cfg.MaxOutputBytes <= 0 {
+		cfg.MaxOutputBytes = 2 << 30
+	}
+	if cfg.MaxSourceSeconds <= 0 {
+		cfg.MaxSourceSeconds = 3600
+	}
+	if cfg.MaxSourcePixels <= 0 {
+		cfg.MaxSourcePixels = 7680 * 4320
+	}
+	if len(cfg.ThumbnailPositions) == 0 {
+		cfg.ThumbnailPositions = domain.DefaultThumbnailPositions
+	}
 	return &Service{
 		cfg:         cfg,
 		assets:      deps.Assets,
@@ -114,5 +184,10 @@ func NewService(deps Dependencies) *Service {
 		queue:       deps.Queue,
 		dlq:         deps.DLQ,
 		nowFn:       time.Now().UTC,
+		transcoder:  deps.Transcoder,
+		blobs:       deps.Blobs,
+		running:     map[string]context.CancelFunc{},
+		uploads:     deps.Uploads,
+		uploadLocks: map[string]*uploadLock{},
 	}
 }
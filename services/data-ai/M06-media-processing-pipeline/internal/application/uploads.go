@@ -0,0 +1,261 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+)
+
+// SupportedChecksumAlgorithms are the per-chunk checksums accepted on
+// upload chunks, named as in the tus checksum extension.
+var SupportedChecksumAlgorithms = []string{"sha256", "sha1", "md5"}
+
+func (s *Service) GetUploadProgress(ctx context.Context, actor Actor, assetID string) (UploadProgress, error) {
+	asset, err := s.uploadAsset(ctx, actor, assetID)
+	if err != nil {
+		return UploadProgress{}, err
+	}
+	if asset.Status != domain.AssetStatusAwaitingUpload {
+		return UploadProgress{AssetID: asset.AssetID, Offset: asset.FileSize, Length: asset.FileSize, Complete: true, Status: string(asset.Status)}, nil
+	}
+	offset, err := s.uploads.Offset(ctx, asset.AssetID)
+	if err != nil {
+		return UploadProgress{}, err
+	}
+	return UploadProgress{AssetID: asset.AssetID, Offset: offset, Length: asset.FileSize, Status: string(asset.Status)}, nil
+}
+
+// AppendUpload stores one chunk of an upload. The chunk must start where
+// the previous one ended; a chunk whose checksum does not match is dropped
+// whole. The last chunk hands the file to completeUpload, and only a file
+// that passes verification gets processing jobs.
+func (s *Service) AppendUpload(ctx context.Context, actor Actor, input UploadChunkInput) (UploadProgress, error) {
+	asset, err := s.uploadAsset(ctx, actor, input.AssetID)
+	if err != nil {
+		return UploadProgress{}, err
+	}
+	if asset.Status != domain.AssetStatusAwaitingUpload {
+		return UploadProgress{}, domain.ErrConflict
+	}
+	var verify hash.Hash
+	if input.ChecksumAlgorithm != "" {
+		if verify, err = newChecksum(input.ChecksumAlgorithm); err != nil {
+			return UploadProgress{}, err
+		}
+	}
+	unlock := s.lockUpload(asset.AssetID)
+	defer unlock()
+
+	offset, err := s.uploads.Offset(ctx, asset.AssetID)
+	if err != nil {
+		return UploadProgress{}, err
+	}
+	if input.Offset != offset {
+		return UploadProgress{}, domain.ErrUploadOffsetMismatch
+	}
+	body := io.LimitReader(input.Body, asset.FileSize-offset+1)
+	if verify != nil {
+		body = io.TeeReader(body, verify)
+	}
+	next, appendErr := s.uploads.Append(ctx, asset.AssetID, offset, body)
+	progress := UploadProgress{AssetID: asset.AssetID, Offset: next, Length: asset.FileSize, Status: string(asset.Status)}
+	switch {
+	case next > asset.FileSize:
+		progress.Offset = offset
+		return progress, errors.Join(domain.ErrPayloadTooLarge, s.uploads.Truncate(ctx, asset.AssetID, offset))
+	case verify != nil && (appendErr != nil || !bytes.Equal(verify.Sum(nil), input.Checksum)):
+		// A chunk that cannot be verified is not kept, even in part.
+		progress.Offset = offset
+		if err := s.uploads.Truncate(ctx, asset.AssetID, offset); err != nil {
+			return progress, err
+		}
+		if appendErr != nil {
+			return progress, appendErr
+		}
+		return progress, domain.ErrChecksumMismatch
+	case appendErr != nil:
+		return progress, appendErr
+	case next < asset.FileSize:
+		return progress, nil
+	}
+	if err := s.completeUpload(ctx, &asset); err != nil {
+		return progress, err
+	}
+	progress.Complete, progress.Status = true, string(asset.Status)
+	return progress, nil
+}
+
+// TerminateUpload abandons an unfinished upload and fails its asset.
+func (s *Service) TerminateUpload(ctx context.Context, actor Actor, assetID string) error {
+	asset, err := s.uploadAsset(ctx, actor, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.Status != domain.AssetStatusAwaitingUpload {
+		return domain.ErrConflict
+	}
+	unlock := s.lockUpload(asset.AssetID)
+	defer unlock()
+	return s.rejectUpload(ctx, asset, "upload_terminated", nil)
+}
+
+// completeUpload verifies a fully received file and starts processing it.
+// Everything the pipeline relies on - size, checksum, container type,
+// duration - is taken from the bytes rather than from the client.
+func (s *Service) completeUpload(ctx context.Context, asset *domain.MediaAsset) error {
+	file, err := s.uploads.Open(ctx, asset.AssetID)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	size := asset.FileSize
+	sum := sha256.New()
+	if _, err := io.Copy(sum, io.NewSectionReader(file, 0, size)); err != nil {
+		return err
+	}
+	checksum := hex.EncodeToString(sum.Sum(nil))
+	if asset.ChecksumDeclared && !strings.EqualFold(checksum, asset.ChecksumSHA256) {
+		return s.rejectUpload(ctx, *asset, "checksum_mismatch", fmt.Errorf("%w: received bytes hash to %s", domain.ErrChecksumMismatch, checksum))
+	}
+	info, err := domain.InspectContainer(file, size)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTruncatedMedia):
+			return s.rejectUpload(ctx, *asset, "truncated_media", err)
+		case errors.Is(err, domain.ErrUnsupportedMedia):
+			return s.rejectUpload(ctx, *asset, "unsupported_media", err)
+		case errors.Is(err, domain.ErrCorruptMedia):
+			return s.rejectUpload(ctx, *asset, "corrupt_media", err)
+		}
+		return err
+	}
+
+	asset.ChecksumSHA256 = checksum
+	asset.MIMEType = info.MIMEType
+	asset.DurationSeconds = int(math.Round(info.DurationSeconds))
+	asset.Width, asset.Height, asset.Rotation = info.Width, info.Height, info.Rotation
+	asset.VideoCodec, asset.AudioCodec = info.VideoCodec, info.AudioCodec
+	asset.FrameRate, asset.FastStart = info.FrameRate, info.FastStart
+	if s.blobs != nil {
+		if _, err := s.blobs.Put(ctx, sourceKey(*asset), io.NewSectionReader(file, 0, size), asset.MIMEType); err != nil {
+			return err
+		}
+	}
+	asset.Status = domain.AssetStatusProcessing
+	asset.LastErrorCode, asset.LastErrorMessage = "", ""
+	asset.UpdatedAt = s.nowFn()
+	if err := s.assets.Update(ctx, *asset); err != nil {
+		return err
+	}
+	_ = s.uploads.Delete(ctx, asset.AssetID)
+	return s.startJobs(ctx, *asset)
+}
+
+func (s *Service) startJobs(ctx context.Context, asset domain.MediaAsset) error {
+	jobs := domain.NewDefaultJobs(asset.AssetID, s.nowFn())
+	if err := s.jobs.CreateMany(ctx, jobs); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := s.queue.Enqueue(ctx, job.JobID); err != nil {
+			asset.Status = domain.AssetStatusFailed
+			asset.LastErrorCode = "queue_unavailable"
+			asset.LastErrorMessage = err.Error()
+			asset.UpdatedAt = s.nowFn()
+			_ = s.assets.Update(ctx, asset)
+			_ = s.campaign.UpdateMediaStatus(ctx, asset.SubmissionID, asset.AssetID, string(asset.Status), asset.LastErrorCode)
+			return err
+		}
+	}
+	return nil
+}
+
+// rejectUpload fails an asset whose upload will never be processed and
+// discards what was received. It returns cause so callers can report it.
+func (s *Service) rejectUpload(ctx context.Context, asset domain.MediaAsset, reason string, cause error) error {
+	asset.Status = domain.AssetStatusFailed
+	asset.LastErrorCode = reason
+	asset.LastErrorMessage = reason
+	if cause != nil {
+		asset.LastErrorMessage = cause.Error()
+	}
+	asset.UpdatedAt = s.nowFn()
+	if err := s.assets.Update(ctx, asset); err != nil {
+		return err
+	}
+	_ = s.uploads.Delete(ctx, asset.AssetID)
+	_ = s.campaign.UpdateMediaStatus(ctx, asset.SubmissionID, asset.AssetID, string(asset.Status), asset.LastErrorCode)
+	return cause
+}
+
+// uploadAsset loads an asset for an upload request. Only the user who
+// registered the upload, or an admin, may send or inspect its bytes.
+func (s *Service) uploadAsset(ctx context.Context, actor Actor, assetID string) (domain.MediaAsset, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.MediaAsset{}, domain.ErrUnauthorized
+	}
+	if s.uploads == nil {
+		return domain.MediaAsset{}, domain.ErrNotFound
+	}
+	asset, err := s.assets.GetByID(ctx, strings.TrimSpace(assetID))
+	if err != nil {
+		return domain.MediaAsset{}, err
+	}
+	if asset.UploadedBy != "" && asset.UploadedBy != actor.SubjectID && strings.ToLower(strings.TrimSpace(actor.Role)) != "admin" {
+		return domain.MediaAsset{}, domain.ErrForbidden
+	}
+	return asset, nil
+}
+
+// uploadLock counts the callers holding or waiting on it, so the last one
+// out can drop it from uploadLocks.
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lockUpload serialises chunks of one upload on this instance; the offset
+// check in the upload store catches writers on other instances.
+func (s *Service) lockUpload(assetID string) func() {
+	s.uploadMu.Lock()
+	lock, ok := s.uploadLocks[assetID]
+	if !ok {
+		lock = &uploadLock{}
+		s.uploadLocks[assetID] = lock
+	}
+	lock.refs++
+	s.uploadMu.Unlock()
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		s.uploadMu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(s.uploadLocks, assetID)
+		}
+		s.uploadMu.Unlock()
+	}
+}
+
+func newChecksum(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(strings.TrimSpace(algorithm)) {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "md5":
+		return md5.New(), nil
+	}
+	return nil, domain.ErrUnsupportedChecksum
+}
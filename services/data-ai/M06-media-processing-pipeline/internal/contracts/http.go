@@ -8,6 +8,9 @@ type UploadRequest struct {
 	MIMEType       string `json:"mime_type"`
 	FileSize       int64  `json:"file_size"`
 	ChecksumSHA256 string `json:"checksum_sha256,omitempty"`
+	// ThumbnailPositions are percentages ("10%"), seconds ("12.5s") or
+	// clock times ("00:01:05"); the service defaults apply when empty.
+	ThumbnailPositions []string `json:"thumbnail_positions,omitempty"`
 }
 
 type UploadResponse struct {
@@ -28,11 +31,21 @@ type ThumbnailDTO struct {
 	URL         string `json:"url"`
 }
 
+type JobDTO struct {
+	JobID    string  `json:"job_id"`
+	JobType  string  `json:"job_type"`
+	Status   string  `json:"status"`
+	Attempts int     `json:"attempts"`
+	Progress float64 `json:"progress"`
+	Error    string  `json:"error,omitempty"`
+}
+
 type AssetStatusResponse struct {
 	AssetID    string         `json:"asset_id"`
 	Status     string         `json:"status"`
 	Outputs    []OutputDTO    `json:"outputs"`
 	Thumbnails []ThumbnailDTO `json:"thumbnails"`
+	Jobs       []JobDTO       `json:"jobs"`
 	ErrorCode  string         `json:"error_code,omitempty"`
 	Error      string         `json:"error,omitempty"`
 }
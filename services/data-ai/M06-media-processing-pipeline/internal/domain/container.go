@@ -0,0 +1,419 @@
+package domain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ContainerInfo is what the ISO base media file format (MP4/MOV) boxes of
+// an upload say about it, independent of what the client declared.
+type ContainerInfo struct {
+	Format          string  `json:"format"`
+	MajorBrand      string  `json:"major_brand,omitempty"`
+	MIMEType        string  `json:"mime_type"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	FrameRate       float64 `json:"frame_rate"`
+	Rotation        int     `json:"rotation"`
+	VideoCodec      string  `json:"video_codec"`
+	AudioCodec      string  `json:"audio_codec,omitempty"`
+	// FastStart is set when the movie box precedes the media data, so the
+	// file can be played before it has been downloaded completely.
+	FastStart bool `json:"fast_start"`
+}
+
+const (
+	maxTopLevelBoxes = 4096
+	maxMovieBoxBytes = 64 << 20
+	maxBoxDepth      = 16
+)
+
+// topLevelBoxes are the box types an ISO-BMFF file may start with; files
+// starting with anything else are not MP4 or QuickTime whatever their
+// declared MIME type.
+var topLevelBoxes = map[string]bool{"ftyp": true, "moov": true, "mdat": true, "free": true, "skip": true, "wide": true, "pnot": true, "uuid": true}
+
+// InspectContainer walks the boxes of an MP4 or QuickTime file. Only box
+// headers and the movie box are read, so inspecting a large file is cheap.
+// It fails with ErrUnsupportedMedia for other formats, ErrTruncatedMedia
+// when boxes or samples extend past the end of the file and
+// ErrCorruptMedia for malformed boxes.
+func InspectContainer(r io.ReaderAt, size int64) (ContainerInfo, error) {
+	info := ContainerInfo{Format: "mov", MIMEType: "video/quicktime"}
+	var moov []byte
+	moovOffset, mdatOffset := int64(-1), int64(-1)
+	header := make([]byte, 16)
+	offset := int64(0)
+	for count := 0; offset < size; count++ {
+		if count >= maxTopLevelBoxes {
+			return ContainerInfo{}, fmt.Errorf("%w: too many boxes", ErrCorruptMedia)
+		}
+		if size-offset < 8 {
+			return ContainerInfo{}, fmt.Errorf("%w: partial box header at %d", ErrTruncatedMedia, offset)
+		}
+		if _, err := readAt(r, header[:8], offset); err != nil {
+			return ContainerInfo{}, err
+		}
+		boxType := string(header[4:8])
+		boxSize, headerLen := int64(binary.BigEndian.Uint32(header)), int64(8)
+		switch boxSize {
+		case 0:
+			boxSize = size - offset
+		case 1:
+			if size-offset < 16 {
+				return ContainerInfo{}, fmt.Errorf("%w: partial box header at %d", ErrTruncatedMedia, offset)
+			}
+			if _, err := readAt(r, header[8:16], offset+8); err != nil {
+				return ContainerInfo{}, err
+			}
+			boxSize, headerLen = int64(binary.BigEndian.Uint64(header[8:16])), 16
+		}
+		if count == 0 && !topLevelBoxes[boxType] {
+			return ContainerInfo{}, fmt.Errorf("%w: not an ISO base media file", ErrUnsupportedMedia)
+		}
+		if boxSize < headerLen {
+			return ContainerInfo{}, fmt.Errorf("%w: %q box with size %d", ErrCorruptMedia, boxType, boxSize)
+		}
+		if boxSize > size-offset {
+			return ContainerInfo{}, fmt.Errorf("%w: %q box ends past end of file", ErrTruncatedMedia, boxType)
+		}
+		payload := boxSize - headerLen
+		switch boxType {
+		case "ftyp":
+			if payload < 4 || payload > 1024 {
+				return ContainerInfo{}, fmt.Errorf("%w: ftyp box with size %d", ErrCorruptMedia, boxSize)
+			}
+			brand := make([]byte, 4)
+			if _, err := readAt(r, brand, offset+headerLen); err != nil {
+				return ContainerInfo{}, err
+			}
+			info.MajorBrand = strings.TrimSpace(string(brand))
+			if info.MajorBrand != "qt" {
+				info.Format, info.MIMEType = "mp4", "video/mp4"
+			}
+		case "moov":
+			if moov != nil {
+				return ContainerInfo{}, fmt.Errorf("%w: more than one moov box", ErrCorruptMedia)
+			}
+			if payload > maxMovieBoxBytes {
+				return ContainerInfo{}, fmt.Errorf("%w: moov box of %d bytes", ErrCorruptMedia, payload)
+			}
+			moov = make([]byte, payload)
+			if _, err := readAt(r, moov, offset+headerLen); err != nil {
+				return ContainerInfo{}, err
+			}
+			moovOffset = offset
+		case "mdat":
+			if mdatOffset < 0 {
+				mdatOffset = offset
+			}
+		}
+		offset += boxSize
+	}
+	if moov == nil {
+		// Encoders that write the movie box last leave it out of an upload
+		// that was cut short.
+		return ContainerInfo{}, fmt.Errorf("%w: moov box missing", ErrTruncatedMedia)
+	}
+	if mdatOffset < 0 {
+		return ContainerInfo{}, fmt.Errorf("%w: mdat box missing", ErrCorruptMedia)
+	}
+	info.FastStart = moovOffset < mdatOffset
+
+	movie, err := parseMovie(moov)
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+	var video, audio *track
+	for i := range movie.tracks {
+		t := &movie.tracks[i]
+		if t.maxChunkOffset >= size {
+			return ContainerInfo{}, fmt.Errorf("%w: samples of %q track past end of file", ErrTruncatedMedia, t.handler)
+		}
+		switch {
+		case t.handler == "vide" && video == nil:
+			video = t
+		case t.handler == "soun" && audio == nil:
+			audio = t
+		}
+	}
+	if video == nil {
+		return ContainerInfo{}, fmt.Errorf("%w: no video track", ErrUnsupportedMedia)
+	}
+	info.VideoCodec = codecName(video.codec)
+	info.Width, info.Height, info.Rotation = video.width, video.height, video.rotation
+	if video.sampleDelta > 0 && video.timescale > 0 {
+		info.FrameRate = math.Round(float64(video.sampleCount)*float64(video.timescale)/float64(video.sampleDelta)*1000) / 1000
+	}
+	if audio != nil {
+		info.AudioCodec = codecName(audio.codec)
+	}
+	if movie.timescale > 0 && movie.duration > 0 {
+		info.DurationSeconds = float64(movie.duration) / float64(movie.timescale)
+	} else if video.timescale > 0 {
+		info.DurationSeconds = float64(video.duration) / float64(video.timescale)
+	}
+	info.DurationSeconds = math.Round(info.DurationSeconds*1000) / 1000
+	return info, nil
+}
+
+type movie struct {
+	timescale uint32
+	duration  uint64
+	tracks    []track
+}
+
+type track struct {
+	handler        string
+	codec          string
+	width          int
+	height         int
+	rotation       int
+	timescale      uint32
+	duration       uint64
+	sampleCount    uint64
+	sampleDelta    uint64
+	maxChunkOffset int64
+}
+
+type box struct {
+	kind string
+	data []byte
+}
+
+// children splits a container box payload into its child boxes.
+func children(buf []byte) ([]box, error) {
+	out := []box{}
+	for len(buf) > 0 {
+		if len(buf) < 8 {
+			return nil, fmt.Errorf("%w: partial box header", ErrCorruptMedia)
+		}
+		size, headerLen := uint64(binary.BigEndian.Uint32(buf)), uint64(8)
+		kind := string(buf[4:8])
+		switch size {
+		case 0:
+			size = uint64(len(buf))
+		case 1:
+			if len(buf) < 16 {
+				return nil, fmt.Errorf("%w: partial box header", ErrCorruptMedia)
+			}
+			size, headerLen = binary.BigEndian.Uint64(buf[8:16]), 16
+		}
+		if size < headerLen || size > uint64(len(buf)) {
+			return nil, fmt.Errorf("%w: %q box overruns its parent", ErrCorruptMedia, kind)
+		}
+		out = append(out, box{kind: kind, data: buf[headerLen:size]})
+		buf = buf[size:]
+	}
+	return out, nil
+}
+
+func parseMovie(buf []byte) (movie, error) {
+	boxes, err := children(buf)
+	if err != nil {
+		return movie{}, err
+	}
+	m := movie{}
+	for _, b := range boxes {
+		switch b.kind {
+		case "mvhd":
+			p := payloadReader{buf: b.data}
+			version := p.u8()
+			p.skip(3)
+			if version == 1 {
+				p.skip(16)
+				m.timescale, m.duration = p.u32(), p.u64()
+			} else {
+				p.skip(8)
+				m.timescale, m.duration = p.u32(), uint64(p.u32())
+			}
+			if p.err != nil {
+				return movie{}, fmt.Errorf("%w: mvhd box: %v", ErrCorruptMedia, p.err)
+			}
+		case "trak":
+			t := track{}
+			if err := parseTrackBoxes(b.data, &t, 0); err != nil {
+				return movie{}, err
+			}
+			m.tracks = append(m.tracks, t)
+		}
+	}
+	return m, nil
+}
+
+// parseTrackBoxes fills t from a track and the containers below it.
+func parseTrackBoxes(buf []byte, t *track, depth int) error {
+	if depth > maxBoxDepth {
+		return fmt.Errorf("%w: boxes nested too deeply", ErrCorruptMedia)
+	}
+	boxes, err := children(buf)
+	if err != nil {
+		return err
+	}
+	for _, b := range boxes {
+		p := payloadReader{buf: b.data}
+		switch b.kind {
+		case "mdia", "minf", "stbl":
+			if err := parseTrackBoxes(b.data, t, depth+1); err != nil {
+				return err
+			}
+		case "tkhd":
+			version := p.u8()
+			p.skip(3)
+			if version == 1 {
+				p.skip(32)
+			} else {
+				p.skip(20)
+			}
+			p.skip(16)
+			matrix := make([]int32, 9)
+			for i := range matrix {
+				matrix[i] = int32(p.u32())
+			}
+			width, height := p.u32()>>16, p.u32()>>16
+			if p.err != nil {
+				return p.err
+			}
+			t.width, t.height = int(width), int(height)
+			t.rotation = matrixRotation(matrix)
+		case "mdhd":
+			version := p.u8()
+			p.skip(3)
+			if version == 1 {
+				p.skip(16)
+				t.timescale, t.duration = p.u32(), p.u64()
+			} else {
+				p.skip(8)
+				t.timescale, t.duration = p.u32(), uint64(p.u32())
+			}
+		case "hdlr":
+			p.skip(8)
+			t.handler = string(p.bytes(4))
+		case "stsd":
+			p.skip(8)
+			entrySize := p.u32()
+			t.codec = string(p.bytes(4))
+			// Visual sample entries carry the coded size after 24 bytes of
+			// reserved and pre-defined fields; it stands in for a missing
+			// track header size.
+			if entrySize >= 36 && t.width == 0 {
+				p.skip(24)
+				t.width, t.height = int(p.u16()), int(p.u16())
+			}
+		case "stts":
+			p.skip(4)
+			n := p.count(8)
+			for i := uint32(0); i < n && p.err == nil; i++ {
+				count, delta := uint64(p.u32()), uint64(p.u32())
+				t.sampleCount += count
+				t.sampleDelta += count * delta
+			}
+		case "stco", "co64":
+			p.skip(4)
+			width := 4
+			if b.kind == "co64" {
+				width = 8
+			}
+			n := p.count(width)
+			for i := uint32(0); i < n && p.err == nil; i++ {
+				var offset int64
+				if width == 8 {
+					offset = int64(p.u64())
+				} else {
+					offset = int64(p.u32())
+				}
+				if offset > t.maxChunkOffset {
+					t.maxChunkOffset = offset
+				}
+			}
+		}
+		if p.err != nil {
+			return fmt.Errorf("%w: %q box: %v", ErrCorruptMedia, b.kind, p.err)
+		}
+	}
+	return nil
+}
+
+// matrixRotation reads the display rotation, in clockwise degrees, from a
+// track header transformation matrix.
+func matrixRotation(m []int32) int {
+	a, b := float64(m[0])/65536, float64(m[1])/65536
+	if a == 0 && b == 0 {
+		return 0
+	}
+	deg := int(math.Round(math.Atan2(b, a)*180/math.Pi/90)) * 90
+	return (deg + 360) % 360
+}
+
+func codecName(fourcc string) string {
+	switch fourcc = strings.TrimSpace(fourcc); fourcc {
+	case "avc1", "avc3":
+		return "h264"
+	case "hvc1", "hev1":
+		return "hevc"
+	case "av01":
+		return "av1"
+	case "vp09":
+		return "vp9"
+	case "mp4v":
+		return "mpeg4"
+	case "mp4a":
+		return "aac"
+	case "ac-3":
+		return "ac3"
+	case "ec-3":
+		return "eac3"
+	case "Opus":
+		return "opus"
+	case "apch", "apcn", "apcs", "apco", "ap4h":
+		return "prores"
+	}
+	return fourcc
+}
+
+// readAt fills buf from off. ReaderAt implementations may report io.EOF
+// alongside a read that ends exactly at the end of the input.
+func readAt(r io.ReaderAt, buf []byte, off int64) (int, error) {
+	n, err := r.ReadAt(buf, off)
+	if err == io.EOF && n == len(buf) {
+		err = nil
+	}
+	return n, err
+}
+
+// payloadReader reads big-endian fields from a box payload and remembers
+// the first overrun instead of panicking.
+type payloadReader struct {
+	buf []byte
+	err error
+}
+
+func (p *payloadReader) bytes(n int) []byte {
+	if p.err != nil || n > len(p.buf) {
+		p.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+	out := p.buf[:n]
+	p.buf = p.buf[n:]
+	return out
+}
+
+func (p *payloadReader) skip(n int)  { p.bytes(n) }
+func (p *payloadReader) u8() uint8   { return p.bytes(1)[0] }
+func (p *payloadReader) u16() uint16 { return binary.BigEndian.Uint16(p.bytes(2)) }
+func (p *payloadReader) u32() uint32 { return binary.BigEndian.Uint32(p.bytes(4)) }
+func (p *payloadReader) u64() uint64 { return binary.BigEndian.Uint64(p.bytes(8)) }
+
+// count reads an entry count and checks the entries fit in the payload.
+func (p *payloadReader) count(entryBytes int) uint32 {
+	n := p.u32()
+	if p.err == nil && uint64(n)*uint64(entryBytes) > uint64(len(p.buf)) {
+		p.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	return n
+}
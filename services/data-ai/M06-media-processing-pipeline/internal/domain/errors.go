@@ -13,4 +13,11 @@ var (
 	ErrIdempotencyConflict = errors.New("idempotency key reused with different payload")
 	ErrIdempotencyInFlight = errors.New("idempotency key request in progress")
 	ErrUnsupportedEvent    = errors.New("unsupported event")
+
+	ErrUnsupportedMedia     = errors.New("unsupported media")
+	ErrCorruptMedia         = errors.New("corrupt media")
+	ErrTruncatedMedia       = errors.New("truncated media")
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	ErrUnsupportedChecksum  = errors.New("unsupported checksum algorithm")
 )
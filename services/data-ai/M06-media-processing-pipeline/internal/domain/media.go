@@ -19,11 +19,14 @@ type OutputProfile string
 type AspectRatio string
 
 const (
-	AssetStatusUploaded    AssetStatus = "uploaded"
-	AssetStatusPendingScan AssetStatus = "pending_scan"
-	AssetStatusProcessing  AssetStatus = "processing"
-	AssetStatusCompleted   AssetStatus = "completed"
-	AssetStatusFailed      AssetStatus = "failed"
+	// AssetStatusAwaitingUpload assets have been registered but their bytes
+	// have not all arrived or been verified; no jobs exist for them yet.
+	AssetStatusAwaitingUpload AssetStatus = "awaiting_upload"
+	AssetStatusUploaded       AssetStatus = "uploaded"
+	AssetStatusPendingScan    AssetStatus = "pending_scan"
+	AssetStatusProcessing     AssetStatus = "processing"
+	AssetStatusCompleted      AssetStatus = "completed"
+	AssetStatusFailed         AssetStatus = "failed"
 )
 
 const (
@@ -46,13 +49,20 @@ const (
 	JobStatusProcessing JobStatus = "processing"
 	JobStatusCompleted  JobStatus = "completed"
 	JobStatusFailed     JobStatus = "failed"
+	JobStatusCancelled  JobStatus = "cancelled"
 )
 
 const MaxJobAttempts = 3
 
+const MaxUploadBytes = 500 * 1024 * 1024
+
 const (
 	Profile1080 OutputProfile = "1080p"
 	Profile720  OutputProfile = "720p"
+
+	// ProfileWatermarked1080 is the 16:9 1080p rendition with the
+	// submission watermark burned in, produced once the asset is approved.
+	ProfileWatermarked1080 OutputProfile = "1080p_watermarked"
 )
 
 const (
@@ -72,10 +82,24 @@ type MediaAsset struct {
 	Status           AssetStatus    `json:"status"`
 	ApprovalStatus   ApprovalStatus `json:"approval_status"`
 	ChecksumSHA256   string         `json:"checksum_sha256"`
-	LastErrorCode    string         `json:"last_error_code,omitempty"`
-	LastErrorMessage string         `json:"last_error_message,omitempty"`
-	CreatedAt        time.Time      `json:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at"`
+	// ChecksumDeclared is set when the client supplied ChecksumSHA256; the
+	// received bytes must then hash to it.
+	ChecksumDeclared bool    `json:"checksum_declared"`
+	UploadedBy       string  `json:"uploaded_by,omitempty"`
+	Width            int     `json:"width,omitempty"`
+	Height           int     `json:"height,omitempty"`
+	VideoCodec       string  `json:"video_codec,omitempty"`
+	AudioCodec       string  `json:"audio_codec,omitempty"`
+	FrameRate        float64 `json:"frame_rate,omitempty"`
+	Rotation         int     `json:"rotation,omitempty"`
+	FastStart        bool    `json:"fast_start"`
+	// ThumbnailPositions are the positions requested at upload; empty
+	// means the service defaults.
+	ThumbnailPositions []string  `json:"thumbnail_positions,omitempty"`
+	LastErrorCode      string    `json:"last_error_code,omitempty"`
+	LastErrorMessage   string    `json:"last_error_message,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 type MediaJob struct {
@@ -84,6 +108,7 @@ type MediaJob struct {
 	JobType      JobType   `json:"job_type"`
 	Status       JobStatus `json:"status"`
 	Attempts     int       `json:"attempts"`
+	Progress     float64   `json:"progress"`
 	ErrorMessage string    `json:"error_message,omitempty"`
 	QueuedAt     time.Time `json:"queued_at"`
 	StartedAt    time.Time `json:"started_at,omitempty"`
@@ -123,6 +148,8 @@ type UploadInput struct {
 	MIMEType       string `json:"mime_type"`
 	FileSize       int64  `json:"file_size"`
 	ChecksumSHA256 string `json:"checksum_sha256"`
+
+	ThumbnailPositions []string `json:"thumbnail_positions,omitempty"`
 }
 
 func ValidateUploadInput(input UploadInput) error {
@@ -136,9 +163,17 @@ func ValidateUploadInput(input UploadInput) error {
 	if input.FileSize <= 0 {
 		return ErrInvalidInput
 	}
-	if input.FileSize > 500*1024*1024 {
+	if input.FileSize > MaxUploadBytes {
 		return ErrPayloadTooLarge
 	}
+	if len(input.ThumbnailPositions) > MaxThumbnailPositions {
+		return ErrInvalidInput
+	}
+	for _, pos := range input.ThumbnailPositions {
+		if _, err := ParseThumbnailPosition(pos, 0); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -168,12 +203,12 @@ func NewDefaultJobs(assetID string, queuedAt time.Time) []MediaJob {
 }
 
 func IsTerminal(status JobStatus) bool {
-	return status == JobStatusCompleted || status == JobStatusFailed
+	return status == JobStatusCompleted || status == JobStatusFailed || status == JobStatusCancelled
 }
 
 func IsRetryableJobFailure(reason string) bool {
 	switch strings.ToLower(strings.TrimSpace(reason)) {
-	case "unsupported_codec", "malware_detected":
+	case "unsupported_codec", "malware_detected", "corrupt_source", "source_too_large", "source_too_long", "output_too_large":
 		return false
 	default:
 		return true
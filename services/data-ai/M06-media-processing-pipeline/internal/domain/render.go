@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const MaxThumbnailPositions = 12
+
+var DefaultThumbnailPositions = []string{"10%", "50%", "90%"}
+
+// Rect is a region of a video frame in pixels.
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func (r Rect) IsZero() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// MediaInfo is what probing a source tells us. Content is the part of the
+// frame that carries picture once letterbox or pillarbox bars are removed;
+// it is zero when the whole frame is picture.
+type MediaInfo struct {
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	VideoCodec      string  `json:"video_codec"`
+	HasAudio        bool    `json:"has_audio"`
+	Content         Rect    `json:"content"`
+}
+
+// SourceLimits bound what a worker agrees to decode.
+type SourceLimits struct {
+	MaxPixels          int
+	MaxDurationSeconds float64
+}
+
+// ValidateSource returns the job failure reason for a source that probes
+// fine but is outside the limits, or "" when it may be processed.
+func ValidateSource(info MediaInfo, limits SourceLimits) string {
+	switch {
+	case info.Width <= 0 || info.Height <= 0 || strings.TrimSpace(info.VideoCodec) == "":
+		return "corrupt_source"
+	case limits.MaxPixels > 0 && info.Width*info.Height > limits.MaxPixels:
+		return "source_too_large"
+	case limits.MaxDurationSeconds > 0 && info.DurationSeconds > limits.MaxDurationSeconds:
+		return "source_too_long"
+	}
+	return ""
+}
+
+// RenditionSize is the output frame size of a profile in an aspect ratio.
+func RenditionSize(profile OutputProfile, aspect AspectRatio) (int, int) {
+	short := 1080
+	if profile == Profile720 {
+		short = 720
+	}
+	switch aspect {
+	case Aspect916:
+		return short, short * 16 / 9
+	case Aspect11:
+		return short, short
+	default:
+		return short * 16 / 9, short
+	}
+}
+
+// CropFor picks the largest window of the target aspect ratio centred on
+// the picture content rather than the raw frame, so letterboxed sources are
+// not cropped into their black bars. It returns a zero Rect when the frame
+// already has the requested shape; bars are then kept rather than zoomed
+// past.
+func CropFor(info MediaInfo, aspect AspectRatio) Rect {
+	rw, rh := aspectParts(aspect)
+	if info.Width <= 0 || info.Height <= 0 || sameShape(info.Width, info.Height, rw, rh) {
+		return Rect{}
+	}
+	area := info.Content
+	if area.IsZero() {
+		area = Rect{Width: info.Width, Height: info.Height}
+	}
+	w, h := area.Width, area.Height
+	if w*rh > h*rw {
+		w = h * rw / rh
+	} else {
+		h = w * rh / rw
+	}
+	w, h = w&^1, h&^1
+	if w <= 0 || h <= 0 {
+		return Rect{}
+	}
+	x := clamp(area.X+(area.Width-w)/2, 0, info.Width-w)
+	y := clamp(area.Y+(area.Height-h)/2, 0, info.Height-h)
+	return Rect{X: x &^ 1, Y: y &^ 1, Width: w, Height: h}
+}
+
+// ParseThumbnailPosition turns a position into seconds from the start. It
+// accepts a percentage of the duration ("10%"), seconds ("12.5s" or "12.5")
+// and clock times ("00:01:05"). With a zero duration only the syntax is
+// checked. Positions past the end are clamped to the last frame.
+func ParseThumbnailPosition(raw string, durationSeconds float64) (float64, error) {
+	pos := strings.TrimSpace(raw)
+	var at float64
+	switch {
+	case strings.HasSuffix(pos, "%"):
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(pos, "%"), 64)
+		if err != nil || !(pct >= 0 && pct <= 100) {
+			return 0, ErrInvalidInput
+		}
+		at = durationSeconds * pct / 100
+	case strings.Contains(pos, ":"):
+		parts := strings.Split(pos, ":")
+		if len(parts) > 3 {
+			return 0, ErrInvalidInput
+		}
+		for _, part := range parts {
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil || !(v >= 0) || math.IsInf(v, 0) {
+				return 0, ErrInvalidInput
+			}
+			at = at*60 + v
+		}
+	default:
+		v, err := strconv.ParseFloat(strings.TrimSuffix(pos, "s"), 64)
+		if err != nil || !(v >= 0) || math.IsInf(v, 0) {
+			return 0, ErrInvalidInput
+		}
+		at = v
+	}
+	if durationSeconds > 0 && at >= durationSeconds {
+		// The last decodable frame sits a little before the reported end.
+		at = math.Max(0, durationSeconds-0.1)
+	}
+	return at, nil
+}
+
+func aspectParts(aspect AspectRatio) (int, int) {
+	switch aspect {
+	case Aspect916:
+		return 9, 16
+	case Aspect11:
+		return 1, 1
+	default:
+		return 16, 9
+	}
+}
+
+// sameShape reports whether a w x h frame is within a pixel of rw:rh.
+func sameShape(w, h, rw, rh int) bool {
+	return math.Abs(float64(w)-float64(h*rw)/float64(rh)) < 1
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,79 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
+)
+
+// ResourceLimits cap what a single transcoder invocation may use.
+type ResourceLimits struct {
+	Threads            int
+	MaxOutputBytes     int64
+	MaxDurationSeconds float64
+}
+
+type WatermarkSpec struct {
+	Text      string
+	Placement string
+	Opacity   float64
+}
+
+// RenderSpec describes one output video. Crop is applied to the source
+// frame before it is scaled to Width x Height; a zero Crop keeps the frame.
+type RenderSpec struct {
+	Source          string
+	Output          string
+	Width           int
+	Height          int
+	Crop            domain.Rect
+	Watermark       *WatermarkSpec
+	DurationSeconds float64
+	Limits          ResourceLimits
+}
+
+type ThumbnailSpec struct {
+	Source    string
+	Output    string
+	AtSeconds float64
+	Width     int
+	Height    int
+	Crop      domain.Rect
+	Limits    ResourceLimits
+}
+
+// Transcoder works on local files. Render reports progress as a fraction
+// between 0 and 1 and must stop promptly once ctx is done.
+type Transcoder interface {
+	Probe(ctx context.Context, source string) (domain.MediaInfo, error)
+	Render(ctx context.Context, spec RenderSpec, progress func(float64)) error
+	Thumbnail(ctx context.Context, spec ThumbnailSpec) error
+}
+
+// BlobStore holds uploaded sources and rendered outputs. Put returns the
+// URL the stored object is served from.
+type BlobStore interface {
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, body io.Reader, contentType string) (string, error)
+}
+
+// UploadStore keeps the bytes of resumable uploads until they are complete
+// and verified.
+type UploadStore interface {
+	// Offset is the number of bytes received so far; 0 for an unknown upload.
+	Offset(ctx context.Context, uploadID string) (int64, error)
+	// Append writes body at offset, which must equal the current offset,
+	// and returns the new offset. Bytes read before an error are kept.
+	Append(ctx context.Context, uploadID string, offset int64, body io.Reader) (int64, error)
+	// Truncate drops everything after offset.
+	Truncate(ctx context.Context, uploadID string, offset int64) error
+	Open(ctx context.Context, uploadID string) (UploadReader, error)
+	Delete(ctx context.Context, uploadID string) error
+}
+
+type UploadReader interface {
+	io.Reader
+	io.ReaderAt
+	io.Closer
+}
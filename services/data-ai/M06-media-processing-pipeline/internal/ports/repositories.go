@@ -10,7 +10,7 @@ import (
 type AssetRepository interface {
 	Create(ctx context.Context, asset domain.MediaAsset) error
 	GetByID(ctx context.Context, assetID string) (domain.MediaAsset, error)
-	GetBySubmissionAndChecksum(ctx context.Context, submissionID, checksum string) (domain.MediaAsset, error)
+	GetBySubmissionAndChecksum(ctx context.Context, submissionID, checksum, uploadedBy string) (domain.MediaAsset, error)
 	Update(ctx context.Context, asset domain.MediaAsset) error
 }
 
@@ -1,14 +1,28 @@
 package unit
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	eventadapter "github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/grpc"
+	httpadapter "github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/http"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/storage"
+	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/adapters/transcoder"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/application"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/contracts"
 	"github.com/viralforge/mesh/services/data-ai/M06-media-processing-pipeline/internal/domain"
@@ -19,25 +33,158 @@ type testDeps struct {
 	repos   *postgres.Repositories
 	queue   *eventadapter.MemoryJobQueue
 	dlq     *eventadapter.MemoryDLQPublisher
+	blobs   *storage.Memory
+	uploads *storage.MemoryUploads
 }
 
 func newService() testDeps {
+	return newServiceWithConfig(application.Config{})
+}
+
+func newServiceWithConfig(cfg application.Config) testDeps {
 	repos := postgres.NewRepositories()
 	queue := eventadapter.NewMemoryJobQueue()
 	dlq := eventadapter.NewMemoryDLQPublisher()
+	blobs := storage.NewMemory("https://cdn.test")
+	uploads := storage.NewMemoryUploads()
+	cfg.ServiceName = "M06-Media-Processing-Pipeline"
+	cfg.IdempotencyTTL = 7 * 24 * time.Hour
+	cfg.EventDedupTTL = 7 * 24 * time.Hour
+	cfg.FullPipelineEnabled = true
 	service := application.NewService(application.Dependencies{
-		Config: application.Config{
-			ServiceName:         "M06-Media-Processing-Pipeline",
-			IdempotencyTTL:      7 * 24 * time.Hour,
-			EventDedupTTL:       7 * 24 * time.Hour,
-			FullPipelineEnabled: true,
-		},
+		Config: cfg,
 		Assets: repos.Assets, Jobs: repos.Jobs, Outputs: repos.Outputs, Thumbnails: repos.Thumbnails, Watermarks: repos.Watermarks, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
-		Campaign: grpcadapter.NewCampaignClient(""),
-		Queue:    queue,
-		DLQ:      dlq,
+		Campaign:   grpcadapter.NewCampaignClient(""),
+		Queue:      queue,
+		DLQ:        dlq,
+		Transcoder: transcoder.NewFake(),
+		Blobs:      blobs,
+		Uploads:    uploads,
 	})
-	return testDeps{service: service, repos: repos, queue: queue, dlq: dlq}
+	return testDeps{service: service, repos: repos, queue: queue, dlq: dlq, blobs: blobs, uploads: uploads}
+}
+
+var hdSource = transcoder.FakeSource{MediaInfo: domain.MediaInfo{Width: 1920, Height: 1080, DurationSeconds: 30, VideoCodec: "h264", HasAudio: true}}
+
+func putSource(t *testing.T, deps testDeps, assetID string, src []byte) {
+	t.Helper()
+	if _, err := deps.blobs.Put(context.Background(), "media-raw/"+assetID, bytes.NewReader(src), "video/mp4"); err != nil {
+		t.Fatalf("put source: %v", err)
+	}
+}
+
+// uploadClip registers an upload of clip and sends it in one chunk, which
+// queues the asset's jobs. Tests that need a particular picture replace the
+// stored source with putSource afterwards.
+func uploadClip(t *testing.T, svc *application.Service, subjectID string, input application.CreateUploadInput, clip []byte) application.UploadResult {
+	t.Helper()
+	sum := sha256.Sum256(clip)
+	input.FileName, input.MIMEType = "clip.mp4", "video/mp4"
+	input.FileSize, input.ChecksumSHA256 = int64(len(clip)), hex.EncodeToString(sum[:])
+	actor := application.Actor{SubjectID: subjectID, Role: "user", IdempotencyKey: "media-upload:" + input.SubmissionID + ":" + input.ChecksumSHA256}
+	upload, err := svc.CreateUpload(context.Background(), actor, input)
+	if err != nil {
+		t.Fatalf("create upload: %v", err)
+	}
+	progress, err := svc.AppendUpload(context.Background(), actor, application.UploadChunkInput{AssetID: upload.AssetID, Body: bytes.NewReader(clip)})
+	if err != nil || !progress.Complete {
+		t.Fatalf("send upload: %+v (%v)", progress, err)
+	}
+	return upload
+}
+
+// mp4Clip describes the single-video-track file buildMP4 writes.
+type mp4Clip struct {
+	width, height int
+	rotation      int
+	seconds       int
+	fps           int
+	audio         bool
+	quickTime     bool
+	moovLast      bool
+}
+
+func mp4Box(kind string, parts ...[]byte) []byte {
+	payload := bytes.Join(parts, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(payload)))
+	return append(append(out, kind...), payload...)
+}
+
+func u32s(values ...uint32) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.BigEndian.AppendUint32(out, v)
+	}
+	return out
+}
+
+// buildMP4 writes a minimal ISO-BMFF file: the sample tables describe the
+// clip but the media data is filler.
+func buildMP4(c mp4Clip) []byte {
+	brand := "isom"
+	if c.quickTime {
+		brand = "qt  "
+	}
+	ftyp := mp4Box("ftyp", []byte(brand), u32s(0x200), []byte(brand))
+	mdat := mp4Box("mdat", make([]byte, 64))
+	moov := func(chunkOffset uint32) []byte {
+		turn := map[int][2]int32{0: {1, 0}, 90: {0, 1}, 180: {-1, 0}, 270: {0, -1}}[c.rotation]
+		cos, sin := turn[0], turn[1]
+		matrix := u32s(uint32(cos<<16), uint32(sin<<16), 0, uint32(-sin<<16), uint32(cos<<16), 0, 0, 0, 1<<30)
+		identity := u32s(1<<16, 0, 0, 0, 1<<16, 0, 0, 0, 1<<30)
+		const timescale = 30000
+		track := func(id uint32, handler, codec string, width, height int, trackMatrix []byte, samples, delta uint32) []byte {
+			entry := append(make([]byte, 6), 0, 1)
+			if handler == "vide" {
+				entry = append(entry, make([]byte, 16)...)
+				entry = binary.BigEndian.AppendUint16(entry, uint16(width))
+				entry = binary.BigEndian.AppendUint16(entry, uint16(height))
+				entry = append(entry, make([]byte, 50)...)
+			} else {
+				entry = append(entry, make([]byte, 20)...)
+			}
+			return mp4Box("trak",
+				mp4Box("tkhd", u32s(3, 0, 0, id, 0, uint32(c.seconds*1000), 0, 0, 0, 0), trackMatrix, u32s(uint32(width<<16), uint32(height<<16))),
+				mp4Box("mdia",
+					mp4Box("mdhd", u32s(0, 0, 0, timescale, uint32(c.seconds*timescale), 0)),
+					mp4Box("hdlr", u32s(0, 0), []byte(handler), make([]byte, 13)),
+					mp4Box("minf", mp4Box("stbl",
+						mp4Box("stsd", u32s(0, 1), mp4Box(codec, entry)),
+						mp4Box("stts", u32s(0, 1, samples, delta)),
+						mp4Box("stco", u32s(0, 1, chunkOffset)),
+					)),
+				),
+			)
+		}
+		parts := [][]byte{
+			mp4Box("mvhd", u32s(0, 0, 0, 1000, uint32(c.seconds*1000), 1<<16), make([]byte, 12), identity, make([]byte, 24), u32s(3)),
+			track(1, "vide", "avc1", c.width, c.height, matrix, uint32(c.seconds*c.fps), uint32(timescale/c.fps)),
+		}
+		if c.audio {
+			parts = append(parts, track(2, "soun", "mp4a", 0, 0, identity, 1, uint32(c.seconds*timescale)))
+		}
+		return mp4Box("moov", parts...)
+	}
+	if c.moovLast {
+		return bytes.Join([][]byte{ftyp, mdat, moov(uint32(len(ftyp) + 8))}, nil)
+	}
+	offset := len(ftyp) + len(moov(0)) + 8
+	return bytes.Join([][]byte{ftyp, moov(uint32(offset)), mdat}, nil)
+}
+
+var hdClip = buildMP4(mp4Clip{width: 1920, height: 1080, seconds: 30, fps: 30, audio: true})
+
+func drainQueue(t *testing.T, svc *application.Service) {
+	t.Helper()
+	for i := 0; i < 32; i++ {
+		err := svc.ProcessNextJob(context.Background())
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("process job: %v", err)
+		}
+	}
 }
 
 func TestCreateUploadIdempotent(t *testing.T) {
@@ -82,14 +229,10 @@ func TestRetryRequiresAdmin(t *testing.T) {
 func TestProcessQueueGeneratesVariantsAndCompletes(t *testing.T) {
 	deps := newService()
 	svc := deps.service
-	actor := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "media-upload:sub-2:def"}
-	asset, err := svc.CreateUpload(context.Background(), actor, application.CreateUploadInput{SubmissionID: "sub-2", FileName: "clip.mp4", MIMEType: "video/mp4", FileSize: 1000, ChecksumSHA256: "def"})
-	if err != nil {
-		t.Fatalf("create upload: %v", err)
-	}
+	asset := uploadClip(t, svc, "user-1", application.CreateUploadInput{SubmissionID: "sub-2"}, hdClip)
 
 	for i := 0; i < 32; i++ {
-		err = svc.ProcessNextJob(context.Background())
+		err := svc.ProcessNextJob(context.Background())
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -115,15 +258,9 @@ func TestProcessQueueGeneratesVariantsAndCompletes(t *testing.T) {
 func TestRetryCompletedAssetNoOp(t *testing.T) {
 	deps := newService()
 	svc := deps.service
-	uploadActor := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "media-upload:sub-3:ghi"}
-	asset, err := svc.CreateUpload(context.Background(), uploadActor, application.CreateUploadInput{
-		SubmissionID: "sub-3", FileName: "clip.mp4", MIMEType: "video/mp4", FileSize: 1000, ChecksumSHA256: "ghi",
-	})
-	if err != nil {
-		t.Fatalf("create upload: %v", err)
-	}
+	asset := uploadClip(t, svc, "user-1", application.CreateUploadInput{SubmissionID: "sub-3"}, hdClip)
 	for i := 0; i < 32; i++ {
-		err = svc.ProcessNextJob(context.Background())
+		err := svc.ProcessNextJob(context.Background())
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -282,19 +419,10 @@ func TestProcessNextJobNoOpWhenPipelineDisabled(t *testing.T) {
 		Campaign: grpcadapter.NewCampaignClient(""),
 		Queue:    queue,
 		DLQ:      dlq,
+		Uploads:  storage.NewMemoryUploads(),
 	})
 
-	actor := application.Actor{SubjectID: "user-flag", Role: "user", IdempotencyKey: "media-upload:sub-flag:hash"}
-	upload, err := svc.CreateUpload(context.Background(), actor, application.CreateUploadInput{
-		SubmissionID:   "sub-flag",
-		FileName:       "clip.mp4",
-		MIMEType:       "video/mp4",
-		FileSize:       1000,
-		ChecksumSHA256: "hash",
-	})
-	if err != nil {
-		t.Fatalf("create upload: %v", err)
-	}
+	upload := uploadClip(t, svc, "user-flag", application.CreateUploadInput{SubmissionID: "sub-flag"}, hdClip)
 	if queue.Len() == 0 {
 		t.Fatalf("expected queued jobs")
 	}
@@ -311,3 +439,449 @@ func TestProcessNextJobNoOpWhenPipelineDisabled(t *testing.T) {
 		t.Fatalf("expected processing status when pipeline disabled, got %s", status.Status)
 	}
 }
+
+func fakeOutput(t *testing.T, deps testDeps, key string) transcoder.FakeOutput {
+	t.Helper()
+	blob, ok := deps.blobs.Object(key)
+	if !ok {
+		t.Fatalf("expected blob %s", key)
+	}
+	var out transcoder.FakeOutput
+	if err := json.Unmarshal(blob, &out); err != nil {
+		t.Fatalf("decode %s: %v", key, err)
+	}
+	return out
+}
+
+func TestTranscodeCentresCropsOnPictureAndRendersRequestedThumbnails(t *testing.T) {
+	deps := newServiceWithConfig(application.Config{TranscodeThreads: 3})
+	svc := deps.service
+	upload := uploadClip(t, svc, "user-1", application.CreateUploadInput{SubmissionID: "sub-crop", ThumbnailPositions: []string{"5s", "50%"}}, hdClip)
+	// A 4:3 picture pillarboxed into a 1080p frame.
+	src := hdSource
+	src.Content = domain.Rect{X: 240, Y: 0, Width: 1440, Height: 1080}
+	putSource(t, deps, upload.AssetID, src.Bytes())
+	drainQueue(t, svc)
+
+	status, err := svc.GetAssetStatus(context.Background(), application.Actor{SubjectID: "user-1"}, upload.AssetID)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if status.Status != string(domain.AssetStatusCompleted) || len(status.Outputs) != 6 || len(status.Thumbnails) != 6 {
+		t.Fatalf("unexpected status %s with %d outputs and %d thumbnails", status.Status, len(status.Outputs), len(status.Thumbnails))
+	}
+	for _, job := range status.Jobs {
+		if job.Progress != 1 {
+			t.Fatalf("expected job %s at full progress, got %v", job.JobType, job.Progress)
+		}
+	}
+	for _, output := range status.Outputs {
+		if !strings.HasPrefix(output.URL, "https://cdn.test/media/"+upload.AssetID+"/") {
+			t.Fatalf("unexpected output url %s", output.URL)
+		}
+	}
+
+	vertical := fakeOutput(t, deps, "media/"+upload.AssetID+"/9x16-1080p.mp4")
+	if vertical.Width != 1080 || vertical.Height != 1920 {
+		t.Fatalf("unexpected 9:16 size %dx%d", vertical.Width, vertical.Height)
+	}
+	if vertical.Crop != (domain.Rect{X: 656, Y: 0, Width: 606, Height: 1080}) {
+		t.Fatalf("expected crop centred on the picture, got %+v", vertical.Crop)
+	}
+	if vertical.Limits.Threads != 3 || vertical.Watermark != nil {
+		t.Fatalf("unexpected render spec %+v", vertical)
+	}
+	wide := fakeOutput(t, deps, "media/"+upload.AssetID+"/1080p.mp4")
+	if wide.Crop != (domain.Rect{}) || wide.Width != 1920 {
+		t.Fatalf("expected uncropped 16:9 rendition, got %+v", wide)
+	}
+	thumb := fakeOutput(t, deps, "thumbnails/"+upload.AssetID+"/1x1/50pct.jpg")
+	if thumb.AtSeconds != 15 || thumb.Width != 720 || thumb.Crop.Width != 1080 {
+		t.Fatalf("unexpected thumbnail %+v", thumb)
+	}
+	if fakeOutput(t, deps, "thumbnails/"+upload.AssetID+"/16x9/5s.jpg").AtSeconds != 5 {
+		t.Fatalf("expected thumbnail at 5s")
+	}
+
+	meta, err := svc.GetAssetMetadata(context.Background(), upload.AssetID)
+	if err != nil {
+		t.Fatalf("metadata: %v", err)
+	}
+	if meta.DurationSeconds != 30 || meta.Codec != "h264" {
+		t.Fatalf("expected probed metadata, got %+v", meta)
+	}
+}
+
+func TestWatermarkIsBurnedIntoApprovedAsset(t *testing.T) {
+	deps := newService()
+	now := time.Now().UTC()
+	asset := domain.MediaAsset{AssetID: "asset-wm", SubmissionID: "sub-wm", OriginalFilename: "clip.mp4", MIMEType: "video/mp4", FileSize: 1000, SourceS3URL: "s3://media-raw/asset-wm", Status: domain.AssetStatusProcessing, ApprovalStatus: domain.ApprovalStatusApproved, CreatedAt: now, UpdatedAt: now}
+	if err := deps.repos.Assets.Create(context.Background(), asset); err != nil {
+		t.Fatalf("seed asset: %v", err)
+	}
+	job := domain.MediaJob{JobID: "job-wm", AssetID: asset.AssetID, JobType: domain.JobTypeWatermark, Status: domain.JobStatusQueued, QueuedAt: now}
+	if err := deps.repos.Jobs.CreateMany(context.Background(), []domain.MediaJob{job}); err != nil {
+		t.Fatalf("seed job: %v", err)
+	}
+	_ = deps.queue.Enqueue(context.Background(), job.JobID)
+	putSource(t, deps, asset.AssetID, hdSource.Bytes())
+	drainQueue(t, deps.service)
+
+	out := fakeOutput(t, deps, "media/asset-wm/1080p-watermarked.mp4")
+	if out.Watermark == nil || out.Watermark.Text != "wmk_sub-wm" || out.Watermark.Placement != "bottom-right" {
+		t.Fatalf("expected burned-in watermark, got %+v", out.Watermark)
+	}
+	record, err := deps.repos.Watermarks.GetByAsset(context.Background(), asset.AssetID)
+	if err != nil || record.AppliedAt.IsZero() {
+		t.Fatalf("expected applied watermark record, got %+v (%v)", record, err)
+	}
+	outputs, _ := deps.repos.Outputs.ListByAsset(context.Background(), asset.AssetID)
+	if len(outputs) != 1 || outputs[0].Profile != domain.ProfileWatermarked1080 {
+		t.Fatalf("unexpected outputs %+v", outputs)
+	}
+}
+
+func TestStalledTranscodeTimesOutAndCorruptSourceFailsFast(t *testing.T) {
+	deps := newServiceWithConfig(application.Config{JobTimeout: 50 * time.Millisecond})
+	svc := deps.service
+	upload := uploadClip(t, svc, "user-1", application.CreateUploadInput{SubmissionID: "sub-stall"}, hdClip)
+	stalled := hdSource
+	stalled.Stall = true
+	putSource(t, deps, upload.AssetID, stalled.Bytes())
+
+	// The first queued job is the 1080p transcode.
+	if err := svc.ProcessNextJob(context.Background()); err != nil {
+		t.Fatalf("process job: %v", err)
+	}
+	job, err := deps.repos.Jobs.GetByID(context.Background(), upload.AssetID+"-job-1")
+	if err != nil {
+		t.Fatalf("load job: %v", err)
+	}
+	if job.Status != domain.JobStatusQueued || job.ErrorMessage != "job_timeout" || job.Attempts != 1 {
+		t.Fatalf("expected timed out job to be requeued, got %+v", job)
+	}
+
+	putSource(t, deps, upload.AssetID, []byte("not a video"))
+	drainQueue(t, svc)
+	job, _ = deps.repos.Jobs.GetByID(context.Background(), upload.AssetID+"-job-1")
+	if job.Status != domain.JobStatusFailed || job.ErrorMessage != "corrupt_source" || job.Attempts != 2 {
+		t.Fatalf("expected corrupt source to fail without retries, got %+v", job)
+	}
+	if len(deps.dlq.Records()) != 0 {
+		t.Fatalf("non-retryable failures must not reach the DLQ")
+	}
+}
+
+func TestCancelJobStopsRunningTranscode(t *testing.T) {
+	deps := newService()
+	svc := deps.service
+	upload := uploadClip(t, svc, "user-1", application.CreateUploadInput{SubmissionID: "sub-cancel"}, hdClip)
+	stalled := hdSource
+	stalled.Stall = true
+	putSource(t, deps, upload.AssetID, stalled.Bytes())
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin"}
+	running := upload.AssetID + "-job-1"
+	queued := upload.AssetID + "-job-2"
+
+	if _, err := svc.CancelJob(context.Background(), application.Actor{SubjectID: "user-1", Role: "user"}, running); err != domain.ErrForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- svc.ProcessNextJob(context.Background()) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, _ := deps.repos.Jobs.GetByID(context.Background(), running)
+		if job.Progress >= 0.5 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job never reported progress: %+v", job)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := svc.CancelJob(context.Background(), admin, running); err != nil {
+		t.Fatalf("cancel running job: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("process job: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("cancelled job kept running")
+	}
+	if _, err := svc.CancelJob(context.Background(), admin, queued); err != nil {
+		t.Fatalf("cancel queued job: %v", err)
+	}
+
+	status, err := svc.GetAssetStatus(context.Background(), application.Actor{SubjectID: "user-1"}, upload.AssetID)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if status.Status != string(domain.AssetStatusFailed) || status.ErrorCode != "job_cancelled" {
+		t.Fatalf("expected failed asset after cancellation, got %s/%s", status.Status, status.ErrorCode)
+	}
+	job, _ := deps.repos.Jobs.GetByID(context.Background(), running)
+	if job.Status != domain.JobStatusCancelled {
+		t.Fatalf("expected cancelled job, got %s", job.Status)
+	}
+
+	putSource(t, deps, upload.AssetID, hdSource.Bytes())
+	drainQueue(t, svc)
+	retry, err := svc.RetryAsset(context.Background(), application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "media-retry:" + upload.AssetID + ":1"}, application.RetryAssetInput{AssetID: upload.AssetID})
+	if err != nil || retry.JobsRestarted != 2 {
+		t.Fatalf("expected both cancelled jobs restarted, got %+v (%v)", retry, err)
+	}
+	drainQueue(t, svc)
+	status, _ = svc.GetAssetStatus(context.Background(), application.Actor{SubjectID: "user-1"}, upload.AssetID)
+	if status.Status != string(domain.AssetStatusCompleted) {
+		t.Fatalf("expected completed asset after retry, got %s", status.Status)
+	}
+}
+
+func TestInspectContainerReadsBoxesAndRejectsBrokenFiles(t *testing.T) {
+	portrait := buildMP4(mp4Clip{width: 1920, height: 1080, rotation: 90, seconds: 12, fps: 25, quickTime: true, moovLast: true})
+	info, err := domain.InspectContainer(bytes.NewReader(portrait), int64(len(portrait)))
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	want := domain.ContainerInfo{Format: "mov", MajorBrand: "qt", MIMEType: "video/quicktime", DurationSeconds: 12, Width: 1920, Height: 1080, FrameRate: 25, Rotation: 90, VideoCodec: "h264"}
+	if info != want {
+		t.Fatalf("unexpected container info %+v", info)
+	}
+	info, err = domain.InspectContainer(bytes.NewReader(hdClip), int64(len(hdClip)))
+	if err != nil || info.MIMEType != "video/mp4" || !info.FastStart || info.AudioCodec != "aac" || info.FrameRate != 30 {
+		t.Fatalf("unexpected faststart info %+v (%v)", info, err)
+	}
+
+	broken := map[string]struct {
+		data []byte
+		want error
+	}{
+		"cut before moov":    {portrait[:len(portrait)-40], domain.ErrTruncatedMedia},
+		"cut inside mdat":    {hdClip[:len(hdClip)-10], domain.ErrTruncatedMedia},
+		"png named as mp4":   {[]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), domain.ErrUnsupportedMedia},
+		"movie without mdat": {mp4Box("moov"), domain.ErrCorruptMedia},
+		"undersized box":     {append(mp4Box("ftyp", []byte("isom")), 0, 0, 0, 4, 'f', 'r', 'e', 'e'), domain.ErrCorruptMedia},
+	}
+	for name, tc := range broken {
+		if _, err := domain.InspectContainer(bytes.NewReader(tc.data), int64(len(tc.data))); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestChunkedUploadResumesAndVerifiesChunks(t *testing.T) {
+	deps := newService()
+	svc := deps.service
+	ctx := context.Background()
+	clip := buildMP4(mp4Clip{width: 1080, height: 1920, seconds: 20, fps: 30, audio: true, moovLast: true})
+	sum := sha256.Sum256(clip)
+	checksum := hex.EncodeToString(sum[:])
+	actor := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "media-upload:sub-chunk:" + checksum}
+	upload, err := svc.CreateUpload(ctx, actor, application.CreateUploadInput{SubmissionID: "sub-chunk", FileName: "clip.mov", MIMEType: "video/quicktime", FileSize: int64(len(clip)), ChecksumSHA256: checksum})
+	if err != nil {
+		t.Fatalf("create upload: %v", err)
+	}
+	if !strings.HasSuffix(upload.UploadURL, "/v1/media/uploads/"+upload.AssetID) || deps.queue.Len() != 0 {
+		t.Fatalf("expected an upload url and no jobs before the bytes arrive, got %s with %d queued", upload.UploadURL, deps.queue.Len())
+	}
+	chunk := func(offset, end int, checksum []byte) application.UploadChunkInput {
+		return application.UploadChunkInput{AssetID: upload.AssetID, Offset: int64(offset), Body: bytes.NewReader(clip[offset:end]), ChecksumAlgorithm: "sha256", Checksum: checksum}
+	}
+	first := sha256.Sum256(clip[:100])
+	if progress, err := svc.AppendUpload(ctx, actor, chunk(0, 100, first[:])); err != nil || progress.Offset != 100 || progress.Complete {
+		t.Fatalf("first chunk: %+v (%v)", progress, err)
+	}
+	if _, err := svc.AppendUpload(ctx, application.Actor{SubjectID: "user-2"}, chunk(100, 200, nil)); err != domain.ErrForbidden {
+		t.Fatalf("expected other users to be refused, got %v", err)
+	}
+	if _, err := svc.AppendUpload(ctx, actor, chunk(50, 200, nil)); err != domain.ErrUploadOffsetMismatch {
+		t.Fatalf("expected offset mismatch, got %v", err)
+	}
+	if _, err := svc.AppendUpload(ctx, actor, chunk(100, 200, first[:])); err != domain.ErrChecksumMismatch {
+		t.Fatalf("expected chunk checksum mismatch, got %v", err)
+	}
+	if _, err := svc.AppendUpload(ctx, actor, application.UploadChunkInput{AssetID: upload.AssetID, Offset: 100, Body: bytes.NewReader(nil), ChecksumAlgorithm: "crc32"}); err != domain.ErrUnsupportedChecksum {
+		t.Fatalf("expected unsupported checksum, got %v", err)
+	}
+	progress, err := svc.GetUploadProgress(ctx, actor, upload.AssetID)
+	if err != nil || progress.Offset != 100 || progress.Length != int64(len(clip)) {
+		t.Fatalf("expected a rejected chunk to leave the offset alone, got %+v (%v)", progress, err)
+	}
+
+	progress, err = svc.AppendUpload(ctx, actor, application.UploadChunkInput{AssetID: upload.AssetID, Offset: 100, Body: bytes.NewReader(clip[100:])})
+	if err != nil || !progress.Complete || progress.Status != string(domain.AssetStatusProcessing) {
+		t.Fatalf("last chunk: %+v (%v)", progress, err)
+	}
+	asset, _ := deps.repos.Assets.GetByID(ctx, upload.AssetID)
+	if asset.MIMEType != "video/mp4" || asset.DurationSeconds != 20 || asset.Width != 1080 || asset.Height != 1920 || asset.FrameRate != 30 || asset.AudioCodec != "aac" || asset.FastStart {
+		t.Fatalf("expected metadata read from the file, got %+v", asset)
+	}
+	if source, ok := deps.blobs.Object("media-raw/" + upload.AssetID); !ok || !bytes.Equal(source, clip) {
+		t.Fatalf("expected verified source in blob storage")
+	}
+	if deps.queue.Len() != len(domain.NewDefaultJobs(upload.AssetID, time.Now())) {
+		t.Fatalf("expected jobs once the upload is verified, got %d", deps.queue.Len())
+	}
+	if _, err := svc.AppendUpload(ctx, actor, chunk(0, 10, nil)); err != domain.ErrConflict {
+		t.Fatalf("expected completed upload to refuse chunks, got %v", err)
+	}
+	drainQueue(t, svc)
+	status, _ := svc.GetAssetStatus(ctx, actor, upload.AssetID)
+	if status.Status != string(domain.AssetStatusCompleted) {
+		t.Fatalf("expected completed asset, got %s", status.Status)
+	}
+}
+
+func TestUploadRejectsSpoofedTruncatedAndMismatchedFiles(t *testing.T) {
+	deps := newService()
+	svc := deps.service
+	ctx := context.Background()
+	send := func(submissionID string, data []byte, declared string) (application.UploadResult, error) {
+		t.Helper()
+		actor := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "media-upload:" + submissionID + ":" + declared}
+		upload, err := svc.CreateUpload(ctx, actor, application.CreateUploadInput{SubmissionID: submissionID, FileName: "clip.mp4", MIMEType: "video/mp4", FileSize: int64(len(data)), ChecksumSHA256: declared})
+		if err != nil {
+			t.Fatalf("create upload: %v", err)
+		}
+		_, err = svc.AppendUpload(ctx, actor, application.UploadChunkInput{AssetID: upload.AssetID, Body: bytes.NewReader(data)})
+		return upload, err
+	}
+	checksumOf := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	spoofed := bytes.Repeat([]byte("GIF89a"), 50)
+	truncated := hdClip[:len(hdClip)-10]
+	cases := []struct {
+		submission string
+		data       []byte
+		declared   string
+		want       error
+		reason     string
+	}{
+		{"sub-spoof", spoofed, checksumOf(spoofed), domain.ErrUnsupportedMedia, "unsupported_media"},
+		{"sub-cut", truncated, checksumOf(truncated), domain.ErrTruncatedMedia, "truncated_media"},
+		{"sub-sum", hdClip, checksumOf(spoofed), domain.ErrChecksumMismatch, "checksum_mismatch"},
+	}
+	for _, tc := range cases {
+		upload, err := send(tc.submission, tc.data, tc.declared)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.submission, tc.want, err)
+		}
+		asset, _ := deps.repos.Assets.GetByID(ctx, upload.AssetID)
+		if asset.Status != domain.AssetStatusFailed || asset.LastErrorCode != tc.reason {
+			t.Fatalf("%s: expected failed asset with %s, got %s/%s", tc.submission, tc.reason, asset.Status, asset.LastErrorCode)
+		}
+		if _, ok := deps.blobs.Object("media-raw/" + upload.AssetID); ok {
+			t.Fatalf("%s: rejected upload reached blob storage", tc.submission)
+		}
+		if offset, _ := deps.uploads.Offset(ctx, upload.AssetID); offset != 0 {
+			t.Fatalf("%s: rejected upload bytes were kept", tc.submission)
+		}
+	}
+	if deps.queue.Len() != 0 {
+		t.Fatalf("rejected uploads must not queue jobs, got %d", deps.queue.Len())
+	}
+}
+
+func TestCreateUploadRestartsFailedUploadForItsUploaderOnly(t *testing.T) {
+	deps := newService()
+	svc := deps.service
+	ctx := context.Background()
+	sum := sha256.Sum256(hdClip)
+	checksum := hex.EncodeToString(sum[:])
+	key := "media-upload:sub-again:" + checksum
+	owner := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: key}
+	input := application.CreateUploadInput{SubmissionID: "sub-again", FileName: "clip.mp4", MIMEType: "video/mp4", FileSize: int64(len(hdClip)), ChecksumSHA256: checksum}
+
+	first, err := svc.CreateUpload(ctx, owner, input)
+	if err != nil {
+		t.Fatalf("create upload: %v", err)
+	}
+	other, err := svc.CreateUpload(ctx, application.Actor{SubjectID: "user-2", Role: "user", IdempotencyKey: key}, input)
+	if err != nil || other.AssetID == first.AssetID {
+		t.Fatalf("expected another uploader to get their own asset, got %s (%v)", other.AssetID, err)
+	}
+	if err := svc.TerminateUpload(ctx, owner, first.AssetID); err != nil {
+		t.Fatalf("terminate upload: %v", err)
+	}
+
+	again, err := svc.CreateUpload(ctx, owner, input)
+	if err != nil || again.AssetID == first.AssetID {
+		t.Fatalf("expected a terminated upload to start over under a new asset, got %s (%v)", again.AssetID, err)
+	}
+	progress, err := svc.AppendUpload(ctx, owner, application.UploadChunkInput{AssetID: again.AssetID, Body: bytes.NewReader(hdClip)})
+	if err != nil || !progress.Complete {
+		t.Fatalf("expected the new upload to accept the file, got %+v (%v)", progress, err)
+	}
+	if replay, err := svc.CreateUpload(ctx, owner, input); err != nil || replay.AssetID != again.AssetID {
+		t.Fatalf("expected the live upload to be replayed, got %s (%v)", replay.AssetID, err)
+	}
+}
+
+func TestTusProtocolCreatesResumesAndCompletesUpload(t *testing.T) {
+	deps := newService()
+	router := httpadapter.NewRouter(httpadapter.NewHandler(deps.service))
+	sum := sha256.Sum256(hdClip)
+	checksum := hex.EncodeToString(sum[:])
+	do := func(method, target string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer user-1")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	b64 := base64.StdEncoding.EncodeToString
+
+	options := do(http.MethodOptions, "/v1/media/uploads", nil, nil)
+	if options.Code != http.StatusNoContent || !strings.Contains(options.Header().Get("Tus-Extension"), "checksum") {
+		t.Fatalf("unexpected discovery response %d %v", options.Code, options.Header())
+	}
+	if rec := do(http.MethodPost, "/v1/media/uploads", nil, map[string]string{"Tus-Resumable": "0.2.2"}); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected unsupported version to be refused, got %d", rec.Code)
+	}
+	created := do(http.MethodPost, "/v1/media/uploads", nil, map[string]string{
+		"Tus-Resumable":   "1.0.0",
+		"Idempotency-Key": "media-upload:sub-tus:" + checksum,
+		"Upload-Length":   strconv.Itoa(len(hdClip)),
+		"Upload-Metadata": "submission_id " + b64([]byte("sub-tus")) + ",filename " + b64([]byte("clip.mp4")) + ",filetype " + b64([]byte("video/mp4")) + ",checksum_sha256 " + b64([]byte(checksum)),
+	})
+	location := created.Header().Get("Location")
+	if created.Code != http.StatusCreated || !strings.HasPrefix(location, "/v1/media/uploads/") {
+		t.Fatalf("unexpected creation response %d %q", created.Code, location)
+	}
+
+	patch := func(offset, end int, extra map[string]string) *httptest.ResponseRecorder {
+		headers := map[string]string{"Tus-Resumable": "1.0.0", "Content-Type": "application/offset+octet-stream", "Upload-Offset": strconv.Itoa(offset)}
+		for k, v := range extra {
+			headers[k] = v
+		}
+		return do(http.MethodPatch, location, hdClip[offset:end], headers)
+	}
+	if rec := patch(0, 200, nil); rec.Code != http.StatusNoContent || rec.Header().Get("Upload-Offset") != "200" {
+		t.Fatalf("first chunk: %d offset %s", rec.Code, rec.Header().Get("Upload-Offset"))
+	}
+	if rec := patch(0, 200, nil); rec.Code != http.StatusConflict {
+		t.Fatalf("expected stale offset to conflict, got %d", rec.Code)
+	}
+	if rec := patch(200, 300, map[string]string{"Upload-Checksum": "sha1 " + b64(make([]byte, 20))}); rec.Code != 460 || rec.Header().Get("Upload-Offset") != "200" {
+		t.Fatalf("expected checksum mismatch, got %d offset %s", rec.Code, rec.Header().Get("Upload-Offset"))
+	}
+	head := do(http.MethodHead, location, nil, map[string]string{"Tus-Resumable": "1.0.0"})
+	if head.Code != http.StatusOK || head.Header().Get("Upload-Offset") != "200" || head.Header().Get("Upload-Length") != strconv.Itoa(len(hdClip)) {
+		t.Fatalf("unexpected resume offset %d %v", head.Code, head.Header())
+	}
+	rest := sha256.Sum256(hdClip[200:])
+	if rec := patch(200, len(hdClip), map[string]string{"Upload-Checksum": "sha256 " + b64(rest[:])}); rec.Code != http.StatusNoContent {
+		t.Fatalf("last chunk: %d %s", rec.Code, rec.Body.String())
+	}
+	if deps.queue.Len() == 0 {
+		t.Fatalf("expected jobs after the tus upload completed")
+	}
+}
@@ -3,3 +3,7 @@ module github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai
 go 1.23
 
 require gopkg.in/yaml.v3 v3.0.1
+
+require github.com/viralforge/mesh/platform v0.0.0
+
+replace github.com/viralforge/mesh/platform => ../../../platform
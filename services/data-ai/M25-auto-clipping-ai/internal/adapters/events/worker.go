@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/application"
+)
+
+type Worker struct {
+	logger       *slog.Logger
+	service      *application.Service
+	pollInterval time.Duration
+}
+
+func NewWorker(logger *slog.Logger, service *application.Service, pollInterval time.Duration) *Worker {
+	return &Worker{logger: logger, service: service, pollInterval: pollInterval}
+}
+
+// Run drains the queue on every tick until ctx is done, backing off to
+// the next tick after an error.
+func (w *Worker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				err := w.service.ProcessNextJob(ctx)
+				if err == nil {
+					continue
+				}
+				if !errors.Is(err, io.EOF) {
+					w.logger.ErrorContext(ctx, "clip job processing failed", "error", err)
+				}
+				break
+			}
+		}
+	}
+}
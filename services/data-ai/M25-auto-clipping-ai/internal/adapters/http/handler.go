@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/application"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/contracts"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+)
+
+// maxJobBodyBytes leaves room for a long source's signals inline.
+const maxJobBodyBytes = 32 << 20
+
+type Handler struct {
+	service *application.Service
+}
+
+func NewHandler(service *application.Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) createClipJob(w http.ResponseWriter, r *http.Request) {
+	var req contracts.CreateClipJobRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJobBodyBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid JSON payload", requestIDFromContext(r.Context()))
+		return
+	}
+	actor := actorFromContext(r.Context())
+	job, err := h.service.CreateClipJob(r.Context(), actor, application.CreateClipJobInput{
+		SourceAssetID: strings.TrimSpace(req.SourceAssetID),
+		Signals: domain.Signals{
+			DurationSeconds: req.DurationSeconds,
+			Loudness:        req.Loudness,
+			SceneCuts:       req.SceneCuts,
+			Transcript:      req.Transcript,
+		},
+		Options: domain.ClipOptions{
+			MinSeconds:    req.MinDurationSeconds,
+			MaxSeconds:    req.MaxDurationSeconds,
+			Keywords:      req.Keywords,
+			Targets:       req.Targets,
+			MaxCandidates: req.MaxCandidates,
+		},
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	w.Header().Set("Location", "/v1/clips/jobs/"+job.JobID)
+	writeSuccess(w, http.StatusAccepted, job)
+}
+
+func (h *Handler) getClipJob(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	job, err := h.service.GetClipJob(r.Context(), actor, r.PathValue("job_id"))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, job)
+}
+
+func (h *Handler) listModels(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	models, err := h.service.ListModels(r.Context(), actor)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, map[string]interface{}{"items": models})
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/application"
+)
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	actorKey     contextKey = "actor"
+)
+
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := strings.TrimSpace(r.Header.Get("X-Request-Id"))
+		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.SplitN(strings.TrimSpace(r.Header.Get("Authorization")), " ", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "bearer token required", requestIDFromContext(r.Context()))
+			return
+		}
+		role := strings.ToLower(strings.TrimSpace(r.Header.Get("X-Actor-Role")))
+		if role == "" {
+			role = "user"
+		}
+		actor := application.Actor{
+			SubjectID:      strings.TrimSpace(parts[1]),
+			Role:           role,
+			RequestID:      requestIDFromContext(r.Context()),
+			IdempotencyKey: strings.TrimSpace(r.Header.Get("Idempotency-Key")),
+		}
+		ctx := context.WithValue(r.Context(), actorKey, actor)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func actorFromContext(ctx context.Context) application.Actor {
+	if value := ctx.Value(actorKey); value != nil {
+		if actor, ok := value.(application.Actor); ok {
+			return actor
+		}
+	}
+	return application.Actor{}
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	if value := ctx.Value(requestIDKey); value != nil {
+		if requestID, ok := value.(string); ok {
+			return requestID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/contracts"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+)
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeSuccess(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, contracts.SuccessResponse{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, code, message, requestID string) {
+	writeJSON(w, status, contracts.ErrorResponse{
+		Status: "error",
+		Error: contracts.ErrorPayload{
+			Code:      code,
+			Message:   message,
+			RequestID: requestID,
+		},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func mapDomainError(err error) (int, string) {
+	switch err {
+	case nil:
+		return http.StatusOK, ""
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized, "UNAUTHORIZED"
+	case domain.ErrForbidden:
+		return http.StatusForbidden, "FORBIDDEN"
+	case domain.ErrNotFound:
+		return http.StatusNotFound, "NOT_FOUND"
+	case domain.ErrInvalidInput:
+		return http.StatusBadRequest, "INVALID_REQUEST"
+	case domain.ErrNoSignals:
+		return http.StatusUnprocessableEntity, "NO_SIGNALS"
+	case domain.ErrNoCandidates:
+		return http.StatusUnprocessableEntity, "NO_CANDIDATES"
+	case domain.ErrIdempotencyRequired:
+		return http.StatusBadRequest, "IDEMPOTENCY_KEY_REQUIRED"
+	case domain.ErrIdempotencyConflict:
+		return http.StatusConflict, "IDEMPOTENCY_COLLISION"
+	case domain.ErrIdempotencyInFlight:
+		return http.StatusConflict, "IDEMPOTENCY_IN_FLIGHT"
+	default:
+		return http.StatusInternalServerError, "INTERNAL_ERROR"
+	}
+}
@@ -0,0 +1,13 @@
+package http
+
+import "net/http"
+
+// NewRouter serves the clip job and model listing APIs. Health and model
+// deployment stay on the bootstrap router.
+func NewRouter(handler *Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/clips/jobs", handler.createClipJob)
+	mux.HandleFunc("GET /v1/clips/jobs/{job_id}", handler.getClipJob)
+	mux.HandleFunc("GET /v1/admin/models", handler.listModels)
+	return requestIDMiddleware(authMiddleware(mux))
+}
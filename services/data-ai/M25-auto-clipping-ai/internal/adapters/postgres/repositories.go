@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/ports"
+)
+
+type Repositories struct {
+	Idempotency *IdempotencyRepository
+	Jobs        *JobRepository
+	Models      *ModelRepository
+}
+
+func NewRepositories() *Repositories {
+	return &Repositories{
+		Idempotency: &IdempotencyRepository{records: map[string]ports.IdempotencyRecord{}},
+		Jobs:        &JobRepository{records: map[string]domain.ClipJob{}},
+		Models:      &ModelRepository{records: map[string]domain.ModelVersion{}},
+	}
+}
+
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]ports.IdempotencyRecord
+}
+
+func (r *IdempotencyRepository) Get(_ context.Context, key string, now time.Time) (*ports.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec, ok := r.records[key]
+	if !ok {
+		return nil, nil
+	}
+	if now.After(rec.ExpiresAt) {
+		delete(r.records, key)
+		return nil, nil
+	}
+	clone := rec
+	clone.ResponseBody = append([]byte(nil), rec.ResponseBody...)
+	return &clone, nil
+}
+
+func (r *IdempotencyRepository) Reserve(_ context.Context, key, requestHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rec, ok := r.records[key]; ok && time.Now().UTC().Before(rec.ExpiresAt) {
+		if rec.RequestHash != requestHash {
+			return domain.ErrIdempotencyConflict
+		}
+		return nil
+	}
+	r.records[key] = ports.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   expiresAt,
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Complete(_ context.Context, key string, responseCode int, responseBody []byte, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec, ok := r.records[key]
+	if !ok {
+		return nil
+	}
+	rec.ResponseCode = responseCode
+	rec.ResponseBody = append([]byte(nil), responseBody...)
+	if at.After(rec.ExpiresAt) {
+		rec.ExpiresAt = at.Add(7 * 24 * time.Hour)
+	}
+	r.records[key] = rec
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, key)
+	return nil
+}
+
+type JobRepository struct {
+	mu      sync.RWMutex
+	records map[string]domain.ClipJob
+}
+
+func (r *JobRepository) Create(_ context.Context, job domain.ClipJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[job.JobID] = job
+	return nil
+}
+
+func (r *JobRepository) GetByID(_ context.Context, jobID string) (domain.ClipJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, ok := r.records[jobID]
+	if !ok {
+		return domain.ClipJob{}, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+func (r *JobRepository) Update(_ context.Context, job domain.ClipJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.records[job.JobID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.records[job.JobID] = job
+	return nil
+}
+
+// CountByModelVersion counts completed jobs per model version.
+func (r *JobRepository) CountByModelVersion(_ context.Context) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := map[string]int{}
+	for _, job := range r.records {
+		if job.Status == domain.JobStatusCompleted {
+			out[job.ModelVersionID]++
+		}
+	}
+	return out, nil
+}
+
+type ModelRepository struct {
+	mu      sync.RWMutex
+	records map[string]domain.ModelVersion
+}
+
+func (r *ModelRepository) Create(_ context.Context, model domain.ModelVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[model.ModelVersionID] = model
+	return nil
+}
+
+func (r *ModelRepository) GetByID(_ context.Context, modelVersionID string) (domain.ModelVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	model, ok := r.records[modelVersionID]
+	if !ok {
+		return domain.ModelVersion{}, domain.ErrNotFound
+	}
+	return model, nil
+}
+
+func (r *ModelRepository) Update(_ context.Context, model domain.ModelVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.records[model.ModelVersionID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.records[model.ModelVersionID] = model
+	return nil
+}
+
+func (r *ModelRepository) List(_ context.Context) ([]domain.ModelVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]domain.ModelVersion, 0, len(r.records))
+	for _, model := range r.records {
+		out = append(out, model)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DeployedAt.Before(out[j].DeployedAt) })
+	return out, nil
+}
@@ -7,16 +7,24 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	defaultWorkerPollInterval = 500 * time.Millisecond
+	defaultJobTimeout         = 2 * time.Minute
+)
+
 type Config struct {
 	ServiceID               string
 	HTTPPort                int
 	GRPCPort                int
 	ClippingToolOwnerAPIURL string
 	IdempotencyStorePath    string
+	WorkerPollInterval      time.Duration
+	JobTimeout              time.Duration
 }
 
 type configFile struct {
@@ -31,6 +39,10 @@ type configFile struct {
 	Persistence struct {
 		IdempotencyStorePath string `yaml:"idempotency_store_path"`
 	} `yaml:"persistence"`
+	Clipping struct {
+		WorkerPollMS int `yaml:"worker_poll_ms"`
+		JobTimeoutMS int `yaml:"job_timeout_ms"`
+	} `yaml:"clipping"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -40,6 +52,8 @@ func LoadConfig(path string) (Config, error) {
 		GRPCPort:                9090,
 		ClippingToolOwnerAPIURL: "http://m24-clipping-tool-service:8080",
 		IdempotencyStorePath:    "data/m25-admin-model-deploy-idempotency.json",
+		WorkerPollInterval:      defaultWorkerPollInterval,
+		JobTimeout:              defaultJobTimeout,
 	}
 	if raw, err := os.ReadFile(path); err == nil {
 		var fileCfg configFile
@@ -61,12 +75,24 @@ func LoadConfig(path string) (Config, error) {
 		if value := strings.TrimSpace(fileCfg.Persistence.IdempotencyStorePath); value != "" && !strings.Contains(value, "${") {
 			cfg.IdempotencyStorePath = value
 		}
+		if fileCfg.Clipping.WorkerPollMS > 0 {
+			cfg.WorkerPollInterval = time.Duration(fileCfg.Clipping.WorkerPollMS) * time.Millisecond
+		}
+		if fileCfg.Clipping.JobTimeoutMS > 0 {
+			cfg.JobTimeout = time.Duration(fileCfg.Clipping.JobTimeoutMS) * time.Millisecond
+		}
 	}
 
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
 	cfg.ClippingToolOwnerAPIURL = envOrDefault("M24_CLIPPING_TOOL_OWNER_API_URL", cfg.ClippingToolOwnerAPIURL)
 	cfg.IdempotencyStorePath = envOrDefault("M25_IDEMPOTENCY_STORE_PATH", cfg.IdempotencyStorePath)
+	if ms := envInt("M25_WORKER_POLL_MS", 0); ms > 0 {
+		cfg.WorkerPollInterval = time.Duration(ms) * time.Millisecond
+	}
+	if ms := envInt("M25_JOB_TIMEOUT_MS", 0); ms > 0 {
+		cfg.JobTimeout = time.Duration(ms) * time.Millisecond
+	}
 	return cfg, nil
 }
 
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -316,3 +317,70 @@ func TestAdminDeployReplayPersistsAcrossRuntimeRestart(t *testing.T) {
 		t.Fatalf("expected persisted replay model_version_id to match after restart: first=%s second=%s", firstModel.ModelVersionID, secondModel.ModelVersionID)
 	}
 }
+
+func TestClipJobFlowUsesDeployedModel(t *testing.T) {
+	runtime := newTestRuntime(t)
+	router := runtime.router()
+	do := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	admin := map[string]string{"Authorization": "Bearer admin-1", "X-Actor-Role": "admin", "Idempotency-Key": "idem-deploy-full"}
+	creator := map[string]string{"Authorization": "Bearer creator-1", "Idempotency-Key": "idem-job-1"}
+
+	deployed := do(http.MethodPost, "/v1/admin/models/deploy", `{"model_name":"clip_ranker","version_tag":"v2","model_artifact_key":"s3://models/clip_ranker/v2","canary_percentage":100,"reason":"full rollout","scoring_weights":{"energy":0.5,"speech_density":0.2,"keywords":0.2,"scene_alignment":0.1}}`, admin)
+	if deployed.Code != http.StatusCreated {
+		t.Fatalf("expected deploy created, got=%d body=%s", deployed.Code, deployed.Body.String())
+	}
+	var deployEnv struct {
+		Data deployModelResponse `json:"data"`
+	}
+	if err := json.Unmarshal(deployed.Body.Bytes(), &deployEnv); err != nil {
+		t.Fatalf("decode deploy response: %v", err)
+	}
+	if deployEnv.Data.ModelStatus != "active" || deployEnv.Data.ScoringWeights.Energy != 0.5 {
+		t.Fatalf("unexpected deploy response: %+v", deployEnv.Data)
+	}
+
+	if rr := do(http.MethodPost, "/v1/clips/jobs", `{"source_asset_id":"asset-1","scene_cuts":[10,20]}`, nil); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized without bearer token, got=%d", rr.Code)
+	}
+	created := do(http.MethodPost, "/v1/clips/jobs", `{"source_asset_id":"asset-1","duration_seconds":60,"scene_cuts":[15,30,45],"loudness":[{"at_seconds":0,"lufs":-30},{"at_seconds":35,"lufs":-10},{"at_seconds":60,"lufs":-30}],"transcript":[{"start_seconds":30,"end_seconds":40,"text":"here is the big reveal"}],"keywords":["reveal"],"min_duration_seconds":10,"max_duration_seconds":20}`, creator)
+	if created.Code != http.StatusAccepted {
+		t.Fatalf("expected job accepted, got=%d body=%s", created.Code, created.Body.String())
+	}
+	var createdEnv struct {
+		Data struct {
+			JobID          string `json:"job_id"`
+			Status         string `json:"status"`
+			ModelVersionID string `json:"model_version_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(created.Body.Bytes(), &createdEnv); err != nil {
+		t.Fatalf("decode job response: %v", err)
+	}
+	if createdEnv.Data.Status != "queued" || createdEnv.Data.ModelVersionID != deployEnv.Data.ModelVersionID {
+		t.Fatalf("expected queued job on deployed model, got %+v", createdEnv.Data)
+	}
+
+	if err := runtime.ensureService().ProcessNextJob(context.Background()); err != nil {
+		t.Fatalf("process job: %v", err)
+	}
+	polled := do(http.MethodGet, "/v1/clips/jobs/"+createdEnv.Data.JobID, "", creator)
+	if polled.Code != http.StatusOK || !strings.Contains(polled.Body.String(), `"status":"completed"`) || !strings.Contains(polled.Body.String(), `"matched_keywords":["reveal"]`) {
+		t.Fatalf("expected completed job with keyword match, got=%d body=%s", polled.Code, polled.Body.String())
+	}
+	if rr := do(http.MethodGet, "/v1/clips/jobs/"+createdEnv.Data.JobID, "", map[string]string{"Authorization": "Bearer creator-2"}); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected forbidden for another creator, got=%d", rr.Code)
+	}
+
+	models := do(http.MethodGet, "/v1/admin/models", "", admin)
+	if models.Code != http.StatusOK || !strings.Contains(models.Body.String(), `"jobs_scored":1`) {
+		t.Fatalf("expected model list with scored job, got=%d body=%s", models.Code, models.Body.String())
+	}
+}
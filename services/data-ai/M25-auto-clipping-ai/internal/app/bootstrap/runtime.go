@@ -7,19 +7,34 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	eventadapter "github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/adapters/events"
+	httpadapter "github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/adapters/http"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/application"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
 )
 
 type Runtime struct {
-	config Config
+	config    Config
+	telemetry *observability.Telemetry
 
 	idempotencyStore     *deployIdempotencyStore
 	idempotencyStoreErr  error
 	idempotencyStoreInit sync.Once
+
+	// Jobs and model versions live in memory, so the API process also runs
+	// the clip worker.
+	service     *application.Service
+	worker      *eventadapter.Worker
+	serviceInit sync.Once
 }
 
 type canonicalErrorEnvelope struct {
@@ -39,25 +54,35 @@ type canonicalSuccessEnvelope struct {
 	Data   interface{} `json:"data"`
 }
 
-func NewRuntime(_ context.Context, configPath string) (Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
-		return Runtime{}, err
+		return nil, err
 	}
 	if err := validateConfig(cfg); err != nil {
-		return Runtime{}, err
+		return nil, err
 	}
 	store, err := newDeployIdempotencyStore(cfg.IdempotencyStorePath, deployModelIdempotencyTTL)
 	if err != nil {
-		return Runtime{}, fmt.Errorf("initialize deploy idempotency store: %w", err)
+		return nil, fmt.Errorf("initialize deploy idempotency store: %w", err)
+	}
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, fmt.Errorf("initialize telemetry: %w", err)
 	}
-	return Runtime{config: cfg, idempotencyStore: store}, nil
+	r := &Runtime{config: cfg, telemetry: telemetry, idempotencyStore: store}
+	r.ensureService()
+	return r, nil
 }
 
-func (r Runtime) RunAPI(ctx context.Context) error {
+func (r *Runtime) RunAPI(ctx context.Context) error {
+	defer r.shutdownTelemetry()
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	defer stopWorker()
+	go func() { _ = r.worker.Run(workerCtx) }()
 	server := &http.Server{
 		Addr:              ":" + strconv.Itoa(r.config.HTTPPort),
-		Handler:           r.router(),
+		Handler:           observability.HTTPMiddleware(r.config.ServiceID)(r.router()),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 	errCh := make(chan error, 1)
@@ -81,11 +106,20 @@ func (r Runtime) RunAPI(ctx context.Context) error {
 	}
 }
 
-func (r Runtime) RunWorker(ctx context.Context) error {
-	<-ctx.Done()
+func (r *Runtime) RunWorker(ctx context.Context) error {
+	defer r.shutdownTelemetry()
+	if err := r.worker.Run(ctx); err != nil {
+		return err
+	}
 	return ctx.Err()
 }
 
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = r.telemetry.Shutdown(ctx)
+}
+
 func (r *Runtime) router() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
@@ -93,9 +127,13 @@ func (r *Runtime) router() http.Handler {
 		_ = json.NewEncoder(w).Encode(map[string]string{
 			"status":  "ok",
 			"service": r.config.ServiceID,
-			"mode":    "out_of_mvp",
+			"mode":    "clipping",
 		})
 	})
+	service := r.ensureService()
+	api := httpadapter.NewRouter(httpadapter.NewHandler(service))
+	mux.Handle("/v1/clips/", api)
+	mux.Handle("GET /v1/admin/models", api)
 	mux.HandleFunc("POST /v1/admin/models/deploy", func(w http.ResponseWriter, req *http.Request) {
 		if !isAdminRequest(req) {
 			writeCanonicalError(
@@ -144,6 +182,10 @@ func (r *Runtime) router() http.Handler {
 			writeCanonicalError(w, http.StatusBadRequest, "INVALID_REQUEST", "canary_percentage must be between 0 and 100", nil)
 			return
 		}
+		if payload.ScoringWeights != nil && !payload.ScoringWeights.Valid() {
+			writeCanonicalError(w, http.StatusBadRequest, "INVALID_REQUEST", "scoring_weights must be non-negative and not all zero", nil)
+			return
+		}
 
 		store, err := r.ensureDeployIdempotencyStore()
 		if err != nil {
@@ -166,14 +208,31 @@ func (r *Runtime) router() http.Handler {
 			return
 		}
 
-		deployedAt := time.Now().UTC()
+		model, err := service.DeployModel(req.Context(), application.Actor{
+			SubjectID: bearerSubject(req),
+			Role:      "admin",
+		}, application.DeployModelInput{
+			ModelName:        payload.ModelName,
+			VersionTag:       payload.VersionTag,
+			ArtifactKey:      payload.ModelArtifactKey,
+			CanaryPercentage: payload.CanaryPercentage,
+			Description:      payload.Description,
+			Reason:           payload.Reason,
+			Weights:          payload.ScoringWeights,
+		})
+		if err != nil {
+			writeCanonicalError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "model registry unavailable", nil)
+			return
+		}
 		response := deployModelResponse{
-			ModelVersionID:   fmt.Sprintf("m25_model_%d", deployedAt.UnixNano()),
+			ModelVersionID:   model.ModelVersionID,
 			DeploymentStatus: fmt.Sprintf("canary_%dpct", payload.CanaryPercentage),
-			DeployedAt:       deployedAt.Format(time.RFC3339),
+			ModelStatus:      string(model.Status),
+			ScoringWeights:   model.Weights,
+			DeployedAt:       model.DeployedAt.Format(time.RFC3339),
 			Message:          "model deployment accepted",
 		}
-		if err := store.store(idempotencyKey, requestHash, response, deployedAt); err != nil {
+		if err := store.store(idempotencyKey, requestHash, response, model.DeployedAt); err != nil {
 			if errors.Is(err, errIdempotencyCollision) {
 				writeCanonicalError(w, http.StatusConflict, "IDEMPOTENCY_COLLISION", "idempotency key reused with different payload", nil)
 				return
@@ -190,7 +249,7 @@ func (r *Runtime) router() http.Handler {
 			Status: "error",
 			Error: canonicalErrorBody{
 				Code:    "SERVICE_OUT_OF_MVP",
-				Message: "M25-Auto-Clipping-AI business APIs are disabled in MVP scope",
+				Message: "endpoint is outside M25-Auto-Clipping-AI MVP scope",
 				Details: map[string]interface{}{
 					"dependency_owner_api": r.config.ClippingToolOwnerAPIURL,
 				},
@@ -208,24 +267,35 @@ type deployModelRequest struct {
 	CanaryPercentage int    `json:"canary_percentage"`
 	Description      string `json:"description"`
 	Reason           string `json:"reason"`
+	// ScoringWeights default to the builtin model's weights.
+	ScoringWeights *domain.ScoringWeights `json:"scoring_weights,omitempty"`
 }
 
 type deployModelResponse struct {
-	ModelVersionID   string `json:"model_version_id"`
-	DeploymentStatus string `json:"deployment_status"`
-	DeployedAt       string `json:"deployed_at"`
-	Message          string `json:"message"`
+	ModelVersionID   string                `json:"model_version_id"`
+	DeploymentStatus string                `json:"deployment_status"`
+	ModelStatus      string                `json:"model_status,omitempty"`
+	ScoringWeights   domain.ScoringWeights `json:"scoring_weights"`
+	DeployedAt       string                `json:"deployed_at"`
+	Message          string                `json:"message"`
 }
 
 func isAdminRequest(req *http.Request) bool {
-	authHeader := strings.TrimSpace(req.Header.Get("Authorization"))
-	parts := strings.SplitN(authHeader, " ", 2)
-	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+	if bearerSubject(req) == "" {
 		return false
 	}
 	return strings.EqualFold(strings.TrimSpace(req.Header.Get("X-Actor-Role")), "admin")
 }
 
+func bearerSubject(req *http.Request) string {
+	authHeader := strings.TrimSpace(req.Header.Get("Authorization"))
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
 func writeCanonicalSuccess(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -265,6 +335,30 @@ func (r *Runtime) ensureDeployIdempotencyStore() (*deployIdempotencyStore, error
 	return r.idempotencyStore, nil
 }
 
-func (r Runtime) String() string {
+// ensureService builds the clipping service on first use, so runtimes
+// assembled by hand in tests get one too.
+func (r *Runtime) ensureService() *application.Service {
+	r.serviceInit.Do(func() {
+		if r.service != nil {
+			return
+		}
+		repos := postgres.NewRepositories()
+		r.service = application.NewService(application.Dependencies{
+			Config:      application.Config{ServiceName: r.config.ServiceID, JobTimeout: r.config.JobTimeout},
+			Idempotency: repos.Idempotency,
+			Jobs:        repos.Jobs,
+			Models:      repos.Models,
+			Queue:       eventadapter.NewMemoryJobQueue(),
+		})
+		poll := r.config.WorkerPollInterval
+		if poll <= 0 {
+			poll = defaultWorkerPollInterval
+		}
+		r.worker = eventadapter.NewWorker(slog.Default(), r.service, poll)
+	})
+	return r.service
+}
+
+func (r *Runtime) String() string {
 	return fmt.Sprintf("%s@:%d", r.config.ServiceID, r.config.HTTPPort)
 }
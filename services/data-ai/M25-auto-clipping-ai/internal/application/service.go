@@ -0,0 +1,308 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+)
+
+// CreateClipJob validates the source signals and options, pins the model
+// version that will score them and queues the job.
+func (s *Service) CreateClipJob(ctx context.Context, actor Actor, input CreateClipJobInput) (domain.ClipJob, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ClipJob{}, domain.ErrUnauthorized
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.ClipJob{}, domain.ErrIdempotencyRequired
+	}
+	input.SourceAssetID = strings.TrimSpace(input.SourceAssetID)
+	if input.SourceAssetID == "" {
+		return domain.ClipJob{}, domain.ErrInvalidInput
+	}
+	signals, err := domain.NormalizeSignals(input.Signals)
+	if err != nil {
+		return domain.ClipJob{}, err
+	}
+	options, err := domain.NormalizeOptions(input.Options)
+	if err != nil {
+		return domain.ClipJob{}, err
+	}
+	if options.MinSeconds > signals.DurationSeconds {
+		return domain.ClipJob{}, domain.ErrNoCandidates
+	}
+
+	requestHash := hashPayload(input)
+	now := s.nowFn()
+	existing, err := s.idempotency.Get(ctx, actor.IdempotencyKey, now)
+	if err != nil {
+		return domain.ClipJob{}, err
+	}
+	if existing != nil {
+		if existing.RequestHash != requestHash {
+			return domain.ClipJob{}, domain.ErrIdempotencyConflict
+		}
+		if len(existing.ResponseBody) == 0 {
+			return domain.ClipJob{}, domain.ErrIdempotencyInFlight
+		}
+		var cached domain.ClipJob
+		if err := json.Unmarshal(existing.ResponseBody, &cached); err != nil {
+			return domain.ClipJob{}, err
+		}
+		return cached, nil
+	}
+	if err := s.idempotency.Reserve(ctx, actor.IdempotencyKey, requestHash, now.Add(s.cfg.IdempotencyTTL)); err != nil {
+		return domain.ClipJob{}, err
+	}
+	completed := false
+	defer func() {
+		if !completed {
+			_ = s.idempotency.Release(ctx, actor.IdempotencyKey)
+		}
+	}()
+
+	jobID := "clipjob_" + hashPayload([]string{actor.IdempotencyKey, now.Format(time.RFC3339Nano)})[:20]
+	model, err := s.pickModel(ctx, jobID)
+	if err != nil {
+		return domain.ClipJob{}, err
+	}
+	job := domain.ClipJob{
+		JobID:          jobID,
+		SourceAssetID:  input.SourceAssetID,
+		RequestedBy:    strings.TrimSpace(actor.SubjectID),
+		Status:         domain.JobStatusQueued,
+		ModelVersionID: model.ModelVersionID,
+		Signals:        signals,
+		Options:        options,
+		CreatedAt:      now,
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return domain.ClipJob{}, err
+	}
+	if err := s.queue.Enqueue(ctx, job.JobID); err != nil {
+		return domain.ClipJob{}, err
+	}
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return domain.ClipJob{}, err
+	}
+	if err := s.idempotency.Complete(ctx, actor.IdempotencyKey, 202, encoded, now); err != nil {
+		return domain.ClipJob{}, err
+	}
+	completed = true
+	return job, nil
+}
+
+// GetClipJob returns a job to the user who created it, or to an admin.
+func (s *Service) GetClipJob(ctx context.Context, actor Actor, jobID string) (domain.ClipJob, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ClipJob{}, domain.ErrUnauthorized
+	}
+	job, err := s.jobs.GetByID(ctx, strings.TrimSpace(jobID))
+	if err != nil {
+		return domain.ClipJob{}, err
+	}
+	if job.RequestedBy != strings.TrimSpace(actor.SubjectID) && !isAdmin(actor) {
+		return domain.ClipJob{}, domain.ErrForbidden
+	}
+	return job, nil
+}
+
+// ProcessNextJob scores the next queued job. It returns io.EOF when the
+// queue is empty. A job that yields no candidates or runs past
+// Config.JobTimeout is marked failed rather than returned as an error.
+func (s *Service) ProcessNextJob(ctx context.Context) error {
+	jobID, err := s.queue.Dequeue(ctx)
+	if err != nil {
+		return err
+	}
+	job, err := s.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != domain.JobStatusQueued {
+		return nil
+	}
+	started := s.nowFn()
+	job.Status = domain.JobStatusRunning
+	job.StartedAt = &started
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return err
+	}
+
+	scoreCtx, cancel := context.WithTimeout(ctx, s.cfg.JobTimeout)
+	candidates, err := s.scoreJob(scoreCtx, job)
+	cancel()
+	completed := s.nowFn()
+	job.CompletedAt = &completed
+	switch {
+	case err == nil:
+		job.Status = domain.JobStatusCompleted
+		job.Candidates = candidates
+	case errors.Is(err, domain.ErrNoCandidates):
+		job.Status = domain.JobStatusFailed
+		job.ErrorCode = "no_candidates"
+	case errors.Is(err, domain.ErrNotFound):
+		job.Status = domain.JobStatusFailed
+		job.ErrorCode = "model_unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		job.Status = domain.JobStatusFailed
+		job.ErrorCode = "timed_out"
+	default:
+		job.Status = domain.JobStatusFailed
+		job.ErrorCode = "scoring_failed"
+	}
+	return s.jobs.Update(ctx, job)
+}
+
+func (s *Service) scoreJob(ctx context.Context, job domain.ClipJob) ([]domain.ClipCandidate, error) {
+	model := domain.BuiltinModel
+	if job.ModelVersionID != domain.BuiltinModel.ModelVersionID {
+		var err error
+		if model, err = s.models.GetByID(ctx, job.ModelVersionID); err != nil {
+			return nil, err
+		}
+	}
+	return domain.GenerateCandidates(ctx, job.Signals, job.Options, model.Weights)
+}
+
+// DeployModel records a new model version. A 100% rollout makes it the
+// active version and retires the previous one; anything less makes it the
+// canary, replacing any earlier canary, and it scores that share of new
+// jobs.
+func (s *Service) DeployModel(ctx context.Context, actor Actor, input DeployModelInput) (domain.ModelVersion, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ModelVersion{}, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return domain.ModelVersion{}, domain.ErrForbidden
+	}
+	weights := domain.DefaultWeights
+	if input.Weights != nil {
+		weights = *input.Weights
+	}
+	input.ModelName = strings.TrimSpace(input.ModelName)
+	input.VersionTag = strings.TrimSpace(input.VersionTag)
+	input.ArtifactKey = strings.TrimSpace(input.ArtifactKey)
+	input.Reason = strings.TrimSpace(input.Reason)
+	if input.ModelName == "" || input.VersionTag == "" || input.ArtifactKey == "" || input.Reason == "" {
+		return domain.ModelVersion{}, domain.ErrInvalidInput
+	}
+	if input.CanaryPercentage < 0 || input.CanaryPercentage > 100 || !weights.Valid() {
+		return domain.ModelVersion{}, domain.ErrInvalidInput
+	}
+
+	now := s.nowFn()
+	model := domain.ModelVersion{
+		ModelVersionID:   fmt.Sprintf("m25_model_%d", now.UnixNano()),
+		ModelName:        input.ModelName,
+		VersionTag:       input.VersionTag,
+		ArtifactKey:      input.ArtifactKey,
+		Weights:          weights,
+		CanaryPercentage: input.CanaryPercentage,
+		Status:           domain.ModelStatusCanary,
+		Description:      strings.TrimSpace(input.Description),
+		Reason:           input.Reason,
+		DeployedBy:       strings.TrimSpace(actor.SubjectID),
+		DeployedAt:       now,
+	}
+	if model.CanaryPercentage == 100 {
+		model.Status = domain.ModelStatusActive
+	}
+	existing, err := s.models.List(ctx)
+	if err != nil {
+		return domain.ModelVersion{}, err
+	}
+	for _, prev := range existing {
+		// A new canary replaces the old one; a full rollout replaces both.
+		if prev.Status == domain.ModelStatusCanary || (prev.Status == domain.ModelStatusActive && model.Status == domain.ModelStatusActive) {
+			prev.Status = domain.ModelStatusRetired
+			if err := s.models.Update(ctx, prev); err != nil {
+				return domain.ModelVersion{}, err
+			}
+		}
+	}
+	if err := s.models.Create(ctx, model); err != nil {
+		return domain.ModelVersion{}, err
+	}
+	return model, nil
+}
+
+// ListModels returns every deployed version, oldest first, after the
+// builtin model, with how many jobs each has scored.
+func (s *Service) ListModels(ctx context.Context, actor Actor) ([]ModelSummary, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return nil, domain.ErrForbidden
+	}
+	models, err := s.models.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.jobs.CountByModelVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	builtin := domain.BuiltinModel
+	if _, active := findModel(models, domain.ModelStatusActive); active {
+		builtin.Status = domain.ModelStatusRetired
+	}
+	out := []ModelSummary{{ModelVersion: builtin, JobsScored: counts[builtin.ModelVersionID]}}
+	for _, model := range models {
+		out = append(out, ModelSummary{ModelVersion: model, JobsScored: counts[model.ModelVersionID]})
+	}
+	return out, nil
+}
+
+// pickModel routes a job to the canary for the canary's share of job IDs
+// and to the active version (or the builtin model) otherwise.
+func (s *Service) pickModel(ctx context.Context, jobID string) (domain.ModelVersion, error) {
+	models, err := s.models.List(ctx)
+	if err != nil {
+		return domain.ModelVersion{}, err
+	}
+	if canary, ok := findModel(models, domain.ModelStatusCanary); ok && bucket(jobID) < canary.CanaryPercentage {
+		return canary, nil
+	}
+	if active, ok := findModel(models, domain.ModelStatusActive); ok {
+		return active, nil
+	}
+	return domain.BuiltinModel, nil
+}
+
+func findModel(models []domain.ModelVersion, status domain.ModelStatus) (domain.ModelVersion, bool) {
+	for i := len(models) - 1; i >= 0; i-- {
+		if models[i].Status == status {
+			return models[i], true
+		}
+	}
+	return domain.ModelVersion{}, false
+}
+
+// bucket maps a job ID to a stable value in [0, 100).
+func bucket(jobID string) int {
+	sum := sha256.Sum256([]byte(jobID))
+	return int(binary.BigEndian.Uint32(sum[:4]) % 100)
+}
+
+func isAdmin(actor Actor) bool {
+	return strings.EqualFold(strings.TrimSpace(actor.Role), "admin")
+}
+
+func hashPayload(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package application
+
+import (
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/ports"
+)
+
+type Config struct {
+	ServiceName    string
+	IdempotencyTTL time.Duration
+	// JobTimeout bounds how long one job may spend scoring.
+	JobTimeout time.Duration
+}
+
+type Actor struct {
+	SubjectID      string
+	Role           string
+	RequestID      string
+	IdempotencyKey string
+}
+
+type CreateClipJobInput struct {
+	SourceAssetID string
+	Signals       domain.Signals
+	Options       domain.ClipOptions
+}
+
+type DeployModelInput struct {
+	ModelName        string
+	VersionTag       string
+	ArtifactKey      string
+	CanaryPercentage int
+	Description      string
+	Reason           string
+	// Weights default to domain.DefaultWeights when nil.
+	Weights *domain.ScoringWeights
+}
+
+// ModelSummary is a model version with the number of jobs it has scored.
+type ModelSummary struct {
+	domain.ModelVersion
+	JobsScored int `json:"jobs_scored"`
+}
+
+type Service struct {
+	cfg         Config
+	idempotency ports.IdempotencyRepository
+	jobs        ports.JobRepository
+	models      ports.ModelRepository
+	queue       ports.JobQueue
+	nowFn       func() time.Time
+}
+
+type Dependencies struct {
+	Config Config
+
+	Idempotency ports.IdempotencyRepository
+	Jobs        ports.JobRepository
+	Models      ports.ModelRepository
+	Queue       ports.JobQueue
+}
+
+func NewService(deps Dependencies) *Service {
+	cfg := deps.Config
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "M25-Auto-Clipping-AI"
+	}
+	if cfg.IdempotencyTTL <= 0 {
+		cfg.IdempotencyTTL = 7 * 24 * time.Hour
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = 2 * time.Minute
+	}
+	return &Service{
+		cfg:         cfg,
+		idempotency: deps.Idempotency,
+		jobs:        deps.Jobs,
+		models:      deps.Models,
+		queue:       deps.Queue,
+		nowFn:       func() time.Time { return time.Now().UTC() },
+	}
+}
@@ -0,0 +1,36 @@
+package contracts
+
+import "github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+
+type SuccessResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
+
+type ErrorPayload struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+type ErrorResponse struct {
+	Status    string       `json:"status"`
+	Error     ErrorPayload `json:"error"`
+	Timestamp string       `json:"timestamp"`
+}
+
+// CreateClipJobRequest carries the source analyses inline: the loudness
+// envelope, scene-cut timestamps and time-coded transcript.
+type CreateClipJobRequest struct {
+	SourceAssetID      string                     `json:"source_asset_id"`
+	DurationSeconds    float64                    `json:"duration_seconds"`
+	Loudness           []domain.LoudnessSample    `json:"loudness"`
+	SceneCuts          []float64                  `json:"scene_cuts"`
+	Transcript         []domain.TranscriptSegment `json:"transcript"`
+	Keywords           []string                   `json:"keywords"`
+	MinDurationSeconds float64                    `json:"min_duration_seconds"`
+	MaxDurationSeconds float64                    `json:"max_duration_seconds"`
+	Targets            []domain.PlatformTarget    `json:"targets"`
+	MaxCandidates      int                        `json:"max_candidates"`
+}
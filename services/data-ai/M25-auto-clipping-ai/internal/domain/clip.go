@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// LoudnessSample is one point of a source's audio loudness envelope, in
+// LUFS (or any dB scale, as long as a source uses one scale throughout).
+type LoudnessSample struct {
+	AtSeconds float64 `json:"at_seconds"`
+	LUFS      float64 `json:"lufs"`
+}
+
+// TranscriptSegment is a time-coded piece of the source's transcript,
+// usually one sentence.
+type TranscriptSegment struct {
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+	Text         string  `json:"text"`
+}
+
+// Signals are the analyses of a source asset the clipping engine works
+// from. Any of them may be missing, but not all.
+type Signals struct {
+	DurationSeconds float64             `json:"duration_seconds"`
+	Loudness        []LoudnessSample    `json:"loudness"`
+	SceneCuts       []float64           `json:"scene_cuts"`
+	Transcript      []TranscriptSegment `json:"transcript"`
+}
+
+const (
+	MaxSourceSeconds  = 6 * 60 * 60
+	MaxSignalPoints   = 200000
+	MaxClipSeconds    = 600
+	MaxCandidateLimit = 20
+
+	DefaultMinClipSeconds = 15
+	DefaultMaxClipSeconds = 60
+	DefaultCandidateLimit = 5
+)
+
+// MaxCutsPerMinute and MaxSegmentsPerMinute bound how dense scene cuts and
+// transcript segments may be, per started minute of source.
+const (
+	MaxCutsPerMinute     = 60
+	MaxSegmentsPerMinute = 60
+)
+
+// NormalizeSignals sorts the signals, drops points outside the source and
+// fills in a missing duration from the latest signal.
+func NormalizeSignals(in Signals) (Signals, error) {
+	if len(in.Loudness)+len(in.SceneCuts)+len(in.Transcript) == 0 {
+		return Signals{}, ErrNoSignals
+	}
+	if len(in.Loudness) > MaxSignalPoints || len(in.SceneCuts) > MaxSignalPoints || len(in.Transcript) > MaxSignalPoints {
+		return Signals{}, ErrInvalidInput
+	}
+	out := Signals{DurationSeconds: in.DurationSeconds}
+	if out.DurationSeconds < 0 || math.IsNaN(out.DurationSeconds) {
+		return Signals{}, ErrInvalidInput
+	}
+	if out.DurationSeconds == 0 {
+		for _, s := range in.Loudness {
+			out.DurationSeconds = math.Max(out.DurationSeconds, s.AtSeconds)
+		}
+		for _, cut := range in.SceneCuts {
+			out.DurationSeconds = math.Max(out.DurationSeconds, cut)
+		}
+		for _, seg := range in.Transcript {
+			out.DurationSeconds = math.Max(out.DurationSeconds, seg.EndSeconds)
+		}
+	}
+	if out.DurationSeconds <= 0 || out.DurationSeconds > MaxSourceSeconds {
+		return Signals{}, ErrInvalidInput
+	}
+	minutes := int(math.Ceil(out.DurationSeconds / 60))
+	if len(in.SceneCuts) > MaxCutsPerMinute*minutes || len(in.Transcript) > MaxSegmentsPerMinute*minutes {
+		return Signals{}, ErrInvalidInput
+	}
+	inside := func(t float64) bool { return t >= 0 && t <= out.DurationSeconds && !math.IsNaN(t) }
+	for _, s := range in.Loudness {
+		if inside(s.AtSeconds) && !math.IsNaN(s.LUFS) && !math.IsInf(s.LUFS, 0) {
+			out.Loudness = append(out.Loudness, s)
+		}
+	}
+	sort.SliceStable(out.Loudness, func(i, j int) bool { return out.Loudness[i].AtSeconds < out.Loudness[j].AtSeconds })
+	for _, cut := range in.SceneCuts {
+		if inside(cut) {
+			out.SceneCuts = append(out.SceneCuts, cut)
+		}
+	}
+	sort.Float64s(out.SceneCuts)
+	for _, seg := range in.Transcript {
+		if seg.EndSeconds < seg.StartSeconds || !inside(seg.StartSeconds) {
+			return Signals{}, ErrInvalidInput
+		}
+		seg.EndSeconds = math.Min(seg.EndSeconds, out.DurationSeconds)
+		seg.Text = strings.TrimSpace(seg.Text)
+		out.Transcript = append(out.Transcript, seg)
+	}
+	sort.SliceStable(out.Transcript, func(i, j int) bool { return out.Transcript[i].StartSeconds < out.Transcript[j].StartSeconds })
+	return out, nil
+}
+
+// PlatformTarget is a destination a clip is cut for.
+type PlatformTarget struct {
+	Platform    string `json:"platform"`
+	AspectRatio string `json:"aspect_ratio"`
+}
+
+// PlatformSpec is what a platform accepts for short clips. A zero
+// MaxSeconds means no limit beyond MaxClipSeconds.
+type PlatformSpec struct {
+	AspectRatio string
+	MinSeconds  float64
+	MaxSeconds  float64
+}
+
+var Platforms = map[string]PlatformSpec{
+	"tiktok":          {AspectRatio: "9:16", MinSeconds: 3, MaxSeconds: 600},
+	"youtube_shorts":  {AspectRatio: "9:16", MinSeconds: 1, MaxSeconds: 60},
+	"instagram_reels": {AspectRatio: "9:16", MinSeconds: 3, MaxSeconds: 90},
+	"x":               {AspectRatio: "16:9", MinSeconds: 1, MaxSeconds: 140},
+	"youtube":         {AspectRatio: "16:9", MinSeconds: 1},
+}
+
+var aspectRatios = map[string]bool{"9:16": true, "1:1": true, "4:5": true, "16:9": true}
+
+// ClipOptions control how candidates are cut and chosen.
+type ClipOptions struct {
+	MinSeconds    float64          `json:"min_duration_seconds"`
+	MaxSeconds    float64          `json:"max_duration_seconds"`
+	Keywords      []string         `json:"keywords,omitempty"`
+	Targets       []PlatformTarget `json:"targets"`
+	MaxCandidates int              `json:"max_candidates"`
+}
+
+// NormalizeOptions applies defaults and validates the options against the
+// targets: the duration range is narrowed to what at least one target
+// accepts, and each target gets its platform's aspect ratio unless another
+// is requested.
+func NormalizeOptions(in ClipOptions) (ClipOptions, error) {
+	out := ClipOptions{MinSeconds: in.MinSeconds, MaxSeconds: in.MaxSeconds, MaxCandidates: in.MaxCandidates}
+	if out.MinSeconds == 0 {
+		out.MinSeconds = DefaultMinClipSeconds
+	}
+	if out.MaxSeconds == 0 {
+		out.MaxSeconds = math.Max(DefaultMaxClipSeconds, out.MinSeconds)
+	}
+	if out.MaxCandidates == 0 {
+		out.MaxCandidates = DefaultCandidateLimit
+	}
+	if out.MinSeconds < 1 || out.MaxSeconds > MaxClipSeconds || out.MinSeconds > out.MaxSeconds || out.MaxCandidates < 1 || out.MaxCandidates > MaxCandidateLimit {
+		return ClipOptions{}, ErrInvalidInput
+	}
+	targets := in.Targets
+	if len(targets) == 0 {
+		targets = []PlatformTarget{{Platform: "tiktok"}}
+	}
+	lowest, highest := math.Inf(1), 0.0
+	seen := map[PlatformTarget]bool{}
+	for _, t := range targets {
+		t.Platform = strings.ToLower(strings.TrimSpace(t.Platform))
+		spec, ok := Platforms[t.Platform]
+		if !ok {
+			return ClipOptions{}, ErrInvalidInput
+		}
+		t.AspectRatio = strings.TrimSpace(t.AspectRatio)
+		if t.AspectRatio == "" {
+			t.AspectRatio = spec.AspectRatio
+		}
+		if !aspectRatios[t.AspectRatio] {
+			return ClipOptions{}, ErrInvalidInput
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out.Targets = append(out.Targets, t)
+		lowest = math.Min(lowest, spec.MinSeconds)
+		highest = math.Max(highest, spec.maxSeconds())
+	}
+	out.MinSeconds = math.Max(out.MinSeconds, lowest)
+	out.MaxSeconds = math.Min(out.MaxSeconds, highest)
+	if out.MinSeconds > out.MaxSeconds {
+		return ClipOptions{}, ErrInvalidInput
+	}
+	for _, kw := range in.Keywords {
+		if tokens := tokenize(kw); len(tokens) > 0 {
+			out.Keywords = append(out.Keywords, strings.Join(tokens, " "))
+		}
+	}
+	if len(out.Keywords) > 100 {
+		return ClipOptions{}, ErrInvalidInput
+	}
+	return out, nil
+}
+
+func (p PlatformSpec) maxSeconds() float64 {
+	if p.MaxSeconds <= 0 {
+		return MaxClipSeconds
+	}
+	return p.MaxSeconds
+}
+
+// fits reports whether a clip of the given length can go to the target.
+func (t PlatformTarget) fits(seconds float64) bool {
+	spec := Platforms[t.Platform]
+	return seconds >= spec.MinSeconds && seconds <= spec.maxSeconds()
+}
+
+// ScoreComponents are a candidate's normalised (0-1) scores per signal.
+type ScoreComponents struct {
+	Energy         float64 `json:"energy"`
+	SpeechDensity  float64 `json:"speech_density"`
+	Keywords       float64 `json:"keywords"`
+	SceneAlignment float64 `json:"scene_alignment"`
+}
+
+type ClipCandidate struct {
+	Rank            int              `json:"rank"`
+	StartSeconds    float64          `json:"start_seconds"`
+	EndSeconds      float64          `json:"end_seconds"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Score           float64          `json:"score"`
+	Components      ScoreComponents  `json:"components"`
+	MatchedKeywords []string         `json:"matched_keywords,omitempty"`
+	Transcript      string           `json:"transcript,omitempty"`
+	Targets         []PlatformTarget `json:"targets"`
+}
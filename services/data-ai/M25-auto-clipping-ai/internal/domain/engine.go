@@ -0,0 +1,442 @@
+package domain
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// binSeconds is the resolution of the loudness timeline.
+	binSeconds = 0.5
+	// alignTolerance is how close a clip edge must be to a cut or sentence
+	// boundary to count as aligned with it.
+	alignTolerance = 0.5
+	// gridStepSeconds spaces the extra start points tried between
+	// boundaries, so long stretches without cuts still get candidates.
+	gridStepSeconds = 2.0
+	// fastSpeechWPS is the words-per-second rate that scores full speech
+	// density.
+	fastSpeechWPS = 3.0
+	// maxOverlap is the largest share of the shorter clip two returned
+	// candidates may have in common.
+	maxOverlap      = 0.5
+	maxExcerptRunes = 280
+	// maxScoredWindows caps the windows scored per job; denser sources are
+	// sampled evenly across their length.
+	maxScoredWindows = 50000
+	// poolPerCandidate is how many of the best windows are kept per
+	// requested candidate for the overlap filter to choose from.
+	poolPerCandidate = 256
+)
+
+// GenerateCandidates scores every window between min and max length that
+// starts and ends on a scene cut, sentence boundary or grid point, then
+// returns the best-scoring windows that do not overlap each other much,
+// ranked from 1. Windows are scored as they are listed and only the best
+// are kept, so memory does not grow with the number of windows. It stops
+// with ctx's error once ctx is done.
+//
+// Each window is scored on:
+//   - energy: the loudest quarter of the window, relative to the source's
+//     median and 95th percentile loudness;
+//   - speech density: transcript words per second against a fast talker;
+//   - keywords: 1 - 0.5^hits for keyword hits inside the window;
+//   - scene alignment: how well both edges sit on a scene cut (1) or a
+//     sentence boundary (0.5).
+func GenerateCandidates(ctx context.Context, sig Signals, opts ClipOptions, weights ScoringWeights) ([]ClipCandidate, error) {
+	if !weights.Valid() {
+		return nil, ErrInvalidInput
+	}
+	tl := newTimeline(sig, opts.Keywords)
+	pool := &candidatePool{limit: opts.MaxCandidates * poolPerCandidate}
+	listed := 0
+	var cancelled error
+	tl.eachWindow(opts.MinSeconds, opts.MaxSeconds, func(start, end float64) bool {
+		if listed++; listed%1024 == 0 {
+			if cancelled = ctx.Err(); cancelled != nil {
+				return false
+			}
+		}
+		c := tl.score(start, end)
+		c.Score = round(weights.score(c.Components), 4)
+		for _, t := range opts.Targets {
+			if t.fits(c.DurationSeconds) {
+				c.Targets = append(c.Targets, t)
+			}
+		}
+		if len(c.Targets) > 0 {
+			pool.offer(c)
+		}
+		return true
+	})
+	if cancelled != nil {
+		return nil, cancelled
+	}
+	scored := pool.candidates
+	sort.Slice(scored, func(i, j int) bool { return better(scored[i], scored[j]) })
+	out := []ClipCandidate{}
+	for _, c := range scored {
+		if len(out) == opts.MaxCandidates {
+			break
+		}
+		if overlapsAny(c, out) {
+			continue
+		}
+		c.Rank = len(out) + 1
+		c.MatchedKeywords = tl.keywordsIn(c.StartSeconds, c.EndSeconds)
+		c.Transcript = tl.excerpt(c.StartSeconds, c.EndSeconds)
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		return nil, ErrNoCandidates
+	}
+	return out, nil
+}
+
+// better orders candidates by score, then earlier start, then shorter
+// length.
+func better(a, b ClipCandidate) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.StartSeconds != b.StartSeconds {
+		return a.StartSeconds < b.StartSeconds
+	}
+	return a.DurationSeconds < b.DurationSeconds
+}
+
+// candidatePool keeps the best limit candidates offered to it, with the
+// worst of them at the root.
+type candidatePool struct {
+	limit      int
+	candidates []ClipCandidate
+}
+
+func (p *candidatePool) offer(c ClipCandidate) {
+	if len(p.candidates) < p.limit {
+		heap.Push(p, c)
+		return
+	}
+	if better(c, p.candidates[0]) {
+		p.candidates[0] = c
+		heap.Fix(p, 0)
+	}
+}
+
+func (p *candidatePool) Len() int { return len(p.candidates) }
+
+func (p *candidatePool) Less(i, j int) bool { return better(p.candidates[j], p.candidates[i]) }
+
+func (p *candidatePool) Swap(i, j int) {
+	p.candidates[i], p.candidates[j] = p.candidates[j], p.candidates[i]
+}
+
+func (p *candidatePool) Push(x any) { p.candidates = append(p.candidates, x.(ClipCandidate)) }
+
+func (p *candidatePool) Pop() any {
+	last := p.candidates[len(p.candidates)-1]
+	p.candidates = p.candidates[:len(p.candidates)-1]
+	return last
+}
+
+type keywordHit struct {
+	at      float64
+	keyword string
+}
+
+type timeline struct {
+	duration  float64
+	energy    []float64
+	words     []float64
+	hits      []keywordHit
+	cuts      []float64
+	sentences []float64
+	segments  []TranscriptSegment
+}
+
+func newTimeline(sig Signals, keywords []string) *timeline {
+	tl := &timeline{duration: sig.DurationSeconds, cuts: sig.SceneCuts, segments: sig.Transcript}
+	tl.energy = energyBins(sig.Loudness, sig.DurationSeconds)
+
+	phrases := make([][]string, 0, len(keywords))
+	for _, kw := range keywords {
+		phrases = append(phrases, strings.Fields(kw))
+	}
+	for _, seg := range sig.Transcript {
+		tokens := tokenize(seg.Text)
+		tl.sentences = append(tl.sentences, seg.StartSeconds, seg.EndSeconds)
+		if len(tokens) == 0 {
+			continue
+		}
+		// Words are spread evenly over their segment.
+		step := (seg.EndSeconds - seg.StartSeconds) / float64(len(tokens))
+		at := func(i int) float64 { return seg.StartSeconds + (float64(i)+0.5)*step }
+		for i := range tokens {
+			tl.words = append(tl.words, at(i))
+			for k, phrase := range phrases {
+				if hasPhraseAt(tokens, i, phrase) {
+					tl.hits = append(tl.hits, keywordHit{at: at(i), keyword: keywords[k]})
+				}
+			}
+		}
+	}
+	sort.Float64s(tl.words)
+	sort.Float64s(tl.sentences)
+	sort.SliceStable(tl.hits, func(i, j int) bool { return tl.hits[i].at < tl.hits[j].at })
+	return tl
+}
+
+// energyBins interpolates the loudness envelope onto fixed bins and maps
+// it to 0 at the median and 1 at the 95th percentile, so only peaks above
+// the source's usual level score.
+func energyBins(samples []LoudnessSample, duration float64) []float64 {
+	n := int(math.Ceil(duration / binSeconds))
+	bins := make([]float64, n)
+	if len(samples) == 0 || n == 0 {
+		return bins
+	}
+	j := 0
+	for i := range bins {
+		t := (float64(i) + 0.5) * binSeconds
+		for j+1 < len(samples) && samples[j+1].AtSeconds <= t {
+			j++
+		}
+		switch {
+		case t <= samples[0].AtSeconds:
+			bins[i] = samples[0].LUFS
+		case j+1 >= len(samples):
+			bins[i] = samples[j].LUFS
+		default:
+			a, b := samples[j], samples[j+1]
+			span := b.AtSeconds - a.AtSeconds
+			if span <= 0 {
+				bins[i] = b.LUFS
+				continue
+			}
+			bins[i] = a.LUFS + (b.LUFS-a.LUFS)*(t-a.AtSeconds)/span
+		}
+	}
+	sorted := append([]float64(nil), bins...)
+	sort.Float64s(sorted)
+	median, peak := percentile(sorted, 0.5), percentile(sorted, 0.95)
+	for i, v := range bins {
+		if peak-median < 1e-6 {
+			bins[i] = 0
+			continue
+		}
+		bins[i] = clamp((v-median)/(peak-median), 0, 1)
+	}
+	return bins
+}
+
+// eachWindow calls fn with each [start, end) pair to score, in order of
+// start, until fn returns false. When there are more than
+// maxScoredWindows pairs, every nth is used so the sample still spans the
+// whole source.
+func (tl *timeline) eachWindow(minSeconds, maxSeconds float64, fn func(start, end float64) bool) {
+	starts := append([]float64{0}, tl.cuts...)
+	starts = append(starts, tl.sentences...)
+	for t := gridStepSeconds; t < tl.duration; t += gridStepSeconds {
+		starts = append(starts, t)
+	}
+	ends := append([]float64{tl.duration}, tl.cuts...)
+	ends = append(ends, tl.sentences...)
+	starts, ends = uniqueTimes(starts), uniqueTimes(ends)
+
+	total := 0
+	for _, s := range starts {
+		if s+minSeconds > tl.duration+1e-9 {
+			break
+		}
+		total += 2 + countIn(ends, s+minSeconds, s+maxSeconds+1e-9)
+	}
+	stride := max(1, (total+maxScoredWindows-1)/maxScoredWindows)
+
+	listed := 0
+	for _, s := range starts {
+		if s+minSeconds > tl.duration+1e-9 {
+			break
+		}
+		// Starts, and ends, are at least 0.01s apart, so a window can only
+		// repeat when a boundary falls exactly min or max seconds in.
+		var fixed []float64
+		emit := func(e float64) bool {
+			e = math.Min(e, tl.duration)
+			start, end := round(s, 2), round(e, 2)
+			if d := end - start; d < minSeconds-1e-9 || d > maxSeconds+1e-9 || slices.Contains(fixed, end) {
+				return true
+			}
+			if listed++; (listed-1)%stride != 0 {
+				return true
+			}
+			return fn(start, end)
+		}
+		if !emit(s + minSeconds) {
+			return
+		}
+		fixed = append(fixed[:0], round(math.Min(s+minSeconds, tl.duration), 2))
+		if !emit(s + maxSeconds) {
+			return
+		}
+		fixed = append(fixed, round(math.Min(s+maxSeconds, tl.duration), 2))
+		for i := sort.SearchFloat64s(ends, s+minSeconds); i < len(ends) && ends[i] <= s+maxSeconds; i++ {
+			if !emit(ends[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (tl *timeline) score(start, end float64) ClipCandidate {
+	c := ClipCandidate{StartSeconds: start, EndSeconds: end, DurationSeconds: round(end-start, 2)}
+
+	first, last := int(start/binSeconds), int(math.Ceil(end/binSeconds))
+	if last > len(tl.energy) {
+		last = len(tl.energy)
+	}
+	if first < last {
+		window := append([]float64(nil), tl.energy[first:last]...)
+		sort.Sort(sort.Reverse(sort.Float64Slice(window)))
+		top := window[:int(math.Ceil(float64(len(window))/4))]
+		c.Components.Energy = round(mean(top), 4)
+	}
+
+	words := countIn(tl.words, start, end)
+	c.Components.SpeechDensity = round(clamp(float64(words)/(end-start)/fastSpeechWPS, 0, 1), 4)
+
+	hits := 0
+	for _, h := range tl.hits {
+		if h.at >= start && h.at < end {
+			hits++
+		}
+	}
+	c.Components.Keywords = round(1-math.Pow(0.5, float64(hits)), 4)
+
+	c.Components.SceneAlignment = round((tl.edgeAlignment(start, true)+tl.edgeAlignment(end, false))/2, 4)
+	return c
+}
+
+// edgeAlignment is 1 for an edge on a scene cut, 0.5 on a sentence
+// boundary and 0 otherwise. The ends of the source count as cuts when the
+// source has any cuts at all.
+func (tl *timeline) edgeAlignment(t float64, isStart bool) float64 {
+	if len(tl.cuts) > 0 {
+		if (isStart && t <= alignTolerance) || (!isStart && t >= tl.duration-alignTolerance) || near(tl.cuts, t) {
+			return 1
+		}
+	}
+	if near(tl.sentences, t) {
+		return 0.5
+	}
+	return 0
+}
+
+func (tl *timeline) keywordsIn(start, end float64) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, h := range tl.hits {
+		if h.at >= start && h.at < end && !seen[h.keyword] {
+			seen[h.keyword] = true
+			out = append(out, h.keyword)
+		}
+	}
+	return out
+}
+
+// excerpt joins the transcript segments centred inside the window.
+func (tl *timeline) excerpt(start, end float64) string {
+	var parts []string
+	for _, seg := range tl.segments {
+		if mid := (seg.StartSeconds + seg.EndSeconds) / 2; mid >= start && mid < end && seg.Text != "" {
+			parts = append(parts, seg.Text)
+		}
+	}
+	text := []rune(strings.Join(parts, " "))
+	if len(text) > maxExcerptRunes {
+		return strings.TrimSpace(string(text[:maxExcerptRunes-1])) + "…"
+	}
+	return string(text)
+}
+
+func overlapsAny(c ClipCandidate, chosen []ClipCandidate) bool {
+	for _, o := range chosen {
+		shared := math.Min(c.EndSeconds, o.EndSeconds) - math.Max(c.StartSeconds, o.StartSeconds)
+		if shared > maxOverlap*math.Min(c.DurationSeconds, o.DurationSeconds) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize lower-cases text and splits it into words, dropping
+// punctuation but keeping apostrophes inside words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+func hasPhraseAt(tokens []string, i int, phrase []string) bool {
+	if len(phrase) == 0 || i+len(phrase) > len(tokens) {
+		return false
+	}
+	for k, word := range phrase {
+		if tokens[i+k] != word {
+			return false
+		}
+	}
+	return true
+}
+
+func uniqueTimes(values []float64) []float64 {
+	sort.Float64s(values)
+	out := values[:0]
+	for _, v := range values {
+		if len(out) == 0 || v-out[len(out)-1] > 0.01 {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// near reports whether sorted holds a value within alignTolerance of t.
+func near(sorted []float64, t float64) bool {
+	i := sort.SearchFloat64s(sorted, t-alignTolerance)
+	return i < len(sorted) && sorted[i] <= t+alignTolerance
+}
+
+func countIn(sorted []float64, start, end float64) int {
+	return sort.SearchFloat64s(sorted, end) - sort.SearchFloat64s(sorted, start)
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(math.Round(p*float64(len(sorted)-1)))]
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func round(v float64, places int) float64 {
+	scale := math.Pow10(places)
+	return math.Round(v*scale) / scale
+}
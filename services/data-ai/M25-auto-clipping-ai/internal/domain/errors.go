@@ -0,0 +1,15 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrIdempotencyRequired = errors.New("idempotency key required")
+	ErrIdempotencyConflict = errors.New("idempotency key reused with different payload")
+	ErrIdempotencyInFlight = errors.New("idempotency key request in progress")
+	ErrNoSignals           = errors.New("source has no loudness, scene or transcript signals")
+	ErrNoCandidates        = errors.New("no clip fits the requested durations and targets")
+)
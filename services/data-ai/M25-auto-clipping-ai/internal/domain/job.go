@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// ClipJob finds clip candidates in one source asset. The model version is
+// fixed when the job is created, so a deployment mid-way does not change
+// how a queued job is scored.
+type ClipJob struct {
+	JobID          string          `json:"job_id"`
+	SourceAssetID  string          `json:"source_asset_id"`
+	RequestedBy    string          `json:"requested_by"`
+	Status         JobStatus       `json:"status"`
+	ModelVersionID string          `json:"model_version_id"`
+	Signals        Signals         `json:"-"`
+	Options        ClipOptions     `json:"options"`
+	Candidates     []ClipCandidate `json:"candidates,omitempty"`
+	ErrorCode      string          `json:"error_code,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+}
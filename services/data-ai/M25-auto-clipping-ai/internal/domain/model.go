@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ScoringWeights set how much each signal counts towards a candidate's
+// score. They are what a deployed model version changes.
+type ScoringWeights struct {
+	Energy         float64 `json:"energy"`
+	SpeechDensity  float64 `json:"speech_density"`
+	Keywords       float64 `json:"keywords"`
+	SceneAlignment float64 `json:"scene_alignment"`
+}
+
+var DefaultWeights = ScoringWeights{Energy: 0.35, SpeechDensity: 0.25, Keywords: 0.25, SceneAlignment: 0.15}
+
+// MaxScoringWeight bounds each weight. Only their ratios matter, and the
+// bound keeps their sum finite.
+const MaxScoringWeight = 1000
+
+func (w ScoringWeights) Valid() bool {
+	sum := 0.0
+	for _, v := range []float64{w.Energy, w.SpeechDensity, w.Keywords, w.SceneAlignment} {
+		if v < 0 || v > MaxScoringWeight || math.IsNaN(v) {
+			return false
+		}
+		sum += v
+	}
+	return sum > 0
+}
+
+func (w ScoringWeights) score(c ScoreComponents) float64 {
+	sum := w.Energy + w.SpeechDensity + w.Keywords + w.SceneAlignment
+	total := w.Energy*c.Energy + w.SpeechDensity*c.SpeechDensity + w.Keywords*c.Keywords + w.SceneAlignment*c.SceneAlignment
+	return total / sum
+}
+
+type ModelStatus string
+
+const (
+	// ModelStatusActive is the version that scores jobs not routed to a
+	// canary; there is at most one.
+	ModelStatusActive  ModelStatus = "active"
+	ModelStatusCanary  ModelStatus = "canary"
+	ModelStatusRetired ModelStatus = "retired"
+)
+
+type ModelVersion struct {
+	ModelVersionID   string         `json:"model_version_id"`
+	ModelName        string         `json:"model_name"`
+	VersionTag       string         `json:"version_tag"`
+	ArtifactKey      string         `json:"model_artifact_key"`
+	Weights          ScoringWeights `json:"scoring_weights"`
+	CanaryPercentage int            `json:"canary_percentage"`
+	Status           ModelStatus    `json:"status"`
+	Description      string         `json:"description,omitempty"`
+	Reason           string         `json:"reason"`
+	DeployedBy       string         `json:"deployed_by"`
+	DeployedAt       time.Time      `json:"deployed_at"`
+}
+
+// BuiltinModel scores jobs until a model version has been deployed.
+var BuiltinModel = ModelVersion{
+	ModelVersionID: "m25_builtin_v1",
+	ModelName:      "heuristic",
+	VersionTag:     "v1",
+	Weights:        DefaultWeights,
+	Status:         ModelStatusActive,
+}
@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+)
+
+type IdempotencyRecord struct {
+	Key          string
+	RequestHash  string
+	ResponseCode int
+	ResponseBody []byte
+	ExpiresAt    time.Time
+}
+
+type IdempotencyRepository interface {
+	Get(ctx context.Context, key string, now time.Time) (*IdempotencyRecord, error)
+	Reserve(ctx context.Context, key, requestHash string, expiresAt time.Time) error
+	Complete(ctx context.Context, key string, responseCode int, responseBody []byte, at time.Time) error
+	Release(ctx context.Context, key string) error
+}
+
+type JobRepository interface {
+	Create(ctx context.Context, job domain.ClipJob) error
+	GetByID(ctx context.Context, jobID string) (domain.ClipJob, error)
+	Update(ctx context.Context, job domain.ClipJob) error
+	CountByModelVersion(ctx context.Context) (map[string]int, error)
+}
+
+type ModelRepository interface {
+	Create(ctx context.Context, model domain.ModelVersion) error
+	GetByID(ctx context.Context, modelVersionID string) (domain.ModelVersion, error)
+	Update(ctx context.Context, model domain.ModelVersion) error
+	List(ctx context.Context) ([]domain.ModelVersion, error)
+}
+
+// JobQueue hands queued job IDs to workers. Dequeue returns io.EOF when
+// the queue is empty.
+type JobQueue interface {
+	Enqueue(ctx context.Context, jobID string) error
+	Dequeue(ctx context.Context) (string, error)
+}
@@ -0,0 +1,375 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/adapters/events"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/application"
+	"github.com/viralforge/mesh/services/data-ai/M25-auto-clipping-ai/internal/domain"
+)
+
+func newService() *application.Service {
+	repos := postgres.NewRepositories()
+	return application.NewService(application.Dependencies{
+		Config: application.Config{
+			ServiceName:    "M25-Auto-Clipping-AI",
+			IdempotencyTTL: 7 * 24 * time.Hour,
+		},
+		Idempotency: repos.Idempotency,
+		Jobs:        repos.Jobs,
+		Models:      repos.Models,
+		Queue:       events.NewMemoryJobQueue(),
+	})
+}
+
+// podcastSignals is a two-minute source with scene cuts every 20s. The
+// 60-80s scene is loud, talks fast and mentions the giveaway; the rest is
+// quiet and slow.
+func podcastSignals() domain.Signals {
+	sig := domain.Signals{DurationSeconds: 120, SceneCuts: []float64{20, 40, 60, 80, 100}}
+	for t := 0.0; t <= 120; t++ {
+		lufs := -30.0
+		if t >= 61 && t <= 79 {
+			lufs = -12
+		}
+		sig.Loudness = append(sig.Loudness, domain.LoudnessSample{AtSeconds: t, LUFS: lufs})
+	}
+	for start := 0.0; start < 120; start += 5 {
+		text := "we talked about the weather"
+		if start >= 60 && start < 80 {
+			text = "okay everyone this giveaway is huge so listen up and do not miss the giveaway link"
+		}
+		sig.Transcript = append(sig.Transcript, domain.TranscriptSegment{StartSeconds: start, EndSeconds: start + 5, Text: text})
+	}
+	return sig
+}
+
+func clipOptions(t *testing.T, in domain.ClipOptions) domain.ClipOptions {
+	t.Helper()
+	out, err := domain.NormalizeOptions(in)
+	if err != nil {
+		t.Fatalf("normalize options: %v", err)
+	}
+	return out
+}
+
+func TestGenerateCandidatesRanksLoudKeywordSceneFirst(t *testing.T) {
+	t.Parallel()
+	opts := clipOptions(t, domain.ClipOptions{MinSeconds: 15, MaxSeconds: 30, Keywords: []string{"Giveaway"}})
+	candidates, err := domain.GenerateCandidates(context.Background(), podcastSignals(), opts, domain.DefaultWeights)
+	if err != nil {
+		t.Fatalf("generate candidates: %v", err)
+	}
+	best := candidates[0]
+	if best.Rank != 1 || best.StartSeconds < 59.5 || best.EndSeconds > 80.5 {
+		t.Fatalf("expected the 60-80s scene to rank first, got %+v", best)
+	}
+	if best.Components.Energy < 0.9 || best.Components.Keywords < 0.9 || best.Components.SceneAlignment != 1 {
+		t.Fatalf("expected strong energy, keyword and scene scores, got %+v", best.Components)
+	}
+	if len(best.MatchedKeywords) != 1 || best.MatchedKeywords[0] != "giveaway" || best.Transcript == "" {
+		t.Fatalf("expected giveaway keyword and transcript excerpt, got %+v", best)
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score > candidates[i-1].Score || candidates[i].Rank != i+1 {
+			t.Fatalf("expected candidates ranked by score, got %+v", candidates)
+		}
+	}
+}
+
+func TestGenerateCandidatesRespectsDurationsTargetsAndOverlap(t *testing.T) {
+	t.Parallel()
+	opts := clipOptions(t, domain.ClipOptions{
+		MinSeconds:    20,
+		MaxSeconds:    90,
+		MaxCandidates: 10,
+		Targets:       []domain.PlatformTarget{{Platform: "youtube_shorts"}, {Platform: "instagram_reels", AspectRatio: "4:5"}},
+	})
+	candidates, err := domain.GenerateCandidates(context.Background(), podcastSignals(), opts, domain.DefaultWeights)
+	if err != nil {
+		t.Fatalf("generate candidates: %v", err)
+	}
+	if len(candidates) < 2 {
+		t.Fatalf("expected several candidates, got %d", len(candidates))
+	}
+	for i, c := range candidates {
+		if c.DurationSeconds < 20 || c.DurationSeconds > 90 {
+			t.Fatalf("candidate outside duration bounds: %+v", c)
+		}
+		for _, target := range c.Targets {
+			if target.Platform == "youtube_shorts" && c.DurationSeconds > 60 {
+				t.Fatalf("clip over 60s offered to youtube_shorts: %+v", c)
+			}
+			if target.Platform == "instagram_reels" && target.AspectRatio != "4:5" {
+				t.Fatalf("expected requested aspect ratio to be kept, got %+v", target)
+			}
+		}
+		for _, other := range candidates[:i] {
+			shared := math.Min(c.EndSeconds, other.EndSeconds) - math.Max(c.StartSeconds, other.StartSeconds)
+			if shared > 0.5*math.Min(c.DurationSeconds, other.DurationSeconds) {
+				t.Fatalf("candidates overlap by more than half: %+v and %+v", other, c)
+			}
+		}
+	}
+}
+
+// denseSignals is a source with as many scene cuts and transcript segments
+// as NormalizeSignals accepts.
+func denseSignals(minutes int) domain.Signals {
+	sig := domain.Signals{DurationSeconds: float64(minutes * 60)}
+	for i := 0; i < minutes*domain.MaxCutsPerMinute; i++ {
+		at := float64(i) * 60 / domain.MaxCutsPerMinute
+		sig.SceneCuts = append(sig.SceneCuts, at+0.3)
+		sig.Transcript = append(sig.Transcript, domain.TranscriptSegment{StartSeconds: at, EndSeconds: at + 0.9, Text: "and then"})
+		sig.Loudness = append(sig.Loudness, domain.LoudnessSample{AtSeconds: at, LUFS: float64(-30 + i%7)})
+	}
+	return sig
+}
+
+func TestGenerateCandidatesBoundsDenseSources(t *testing.T) {
+	t.Parallel()
+	crowded := denseSignals(1)
+	crowded.SceneCuts = append(crowded.SceneCuts, 59.9)
+	if _, err := domain.NormalizeSignals(crowded); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected too many cuts per minute to be refused, got %v", err)
+	}
+
+	sig, err := domain.NormalizeSignals(denseSignals(20))
+	if err != nil {
+		t.Fatalf("normalize signals: %v", err)
+	}
+	opts := clipOptions(t, domain.ClipOptions{MinSeconds: 15, MaxSeconds: 600, MaxCandidates: domain.MaxCandidateLimit})
+	candidates, err := domain.GenerateCandidates(context.Background(), sig, opts, domain.DefaultWeights)
+	if err != nil || len(candidates) == 0 {
+		t.Fatalf("expected candidates from a dense source, got %d (%v)", len(candidates), err)
+	}
+	if last := candidates[len(candidates)-1]; last.Rank != len(candidates) {
+		t.Fatalf("expected ranked candidates, got %+v", last)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := domain.GenerateCandidates(cancelled, sig, opts, domain.DefaultWeights); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected scoring to stop with the context, got %v", err)
+	}
+}
+
+func TestNormalizeRejectsImpossibleRequests(t *testing.T) {
+	t.Parallel()
+	if _, err := domain.NormalizeSignals(domain.Signals{DurationSeconds: 60}); !errors.Is(err, domain.ErrNoSignals) {
+		t.Fatalf("expected no signals error, got %v", err)
+	}
+	cases := []domain.ClipOptions{
+		{MinSeconds: 30, MaxSeconds: 20},
+		{Targets: []domain.PlatformTarget{{Platform: "myspace"}}},
+		{Targets: []domain.PlatformTarget{{Platform: "tiktok", AspectRatio: "21:9"}}},
+		{MinSeconds: 70, MaxSeconds: 120, Targets: []domain.PlatformTarget{{Platform: "youtube_shorts"}}},
+		{MaxCandidates: 50},
+	}
+	for _, opts := range cases {
+		if _, err := domain.NormalizeOptions(opts); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("expected invalid input for %+v, got %v", opts, err)
+		}
+	}
+}
+
+func TestClipJobLifecycle(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	ctx := context.Background()
+	actor := application.Actor{SubjectID: "creator-1", Role: "creator", IdempotencyKey: "idem-job-1"}
+	input := application.CreateClipJobInput{
+		SourceAssetID: "asset-1",
+		Signals:       podcastSignals(),
+		Options:       domain.ClipOptions{Keywords: []string{"giveaway"}},
+	}
+
+	job, err := svc.CreateClipJob(ctx, actor, input)
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if job.Status != domain.JobStatusQueued || job.ModelVersionID != domain.BuiltinModel.ModelVersionID {
+		t.Fatalf("expected queued job on builtin model, got %+v", job)
+	}
+	replay, err := svc.CreateClipJob(ctx, actor, input)
+	if err != nil || replay.JobID != job.JobID {
+		t.Fatalf("expected idempotent replay, got job=%s err=%v", replay.JobID, err)
+	}
+	input.SourceAssetID = "asset-2"
+	if _, err := svc.CreateClipJob(ctx, actor, input); !errors.Is(err, domain.ErrIdempotencyConflict) {
+		t.Fatalf("expected idempotency conflict, got %v", err)
+	}
+
+	if err := svc.ProcessNextJob(ctx); err != nil {
+		t.Fatalf("process job: %v", err)
+	}
+	if err := svc.ProcessNextJob(ctx); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected empty queue, got %v", err)
+	}
+	done, err := svc.GetClipJob(ctx, actor, job.JobID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if done.Status != domain.JobStatusCompleted || len(done.Candidates) == 0 || done.CompletedAt == nil {
+		t.Fatalf("expected completed job with candidates, got %+v", done)
+	}
+	if _, err := svc.GetClipJob(ctx, application.Actor{SubjectID: "creator-2"}, job.JobID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for another creator, got %v", err)
+	}
+	if _, err := svc.GetClipJob(ctx, application.Actor{SubjectID: "ops-1", Role: "admin"}, job.JobID); err != nil {
+		t.Fatalf("expected admin access, got %v", err)
+	}
+
+	short := application.CreateClipJobInput{
+		SourceAssetID: "asset-3",
+		Signals:       domain.Signals{SceneCuts: []float64{4, 8}},
+	}
+	actor.IdempotencyKey = "idem-job-short"
+	if _, err := svc.CreateClipJob(ctx, actor, short); !errors.Is(err, domain.ErrNoCandidates) {
+		t.Fatalf("expected no candidates for a source shorter than the minimum clip, got %v", err)
+	}
+}
+
+// gatedQueue holds each enqueue until release is closed, and fails the
+// first one.
+type gatedQueue struct {
+	*events.MemoryJobQueue
+	entered chan struct{}
+	release chan struct{}
+	failed  bool
+}
+
+func (q *gatedQueue) Enqueue(ctx context.Context, jobID string) error {
+	q.entered <- struct{}{}
+	<-q.release
+	if !q.failed {
+		q.failed = true
+		return errors.New("queue unavailable")
+	}
+	return q.MemoryJobQueue.Enqueue(ctx, jobID)
+}
+
+func TestCreateClipJobRefusesInFlightReplayAndReleasesKeyOnFailure(t *testing.T) {
+	t.Parallel()
+	repos := postgres.NewRepositories()
+	queue := &gatedQueue{MemoryJobQueue: events.NewMemoryJobQueue(), entered: make(chan struct{}, 2), release: make(chan struct{})}
+	svc := application.NewService(application.Dependencies{
+		Idempotency: repos.Idempotency,
+		Jobs:        repos.Jobs,
+		Models:      repos.Models,
+		Queue:       queue,
+	})
+	ctx := context.Background()
+	actor := application.Actor{SubjectID: "creator-1", IdempotencyKey: "idem-gated"}
+	input := application.CreateClipJobInput{SourceAssetID: "asset-1", Signals: podcastSignals()}
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateClipJob(ctx, actor, input)
+		first <- err
+	}()
+	<-queue.entered
+	if _, err := svc.CreateClipJob(ctx, actor, input); !errors.Is(err, domain.ErrIdempotencyInFlight) {
+		t.Fatalf("expected a replay during the first request to be in flight, got %v", err)
+	}
+	close(queue.release)
+	if err := <-first; err == nil {
+		t.Fatalf("expected the failed enqueue to surface")
+	}
+	if _, err := svc.CreateClipJob(ctx, actor, input); err != nil {
+		t.Fatalf("expected a retry with the same key to go through, got %v", err)
+	}
+}
+
+func TestScoringWeightsAreBounded(t *testing.T) {
+	t.Parallel()
+	huge := domain.ScoringWeights{Energy: math.MaxFloat64, SpeechDensity: math.MaxFloat64}
+	if huge.Valid() {
+		t.Fatalf("expected weights whose sum overflows to be refused")
+	}
+	if !(domain.ScoringWeights{Energy: domain.MaxScoringWeight, Keywords: 1}).Valid() {
+		t.Fatalf("expected weights up to the bound to be accepted")
+	}
+}
+
+func TestDeployModelRoutesCanaryShareAndTracksVersions(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin"}
+	deploy := func(tag string, pct int) domain.ModelVersion {
+		t.Helper()
+		model, err := svc.DeployModel(ctx, admin, application.DeployModelInput{
+			ModelName:        "clip_ranker",
+			VersionTag:       tag,
+			ArtifactKey:      "s3://models/clip_ranker/" + tag,
+			CanaryPercentage: pct,
+			Reason:           "rollout",
+			Weights:          &domain.ScoringWeights{Energy: 1, Keywords: 1},
+		})
+		if err != nil {
+			t.Fatalf("deploy %s: %v", tag, err)
+		}
+		return model
+	}
+
+	if _, err := svc.DeployModel(ctx, application.Actor{SubjectID: "creator-1"}, application.DeployModelInput{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden deploy for non-admin, got %v", err)
+	}
+	stable := deploy("v1", 100)
+	canary := deploy("v2", 50)
+	if stable.Status != domain.ModelStatusActive || canary.Status != domain.ModelStatusCanary {
+		t.Fatalf("unexpected statuses: stable=%s canary=%s", stable.Status, canary.Status)
+	}
+
+	seen := map[string]int{}
+	for i := 0; i < 40; i++ {
+		job, err := svc.CreateClipJob(ctx, application.Actor{SubjectID: "creator-1", IdempotencyKey: fmt.Sprintf("idem-%d", i)}, application.CreateClipJobInput{
+			SourceAssetID: "asset-1",
+			Signals:       podcastSignals(),
+		})
+		if err != nil {
+			t.Fatalf("create job %d: %v", i, err)
+		}
+		seen[job.ModelVersionID]++
+	}
+	if seen[stable.ModelVersionID] == 0 || seen[canary.ModelVersionID] == 0 || seen[domain.BuiltinModel.ModelVersionID] != 0 {
+		t.Fatalf("expected jobs split between active and canary, got %v", seen)
+	}
+	for {
+		if err := svc.ProcessNextJob(ctx); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			t.Fatalf("process job: %v", err)
+		}
+	}
+
+	deploy("v3", 100)
+	models, err := svc.ListModels(ctx, admin)
+	if err != nil {
+		t.Fatalf("list models: %v", err)
+	}
+	statuses := map[string]domain.ModelStatus{}
+	scored := 0
+	for _, m := range models {
+		statuses[m.VersionTag] = m.Status
+		scored += m.JobsScored
+		if m.ModelVersionID == canary.ModelVersionID && m.JobsScored != seen[canary.ModelVersionID] {
+			t.Fatalf("expected canary to have scored %d jobs, got %d", seen[canary.ModelVersionID], m.JobsScored)
+		}
+	}
+	if scored != 40 {
+		t.Fatalf("expected 40 scored jobs across versions, got %d", scored)
+	}
+	if statuses["v1"] != domain.ModelStatusRetired || statuses["v2"] != domain.ModelStatusRetired || statuses["v3"] != domain.ModelStatusActive {
+		t.Fatalf("expected full rollout to retire earlier versions, got %v", statuses)
+	}
+}
@@ -84,3 +84,44 @@ func (h *Handler) getExport(w http.ResponseWriter, r *http.Request) {
 		"ready_at":     job.ReadyAt,
 	})
 }
+
+func (h *Handler) listSubmissionFacts(w http.ResponseWriter, r *http.Request) {
+	rows, next, err := h.service.ListSubmissionFacts(r.Context(), actorFromContext(r.Context()), warehouseFactsInput(r))
+	h.writeWarehouseRows(w, r, rows, next, err)
+}
+
+func (h *Handler) listClickFacts(w http.ResponseWriter, r *http.Request) {
+	rows, next, err := h.service.ListClickFacts(r.Context(), actorFromContext(r.Context()), warehouseFactsInput(r))
+	h.writeWarehouseRows(w, r, rows, next, err)
+}
+
+func (h *Handler) listPayoutFacts(w http.ResponseWriter, r *http.Request) {
+	rows, next, err := h.service.ListPayoutFacts(r.Context(), actorFromContext(r.Context()), warehouseFactsInput(r))
+	h.writeWarehouseRows(w, r, rows, next, err)
+}
+
+func (h *Handler) listCampaigns(w http.ResponseWriter, r *http.Request) {
+	rows, next, err := h.service.ListCampaigns(r.Context(), actorFromContext(r.Context()), warehouseFactsInput(r))
+	h.writeWarehouseRows(w, r, rows, next, err)
+}
+
+func (h *Handler) writeWarehouseRows(w http.ResponseWriter, r *http.Request, rows interface{}, next string, err error) {
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", contracts.WarehousePage{Items: rows, NextCursor: next})
+}
+
+func warehouseFactsInput(r *http.Request) application.WarehouseFactsInput {
+	query := r.URL.Query()
+	return application.WarehouseFactsInput{
+		CreatorID:  strings.TrimSpace(query.Get("creator_id")),
+		CampaignID: strings.TrimSpace(query.Get("campaign_id")),
+		From:       strings.TrimSpace(query.Get("from")),
+		To:         strings.TrimSpace(query.Get("to")),
+		Cursor:     strings.TrimSpace(query.Get("cursor")),
+		Limit:      strings.TrimSpace(query.Get("limit")),
+	}
+}
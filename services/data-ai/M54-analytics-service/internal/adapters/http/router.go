@@ -29,6 +29,10 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Get("/analytics/admin/financial-report", handler.getAdminFinancialReport)
 			r.Post("/analytics/export", handler.requestExport)
 			r.Get("/analytics/export/{id}", handler.getExport)
+			r.Get("/analytics/warehouse/submissions", handler.listSubmissionFacts)
+			r.Get("/analytics/warehouse/clicks", handler.listClickFacts)
+			r.Get("/analytics/warehouse/payouts", handler.listPayoutFacts)
+			r.Get("/analytics/warehouse/campaigns", handler.listCampaigns)
 		})
 	})
 	return r
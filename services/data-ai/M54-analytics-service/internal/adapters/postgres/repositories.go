@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -242,6 +243,106 @@ func (r *WarehouseRepository) GetFinancialReport(_ context.Context, from, to tim
 	return report, nil
 }
 
+func (r *WarehouseRepository) ListSubmissions(_ context.Context, filter domain.FactFilter) ([]domain.FactSubmission, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.FactSubmission{}
+	for _, row := range r.submissions {
+		if factMatches(filter, row.CreatorID, row.CampaignID, row.OccurredAt) && pastCursor(filter, row.OccurredAt, row.SubmissionID) {
+			out = append(out, row)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.FactSubmission) int {
+		return compareFacts(a.OccurredAt, b.OccurredAt, a.SubmissionID, b.SubmissionID)
+	})
+	return limitRows(out, filter.Limit), nil
+}
+
+// ListClicks filters clicks on the clicking user, as CreatorID.
+func (r *WarehouseRepository) ListClicks(_ context.Context, filter domain.FactFilter) ([]domain.FactClick, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.FactClick{}
+	for _, row := range r.clicks {
+		if factMatches(filter, row.UserID, "", row.OccurredAt) && pastCursor(filter, row.OccurredAt, row.ClickID) {
+			out = append(out, row)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.FactClick) int {
+		return compareFacts(a.OccurredAt, b.OccurredAt, a.ClickID, b.ClickID)
+	})
+	return limitRows(out, filter.Limit), nil
+}
+
+func (r *WarehouseRepository) ListPayouts(_ context.Context, filter domain.FactFilter) ([]domain.FactPayout, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.FactPayout{}
+	for _, row := range r.payouts {
+		if factMatches(filter, row.CreatorID, "", row.OccurredAt) && pastCursor(filter, row.OccurredAt, row.PayoutID) {
+			out = append(out, row)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.FactPayout) int {
+		return compareFacts(a.OccurredAt, b.OccurredAt, a.PayoutID, b.PayoutID)
+	})
+	return limitRows(out, filter.Limit), nil
+}
+
+// ListCampaigns filters campaigns on their launch time; the ID filters do
+// not apply.
+func (r *WarehouseRepository) ListCampaigns(_ context.Context, filter domain.FactFilter) ([]domain.DimCampaign, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.DimCampaign{}
+	for _, row := range r.campaigns {
+		if factMatches(domain.FactFilter{From: filter.From, To: filter.To}, "", "", row.LaunchedAt) && pastCursor(filter, row.LaunchedAt, row.CampaignID) {
+			out = append(out, row)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.DimCampaign) int {
+		return compareFacts(a.LaunchedAt, b.LaunchedAt, a.CampaignID, b.CampaignID)
+	})
+	return limitRows(out, filter.Limit), nil
+}
+
+// factMatches reports whether a row falls inside the filter. A filter on
+// campaign never matches rows without one.
+func factMatches(filter domain.FactFilter, creatorID, campaignID string, at time.Time) bool {
+	if filter.CreatorID != "" && filter.CreatorID != creatorID {
+		return false
+	}
+	if filter.CampaignID != "" && filter.CampaignID != campaignID {
+		return false
+	}
+	if !filter.From.IsZero() && at.Before(filter.From) {
+		return false
+	}
+	return filter.To.IsZero() || at.Before(filter.To)
+}
+
+// pastCursor reports whether a row sorts after the filter's cursor.
+func pastCursor(filter domain.FactFilter, at time.Time, id string) bool {
+	if filter.AfterAt.IsZero() && filter.AfterID == "" {
+		return true
+	}
+	return compareFacts(at, filter.AfterAt, id, filter.AfterID) > 0
+}
+
+func limitRows[T any](rows []T, limit int) []T {
+	if limit > 0 && len(rows) > limit {
+		return rows[:limit]
+	}
+	return rows
+}
+
+func compareFacts(atA, atB time.Time, idA, idB string) int {
+	if c := atA.Compare(atB); c != 0 {
+		return c
+	}
+	return strings.Compare(idA, idB)
+}
+
 type ExportRepository struct {
 	mu      sync.RWMutex
 	records map[string]domain.ExportJob
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M54-analytics-service/internal/domain"
+)
+
+const (
+	// Warehouse reads are paged so a reader never pulls a whole table in
+	// one response.
+	defaultWarehousePageSize = 1000
+	maxWarehousePageSize     = 5000
+)
+
+// ListSubmissionFacts, ListClickFacts, ListPayoutFacts and ListCampaigns
+// expose the warehouse rows to internal readers such as
+// M56-Predictive-Analytics. They are open to admins and services only, and
+// return one page plus the cursor of the next, empty on the last page.
+func (s *Service) ListSubmissionFacts(ctx context.Context, actor Actor, input WarehouseFactsInput) ([]domain.FactSubmission, string, error) {
+	filter, err := warehouseFilter(actor, input)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := s.warehouse.ListSubmissions(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, next := warehousePage(rows, filter.Limit-1, func(row domain.FactSubmission) (time.Time, string) { return row.OccurredAt, row.SubmissionID })
+	return rows, next, nil
+}
+
+func (s *Service) ListClickFacts(ctx context.Context, actor Actor, input WarehouseFactsInput) ([]domain.FactClick, string, error) {
+	filter, err := warehouseFilter(actor, input)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := s.warehouse.ListClicks(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, next := warehousePage(rows, filter.Limit-1, func(row domain.FactClick) (time.Time, string) { return row.OccurredAt, row.ClickID })
+	return rows, next, nil
+}
+
+func (s *Service) ListPayoutFacts(ctx context.Context, actor Actor, input WarehouseFactsInput) ([]domain.FactPayout, string, error) {
+	filter, err := warehouseFilter(actor, input)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := s.warehouse.ListPayouts(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, next := warehousePage(rows, filter.Limit-1, func(row domain.FactPayout) (time.Time, string) { return row.OccurredAt, row.PayoutID })
+	return rows, next, nil
+}
+
+// ListCampaigns returns the campaigns launched within From and To; the ID
+// filters do not apply.
+func (s *Service) ListCampaigns(ctx context.Context, actor Actor, input WarehouseFactsInput) ([]domain.DimCampaign, string, error) {
+	filter, err := warehouseFilter(actor, input)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.CreatorID, filter.CampaignID = "", ""
+	rows, err := s.warehouse.ListCampaigns(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, next := warehousePage(rows, filter.Limit-1, func(row domain.DimCampaign) (time.Time, string) { return row.LaunchedAt, row.CampaignID })
+	return rows, next, nil
+}
+
+// warehouseFilter asks the repository for one row past the page size, so
+// the page knows whether another follows.
+func warehouseFilter(actor Actor, input WarehouseFactsInput) (domain.FactFilter, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.FactFilter{}, domain.ErrUnauthorized
+	}
+	if role := normalizeRole(actor.Role); role != "admin" && role != "service" {
+		return domain.FactFilter{}, domain.ErrForbidden
+	}
+	filter := domain.FactFilter{
+		CreatorID:  strings.TrimSpace(input.CreatorID),
+		CampaignID: strings.TrimSpace(input.CampaignID),
+		Limit:      defaultWarehousePageSize + 1,
+	}
+	var err error
+	if filter.From, err = parseTimestamp(input.From); err != nil {
+		return domain.FactFilter{}, err
+	}
+	if filter.To, err = parseTimestamp(input.To); err != nil {
+		return domain.FactFilter{}, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return domain.FactFilter{}, domain.ErrInvalidInput
+	}
+	if raw := strings.TrimSpace(input.Limit); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxWarehousePageSize {
+			return domain.FactFilter{}, domain.ErrInvalidInput
+		}
+		filter.Limit = limit + 1
+	}
+	if filter.AfterAt, filter.AfterID, err = decodeWarehouseCursor(input.Cursor); err != nil {
+		return domain.FactFilter{}, err
+	}
+	return filter, nil
+}
+
+func warehousePage[T any](rows []T, limit int, key func(T) (time.Time, string)) ([]T, string) {
+	if len(rows) <= limit {
+		return rows, ""
+	}
+	rows = rows[:limit]
+	at, id := key(rows[len(rows)-1])
+	return rows, encodeWarehouseCursor(at, id)
+}
+
+// encodeWarehouseCursor packs the time and ID of a page's last row.
+func encodeWarehouseCursor(at time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(at.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeWarehouseCursor(raw string) (time.Time, string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return time.Time{}, "", nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}, "", domain.ErrInvalidInput
+	}
+	stamp, id, ok := strings.Cut(string(decoded), "|")
+	if !ok {
+		return time.Time{}, "", domain.ErrInvalidInput
+	}
+	at, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return time.Time{}, "", domain.ErrInvalidInput
+	}
+	return at.UTC(), id, nil
+}
+
+func parseTimestamp(raw string) (time.Time, error) {
+	if strings.TrimSpace(raw) == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidInput
+	}
+	return t.UTC(), nil
+}
@@ -31,6 +31,18 @@ type FinancialReportInput struct {
 	DateTo   string
 }
 
+// WarehouseFactsInput narrows a warehouse read. From and To are RFC 3339
+// timestamps; From is inclusive and To exclusive, and either may be empty.
+// Cursor is the next_cursor of the previous page and Limit the page size.
+type WarehouseFactsInput struct {
+	CreatorID  string
+	CampaignID string
+	From       string
+	To         string
+	Cursor     string
+	Limit      string
+}
+
 type ExportInput struct {
 	ReportType string
 	Format     string
@@ -105,6 +117,8 @@ func normalizeRole(raw string) string {
 	switch raw {
 	case "admin":
 		return "admin"
+	case "service":
+		return "service"
 	default:
 		return "creator"
 	}
@@ -14,6 +14,13 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// WarehousePage is one page of warehouse rows; NextCursor is empty on the
+// last page.
+type WarehousePage struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
 type ErrorResponse struct {
 	Status string       `json:"status"`
 	Error  ErrorPayload `json:"error"`
@@ -59,6 +59,20 @@ type DimCampaign struct {
 	LaunchedAt time.Time `json:"launched_at"`
 }
 
+// FactFilter narrows a warehouse read. Empty IDs match every row; From is
+// inclusive and To exclusive, and either may be zero. Rows come back ordered
+// by time then ID; AfterAt and AfterID resume after the last row of the
+// previous page, and Limit caps the rows returned, zero meaning no cap.
+type FactFilter struct {
+	CreatorID  string
+	CampaignID string
+	From       time.Time
+	To         time.Time
+	AfterAt    time.Time
+	AfterID    string
+	Limit      int
+}
+
 type DailyEarnings struct {
 	DayDate       string    `json:"day_date"`
 	CreatorID     string    `json:"creator_id"`
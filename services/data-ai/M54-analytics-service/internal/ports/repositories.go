@@ -18,6 +18,11 @@ type WarehouseRepository interface {
 
 	GetCreatorDashboard(ctx context.Context, userID string, from, to time.Time) (domain.CreatorDashboard, error)
 	GetFinancialReport(ctx context.Context, from, to time.Time) (domain.FinancialReport, error)
+
+	ListSubmissions(ctx context.Context, filter domain.FactFilter) ([]domain.FactSubmission, error)
+	ListClicks(ctx context.Context, filter domain.FactFilter) ([]domain.FactClick, error)
+	ListPayouts(ctx context.Context, filter domain.FactFilter) ([]domain.FactPayout, error)
+	ListCampaigns(ctx context.Context, filter domain.FactFilter) ([]domain.DimCampaign, error)
 }
 
 type ExportRepository interface {
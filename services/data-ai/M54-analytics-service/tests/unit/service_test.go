@@ -2,6 +2,7 @@ package unit
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -105,3 +106,74 @@ func TestFinancialReportForbiddenForCreator(t *testing.T) {
 		t.Fatalf("expected ErrForbidden, got %v", err)
 	}
 }
+
+func TestWarehouseFactsAreFilteredForServiceReaders(t *testing.T) {
+	t.Parallel()
+
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{Warehouse: repos.Warehouse})
+	ctx := context.Background()
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, creator := range []string{"creator-1", "creator-2", "creator-1"} {
+		_ = repos.Warehouse.AddSubmission(ctx, domain.FactSubmission{SubmissionID: "sub-" + string(rune('a'+i)), CreatorID: creator, CampaignID: "camp-1", Views: 100, OccurredAt: day.AddDate(0, 0, 2-i)})
+	}
+	_ = repos.Warehouse.UpsertCampaign(ctx, domain.DimCampaign{CampaignID: "camp-1", LaunchedAt: day})
+
+	if _, _, err := svc.ListSubmissionFacts(ctx, application.Actor{SubjectID: "creator-1", Role: "creator"}, application.WarehouseFactsInput{}); err != domain.ErrForbidden {
+		t.Fatalf("expected creators to be refused, got %v", err)
+	}
+	if _, _, err := svc.ListSubmissionFacts(ctx, application.Actor{SubjectID: "m56", Role: "service"}, application.WarehouseFactsInput{From: "yesterday"}); err != domain.ErrInvalidInput {
+		t.Fatalf("expected a malformed bound to be refused, got %v", err)
+	}
+	rows, _, err := svc.ListSubmissionFacts(ctx, application.Actor{SubjectID: "m56", Role: "service"}, application.WarehouseFactsInput{
+		CreatorID: "creator-1",
+		From:      day.Format(time.RFC3339),
+		To:        day.AddDate(0, 0, 2).Format(time.RFC3339),
+	})
+	if err != nil || len(rows) != 1 || rows[0].SubmissionID != "sub-c" {
+		t.Fatalf("expected only creator-1's submission inside the range, got %+v (%v)", rows, err)
+	}
+	campaigns, _, err := svc.ListCampaigns(ctx, application.Actor{SubjectID: "ops", Role: "admin"}, application.WarehouseFactsInput{})
+	if err != nil || len(campaigns) != 1 {
+		t.Fatalf("expected the launched campaign, got %+v (%v)", campaigns, err)
+	}
+}
+
+func TestWarehouseFactsArePagedByCursor(t *testing.T) {
+	t.Parallel()
+
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{Warehouse: repos.Warehouse})
+	ctx := context.Background()
+	reader := application.Actor{SubjectID: "m56", Role: "service"}
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	// Two rows share a timestamp so the cursor has to break the tie on ID.
+	for i, at := range []time.Time{day, day, day.Add(time.Hour), day.Add(2 * time.Hour), day.Add(3 * time.Hour)} {
+		_ = repos.Warehouse.AddSubmission(ctx, domain.FactSubmission{SubmissionID: "sub-" + string(rune('a'+i)), CreatorID: "creator-1", OccurredAt: at})
+	}
+
+	if _, _, err := svc.ListSubmissionFacts(ctx, reader, application.WarehouseFactsInput{Limit: "0"}); err != domain.ErrInvalidInput {
+		t.Fatalf("expected a zero page size to be refused, got %v", err)
+	}
+	if _, _, err := svc.ListSubmissionFacts(ctx, reader, application.WarehouseFactsInput{Cursor: "not-a-cursor"}); err != domain.ErrInvalidInput {
+		t.Fatalf("expected a malformed cursor to be refused, got %v", err)
+	}
+	seen := []string{}
+	cursor := ""
+	for page := 0; ; page++ {
+		rows, next, err := svc.ListSubmissionFacts(ctx, reader, application.WarehouseFactsInput{Limit: "2", Cursor: cursor})
+		if err != nil || len(rows) > 2 {
+			t.Fatalf("page %d: got %d rows (%v)", page, len(rows), err)
+		}
+		for _, row := range rows {
+			seen = append(seen, row.SubmissionID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if strings.Join(seen, ",") != "sub-a,sub-b,sub-c,sub-d,sub-e" {
+		t.Fatalf("expected every row exactly once in order, got %v", seen)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/data-ai/M55-dashboard-service/internal/contracts"
 	"github.com/viralforge/mesh/services/data-ai/M55-dashboard-service/internal/domain"
 )
@@ -45,6 +46,7 @@ func (s *Service) publishDLQIdempotencyConflict(ctx context.Context, key, traceI
 		return nil
 	}
 	now := time.Now().UTC()
+	traceID = observability.TraceParent(ctx, traceID)
 	return s.dlq.PublishDLQ(ctx, contracts.DLQRecord{
 		OriginalEvent: contracts.EventEnvelope{
 			EventID:          uuid.NewString(),
@@ -81,3 +81,27 @@ func (h *Handler) predictCampaignSuccess(w http.ResponseWriter, r *http.Request)
 	}
 	writeSuccess(w, http.StatusOK, "campaign success prediction", row)
 }
+
+func (h *Handler) runBacktests(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	reports, err := h.service.RunBacktests(r.Context(), actor)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "backtests completed", map[string]interface{}{"items": reports})
+}
+
+func (h *Handler) listBacktests(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	reports, err := h.service.ListBacktests(r.Context(), actor, application.ListBacktestsInput{
+		ModelVersion: strings.TrimSpace(r.URL.Query().Get("model_version")),
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "backtests", map[string]interface{}{"items": reports})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/contracts"
@@ -34,6 +35,9 @@ func writeError(w http.ResponseWriter, status int, code, message, requestID stri
 }
 
 func mapDomainError(err error) (int, string) {
+	if errors.Is(err, domain.ErrWarehouseUnavailable) {
+		return http.StatusServiceUnavailable, "warehouse_unavailable"
+	}
 	switch err {
 	case nil:
 		return http.StatusOK, ""
@@ -47,6 +51,8 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusBadRequest, "idempotency_key_required"
 	case domain.ErrIdempotencyConflict:
 		return http.StatusConflict, "idempotency_conflict"
+	case domain.ErrInsufficientData:
+		return http.StatusUnprocessableEntity, "insufficient_history"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
@@ -16,6 +16,8 @@ func NewRouter(handler *Handler) http.Handler {
 	protected.HandleFunc("GET /api/v1/predictions/clip-recommendations", handler.getClipRecommendations)
 	protected.HandleFunc("GET /api/v1/predictions/churn-risk", handler.getChurnRisk)
 	protected.HandleFunc("POST /api/v1/campaigns/{campaign_id}/predict-success", handler.predictCampaignSuccess)
+	protected.HandleFunc("POST /api/v1/models/backtests", handler.runBacktests)
+	protected.HandleFunc("GET /api/v1/models/backtests", handler.listBacktests)
 
 	mux.Handle("/", requestIDMiddleware(authMiddleware(protected)))
 	return mux
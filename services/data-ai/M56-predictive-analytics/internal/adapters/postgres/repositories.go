@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,12 +13,16 @@ import (
 type Repositories struct {
 	Idempotency *IdempotencyRepository
 	Predictions *PredictionRepository
+	Backtests   *BacktestRepository
+	Warehouse   *WarehouseRepository
 }
 
 func NewRepositories() *Repositories {
 	return &Repositories{
 		Idempotency: &IdempotencyRepository{records: map[string]ports.IdempotencyRecord{}},
 		Predictions: &PredictionRepository{records: map[string]domain.CampaignSuccessPrediction{}},
+		Backtests:   &BacktestRepository{},
+		Warehouse:   &WarehouseRepository{},
 	}
 }
 
@@ -86,3 +91,40 @@ func (r *PredictionRepository) SaveCampaignSuccess(_ context.Context, row domain
 	r.records[row.PredictionID] = row
 	return nil
 }
+
+type BacktestRepository struct {
+	mu      sync.RWMutex
+	records []domain.BacktestReport
+}
+
+func (r *BacktestRepository) Save(_ context.Context, report domain.BacktestReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, report)
+	return nil
+}
+
+func (r *BacktestRepository) List(_ context.Context) ([]domain.BacktestReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := append([]domain.BacktestReport(nil), r.records...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].RunAt.After(out[j].RunAt) })
+	return out, nil
+}
+
+func (r *BacktestRepository) Latest(_ context.Context, modelVersion string) (*domain.BacktestReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var latest *domain.BacktestReport
+	for i := range r.records {
+		row := r.records[i]
+		if row.ModelVersion != modelVersion || row.Status != domain.BacktestStatusOK {
+			continue
+		}
+		if latest == nil || !row.RunAt.Before(latest.RunAt) {
+			clone := row
+			latest = &clone
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
+)
+
+// WarehouseRepository is an in-memory copy of M54's fact_submissions,
+// fact_clicks, fact_payouts and dim_campaigns tables for tests; deployed
+// processes read M54 through adapters/warehouse. The Add methods load rows
+// the way M54's event worker writes them.
+type WarehouseRepository struct {
+	mu          sync.RWMutex
+	submissions []domain.SubmissionFact
+	clicks      []domain.ClickFact
+	payouts     []domain.PayoutFact
+	campaigns   map[string]domain.CampaignDim
+}
+
+func (r *WarehouseRepository) AddSubmission(row domain.SubmissionFact) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submissions = append(r.submissions, row)
+}
+
+func (r *WarehouseRepository) AddClick(row domain.ClickFact) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clicks = append(r.clicks, row)
+}
+
+func (r *WarehouseRepository) AddPayout(row domain.PayoutFact) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payouts = append(r.payouts, row)
+}
+
+func (r *WarehouseRepository) UpsertCampaign(row domain.CampaignDim) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.campaigns == nil {
+		r.campaigns = map[string]domain.CampaignDim{}
+	}
+	r.campaigns[row.CampaignID] = row
+}
+
+func (r *WarehouseRepository) Submissions(_ context.Context, filter domain.WarehouseFilter) ([]domain.SubmissionFact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.SubmissionFact{}
+	for _, row := range r.submissions {
+		if matches(filter, row.CreatorID, row.CampaignID, row.OccurredAt) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *WarehouseRepository) Clicks(_ context.Context, filter domain.WarehouseFilter) ([]domain.ClickFact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.ClickFact{}
+	for _, row := range r.clicks {
+		if matches(filter, row.UserID, "", row.OccurredAt) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *WarehouseRepository) Payouts(_ context.Context, filter domain.WarehouseFilter) ([]domain.PayoutFact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.PayoutFact{}
+	for _, row := range r.payouts {
+		if matches(filter, row.CreatorID, "", row.OccurredAt) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *WarehouseRepository) Campaigns(_ context.Context, launchedFrom, launchedTo time.Time) ([]domain.CampaignDim, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []domain.CampaignDim{}
+	for _, row := range r.campaigns {
+		if inRange(row.LaunchedAt, launchedFrom, launchedTo) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].LaunchedAt.Equal(out[j].LaunchedAt) {
+			return out[i].LaunchedAt.Before(out[j].LaunchedAt)
+		}
+		return out[i].CampaignID < out[j].CampaignID
+	})
+	return out, nil
+}
+
+func matches(filter domain.WarehouseFilter, creatorID, campaignID string, at time.Time) bool {
+	if filter.CreatorID != "" && filter.CreatorID != creatorID {
+		return false
+	}
+	if filter.CampaignID != "" && filter.CampaignID != campaignID {
+		return false
+	}
+	return inRange(at, filter.From, filter.To)
+}
+
+func inRange(at, from, to time.Time) bool {
+	if !from.IsZero() && at.Before(from) {
+		return false
+	}
+	return to.IsZero() || at.Before(to)
+}
@@ -0,0 +1,179 @@
+// Package warehouse reads M54-Analytics-Service's warehouse facts for
+// ports.WarehouseReader.
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/platform/resiliency"
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
+)
+
+// M54Config points the reader at M54's REST API. Requests are made as
+// Subject with the service role, which M54 requires for warehouse reads.
+// Without a Client, reads are traced and go through m54Policy.
+type M54Config struct {
+	BaseURL string
+	Subject string
+	Client  *http.Client
+}
+
+// m54PageSize rows are requested per page; m54PageBytes bounds one page's
+// response body.
+const (
+	m54PageSize  = 1000
+	m54PageBytes = 8 << 20
+)
+
+// M54Reader serves ports.WarehouseReader from
+// GET {BaseURL}/api/v1/analytics/warehouse/{submissions,clicks,payouts,campaigns}.
+type M54Reader struct {
+	cfg M54Config
+}
+
+func NewM54Reader(cfg M54Config) *M54Reader {
+	cfg.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if cfg.Client == nil {
+		cfg.Client = resiliency.WrapClient(observability.WrapClient(&http.Client{Timeout: 30 * time.Second}), m54Policy())
+	}
+	return &M54Reader{cfg: cfg}
+}
+
+// m54Policy retries a failed page read within a retry budget, sheds reads
+// past the bulkhead, and opens after repeated failures so an M54 outage
+// fails forecasts fast instead of stacking 30s page reads.
+func m54Policy() *resiliency.Policy {
+	return resiliency.NewPolicy(resiliency.PolicyConfig{
+		Name:           "M54",
+		AttemptTimeout: 10 * time.Second,
+		Retry: resiliency.RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: 50 * time.Millisecond,
+			MaxBackoff:     time.Second,
+			Budget:         resiliency.NewRetryBudget(0.2, 1),
+		},
+		Breaker:       &resiliency.BreakerConfig{FailureThreshold: 5, OpenTimeout: 30 * time.Second},
+		MaxConcurrent: 8,
+		MaxWait:       time.Second,
+		Observer:      observability.ResiliencyObserver(),
+	})
+}
+
+func (r *M54Reader) Submissions(ctx context.Context, filter domain.WarehouseFilter) ([]domain.SubmissionFact, error) {
+	return getAll[domain.SubmissionFact](ctx, r, "submissions", filterQuery(filter))
+}
+
+func (r *M54Reader) Clicks(ctx context.Context, filter domain.WarehouseFilter) ([]domain.ClickFact, error) {
+	return getAll[domain.ClickFact](ctx, r, "clicks", filterQuery(filter))
+}
+
+func (r *M54Reader) Payouts(ctx context.Context, filter domain.WarehouseFilter) ([]domain.PayoutFact, error) {
+	return getAll[domain.PayoutFact](ctx, r, "payouts", filterQuery(filter))
+}
+
+func (r *M54Reader) Campaigns(ctx context.Context, launchedFrom, launchedTo time.Time) ([]domain.CampaignDim, error) {
+	return getAll[domain.CampaignDim](ctx, r, "campaigns", filterQuery(domain.WarehouseFilter{From: launchedFrom, To: launchedTo}))
+}
+
+type m54Envelope struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type m54Page struct {
+	Items      json.RawMessage `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+// getAll reads a resource page by page, following next_cursor until M54
+// reports the last page.
+func getAll[T any](ctx context.Context, r *M54Reader, resource string, query url.Values) ([]T, error) {
+	query.Set("limit", strconv.Itoa(m54PageSize))
+	rows := []T{}
+	for {
+		page, err := r.get(ctx, resource, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Items) > 0 && string(page.Items) != "null" {
+			var items []T
+			if err := json.Unmarshal(page.Items, &items); err != nil {
+				return nil, fmt.Errorf("%w: malformed %s response", domain.ErrWarehouseUnavailable, resource)
+			}
+			rows = append(rows, items...)
+		}
+		if page.NextCursor == "" {
+			return rows, nil
+		}
+		if page.NextCursor == query.Get("cursor") {
+			return nil, fmt.Errorf("%w: M54 repeated the %s cursor", domain.ErrWarehouseUnavailable, resource)
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+}
+
+func (r *M54Reader) get(ctx context.Context, resource string, query url.Values) (m54Page, error) {
+	target := r.cfg.BaseURL + "/api/v1/analytics/warehouse/" + resource
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return m54Page{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.cfg.Subject)
+	req.Header.Set("X-Actor-Role", "service")
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return m54Page{}, ctx.Err()
+		}
+		return m54Page{}, fmt.Errorf("%w: %v", domain.ErrWarehouseUnavailable, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, m54PageBytes))
+	if err != nil {
+		return m54Page{}, fmt.Errorf("%w: %v", domain.ErrWarehouseUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return m54Page{}, fmt.Errorf("%w: M54 returned %d for %s", domain.ErrWarehouseUnavailable, resp.StatusCode, resource)
+	}
+	var envelope m54Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return m54Page{}, fmt.Errorf("%w: malformed %s response", domain.ErrWarehouseUnavailable, resource)
+	}
+	var page m54Page
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return page, nil
+	}
+	if err := json.Unmarshal(envelope.Data, &page); err != nil {
+		return m54Page{}, fmt.Errorf("%w: malformed %s response", domain.ErrWarehouseUnavailable, resource)
+	}
+	return page, nil
+}
+
+func filterQuery(filter domain.WarehouseFilter) url.Values {
+	query := url.Values{}
+	if filter.CreatorID != "" {
+		query.Set("creator_id", filter.CreatorID)
+	}
+	if filter.CampaignID != "" {
+		query.Set("campaign_id", filter.CampaignID)
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.UTC().Format(time.RFC3339Nano))
+	}
+	return query
+}
@@ -1,10 +1,20 @@
 package bootstrap
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+
+	"github.com/viralforge/mesh/platform/observability"
+)
 
 // Build wires runtime dependencies for this service and starts the API server.
 func Build() error {
 	cfg := loadConfig()
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv(serviceName, ""))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
 	runtime := NewRuntime(cfg)
 	return http.ListenAndServe(runtime.Addr, runtime.Router)
 }
@@ -8,6 +8,8 @@ import (
 
 type Config struct {
 	HTTPPort string
+	// M54AnalyticsURL is where the warehouse facts are read from.
+	M54AnalyticsURL string
 }
 
 func loadConfig() Config {
@@ -18,5 +20,9 @@ func loadConfig() Config {
 	if _, err := strconv.Atoi(strings.TrimPrefix(port, ":")); err != nil {
 		port = "8080"
 	}
-	return Config{HTTPPort: port}
+	m54URL := strings.TrimSpace(os.Getenv("M54_ANALYTICS_API_URL"))
+	if m54URL == "" {
+		m54URL = "http://m54-analytics-service:8080"
+	}
+	return Config{HTTPPort: port, M54AnalyticsURL: m54URL}
 }
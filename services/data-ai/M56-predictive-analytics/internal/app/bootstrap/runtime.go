@@ -4,11 +4,15 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/viralforge/mesh/platform/observability"
 	httpadapter "github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/adapters/http"
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/adapters/warehouse"
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/application"
 )
 
+const serviceName = "M56-Predictive-Analytics"
+
 type Runtime struct {
 	Router http.Handler
 	Addr   string
@@ -18,14 +22,16 @@ func NewRuntime(cfg Config) *Runtime {
 	repos := postgres.NewRepositories()
 	service := application.NewService(application.Dependencies{
 		Config: application.Config{
-			ServiceName: "M56-Predictive-Analytics",
+			ServiceName: serviceName,
 		},
 		Idempotency: repos.Idempotency,
 		Predictions: repos.Predictions,
+		Backtests:   repos.Backtests,
+		Warehouse:   warehouse.NewM54Reader(warehouse.M54Config{BaseURL: cfg.M54AnalyticsURL, Subject: serviceName}),
 	})
 	handler := httpadapter.NewHandler(service)
 	return &Runtime{
-		Router: httpadapter.NewRouter(handler),
+		Router: observability.HTTPMiddleware(serviceName)(httpadapter.NewRouter(handler)),
 		Addr:   normalizeAddr(cfg.HTTPPort),
 	}
 }
@@ -7,36 +7,66 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
 )
 
-func (s *Service) GetViewForecast(_ context.Context, actor Actor, input ViewForecastInput) (domain.ViewForecast, error) {
+const (
+	// intervalZ gives the 90% prediction interval the spec asks for.
+	intervalZ = 1.645
+	// weeklyPeriod is the seasonality of daily views (day of week).
+	weeklyPeriod = 7
+	// recommendationLookback bounds which submissions can be recommended.
+	recommendationLookback = 90 * 24 * time.Hour
+)
+
+// GetViewForecast fits Holt-Winters to the creator's daily views and
+// forecasts the total for the window with a 90% prediction interval.
+func (s *Service) GetViewForecast(ctx context.Context, actor Actor, input ViewForecastInput) (domain.ViewForecast, error) {
 	userID, err := resolveUser(actor, input.UserID)
 	if err != nil {
 		return domain.ViewForecast{}, err
 	}
 	window := normalizeWindow(input.WindowDays)
-	base := stableScore(userID)
-	views := int(float64(window) * (1200 + base*1800))
-	low := int(float64(views) * 0.85)
-	high := int(float64(views) * 1.15)
-	confidence := clamp(0.55+base*0.4, 0, 0.99)
+	now := s.nowFn()
+	series, err := s.viewHistory(ctx, userID, now)
+	if err != nil {
+		return domain.ViewForecast{}, err
+	}
+	model, err := domain.FitHoltWinters(series, weeklyPeriod)
+	if err != nil {
+		return domain.ViewForecast{}, err
+	}
+	point, low, high := model.ForecastTotal(window, intervalZ)
+	point, low = math.Max(point, 0), math.Max(low, 0)
+	// Confidence is how tight the interval is relative to the forecast.
+	confidence := clamp(1-(high-low)/(2*math.Max(point, 1)), 0, 0.99)
 
-	return domain.ViewForecast{
+	version := s.modelVersion(domain.ModelViewForecast)
+	forecast := domain.ViewForecast{
 		UserID:            userID,
 		ForecastWindow:    fmt.Sprintf("%dd", window),
-		ForecastViews:     views,
-		ForecastViewsLow:  low,
-		ForecastViewsHigh: high,
-		ConfidenceScore:   confidence,
-		ModelVersion:      s.cfg.ModelVersion,
-		GeneratedAt:       s.nowFn(),
-	}, nil
+		ForecastViews:     int(math.Round(point)),
+		ForecastViewsLow:  int(math.Round(low)),
+		ForecastViewsHigh: int(math.Round(high)),
+		ConfidenceScore:   round(confidence, 4),
+		HistoryDays:       len(series),
+		Seasonal:          model.Period > 0,
+		ModelVersion:      version,
+		GeneratedAt:       now,
+	}
+	if report, err := s.backtests.Latest(ctx, version); err == nil && report != nil {
+		forecast.BacktestMAPE = &report.Value
+	}
+	return forecast, nil
 }
 
-func (s *Service) GetClipRecommendations(_ context.Context, actor Actor, input ClipRecommendationsInput) ([]domain.ClipRecommendation, error) {
+// GetClipRecommendations ranks the creator's recent submissions by how far
+// they outperformed the creator's median.
+func (s *Service) GetClipRecommendations(ctx context.Context, actor Actor, input ClipRecommendationsInput) ([]domain.ClipRecommendation, error) {
 	userID, err := resolveUser(actor, input.UserID)
 	if err != nil {
 		return nil, err
@@ -48,42 +78,82 @@ func (s *Service) GetClipRecommendations(_ context.Context, actor Actor, input C
 	if limit > 10 {
 		limit = 10
 	}
-	base := stableScore(userID)
+	now := s.nowFn()
+	subs, err := s.warehouse.Submissions(ctx, domain.WarehouseFilter{CreatorID: userID, From: now.Add(-recommendationLookback), To: now})
+	if err != nil {
+		return nil, err
+	}
 	out := make([]domain.ClipRecommendation, 0, limit)
-	for i := 0; i < limit; i++ {
-		score := clamp(base-0.08*float64(i), 0.25, 0.99)
+	if len(subs) == 0 {
+		return out, nil
+	}
+	views := make([]float64, len(subs))
+	for i, sub := range subs {
+		views[i] = float64(sub.Views)
+	}
+	median := math.Max(percentile(views, 0.5), 1)
+	sort.SliceStable(subs, func(i, j int) bool {
+		if subs[i].Views != subs[j].Views {
+			return subs[i].Views > subs[j].Views
+		}
+		return subs[i].OccurredAt.After(subs[j].OccurredAt)
+	})
+	for _, sub := range subs {
+		if len(out) == limit {
+			break
+		}
+		lift := float64(sub.Views) / median
 		out = append(out, domain.ClipRecommendation{
-			SourceID:      fmt.Sprintf("src_%s_%02d", sanitizeID(userID), i+1),
-			Score:         score,
-			Reason:        recommendationReason(i),
-			ExpectedViews: int(8000 + score*18000),
+			SourceID: sub.SubmissionID,
+			Score:    round(lift/(1+lift), 4),
+			Reason:   recommendationReason(lift, sub.Platform),
+			// Repeat performance regresses towards the creator's median.
+			ExpectedViews: int(median * math.Sqrt(lift)),
 		})
 	}
 	return out, nil
 }
 
-func (s *Service) GetChurnRisk(_ context.Context, actor Actor, input ChurnRiskInput) (domain.ChurnRisk, error) {
+// GetChurnRisk scores the creator with the churn model fitted on the
+// warehouse's engagement history.
+func (s *Service) GetChurnRisk(ctx context.Context, actor Actor, input ChurnRiskInput) (domain.ChurnRisk, error) {
 	userID, err := resolveUser(actor, input.UserID)
 	if err != nil {
 		return domain.ChurnRisk{}, err
 	}
-	score := clamp(1.0-stableScore(userID), 0.05, 0.95)
-	level := "Low"
-	action := "Maintain engagement cadence"
-	if score >= 0.67 {
-		level = "High"
-		action = "Trigger retention workflow"
-	} else if score >= 0.33 {
-		level = "Medium"
-		action = "Send nudges and incentive campaign"
-	}
-	return domain.ChurnRisk{
-		UserID:            userID,
-		ChurnRiskLevel:    level,
-		ChurnRiskScore:    score,
-		RecommendedAction: action,
-		GeneratedAt:       s.nowFn(),
-	}, nil
+	now := s.nowFn()
+	trained, err := s.churnModel(ctx, now)
+	if err != nil {
+		return domain.ChurnRisk{}, err
+	}
+	activity, err := s.creatorActivity(ctx, domain.WarehouseFilter{CreatorID: userID, From: now.Add(-domain.ChurnLookback), To: now})
+	if err != nil {
+		return domain.ChurnRisk{}, err
+	}
+	version := s.modelVersion(domain.ModelChurn)
+	risk := domain.ChurnRisk{UserID: userID, ModelVersion: version, GeneratedAt: now}
+	history := domain.CreatorActivity{}
+	if a := activity[userID]; a != nil {
+		history = *a
+	}
+	if row, ok := domain.ChurnFeatures(history, now); ok {
+		risk.ChurnRiskScore = round(clamp(trained.model.Predict(row), 0.01, 0.99), 4)
+		risk.Drivers = roundMap(trained.model.Contributions(row))
+	} else {
+		// No activity in the lookback window: the creator has already
+		// churned by the model's own definition.
+		risk.ChurnRiskScore = 0.99
+	}
+	risk.ChurnRiskLevel, risk.RecommendedAction = "Low", "Maintain engagement cadence"
+	if risk.ChurnRiskScore >= 0.67 {
+		risk.ChurnRiskLevel, risk.RecommendedAction = "High", "Trigger retention workflow"
+	} else if risk.ChurnRiskScore >= 0.33 {
+		risk.ChurnRiskLevel, risk.RecommendedAction = "Medium", "Send nudges and incentive campaign"
+	}
+	if report, err := s.backtests.Latest(ctx, version); err == nil && report != nil {
+		risk.BacktestAUC = &report.Value
+	}
+	return risk, nil
 }
 
 func (s *Service) PredictCampaignSuccess(ctx context.Context, actor Actor, input CampaignSuccessInput) (domain.CampaignSuccessPrediction, error) {
@@ -113,17 +183,18 @@ func (s *Service) PredictCampaignSuccess(ctx context.Context, actor Actor, input
 		}
 		return cached, nil
 	}
+	// Fit before reserving the key, so a model that cannot be fitted yet
+	// does not leave the key reserved without a response.
+	trained, err := s.campaignModel(ctx, now)
+	if err != nil {
+		return domain.CampaignSuccessPrediction{}, err
+	}
 	if err := s.idempotency.Reserve(ctx, actor.IdempotencyKey, requestHash, now.Add(s.cfg.IdempotencyTTL)); err != nil {
 		return domain.CampaignSuccessPrediction{}, err
 	}
 
-	rewardComponent := clamp(input.RewardRate/3.0, 0, 1)
-	budgetComponent := clamp(math.Log10(input.Budget+1)/4.0, 0, 1)
-	nicheComponent := 0.6
-	if strings.EqualFold(strings.TrimSpace(input.Niche), "gaming") || strings.EqualFold(strings.TrimSpace(input.Niche), "beauty") {
-		nicheComponent = 0.72
-	}
-	likelihood := clamp(rewardComponent*0.35+budgetComponent*0.40+nicheComponent*0.25, 0.01, 0.99)
+	row := domain.CampaignFeatures(input.RewardRate, input.Budget, trained.rates.Rate(input.Niche, nil))
+	likelihood := clamp(trained.model.Predict(row), 0.01, 0.99)
 	prediction := "Low"
 	advice := "Increase reward rate or budget to improve projected success."
 	if likelihood >= 0.8 {
@@ -133,26 +204,47 @@ func (s *Service) PredictCampaignSuccess(ctx context.Context, actor Actor, input
 		prediction = "Medium"
 		advice = "Small reward-rate increase can push this campaign above 80%."
 	}
-	row := domain.CampaignSuccessPrediction{
-		PredictionID:      fmt.Sprintf("pred_%d", now.UnixNano()),
-		CampaignID:        strings.TrimSpace(input.CampaignID),
-		SuccessLikelihood: likelihood,
-		SuccessPrediction: prediction,
-		Advice:            advice,
-		ModelVersion:      s.cfg.ModelVersion,
-		GeneratedAt:       now,
+	version := s.modelVersion(domain.ModelCampaignSuccess)
+	result := domain.CampaignSuccessPrediction{
+		PredictionID:        fmt.Sprintf("pred_%d", now.UnixNano()),
+		CampaignID:          strings.TrimSpace(input.CampaignID),
+		SuccessLikelihood:   round(likelihood, 4),
+		SuccessPrediction:   prediction,
+		Advice:              advice,
+		ComparableCampaigns: int(trained.rates.Totals[strings.ToLower(strings.TrimSpace(input.Niche))]),
+		ModelVersion:        version,
+		GeneratedAt:         now,
+	}
+	if report, err := s.backtests.Latest(ctx, version); err == nil && report != nil {
+		result.BacktestAUC = &report.Value
 	}
-	if err := s.predictions.SaveCampaignSuccess(ctx, row); err != nil {
+	if err := s.predictions.SaveCampaignSuccess(ctx, result); err != nil {
 		return domain.CampaignSuccessPrediction{}, err
 	}
-	encoded, err := json.Marshal(row)
+	encoded, err := json.Marshal(result)
 	if err != nil {
 		return domain.CampaignSuccessPrediction{}, err
 	}
 	if err := s.idempotency.Complete(ctx, actor.IdempotencyKey, 200, encoded, now); err != nil {
 		return domain.CampaignSuccessPrediction{}, err
 	}
-	return row, nil
+	return result, nil
+}
+
+// viewHistory returns the creator's daily views up to yesterday, starting
+// at their first day with views.
+func (s *Service) viewHistory(ctx context.Context, creatorID string, now time.Time) ([]float64, error) {
+	end := domain.StartOfDay(now)
+	start := end.AddDate(0, 0, -s.cfg.HistoryDays)
+	subs, err := s.warehouse.Submissions(ctx, domain.WarehouseFilter{CreatorID: creatorID, From: start, To: end})
+	if err != nil {
+		return nil, err
+	}
+	return domain.TrimLeadingZeros(domain.DailyViews(subs, start, s.cfg.HistoryDays)), nil
+}
+
+func (s *Service) modelVersion(kind domain.ModelKind) string {
+	return kind.ModelName() + "@" + s.cfg.ModelVersion
 }
 
 func resolveUser(actor Actor, requested string) (string, error) {
@@ -170,16 +262,19 @@ func resolveUser(actor Actor, requested string) (string, error) {
 	return requested, nil
 }
 
-func recommendationReason(index int) string {
-	reasons := []string{
-		"High viral potential from recent trajectory",
-		"Strong niche alignment with your history",
-		"Similar profile to your top performers",
+func recommendationReason(lift float64, platform string) string {
+	platform = strings.TrimSpace(platform)
+	if platform == "" {
+		platform = "your channels"
 	}
-	if index < len(reasons) {
-		return reasons[index]
+	switch {
+	case lift >= 2:
+		return fmt.Sprintf("Drew %.1fx your median views on %s", lift, platform)
+	case lift >= 1:
+		return fmt.Sprintf("Above your median views on %s", platform)
+	default:
+		return fmt.Sprintf("Recent %s clip with steady views", platform)
 	}
-	return "High expected upside based on model features"
 }
 
 func normalizeWindow(window int) int {
@@ -191,13 +286,6 @@ func normalizeWindow(window int) int {
 	}
 }
 
-func stableScore(seed string) float64 {
-	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(seed))))
-	// Use two bytes for deterministic fractional spread.
-	value := float64(int(sum[0])<<8|int(sum[1])) / 65535.0
-	return clamp(value, 0.1, 0.95)
-}
-
 func hashPayload(v interface{}) string {
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -217,15 +305,23 @@ func clamp(value, min, max float64) float64 {
 	return value
 }
 
-func sanitizeID(raw string) string {
-	raw = strings.ToLower(strings.TrimSpace(raw))
-	raw = strings.ReplaceAll(raw, "-", "")
-	raw = strings.ReplaceAll(raw, "_", "")
-	if raw == "" {
-		return "anon"
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
+
+func roundMap(values map[string]float64) map[string]float64 {
+	for k, v := range values {
+		values[k] = round(v, 4)
 	}
-	if len(raw) > 12 {
-		return raw[:12]
+	return values
+}
+
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
 	}
-	return raw
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted[int(math.Round(p*float64(len(sorted)-1)))]
 }
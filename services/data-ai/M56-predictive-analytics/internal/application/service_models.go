@@ -0,0 +1,403 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
+)
+
+const (
+	// churnCutoffs weekly snapshots are labelled for churn training; the
+	// newest is one churn horizon ago, so every label is observed.
+	churnCutoffs    = 20
+	churnMinRows    = 20
+	campaignMinRows = 10
+	// campaignHistory bounds which launches campaign models learn from.
+	campaignHistory = 365 * 24 * time.Hour
+	// forecastBacktestOrigins rolling origins, a week apart, are replayed
+	// per creator; each forecasts the following week.
+	forecastBacktestOrigins = 4
+	forecastBacktestHorizon = 7
+	forecastBacktestMinFit  = 4 * weeklyPeriod
+	// campaignTrainShare of campaigns, oldest first, train the campaign
+	// backtest; the newest are held out.
+	campaignTrainShare = 0.75
+	// fitFailureRetry is how long a failed churn or campaign fit is served
+	// from the cache before it is attempted again.
+	fitFailureRetry = 5 * time.Minute
+)
+
+type churnRow struct {
+	cutoff time.Time
+	x      []float64
+	y      bool
+}
+
+// churnModel returns the cached churn model, refitting it once it is older
+// than the retrain interval.
+func (s *Service) churnModel(ctx context.Context, now time.Time) (trainedModel, error) {
+	return s.cachedModel(domain.ModelChurn, now, func() (trainedModel, error) {
+		rows, err := s.churnDataset(ctx, now)
+		if err != nil {
+			return trainedModel{}, err
+		}
+		x, y := splitChurnRows(rows)
+		model, err := domain.FitLogistic(domain.ChurnFeatureNames, x, y, churnMinRows)
+		if err != nil {
+			return trainedModel{}, err
+		}
+		return trainedModel{model: model, rows: len(x), trainedAt: now}, nil
+	})
+}
+
+// campaignModel returns the cached campaign-success model, refitting it
+// once it is older than the retrain interval.
+func (s *Service) campaignModel(ctx context.Context, now time.Time) (trainedModel, error) {
+	return s.cachedModel(domain.ModelCampaignSuccess, now, func() (trainedModel, error) {
+		outcomes, err := s.campaignOutcomes(ctx, now)
+		if err != nil {
+			return trainedModel{}, err
+		}
+		rates := domain.NewCategoryRates(outcomes)
+		x, y := campaignRows(outcomes, rates, true)
+		model, err := domain.FitLogistic(domain.CampaignFeatureNames, x, y, campaignMinRows)
+		if err != nil {
+			return trainedModel{}, err
+		}
+		return trainedModel{model: model, rates: rates, rows: len(x), trainedAt: now}, nil
+	})
+}
+
+// cachedModel serves the cached fit of kind, refitting it once it is older
+// than the retrain interval. A failed fit is cached too, for
+// fitFailureRetry, so requests against a thin or unreachable warehouse do
+// not each pull the training set again.
+func (s *Service) cachedModel(kind domain.ModelKind, now time.Time, fit func() (trainedModel, error)) (trainedModel, error) {
+	s.modelsMu.Lock()
+	defer s.modelsMu.Unlock()
+	if failed, ok := s.fitFailures[kind]; ok && now.Sub(failed.at) < fitFailureRetry {
+		return trainedModel{}, failed.err
+	}
+	if cached, ok := s.models[kind]; ok && now.Sub(cached.trainedAt) < s.cfg.RetrainInterval {
+		return cached, nil
+	}
+	trained, err := fit()
+	if err != nil {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			s.fitFailures[kind] = fitFailure{err: err, at: now}
+		}
+		return trainedModel{}, err
+	}
+	delete(s.fitFailures, kind)
+	s.models[kind] = trained
+	return trained, nil
+}
+
+// churnDataset labels every recently active creator at weekly cutoffs: the
+// features use activity before the cutoff, and the label is whether the
+// creator then stayed inactive for a full churn horizon.
+func (s *Service) churnDataset(ctx context.Context, now time.Time) ([]churnRow, error) {
+	newest := domain.StartOfDay(now).Add(-domain.ChurnHorizon)
+	oldest := newest.AddDate(0, 0, -7*(churnCutoffs-1))
+	activity, err := s.creatorActivity(ctx, domain.WarehouseFilter{From: oldest.Add(-domain.ChurnLookback), To: now})
+	if err != nil {
+		return nil, err
+	}
+	creators := make([]string, 0, len(activity))
+	for id := range activity {
+		creators = append(creators, id)
+	}
+	sort.Strings(creators)
+	rows := []churnRow{}
+	for k := 0; k < churnCutoffs; k++ {
+		cutoff := newest.AddDate(0, 0, -7*k)
+		for _, id := range creators {
+			x, ok := domain.ChurnFeatures(*activity[id], cutoff)
+			if !ok {
+				continue
+			}
+			rows = append(rows, churnRow{cutoff: cutoff, x: x, y: !activity[id].ActiveBetween(cutoff, cutoff.Add(domain.ChurnHorizon))})
+		}
+	}
+	return rows, nil
+}
+
+func (s *Service) creatorActivity(ctx context.Context, filter domain.WarehouseFilter) (map[string]*domain.CreatorActivity, error) {
+	subs, err := s.warehouse.Submissions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	clicks, err := s.warehouse.Clicks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := s.warehouse.Payouts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return domain.GroupActivity(subs, clicks, payouts), nil
+}
+
+func (s *Service) campaignOutcomes(ctx context.Context, now time.Time) ([]domain.CampaignOutcome, error) {
+	from := now.Add(-campaignHistory)
+	campaigns, err := s.warehouse.Campaigns(ctx, from, now)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.warehouse.Submissions(ctx, domain.WarehouseFilter{From: from, To: now})
+	if err != nil {
+		return nil, err
+	}
+	return domain.CampaignOutcomes(campaigns, subs, now), nil
+}
+
+func splitChurnRows(rows []churnRow) ([][]float64, []bool) {
+	x := make([][]float64, len(rows))
+	y := make([]bool, len(rows))
+	for i, row := range rows {
+		x[i], y[i] = row.x, row.y
+	}
+	return x, y
+}
+
+// campaignRows builds feature rows. Training rows leave their own outcome
+// out of the category rate.
+func campaignRows(outcomes []domain.CampaignOutcome, rates domain.CategoryRates, training bool) ([][]float64, []bool) {
+	x := make([][]float64, len(outcomes))
+	y := make([]bool, len(outcomes))
+	for i := range outcomes {
+		o := outcomes[i]
+		var exclude *domain.CampaignOutcome
+		if training {
+			exclude = &o
+		}
+		x[i] = domain.CampaignFeatures(o.Campaign.RewardRate, o.Campaign.Budget, rates.Rate(o.Campaign.Category, exclude))
+		y[i] = o.Succeeded
+	}
+	return x, y
+}
+
+// RunBacktests scores every model out of sample on warehouse history and
+// stores one report per model version.
+func (s *Service) RunBacktests(ctx context.Context, actor Actor) ([]domain.BacktestReport, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return nil, domain.ErrForbidden
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return nil, domain.ErrIdempotencyRequired
+	}
+	requestHash := hashPayload(map[string]string{"operation": "run_backtests", "model_release": s.cfg.ModelVersion})
+	now := s.nowFn()
+	existing, err := s.idempotency.Get(ctx, actor.IdempotencyKey, now)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.RequestHash != requestHash {
+			return nil, domain.ErrIdempotencyConflict
+		}
+		var cached []domain.BacktestReport
+		if err := json.Unmarshal(existing.ResponseBody, &cached); err != nil {
+			return nil, err
+		}
+		return cached, nil
+	}
+	if err := s.idempotency.Reserve(ctx, actor.IdempotencyKey, requestHash, now.Add(s.cfg.IdempotencyTTL)); err != nil {
+		return nil, err
+	}
+
+	reports := make([]domain.BacktestReport, 0, 3)
+	for _, run := range []func(context.Context, time.Time) (domain.BacktestReport, error){
+		s.backtestViewForecast,
+		s.backtestChurn,
+		s.backtestCampaignSuccess,
+	} {
+		report, err := run(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		report.ReportID = fmt.Sprintf("bt_%s_%d", report.ModelKind, now.UnixNano())
+		report.ModelVersion = s.modelVersion(report.ModelKind)
+		report.RunAt = now
+		if report.Status == "" {
+			report.Status = domain.BacktestStatusOK
+		}
+		if err := s.backtests.Save(ctx, report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	encoded, err := json.Marshal(reports)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.idempotency.Complete(ctx, actor.IdempotencyKey, 200, encoded, now); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (s *Service) ListBacktests(ctx context.Context, actor Actor, input ListBacktestsInput) ([]domain.BacktestReport, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return nil, domain.ErrForbidden
+	}
+	rows, err := s.backtests.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version := strings.TrimSpace(input.ModelVersion)
+	out := make([]domain.BacktestReport, 0, len(rows))
+	for _, row := range rows {
+		if version == "" || row.ModelVersion == version {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// backtestViewForecast replays weekly rolling origins per creator: fit on
+// the history before the origin, forecast the next week's total and
+// compare it with what the warehouse recorded.
+func (s *Service) backtestViewForecast(ctx context.Context, now time.Time) (domain.BacktestReport, error) {
+	report := domain.BacktestReport{ModelKind: domain.ModelViewForecast, Metric: "mape"}
+	end := domain.StartOfDay(now)
+	start := end.AddDate(0, 0, -s.cfg.HistoryDays)
+	subs, err := s.warehouse.Submissions(ctx, domain.WarehouseFilter{From: start, To: end})
+	if err != nil {
+		return report, err
+	}
+	byCreator := map[string][]domain.SubmissionFact{}
+	for _, sub := range subs {
+		byCreator[sub.CreatorID] = append(byCreator[sub.CreatorID], sub)
+	}
+	creators := make([]string, 0, len(byCreator))
+	for id := range byCreator {
+		creators = append(creators, id)
+	}
+	sort.Strings(creators)
+	if len(creators) > s.cfg.BacktestCreators {
+		creators = creators[:s.cfg.BacktestCreators]
+	}
+
+	var actual, predicted []float64
+	covered := 0
+	for _, id := range creators {
+		series := domain.TrimLeadingZeros(domain.DailyViews(byCreator[id], start, s.cfg.HistoryDays))
+		for k := 1; k <= forecastBacktestOrigins; k++ {
+			origin := len(series) - k*forecastBacktestHorizon
+			if origin < forecastBacktestMinFit {
+				break
+			}
+			model, err := domain.FitHoltWinters(series[:origin], weeklyPeriod)
+			if err != nil {
+				continue
+			}
+			point, low, high := model.ForecastTotal(forecastBacktestHorizon, intervalZ)
+			observed := 0.0
+			for _, v := range series[origin : origin+forecastBacktestHorizon] {
+				observed += v
+			}
+			if observed == 0 {
+				continue
+			}
+			actual = append(actual, observed)
+			predicted = append(predicted, math.Max(point, 0))
+			if observed >= low && observed <= high {
+				covered++
+			}
+		}
+	}
+	mape, n := domain.MAPE(actual, predicted)
+	if n == 0 {
+		report.Status = domain.BacktestStatusInsufficientData
+		return report, nil
+	}
+	coverage := round(float64(covered)/float64(n), 4)
+	report.Value, report.Samples, report.Coverage = round(mape, 4), n, &coverage
+	return report, nil
+}
+
+// backtestChurn trains on cutoffs whose labels were already known at the
+// newest cutoff and reports AUC on the newest cutoff's creators.
+func (s *Service) backtestChurn(ctx context.Context, now time.Time) (domain.BacktestReport, error) {
+	report := domain.BacktestReport{ModelKind: domain.ModelChurn, Metric: "auc"}
+	rows, err := s.churnDataset(ctx, now)
+	if err != nil {
+		return report, err
+	}
+	newest := domain.StartOfDay(now).Add(-domain.ChurnHorizon)
+	var train, test []churnRow
+	for _, row := range rows {
+		switch {
+		case row.cutoff.Equal(newest):
+			test = append(test, row)
+		case !row.cutoff.Add(domain.ChurnHorizon).After(newest):
+			train = append(train, row)
+		}
+	}
+	x, y := splitChurnRows(train)
+	model, err := domain.FitLogistic(domain.ChurnFeatureNames, x, y, churnMinRows)
+	if err != nil {
+		report.Status = domain.BacktestStatusInsufficientData
+		return report, nil
+	}
+	testX, testY := splitChurnRows(test)
+	return classifierReport(report, model, testX, testY), nil
+}
+
+// backtestCampaignSuccess trains on the oldest campaigns and reports AUC
+// on the newest, with category rates taken from the training campaigns
+// only.
+func (s *Service) backtestCampaignSuccess(ctx context.Context, now time.Time) (domain.BacktestReport, error) {
+	report := domain.BacktestReport{ModelKind: domain.ModelCampaignSuccess, Metric: "auc"}
+	outcomes, err := s.campaignOutcomes(ctx, now)
+	if err != nil {
+		return report, err
+	}
+	split := int(float64(len(outcomes)) * campaignTrainShare)
+	train, test := outcomes[:split], outcomes[split:]
+	rates := domain.NewCategoryRates(train)
+	x, y := campaignRows(train, rates, true)
+	model, err := domain.FitLogistic(domain.CampaignFeatureNames, x, y, campaignMinRows)
+	if err != nil {
+		report.Status = domain.BacktestStatusInsufficientData
+		return report, nil
+	}
+	testX, testY := campaignRows(test, rates, false)
+	return classifierReport(report, model, testX, testY), nil
+}
+
+func classifierReport(report domain.BacktestReport, model domain.LogisticModel, x [][]float64, y []bool) domain.BacktestReport {
+	positives := 0
+	scores := make([]float64, len(x))
+	for i := range x {
+		scores[i] = model.Predict(x[i])
+		if y[i] {
+			positives++
+		}
+	}
+	if positives == 0 || positives == len(y) {
+		// AUC needs both classes in the held-out set.
+		report.Status = domain.BacktestStatusInsufficientData
+		return report
+	}
+	report.Value, report.Samples = round(domain.AUC(scores, y), 4), len(x)
+	return report
+}
+
+func isAdmin(actor Actor) bool {
+	return strings.EqualFold(strings.TrimSpace(actor.Role), "admin")
+}
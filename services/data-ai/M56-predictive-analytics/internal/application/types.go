@@ -1,15 +1,26 @@
 package application
 
 import (
+	"sync"
 	"time"
 
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/ports"
 )
 
 type Config struct {
 	ServiceName    string
 	IdempotencyTTL time.Duration
-	ModelVersion   string
+	// ModelVersion is the model release; each model reports its version as
+	// <algorithm>@<release>, and backtests are kept per version.
+	ModelVersion string
+	// HistoryDays is how much daily view history forecasts fit on.
+	HistoryDays int
+	// RetrainInterval is how long a fitted churn or campaign model is
+	// reused before it is refitted from the warehouse.
+	RetrainInterval time.Duration
+	// BacktestCreators caps how many creators a forecast backtest replays.
+	BacktestCreators int
 }
 
 type Actor struct {
@@ -40,11 +51,35 @@ type CampaignSuccessInput struct {
 	Niche      string
 }
 
+type ListBacktestsInput struct {
+	ModelVersion string
+}
+
+// trainedModel is a fitted classifier and the state it was fitted with.
+type trainedModel struct {
+	model     domain.LogisticModel
+	rates     domain.CategoryRates
+	rows      int
+	trainedAt time.Time
+}
+
+type fitFailure struct {
+	err error
+	at  time.Time
+}
+
 type Service struct {
 	cfg         Config
 	idempotency ports.IdempotencyRepository
 	predictions ports.PredictionRepository
+	backtests   ports.BacktestRepository
+	warehouse   ports.WarehouseReader
 	nowFn       func() time.Time
+
+	modelsMu sync.Mutex
+	models   map[domain.ModelKind]trainedModel
+	// fitFailures holds the last failed fit per model, see cachedModel.
+	fitFailures map[domain.ModelKind]fitFailure
 }
 
 type Dependencies struct {
@@ -52,6 +87,8 @@ type Dependencies struct {
 
 	Idempotency ports.IdempotencyRepository
 	Predictions ports.PredictionRepository
+	Backtests   ports.BacktestRepository
+	Warehouse   ports.WarehouseReader
 }
 
 func NewService(deps Dependencies) *Service {
@@ -63,12 +100,25 @@ func NewService(deps Dependencies) *Service {
 		cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
 	if cfg.ModelVersion == "" {
-		cfg.ModelVersion = "v1.0.0"
+		cfg.ModelVersion = "v2.0.0"
+	}
+	if cfg.HistoryDays <= 0 {
+		cfg.HistoryDays = 180
+	}
+	if cfg.RetrainInterval <= 0 {
+		cfg.RetrainInterval = time.Hour
+	}
+	if cfg.BacktestCreators <= 0 {
+		cfg.BacktestCreators = 200
 	}
 	return &Service{
 		cfg:         cfg,
 		idempotency: deps.Idempotency,
 		predictions: deps.Predictions,
+		backtests:   deps.Backtests,
+		warehouse:   deps.Warehouse,
 		nowFn:       func() time.Time { return time.Now().UTC() },
+		models:      map[domain.ModelKind]trainedModel{},
+		fitFailures: map[domain.ModelKind]fitFailure{},
 	}
 }
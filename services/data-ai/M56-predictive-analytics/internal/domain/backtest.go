@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+type ModelKind string
+
+const (
+	ModelViewForecast    ModelKind = "view_forecast"
+	ModelChurn           ModelKind = "churn"
+	ModelCampaignSuccess ModelKind = "campaign_success"
+)
+
+// ModelName is the algorithm behind each kind; a model version is the
+// name plus the service's model release, e.g. holt_winters@v2.0.0.
+func (k ModelKind) ModelName() string {
+	switch k {
+	case ModelViewForecast:
+		return "holt_winters"
+	case ModelChurn:
+		return "churn_logreg"
+	case ModelCampaignSuccess:
+		return "campaign_logreg"
+	default:
+		return string(k)
+	}
+}
+
+const (
+	BacktestStatusOK               = "ok"
+	BacktestStatusInsufficientData = "insufficient_data"
+)
+
+// BacktestReport is one model version's out-of-sample score. Forecasts
+// report MAPE (lower is better) and their 90% interval coverage;
+// classifiers report AUC (higher is better).
+type BacktestReport struct {
+	ReportID     string    `json:"report_id"`
+	ModelKind    ModelKind `json:"model_kind"`
+	ModelVersion string    `json:"model_version"`
+	Metric       string    `json:"metric"`
+	Value        float64   `json:"value"`
+	Coverage     *float64  `json:"interval_coverage,omitempty"`
+	Samples      int       `json:"samples"`
+	Status       string    `json:"status"`
+	RunAt        time.Time `json:"run_at"`
+}
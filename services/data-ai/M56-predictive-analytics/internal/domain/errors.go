@@ -3,9 +3,11 @@ package domain
 import "errors"
 
 var (
-	ErrInvalidInput        = errors.New("invalid input")
-	ErrUnauthorized        = errors.New("unauthorized")
-	ErrForbidden           = errors.New("forbidden")
-	ErrIdempotencyRequired = errors.New("idempotency key required")
-	ErrIdempotencyConflict = errors.New("idempotency key reused with different payload")
+	ErrInvalidInput         = errors.New("invalid input")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrIdempotencyRequired  = errors.New("idempotency key required")
+	ErrIdempotencyConflict  = errors.New("idempotency key reused with different payload")
+	ErrInsufficientData     = errors.New("not enough warehouse history to fit the model")
+	ErrWarehouseUnavailable = errors.New("warehouse unavailable")
 )
@@ -0,0 +1,293 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ChurnHorizon is how long a creator must stay inactive to count as
+	// churned, and ChurnLookback how far back features look.
+	ChurnHorizon  = 30 * 24 * time.Hour
+	ChurnLookback = 90 * 24 * time.Hour
+	// CampaignOutcomeWindow is how long after launch a campaign's
+	// submissions count towards its outcome.
+	CampaignOutcomeWindow = 30 * 24 * time.Hour
+	// CampaignSuccessShare is the share of the views a campaign's budget
+	// pays for (at reward_rate per 1,000 views) that counts as success.
+	CampaignSuccessShare = 0.8
+)
+
+var ChurnFeatureNames = []string{
+	"days_since_last_activity",
+	"submissions_30d",
+	"submission_trend",
+	"clicks_30d",
+	"approval_rate_90d",
+	"payouts_30d",
+	"views_30d",
+}
+
+var CampaignFeatureNames = []string{
+	"log_reward_rate",
+	"log_budget",
+	"category_success_rate",
+}
+
+// CreatorActivity is one creator's warehouse history.
+type CreatorActivity struct {
+	Submissions []SubmissionFact
+	Clicks      []ClickFact
+	Payouts     []PayoutFact
+}
+
+// GroupActivity splits warehouse rows by creator.
+func GroupActivity(subs []SubmissionFact, clicks []ClickFact, payouts []PayoutFact) map[string]*CreatorActivity {
+	out := map[string]*CreatorActivity{}
+	get := func(id string) *CreatorActivity {
+		if out[id] == nil {
+			out[id] = &CreatorActivity{}
+		}
+		return out[id]
+	}
+	for _, s := range subs {
+		if s.CreatorID != "" {
+			a := get(s.CreatorID)
+			a.Submissions = append(a.Submissions, s)
+		}
+	}
+	for _, c := range clicks {
+		if c.UserID != "" {
+			a := get(c.UserID)
+			a.Clicks = append(a.Clicks, c)
+		}
+	}
+	for _, p := range payouts {
+		if p.CreatorID != "" {
+			a := get(p.CreatorID)
+			a.Payouts = append(a.Payouts, p)
+		}
+	}
+	return out
+}
+
+// LastActivityBefore returns the latest activity strictly before at.
+func (a CreatorActivity) LastActivityBefore(at time.Time) (time.Time, bool) {
+	var last time.Time
+	consider := func(t time.Time) {
+		if t.Before(at) && t.After(last) {
+			last = t
+		}
+	}
+	for _, s := range a.Submissions {
+		consider(s.OccurredAt)
+	}
+	for _, c := range a.Clicks {
+		consider(c.OccurredAt)
+	}
+	for _, p := range a.Payouts {
+		consider(p.OccurredAt)
+	}
+	return last, !last.IsZero()
+}
+
+// ActiveBetween reports whether there is any activity in [from, to).
+func (a CreatorActivity) ActiveBetween(from, to time.Time) bool {
+	in := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	for _, s := range a.Submissions {
+		if in(s.OccurredAt) {
+			return true
+		}
+	}
+	for _, c := range a.Clicks {
+		if in(c.OccurredAt) {
+			return true
+		}
+	}
+	for _, p := range a.Payouts {
+		if in(p.OccurredAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChurnFeatures describes a creator's engagement as of at, using only
+// activity before at. ok is false when the creator has no activity in the
+// lookback window, since churn is only scored for recently active
+// creators.
+func ChurnFeatures(a CreatorActivity, at time.Time) (row []float64, ok bool) {
+	if !a.ActiveBetween(at.Add(-ChurnLookback), at) {
+		return nil, false
+	}
+	last, _ := a.LastActivityBefore(at)
+	month := at.Add(-30 * 24 * time.Hour)
+	prevMonth := at.Add(-60 * 24 * time.Hour)
+	lookback := at.Add(-ChurnLookback)
+
+	var subs30, subsPrev, subs90, approved90 int
+	var views30 int64
+	for _, s := range a.Submissions {
+		switch {
+		case !s.OccurredAt.Before(at) || s.OccurredAt.Before(lookback):
+			continue
+		case !s.OccurredAt.Before(month):
+			subs30++
+			views30 += s.Views
+		case !s.OccurredAt.Before(prevMonth):
+			subsPrev++
+		}
+		subs90++
+		if strings.EqualFold(s.Status, "approved") {
+			approved90++
+		}
+	}
+	clicks30 := 0
+	for _, c := range a.Clicks {
+		if !c.OccurredAt.Before(month) && c.OccurredAt.Before(at) {
+			clicks30++
+		}
+	}
+	payouts30 := 0.0
+	for _, p := range a.Payouts {
+		if !p.OccurredAt.Before(month) && p.OccurredAt.Before(at) {
+			payouts30 += p.Amount
+		}
+	}
+	approvalRate := 0.0
+	if subs90 > 0 {
+		approvalRate = float64(approved90) / float64(subs90)
+	}
+	return []float64{
+		math.Min(at.Sub(last).Hours()/24, ChurnLookback.Hours()/24) / 30,
+		math.Log1p(float64(subs30)),
+		math.Log1p(float64(subs30)) - math.Log1p(float64(subsPrev)),
+		math.Log1p(float64(clicks30)),
+		approvalRate,
+		math.Log1p(math.Max(payouts30, 0)),
+		math.Log1p(float64(views30)),
+	}, true
+}
+
+// DailyViews sums submission views per UTC day over [from, from+days).
+func DailyViews(subs []SubmissionFact, from time.Time, days int) []float64 {
+	from = StartOfDay(from)
+	out := make([]float64, days)
+	for _, s := range subs {
+		day := int(s.OccurredAt.Sub(from).Hours() / 24)
+		if s.OccurredAt.Before(from) || day >= days {
+			continue
+		}
+		out[day] += float64(s.Views)
+	}
+	return out
+}
+
+// TrimLeadingZeros drops the days before a creator's first views, so new
+// creators are not forecast from months of zeros.
+func TrimLeadingZeros(series []float64) []float64 {
+	for i, v := range series {
+		if v != 0 {
+			return series[i:]
+		}
+	}
+	return nil
+}
+
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CampaignOutcome is a launched campaign with its observed result.
+type CampaignOutcome struct {
+	Campaign       CampaignDim
+	DeliveredViews int64
+	TargetViews    float64
+	Succeeded      bool
+}
+
+// CampaignOutcomes labels campaigns whose outcome window closed before
+// now: a campaign succeeded when approved submissions within the window
+// delivered CampaignSuccessShare of the views its budget pays for.
+func CampaignOutcomes(campaigns []CampaignDim, subs []SubmissionFact, now time.Time) []CampaignOutcome {
+	views := map[string]int64{}
+	launched := map[string]time.Time{}
+	for _, c := range campaigns {
+		launched[c.CampaignID] = c.LaunchedAt
+	}
+	for _, s := range subs {
+		at, ok := launched[s.CampaignID]
+		if !ok || !strings.EqualFold(s.Status, "approved") || s.OccurredAt.Before(at) || !s.OccurredAt.Before(at.Add(CampaignOutcomeWindow)) {
+			continue
+		}
+		views[s.CampaignID] += s.Views
+	}
+	out := []CampaignOutcome{}
+	for _, c := range campaigns {
+		if c.RewardRate <= 0 || c.Budget <= 0 || c.LaunchedAt.Add(CampaignOutcomeWindow).After(now) {
+			continue
+		}
+		target := c.Budget / c.RewardRate * 1000
+		out = append(out, CampaignOutcome{
+			Campaign:       c,
+			DeliveredViews: views[c.CampaignID],
+			TargetViews:    target,
+			Succeeded:      float64(views[c.CampaignID]) >= CampaignSuccessShare*target,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Campaign.LaunchedAt.Before(out[j].Campaign.LaunchedAt) })
+	return out
+}
+
+// CategoryRates holds per-category success counts for target-encoding a
+// campaign's niche, smoothed towards the overall success rate.
+type CategoryRates struct {
+	Prior     float64            `json:"prior"`
+	Successes map[string]float64 `json:"-"`
+	Totals    map[string]float64 `json:"-"`
+}
+
+const categorySmoothing = 5.0
+
+func NewCategoryRates(outcomes []CampaignOutcome) CategoryRates {
+	r := CategoryRates{Successes: map[string]float64{}, Totals: map[string]float64{}}
+	wins := 0.0
+	for _, o := range outcomes {
+		key := normalizeCategory(o.Campaign.Category)
+		r.Totals[key]++
+		if o.Succeeded {
+			r.Successes[key]++
+			wins++
+		}
+	}
+	if len(outcomes) > 0 {
+		r.Prior = wins / float64(len(outcomes))
+	}
+	return r
+}
+
+// Rate is the smoothed success rate of a category. With exclude set, the
+// given outcome is left out, so a training row does not see its own label.
+func (r CategoryRates) Rate(category string, exclude *CampaignOutcome) float64 {
+	key := normalizeCategory(category)
+	wins, total := r.Successes[key], r.Totals[key]
+	if exclude != nil {
+		total--
+		if exclude.Succeeded {
+			wins--
+		}
+	}
+	return (wins + r.Prior*categorySmoothing) / (total + categorySmoothing)
+}
+
+// CampaignFeatures builds a campaign's feature row.
+func CampaignFeatures(rewardRate, budget, categoryRate float64) []float64 {
+	return []float64{math.Log(rewardRate), math.Log1p(budget), categoryRate}
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
@@ -0,0 +1,188 @@
+package domain
+
+import "math"
+
+// HoltWinters is an additive Holt-Winters (ETS(A,Ad,A)) model with a damped
+// trend, fitted to a daily series. Period is 0 when the series was too
+// short for a seasonal fit and the model falls back to damped Holt.
+type HoltWinters struct {
+	Alpha  float64 `json:"alpha"`
+	Beta   float64 `json:"beta"`
+	Gamma  float64 `json:"gamma"`
+	Phi    float64 `json:"phi"`
+	Period int     `json:"period"`
+	Sigma  float64 `json:"sigma"`
+
+	level    float64
+	trend    float64
+	seasonal []float64
+}
+
+// MinForecastHistory is the shortest series FitHoltWinters accepts.
+const MinForecastHistory = 4
+
+var (
+	alphaGrid = []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+	betaGrid  = []float64{0.01, 0.05, 0.1, 0.2, 0.3}
+	gammaGrid = []float64{0.01, 0.05, 0.1, 0.2, 0.3, 0.5}
+	phiGrid   = []float64{0.9, 0.95, 0.98, 1}
+)
+
+// FitHoltWinters grid-searches the smoothing parameters that minimise the
+// one-step-ahead squared error. A seasonal model needs two full periods.
+func FitHoltWinters(series []float64, period int) (HoltWinters, error) {
+	if len(series) < MinForecastHistory {
+		return HoltWinters{}, ErrInsufficientData
+	}
+	if period < 2 || len(series) < 2*period {
+		period = 0
+	}
+	gammas := gammaGrid
+	if period == 0 {
+		gammas = []float64{0}
+	}
+	best := HoltWinters{}
+	bestSSE := math.Inf(1)
+	for _, alpha := range alphaGrid {
+		for _, beta := range betaGrid {
+			for _, gamma := range gammas {
+				for _, phi := range phiGrid {
+					m := HoltWinters{Alpha: alpha, Beta: beta, Gamma: gamma, Phi: phi, Period: period}
+					sse, n := m.run(series)
+					if n > 0 && sse < bestSSE {
+						bestSSE = sse
+						best = m
+					}
+				}
+			}
+		}
+	}
+	_, n := best.run(series)
+	dof := n - 2
+	if best.Period > 0 {
+		dof -= 2
+	}
+	if dof < 1 {
+		dof = 1
+	}
+	best.Sigma = math.Sqrt(bestSSE / float64(dof))
+	return best, nil
+}
+
+// run initialises the states, filters the series through them and returns
+// the one-step squared error over the observations after initialisation.
+func (m *HoltWinters) run(series []float64) (float64, int) {
+	start := 2
+	if m.Period > 0 {
+		start = m.Period
+		first := mean(series[:m.Period])
+		m.level = first
+		m.trend = (mean(series[m.Period:2*m.Period]) - first) / float64(m.Period)
+		m.seasonal = make([]float64, m.Period)
+		for i := range m.seasonal {
+			m.seasonal[i] = series[i] - first
+		}
+	} else {
+		m.level = series[1]
+		m.trend = series[1] - series[0]
+		m.seasonal = nil
+	}
+	sse, n := 0.0, 0
+	for t := start; t < len(series); t++ {
+		season := 0.0
+		if m.Period > 0 {
+			season = m.seasonal[t%m.Period]
+		}
+		damped := m.Phi * m.trend
+		err := series[t] - (m.level + damped + season)
+		sse += err * err
+		n++
+		prevLevel := m.level
+		m.level = prevLevel + damped + m.Alpha*err
+		m.trend = damped + m.Alpha*m.Beta*err
+		if m.Period > 0 {
+			m.seasonal[t%m.Period] = season + m.Gamma*err
+		}
+	}
+	if m.Period > 0 {
+		// Rotate so seasonal[0] belongs to the first forecast step.
+		rotated := make([]float64, m.Period)
+		for i := range rotated {
+			rotated[i] = m.seasonal[(len(series)+i)%m.Period]
+		}
+		m.seasonal = rotated
+	}
+	return sse, n
+}
+
+// Forecast returns the point forecasts for the next h steps.
+func (m HoltWinters) Forecast(h int) []float64 {
+	out := make([]float64, h)
+	dampSum := 0.0
+	for k := 1; k <= h; k++ {
+		dampSum += math.Pow(m.Phi, float64(k))
+		out[k-1] = m.level + dampSum*m.trend
+		if m.Period > 0 {
+			out[k-1] += m.seasonal[(k-1)%m.Period]
+		}
+	}
+	return out
+}
+
+// ForecastTotal returns the forecast sum of the next h steps and its
+// prediction interval at z standard errors. The variance of the summed
+// errors comes from the model's error-correction form: a shock at step i
+// carries into every later step j through c_(j-i), so its coefficient in
+// the total is 1 + c_1 + ... + c_(h-i).
+func (m HoltWinters) ForecastTotal(h int, z float64) (point, low, high float64) {
+	for _, v := range m.Forecast(h) {
+		point += v
+	}
+	c := make([]float64, h)
+	dampSum := 0.0
+	for j := 1; j < h; j++ {
+		dampSum += math.Pow(m.Phi, float64(j))
+		c[j] = m.Alpha + m.Alpha*m.Beta*dampSum
+		if m.Period > 0 && j%m.Period == 0 {
+			c[j] += m.Gamma
+		}
+	}
+	variance, carry := 0.0, 1.0
+	for i := h; i >= 1; i-- {
+		// carry is 1 + c_1 + ... + c_(h-i) for the shock at step i.
+		variance += carry * carry
+		if h-i+1 < h {
+			carry += c[h-i+1]
+		}
+	}
+	spread := z * m.Sigma * math.Sqrt(variance)
+	return point, point - spread, point + spread
+}
+
+// MAPE is the mean absolute percentage error over pairs whose actual value
+// is non-zero.
+func MAPE(actual, forecast []float64) (float64, int) {
+	total, n := 0.0, 0
+	for i := range actual {
+		if i >= len(forecast) || actual[i] == 0 {
+			continue
+		}
+		total += math.Abs((actual[i] - forecast[i]) / actual[i])
+		n++
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return total / float64(n), n
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// LogisticModel is an L2-regularised logistic regression over standardised
+// features.
+type LogisticModel struct {
+	Features []string  `json:"features"`
+	Weights  []float64 `json:"weights"`
+	Bias     float64   `json:"bias"`
+	Means    []float64 `json:"means"`
+	Scales   []float64 `json:"scales"`
+}
+
+const (
+	logisticIterations = 400
+	logisticRate       = 0.5
+	logisticL2         = 0.01
+)
+
+// FitLogistic trains on rows x with labels y by batch gradient descent. It
+// needs minRows rows and both classes present.
+func FitLogistic(features []string, x [][]float64, y []bool, minRows int) (LogisticModel, error) {
+	if len(x) < minRows || len(x) != len(y) {
+		return LogisticModel{}, ErrInsufficientData
+	}
+	positives := 0
+	for _, label := range y {
+		if label {
+			positives++
+		}
+	}
+	if positives == 0 || positives == len(y) {
+		return LogisticModel{}, ErrInsufficientData
+	}
+	d := len(features)
+	m := LogisticModel{
+		Features: append([]string(nil), features...),
+		Weights:  make([]float64, d),
+		Means:    make([]float64, d),
+		Scales:   make([]float64, d),
+	}
+	for j := 0; j < d; j++ {
+		col := make([]float64, len(x))
+		for i := range x {
+			col[i] = x[i][j]
+		}
+		m.Means[j] = mean(col)
+		variance := 0.0
+		for _, v := range col {
+			variance += (v - m.Means[j]) * (v - m.Means[j])
+		}
+		m.Scales[j] = math.Sqrt(variance / float64(len(col)))
+		if m.Scales[j] < 1e-9 {
+			m.Scales[j] = 1
+		}
+	}
+	z := make([][]float64, len(x))
+	for i := range x {
+		z[i] = m.standardise(x[i])
+	}
+	n := float64(len(x))
+	grad := make([]float64, d)
+	for iter := 0; iter < logisticIterations; iter++ {
+		for j := range grad {
+			grad[j] = logisticL2 * m.Weights[j]
+		}
+		gradBias := 0.0
+		for i := range z {
+			diff := sigmoid(m.linear(z[i])) - boolFloat(y[i])
+			for j := range grad {
+				grad[j] += diff * z[i][j] / n
+			}
+			gradBias += diff / n
+		}
+		for j := range m.Weights {
+			m.Weights[j] -= logisticRate * grad[j]
+		}
+		m.Bias -= logisticRate * gradBias
+	}
+	return m, nil
+}
+
+// Predict returns the positive-class probability for one row.
+func (m LogisticModel) Predict(row []float64) float64 {
+	return sigmoid(m.linear(m.standardise(row)))
+}
+
+// Contributions returns each feature's share of the log-odds for a row,
+// for explaining a score.
+func (m LogisticModel) Contributions(row []float64) map[string]float64 {
+	z := m.standardise(row)
+	out := make(map[string]float64, len(m.Features))
+	for j, name := range m.Features {
+		out[name] = m.Weights[j] * z[j]
+	}
+	return out
+}
+
+func (m LogisticModel) standardise(row []float64) []float64 {
+	out := make([]float64, len(m.Weights))
+	for j := range out {
+		out[j] = (row[j] - m.Means[j]) / m.Scales[j]
+	}
+	return out
+}
+
+func (m LogisticModel) linear(z []float64) float64 {
+	sum := m.Bias
+	for j, w := range m.Weights {
+		sum += w * z[j]
+	}
+	return sum
+}
+
+// AUC is the area under the ROC curve: the probability a random positive
+// scores above a random negative, with ties counting half. It is 0 when
+// either class is missing.
+func AUC(scores []float64, labels []bool) float64 {
+	type pair struct {
+		score float64
+		label bool
+	}
+	pairs := make([]pair, len(scores))
+	positives := 0
+	for i := range scores {
+		pairs[i] = pair{scores[i], labels[i]}
+		if labels[i] {
+			positives++
+		}
+	}
+	negatives := len(pairs) - positives
+	if positives == 0 || negatives == 0 {
+		return 0
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].score < pairs[j].score })
+	// Sum the positives' average ranks (Mann-Whitney U).
+	rankSum := 0.0
+	for i := 0; i < len(pairs); {
+		j := i
+		for j < len(pairs) && pairs[j].score == pairs[i].score {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if pairs[k].label {
+				rankSum += avgRank
+			}
+		}
+		i = j
+	}
+	p := float64(positives)
+	return (rankSum - p*(p+1)/2) / (p * float64(negatives))
+}
+
+func sigmoid(v float64) float64 {
+	return 1 / (1 + math.Exp(-v))
+}
+
+func boolFloat(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
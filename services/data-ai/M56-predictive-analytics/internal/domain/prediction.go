@@ -9,7 +9,10 @@ type ViewForecast struct {
 	ForecastViewsLow  int       `json:"forecast_views_low"`
 	ForecastViewsHigh int       `json:"forecast_views_high"`
 	ConfidenceScore   float64   `json:"confidence_score"`
+	HistoryDays       int       `json:"history_days"`
+	Seasonal          bool      `json:"seasonal"`
 	ModelVersion      string    `json:"model_version"`
+	BacktestMAPE      *float64  `json:"backtest_mape,omitempty"`
 	GeneratedAt       time.Time `json:"generated_at"`
 }
 
@@ -21,19 +24,24 @@ type ClipRecommendation struct {
 }
 
 type ChurnRisk struct {
-	UserID            string    `json:"user_id"`
-	ChurnRiskLevel    string    `json:"churn_risk_level"`
-	ChurnRiskScore    float64   `json:"churn_risk_score"`
-	RecommendedAction string    `json:"recommended_action"`
-	GeneratedAt       time.Time `json:"generated_at"`
+	UserID            string             `json:"user_id"`
+	ChurnRiskLevel    string             `json:"churn_risk_level"`
+	ChurnRiskScore    float64            `json:"churn_risk_score"`
+	RecommendedAction string             `json:"recommended_action"`
+	Drivers           map[string]float64 `json:"drivers,omitempty"`
+	ModelVersion      string             `json:"model_version"`
+	BacktestAUC       *float64           `json:"backtest_auc,omitempty"`
+	GeneratedAt       time.Time          `json:"generated_at"`
 }
 
 type CampaignSuccessPrediction struct {
-	PredictionID      string    `json:"prediction_id"`
-	CampaignID        string    `json:"campaign_id"`
-	SuccessLikelihood float64   `json:"success_likelihood"`
-	SuccessPrediction string    `json:"success_prediction"`
-	Advice            string    `json:"advice"`
-	ModelVersion      string    `json:"model_version"`
-	GeneratedAt       time.Time `json:"generated_at"`
+	PredictionID        string    `json:"prediction_id"`
+	CampaignID          string    `json:"campaign_id"`
+	SuccessLikelihood   float64   `json:"success_likelihood"`
+	SuccessPrediction   string    `json:"success_prediction"`
+	Advice              string    `json:"advice"`
+	ComparableCampaigns int       `json:"comparable_campaigns"`
+	ModelVersion        string    `json:"model_version"`
+	BacktestAUC         *float64  `json:"backtest_auc,omitempty"`
+	GeneratedAt         time.Time `json:"generated_at"`
 }
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// The warehouse rows below mirror the M54 facts M56 reads its features
+// from: fact_submissions, fact_clicks, fact_payouts and dim_campaigns.
+
+type SubmissionFact struct {
+	SubmissionID string    `json:"submission_id"`
+	CreatorID    string    `json:"creator_id"`
+	CampaignID   string    `json:"campaign_id"`
+	Platform     string    `json:"platform"`
+	Status       string    `json:"status"`
+	Views        int64     `json:"views"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+type ClickFact struct {
+	ClickID    string    `json:"click_id"`
+	UserID     string    `json:"user_id"`
+	Platform   string    `json:"platform"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type PayoutFact struct {
+	PayoutID   string    `json:"payout_id"`
+	CreatorID  string    `json:"creator_id"`
+	Amount     float64   `json:"amount"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type CampaignDim struct {
+	CampaignID string    `json:"campaign_id"`
+	Category   string    `json:"category"`
+	RewardRate float64   `json:"reward_rate"`
+	Budget     float64   `json:"budget"`
+	LaunchedAt time.Time `json:"launched_at"`
+}
+
+// WarehouseFilter narrows a fact read. Empty IDs match every row; From is
+// inclusive and To exclusive.
+type WarehouseFilter struct {
+	CreatorID  string
+	CampaignID string
+	From       time.Time
+	To         time.Time
+}
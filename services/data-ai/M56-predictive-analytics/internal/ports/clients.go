@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
+)
+
+// WarehouseReader reads the facts M54-Analytics-Service warehouses from
+// canonical events. M56 never writes them.
+type WarehouseReader interface {
+	Submissions(ctx context.Context, filter domain.WarehouseFilter) ([]domain.SubmissionFact, error)
+	Clicks(ctx context.Context, filter domain.WarehouseFilter) ([]domain.ClickFact, error)
+	Payouts(ctx context.Context, filter domain.WarehouseFilter) ([]domain.PayoutFact, error)
+	Campaigns(ctx context.Context, launchedFrom, launchedTo time.Time) ([]domain.CampaignDim, error)
+}
@@ -24,3 +24,10 @@ type IdempotencyRepository interface {
 type PredictionRepository interface {
 	SaveCampaignSuccess(ctx context.Context, row domain.CampaignSuccessPrediction) error
 }
+
+type BacktestRepository interface {
+	Save(ctx context.Context, report domain.BacktestReport) error
+	List(ctx context.Context) ([]domain.BacktestReport, error)
+	// Latest returns the newest successful report for a model version.
+	Latest(ctx context.Context, modelVersion string) (*domain.BacktestReport, error)
+}
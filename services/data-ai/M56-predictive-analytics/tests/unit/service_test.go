@@ -2,16 +2,35 @@ package unit
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/adapters/warehouse"
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/application"
 	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M56-predictive-analytics/internal/ports"
 )
 
 func newService() *application.Service {
+	svc, _ := newSeededService()
+	return svc
+}
+
+// newSeededService returns a service reading a warehouse seeded with
+// deterministic M54 history relative to now.
+func newSeededService() (*application.Service, *postgres.Repositories) {
 	repos := postgres.NewRepositories()
+	seedWarehouse(repos.Warehouse, time.Now().UTC())
 	return application.NewService(application.Dependencies{
 		Config: application.Config{
 			ServiceName:    "M56-Predictive-Analytics",
@@ -20,7 +39,94 @@ func newService() *application.Service {
 		},
 		Idempotency: repos.Idempotency,
 		Predictions: repos.Predictions,
-	})
+		Backtests:   repos.Backtests,
+		Warehouse:   repos.Warehouse,
+	}), repos
+}
+
+// weeklyViews is creator-steady's daily views: a weekly cycle on a gentle
+// upward trend.
+func weeklyViews(day int) float64 {
+	return 1000 + 3*float64(day) + 300*math.Sin(2*math.Pi*float64(day%7)/7)
+}
+
+// seedWarehouse writes 260 days of history:
+//   - creator-steady posts daily on a weekly cycle (the forecast fixture);
+//   - creator-fading stops posting 12 days ago after a slowdown;
+//   - 60 more creators post, click and get paid at random, and a third of
+//     them slow down and then stop, so churn has labels to learn from;
+//   - 40 campaigns whose delivered views grow with their reward rate.
+func seedWarehouse(w *postgres.WarehouseRepository, now time.Time) {
+	rng := rand.New(rand.NewSource(7))
+	today := domain.StartOfDay(now)
+	const days = 260
+	start := today.AddDate(0, 0, -days)
+
+	for d := 0; d < days; d++ {
+		w.AddSubmission(domain.SubmissionFact{
+			SubmissionID: fmt.Sprintf("steady-%03d", d),
+			CreatorID:    "creator-steady",
+			Platform:     "tiktok",
+			Status:       "approved",
+			Views:        int64(weeklyViews(d)),
+			OccurredAt:   start.AddDate(0, 0, d).Add(12 * time.Hour),
+		})
+	}
+	w.AddSubmission(domain.SubmissionFact{SubmissionID: "steady-viral", CreatorID: "creator-steady", Platform: "youtube", Status: "approved", Views: 20000, OccurredAt: today.AddDate(0, 0, -60)})
+
+	addCreator := func(id string, stopDay int, rate float64) {
+		for d := 0; d < stopDay; d++ {
+			// Activity thins out over the month before a creator stops.
+			p := rate
+			if left := stopDay - d; stopDay < days && left < 30 {
+				p *= float64(left) / 30
+			}
+			at := start.AddDate(0, 0, d).Add(time.Duration(rng.Intn(24)) * time.Hour)
+			if rng.Float64() < p {
+				status := "approved"
+				if rng.Float64() < 0.2 {
+					status = "rejected"
+				}
+				w.AddSubmission(domain.SubmissionFact{SubmissionID: fmt.Sprintf("%s-%03d", id, d), CreatorID: id, Platform: "tiktok", Status: status, Views: int64(200 + rng.Intn(800)), OccurredAt: at})
+			}
+			if rng.Float64() < p {
+				w.AddClick(domain.ClickFact{ClickID: fmt.Sprintf("%s-click-%03d", id, d), UserID: id, OccurredAt: at})
+			}
+			if d%14 == 0 && rng.Float64() < p {
+				w.AddPayout(domain.PayoutFact{PayoutID: fmt.Sprintf("%s-pay-%03d", id, d), CreatorID: id, Amount: 50 + rng.Float64()*100, OccurredAt: at})
+			}
+		}
+	}
+	addCreator("creator-fading", days-12, 0.6)
+	addCreator("creator-active", days, 0.6)
+	for i := 0; i < 60; i++ {
+		stop := days
+		if i%3 == 0 {
+			stop = 60 + rng.Intn(days-60)
+		}
+		addCreator(fmt.Sprintf("creator-%02d", i), stop, 0.3+rng.Float64()*0.4)
+	}
+
+	for i := 0; i < 40; i++ {
+		rate := 0.5 + 2.5*rng.Float64()
+		budget := 1000 + 9000*rng.Float64()
+		launched := today.AddDate(0, 0, -40-rng.Intn(300))
+		category := []string{"gaming", "tech", "beauty"}[i%3]
+		w.UpsertCampaign(domain.CampaignDim{CampaignID: fmt.Sprintf("camp-hist-%02d", i), Category: category, RewardRate: rate, Budget: budget, LaunchedAt: launched})
+		target := budget / rate * 1000
+		delivered := target * (rate / 1.75) * (0.8 + 0.4*rng.Float64())
+		for k := 0; k < 3; k++ {
+			w.AddSubmission(domain.SubmissionFact{
+				SubmissionID: fmt.Sprintf("camp-hist-%02d-%d", i, k),
+				CreatorID:    fmt.Sprintf("campaign-creator-%02d", i),
+				CampaignID:   fmt.Sprintf("camp-hist-%02d", i),
+				Platform:     "tiktok",
+				Status:       "approved",
+				Views:        int64(delivered / 3),
+				OccurredAt:   launched.AddDate(0, 0, 5+k*7),
+			})
+		}
+	}
 }
 
 func TestPredictCampaignSuccessIdempotency(t *testing.T) {
@@ -88,3 +194,334 @@ func TestReadPredictionsRequireAuth(t *testing.T) {
 		t.Fatalf("expected unauthorized, got %v", err)
 	}
 }
+
+func TestHoltWintersRecoversWeeklySeasonality(t *testing.T) {
+	t.Parallel()
+	series := make([]float64, 84)
+	for d := range series {
+		series[d] = weeklyViews(d)
+	}
+	model, err := domain.FitHoltWinters(series, 7)
+	if err != nil {
+		t.Fatalf("fit: %v", err)
+	}
+	if model.Period != 7 {
+		t.Fatalf("expected weekly seasonal fit, got period %d", model.Period)
+	}
+	forecast := model.Forecast(7)
+	for k, got := range forecast {
+		want := weeklyViews(len(series) + k)
+		if math.Abs(got-want)/want > 0.03 {
+			t.Fatalf("step %d: forecast %.1f, want about %.1f", k+1, got, want)
+		}
+	}
+	point, low, high := model.ForecastTotal(30, 1.645)
+	if !(low < point && point < high) {
+		t.Fatalf("expected interval around point, got low=%.1f point=%.1f high=%.1f", low, point, high)
+	}
+	if _, err := domain.FitHoltWinters([]float64{1, 2}, 7); !errors.Is(err, domain.ErrInsufficientData) {
+		t.Fatalf("expected insufficient data for a two-day series, got %v", err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	if auc := domain.AUC([]float64{0.9, 0.8, 0.3, 0.1}, []bool{true, true, false, false}); auc != 1 {
+		t.Fatalf("expected perfect AUC, got %v", auc)
+	}
+	if auc := domain.AUC([]float64{0.5, 0.5}, []bool{true, false}); auc != 0.5 {
+		t.Fatalf("expected ties to count half, got %v", auc)
+	}
+	mape, n := domain.MAPE([]float64{100, 0, 200}, []float64{110, 5, 180})
+	if n != 2 || math.Abs(mape-0.1) > 1e-9 {
+		t.Fatalf("expected MAPE 0.1 over 2 points, got %v over %d", mape, n)
+	}
+}
+
+func TestViewForecastFitsWarehouseHistory(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	actor := application.Actor{SubjectID: "creator-steady", Role: "creator"}
+	forecast, err := svc.GetViewForecast(context.Background(), actor, application.ViewForecastInput{WindowDays: 7})
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	// The viral youtube post two months back is noise the fit must absorb.
+	want := 0.0
+	for k := 0; k < 7; k++ {
+		want += weeklyViews(260 + k)
+	}
+	if !forecast.Seasonal || forecast.HistoryDays != 180 || forecast.ModelVersion != "holt_winters@v1.0.0" {
+		t.Fatalf("unexpected forecast metadata: %+v", forecast)
+	}
+	if float64(forecast.ForecastViewsLow) > want || float64(forecast.ForecastViewsHigh) < want {
+		t.Fatalf("expected interval %d-%d to contain %.0f", forecast.ForecastViewsLow, forecast.ForecastViewsHigh, want)
+	}
+	if math.Abs(float64(forecast.ForecastViews)-want)/want > 0.15 {
+		t.Fatalf("forecast %d too far from %.0f", forecast.ForecastViews, want)
+	}
+	if _, err := svc.GetViewForecast(context.Background(), application.Actor{SubjectID: "creator-new"}, application.ViewForecastInput{}); !errors.Is(err, domain.ErrInsufficientData) {
+		t.Fatalf("expected insufficient data for a creator without history, got %v", err)
+	}
+}
+
+func TestChurnRiskScoresFadingCreatorHigher(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin"}
+	fading, err := svc.GetChurnRisk(context.Background(), admin, application.ChurnRiskInput{UserID: "creator-fading"})
+	if err != nil {
+		t.Fatalf("churn for fading creator: %v", err)
+	}
+	active, err := svc.GetChurnRisk(context.Background(), admin, application.ChurnRiskInput{UserID: "creator-active"})
+	if err != nil {
+		t.Fatalf("churn for active creator: %v", err)
+	}
+	if fading.ChurnRiskScore <= active.ChurnRiskScore {
+		t.Fatalf("expected fading creator to score higher: fading=%v active=%v", fading.ChurnRiskScore, active.ChurnRiskScore)
+	}
+	if fading.ModelVersion != "churn_logreg@v1.0.0" || len(fading.Drivers) != len(domain.ChurnFeatureNames) {
+		t.Fatalf("expected model version and per-feature drivers, got %+v", fading)
+	}
+}
+
+func TestCampaignSuccessLearnsFromPastCampaigns(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	predict := func(key string, rate float64) domain.CampaignSuccessPrediction {
+		t.Helper()
+		row, err := svc.PredictCampaignSuccess(context.Background(), application.Actor{SubjectID: "brand-1", IdempotencyKey: key}, application.CampaignSuccessInput{
+			CampaignID: "camp-new",
+			RewardRate: rate,
+			Budget:     5000,
+			Niche:      "gaming",
+		})
+		if err != nil {
+			t.Fatalf("predict: %v", err)
+		}
+		return row
+	}
+	low, high := predict("idem-low", 0.6), predict("idem-high", 2.8)
+	if high.SuccessLikelihood <= low.SuccessLikelihood {
+		t.Fatalf("expected higher reward rate to predict more success: low=%v high=%v", low.SuccessLikelihood, high.SuccessLikelihood)
+	}
+	if high.ComparableCampaigns == 0 || high.ModelVersion != "campaign_logreg@v1.0.0" {
+		t.Fatalf("unexpected prediction metadata: %+v", high)
+	}
+
+	empty := application.NewService(application.Dependencies{
+		Idempotency: postgres.NewRepositories().Idempotency,
+		Predictions: postgres.NewRepositories().Predictions,
+		Backtests:   postgres.NewRepositories().Backtests,
+		Warehouse:   postgres.NewRepositories().Warehouse,
+	})
+	_, err := empty.PredictCampaignSuccess(context.Background(), application.Actor{SubjectID: "brand-1", IdempotencyKey: "idem-empty"}, application.CampaignSuccessInput{CampaignID: "c", RewardRate: 1, Budget: 100})
+	if !errors.Is(err, domain.ErrInsufficientData) {
+		t.Fatalf("expected insufficient data without campaign history, got %v", err)
+	}
+}
+
+// countingWarehouse counts the submission reads a model fit makes.
+type countingWarehouse struct {
+	ports.WarehouseReader
+	reads atomic.Int32
+}
+
+func (w *countingWarehouse) Submissions(ctx context.Context, filter domain.WarehouseFilter) ([]domain.SubmissionFact, error) {
+	w.reads.Add(1)
+	return w.WarehouseReader.Submissions(ctx, filter)
+}
+
+func TestFailedChurnFitIsNotRetriedOnEveryRequest(t *testing.T) {
+	t.Parallel()
+	repos := postgres.NewRepositories()
+	wh := &countingWarehouse{WarehouseReader: repos.Warehouse}
+	svc := application.NewService(application.Dependencies{Idempotency: repos.Idempotency, Predictions: repos.Predictions, Backtests: repos.Backtests, Warehouse: wh})
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin"}
+	if _, err := svc.GetChurnRisk(context.Background(), admin, application.ChurnRiskInput{UserID: "creator-1"}); !errors.Is(err, domain.ErrInsufficientData) {
+		t.Fatalf("expected insufficient data from an empty warehouse, got %v", err)
+	}
+	first := wh.reads.Load()
+	if _, err := svc.GetChurnRisk(context.Background(), admin, application.ChurnRiskInput{UserID: "creator-2"}); !errors.Is(err, domain.ErrInsufficientData) {
+		t.Fatalf("expected the cached failure, got %v", err)
+	}
+	if again := wh.reads.Load() - first; again >= first {
+		t.Fatalf("expected the failed fit to be served from cache, the second request read the warehouse %d times (first %d)", again, first)
+	}
+}
+
+func TestClipRecommendationsRankByLift(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	items, err := svc.GetClipRecommendations(context.Background(), application.Actor{SubjectID: "creator-steady"}, application.ClipRecommendationsInput{Limit: 2})
+	if err != nil {
+		t.Fatalf("recommendations: %v", err)
+	}
+	if len(items) != 2 || items[0].SourceID != "steady-viral" || items[0].Score <= items[1].Score {
+		t.Fatalf("expected the viral post first, got %+v", items)
+	}
+}
+
+func TestBacktestsReportMetricsPerModelVersion(t *testing.T) {
+	t.Parallel()
+	svc := newService()
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-backtest"}
+	if _, err := svc.RunBacktests(ctx, application.Actor{SubjectID: "creator-1", IdempotencyKey: "idem-x"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for non-admin, got %v", err)
+	}
+	reports, err := svc.RunBacktests(ctx, admin)
+	if err != nil {
+		t.Fatalf("run backtests: %v", err)
+	}
+	byKind := map[domain.ModelKind]domain.BacktestReport{}
+	for _, r := range reports {
+		if r.Status != domain.BacktestStatusOK || r.Samples == 0 {
+			t.Fatalf("expected a scored report, got %+v", r)
+		}
+		byKind[r.ModelKind] = r
+	}
+	if r := byKind[domain.ModelViewForecast]; r.Metric != "mape" || r.Value <= 0 || r.Value > 1 || r.Coverage == nil {
+		t.Fatalf("unexpected forecast backtest: %+v", r)
+	}
+	if r := byKind[domain.ModelChurn]; r.Metric != "auc" || r.Value < 0.7 {
+		t.Fatalf("unexpected churn backtest: %+v", r)
+	}
+	if r := byKind[domain.ModelCampaignSuccess]; r.Metric != "auc" || r.Value < 0.6 {
+		t.Fatalf("unexpected campaign backtest: %+v", r)
+	}
+
+	listed, err := svc.ListBacktests(ctx, admin, application.ListBacktestsInput{ModelVersion: "churn_logreg@v1.0.0"})
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected one churn report, got %d err=%v", len(listed), err)
+	}
+	forecast, err := svc.GetViewForecast(ctx, application.Actor{SubjectID: "creator-steady"}, application.ViewForecastInput{WindowDays: 7})
+	if err != nil || forecast.BacktestMAPE == nil {
+		t.Fatalf("expected forecast to carry its version's backtest MAPE, got %+v err=%v", forecast, err)
+	}
+}
+
+const fakeM54PageSize = 25
+
+// fakeM54 serves a seeded warehouse the way M54's warehouse endpoints do.
+func fakeM54(t *testing.T, w *postgres.WarehouseRepository) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Actor-Role") != "service" || r.Header.Get("Authorization") != "Bearer M56-Predictive-Analytics" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+		query := r.URL.Query()
+		filter := domain.WarehouseFilter{CreatorID: query.Get("creator_id"), CampaignID: query.Get("campaign_id")}
+		filter.From, _ = time.Parse(time.RFC3339Nano, query.Get("from"))
+		filter.To, _ = time.Parse(time.RFC3339Nano, query.Get("to"))
+		var rows any
+		var err error
+		switch r.URL.Path {
+		case "/api/v1/analytics/warehouse/submissions":
+			rows, err = w.Submissions(r.Context(), filter)
+		case "/api/v1/analytics/warehouse/clicks":
+			rows, err = w.Clicks(r.Context(), filter)
+		case "/api/v1/analytics/warehouse/payouts":
+			rows, err = w.Payouts(r.Context(), filter)
+		case "/api/v1/analytics/warehouse/campaigns":
+			rows, err = w.Campaigns(r.Context(), filter.From, filter.To)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// Pages are smaller than the reader asks for, so it has to follow
+		// next_cursor to see every row.
+		var items []json.RawMessage
+		raw, _ := json.Marshal(rows)
+		_ = json.Unmarshal(raw, &items)
+		offset, _ := strconv.Atoi(query.Get("cursor"))
+		end, next := min(offset+fakeM54PageSize, len(items)), ""
+		if end < len(items) {
+			next = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(rw).Encode(map[string]any{"status": "success", "data": map[string]any{"items": items[offset:end], "next_cursor": next}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestM54ReaderServesForecastsFromTheAnalyticsAPI(t *testing.T) {
+	t.Parallel()
+	seeded := &postgres.WarehouseRepository{}
+	seedWarehouse(seeded, time.Now().UTC())
+	srv := fakeM54(t, seeded)
+	newWithReader := func(baseURL string) *application.Service {
+		repos := postgres.NewRepositories()
+		return application.NewService(application.Dependencies{
+			Config:      application.Config{ServiceName: "M56-Predictive-Analytics", ModelVersion: "v1.0.0"},
+			Idempotency: repos.Idempotency,
+			Predictions: repos.Predictions,
+			Backtests:   repos.Backtests,
+			Warehouse:   warehouse.NewM54Reader(warehouse.M54Config{BaseURL: baseURL, Subject: "M56-Predictive-Analytics"}),
+		})
+	}
+	actor := application.Actor{SubjectID: "creator-steady", Role: "creator"}
+	input := application.ViewForecastInput{WindowDays: 7}
+
+	want, err := newService().GetViewForecast(context.Background(), actor, input)
+	if err != nil {
+		t.Fatalf("forecast from the in-memory warehouse: %v", err)
+	}
+	got, err := newWithReader(srv.URL).GetViewForecast(context.Background(), actor, input)
+	if err != nil {
+		t.Fatalf("forecast through M54: %v", err)
+	}
+	if got.ForecastViews != want.ForecastViews || got.HistoryDays != want.HistoryDays {
+		t.Fatalf("expected the same forecast through M54, got %+v want %+v", got, want)
+	}
+
+	srv.Close()
+	if _, err := newWithReader(srv.URL).GetViewForecast(context.Background(), actor, input); !errors.Is(err, domain.ErrWarehouseUnavailable) {
+		t.Fatalf("expected an unreachable M54 to surface, got %v", err)
+	}
+}
+
+func TestM54ReaderRetriesAFailedPageAndStopsCallingAnM54Outage(t *testing.T) {
+	t.Parallel()
+	seeded := &postgres.WarehouseRepository{}
+	seedWarehouse(seeded, time.Now().UTC())
+	upstream := fakeM54(t, seeded)
+	var hits atomic.Int32
+	var failOnce, down atomic.Bool
+	failOnce.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if failOnce.Swap(false) || down.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		upstream.Config.Handler.ServeHTTP(rw, r)
+	}))
+	t.Cleanup(srv.Close)
+	reader := warehouse.NewM54Reader(warehouse.M54Config{BaseURL: srv.URL, Subject: "M56-Predictive-Analytics"})
+	filter := domain.WarehouseFilter{CreatorID: "creator-steady"}
+
+	rows, err := reader.Submissions(context.Background(), filter)
+	if err != nil || len(rows) == 0 {
+		t.Fatalf("expected a single 503 to be retried, got %d rows err=%v", len(rows), err)
+	}
+
+	down.Store(true)
+	for range 5 {
+		if _, err := reader.Submissions(context.Background(), filter); !errors.Is(err, domain.ErrWarehouseUnavailable) {
+			t.Fatalf("expected an M54 outage to surface, got %v", err)
+		}
+	}
+	before := hits.Load()
+	if _, err := reader.Submissions(context.Background(), filter); !errors.Is(err, domain.ErrWarehouseUnavailable) {
+		t.Fatalf("expected an open breaker to surface as unavailable, got %v", err)
+	}
+	if hits.Load() != before {
+		t.Fatalf("expected the open breaker to stop calling M54, got %d more requests", hits.Load()-before)
+	}
+}
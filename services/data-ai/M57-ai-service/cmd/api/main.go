@@ -1,25 +1,58 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/adapters/classifier"
 	httpadapter "github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/application"
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/ports"
 )
 
 func main() {
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv("M57-AI-Service", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
+
+	classifiers := map[string]ports.Classifier{}
+	if url := strings.TrimSpace(os.Getenv("M57_REMOTE_MODEL_URL")); url != "" {
+		classifiers[domain.BackendRemote] = classifier.NewRemote(classifier.RemoteConfig{
+			BaseURL: url,
+			Token:   os.Getenv("M57_REMOTE_MODEL_TOKEN"),
+		})
+	}
+
+	// A share of confident predictions is reviewed so shadow evaluation
+	// sees more than the uncertain ones.
+	sampleRate := 0.05
+	if raw := strings.TrimSpace(os.Getenv("M57_REVIEW_SAMPLE_RATE")); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			sampleRate = v
+		}
+	}
+
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
+		Config:      application.Config{ReviewSampleRate: sampleRate},
 		Predictions: repos.Predictions,
 		BatchJobs:   repos.BatchJobs,
 		Models:      repos.Models,
+		Shadows:     repos.Shadows,
+		Reviews:     repos.Reviews,
 		Feedback:    repos.Feedback,
 		Audit:       repos.Audit,
 		Idempotency: repos.Idempotency,
+		Classifiers: classifiers,
 	})
 	router := httpadapter.NewRouter(httpadapter.NewHandler(svc))
 
@@ -32,7 +65,7 @@ func main() {
 	}
 
 	log.Printf("M57-AI-Service listening on %s", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := http.ListenAndServe(addr, observability.HTTPMiddleware("M57-AI-Service")(router)); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,89 @@
+// Package classifier adapts remote model servers to ports.Classifier. Local
+// TF-IDF models are scored in-process by the application layer.
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+)
+
+// RemoteConfig points the adapter at a model server that accepts
+// POST {BaseURL}/v1/classify.
+type RemoteConfig struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+// Remote scores text on a model server. The server is expected to answer
+// with {"scores": {"<label>": <probability>, ...}} for the requested model
+// version.
+type Remote struct {
+	cfg RemoteConfig
+}
+
+func NewRemote(cfg RemoteConfig) *Remote {
+	cfg.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Remote{cfg: cfg}
+}
+
+type remoteRequest struct {
+	ModelID      string `json:"model_id"`
+	ModelVersion string `json:"model_version"`
+	Text         string `json:"text"`
+}
+
+type remoteResponse struct {
+	Scores map[string]float64 `json:"scores"`
+}
+
+func (r *Remote) Classify(ctx context.Context, model domain.Model, text string) (map[string]float64, error) {
+	raw, err := json.Marshal(remoteRequest{ModelID: model.ModelID, ModelVersion: model.Version, Text: text})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.BaseURL+"/v1/classify", bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.cfg.Token)
+	}
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrModelUnavailable, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: model server returned %d", domain.ErrModelUnavailable, resp.StatusCode)
+	}
+	var out remoteResponse
+	if err := json.Unmarshal(body, &out); err != nil || len(out.Scores) == 0 {
+		return nil, fmt.Errorf("%w: malformed model server response", domain.ErrModelUnavailable)
+	}
+	scores := make(map[string]float64, len(out.Scores))
+	for label, p := range out.Scores {
+		label, ok := domain.NormalizeLabel(label)
+		if !ok || p < 0 || p > 1 {
+			return nil, fmt.Errorf("%w: malformed model server response", domain.ErrModelUnavailable)
+		}
+		scores[label] = p
+	}
+	return scores, nil
+}
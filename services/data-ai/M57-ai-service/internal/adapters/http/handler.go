@@ -78,15 +78,19 @@ func (h *Handler) getBatchStatus(w http.ResponseWriter, r *http.Request) {
 
 func toPredictionResponse(row domain.Prediction) contracts.PredictionResponse {
 	return contracts.PredictionResponse{
-		PredictionID: row.PredictionID,
-		UserID:       row.UserID,
-		ContentID:    row.ContentID,
-		Label:        row.Label,
-		Confidence:   row.Confidence,
-		Flagged:      row.Flagged,
-		ModelID:      row.ModelID,
-		ModelVersion: row.ModelVersion,
-		CreatedAt:    row.CreatedAt.UTC().Format(time.RFC3339),
+		PredictionID:   row.PredictionID,
+		UserID:         row.UserID,
+		ContentID:      row.ContentID,
+		Label:          row.Label,
+		PredictedLabel: row.PredictedLabel,
+		Confidence:     row.Confidence,
+		Scores:         row.Scores,
+		Flagged:        row.Flagged,
+		ReviewStatus:   row.ReviewStatus,
+		ReviewID:       row.ReviewID,
+		ModelID:        row.ModelID,
+		ModelVersion:   row.ModelVersion,
+		CreatedAt:      row.CreatedAt.UTC().Format(time.RFC3339),
 	}
 }
 
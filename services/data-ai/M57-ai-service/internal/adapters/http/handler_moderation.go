@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/application"
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/contracts"
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+)
+
+func (h *Handler) submitFeedback(w http.ResponseWriter, r *http.Request) {
+	var req contracts.FeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	row, err := h.service.SubmitFeedback(r.Context(), actorFromContext(r.Context()), application.FeedbackInput{
+		PredictionID: strings.TrimSpace(r.PathValue("prediction_id")),
+		Label:        req.Label,
+		Comment:      req.Comment,
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "feedback recorded", contracts.FeedbackResponse{
+		FeedbackID:   row.FeedbackID,
+		PredictionID: row.PredictionID,
+		UserID:       row.UserID,
+		Feedback:     row.Feedback,
+		Label:        row.Label,
+		Comment:      row.Comment,
+		CreatedAt:    row.CreatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *Handler) listReviews(w http.ResponseWriter, r *http.Request) {
+	items, err := h.service.ListReviews(r.Context(), actorFromContext(r.Context()), application.ListReviewsInput{
+		Status: r.URL.Query().Get("status"),
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	out := make([]contracts.ReviewItemResponse, 0, len(items))
+	for _, item := range items {
+		out = append(out, toReviewResponse(item))
+	}
+	writeSuccess(w, http.StatusOK, "review queue", map[string]any{"items": out})
+}
+
+func (h *Handler) decideReview(w http.ResponseWriter, r *http.Request) {
+	var req contracts.ReviewDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	item, err := h.service.DecideReview(r.Context(), actorFromContext(r.Context()), application.ReviewDecisionInput{
+		ReviewID: strings.TrimSpace(r.PathValue("review_id")),
+		Label:    req.Label,
+		Note:     req.Note,
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "review decided", toReviewResponse(item))
+}
+
+func (h *Handler) listModels(w http.ResponseWriter, r *http.Request) {
+	models, err := h.service.ListModels(r.Context(), actorFromContext(r.Context()), r.URL.Query().Get("model_id"))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	out := make([]contracts.ModelResponse, 0, len(models))
+	for _, model := range models {
+		out = append(out, toModelResponse(model))
+	}
+	writeSuccess(w, http.StatusOK, "models", map[string]any{"items": out})
+}
+
+func (h *Handler) trainModel(w http.ResponseWriter, r *http.Request) {
+	var req contracts.TrainModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	examples := make([]domain.Example, 0, len(req.Examples))
+	for _, ex := range req.Examples {
+		examples = append(examples, domain.Example{Text: ex.Text, Label: ex.Label})
+	}
+	model, err := h.service.TrainModel(r.Context(), actorFromContext(r.Context()), application.TrainModelInput{
+		ModelID:         req.ModelID,
+		Version:         req.Version,
+		DisplayName:     req.DisplayName,
+		Backend:         req.Backend,
+		Labels:          req.Labels,
+		Examples:        examples,
+		IncludeFeedback: req.IncludeFeedback,
+		Thresholds:      req.Thresholds,
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusCreated, "model trained", toModelResponse(model))
+}
+
+func (h *Handler) recalibrateModel(w http.ResponseWriter, r *http.Request) {
+	var req contracts.RecalibrateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	model, err := h.service.Recalibrate(r.Context(), actorFromContext(r.Context()), application.RecalibrateInput{
+		ModelID:     strings.TrimSpace(r.PathValue("model_id")),
+		Version:     req.Version,
+		BaseVersion: req.BaseVersion,
+		Thresholds:  req.Thresholds,
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusCreated, "model recalibrated", toModelResponse(model))
+}
+
+func (h *Handler) evaluateShadow(w http.ResponseWriter, r *http.Request) {
+	eval, err := h.service.EvaluateShadow(r.Context(), actorFromContext(r.Context()), r.PathValue("model_id"), r.PathValue("version"))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "shadow evaluation", contracts.ShadowEvaluationResponse{
+		ModelID:          eval.ModelID,
+		CandidateVersion: eval.CandidateVersion,
+		ShadowSamples:    eval.ShadowSamples,
+		LabelledSamples:  eval.LabelledSamples,
+		Agreement:        eval.Agreement,
+		Candidate:        toMetricsResponse(eval.Candidate),
+		Active:           toMetricsResponse(eval.Active),
+		MinSamples:       eval.MinSamples,
+		Promotable:       eval.Promotable,
+		Reason:           eval.Reason,
+		EvaluatedAt:      eval.EvaluatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *Handler) promoteModel(w http.ResponseWriter, r *http.Request) {
+	model, err := h.service.PromoteModel(r.Context(), actorFromContext(r.Context()), r.PathValue("model_id"), r.PathValue("version"))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "model promoted", toModelResponse(model))
+}
+
+func toReviewResponse(item domain.ReviewItem) contracts.ReviewItemResponse {
+	out := contracts.ReviewItemResponse{
+		ReviewID:       item.ReviewID,
+		PredictionID:   item.PredictionID,
+		UserID:         item.UserID,
+		ContentID:      item.ContentID,
+		Content:        item.Content,
+		ModelID:        item.ModelID,
+		ModelVersion:   item.ModelVersion,
+		PredictedLabel: item.PredictedLabel,
+		Confidence:     item.Confidence,
+		Scores:         item.Scores,
+		Status:         item.Status,
+		Decision:       item.Decision,
+		ReviewerID:     item.ReviewerID,
+		Note:           item.Note,
+		CreatedAt:      item.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if item.ResolvedAt != nil {
+		out.ResolvedAt = item.ResolvedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func toModelResponse(model domain.Model) contracts.ModelResponse {
+	out := contracts.ModelResponse{
+		ModelID:      model.ModelID,
+		Version:      model.Version,
+		DisplayName:  model.DisplayName,
+		Status:       model.Status,
+		Active:       model.Active,
+		Backend:      model.Backend,
+		Labels:       model.Labels,
+		Thresholds:   model.Thresholds,
+		TrainedOn:    model.TrainedOn,
+		CalibratedOn: model.CalibratedOn,
+		BaseVersion:  model.BaseVersion,
+		CreatedAt:    model.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if model.Text != nil {
+		out.Temperature = model.Text.Temperature
+	}
+	if model.PromotedAt != nil {
+		out.PromotedAt = model.PromotedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func toMetricsResponse(m domain.ClassifierMetrics) contracts.ClassifierMetricsResponse {
+	out := contracts.ClassifierMetricsResponse{
+		Accuracy:       m.Accuracy,
+		MacroPrecision: m.MacroPrecision,
+		MacroRecall:    m.MacroRecall,
+		MacroF1:        m.MacroF1,
+		Labels:         make([]contracts.LabelMetricsResponse, 0, len(m.Labels)),
+	}
+	for _, l := range m.Labels {
+		out.Labels = append(out.Labels, contracts.LabelMetricsResponse{Label: l.Label, Precision: l.Precision, Recall: l.Recall, Support: l.Support})
+	}
+	return out
+}
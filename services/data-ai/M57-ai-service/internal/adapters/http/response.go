@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/contracts"
@@ -29,6 +30,10 @@ func writeError(w http.ResponseWriter, status int, code, message, requestID stri
 }
 
 func mapDomainError(err error) (int, string) {
+	// Classifier adapters wrap ErrModelUnavailable with the backend's reason.
+	if errors.Is(err, domain.ErrModelUnavailable) {
+		return http.StatusServiceUnavailable, "model_unavailable"
+	}
 	switch err {
 	case nil:
 		return http.StatusOK, ""
@@ -44,6 +49,10 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusBadRequest, "idempotency_key_required"
 	case domain.ErrIdempotencyConflict, domain.ErrConflict:
 		return http.StatusConflict, "conflict"
+	case domain.ErrPromotionBlocked:
+		return http.StatusConflict, "promotion_blocked"
+	case domain.ErrInsufficientTrainingData:
+		return http.StatusUnprocessableEntity, "insufficient_training_data"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
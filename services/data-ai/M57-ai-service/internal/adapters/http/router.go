@@ -31,5 +31,39 @@ func NewRouter(handler *Handler) http.Handler {
 		}
 		handler.getBatchStatus(w, r)
 	})))
+	mux.Handle("/api/v1/ai/predictions/{prediction_id}/feedback", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodPost: handler.submitFeedback,
+	})))
+	mux.Handle("/api/v1/ai/reviews", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodGet: handler.listReviews,
+	})))
+	mux.Handle("/api/v1/ai/reviews/{review_id}/decision", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodPost: handler.decideReview,
+	})))
+	mux.Handle("/api/v1/ai/models", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodGet:  handler.listModels,
+		http.MethodPost: handler.trainModel,
+	})))
+	mux.Handle("/api/v1/ai/models/{model_id}/recalibrate", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodPost: handler.recalibrateModel,
+	})))
+	mux.Handle("/api/v1/ai/models/{model_id}/versions/{version}/shadow-evaluation", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodGet: handler.evaluateShadow,
+	})))
+	mux.Handle("/api/v1/ai/models/{model_id}/versions/{version}/promote", authMiddleware(methods(map[string]http.HandlerFunc{
+		http.MethodPost: handler.promoteModel,
+	})))
 	return requestIDMiddleware(mux)
 }
+
+// methods dispatches on the request method and answers 405 otherwise.
+func methods(byMethod map[string]http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next, ok := byMethod[r.Method]
+		if !ok {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		next(w, r)
+	})
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,25 +14,23 @@ type Repositories struct {
 	Predictions *PredictionRepository
 	BatchJobs   *BatchJobRepository
 	Models      *ModelRepository
+	Shadows     *ShadowRepository
+	Reviews     *ReviewRepository
 	Feedback    *FeedbackRepository
 	Audit       *AuditRepository
 	Idempotency *IdempotencyRepository
 }
 
 func NewRepositories() *Repositories {
-	now := time.Now().UTC()
+	seed := domain.DefaultModel(time.Now().UTC())
 	return &Repositories{
 		Predictions: &PredictionRepository{rowsByID: map[string]domain.Prediction{}},
 		BatchJobs:   &BatchJobRepository{rowsByID: map[string]domain.BatchJob{}},
 		Models: &ModelRepository{rowsByKey: map[string]domain.Model{
-			modelKey("vf-core", "2026.02"): {
-				ModelID:     "vf-core",
-				Version:     "2026.02",
-				DisplayName: "ViralForge Core Classifier",
-				Active:      true,
-				CreatedAt:   now,
-			},
+			modelKey(seed.ModelID, seed.Version): seed,
 		}},
+		Shadows:     &ShadowRepository{rows: make([]domain.ShadowPrediction, 0, 32)},
+		Reviews:     &ReviewRepository{rowsByID: map[string]domain.ReviewItem{}},
 		Feedback:    &FeedbackRepository{rows: make([]domain.FeedbackLog, 0, 32)},
 		Audit:       &AuditRepository{rows: make([]domain.AuditLog, 0, 64)},
 		Idempotency: &IdempotencyRepository{rows: map[string]ports.IdempotencyRecord{}},
@@ -74,6 +73,16 @@ func (r *PredictionRepository) FindByKey(_ context.Context, contentHash, modelID
 	return domain.Prediction{}, false, nil
 }
 
+func (r *PredictionRepository) Update(_ context.Context, row domain.Prediction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rowsByID[row.PredictionID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.rowsByID[row.PredictionID] = row
+	return nil
+}
+
 type BatchJobRepository struct {
 	mu       sync.Mutex
 	rowsByID map[string]domain.BatchJob
@@ -120,10 +129,159 @@ func (r *ModelRepository) GetActive(_ context.Context, modelID, version string)
 	return row, nil
 }
 
+func (r *ModelRepository) Get(_ context.Context, modelID, version string) (domain.Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rowsByKey[modelKey(modelID, version)]
+	if !ok {
+		return domain.Model{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+
+func (r *ModelRepository) ActiveFor(_ context.Context, modelID string) (domain.Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rowsByKey {
+		if row.ModelID == modelID && row.Active {
+			return row, nil
+		}
+	}
+	return domain.Model{}, domain.ErrNotFound
+}
+
+func (r *ModelRepository) Create(_ context.Context, row domain.Model) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := modelKey(row.ModelID, row.Version)
+	if _, ok := r.rowsByKey[key]; ok {
+		return domain.ErrConflict
+	}
+	r.rowsByKey[key] = row
+	return nil
+}
+
+func (r *ModelRepository) List(_ context.Context, modelID string) ([]domain.Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.Model, 0, len(r.rowsByKey))
+	for _, row := range r.rowsByKey {
+		if modelID == "" || row.ModelID == modelID {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ModelID != out[j].ModelID {
+			return out[i].ModelID < out[j].ModelID
+		}
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out, nil
+}
+
+func (r *ModelRepository) Promote(_ context.Context, modelID, version string, at time.Time) (domain.Model, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := modelKey(modelID, version)
+	row, ok := r.rowsByKey[key]
+	if !ok {
+		return domain.Model{}, domain.ErrNotFound
+	}
+	for k, other := range r.rowsByKey {
+		if other.ModelID == modelID && other.Active {
+			other.Active, other.Status = false, domain.ModelStatusRetired
+			r.rowsByKey[k] = other
+		}
+	}
+	row.Active, row.Status, row.PromotedAt = true, domain.ModelStatusActive, &at
+	r.rowsByKey[key] = row
+	return row, nil
+}
+
 func modelKey(modelID, version string) string {
 	return modelID + "@" + version
 }
 
+type ShadowRepository struct {
+	mu   sync.Mutex
+	rows []domain.ShadowPrediction
+}
+
+func (r *ShadowRepository) Append(_ context.Context, row domain.ShadowPrediction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows = append(r.rows, row)
+	return nil
+}
+
+func (r *ShadowRepository) ListByVersion(_ context.Context, modelID, version string) ([]domain.ShadowPrediction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.ShadowPrediction, 0)
+	for _, row := range r.rows {
+		if row.ModelID == modelID && row.ModelVersion == version {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+type ReviewRepository struct {
+	mu       sync.Mutex
+	rowsByID map[string]domain.ReviewItem
+}
+
+func (r *ReviewRepository) Create(_ context.Context, row domain.ReviewItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rowsByID[row.ReviewID]; ok {
+		return domain.ErrConflict
+	}
+	r.rowsByID[row.ReviewID] = row
+	return nil
+}
+
+func (r *ReviewRepository) GetByID(_ context.Context, reviewID string) (domain.ReviewItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rowsByID[reviewID]
+	if !ok {
+		return domain.ReviewItem{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+
+func (r *ReviewRepository) List(_ context.Context, status string) ([]domain.ReviewItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.ReviewItem, 0, len(r.rowsByID))
+	for _, row := range r.rowsByID {
+		if status == "" || row.Status == status {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ReviewID < out[j].ReviewID
+	})
+	return out, nil
+}
+
+func (r *ReviewRepository) Update(_ context.Context, row domain.ReviewItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rowsByID[row.ReviewID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.rowsByID[row.ReviewID] = row
+	return nil
+}
+
 type FeedbackRepository struct {
 	mu   sync.Mutex
 	rows []domain.FeedbackLog
@@ -136,6 +294,12 @@ func (r *FeedbackRepository) Append(_ context.Context, row domain.FeedbackLog) e
 	return nil
 }
 
+func (r *FeedbackRepository) List(_ context.Context) ([]domain.FeedbackLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.FeedbackLog(nil), r.rows...), nil
+}
+
 type AuditRepository struct {
 	mu   sync.Mutex
 	rows []domain.AuditLog
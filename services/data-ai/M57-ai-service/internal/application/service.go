@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -64,8 +65,8 @@ func (s *Service) Analyze(ctx context.Context, actor Actor, in AnalyzeInput) (do
 		return cached, nil
 	}
 
-	row := s.buildPrediction(userID, strings.TrimSpace(in.ContentID), contentHash, content, model)
-	if err := s.predictions.Create(ctx, row); err != nil {
+	row, err := s.predict(ctx, userID, strings.TrimSpace(in.ContentID), contentHash, content, model)
+	if err != nil {
 		return domain.Prediction{}, err
 	}
 	if s.feedback != nil {
@@ -73,7 +74,7 @@ func (s *Service) Analyze(ctx context.Context, actor Actor, in AnalyzeInput) (do
 			FeedbackID:   nextID("fdbk"),
 			PredictionID: row.PredictionID,
 			UserID:       row.UserID,
-			Feedback:     "prediction_created",
+			Feedback:     domain.FeedbackPredictionCreated,
 			CreatedAt:    row.CreatedAt,
 		})
 	}
@@ -163,8 +164,8 @@ func (s *Service) BatchAnalyze(ctx context.Context, actor Actor, in BatchAnalyze
 			return domain.BatchJob{}, err
 		}
 		if !found {
-			row = s.buildPrediction(userID, strings.TrimSpace(item.ContentID), contentHash, content, model)
-			if err := s.predictions.Create(ctx, row); err != nil {
+			row, err = s.predict(ctx, userID, strings.TrimSpace(item.ContentID), contentHash, content, model)
+			if err != nil {
 				return domain.BatchJob{}, err
 			}
 		}
@@ -237,49 +238,131 @@ func canActForUser(actor Actor, userID string) bool {
 func (s *Service) resolveModel(ctx context.Context, modelID, version string) (domain.Model, error) {
 	modelID = strings.TrimSpace(modelID)
 	if modelID == "" {
-		modelID = "vf-core"
+		modelID = s.cfg.DefaultModelID
 	}
 	version = strings.TrimSpace(version)
-	if version == "" {
-		version = "2026.02"
-	}
 	if s.models == nil {
-		return domain.Model{ModelID: modelID, Version: version, Active: true}, nil
+		model := domain.DefaultModel(s.nowFn())
+		if modelID != model.ModelID || (version != "" && version != model.Version) {
+			return domain.Model{}, domain.ErrNotFound
+		}
+		return model, nil
+	}
+	if version == "" {
+		return s.models.ActiveFor(ctx, modelID)
 	}
 	return s.models.GetActive(ctx, modelID, version)
 }
 
-func (s *Service) buildPrediction(userID, contentID, contentHash, content string, model domain.Model) domain.Prediction {
-	label := classify(content)
-	flagged := label != "safe"
-	confidence := 0.91
-	if flagged {
-		confidence = 0.97
-	}
-	return domain.Prediction{
-		PredictionID: nextID("pred"),
-		UserID:       userID,
-		ContentID:    contentID,
-		ContentHash:  contentHash,
-		ModelID:      model.ModelID,
-		ModelVersion: model.Version,
-		Label:        label,
-		Confidence:   confidence,
-		Flagged:      flagged,
-		CreatedAt:    s.nowFn(),
+// predict scores content with model, stores the prediction, queues it for
+// review when its confidence is under the label's threshold or it is drawn
+// for the audit sample, and scores the same content with every shadow
+// version of the model.
+func (s *Service) predict(ctx context.Context, userID, contentID, contentHash, content string, model domain.Model) (domain.Prediction, error) {
+	scores, err := s.score(ctx, model, content)
+	if err != nil {
+		return domain.Prediction{}, err
+	}
+	label, confidence := domain.TopLabel(scores)
+	row := domain.Prediction{
+		PredictionID:   nextID("pred"),
+		UserID:         userID,
+		ContentID:      contentID,
+		ContentHash:    contentHash,
+		ModelID:        model.ModelID,
+		ModelVersion:   model.Version,
+		Label:          label,
+		PredictedLabel: label,
+		Confidence:     confidence,
+		Flagged:        label != domain.LabelSafe,
+		Scores:         scores,
+		Content:        content,
+		CreatedAt:      s.nowFn(),
+	}
+	var review *domain.ReviewItem
+	sampled := s.reviews != nil && confidence >= model.Threshold(label) && s.cfg.ReviewSampleRate > 0 && s.sampleFn() < s.cfg.ReviewSampleRate
+	if sampled {
+		row.ReviewSampleRate = s.cfg.ReviewSampleRate
+	}
+	if s.reviews != nil && (confidence < model.Threshold(label) || sampled) {
+		item := newReviewItem(row)
+		review = &item
+		row.ReviewID, row.ReviewStatus = item.ReviewID, domain.ReviewStatusPending
+	}
+	if err := s.predictions.Create(ctx, row); err != nil {
+		return domain.Prediction{}, err
+	}
+	if review != nil {
+		if err := s.reviews.Create(ctx, *review); err != nil {
+			return domain.Prediction{}, err
+		}
 	}
+	s.runShadows(ctx, row, model)
+	return row, nil
 }
 
-func classify(content string) string {
-	content = strings.ToLower(content)
-	switch {
-	case strings.Contains(content, "dmca"), strings.Contains(content, "copyright"), strings.Contains(content, "pirated"):
-		return "copyright_risk"
-	case strings.Contains(content, "fraud"), strings.Contains(content, "scam"):
-		return "fraud_risk"
-	default:
-		return "safe"
+// runShadows records what each shadow version of model says about the
+// prediction's content. Shadow failures never affect the caller.
+func (s *Service) runShadows(ctx context.Context, row domain.Prediction, active domain.Model) {
+	if s.models == nil || s.shadows == nil {
+		return
+	}
+	versions, err := s.models.List(ctx, active.ModelID)
+	if err != nil {
+		return
+	}
+	for _, candidate := range versions {
+		if candidate.Status != domain.ModelStatusShadow {
+			continue
+		}
+		scores, err := s.score(ctx, candidate, row.Content)
+		if err != nil {
+			continue
+		}
+		label, confidence := domain.TopLabel(scores)
+		_ = s.shadows.Append(ctx, domain.ShadowPrediction{
+			PredictionID: row.PredictionID,
+			ModelID:      candidate.ModelID,
+			ModelVersion: candidate.Version,
+			Label:        label,
+			Confidence:   confidence,
+			Scores:       scores,
+			CreatedAt:    s.nowFn(),
+		})
+	}
+}
+
+func (s *Service) score(ctx context.Context, model domain.Model, content string) (map[string]float64, error) {
+	backend := model.Backend
+	if backend == "" {
+		backend = domain.BackendLocal
+	}
+	classifier, ok := s.classifiers[backend]
+	if !ok {
+		return nil, domain.ErrModelUnavailable
+	}
+	scores, err := classifier.Classify(ctx, model, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) == 0 {
+		return nil, domain.ErrModelUnavailable
+	}
+	out := make(map[string]float64, len(scores))
+	for label, p := range scores {
+		out[label] = math.Round(p*10000) / 10000
+	}
+	return out, nil
+}
+
+// localClassifier scores local models with their in-process TextModel.
+type localClassifier struct{}
+
+func (localClassifier) Classify(_ context.Context, model domain.Model, text string) (map[string]float64, error) {
+	if model.Text == nil {
+		return nil, domain.ErrModelUnavailable
 	}
+	return model.Text.Predict(text), nil
 }
 
 func hashText(v string) string {
@@ -0,0 +1,427 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+)
+
+// ListModels returns every version, of modelID if set, oldest first.
+func (s *Service) ListModels(ctx context.Context, actor Actor, modelID string) ([]domain.Model, error) {
+	if err := requireAdmin(actor); err != nil {
+		return nil, err
+	}
+	return s.models.List(ctx, strings.TrimSpace(modelID))
+}
+
+// TrainModel registers a model version. The first version of a model goes
+// live at once; later ones run in shadow until promoted.
+func (s *Service) TrainModel(ctx context.Context, actor Actor, in TrainModelInput) (domain.Model, error) {
+	if err := requireAdmin(actor); err != nil {
+		return domain.Model{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.Model{}, domain.ErrIdempotencyRequired
+	}
+	modelID, version := s.modelIDOrDefault(in.ModelID), strings.TrimSpace(in.Version)
+	backend := strings.ToLower(strings.TrimSpace(in.Backend))
+	if backend == "" {
+		backend = domain.BackendLocal
+	}
+	if version == "" || (backend != domain.BackendLocal && backend != domain.BackendRemote) {
+		return domain.Model{}, domain.ErrInvalidInput
+	}
+	requestHash := hashJSON(map[string]any{
+		"op":               "train_model",
+		"model_id":         modelID,
+		"version":          version,
+		"display_name":     strings.TrimSpace(in.DisplayName),
+		"backend":          backend,
+		"labels":           in.Labels,
+		"examples":         in.Examples,
+		"include_feedback": in.IncludeFeedback,
+		"thresholds":       in.Thresholds,
+	})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Model{}, err
+	} else if ok {
+		var out domain.Model
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+
+	active, hasActive, err := s.activeModel(ctx, modelID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	model := domain.Model{
+		ModelID:     modelID,
+		Version:     version,
+		DisplayName: strings.TrimSpace(in.DisplayName),
+		Backend:     backend,
+		CreatedAt:   s.nowFn(),
+	}
+	if model.DisplayName == "" {
+		model.DisplayName = modelID + " " + version
+	}
+	switch backend {
+	case domain.BackendLocal:
+		examples := append([]domain.Example(nil), in.Examples...)
+		if len(examples) == 0 && hasActive && active.Text != nil {
+			examples = append(examples, active.Text.Examples...)
+			model.BaseVersion = active.Version
+		}
+		if in.IncludeFeedback {
+			feedback, err := s.feedbackExamples(ctx)
+			if err != nil {
+				return domain.Model{}, err
+			}
+			examples = withFeedback(examples, feedback)
+		}
+		text, err := domain.TrainTextModel(examples)
+		if err != nil {
+			return domain.Model{}, err
+		}
+		model.Text, model.Labels, model.TrainedOn = text, text.Labels, len(text.Examples)
+	case domain.BackendRemote:
+		if _, ok := s.classifiers[domain.BackendRemote]; !ok {
+			return domain.Model{}, domain.ErrModelUnavailable
+		}
+		labels, err := normalizeLabels(in.Labels)
+		if err != nil {
+			return domain.Model{}, err
+		}
+		model.Labels = labels
+	}
+	if model.Thresholds, err = mergeThresholds(model.Labels, active, in.Thresholds); err != nil {
+		return domain.Model{}, err
+	}
+	model.Status = domain.ModelStatusShadow
+	if !hasActive {
+		model.Status, model.Active = domain.ModelStatusActive, true
+		model.PromotedAt = &model.CreatedAt
+	}
+
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Model{}, err
+	}
+	if err := s.models.Create(ctx, model); err != nil {
+		return domain.Model{}, err
+	}
+	s.auditModel(ctx, actor, "ai.model.trained", model)
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, model)
+	return model, nil
+}
+
+// Recalibrate registers a shadow copy of a local version whose softmax
+// temperature is refitted to the reviewed feedback. Weights are unchanged,
+// so it only moves confidences (and with them what reaches review).
+func (s *Service) Recalibrate(ctx context.Context, actor Actor, in RecalibrateInput) (domain.Model, error) {
+	if err := requireAdmin(actor); err != nil {
+		return domain.Model{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.Model{}, domain.ErrIdempotencyRequired
+	}
+	modelID, version := s.modelIDOrDefault(in.ModelID), strings.TrimSpace(in.Version)
+	if version == "" {
+		return domain.Model{}, domain.ErrInvalidInput
+	}
+	baseVersion := strings.TrimSpace(in.BaseVersion)
+	requestHash := hashJSON(map[string]any{
+		"op":           "recalibrate_model",
+		"model_id":     modelID,
+		"version":      version,
+		"base_version": baseVersion,
+		"thresholds":   in.Thresholds,
+	})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Model{}, err
+	} else if ok {
+		var out domain.Model
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+
+	var base domain.Model
+	var err error
+	if baseVersion == "" {
+		base, err = s.models.ActiveFor(ctx, modelID)
+	} else {
+		base, err = s.models.Get(ctx, modelID, baseVersion)
+	}
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if base.Text == nil {
+		return domain.Model{}, domain.ErrInvalidInput
+	}
+	feedback, err := s.feedbackExamples(ctx)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	text, used := base.Text.Calibrated(feedback)
+	if used == 0 {
+		return domain.Model{}, domain.ErrInsufficientTrainingData
+	}
+	model := base
+	model.Version, model.BaseVersion = version, base.Version
+	model.DisplayName = base.DisplayName + " (recalibrated)"
+	model.Active, model.Status, model.PromotedAt = false, domain.ModelStatusShadow, nil
+	model.CreatedAt, model.Text, model.CalibratedOn = s.nowFn(), text, used
+	if model.Thresholds, err = mergeThresholds(model.Labels, base, in.Thresholds); err != nil {
+		return domain.Model{}, err
+	}
+
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Model{}, err
+	}
+	if err := s.models.Create(ctx, model); err != nil {
+		return domain.Model{}, err
+	}
+	s.auditModel(ctx, actor, "ai.model.recalibrated", model)
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, model)
+	return model, nil
+}
+
+// EvaluateShadow reports a shadow version's precision and recall against the
+// version that served the same predictions, scored on reviewed labels.
+func (s *Service) EvaluateShadow(ctx context.Context, actor Actor, modelID, version string) (domain.ShadowEvaluation, error) {
+	if err := requireAdmin(actor); err != nil {
+		return domain.ShadowEvaluation{}, err
+	}
+	return s.evaluateShadow(ctx, s.modelIDOrDefault(modelID), strings.TrimSpace(version))
+}
+
+// PromoteModel makes a shadow version active once its shadow evaluation
+// covers enough reviewed predictions and its macro F1 is no worse than the
+// active version's.
+func (s *Service) PromoteModel(ctx context.Context, actor Actor, modelID, version string) (domain.Model, error) {
+	if err := requireAdmin(actor); err != nil {
+		return domain.Model{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.Model{}, domain.ErrIdempotencyRequired
+	}
+	modelID, version = s.modelIDOrDefault(modelID), strings.TrimSpace(version)
+	requestHash := hashJSON(map[string]any{"op": "promote_model", "model_id": modelID, "version": version})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Model{}, err
+	} else if ok {
+		var out domain.Model
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+	eval, err := s.evaluateShadow(ctx, modelID, version)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if !eval.Promotable {
+		return domain.Model{}, domain.ErrPromotionBlocked
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Model{}, err
+	}
+	model, err := s.models.Promote(ctx, modelID, version, s.nowFn())
+	if err != nil {
+		return domain.Model{}, err
+	}
+	s.auditModel(ctx, actor, "ai.model.promoted", model)
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, model)
+	return model, nil
+}
+
+func (s *Service) evaluateShadow(ctx context.Context, modelID, version string) (domain.ShadowEvaluation, error) {
+	if version == "" {
+		return domain.ShadowEvaluation{}, domain.ErrInvalidInput
+	}
+	candidate, err := s.models.Get(ctx, modelID, version)
+	if err != nil {
+		return domain.ShadowEvaluation{}, err
+	}
+	shadows, err := s.shadows.ListByVersion(ctx, modelID, version)
+	if err != nil {
+		return domain.ShadowEvaluation{}, err
+	}
+	feedback, err := s.feedback.List(ctx)
+	if err != nil {
+		return domain.ShadowEvaluation{}, err
+	}
+	truth := domain.GroundTruth(feedback)
+
+	eval := domain.ShadowEvaluation{
+		ModelID:          modelID,
+		CandidateVersion: version,
+		ShadowSamples:    len(shadows),
+		MinSamples:       s.cfg.MinShadowSamples,
+		EvaluatedAt:      s.nowFn(),
+	}
+	var want, gotCandidate, gotActive []string
+	var weights []float64
+	agree := 0
+	for _, shadow := range shadows {
+		pred, err := s.predictions.GetByID(ctx, shadow.PredictionID)
+		if err != nil {
+			continue
+		}
+		if shadow.Label == pred.PredictedLabel {
+			agree++
+		}
+		if t, ok := truth[shadow.PredictionID]; ok {
+			want = append(want, t.Label)
+			gotCandidate = append(gotCandidate, shadow.Label)
+			gotActive = append(gotActive, pred.PredictedLabel)
+			// An audit-sampled prediction stands for every confident one
+			// that was not drawn.
+			weight := 1.0
+			if pred.ReviewSampleRate > 0 {
+				weight = 1 / pred.ReviewSampleRate
+				eval.SampledSamples++
+			}
+			weights = append(weights, weight)
+		}
+	}
+	if len(shadows) > 0 {
+		eval.Agreement = math.Round(float64(agree)/float64(len(shadows))*10000) / 10000
+	}
+	eval.LabelledSamples = len(want)
+	eval.Candidate = domain.ScoreWeighted(want, gotCandidate, weights)
+	eval.Active = domain.ScoreWeighted(want, gotActive, weights)
+
+	switch {
+	case candidate.Status != domain.ModelStatusShadow:
+		eval.Reason = "version is " + candidate.Status + ", not shadow"
+	case eval.LabelledSamples < eval.MinSamples:
+		eval.Reason = "not enough reviewed shadow predictions"
+	case eval.Candidate.MacroF1 < eval.Active.MacroF1:
+		eval.Reason = "candidate macro F1 is below the active version's"
+	default:
+		eval.Promotable = true
+	}
+	return eval, nil
+}
+
+// feedbackExamples turns the ground-truth label of every reviewed
+// prediction into a training example.
+func (s *Service) feedbackExamples(ctx context.Context) ([]domain.Example, error) {
+	if s.feedback == nil {
+		return nil, nil
+	}
+	rows, err := s.feedback.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	truth := domain.GroundTruth(rows)
+	ids := make([]string, 0, len(truth))
+	for id, row := range truth {
+		if strings.TrimSpace(row.Content) != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	out := make([]domain.Example, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, domain.Example{Text: truth[id].Content, Label: truth[id].Label, PredictionID: id})
+	}
+	return out, nil
+}
+
+// withFeedback adds the reviewed feedback to a training set. Examples that
+// came from earlier feedback on the same prediction are replaced, so a
+// retrain on top of a retrain does not count a prediction twice and picks up
+// changed decisions.
+func withFeedback(examples, feedback []domain.Example) []domain.Example {
+	fresh := make(map[string]bool, len(feedback))
+	for _, ex := range feedback {
+		fresh[ex.PredictionID] = true
+	}
+	out := make([]domain.Example, 0, len(examples)+len(feedback))
+	for _, ex := range examples {
+		if ex.PredictionID == "" || !fresh[ex.PredictionID] {
+			out = append(out, ex)
+		}
+	}
+	return append(out, feedback...)
+}
+
+func (s *Service) activeModel(ctx context.Context, modelID string) (domain.Model, bool, error) {
+	model, err := s.models.ActiveFor(ctx, modelID)
+	if err == domain.ErrNotFound {
+		return domain.Model{}, false, nil
+	}
+	if err != nil {
+		return domain.Model{}, false, err
+	}
+	return model, true, nil
+}
+
+func (s *Service) modelIDOrDefault(modelID string) string {
+	if modelID = strings.TrimSpace(modelID); modelID == "" {
+		return s.cfg.DefaultModelID
+	}
+	return modelID
+}
+
+func (s *Service) auditModel(ctx context.Context, actor Actor, eventType string, model domain.Model) {
+	if s.audit == nil {
+		return
+	}
+	_ = s.audit.Append(ctx, domain.AuditLog{
+		EventID:    nextID("audit"),
+		EventType:  eventType,
+		ActorID:    actor.SubjectID,
+		EntityID:   model.ModelID + "@" + model.Version,
+		OccurredAt: s.nowFn(),
+		Metadata: map[string]string{
+			"status":  model.Status,
+			"backend": model.Backend,
+		},
+	})
+}
+
+func normalizeLabels(labels []string) ([]string, error) {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(labels))
+	for _, raw := range labels {
+		label, ok := domain.NormalizeLabel(raw)
+		if !ok {
+			return nil, domain.ErrInvalidInput
+		}
+		if !seen[label] {
+			seen[label] = true
+			out = append(out, label)
+		}
+	}
+	if len(out) < 2 {
+		return nil, domain.ErrInvalidInput
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// mergeThresholds gives every label a threshold: the requested one, else the
+// base version's, else domain.DefaultThreshold.
+func mergeThresholds(labels []string, base domain.Model, requested map[string]float64) (map[string]float64, error) {
+	out := make(map[string]float64, len(labels))
+	for _, label := range labels {
+		out[label] = base.Threshold(label)
+	}
+	for raw, t := range requested {
+		label, ok := domain.NormalizeLabel(raw)
+		if !ok || t <= 0 || t > 1 {
+			return nil, domain.ErrInvalidInput
+		}
+		if _, known := out[label]; !known {
+			return nil, domain.ErrInvalidInput
+		}
+		out[label] = t
+	}
+	return out, nil
+}
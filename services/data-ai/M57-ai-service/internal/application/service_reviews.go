@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+)
+
+func newReviewItem(row domain.Prediction) domain.ReviewItem {
+	return domain.ReviewItem{
+		ReviewID:       nextID("review"),
+		PredictionID:   row.PredictionID,
+		UserID:         row.UserID,
+		ContentID:      row.ContentID,
+		Content:        row.Content,
+		ModelID:        row.ModelID,
+		ModelVersion:   row.ModelVersion,
+		PredictedLabel: row.PredictedLabel,
+		Confidence:     row.Confidence,
+		Scores:         row.Scores,
+		Status:         domain.ReviewStatusPending,
+		CreatedAt:      row.CreatedAt,
+	}
+}
+
+// ListReviews returns review items oldest first, pending ones by default.
+func (s *Service) ListReviews(ctx context.Context, actor Actor, in ListReviewsInput) ([]domain.ReviewItem, error) {
+	if err := requireReviewer(actor); err != nil {
+		return nil, err
+	}
+	status := strings.ToLower(strings.TrimSpace(in.Status))
+	switch status {
+	case "":
+		status = domain.ReviewStatusPending
+	case "all":
+		status = ""
+	case domain.ReviewStatusPending, domain.ReviewStatusResolved:
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+	return s.reviews.List(ctx, status)
+}
+
+// DecideReview records a reviewer's label for a queued prediction. The
+// decision replaces the prediction's label and becomes training feedback.
+func (s *Service) DecideReview(ctx context.Context, actor Actor, in ReviewDecisionInput) (domain.ReviewItem, error) {
+	if err := requireReviewer(actor); err != nil {
+		return domain.ReviewItem{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.ReviewItem{}, domain.ErrIdempotencyRequired
+	}
+	reviewID := strings.TrimSpace(in.ReviewID)
+	label, ok := domain.NormalizeLabel(in.Label)
+	if reviewID == "" || !ok {
+		return domain.ReviewItem{}, domain.ErrInvalidInput
+	}
+	note := strings.TrimSpace(in.Note)
+	requestHash := hashJSON(map[string]any{
+		"op":        "review_decision",
+		"review_id": reviewID,
+		"label":     label,
+		"note":      note,
+	})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.ReviewItem{}, err
+	} else if ok {
+		var out domain.ReviewItem
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+
+	item, err := s.reviews.GetByID(ctx, reviewID)
+	if err != nil {
+		return domain.ReviewItem{}, err
+	}
+	if item.Status != domain.ReviewStatusPending {
+		return domain.ReviewItem{}, domain.ErrConflict
+	}
+	pred, err := s.predictions.GetByID(ctx, item.PredictionID)
+	if err != nil {
+		return domain.ReviewItem{}, err
+	}
+	if err := s.requireModelLabel(ctx, pred, label); err != nil {
+		return domain.ReviewItem{}, err
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.ReviewItem{}, err
+	}
+
+	now := s.nowFn()
+	item.Status, item.Decision, item.ReviewerID, item.Note, item.ResolvedAt = domain.ReviewStatusResolved, label, actor.SubjectID, note, &now
+	if err := s.reviews.Update(ctx, item); err != nil {
+		return domain.ReviewItem{}, err
+	}
+	pred.Label, pred.Flagged, pred.ReviewStatus = label, label != domain.LabelSafe, domain.ReviewStatusResolved
+	if err := s.predictions.Update(ctx, pred); err != nil {
+		return domain.ReviewItem{}, err
+	}
+	if s.feedback != nil {
+		_ = s.feedback.Append(ctx, domain.FeedbackLog{
+			FeedbackID:   nextID("fdbk"),
+			PredictionID: pred.PredictionID,
+			UserID:       actor.SubjectID,
+			Feedback:     domain.FeedbackReviewDecision,
+			Label:        label,
+			Comment:      note,
+			Content:      pred.Content,
+			CreatedAt:    now,
+		})
+	}
+	if s.audit != nil {
+		_ = s.audit.Append(ctx, domain.AuditLog{
+			EventID:    nextID("audit"),
+			EventType:  "ai.review.decided",
+			ActorID:    actor.SubjectID,
+			EntityID:   item.ReviewID,
+			OccurredAt: now,
+			Metadata: map[string]string{
+				"prediction_id":   pred.PredictionID,
+				"predicted_label": item.PredictedLabel,
+				"decision":        label,
+			},
+		})
+	}
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, item)
+	return item, nil
+}
+
+// SubmitFeedback lets the prediction's owner (or support) confirm or correct
+// its label. A correction reopens the prediction for human review; only a
+// reviewer's decision changes the label.
+func (s *Service) SubmitFeedback(ctx context.Context, actor Actor, in FeedbackInput) (domain.FeedbackLog, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.FeedbackLog{}, domain.ErrUnauthorized
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.FeedbackLog{}, domain.ErrIdempotencyRequired
+	}
+	predictionID := strings.TrimSpace(in.PredictionID)
+	label, ok := domain.NormalizeLabel(in.Label)
+	if predictionID == "" || !ok {
+		return domain.FeedbackLog{}, domain.ErrInvalidInput
+	}
+	comment := strings.TrimSpace(in.Comment)
+	requestHash := hashJSON(map[string]any{
+		"op":            "feedback",
+		"prediction_id": predictionID,
+		"label":         label,
+		"comment":       comment,
+	})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.FeedbackLog{}, err
+	} else if ok {
+		var out domain.FeedbackLog
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+
+	pred, err := s.predictions.GetByID(ctx, predictionID)
+	if err != nil {
+		return domain.FeedbackLog{}, err
+	}
+	if !canActForUser(actor, pred.UserID) {
+		return domain.FeedbackLog{}, domain.ErrForbidden
+	}
+	if err := s.requireModelLabel(ctx, pred, label); err != nil {
+		return domain.FeedbackLog{}, err
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.FeedbackLog{}, err
+	}
+
+	row := domain.FeedbackLog{
+		FeedbackID:   nextID("fdbk"),
+		PredictionID: pred.PredictionID,
+		UserID:       actor.SubjectID,
+		Feedback:     domain.FeedbackUserConfirmation,
+		Label:        label,
+		Comment:      comment,
+		Content:      pred.Content,
+		CreatedAt:    s.nowFn(),
+	}
+	if label != pred.Label {
+		row.Feedback = domain.FeedbackUserCorrection
+		if s.reviews != nil && pred.ReviewStatus != domain.ReviewStatusPending {
+			item := newReviewItem(pred)
+			item.CreatedAt = row.CreatedAt
+			if err := s.reviews.Create(ctx, item); err != nil {
+				return domain.FeedbackLog{}, err
+			}
+			pred.ReviewID, pred.ReviewStatus = item.ReviewID, domain.ReviewStatusPending
+			if err := s.predictions.Update(ctx, pred); err != nil {
+				return domain.FeedbackLog{}, err
+			}
+		}
+	}
+	if err := s.feedback.Append(ctx, row); err != nil {
+		return domain.FeedbackLog{}, err
+	}
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, row)
+	return row, nil
+}
+
+func (s *Service) requireModelLabel(ctx context.Context, pred domain.Prediction, label string) error {
+	if s.models == nil {
+		return nil
+	}
+	model, err := s.models.Get(ctx, pred.ModelID, pred.ModelVersion)
+	if err != nil {
+		return err
+	}
+	if !model.HasLabel(label) {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
+func requireReviewer(actor Actor) error {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ErrUnauthorized
+	}
+	switch strings.ToLower(strings.TrimSpace(actor.Role)) {
+	case "admin", "support", "moderator":
+		return nil
+	default:
+		return domain.ErrForbidden
+	}
+}
+
+func requireAdmin(actor Actor) error {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ErrUnauthorized
+	}
+	if strings.ToLower(strings.TrimSpace(actor.Role)) != "admin" {
+		return domain.ErrForbidden
+	}
+	return nil
+}
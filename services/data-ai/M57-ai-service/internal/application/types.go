@@ -1,14 +1,24 @@
 package application
 
 import (
+	"math/rand"
 	"time"
 
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/ports"
 )
 
 type Config struct {
 	ServiceName    string
 	IdempotencyTTL time.Duration
+	DefaultModelID string
+	// MinShadowSamples is how many reviewed shadow predictions a candidate
+	// version needs before it can be promoted.
+	MinShadowSamples int
+	// ReviewSampleRate is the share of confident predictions also queued
+	// for review, so shadow evaluation is not limited to uncertain ones.
+	// Zero disables sampling.
+	ReviewSampleRate float64
 }
 
 type Actor struct {
@@ -38,15 +48,59 @@ type BatchAnalyzeInput struct {
 	Items        []BatchItemInput
 }
 
+// TrainModelInput registers a model version. Local versions are trained on
+// Examples plus, with IncludeFeedback, every reviewed prediction; with no
+// Examples they start from the active version's training set. Remote
+// versions declare their Labels instead.
+type TrainModelInput struct {
+	ModelID         string
+	Version         string
+	DisplayName     string
+	Backend         string
+	Labels          []string
+	Examples        []domain.Example
+	IncludeFeedback bool
+	Thresholds      map[string]float64
+}
+
+// RecalibrateInput registers Version as a copy of BaseVersion (the active
+// version by default) with its temperature refitted to reviewed feedback.
+type RecalibrateInput struct {
+	ModelID     string
+	Version     string
+	BaseVersion string
+	Thresholds  map[string]float64
+}
+
+type ListReviewsInput struct {
+	Status string
+}
+
+type ReviewDecisionInput struct {
+	ReviewID string
+	Label    string
+	Note     string
+}
+
+type FeedbackInput struct {
+	PredictionID string
+	Label        string
+	Comment      string
+}
+
 type Service struct {
 	cfg         Config
 	predictions ports.PredictionRepository
 	batchJobs   ports.BatchJobRepository
 	models      ports.ModelRepository
+	shadows     ports.ShadowRepository
+	reviews     ports.ReviewRepository
 	feedback    ports.FeedbackRepository
 	audit       ports.AuditRepository
 	idempotency ports.IdempotencyRepository
+	classifiers map[string]ports.Classifier
 	nowFn       func() time.Time
+	sampleFn    func() float64
 }
 
 type Dependencies struct {
@@ -54,9 +108,14 @@ type Dependencies struct {
 	Predictions ports.PredictionRepository
 	BatchJobs   ports.BatchJobRepository
 	Models      ports.ModelRepository
+	Shadows     ports.ShadowRepository
+	Reviews     ports.ReviewRepository
 	Feedback    ports.FeedbackRepository
 	Audit       ports.AuditRepository
 	Idempotency ports.IdempotencyRepository
+	// Classifiers maps a model Backend to the classifier that scores it.
+	// Local models are scored in-process unless overridden.
+	Classifiers map[string]ports.Classifier
 }
 
 func NewService(deps Dependencies) *Service {
@@ -67,14 +126,33 @@ func NewService(deps Dependencies) *Service {
 	if cfg.IdempotencyTTL <= 0 {
 		cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
+	if cfg.DefaultModelID == "" {
+		cfg.DefaultModelID = "vf-core"
+	}
+	if cfg.MinShadowSamples <= 0 {
+		cfg.MinShadowSamples = 20
+	}
+	if cfg.ReviewSampleRate < 0 || cfg.ReviewSampleRate > 1 {
+		cfg.ReviewSampleRate = 0
+	}
+	classifiers := map[string]ports.Classifier{domain.BackendLocal: localClassifier{}}
+	for backend, c := range deps.Classifiers {
+		if c != nil {
+			classifiers[backend] = c
+		}
+	}
 	return &Service{
 		cfg:         cfg,
 		predictions: deps.Predictions,
 		batchJobs:   deps.BatchJobs,
 		models:      deps.Models,
+		shadows:     deps.Shadows,
+		reviews:     deps.Reviews,
 		feedback:    deps.Feedback,
 		audit:       deps.Audit,
 		idempotency: deps.Idempotency,
+		classifiers: classifiers,
 		nowFn:       func() time.Time { return time.Now().UTC() },
+		sampleFn:    rand.Float64,
 	}
 }
@@ -41,15 +41,19 @@ type BatchAnalyzeRequest struct {
 }
 
 type PredictionResponse struct {
-	PredictionID string  `json:"prediction_id"`
-	UserID       string  `json:"user_id"`
-	ContentID    string  `json:"content_id,omitempty"`
-	Label        string  `json:"label"`
-	Confidence   float64 `json:"confidence"`
-	Flagged      bool    `json:"flagged"`
-	ModelID      string  `json:"model_id"`
-	ModelVersion string  `json:"model_version"`
-	CreatedAt    string  `json:"created_at"`
+	PredictionID   string             `json:"prediction_id"`
+	UserID         string             `json:"user_id"`
+	ContentID      string             `json:"content_id,omitempty"`
+	Label          string             `json:"label"`
+	PredictedLabel string             `json:"predicted_label"`
+	Confidence     float64            `json:"confidence"`
+	Scores         map[string]float64 `json:"scores,omitempty"`
+	Flagged        bool               `json:"flagged"`
+	ReviewStatus   string             `json:"review_status,omitempty"`
+	ReviewID       string             `json:"review_id,omitempty"`
+	ModelID        string             `json:"model_id"`
+	ModelVersion   string             `json:"model_version"`
+	CreatedAt      string             `json:"created_at"`
 }
 
 type BatchStatusResponse struct {
@@ -65,3 +69,110 @@ type BatchStatusResponse struct {
 	StatusURL      string               `json:"status_url"`
 	Predictions    []PredictionResponse `json:"predictions,omitempty"`
 }
+
+type FeedbackRequest struct {
+	Label   string `json:"label"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type FeedbackResponse struct {
+	FeedbackID   string `json:"feedback_id"`
+	PredictionID string `json:"prediction_id"`
+	UserID       string `json:"user_id"`
+	Feedback     string `json:"feedback"`
+	Label        string `json:"label"`
+	Comment      string `json:"comment,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type ReviewDecisionRequest struct {
+	Label string `json:"label"`
+	Note  string `json:"note,omitempty"`
+}
+
+type ReviewItemResponse struct {
+	ReviewID       string             `json:"review_id"`
+	PredictionID   string             `json:"prediction_id"`
+	UserID         string             `json:"user_id"`
+	ContentID      string             `json:"content_id,omitempty"`
+	Content        string             `json:"content,omitempty"`
+	ModelID        string             `json:"model_id"`
+	ModelVersion   string             `json:"model_version"`
+	PredictedLabel string             `json:"predicted_label"`
+	Confidence     float64            `json:"confidence"`
+	Scores         map[string]float64 `json:"scores,omitempty"`
+	Status         string             `json:"status"`
+	Decision       string             `json:"decision,omitempty"`
+	ReviewerID     string             `json:"reviewer_id,omitempty"`
+	Note           string             `json:"note,omitempty"`
+	CreatedAt      string             `json:"created_at"`
+	ResolvedAt     string             `json:"resolved_at,omitempty"`
+}
+
+type ExampleRequest struct {
+	Text  string `json:"text"`
+	Label string `json:"label"`
+}
+
+type TrainModelRequest struct {
+	ModelID         string             `json:"model_id,omitempty"`
+	Version         string             `json:"version"`
+	DisplayName     string             `json:"display_name,omitempty"`
+	Backend         string             `json:"backend,omitempty"`
+	Labels          []string           `json:"labels,omitempty"`
+	Examples        []ExampleRequest   `json:"examples,omitempty"`
+	IncludeFeedback bool               `json:"include_feedback,omitempty"`
+	Thresholds      map[string]float64 `json:"thresholds,omitempty"`
+}
+
+type RecalibrateRequest struct {
+	Version     string             `json:"version"`
+	BaseVersion string             `json:"base_version,omitempty"`
+	Thresholds  map[string]float64 `json:"thresholds,omitempty"`
+}
+
+type ModelResponse struct {
+	ModelID      string             `json:"model_id"`
+	Version      string             `json:"version"`
+	DisplayName  string             `json:"display_name"`
+	Status       string             `json:"status"`
+	Active       bool               `json:"active"`
+	Backend      string             `json:"backend"`
+	Labels       []string           `json:"labels"`
+	Thresholds   map[string]float64 `json:"thresholds"`
+	TrainedOn    int                `json:"trained_on,omitempty"`
+	CalibratedOn int                `json:"calibrated_on,omitempty"`
+	Temperature  float64            `json:"temperature,omitempty"`
+	BaseVersion  string             `json:"base_version,omitempty"`
+	CreatedAt    string             `json:"created_at"`
+	PromotedAt   string             `json:"promoted_at,omitempty"`
+}
+
+type LabelMetricsResponse struct {
+	Label     string  `json:"label"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	Support   int     `json:"support"`
+}
+
+type ClassifierMetricsResponse struct {
+	Accuracy       float64                `json:"accuracy"`
+	MacroPrecision float64                `json:"macro_precision"`
+	MacroRecall    float64                `json:"macro_recall"`
+	MacroF1        float64                `json:"macro_f1"`
+	Labels         []LabelMetricsResponse `json:"labels"`
+}
+
+type ShadowEvaluationResponse struct {
+	ModelID          string                    `json:"model_id"`
+	CandidateVersion string                    `json:"candidate_version"`
+	ShadowSamples    int                       `json:"shadow_samples"`
+	LabelledSamples  int                       `json:"labelled_samples"`
+	Agreement        float64                   `json:"agreement"`
+	Candidate        ClassifierMetricsResponse `json:"candidate"`
+	Active           ClassifierMetricsResponse `json:"active"`
+	MinSamples       int                       `json:"min_samples"`
+	Promotable       bool                      `json:"promotable"`
+	Reason           string                    `json:"reason,omitempty"`
+	EvaluatedAt      string                    `json:"evaluated_at"`
+}
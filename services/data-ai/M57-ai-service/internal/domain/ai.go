@@ -7,6 +7,31 @@ const (
 	BatchStatusCompleted = "completed"
 )
 
+const (
+	ModelStatusActive  = "active"
+	ModelStatusShadow  = "shadow"
+	ModelStatusRetired = "retired"
+
+	BackendLocal  = "local"
+	BackendRemote = "remote"
+)
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusResolved = "resolved"
+)
+
+const (
+	FeedbackPredictionCreated = "prediction_created"
+	FeedbackReviewDecision    = "review_decision"
+	FeedbackUserCorrection    = "user_correction"
+	FeedbackUserConfirmation  = "user_confirmation"
+)
+
+// DefaultThreshold is the confidence a label needs to skip human review when
+// the model does not set its own threshold for that label.
+const DefaultThreshold = 0.6
+
 type Prediction struct {
 	PredictionID string    `json:"prediction_id"`
 	UserID       string    `json:"user_id"`
@@ -18,6 +43,18 @@ type Prediction struct {
 	Confidence   float64   `json:"confidence"`
 	Flagged      bool      `json:"flagged"`
 	CreatedAt    time.Time `json:"created_at"`
+	// PredictedLabel is the model's label; Label becomes the reviewer's
+	// decision once the prediction has been reviewed.
+	PredictedLabel string             `json:"predicted_label"`
+	Scores         map[string]float64 `json:"scores,omitempty"`
+	ReviewID       string             `json:"review_id,omitempty"`
+	ReviewStatus   string             `json:"review_status,omitempty"`
+	// ReviewSampleRate is set when a confident prediction was queued for
+	// review as part of the random audit sample, at that rate.
+	ReviewSampleRate float64 `json:"-"`
+	// Content is kept for review and retraining and is only shown to
+	// reviewers on review items.
+	Content string `json:"-"`
 }
 
 type BatchJob struct {
@@ -41,6 +78,97 @@ type Model struct {
 	DisplayName string    `json:"display_name"`
 	Active      bool      `json:"active"`
 	CreatedAt   time.Time `json:"created_at"`
+	// Status is active for the version serving traffic, shadow for
+	// candidates scored alongside it, and retired once replaced.
+	Status     string             `json:"status"`
+	Backend    string             `json:"backend"`
+	Labels     []string           `json:"labels"`
+	Thresholds map[string]float64 `json:"thresholds"`
+	// TrainedOn counts training examples, and CalibratedOn the feedback a
+	// recalibration fitted the temperature to.
+	TrainedOn    int        `json:"trained_on,omitempty"`
+	CalibratedOn int        `json:"calibrated_on,omitempty"`
+	BaseVersion  string     `json:"base_version,omitempty"`
+	PromotedAt   *time.Time `json:"promoted_at,omitempty"`
+	Text         *TextModel `json:"-"`
+}
+
+// Threshold returns the confidence label needs to skip human review.
+func (m Model) Threshold(label string) float64 {
+	if t, ok := m.Thresholds[label]; ok {
+		return t
+	}
+	return DefaultThreshold
+}
+
+// HasLabel reports whether the model can emit label.
+func (m Model) HasLabel(label string) bool {
+	for _, l := range m.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultModel is vf-core trained on SeedExamples, the version every
+// deployment starts with.
+func DefaultModel(at time.Time) Model {
+	text, err := TrainTextModel(SeedExamples)
+	if err != nil {
+		panic("seed examples do not train: " + err.Error())
+	}
+	return Model{
+		ModelID:     "vf-core",
+		Version:     "2026.02",
+		DisplayName: "ViralForge Core Classifier",
+		Active:      true,
+		CreatedAt:   at,
+		Status:      ModelStatusActive,
+		Backend:     BackendLocal,
+		Labels:      text.Labels,
+		Thresholds: map[string]float64{
+			LabelSafe:          0.5,
+			LabelCopyrightRisk: 0.7,
+			LabelFraudRisk:     0.7,
+		},
+		TrainedOn: len(text.Examples),
+		Text:      text,
+	}
+}
+
+// ShadowPrediction is a candidate version's verdict on content the active
+// version labelled. It never reaches the caller.
+type ShadowPrediction struct {
+	PredictionID string             `json:"prediction_id"`
+	ModelID      string             `json:"model_id"`
+	ModelVersion string             `json:"model_version"`
+	Label        string             `json:"label"`
+	Confidence   float64            `json:"confidence"`
+	Scores       map[string]float64 `json:"scores,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// ReviewItem queues a prediction for a human decision: one whose confidence
+// fell below its label's threshold, one a user corrected, or a confident one
+// drawn for the audit sample.
+type ReviewItem struct {
+	ReviewID       string             `json:"review_id"`
+	PredictionID   string             `json:"prediction_id"`
+	UserID         string             `json:"user_id"`
+	ContentID      string             `json:"content_id,omitempty"`
+	Content        string             `json:"content,omitempty"`
+	ModelID        string             `json:"model_id"`
+	ModelVersion   string             `json:"model_version"`
+	PredictedLabel string             `json:"predicted_label"`
+	Confidence     float64            `json:"confidence"`
+	Scores         map[string]float64 `json:"scores,omitempty"`
+	Status         string             `json:"status"`
+	Decision       string             `json:"decision,omitempty"`
+	ReviewerID     string             `json:"reviewer_id,omitempty"`
+	Note           string             `json:"note,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ResolvedAt     *time.Time         `json:"resolved_at,omitempty"`
 }
 
 type FeedbackLog struct {
@@ -49,6 +177,11 @@ type FeedbackLog struct {
 	UserID       string    `json:"user_id"`
 	Feedback     string    `json:"feedback"`
 	CreatedAt    time.Time `json:"created_at"`
+	// Label is the correct label for review decisions and user
+	// corrections; it is empty for lifecycle entries.
+	Label   string `json:"label,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	Content string `json:"-"`
 }
 
 type AuditLog struct {
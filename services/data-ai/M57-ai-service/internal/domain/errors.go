@@ -10,4 +10,8 @@ var (
 	ErrConflict            = errors.New("conflict")
 	ErrIdempotencyRequired = errors.New("idempotency_key_required")
 	ErrIdempotencyConflict = errors.New("idempotency_conflict")
+
+	ErrInsufficientTrainingData = errors.New("insufficient_training_data")
+	ErrModelUnavailable         = errors.New("model_unavailable")
+	ErrPromotionBlocked         = errors.New("promotion_blocked")
 )
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type LabelMetrics struct {
+	Label     string  `json:"label"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	// Support counts samples whose true label is Label.
+	Support int `json:"support"`
+}
+
+type ClassifierMetrics struct {
+	Accuracy       float64        `json:"accuracy"`
+	MacroPrecision float64        `json:"macro_precision"`
+	MacroRecall    float64        `json:"macro_recall"`
+	MacroF1        float64        `json:"macro_f1"`
+	Labels         []LabelMetrics `json:"labels"`
+}
+
+// ShadowEvaluation compares a shadow version with the version that served
+// the same predictions, on the predictions that have a reviewed label.
+// SampledSamples counts the labelled predictions that were confident and
+// only reviewed as part of the audit sample.
+type ShadowEvaluation struct {
+	ModelID          string            `json:"model_id"`
+	CandidateVersion string            `json:"candidate_version"`
+	ShadowSamples    int               `json:"shadow_samples"`
+	LabelledSamples  int               `json:"labelled_samples"`
+	SampledSamples   int               `json:"sampled_samples"`
+	Agreement        float64           `json:"agreement"`
+	Candidate        ClassifierMetrics `json:"candidate"`
+	Active           ClassifierMetrics `json:"active"`
+	MinSamples       int               `json:"min_samples"`
+	Promotable       bool              `json:"promotable"`
+	Reason           string            `json:"reason,omitempty"`
+	EvaluatedAt      time.Time         `json:"evaluated_at"`
+}
+
+// ScoreClassifier scores predicted against truth. Labels that appear in
+// neither are left out of the macro averages; a label that was never
+// predicted has zero precision.
+func ScoreClassifier(truth, predicted []string) ClassifierMetrics {
+	return ScoreWeighted(truth, predicted, nil)
+}
+
+// ScoreWeighted is ScoreClassifier with a weight per sample, so samples
+// drawn at a lower rate can stand for the traffic they were drawn from. A
+// nil weights slice weighs every sample 1. Support stays a plain count.
+func ScoreWeighted(truth, predicted []string, weights []float64) ClassifierMetrics {
+	type counts struct {
+		tp, fp, fn float64
+		support    int
+	}
+	byLabel := map[string]*counts{}
+	get := func(label string) *counts {
+		if byLabel[label] == nil {
+			byLabel[label] = &counts{}
+		}
+		return byLabel[label]
+	}
+	var correct, total float64
+	for i := range truth {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		total += w
+		get(truth[i]).support++
+		if truth[i] == predicted[i] {
+			correct += w
+			get(truth[i]).tp += w
+			continue
+		}
+		get(truth[i]).fn += w
+		get(predicted[i]).fp += w
+	}
+	out := ClassifierMetrics{Labels: make([]LabelMetrics, 0, len(byLabel))}
+	if len(truth) == 0 || total == 0 {
+		return out
+	}
+	out.Accuracy = round4(correct / total)
+	for label, c := range byLabel {
+		m := LabelMetrics{Label: label, Support: c.support}
+		if c.tp+c.fp > 0 {
+			m.Precision = c.tp / (c.tp + c.fp)
+		}
+		if c.tp+c.fn > 0 {
+			m.Recall = c.tp / (c.tp + c.fn)
+		}
+		out.MacroPrecision += m.Precision
+		out.MacroRecall += m.Recall
+		m.Precision, m.Recall = round4(m.Precision), round4(m.Recall)
+		out.Labels = append(out.Labels, m)
+	}
+	sort.Slice(out.Labels, func(i, j int) bool { return out.Labels[i].Label < out.Labels[j].Label })
+	n := float64(len(byLabel))
+	out.MacroPrecision /= n
+	out.MacroRecall /= n
+	if out.MacroPrecision+out.MacroRecall > 0 {
+		out.MacroF1 = 2 * out.MacroPrecision * out.MacroRecall / (out.MacroPrecision + out.MacroRecall)
+	}
+	out.MacroPrecision, out.MacroRecall, out.MacroF1 = round4(out.MacroPrecision), round4(out.MacroRecall), round4(out.MacroF1)
+	return out
+}
+
+// GroundTruth picks the label each prediction should have had from the
+// latest reviewer decision in the feedback log. User corrections and
+// confirmations are the submitter's own word and are not trusted as labels.
+func GroundTruth(rows []FeedbackLog) map[string]FeedbackLog {
+	out := map[string]FeedbackLog{}
+	for _, row := range rows {
+		if row.Label == "" || row.Feedback != FeedbackReviewDecision {
+			continue
+		}
+		prev, ok := out[row.PredictionID]
+		if !ok || !row.CreatedAt.Before(prev.CreatedAt) {
+			out[row.PredictionID] = row
+		}
+	}
+	return out
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,322 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	LabelSafe          = "safe"
+	LabelCopyrightRisk = "copyright_risk"
+	LabelFraudRisk     = "fraud_risk"
+)
+
+const (
+	trainEpochs       = 300
+	trainLearningRate = 2.0
+	trainL2           = 1e-3
+	minTrainExamples  = 4
+)
+
+// Example is one labelled text used to train or calibrate a model.
+// PredictionID is set on examples taken from reviewed predictions.
+type Example struct {
+	Text         string `json:"text"`
+	Label        string `json:"label"`
+	PredictionID string `json:"prediction_id,omitempty"`
+}
+
+// TextModel is a TF-IDF bag of unigrams and bigrams feeding a multinomial
+// logistic regression. Temperature divides the logits before the softmax and
+// is the only parameter recalibration changes.
+type TextModel struct {
+	Labels      []string       `json:"labels"`
+	Vocabulary  map[string]int `json:"vocabulary"`
+	IDF         []float64      `json:"idf"`
+	Weights     [][]float64    `json:"weights"`
+	Bias        []float64      `json:"bias"`
+	Temperature float64        `json:"temperature"`
+	// Examples is the training set, kept so a retrain can extend it with
+	// reviewed feedback.
+	Examples []Example `json:"-"`
+}
+
+// NormalizeLabel lowercases a label and rejects anything but [a-z0-9_].
+func NormalizeLabel(label string) (string, bool) {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "" {
+		return "", false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' {
+			return "", false
+		}
+	}
+	return label, true
+}
+
+// Tokenize lowercases text and returns its unigrams followed by its bigrams
+// (joined with "_"). Single characters are dropped.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, w := range words {
+		if len([]rune(w)) > 1 {
+			kept = append(kept, w)
+		}
+	}
+	tokens := append([]string(nil), kept...)
+	for i := 1; i < len(kept); i++ {
+		tokens = append(tokens, kept[i-1]+"_"+kept[i])
+	}
+	return tokens
+}
+
+// TrainTextModel fits a model on examples. It needs at least four examples
+// spanning two labels.
+func TrainTextModel(examples []Example) (*TextModel, error) {
+	clean := make([]Example, 0, len(examples))
+	labelSet := map[string]bool{}
+	for _, ex := range examples {
+		label, ok := NormalizeLabel(ex.Label)
+		if !ok || strings.TrimSpace(ex.Text) == "" {
+			return nil, ErrInvalidInput
+		}
+		clean = append(clean, Example{Text: strings.TrimSpace(ex.Text), Label: label, PredictionID: ex.PredictionID})
+		labelSet[label] = true
+	}
+	if len(clean) < minTrainExamples || len(labelSet) < 2 {
+		return nil, ErrInsufficientTrainingData
+	}
+
+	m := &TextModel{Vocabulary: map[string]int{}, Temperature: 1, Examples: clean}
+	for label := range labelSet {
+		m.Labels = append(m.Labels, label)
+	}
+	sort.Strings(m.Labels)
+	labelIndex := make(map[string]int, len(m.Labels))
+	for i, label := range m.Labels {
+		labelIndex[label] = i
+	}
+
+	docFreq := map[string]int{}
+	for _, ex := range clean {
+		seen := map[string]bool{}
+		for _, tok := range Tokenize(ex.Text) {
+			if !seen[tok] {
+				seen[tok] = true
+				docFreq[tok]++
+			}
+		}
+	}
+	terms := make([]string, 0, len(docFreq))
+	for tok := range docFreq {
+		terms = append(terms, tok)
+	}
+	sort.Strings(terms)
+	m.IDF = make([]float64, len(terms))
+	n := float64(len(clean))
+	for i, tok := range terms {
+		m.Vocabulary[tok] = i
+		m.IDF[i] = math.Log((1+n)/(1+float64(docFreq[tok]))) + 1
+	}
+
+	xs := make([]map[int]float64, len(clean))
+	ys := make([]int, len(clean))
+	for i, ex := range clean {
+		xs[i] = m.vectorize(ex.Text)
+		ys[i] = labelIndex[ex.Label]
+	}
+	k := len(m.Labels)
+	m.Weights = make([][]float64, k)
+	for c := range m.Weights {
+		m.Weights[c] = make([]float64, len(terms))
+	}
+	m.Bias = make([]float64, k)
+
+	// Full-batch gradient descent is deterministic and fast enough for the
+	// few thousand examples a moderation team labels by hand.
+	for epoch := 0; epoch < trainEpochs; epoch++ {
+		gradW := make([][]float64, k)
+		for c := range gradW {
+			gradW[c] = make([]float64, len(terms))
+		}
+		gradB := make([]float64, k)
+		for i, x := range xs {
+			probs := softmax(m.logits(x), 1)
+			for c := 0; c < k; c++ {
+				diff := probs[c]
+				if c == ys[i] {
+					diff--
+				}
+				gradB[c] += diff
+				for j, v := range x {
+					gradW[c][j] += diff * v
+				}
+			}
+		}
+		for c := 0; c < k; c++ {
+			m.Bias[c] -= trainLearningRate * gradB[c] / n
+			for j := range m.Weights[c] {
+				m.Weights[c][j] -= trainLearningRate * (gradW[c][j]/n + trainL2*m.Weights[c][j])
+			}
+		}
+	}
+	return m, nil
+}
+
+// Predict returns a probability for every label.
+func (m *TextModel) Predict(text string) map[string]float64 {
+	probs := softmax(m.logits(m.vectorize(text)), m.temperature())
+	out := make(map[string]float64, len(m.Labels))
+	for c, label := range m.Labels {
+		out[label] = probs[c]
+	}
+	return out
+}
+
+// Calibrated returns a copy of the model with the temperature that minimises
+// log loss on examples. Examples with labels the model does not know are
+// ignored; with none left the copy keeps the current temperature.
+func (m *TextModel) Calibrated(examples []Example) (*TextModel, int) {
+	cp := *m
+	index := make(map[string]int, len(m.Labels))
+	for i, label := range m.Labels {
+		index[label] = i
+	}
+	type row struct {
+		logits []float64
+		label  int
+	}
+	rows := make([]row, 0, len(examples))
+	for _, ex := range examples {
+		if c, ok := index[ex.Label]; ok {
+			rows = append(rows, row{logits: m.logits(m.vectorize(ex.Text)), label: c})
+		}
+	}
+	if len(rows) == 0 {
+		return &cp, 0
+	}
+	best, bestLoss := cp.temperature(), math.Inf(1)
+	for t := 0.25; t <= 4.0001; t += 0.05 {
+		loss := 0.0
+		for _, r := range rows {
+			loss -= math.Log(math.Max(softmax(r.logits, t)[r.label], 1e-12))
+		}
+		if loss < bestLoss {
+			best, bestLoss = t, loss
+		}
+	}
+	cp.Temperature = math.Round(best*100) / 100
+	return &cp, len(rows)
+}
+
+func (m *TextModel) temperature() float64 {
+	if m.Temperature <= 0 {
+		return 1
+	}
+	return m.Temperature
+}
+
+// vectorize returns the L2-normalised TF-IDF vector of text over the
+// model's vocabulary.
+func (m *TextModel) vectorize(text string) map[int]float64 {
+	x := map[int]float64{}
+	for _, tok := range Tokenize(text) {
+		if j, ok := m.Vocabulary[tok]; ok {
+			x[j] += m.IDF[j]
+		}
+	}
+	norm := 0.0
+	for _, v := range x {
+		norm += v * v
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for j := range x {
+			x[j] /= norm
+		}
+	}
+	return x
+}
+
+func (m *TextModel) logits(x map[int]float64) []float64 {
+	out := make([]float64, len(m.Labels))
+	for c := range out {
+		z := m.Bias[c]
+		for j, v := range x {
+			z += m.Weights[c][j] * v
+		}
+		out[c] = z
+	}
+	return out
+}
+
+func softmax(logits []float64, temperature float64) []float64 {
+	out := make([]float64, len(logits))
+	peak := math.Inf(-1)
+	for _, z := range logits {
+		peak = math.Max(peak, z/temperature)
+	}
+	sum := 0.0
+	for i, z := range logits {
+		out[i] = math.Exp(z/temperature - peak)
+		sum += out[i]
+	}
+	for i := range out {
+		out[i] /= sum
+	}
+	return out
+}
+
+// TopLabel returns the most probable label, breaking ties by name.
+func TopLabel(scores map[string]float64) (string, float64) {
+	best, bestScore := "", -1.0
+	for label, score := range scores {
+		if score > bestScore || (score == bestScore && label < best) {
+			best, bestScore = label, score
+		}
+	}
+	return best, bestScore
+}
+
+// SeedExamples trains the builtin vf-core model until a moderation team has
+// labelled its own data.
+var SeedExamples = []Example{
+	{Text: "check out my new dance video", Label: LabelSafe},
+	{Text: "normal content about my morning routine", Label: LabelSafe},
+	{Text: "safe text for a cooking tutorial", Label: LabelSafe},
+	{Text: "behind the scenes of our latest shoot", Label: LabelSafe},
+	{Text: "thanks everyone for the support this week", Label: LabelSafe},
+	{Text: "gaming stream highlights from last night", Label: LabelSafe},
+	{Text: "my original song recorded at home", Label: LabelSafe},
+	{Text: "travel vlog day three in lisbon", Label: LabelSafe},
+	{Text: "product review of the new headphones", Label: LabelSafe},
+	{Text: "workout tips for beginners", Label: LabelSafe},
+	{Text: "reacting to comments from my followers", Label: LabelSafe},
+	{Text: "unboxing the merch we designed", Label: LabelSafe},
+	{Text: "weekly update on the community challenge", Label: LabelSafe},
+	{Text: "funny moments with my dog", Label: LabelSafe},
+	{Text: "tutorial on editing short clips", Label: LabelSafe},
+	{Text: "safe and friendly content for everyone", Label: LabelSafe},
+	{Text: "received a dmca takedown notice for this upload", Label: LabelCopyrightRisk},
+	{Text: "full movie pirated copy free download", Label: LabelCopyrightRisk},
+	{Text: "copyright claim on the background music", Label: LabelCopyrightRisk},
+	{Text: "reupload of the full episode without permission", Label: LabelCopyrightRisk},
+	{Text: "leaked album stream all tracks", Label: LabelCopyrightRisk},
+	{Text: "copyright strike risk using their footage", Label: LabelCopyrightRisk},
+	{Text: "pirated software crack and keygen", Label: LabelCopyrightRisk},
+	{Text: "ripped the whole concert from the paid stream", Label: LabelCopyrightRisk},
+	{Text: "send me your password to claim the prize", Label: LabelFraudRisk},
+	{Text: "guaranteed crypto returns double your money", Label: LabelFraudRisk},
+	{Text: "this giveaway is a scam asking for card details", Label: LabelFraudRisk},
+	{Text: "fraud warning fake payment screenshot", Label: LabelFraudRisk},
+	{Text: "wire a fee to unlock your winnings", Label: LabelFraudRisk},
+	{Text: "buy followers cheap with stolen card", Label: LabelFraudRisk},
+	{Text: "scam link pretending to be support", Label: LabelFraudRisk},
+	{Text: "investment scheme that pays you to recruit", Label: LabelFraudRisk},
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+)
+
+// Classifier scores text with one model version and returns a probability
+// per label. Implementations are registered per domain.Model Backend.
+type Classifier interface {
+	Classify(ctx context.Context, model domain.Model, text string) (map[string]float64, error)
+}
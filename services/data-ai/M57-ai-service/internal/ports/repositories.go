@@ -11,6 +11,7 @@ type PredictionRepository interface {
 	Create(ctx context.Context, row domain.Prediction) error
 	GetByID(ctx context.Context, predictionID string) (domain.Prediction, error)
 	FindByKey(ctx context.Context, contentHash, modelID, modelVersion string) (domain.Prediction, bool, error)
+	Update(ctx context.Context, row domain.Prediction) error
 }
 
 type BatchJobRepository interface {
@@ -20,10 +21,31 @@ type BatchJobRepository interface {
 
 type ModelRepository interface {
 	GetActive(ctx context.Context, modelID, version string) (domain.Model, error)
+	Get(ctx context.Context, modelID, version string) (domain.Model, error)
+	// ActiveFor returns the version of modelID serving traffic.
+	ActiveFor(ctx context.Context, modelID string) (domain.Model, error)
+	Create(ctx context.Context, row domain.Model) error
+	List(ctx context.Context, modelID string) ([]domain.Model, error)
+	// Promote activates version and retires the version it replaces.
+	Promote(ctx context.Context, modelID, version string, at time.Time) (domain.Model, error)
+}
+
+type ShadowRepository interface {
+	Append(ctx context.Context, row domain.ShadowPrediction) error
+	ListByVersion(ctx context.Context, modelID, version string) ([]domain.ShadowPrediction, error)
+}
+
+type ReviewRepository interface {
+	Create(ctx context.Context, row domain.ReviewItem) error
+	GetByID(ctx context.Context, reviewID string) (domain.ReviewItem, error)
+	// List returns items oldest first; an empty status matches all.
+	List(ctx context.Context, status string) ([]domain.ReviewItem, error)
+	Update(ctx context.Context, row domain.ReviewItem) error
 }
 
 type FeedbackRepository interface {
 	Append(ctx context.Context, row domain.FeedbackLog) error
+	List(ctx context.Context) ([]domain.FeedbackLog, error)
 }
 
 type AuditRepository interface {
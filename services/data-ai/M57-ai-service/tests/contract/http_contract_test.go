@@ -19,6 +19,8 @@ func newRouter() http.Handler {
 		Predictions: repos.Predictions,
 		BatchJobs:   repos.BatchJobs,
 		Models:      repos.Models,
+		Shadows:     repos.Shadows,
+		Reviews:     repos.Reviews,
 		Feedback:    repos.Feedback,
 		Audit:       repos.Audit,
 		Idempotency: repos.Idempotency,
@@ -87,3 +89,42 @@ func TestAnalyzeAndBatchStatusEndpoints(t *testing.T) {
 		t.Fatalf("status failed: status=%d body=%s", statusRR.Code, statusRR.Body.String())
 	}
 }
+
+func TestModerationEndpointsCheckRoles(t *testing.T) {
+	router := newRouter()
+	serve := func(method, path, role, idem string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer u3")
+		if role != "" {
+			req.Header.Set("X-Actor-Role", role)
+		}
+		if idem != "" {
+			req.Header.Set("Idempotency-Key", idem)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := serve(http.MethodGet, "/api/v1/ai/reviews", "", ""); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected users to be forbidden from the review queue, got %d", rr.Code)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/ai/reviews?status=all", "moderator", ""); rr.Code != http.StatusOK {
+		t.Fatalf("expected moderators to read the review queue, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := serve(http.MethodDelete, "/api/v1/ai/models", "admin", ""); rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected method not allowed, got %d", rr.Code)
+	}
+
+	rr := serve(http.MethodPost, "/api/v1/ai/models/vf-core/versions/2026.02/promote", "admin", "idem-promote")
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected promoting the active version to be blocked, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var out contracts.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil || out.Code != "promotion_blocked" {
+		t.Fatalf("unexpected error envelope: %+v err=%v", out, err)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/ai/models/vf-core/versions/2026.02/shadow-evaluation", "admin", ""); rr.Code != http.StatusOK {
+		t.Fatalf("expected shadow evaluation, got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -2,11 +2,18 @@ package unit
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/adapters/classifier"
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/application"
 	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/domain"
+	"github.com/viralforge/mesh/services/data-ai/M57-ai-service/internal/ports"
 )
 
 func TestAnalyzeAndIdempotentReplay(t *testing.T) {
@@ -70,3 +77,408 @@ func TestBatchAnalyzeAndStatus(t *testing.T) {
 		t.Fatalf("unexpected batch status response: %+v", got)
 	}
 }
+
+func newModerationService(classifiers map[string]ports.Classifier) *application.Service {
+	repos := postgres.NewRepositories()
+	return application.NewService(application.Dependencies{
+		Config:      application.Config{MinShadowSamples: 6},
+		Predictions: repos.Predictions,
+		BatchJobs:   repos.BatchJobs,
+		Models:      repos.Models,
+		Shadows:     repos.Shadows,
+		Reviews:     repos.Reviews,
+		Feedback:    repos.Feedback,
+		Audit:       repos.Audit,
+		Idempotency: repos.Idempotency,
+		Classifiers: classifiers,
+	})
+}
+
+func TestTextModelLearnsSeedLabels(t *testing.T) {
+	model, err := domain.TrainTextModel(domain.SeedExamples)
+	if err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	cases := map[string]string{
+		"pirated movie full download":             domain.LabelCopyrightRisk,
+		"copyright claim notice":                  domain.LabelCopyrightRisk,
+		"scam link asking for your password":      domain.LabelFraudRisk,
+		"cooking tutorial for my morning routine": domain.LabelSafe,
+	}
+	for text, want := range cases {
+		if got, p := domain.TopLabel(model.Predict(text)); got != want {
+			t.Fatalf("%q: expected %s, got %s (%.2f)", text, want, got, p)
+		}
+	}
+	if _, err := domain.TrainTextModel(domain.SeedExamples[:3]); !errors.Is(err, domain.ErrInsufficientTrainingData) {
+		t.Fatalf("expected insufficient training data, got %v", err)
+	}
+
+	metrics := domain.ScoreClassifier([]string{"safe", "safe", "fraud_risk", "fraud_risk"}, []string{"safe", "fraud_risk", "fraud_risk", "fraud_risk"})
+	if metrics.Accuracy != 0.75 || metrics.Labels[0].Label != "fraud_risk" || metrics.Labels[0].Precision != 0.6667 || metrics.Labels[1].Recall != 0.5 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestLowConfidencePredictionsAreReviewed(t *testing.T) {
+	svc := newModerationService(nil)
+	ctx := context.Background()
+	user := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "idem-unsure"}
+
+	row, err := svc.Analyze(ctx, user, application.AnalyzeInput{Content: "free download of the giveaway prize"})
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if row.ReviewStatus != domain.ReviewStatusPending || row.ReviewID == "" || len(row.Scores) != 3 {
+		t.Fatalf("expected an ambiguous prediction to be queued with scores, got %+v", row)
+	}
+	if _, err := svc.ListReviews(ctx, user, application.ListReviewsInput{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected users to be kept out of the review queue, got %v", err)
+	}
+	moderator := application.Actor{SubjectID: "mod-1", Role: "moderator", IdempotencyKey: "idem-decide"}
+	queue, err := svc.ListReviews(ctx, moderator, application.ListReviewsInput{})
+	if err != nil || len(queue) != 1 || queue[0].PredictionID != row.PredictionID {
+		t.Fatalf("expected the prediction in the queue, got %+v err=%v", queue, err)
+	}
+	if _, err := svc.DecideReview(ctx, moderator, application.ReviewDecisionInput{ReviewID: row.ReviewID, Label: "spam"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected a label outside the model to be rejected, got %v", err)
+	}
+	moderator.IdempotencyKey = "idem-decide-2"
+	item, err := svc.DecideReview(ctx, moderator, application.ReviewDecisionInput{ReviewID: row.ReviewID, Label: domain.LabelFraudRisk, Note: "fake prize"})
+	if err != nil {
+		t.Fatalf("decide: %v", err)
+	}
+	if item.Status != domain.ReviewStatusResolved || item.Decision != domain.LabelFraudRisk || item.ReviewerID != "mod-1" {
+		t.Fatalf("unexpected review item: %+v", item)
+	}
+	moderator.IdempotencyKey = "idem-decide-3"
+	if _, err := svc.DecideReview(ctx, moderator, application.ReviewDecisionInput{ReviewID: row.ReviewID, Label: domain.LabelSafe}); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a resolved review to reject a second decision, got %v", err)
+	}
+	pending, _ := svc.ListReviews(ctx, moderator, application.ListReviewsInput{})
+	if len(pending) != 0 {
+		t.Fatalf("expected an empty queue, got %+v", pending)
+	}
+
+	// A confident prediction skips review until its owner disputes it.
+	user.IdempotencyKey = "idem-sure"
+	sure, err := svc.Analyze(ctx, user, application.AnalyzeInput{Content: "pirated movie full download dmca"})
+	if err != nil || sure.ReviewStatus != "" || sure.Label != domain.LabelCopyrightRisk || !sure.Flagged {
+		t.Fatalf("expected a confident copyright flag, got %+v err=%v", sure, err)
+	}
+	user.IdempotencyKey = "idem-dispute"
+	fb, err := svc.SubmitFeedback(ctx, user, application.FeedbackInput{PredictionID: sure.PredictionID, Label: domain.LabelSafe, Comment: "I own this film"})
+	if err != nil || fb.Feedback != domain.FeedbackUserCorrection {
+		t.Fatalf("expected a correction, got %+v err=%v", fb, err)
+	}
+	if _, err := svc.SubmitFeedback(ctx, application.Actor{SubjectID: "user-2", IdempotencyKey: "idem-other"}, application.FeedbackInput{PredictionID: sure.PredictionID, Label: domain.LabelSafe}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected other users to be forbidden, got %v", err)
+	}
+	pending, _ = svc.ListReviews(ctx, moderator, application.ListReviewsInput{})
+	if len(pending) != 1 || pending[0].PredictionID != sure.PredictionID {
+		t.Fatalf("expected the disputed prediction to be queued, got %+v", pending)
+	}
+}
+
+func TestShadowEvaluationGatesPromotion(t *testing.T) {
+	svc := newModerationService(nil)
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-train"}
+
+	// Reviewers keep overturning the active model on giveaway bait.
+	giveaways := []string{
+		"free giveaway claim your prize now",
+		"giveaway winner send a small fee",
+		"you won the giveaway click to claim",
+		"claim your free prize before it expires",
+		"giveaway prize for every follower today",
+		"last chance to claim the giveaway reward",
+	}
+	for i, text := range giveaways {
+		row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: fmt.Sprintf("idem-seed-%d", i)}, application.AnalyzeInput{Content: text})
+		if err != nil {
+			t.Fatalf("analyze: %v", err)
+		}
+		if row.ReviewID == "" {
+			// Confident but wrong: a report sends it to review all the same.
+			if _, err := svc.SubmitFeedback(ctx, application.Actor{SubjectID: "user-9", Role: "support", IdempotencyKey: fmt.Sprintf("idem-seed-report-%d", i)}, application.FeedbackInput{PredictionID: row.PredictionID, Label: domain.LabelFraudRisk}); err != nil && row.Label != domain.LabelFraudRisk {
+				t.Fatalf("report: %v", err)
+			}
+		}
+	}
+	moderator := application.Actor{SubjectID: "mod-1", Role: "moderator"}
+	queue, err := svc.ListReviews(ctx, moderator, application.ListReviewsInput{})
+	if err != nil || len(queue) == 0 {
+		t.Fatalf("expected giveaway bait in the review queue, got %d err=%v", len(queue), err)
+	}
+	for i, item := range queue {
+		moderator.IdempotencyKey = fmt.Sprintf("idem-seed-decide-%d", i)
+		if _, err := svc.DecideReview(ctx, moderator, application.ReviewDecisionInput{ReviewID: item.ReviewID, Label: domain.LabelFraudRisk}); err != nil {
+			t.Fatalf("decide: %v", err)
+		}
+	}
+
+	if _, err := svc.TrainModel(ctx, application.Actor{SubjectID: "mod-1", Role: "moderator", IdempotencyKey: "idem-x"}, application.TrainModelInput{Version: "2026.03"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected training to be admin only, got %v", err)
+	}
+	candidate, err := svc.TrainModel(ctx, admin, application.TrainModelInput{Version: "2026.03", IncludeFeedback: true})
+	if err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	if candidate.Status != domain.ModelStatusShadow || candidate.BaseVersion != "2026.02" || candidate.TrainedOn <= len(domain.SeedExamples) {
+		t.Fatalf("expected a shadow retrain on seed plus feedback, got %+v", candidate)
+	}
+	admin.IdempotencyKey = "idem-promote-early"
+	if _, err := svc.PromoteModel(ctx, admin, "vf-core", "2026.03"); !errors.Is(err, domain.ErrPromotionBlocked) {
+		t.Fatalf("expected promotion without shadow traffic to be blocked, got %v", err)
+	}
+
+	// Live traffic is labelled by the active model and shadowed by the
+	// candidate; moderators then label it.
+	traffic := []struct{ text, truth string }{
+		{"claim your giveaway reward today", domain.LabelFraudRisk},
+		{"giveaway winner announced claim the prize", domain.LabelFraudRisk},
+		{"prize giveaway for all followers", domain.LabelFraudRisk},
+		{"giveaway reward for every follower", domain.LabelFraudRisk},
+		{"my cooking tutorial for beginners", domain.LabelSafe},
+		{"dmca takedown on pirated stream", domain.LabelCopyrightRisk},
+		{"travel vlog with my dog", domain.LabelSafe},
+	}
+	for i, item := range traffic {
+		row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: fmt.Sprintf("idem-live-%d", i)}, application.AnalyzeInput{Content: item.text})
+		if err != nil {
+			t.Fatalf("analyze: %v", err)
+		}
+		if row.ModelVersion != "2026.02" {
+			t.Fatalf("expected the active version to serve traffic, got %s", row.ModelVersion)
+		}
+		reviewAs(t, svc, fmt.Sprintf("live-%d", i), row, item.truth)
+	}
+
+	eval, err := svc.EvaluateShadow(ctx, application.Actor{SubjectID: "ops-1", Role: "admin"}, "vf-core", "2026.03")
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if eval.ShadowSamples != len(traffic) || eval.LabelledSamples != len(traffic) || !eval.Promotable {
+		t.Fatalf("expected a promotable evaluation over all traffic, got %+v", eval)
+	}
+	if eval.Candidate.MacroRecall <= eval.Active.MacroRecall || len(eval.Candidate.Labels) == 0 {
+		t.Fatalf("expected the retrain to recall more, candidate=%+v active=%+v", eval.Candidate, eval.Active)
+	}
+
+	admin.IdempotencyKey = "idem-promote"
+	promoted, err := svc.PromoteModel(ctx, admin, "vf-core", "2026.03")
+	if err != nil || !promoted.Active || promoted.PromotedAt == nil {
+		t.Fatalf("expected promotion, got %+v err=%v", promoted, err)
+	}
+	models, _ := svc.ListModels(ctx, admin, "vf-core")
+	if len(models) != 2 || models[0].Status != domain.ModelStatusRetired {
+		t.Fatalf("expected the previous version to be retired, got %+v", models)
+	}
+	row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: "idem-after"}, application.AnalyzeInput{Content: "claim the giveaway prize"})
+	if err != nil || row.ModelVersion != "2026.03" || row.Label != domain.LabelFraudRisk {
+		t.Fatalf("expected the promoted version to flag giveaway bait, got %+v err=%v", row, err)
+	}
+}
+
+// reviewAs has a moderator label row, reporting it for review first when the
+// model was confident enough to skip the queue.
+func reviewAs(t *testing.T, svc *application.Service, key string, row domain.Prediction, label string) {
+	t.Helper()
+	ctx := context.Background()
+	if row.ReviewID == "" {
+		report := domain.LabelSafe
+		if row.Label == domain.LabelSafe {
+			report = domain.LabelFraudRisk
+		}
+		if _, err := svc.SubmitFeedback(ctx, application.Actor{SubjectID: "support-1", Role: "support", IdempotencyKey: "idem-report-" + key}, application.FeedbackInput{PredictionID: row.PredictionID, Label: report}); err != nil {
+			t.Fatalf("report: %v", err)
+		}
+	}
+	moderator := application.Actor{SubjectID: "mod-1", Role: "moderator", IdempotencyKey: "idem-review-" + key}
+	queue, err := svc.ListReviews(ctx, moderator, application.ListReviewsInput{})
+	if err != nil {
+		t.Fatalf("list reviews: %v", err)
+	}
+	reviewID := ""
+	for _, item := range queue {
+		if item.PredictionID == row.PredictionID {
+			reviewID = item.ReviewID
+		}
+	}
+	if _, err := svc.DecideReview(ctx, moderator, application.ReviewDecisionInput{ReviewID: reviewID, Label: label}); err != nil {
+		t.Fatalf("decide: %v", err)
+	}
+}
+
+func TestRetrainCountsEachReviewedPredictionOnce(t *testing.T) {
+	svc := newModerationService(nil)
+	ctx := context.Background()
+	reviewed := []struct{ text, truth string }{
+		{"claim your giveaway reward today", domain.LabelFraudRisk},
+		{"travel vlog with my dog", domain.LabelSafe},
+	}
+	for i, item := range reviewed {
+		row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: fmt.Sprintf("idem-fb-%d", i)}, application.AnalyzeInput{Content: item.text})
+		if err != nil {
+			t.Fatalf("analyze: %v", err)
+		}
+		reviewAs(t, svc, fmt.Sprintf("fb-%d", i), row, item.truth)
+	}
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-custom-v1"}
+	first, err := svc.TrainModel(ctx, admin, application.TrainModelInput{ModelID: "custom", Version: "v1", Examples: domain.SeedExamples, IncludeFeedback: true})
+	if err != nil || !first.Active || first.TrainedOn != len(domain.SeedExamples)+len(reviewed) {
+		t.Fatalf("expected a live first version on seed plus feedback, got %+v err=%v", first, err)
+	}
+	admin.IdempotencyKey = "idem-custom-v2"
+	second, err := svc.TrainModel(ctx, admin, application.TrainModelInput{ModelID: "custom", Version: "v2", IncludeFeedback: true})
+	if err != nil || second.BaseVersion != "v1" || second.TrainedOn != first.TrainedOn {
+		t.Fatalf("expected the retrain to reuse v1's examples without repeating feedback, got %+v err=%v", second, err)
+	}
+}
+
+func TestConfidentPredictionsAreSampledIntoShadowEvaluation(t *testing.T) {
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{
+		Config:      application.Config{MinShadowSamples: 1, ReviewSampleRate: 1},
+		Predictions: repos.Predictions,
+		Models:      repos.Models,
+		Shadows:     repos.Shadows,
+		Reviews:     repos.Reviews,
+		Feedback:    repos.Feedback,
+		Audit:       repos.Audit,
+		Idempotency: repos.Idempotency,
+	})
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-sample-train"}
+	if _, err := svc.TrainModel(ctx, admin, application.TrainModelInput{Version: "2026.03", Examples: domain.SeedExamples}); err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: "idem-sample"}, application.AnalyzeInput{Content: "pirated movie full download dmca"})
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if row.Label != domain.LabelCopyrightRisk || row.ReviewStatus != domain.ReviewStatusPending {
+		t.Fatalf("expected a confident prediction drawn for review, got %+v", row)
+	}
+	reviewAs(t, svc, "sample", row, domain.LabelCopyrightRisk)
+	eval, err := svc.EvaluateShadow(ctx, application.Actor{SubjectID: "ops-1", Role: "admin"}, "vf-core", "2026.03")
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if eval.LabelledSamples != 1 || eval.SampledSamples != 1 {
+		t.Fatalf("expected the sampled prediction in the evaluation, got %+v", eval)
+	}
+}
+
+func TestRecalibrateFitsTemperatureToFeedback(t *testing.T) {
+	svc := newModerationService(nil)
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-recal-empty"}
+	if _, err := svc.Recalibrate(ctx, admin, application.RecalibrateInput{Version: "2026.02-cal"}); !errors.Is(err, domain.ErrInsufficientTrainingData) {
+		t.Fatalf("expected recalibration without feedback to fail, got %v", err)
+	}
+	confirmed := []string{"pirated movie full download", "dmca takedown notice", "scam asking for card details", "my morning workout tips"}
+	for i, text := range confirmed {
+		row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: fmt.Sprintf("idem-cal-%d", i)}, application.AnalyzeInput{Content: text})
+		if err != nil {
+			t.Fatalf("analyze: %v", err)
+		}
+		reviewAs(t, svc, fmt.Sprintf("cal-%d", i), row, row.Label)
+	}
+	admin.IdempotencyKey = "idem-recal"
+	model, err := svc.Recalibrate(ctx, admin, application.RecalibrateInput{Version: "2026.02-cal", Thresholds: map[string]float64{domain.LabelFraudRisk: 0.8}})
+	if err != nil {
+		t.Fatalf("recalibrate: %v", err)
+	}
+	if model.Status != domain.ModelStatusShadow || model.CalibratedOn != len(confirmed) || model.Text.Temperature >= 1 {
+		t.Fatalf("expected a sharper shadow copy, got %+v temperature=%v", model, model.Text.Temperature)
+	}
+	if model.Threshold(domain.LabelFraudRisk) != 0.8 || model.Threshold(domain.LabelCopyrightRisk) != 0.7 {
+		t.Fatalf("expected thresholds merged over the base version's, got %v", model.Thresholds)
+	}
+}
+
+func TestOnlyReviewerDecisionsAreGroundTruth(t *testing.T) {
+	svc := newModerationService(nil)
+	ctx := context.Background()
+	user := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "idem-gt-analyze"}
+	text := "pirated movie full download dmca"
+	row, err := svc.Analyze(ctx, user, application.AnalyzeInput{Content: text})
+	if err != nil || row.Label != domain.LabelCopyrightRisk {
+		t.Fatalf("expected a copyright flag, got %+v err=%v", row, err)
+	}
+	user.IdempotencyKey = "idem-gt-correct"
+	if _, err := svc.SubmitFeedback(ctx, user, application.FeedbackInput{PredictionID: row.PredictionID, Label: domain.LabelSafe}); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+
+	// The owner's correction is their own word, not a label to learn from.
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-gt-recal"}
+	if _, err := svc.Recalibrate(ctx, admin, application.RecalibrateInput{Version: "2026.02-cal"}); !errors.Is(err, domain.ErrInsufficientTrainingData) {
+		t.Fatalf("expected a user correction to be ignored, got %v", err)
+	}
+	admin.IdempotencyKey = "idem-gt-train"
+	model, err := svc.TrainModel(ctx, admin, application.TrainModelInput{Version: "2026.03", IncludeFeedback: true})
+	if err != nil || model.TrainedOn != len(domain.SeedExamples) {
+		t.Fatalf("expected training to skip the user correction, got %+v err=%v", model, err)
+	}
+
+	moderator := application.Actor{SubjectID: "mod-1", Role: "moderator", IdempotencyKey: "idem-gt-decide"}
+	queue, err := svc.ListReviews(ctx, moderator, application.ListReviewsInput{})
+	if err != nil || len(queue) != 1 || queue[0].Content != text {
+		t.Fatalf("expected the disputed content in the review queue, got %+v err=%v", queue, err)
+	}
+	if _, err := svc.DecideReview(ctx, moderator, application.ReviewDecisionInput{ReviewID: queue[0].ReviewID, Label: domain.LabelCopyrightRisk}); err != nil {
+		t.Fatalf("decide: %v", err)
+	}
+	admin.IdempotencyKey = "idem-gt-recal-2"
+	model, err = svc.Recalibrate(ctx, admin, application.RecalibrateInput{Version: "2026.02-cal"})
+	if err != nil || model.CalibratedOn != 1 {
+		t.Fatalf("expected the reviewer decision to be used, got %+v err=%v", model, err)
+	}
+}
+
+func TestRemoteClassifierBackend(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var req struct {
+			ModelVersion string `json:"model_version"`
+			Text         string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/v1/classify" || r.Header.Get("Authorization") != "Bearer secret" || req.ModelVersion != "remote-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"scores": map[string]float64{"safe": 0.1, "hate_speech": 0.9}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "ops-1", Role: "admin", IdempotencyKey: "idem-remote"}
+	input := application.TrainModelInput{ModelID: "vf-remote", Version: "remote-1", Backend: domain.BackendRemote, Labels: []string{"safe", "hate_speech"}}
+	if _, err := newModerationService(nil).TrainModel(ctx, admin, input); !errors.Is(err, domain.ErrModelUnavailable) {
+		t.Fatalf("expected remote models to need a remote classifier, got %v", err)
+	}
+
+	svc := newModerationService(map[string]ports.Classifier{
+		domain.BackendRemote: classifier.NewRemote(classifier.RemoteConfig{BaseURL: server.URL, Token: "secret"}),
+	})
+	model, err := svc.TrainModel(ctx, admin, input)
+	if err != nil || !model.Active {
+		t.Fatalf("expected the first version of a model to go live, got %+v err=%v", model, err)
+	}
+	row, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: "idem-remote-1"}, application.AnalyzeInput{Content: "some text", ModelID: "vf-remote"})
+	if err != nil || row.Label != "hate_speech" || !row.Flagged || row.ReviewStatus != "" {
+		t.Fatalf("expected a confident remote flag, got %+v err=%v", row, err)
+	}
+	healthy = false
+	if _, err := svc.Analyze(ctx, application.Actor{SubjectID: "user-1", IdempotencyKey: "idem-remote-2"}, application.AnalyzeInput{Content: "other text", ModelID: "vf-remote"}); !errors.Is(err, domain.ErrModelUnavailable) {
+		t.Fatalf("expected model unavailable, got %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/data-ai/M58-content-recommendation-engine/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/data-ai/M58-content-recommendation-engine/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/data-ai/M58-content-recommendation-engine/internal/adapters/http"
@@ -23,6 +24,7 @@ import (
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
@@ -34,7 +36,11 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 
 	repos := postgres.NewRepositories()
@@ -66,9 +72,9 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 
 	handler := httpadapter.NewHandler(service)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewRecommendationInternalServer(service))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
@@ -79,12 +85,13 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	worker := eventadapter.NewWorker(logger, consumer, dlqPublisher, service, cfg.ConsumerPollInterval)
 
 	_ = ctx
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
 
 	go func() {
@@ -113,6 +120,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -126,3 +134,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/data-ai/M58-content-recommendation-engine/internal/contracts"
 	"github.com/viralforge/mesh/services/data-ai/M58-content-recommendation-engine/internal/domain"
 	"github.com/viralforge/mesh/services/data-ai/M58-content-recommendation-engine/internal/ports"
@@ -134,7 +135,7 @@ func (s *Service) enqueueDomainEvent(ctx context.Context, eventType, partitionPa
 		PartitionKeyPath: partitionPath,
 		PartitionKey:     partitionKey,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          nonEmpty(traceID, uuid.NewString()),
+		TraceID:          observability.TraceParent(ctx, nonEmpty(traceID, uuid.NewString())),
 		SchemaVersion:    "1.0",
 		Data:             data,
 	}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/data-ai/M95-referral-analytics-service/internal/application"
 	"github.com/viralforge/mesh/services/data-ai/M95-referral-analytics-service/internal/contracts"
 	"github.com/viralforge/mesh/services/data-ai/M95-referral-analytics-service/internal/domain"
@@ -57,15 +58,18 @@ func (w *Worker) Run(ctx context.Context) error {
 			if e == nil {
 				continue
 			}
-			if err := w.service.HandleCanonicalEvent(ctx, *e); err != nil {
+			ectx, span := observability.StartEventSpan(ctx, e.EventType, e.TraceID)
+			err = w.service.HandleCanonicalEvent(ectx, *e)
+			observability.EndEventSpan(span, err)
+			if err != nil {
 				if e.EventClass == domain.CanonicalEventClassAnalyticsOnly {
-					w.logger.WarnContext(ctx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+					w.logger.WarnContext(ectx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 					continue
 				}
-				w.logger.ErrorContext(ctx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+				w.logger.ErrorContext(ectx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 				if w.dlqPublisher != nil {
 					now := time.Now().UTC()
-					_ = w.dlqPublisher.PublishDLQ(ctx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "referral-analytics-service.dlq", TraceID: e.TraceID})
+					_ = w.dlqPublisher.PublishDLQ(ectx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "referral-analytics-service.dlq", TraceID: e.TraceID})
 				}
 			}
 		}
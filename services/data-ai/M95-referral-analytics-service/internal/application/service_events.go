@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/data-ai/M95-referral-analytics-service/internal/contracts"
 	"github.com/viralforge/mesh/services/data-ai/M95-referral-analytics-service/internal/domain"
 )
@@ -70,7 +71,7 @@ func (s *Service) publishDLQIdempotencyConflict(ctx context.Context, key, traceI
 	if strings.TrimSpace(trace) == "" {
 		trace = uuid.NewString()
 	}
-	return s.dlq.PublishDLQ(ctx, publishDLQIdempotencyConflictRecord(key, s.cfg.ServiceName, trace, now))
+	return s.dlq.PublishDLQ(ctx, publishDLQIdempotencyConflictRecord(key, s.cfg.ServiceName, observability.TraceParent(ctx, trace), now))
 }
 
 func validateEnvelope(event contracts.EventEnvelope) error {
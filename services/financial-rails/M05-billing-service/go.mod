@@ -7,6 +7,7 @@ toolchain go1.24.5
 require (
 	github.com/go-chi/chi/v5 v5.1.0
 	github.com/google/uuid v1.6.0
+	github.com/viralforge/mesh/platform v0.0.0
 	google.golang.org/grpc v1.66.2
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -18,3 +19,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
 )
+
+replace github.com/viralforge/mesh/platform => ../../../platform
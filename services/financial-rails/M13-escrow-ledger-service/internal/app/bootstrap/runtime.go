@@ -12,6 +12,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/financial-rails/M13-escrow-ledger-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/financial-rails/M13-escrow-ledger-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/financial-rails/M13-escrow-ledger-service/internal/adapters/http"
@@ -20,12 +21,14 @@ import (
 	"google.golang.org/grpc"
 )
 
-type Runtime struct { cfg Config; logger *slog.Logger; httpServer *http.Server; grpcServer *grpc.Server; grpcLis net.Listener; worker *eventadapter.Worker }
+type Runtime struct { cfg Config; logger *slog.Logger; telemetry *observability.Telemetry; httpServer *http.Server; grpcServer *grpc.Server; grpcLis net.Listener; worker *eventadapter.Worker }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil { return nil, err }
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil { return nil, err }
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	domainPub := eventadapter.NewMemoryDomainPublisher()
@@ -34,24 +37,28 @@ func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
 	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval, OutboxFlushBatchSize: cfg.OutboxFlushBatchSize}, Holds: repos.Holds, Ledger: repos.Ledger, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub, Analytics: analyticsPub, DLQ: dlqPub})
 	handler := httpadapter.NewHandler(svc)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5*time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5*time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewEscrowLedgerInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil { return nil, err }
 	worker := eventadapter.NewWorker(logger, nil, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
-	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM); defer stop(); errCh := make(chan error, 2)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM); defer stop(); defer r.shutdownTelemetry(); errCh := make(chan error, 2)
 	go func(){ if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) { errCh <- err } }()
 	go func(){ if err := r.grpcServer.Serve(r.grpcLis); err != nil { errCh <- err } }()
 	select { case <-ctx.Done(): case err := <-errCh: r.logger.ErrorContext(ctx, "runtime failure", "error", err) }
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second); defer cancel(); _ = r.httpServer.Shutdown(shutdownCtx); r.grpcServer.GracefulStop(); return nil
 }
 func (r *Runtime) RunWorker(ctx context.Context) error {
-	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM); defer stop(); errCh := make(chan error,1)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM); defer stop(); defer r.shutdownTelemetry(); errCh := make(chan error,1)
 	go func(){ if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) { errCh <- err } }()
 	select { case <-ctx.Done(): return nil; case err := <-errCh: return err }
 }
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second); defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil { r.logger.Warn("telemetry shutdown failed", "error", err) }
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/financial-rails/M13-escrow-ledger-service/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M13-escrow-ledger-service/internal/domain"
 	"github.com/viralforge/mesh/services/financial-rails/M13-escrow-ledger-service/internal/ports"
@@ -56,7 +57,7 @@ func (s *Service) enqueueEvent(ctx context.Context, eventType, traceID string, d
 	b, err := json.Marshal(data)
 	if err != nil { return domain.ErrInvalidInput }
 	if strings.TrimSpace(traceID) == "" { traceID = uuid.NewString() }
-	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: eventType, EventClass: domain.CanonicalEventClass(eventType), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(eventType), PartitionKey: escrowID, SourceService: s.cfg.ServiceName, TraceID: traceID, SchemaVersion: "v1", Data: b}
+	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: eventType, EventClass: domain.CanonicalEventClass(eventType), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(eventType), PartitionKey: escrowID, SourceService: s.cfg.ServiceName, TraceID: observability.TraceParent(ctx, traceID), SchemaVersion: "v1", Data: b}
 	return s.outbox.Enqueue(ctx, ports.OutboxRecord{RecordID: uuid.NewString(), EventClass: env.EventClass, Envelope: env, CreatedAt: now})
 }
 
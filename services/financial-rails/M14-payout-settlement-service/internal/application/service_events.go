@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/financial-rails/M14-payout-settlement-service/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M14-payout-settlement-service/internal/domain"
 	"github.com/viralforge/mesh/services/financial-rails/M14-payout-settlement-service/internal/ports"
@@ -101,7 +102,7 @@ func (s *Service) publishAnalyticsProcessing(ctx context.Context, payout domain.
 		PartitionKeyPath: "data.payout_id",
 		PartitionKey:     payout.PayoutID,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          uuid.NewString(),
+		TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 		SchemaVersion:    "v1",
 		Data:             data,
 	}
@@ -135,7 +136,7 @@ func (s *Service) enqueueDomainPayoutPaid(ctx context.Context, payout domain.Pay
 			PartitionKeyPath: "data.payout_id",
 			PartitionKey:     payout.PayoutID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
@@ -171,7 +172,7 @@ func (s *Service) enqueueDomainPayoutFailed(ctx context.Context, payout domain.P
 			PartitionKeyPath: "data.payout_id",
 			PartitionKey:     payout.PayoutID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -31,6 +32,9 @@ func (h *Handler) createTransaction(w http.ResponseWriter, r *http.Request) {
 		Currency:              strings.TrimSpace(req.Currency),
 		TrafficSource:         strings.TrimSpace(req.TrafficSource),
 		UserTier:              strings.TrimSpace(req.UserTier),
+		ReferralCookieID:      referralCookieID(r, req.ReferralCookieID),
+		ClientIP:              clientIP(r),
+		UserAgent:             strings.TrimSpace(r.UserAgent()),
 	})
 	if err != nil {
 		status, code := mapDomainError(err)
@@ -166,3 +170,27 @@ func parseIntOrDefault(raw string, fallback int) int {
 	}
 	return value
 }
+
+// referralCookieID prefers the body's referral_cookie_id and falls back to
+// the affiliate click cookie when the checkout is posted from the browser.
+func referralCookieID(r *http.Request, fromBody string) string {
+	if v := strings.TrimSpace(fromBody); v != "" {
+		return v
+	}
+	if c, err := r.Cookie("affiliate_click_id"); err == nil {
+		return strings.TrimSpace(c.Value)
+	}
+	return ""
+}
+
+func clientIP(r *http.Request) string {
+	if raw := strings.TrimSpace(r.Header.Get("X-Forwarded-For")); raw != "" {
+		first, _, _ := strings.Cut(raw, ",")
+		return strings.TrimSpace(first)
+	}
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err == nil && host != "" {
+		return host
+	}
+	return strings.TrimSpace(r.RemoteAddr)
+}
@@ -383,6 +383,39 @@ func (s *Service) HandleProviderWebhook(ctx context.Context, input HandleWebhook
 		if err := s.enqueueDomainTransactionRefunded(ctx, transaction, refund); err != nil {
 			return domain.Transaction{}, err
 		}
+	case domain.IsChargebackWebhook(input.EventType):
+		at := s.nowFn()
+		amount := input.Amount
+		if amount <= 0 || amount > transaction.Amount {
+			amount = transaction.Amount
+		}
+		chargebackID := strings.TrimSpace(input.ProviderEventID)
+		if chargebackID == "" {
+			chargebackID = uuid.NewString()
+		}
+		transaction.Status = domain.TransactionStatusChargedBack
+		transaction.ChargedBackAt = &at
+		transaction.UpdatedAt = at
+		if err := s.transactions.Update(ctx, transaction); err != nil {
+			return domain.Transaction{}, err
+		}
+		balance, err := s.balances.GetOrCreate(ctx, transaction.UserID)
+		if err != nil {
+			return domain.Transaction{}, err
+		}
+		balance.PendingBalance -= amount
+		if balance.PendingBalance < 0 {
+			balance.NegativeBalance += -balance.PendingBalance
+			balance.PendingBalance = 0
+		}
+		balance.LastTransactionID = transaction.TransactionID
+		balance.UpdatedAt = at
+		if err := s.balances.Upsert(ctx, balance); err != nil {
+			return domain.Transaction{}, err
+		}
+		if err := s.enqueueDomainTransactionChargedBack(ctx, transaction, chargebackID, amount, strings.TrimSpace(input.Reason)); err != nil {
+			return domain.Transaction{}, err
+		}
 	default:
 		return domain.Transaction{}, domain.ErrUnsupportedEventType
 	}
@@ -443,7 +476,11 @@ func (s *Service) createTransactionWithKey(ctx context.Context, input CreateTran
 		return domain.Transaction{}, fmt.Errorf("product status check: %w", err)
 	}
 
-	requestHash := hashPayload(input)
+	// The referral context describes the connection, not the purchase, so a
+	// retry from another connection still replays.
+	hashed := input
+	hashed.ReferralCookieID, hashed.ClientIP, hashed.UserAgent = "", "", ""
+	requestHash := hashPayload(hashed)
 	now := s.nowFn()
 	existing, err := s.idempotency.Get(ctx, idempotencyKey, now)
 	if err != nil {
@@ -479,6 +516,9 @@ func (s *Service) createTransactionWithKey(ctx context.Context, input CreateTran
 		PlatformFeeRate:       feeRate,
 		Status:                domain.TransactionStatusPending,
 		IdempotencyKey:        idempotencyKey,
+		ReferralCookieID:      strings.TrimSpace(input.ReferralCookieID),
+		ClientIPHash:          sessionHash(input.ClientIP),
+		UserAgentHash:         sessionHash(input.UserAgent),
 		CreatedAt:             now,
 		UpdatedAt:             now,
 	}
@@ -548,6 +588,17 @@ func (s *Service) lookupWebhookTransaction(ctx context.Context, input HandleWebh
 	return domain.Transaction{}, domain.ErrNotFound
 }
 
+// sessionHash is the SHA-256 hex digest of a trimmed client detail, the form
+// affiliate attribution matches fingerprints on. Empty values stay empty.
+func sessionHash(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
 func hashPayload(value interface{}) string {
 	blob, err := json.Marshal(value)
 	if err != nil {
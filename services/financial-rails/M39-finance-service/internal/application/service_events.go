@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/domain"
 	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/ports"
@@ -76,12 +77,17 @@ func (s *Service) enqueueDomainTransactionSucceeded(ctx context.Context, transac
 		occurredAt = *transaction.SucceededAt
 	}
 	payload := contracts.TransactionSucceededPayload{
-		TransactionID: transaction.TransactionID,
-		UserID:        transaction.UserID,
-		Amount:        transaction.Amount,
-		Currency:      transaction.Currency,
-		Provider:      string(transaction.Provider),
-		OccurredAt:    occurredAt.Format(time.RFC3339),
+		TransactionID:    transaction.TransactionID,
+		UserID:           transaction.UserID,
+		Amount:           transaction.Amount,
+		Currency:         transaction.Currency,
+		Provider:         string(transaction.Provider),
+		CampaignID:       transaction.CampaignID,
+		ProductID:        transaction.ProductID,
+		ReferralCookieID: transaction.ReferralCookieID,
+		IPHash:           transaction.ClientIPHash,
+		UserAgentHash:    transaction.UserAgentHash,
+		OccurredAt:       occurredAt.Format(time.RFC3339),
 	}
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -98,7 +104,7 @@ func (s *Service) enqueueDomainTransactionSucceeded(ctx context.Context, transac
 			PartitionKeyPath: "data.transaction_id",
 			PartitionKey:     transaction.TransactionID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
@@ -135,7 +141,7 @@ func (s *Service) enqueueDomainTransactionFailed(ctx context.Context, transactio
 			PartitionKeyPath: "data.transaction_id",
 			PartitionKey:     transaction.TransactionID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
@@ -173,7 +179,45 @@ func (s *Service) enqueueDomainTransactionRefunded(ctx context.Context, transact
 			PartitionKeyPath: "data.transaction_id",
 			PartitionKey:     transaction.TransactionID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
+			SchemaVersion:    "v1",
+			Data:             data,
+		},
+		CreatedAt: s.nowFn(),
+	})
+}
+
+func (s *Service) enqueueDomainTransactionChargedBack(ctx context.Context, transaction domain.Transaction, chargebackID string, amount float64, reason string) error {
+	occurredAt := s.nowFn()
+	if transaction.ChargedBackAt != nil {
+		occurredAt = *transaction.ChargedBackAt
+	}
+	payload := contracts.TransactionChargedBackPayload{
+		TransactionID: transaction.TransactionID,
+		ChargebackID:  chargebackID,
+		UserID:        transaction.UserID,
+		Amount:        amount,
+		Currency:      transaction.Currency,
+		Provider:      string(transaction.Provider),
+		OccurredAt:    occurredAt.Format(time.RFC3339),
+		Reason:        reason,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.outbox.Enqueue(ctx, ports.OutboxRecord{
+		RecordID:   uuid.NewString(),
+		EventClass: domain.CanonicalEventClassDomain,
+		Envelope: contracts.EventEnvelope{
+			EventID:          uuid.NewString(),
+			EventType:        domain.EventTransactionChargedBack,
+			EventClass:       domain.CanonicalEventClassDomain,
+			OccurredAt:       occurredAt,
+			PartitionKeyPath: "data.transaction_id",
+			PartitionKey:     transaction.TransactionID,
+			SourceService:    s.cfg.ServiceName,
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
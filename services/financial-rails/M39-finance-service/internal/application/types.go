@@ -34,6 +34,11 @@ type CreateTransactionInput struct {
 	Currency              string
 	TrafficSource         string
 	UserTier              string
+	// ReferralCookieID, ClientIP and UserAgent describe the buyer's session
+	// for affiliate attribution. Only hashes of the IP and user agent are kept.
+	ReferralCookieID string
+	ClientIP         string
+	UserAgent        string
 }
 
 type CreateRefundInput struct {
@@ -19,16 +19,32 @@ type EventEnvelope struct {
 }
 
 type TransactionSucceededPayload struct {
+	TransactionID    string  `json:"transaction_id"`
+	UserID           string  `json:"user_id"`
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	Provider         string  `json:"provider"`
+	CampaignID       string  `json:"campaign_id,omitempty"`
+	ProductID        string  `json:"product_id,omitempty"`
+	ReferralCookieID string  `json:"referral_cookie_id,omitempty"`
+	IPHash           string  `json:"ip_hash,omitempty"`
+	UserAgentHash    string  `json:"user_agent_hash,omitempty"`
+	OccurredAt       string  `json:"occurred_at"`
+}
+
+type TransactionFailedPayload struct {
 	TransactionID string  `json:"transaction_id"`
 	UserID        string  `json:"user_id"`
 	Amount        float64 `json:"amount"`
 	Currency      string  `json:"currency"`
 	Provider      string  `json:"provider"`
 	OccurredAt    string  `json:"occurred_at"`
+	Reason        string  `json:"reason"`
 }
 
-type TransactionFailedPayload struct {
+type TransactionRefundedPayload struct {
 	TransactionID string  `json:"transaction_id"`
+	RefundID      string  `json:"refund_id"`
 	UserID        string  `json:"user_id"`
 	Amount        float64 `json:"amount"`
 	Currency      string  `json:"currency"`
@@ -37,9 +53,9 @@ type TransactionFailedPayload struct {
 	Reason        string  `json:"reason"`
 }
 
-type TransactionRefundedPayload struct {
+type TransactionChargedBackPayload struct {
 	TransactionID string  `json:"transaction_id"`
-	RefundID      string  `json:"refund_id"`
+	ChargebackID  string  `json:"chargeback_id"`
 	UserID        string  `json:"user_id"`
 	Amount        float64 `json:"amount"`
 	Currency      string  `json:"currency"`
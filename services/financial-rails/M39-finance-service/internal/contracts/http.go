@@ -12,6 +12,7 @@ type CreateTransactionRequest struct {
 	Currency              string  `json:"currency"`
 	TrafficSource         string  `json:"traffic_source,omitempty"`
 	UserTier              string  `json:"user_tier,omitempty"`
+	ReferralCookieID      string  `json:"referral_cookie_id,omitempty"`
 }
 
 type CreateRefundRequest struct {
@@ -10,4 +10,7 @@ const (
 	EventTransactionSucceeded = "transaction.succeeded"
 	EventTransactionFailed    = "transaction.failed"
 	EventTransactionRefunded  = "transaction.refunded"
+	// EventTransactionChargedBack reports funds the provider withdrew after
+	// the buyer disputed the charge.
+	EventTransactionChargedBack = "transaction.charged_back"
 )
@@ -13,6 +13,9 @@ const (
 	TransactionStatusSucceeded TransactionStatus = "succeeded"
 	TransactionStatusFailed    TransactionStatus = "failed"
 	TransactionStatusRefunded  TransactionStatus = "refunded"
+	// TransactionStatusChargedBack marks a transaction whose funds the
+	// provider withdrew after a dispute.
+	TransactionStatusChargedBack TransactionStatus = "charged_back"
 )
 
 const (
@@ -34,11 +37,15 @@ type Transaction struct {
 	Status                TransactionStatus `json:"status"`
 	FailureReason         string            `json:"failure_reason,omitempty"`
 	IdempotencyKey        string            `json:"idempotency_key"`
+	ReferralCookieID      string            `json:"referral_cookie_id,omitempty"`
+	ClientIPHash          string            `json:"client_ip_hash,omitempty"`
+	UserAgentHash         string            `json:"user_agent_hash,omitempty"`
 	CreatedAt             time.Time         `json:"created_at"`
 	UpdatedAt             time.Time         `json:"updated_at"`
 	SucceededAt           *time.Time        `json:"succeeded_at,omitempty"`
 	FailedAt              *time.Time        `json:"failed_at,omitempty"`
 	RefundedAt            *time.Time        `json:"refunded_at,omitempty"`
+	ChargedBackAt         *time.Time        `json:"charged_back_at,omitempty"`
 }
 
 type Refund struct {
@@ -143,3 +150,14 @@ func IsRefundWebhook(eventType string) bool {
 		return false
 	}
 }
+
+// IsChargebackWebhook reports provider events for a dispute the merchant
+// lost, when the funds are actually withdrawn.
+func IsChargebackWebhook(eventType string) bool {
+	switch strings.ToLower(strings.TrimSpace(eventType)) {
+	case "charge.dispute.funds_withdrawn", "payment.sale.reversed", "chargeback.created":
+		return true
+	default:
+		return false
+	}
+}
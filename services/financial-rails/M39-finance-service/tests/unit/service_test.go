@@ -2,12 +2,16 @@ package unit
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"testing"
 
 	eventadapter "github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/adapters/grpc"
 	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/application"
+	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M39-finance-service/internal/domain"
 )
 
@@ -175,3 +179,125 @@ func TestAdminRefundTransactionIdempotency(t *testing.T) {
 		t.Fatalf("expected idempotent refund replay")
 	}
 }
+
+func TestTransactionSucceededCarriesReferralContext(t *testing.T) {
+	t.Parallel()
+
+	repos := postgres.NewRepositories()
+	published := &capturingPublisher{}
+	svc := application.NewService(application.Dependencies{
+		Transactions:   repos.Transactions,
+		Refunds:        repos.Refunds,
+		Balances:       repos.Balances,
+		Webhooks:       repos.Webhooks,
+		Idempotency:    repos.Idempotency,
+		EventDedup:     repos.EventDedup,
+		Outbox:         repos.Outbox,
+		Auth:           grpcadapter.NewAuthClient(""),
+		Campaign:       grpcadapter.NewCampaignClient(""),
+		ContentLibrary: grpcadapter.NewContentLibraryClient(""),
+		Escrow:         grpcadapter.NewEscrowClient(""),
+		FeeEngine:      grpcadapter.NewFeeEngineClient(""),
+		Product:        grpcadapter.NewProductClient(""),
+		DomainEvents:   published,
+		Analytics:      eventadapter.NewMemoryAnalyticsPublisher(),
+		DLQ:            eventadapter.NewLoggingDLQPublisher(),
+	})
+
+	actor := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "txn:ref:user-1"}
+	input := application.CreateTransactionInput{
+		UserID:           "user-1",
+		CampaignID:       "campaign-1",
+		ProductID:        "product-1",
+		Provider:         domain.ProviderStripe,
+		Amount:           40,
+		Currency:         "USD",
+		ReferralCookieID: "cookie-1",
+		ClientIP:         "203.0.113.7",
+		UserAgent:        "Mozilla/5.0",
+	}
+	txn, err := svc.CreateTransaction(context.Background(), actor, input)
+	if err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	input.ClientIP = "198.51.100.2"
+	if replay, err := svc.CreateTransaction(context.Background(), actor, input); err != nil || replay.TransactionID != txn.TransactionID {
+		t.Fatalf("expected replay from another connection, got %v", err)
+	}
+
+	if len(published.events) != 1 || published.events[0].EventType != "transaction.succeeded" {
+		t.Fatalf("expected one transaction.succeeded event, got %d", len(published.events))
+	}
+	var payload contracts.TransactionSucceededPayload
+	if err := json.Unmarshal(published.events[0].Data, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	ipHash := sha256.Sum256([]byte("203.0.113.7"))
+	if payload.ProductID != "product-1" || payload.ReferralCookieID != "cookie-1" || payload.IPHash != hex.EncodeToString(ipHash[:]) || payload.UserAgentHash == "" {
+		t.Fatalf("unexpected referral context: %+v", payload)
+	}
+}
+
+type capturingPublisher struct {
+	events []contracts.EventEnvelope
+}
+
+func (p *capturingPublisher) PublishDomain(_ context.Context, event contracts.EventEnvelope) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestChargebackWebhookPublishesChargedBack(t *testing.T) {
+	t.Parallel()
+
+	repos := postgres.NewRepositories()
+	published := &capturingPublisher{}
+	svc := application.NewService(application.Dependencies{
+		Transactions:   repos.Transactions,
+		Refunds:        repos.Refunds,
+		Balances:       repos.Balances,
+		Webhooks:       repos.Webhooks,
+		Idempotency:    repos.Idempotency,
+		EventDedup:     repos.EventDedup,
+		Outbox:         repos.Outbox,
+		Auth:           grpcadapter.NewAuthClient(""),
+		Campaign:       grpcadapter.NewCampaignClient(""),
+		ContentLibrary: grpcadapter.NewContentLibraryClient(""),
+		Escrow:         grpcadapter.NewEscrowClient(""),
+		FeeEngine:      grpcadapter.NewFeeEngineClient(""),
+		Product:        grpcadapter.NewProductClient(""),
+		DomainEvents:   published,
+		Analytics:      eventadapter.NewMemoryAnalyticsPublisher(),
+		DLQ:            eventadapter.NewLoggingDLQPublisher(),
+	})
+
+	txn, err := svc.CreateTransaction(context.Background(), application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "txn:cb:user-1"}, application.CreateTransactionInput{
+		UserID: "user-1", CampaignID: "campaign-1", ProductID: "product-1", Provider: domain.ProviderStripe, Amount: 60, Currency: "USD",
+	})
+	if err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	charged, err := svc.HandleProviderWebhook(context.Background(), application.HandleWebhookInput{
+		WebhookID:       "wh-cb-1",
+		Provider:        "stripe",
+		EventType:       "charge.dispute.funds_withdrawn",
+		ProviderEventID: "dp_1",
+		TransactionID:   txn.TransactionID,
+		Amount:          60,
+		Reason:          "fraudulent",
+	})
+	if err != nil {
+		t.Fatalf("chargeback webhook: %v", err)
+	}
+	if charged.Status != domain.TransactionStatusChargedBack {
+		t.Fatalf("expected charged_back status, got %s", charged.Status)
+	}
+	last := published.events[len(published.events)-1]
+	var payload contracts.TransactionChargedBackPayload
+	if err := json.Unmarshal(last.Data, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if last.EventType != domain.EventTransactionChargedBack || last.PartitionKey != txn.TransactionID || payload.ChargebackID != "dp_1" || payload.Amount != 60 {
+		t.Fatalf("unexpected chargeback event %s: %+v", last.EventType, payload)
+	}
+}
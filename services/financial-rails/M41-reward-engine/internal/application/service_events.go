@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/financial-rails/M41-reward-engine/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M41-reward-engine/internal/domain"
 	"github.com/viralforge/mesh/services/financial-rails/M41-reward-engine/internal/ports"
@@ -206,7 +207,7 @@ func (s *Service) enqueueDomainRewardCalculated(ctx context.Context, reward doma
 			PartitionKeyPath: "data.submission_id",
 			PartitionKey:     reward.SubmissionID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
@@ -250,7 +251,7 @@ func (s *Service) enqueueDomainRewardPayoutEligible(ctx context.Context, reward
 			PartitionKeyPath: "data.submission_id",
 			PartitionKey:     reward.SubmissionID,
 			SourceService:    s.cfg.ServiceName,
-			TraceID:          uuid.NewString(),
+			TraceID:          observability.TraceParent(ctx, uuid.NewString()),
 			SchemaVersion:    "v1",
 			Data:             data,
 		},
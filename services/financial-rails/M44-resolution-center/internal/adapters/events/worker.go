@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/financial-rails/M44-resolution-center/internal/application"
 	"github.com/viralforge/mesh/services/financial-rails/M44-resolution-center/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M44-resolution-center/internal/domain"
@@ -49,14 +50,17 @@ func (w *Worker) Run(ctx context.Context) error {
 			if event == nil {
 				continue
 			}
-			if err := w.service.HandleCanonicalEvent(ctx, *event); err != nil {
+			ectx, span := observability.StartEventSpan(ctx, event.EventType, event.TraceID)
+			err = w.service.HandleCanonicalEvent(ectx, *event)
+			observability.EndEventSpan(span, err)
+			if err != nil {
 				if event.EventClass == domain.CanonicalEventClassAnalyticsOnly || domain.CanonicalEventClass(event.EventType) == domain.CanonicalEventClassAnalyticsOnly {
-					w.logger.WarnContext(ctx, "analytics-only event dropped", "event_type", event.EventType, "event_id", event.EventID, "error", err)
+					w.logger.WarnContext(ectx, "analytics-only event dropped", "event_type", event.EventType, "event_id", event.EventID, "error", err)
 					continue
 				}
 				now := time.Now().UTC()
-				_ = w.dlqPublisher.PublishDLQ(ctx, contracts.DLQRecord{OriginalEvent: *event, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: event.EventType, DLQTopic: "resolution-center.dlq", TraceID: event.TraceID})
-				w.logger.ErrorContext(ctx, "canonical event failed", "event_type", event.EventType, "event_id", event.EventID, "error", err)
+				_ = w.dlqPublisher.PublishDLQ(ectx, contracts.DLQRecord{OriginalEvent: *event, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: event.EventType, DLQTopic: "resolution-center.dlq", TraceID: event.TraceID})
+				w.logger.ErrorContext(ectx, "canonical event failed", "event_type", event.EventType, "event_id", event.EventID, "error", err)
 			}
 		}
 	}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/financial-rails/M44-resolution-center/internal/contracts"
 	"github.com/viralforge/mesh/services/financial-rails/M44-resolution-center/internal/domain"
 	"github.com/viralforge/mesh/services/financial-rails/M44-resolution-center/internal/ports"
@@ -146,7 +147,7 @@ func (s *Service) publishDisputeResolvedAnalytics(ctx context.Context, actor Act
 		PartitionKeyPath: domain.CanonicalPartitionKeyPath(domain.EventDisputeResolved),
 		PartitionKey:     dispute.DisputeID,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          nonEmpty(actor.RequestID, uuid.NewString()),
+		TraceID:          observability.TraceParent(ctx, nonEmpty(actor.RequestID, uuid.NewString())),
 		SchemaVersion:    "v1",
 		Data:             data,
 	}
@@ -171,7 +172,7 @@ func (s *Service) enqueueDomainEvent(ctx context.Context, eventType, partitionPa
 	if err != nil {
 		return err
 	}
-	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: eventType, EventClass: domain.CanonicalEventClassDomain, OccurredAt: s.nowFn(), PartitionKeyPath: partitionPath, PartitionKey: partitionKey, SourceService: s.cfg.ServiceName, TraceID: nonEmpty(traceID, uuid.NewString()), SchemaVersion: "v1", Data: data}
+	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: eventType, EventClass: domain.CanonicalEventClassDomain, OccurredAt: s.nowFn(), PartitionKeyPath: partitionPath, PartitionKey: partitionKey, SourceService: s.cfg.ServiceName, TraceID: observability.TraceParent(ctx, nonEmpty(traceID, uuid.NewString())), SchemaVersion: "v1", Data: data}
 	if err := validatePartitionKeyInvariant(env, partitionPath); err != nil {
 		return err
 	}
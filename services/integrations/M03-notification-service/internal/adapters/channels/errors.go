@@ -0,0 +1,16 @@
+package channels
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+var errStubUnavailable = errors.New("stub channel unavailable")
+
+// rejected wraps domain.ErrRecipientRejected so the dispatcher bounces the
+// delivery instead of retrying it.
+func rejected(detail string) error {
+	return fmt.Errorf("%w: %s", domain.ErrRecipientRejected, detail)
+}
@@ -0,0 +1,103 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// GatewayConfig points at an HTTP push or SMS gateway.
+type GatewayConfig struct {
+	BaseURL string
+	APIKey  string
+	// Sender is the SMS sender ID or short code; unused for push.
+	Sender  string
+	Timeout time.Duration
+}
+
+// PushGateway posts to {base}/v1/push. The generic contract is
+// {"token","title","body","data"} answered by {"id"}; 404 and 410 mean the
+// device token is gone.
+type PushGateway struct{ gateway }
+
+// SMSGateway posts to {base}/v1/messages with {"to","from","body"}; 400 and
+// 422 mean the number is invalid or opted out.
+type SMSGateway struct{ gateway }
+
+func NewPushGateway(cfg GatewayConfig) *PushGateway { return &PushGateway{newGateway(cfg)} }
+func NewSMSGateway(cfg GatewayConfig) *SMSGateway   { return &SMSGateway{newGateway(cfg)} }
+
+func (g *PushGateway) Channel() string { return domain.ChannelPush }
+func (g *SMSGateway) Channel() string  { return domain.ChannelSMS }
+
+func (g *PushGateway) Send(ctx context.Context, msg domain.OutboundMessage) (string, error) {
+	return g.post(ctx, "/v1/push", map[string]any{
+		"token": msg.Recipient,
+		"title": msg.Subject,
+		"body":  msg.Body,
+		"data":  msg.Data,
+	}, http.StatusNotFound, http.StatusGone)
+}
+
+func (g *SMSGateway) Send(ctx context.Context, msg domain.OutboundMessage) (string, error) {
+	return g.post(ctx, "/v1/messages", map[string]any{
+		"to":   msg.Recipient,
+		"from": g.cfg.Sender,
+		"body": msg.Body,
+	}, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+type gateway struct {
+	cfg    GatewayConfig
+	client *http.Client
+}
+
+func newGateway(cfg GatewayConfig) gateway {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return gateway{cfg: cfg, client: observability.WrapClient(&http.Client{Timeout: cfg.Timeout})}
+}
+
+func (g gateway) post(ctx context.Context, path string, payload any, rejectedStatuses ...int) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.cfg.BaseURL+path, bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.cfg.APIKey)
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	for _, status := range rejectedStatuses {
+		if resp.StatusCode == status {
+			return "", rejected(fmt.Sprintf("%s: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body))))
+		}
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s: status %d", path, resp.StatusCode)
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &out)
+	return out.ID, nil
+}
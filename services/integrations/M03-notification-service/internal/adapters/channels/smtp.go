@@ -0,0 +1,136 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// SMTPConfig points at an SMTP relay. A local MailHog or Mailpit on
+// localhost:1025 works without credentials.
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+	// UnsubscribeURL, when set, is advertised in List-Unsubscribe with the
+	// delivery ID appended so the webhook can correlate it.
+	UnsubscribeURL string
+	Timeout        time.Duration
+	// TLS configures STARTTLS. ServerName defaults to the relay's host.
+	TLS *tls.Config
+}
+
+type SMTPSender struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMTPSender{cfg: cfg}
+}
+
+func (s *SMTPSender) Channel() string { return domain.ChannelEmail }
+
+// Send delivers msg and returns its Message-ID. Permanent 5xx replies to
+// RCPT TO are reported as rejected recipients.
+func (s *SMTPSender) Send(ctx context.Context, msg domain.OutboundMessage) (string, error) {
+	host, _, err := net.SplitHostPort(s.cfg.Addr)
+	if err != nil {
+		return "", fmt.Errorf("smtp addr: %w", err)
+	}
+	dialer := net.Dialer{Timeout: s.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return "", err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(s.cfg.Timeout))
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return "", err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(s.tlsConfig(host)); err != nil {
+			return "", err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)); err != nil {
+			return "", err
+		}
+	}
+	if err := client.Mail(s.cfg.From); err != nil {
+		return "", err
+	}
+	if err := client.Rcpt(msg.Recipient); err != nil {
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+			return "", rejected(err.Error())
+		}
+		return "", err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return "", err
+	}
+	messageID := "<" + msg.DeliveryID + "@" + host + ">"
+	if _, err := w.Write(s.compose(msg, messageID)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return messageID, client.Quit()
+}
+
+func (s *SMTPSender) tlsConfig(host string) *tls.Config {
+	cfg := &tls.Config{}
+	if s.cfg.TLS != nil {
+		cfg = s.cfg.TLS.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = host
+	}
+	return cfg
+}
+
+func (s *SMTPSender) compose(msg domain.OutboundMessage, messageID string) []byte {
+	var b bytes.Buffer
+	header := func(k, v string) { b.WriteString(k + ": " + v + "\r\n") }
+	header("From", s.cfg.From)
+	header("To", msg.Recipient)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Message-ID", messageID)
+	header("Date", time.Now().UTC().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	if msg.Locale != "" {
+		header("Content-Language", msg.Locale)
+	}
+	if s.cfg.UnsubscribeURL != "" {
+		header("List-Unsubscribe", "<"+strings.TrimRight(s.cfg.UnsubscribeURL, "/")+"/"+msg.DeliveryID+">")
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
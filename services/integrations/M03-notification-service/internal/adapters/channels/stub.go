@@ -0,0 +1,54 @@
+package channels
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// StubSender accepts every message and keeps it in memory. It backs local
+// runs without an SMTP server or gateways, and tests.
+type StubSender struct {
+	channel string
+	logger  *slog.Logger
+
+	mu   sync.Mutex
+	sent []domain.OutboundMessage
+	// Reject holds recipients answered with domain.ErrRecipientRejected and
+	// Fail recipients answered with a transient error.
+	Reject map[string]bool
+	Fail   map[string]bool
+}
+
+func NewStubSender(channel string, logger *slog.Logger) *StubSender {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StubSender{channel: channel, logger: logger, Reject: map[string]bool{}, Fail: map[string]bool{}}
+}
+
+func (s *StubSender) Channel() string { return s.channel }
+
+func (s *StubSender) Send(ctx context.Context, msg domain.OutboundMessage) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Reject[msg.Recipient] {
+		return "", rejected("stub rejected " + msg.Recipient)
+	}
+	if s.Fail[msg.Recipient] {
+		return "", errStubUnavailable
+	}
+	s.sent = append(s.sent, msg)
+	s.logger.InfoContext(ctx, "stub notification sent", "channel", s.channel, "delivery_id", msg.DeliveryID, "subject", msg.Subject)
+	return s.channel + "-stub-" + strconv.Itoa(len(s.sent)), nil
+}
+
+// Sent returns the messages accepted so far.
+func (s *StubSender) Sent() []domain.OutboundMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.OutboundMessage(nil), s.sent...)
+}
@@ -11,11 +11,19 @@ import (
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/ports"
 )
 
-type Handler struct{ service *application.Service }
+type Handler struct {
+	service *application.Service
+	streams ports.StreamSubscriber
+}
 
-func NewHandler(service *application.Service) *Handler { return &Handler{service: service} }
+// NewHandler builds the HTTP handlers; a nil streams disables the event
+// stream endpoint.
+func NewHandler(service *application.Service, streams ports.StreamSubscriber) *Handler {
+	return &Handler{service: service, streams: streams}
+}
 
 func (h *Handler) listNotifications(w http.ResponseWriter, r *http.Request) {
 	actor := actorFromContext(r.Context())
@@ -108,7 +116,7 @@ func (h *Handler) updatePreferences(w http.ResponseWriter, r *http.Request) {
 	prefs, err := h.service.UpdatePreferences(r.Context(), actor, application.UpdatePreferencesInput{
 		EmailEnabled: req.EmailEnabled, PushEnabled: req.PushEnabled, SMSEnabled: req.SMSEnabled, InAppEnabled: req.InAppEnabled,
 		QuietHoursEnabled: req.QuietHoursEnabled, QuietHoursStart: req.QuietHoursStart, QuietHoursEnd: req.QuietHoursEnd, QuietHoursTZ: req.QuietHoursTZ,
-		Language: req.Language, BatchingEnabled: req.BatchingEnabled, MutedTypes: req.MutedTypes,
+		Language: req.Language, BatchingEnabled: req.BatchingEnabled, DigestFrequency: req.DigestFrequency, MutedTypes: req.MutedTypes,
 	})
 	if err != nil {
 		code, c := mapDomainError(err)
@@ -129,6 +137,64 @@ func (h *Handler) deleteScheduled(w http.ResponseWriter, r *http.Request) {
 	writeSuccess(w, http.StatusOK, "scheduled notification cancel processed", contracts.DeleteScheduledResponse{ScheduledID: chi.URLParam(r, "id"), Cancelled: cancelled})
 }
 
+func (h *Handler) getContact(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	row, err := h.service.GetContact(r.Context(), actor)
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "notification contact", toContactResponse(row))
+}
+
+func (h *Handler) updateContact(w http.ResponseWriter, r *http.Request) {
+	var req contracts.UpdateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	actor := actorFromContext(r.Context())
+	row, err := h.service.UpdateContact(r.Context(), actor, application.UpdateContactInput{UserID: req.UserID, Email: req.Email, Phone: req.Phone, PushTokens: req.PushTokens})
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "notification contact updated", toContactResponse(row))
+}
+
+func (h *Handler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	rows, err := h.service.ListDeliveries(r.Context(), actor, chi.URLParam(r, "id"))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	resp := contracts.ListDeliveriesResponse{Items: make([]contracts.DeliveryItem, 0, len(rows))}
+	for _, d := range rows {
+		resp.Items = append(resp.Items, toDeliveryItem(d))
+	}
+	writeSuccess(w, http.StatusOK, "notification deliveries", resp)
+}
+
+func (h *Handler) deliveryFeedback(w http.ResponseWriter, r *http.Request) {
+	var req contracts.DeliveryFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	actor := actorFromContext(r.Context())
+	row, err := h.service.RecordDeliveryFeedback(r.Context(), actor, application.DeliveryFeedbackInput{DeliveryID: req.DeliveryID, ProviderMessageID: req.ProviderMessageID, Kind: req.Kind, Reason: req.Reason})
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "delivery feedback recorded", toDeliveryItem(row))
+}
+
 func toNotificationItem(n domain.Notification) contracts.NotificationItem {
 	item := contracts.NotificationItem{NotificationID: n.NotificationID, UserID: n.UserID, Type: n.Type, Title: n.Title, Body: n.Body, Metadata: n.Metadata, CreatedAt: n.CreatedAt.UTC().Format(time.RFC3339)}
 	if n.ReadAt != nil {
@@ -153,7 +219,28 @@ func toPreferencesResponse(p domain.Preferences) contracts.PreferencesResponse {
 		QuietHoursTZ:      p.QuietHoursTZ,
 		Language:          p.Language,
 		BatchingEnabled:   p.BatchingEnabled,
+		DigestFrequency:   p.DigestFrequency,
 		MutedTypes:        append([]string(nil), p.MutedTypes...),
 		UpdatedAt:         p.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 }
+
+func toContactResponse(c domain.Contact) contracts.ContactResponse {
+	resp := contracts.ContactResponse{UserID: c.UserID, Email: c.Email, Phone: c.Phone, PushTokens: append([]string(nil), c.PushTokens...), Suppressed: c.Suppressed}
+	if !c.UpdatedAt.IsZero() {
+		resp.UpdatedAt = c.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
+
+func toDeliveryItem(d domain.Delivery) contracts.DeliveryItem {
+	item := contracts.DeliveryItem{DeliveryID: d.DeliveryID, NotificationID: d.NotificationID, Channel: d.Channel, Recipient: d.Recipient, Locale: d.Locale, Status: d.Status, Attempts: d.Attempts, LastError: d.LastError, ProviderMessageID: d.ProviderMessageID, UpdatedAt: d.UpdatedAt.UTC().Format(time.RFC3339)}
+	switch d.Status {
+	case domain.DeliveryStatusPending, domain.DeliveryStatusDeferred, domain.DeliveryStatusRetrying:
+		item.NextAttemptAt = d.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+	if d.SentAt != nil {
+		item.SentAt = d.SentAt.UTC().Format(time.RFC3339)
+	}
+	return item
+}
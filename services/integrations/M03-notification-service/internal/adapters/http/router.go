@@ -12,6 +12,7 @@ func NewRouter(handler *Handler) http.Handler {
 	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) { writeSuccess(w, http.StatusOK, "ok", nil) })
 	r.Get("/readyz", func(w http.ResponseWriter, _ *http.Request) { writeSuccess(w, http.StatusOK, "ready", nil) })
 	r.Route("/v1", func(r chi.Router) {
+		r.With(streamTokenMiddleware, authMiddleware).Get("/notifications/stream", handler.stream)
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware)
 			r.Get("/notifications", handler.listNotifications)
@@ -22,6 +23,10 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Get("/notifications/preferences", handler.getPreferences)
 			r.Put("/notifications/preferences", handler.updatePreferences)
 			r.Delete("/notifications/scheduled/{id}", handler.deleteScheduled)
+			r.Get("/notifications/contacts", handler.getContact)
+			r.Put("/notifications/contacts", handler.updateContact)
+			r.Get("/notifications/{id}/deliveries", handler.listDeliveries)
+			r.Post("/notifications/deliveries/feedback", handler.deliveryFeedback)
 		})
 	})
 	return r
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/contracts"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// streamHeartbeat keeps idle connections open through proxies.
+const streamHeartbeat = 25 * time.Second
+
+// stream serves Server-Sent Events: "notification" events carry the
+// notification ID as the SSE id so browsers resume with Last-Event-ID, and
+// "unread_count" events follow every inbox change.
+func (h *Handler) stream(w http.ResponseWriter, r *http.Request) {
+	if h.streams == nil {
+		writeError(w, http.StatusServiceUnavailable, "stream_unavailable", "notification stream is not enabled", requestIDFromContext(r.Context()))
+		return
+	}
+	actor := actorFromContext(r.Context())
+	// Subscribe before replaying so nothing created in between is lost.
+	events, cancel := h.streams.Subscribe(actor.SubjectID)
+	defer cancel()
+	lastID := firstNonEmptyHeader(r.Header.Get("Last-Event-ID"), r.URL.Query().Get("last_event_id"))
+	missed, unread, err := h.service.NotificationsSince(r.Context(), actor, lastID)
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	replayed := make(map[string]bool, len(missed))
+	for _, n := range missed {
+		replayed[n.NotificationID] = true
+		if err := writeSSE(w, n.NotificationID, domain.StreamKindNotification, toNotificationItem(n)); err != nil {
+			return
+		}
+	}
+	if err := writeSSE(w, "", domain.StreamKindUnreadCount, contracts.UnreadCountResponse{UnreadCount: unread}); err != nil {
+		return
+	}
+	_ = rc.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			switch {
+			case event.Kind == domain.StreamKindNotification && event.Notification != nil:
+				if replayed[event.Notification.NotificationID] {
+					continue
+				}
+				if err := writeSSE(w, event.Notification.NotificationID, domain.StreamKindNotification, toNotificationItem(*event.Notification)); err != nil {
+					return
+				}
+				err = writeSSE(w, "", domain.StreamKindUnreadCount, contracts.UnreadCountResponse{UnreadCount: event.UnreadCount})
+				if err != nil {
+					return
+				}
+			case event.Kind == domain.StreamKindUnreadCount:
+				if err := writeSSE(w, "", domain.StreamKindUnreadCount, contracts.UnreadCountResponse{UnreadCount: event.UnreadCount}); err != nil {
+					return
+				}
+			default:
+				continue
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, id, event string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+	b.WriteString("event: " + event + "\n")
+	b.WriteString("data: " + string(raw) + "\n\n")
+	_, err = fmt.Fprint(w, b.String())
+	return err
+}
+
+// streamTokenMiddleware lets EventSource clients, which cannot set headers,
+// pass their bearer token as access_token.
+func streamTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := strings.TrimSpace(r.URL.Query().Get("access_token")); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func firstNonEmptyHeader(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
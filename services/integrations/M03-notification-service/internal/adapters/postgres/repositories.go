@@ -13,6 +13,9 @@ import (
 type Repositories struct {
 	Notifications *NotificationRepo
 	Preferences   *PreferencesRepo
+	Deliveries    *DeliveryRepo
+	Contacts      *ContactRepo
+	Digests       *DigestRepo
 	Scheduled     *ScheduledRepo
 	Idempotency   *IdempotencyRepo
 	EventDedup    *EventDedupRepo
@@ -22,6 +25,9 @@ func NewRepositories() *Repositories {
 	return &Repositories{
 		Notifications: &NotificationRepo{rows: map[string]domain.Notification{}},
 		Preferences:   &PreferencesRepo{rows: map[string]domain.Preferences{}},
+		Deliveries:    &DeliveryRepo{rows: map[string]domain.Delivery{}},
+		Contacts:      &ContactRepo{rows: map[string]domain.Contact{}},
+		Digests:       &DigestRepo{rows: map[string]domain.DigestEntry{}},
 		Scheduled:     &ScheduledRepo{rows: map[string]struct{}{}},
 		Idempotency:   &IdempotencyRepo{rows: map[string]ports.IdempotencyRecord{}},
 		EventDedup:    &EventDedupRepo{rows: map[string]time.Time{}},
@@ -142,6 +148,107 @@ func (r *PreferencesRepo) Upsert(_ context.Context, row domain.Preferences) erro
 	return nil
 }
 
+type DeliveryRepo struct {
+	mu   sync.Mutex
+	rows map[string]domain.Delivery
+}
+
+func (r *DeliveryRepo) Create(_ context.Context, row domain.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.rows[row.DeliveryID]; exists {
+		return domain.ErrConflict
+	}
+	r.rows[row.DeliveryID] = row
+	return nil
+}
+func (r *DeliveryRepo) GetByID(_ context.Context, deliveryID string) (domain.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[deliveryID]
+	if !ok {
+		return domain.Delivery{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+func (r *DeliveryRepo) GetByProviderMessageID(_ context.Context, providerMessageID string) (domain.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rows {
+		if providerMessageID != "" && row.ProviderMessageID == providerMessageID {
+			return row, nil
+		}
+	}
+	return domain.Delivery{}, domain.ErrNotFound
+}
+func (r *DeliveryRepo) Update(_ context.Context, row domain.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rows[row.DeliveryID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.rows[row.DeliveryID] = row
+	return nil
+}
+func (r *DeliveryRepo) ListDue(_ context.Context, now time.Time, limit int) ([]domain.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.Delivery, 0)
+	for _, row := range r.rows {
+		if row.IsDue(now) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].NextAttemptAt.Equal(out[j].NextAttemptAt) {
+			return out[i].DeliveryID < out[j].DeliveryID
+		}
+		return out[i].NextAttemptAt.Before(out[j].NextAttemptAt)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+func (r *DeliveryRepo) ListByNotificationID(_ context.Context, notificationID string) ([]domain.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.Delivery, 0)
+	for _, row := range r.rows {
+		if row.NotificationID == notificationID {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Channel == out[j].Channel {
+			return out[i].DeliveryID < out[j].DeliveryID
+		}
+		return out[i].Channel < out[j].Channel
+	})
+	return out, nil
+}
+
+type ContactRepo struct {
+	mu   sync.Mutex
+	rows map[string]domain.Contact
+}
+
+func (r *ContactRepo) GetByUserID(_ context.Context, userID string) (domain.Contact, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[userID]
+	if !ok {
+		return domain.Contact{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+func (r *ContactRepo) Upsert(_ context.Context, row domain.Contact) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[row.UserID] = row
+	return nil
+}
+
 type ScheduledRepo struct {
 	mu   sync.Mutex
 	rows map[string]struct{}
@@ -225,3 +332,43 @@ func (r *EventDedupRepo) MarkProcessed(_ context.Context, eventID, _ string, exp
 	r.rows[eventID] = expiresAt
 	return nil
 }
+
+type DigestRepo struct {
+	mu   sync.Mutex
+	rows map[string]domain.DigestEntry
+}
+
+func (r *DigestRepo) Add(_ context.Context, row domain.DigestEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.rows[row.EntryID]; exists {
+		return domain.ErrConflict
+	}
+	r.rows[row.EntryID] = row
+	return nil
+}
+func (r *DigestRepo) ListDue(_ context.Context, now time.Time) ([]domain.DigestEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.DigestEntry, 0)
+	for _, row := range r.rows {
+		if !row.DueAt.After(now) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].EntryID < out[j].EntryID
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+func (r *DigestRepo) Delete(_ context.Context, entryIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range entryIDs {
+		delete(r.rows, id)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// MemoryBus is the in-process StreamBus. It fans out between the hubs of one
+// process only; deployments with several API replicas or a separate worker
+// use KafkaBus.
+type MemoryBus struct {
+	mu   sync.Mutex
+	subs []chan domain.StreamEvent
+}
+
+func NewMemoryBus() *MemoryBus { return &MemoryBus{} }
+
+func (b *MemoryBus) Publish(_ context.Context, event domain.StreamEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context) (<-chan domain.StreamEvent, error) {
+	ch := make(chan domain.StreamEvent, 256)
+	b.mu.Lock()
+	b.subs = append(b.subs, ch)
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subs {
+			if sub == ch {
+				b.subs = append(b.subs[:i], b.subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/ports"
+)
+
+// subscriberBuffer is how many events a slow client may lag behind before
+// further events for it are dropped; it recovers by reconnecting with
+// Last-Event-ID.
+const subscriberBuffer = 32
+
+// Hub delivers stream events to the clients connected to this instance.
+type Hub struct {
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	nextID int
+	subs   map[string]map[int]chan domain.StreamEvent
+}
+
+func NewHub(logger *slog.Logger) *Hub {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Hub{logger: logger, subs: map[string]map[int]chan domain.StreamEvent{}}
+}
+
+func (h *Hub) Subscribe(userID string) (<-chan domain.StreamEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	id := h.nextID
+	ch := make(chan domain.StreamEvent, subscriberBuffer)
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[int]chan domain.StreamEvent{}
+	}
+	h.subs[userID][id] = ch
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[userID][id]; !ok {
+			return
+		}
+		delete(h.subs[userID], id)
+		if len(h.subs[userID]) == 0 {
+			delete(h.subs, userID)
+		}
+		close(ch)
+	}
+}
+
+// Close disconnects every local client so open streams end on shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for userID, subs := range h.subs {
+		for _, ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, userID)
+	}
+}
+
+// Broadcast hands event to every local client of its user without blocking.
+func (h *Hub) Broadcast(event domain.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, ch := range h.subs[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			h.logger.Warn("stream subscriber lagging, event dropped", "user_id", event.UserID, "kind", event.Kind)
+		}
+	}
+}
+
+// Run relays bus events to local clients until ctx is done, then closes
+// them.
+func (h *Hub) Run(ctx context.Context, bus ports.StreamBus) error {
+	events, err := bus.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			h.Broadcast(event)
+		}
+	}
+}
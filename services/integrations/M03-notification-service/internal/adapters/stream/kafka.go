@@ -0,0 +1,114 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// StreamTopic is the event-bus topic stream events travel on between
+// instances.
+const StreamTopic = "notification-service.stream"
+
+// KafkaBus is the StreamBus shared by every process of the service: the
+// process that changes an inbox publishes once and each API replica relays
+// the event to its own connections. Every instance reads the topic in a
+// consumer group of its own, from the newest offset, because stream events
+// only matter to clients connected right now.
+type KafkaBus struct {
+	writer  *kafka.Writer
+	brokers []string
+	groupID string
+	logger  *slog.Logger
+}
+
+// NewKafkaBus connects to brokers. instanceID must be unique per process so
+// that every instance sees every event.
+func NewKafkaBus(brokers []string, instanceID string, logger *slog.Logger) (*KafkaBus, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("kafka stream bus requires at least one broker")
+	}
+	if instanceID == "" {
+		return nil, fmt.Errorf("kafka stream bus requires an instance id")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &KafkaBus{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  StreamTopic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireOne,
+			BatchTimeout:           10 * time.Millisecond,
+			AllowAutoTopicCreation: true,
+		},
+		brokers: brokers,
+		groupID: StreamTopic + "." + instanceID,
+		logger:  logger,
+	}, nil
+}
+
+// Publish writes the event keyed by user so a user's events stay in order.
+func (b *KafkaBus) Publish(ctx context.Context, event domain.StreamEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.writer.WriteMessages(ctx, kafka.Message{Key: []byte(event.UserID), Value: raw, Time: time.Now().UTC()})
+}
+
+// Subscribe relays the topic until ctx is done. Undecodable messages are
+// logged and skipped.
+func (b *KafkaBus) Subscribe(ctx context.Context) (<-chan domain.StreamEvent, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     b.brokers,
+		GroupID:     b.groupID,
+		Topic:       StreamTopic,
+		StartOffset: kafka.LastOffset,
+		MinBytes:    1,
+		MaxBytes:    1e6,
+		MaxWait:     250 * time.Millisecond,
+	})
+	out := make(chan domain.StreamEvent, 256)
+	go func() {
+		defer close(out)
+		defer reader.Close()
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+					return
+				}
+				b.logger.Warn("stream relay read failed", "topic", StreamTopic, "error", err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+			var event domain.StreamEvent
+			if err := json.Unmarshal(msg.Value, &event); err != nil {
+				b.logger.Warn("undecodable stream event", "topic", StreamTopic, "error", err)
+				continue
+			}
+			select {
+			case out <- event:
+			default:
+				b.logger.Warn("stream relay lagging, event dropped", "user_id", event.UserID, "kind", event.Kind)
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (b *KafkaBus) Close() error {
+	return b.writer.Close()
+}
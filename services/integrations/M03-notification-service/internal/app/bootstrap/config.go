@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,6 +18,26 @@ type Config struct {
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
+	DispatchMaxAttempts  int
+	DispatchRetryBase    time.Duration
+	DispatchBatchSize    int
+	TemplatesPath        string
+	// StubChannels replaces every channel adapter with an in-memory sender
+	// that logs what it would have sent.
+	StubChannels   bool
+	SMTPAddr       string
+	SMTPFrom       string
+	SMTPUsername   string
+	SMTPPassword   string
+	UnsubscribeURL string
+	PushGatewayURL string
+	PushAPIKey     string
+	SMSGatewayURL  string
+	SMSAPIKey      string
+	SMSSender      string
+	// StreamKafkaBrokers selects the event-bus stream bus so inbox changes
+	// reach SSE clients on every replica; empty keeps the in-process bus.
+	StreamKafkaBrokers []string
 }
 
 type configFile struct {
@@ -24,10 +46,34 @@ type configFile struct {
 		HTTPPort int    `yaml:"http_port"`
 		GRPCPort int    `yaml:"grpc_port"`
 	} `yaml:"service"`
+	Dispatch struct {
+		MaxAttempts      int    `yaml:"max_attempts"`
+		RetryBaseSeconds int    `yaml:"retry_base_seconds"`
+		BatchSize        int    `yaml:"batch_size"`
+		TemplatesPath    string `yaml:"templates_path"`
+	} `yaml:"dispatch"`
+	Channels struct {
+		Stub  *bool `yaml:"stub"`
+		Email struct {
+			SMTPAddr       string `yaml:"smtp_addr"`
+			From           string `yaml:"from"`
+			UnsubscribeURL string `yaml:"unsubscribe_url"`
+		} `yaml:"email"`
+		Push struct {
+			GatewayURL string `yaml:"gateway_url"`
+		} `yaml:"push"`
+		SMS struct {
+			GatewayURL string `yaml:"gateway_url"`
+			Sender     string `yaml:"sender"`
+		} `yaml:"sms"`
+	} `yaml:"channels"`
+	Stream struct {
+		KafkaBrokers []string `yaml:"kafka_brokers"`
+	} `yaml:"stream"`
 }
 
 func LoadConfig(path string) (Config, error) {
-	cfg := Config{ServiceID: "M03-Notification-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second}
+	cfg := Config{ServiceID: "M03-Notification-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second, DispatchMaxAttempts: 5, DispatchRetryBase: 30 * time.Second, DispatchBatchSize: 100, StubChannels: true, SMTPFrom: "ViralForge <no-reply@viralforge.local>"}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
 		if err := yaml.Unmarshal(raw, &f); err != nil {
@@ -42,15 +88,77 @@ func LoadConfig(path string) (Config, error) {
 		if f.Service.GRPCPort > 0 {
 			cfg.GRPCPort = f.Service.GRPCPort
 		}
+		if f.Dispatch.MaxAttempts > 0 {
+			cfg.DispatchMaxAttempts = f.Dispatch.MaxAttempts
+		}
+		if f.Dispatch.RetryBaseSeconds > 0 {
+			cfg.DispatchRetryBase = time.Duration(f.Dispatch.RetryBaseSeconds) * time.Second
+		}
+		if f.Dispatch.BatchSize > 0 {
+			cfg.DispatchBatchSize = f.Dispatch.BatchSize
+		}
+		cfg.TemplatesPath = f.Dispatch.TemplatesPath
+		if f.Channels.Stub != nil {
+			cfg.StubChannels = *f.Channels.Stub
+		}
+		cfg.SMTPAddr = f.Channels.Email.SMTPAddr
+		if f.Channels.Email.From != "" {
+			cfg.SMTPFrom = f.Channels.Email.From
+		}
+		cfg.UnsubscribeURL = f.Channels.Email.UnsubscribeURL
+		cfg.PushGatewayURL = f.Channels.Push.GatewayURL
+		cfg.SMSGatewayURL = f.Channels.SMS.GatewayURL
+		cfg.SMSSender = f.Channels.SMS.Sender
+		cfg.StreamKafkaBrokers = f.Stream.KafkaBrokers
 	}
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
 	cfg.IdempotencyTTL = time.Duration(envInt("IDEMPOTENCY_TTL_HOURS", int(cfg.IdempotencyTTL.Hours()))) * time.Hour
 	cfg.EventDedupTTL = time.Duration(envInt("EVENT_DEDUP_TTL_HOURS", int(cfg.EventDedupTTL.Hours()))) * time.Hour
 	cfg.ConsumerPollInterval = time.Duration(envInt("CONSUMER_POLL_SECONDS", int(cfg.ConsumerPollInterval.Seconds()))) * time.Second
+	cfg.DispatchMaxAttempts = envInt("DISPATCH_MAX_ATTEMPTS", cfg.DispatchMaxAttempts)
+	cfg.StubChannels = envBool("NOTIFICATION_STUB_CHANNELS", cfg.StubChannels)
+	cfg.SMTPAddr = envString("SMTP_ADDR", cfg.SMTPAddr)
+	cfg.SMTPUsername = os.Getenv("SMTP_USERNAME")
+	cfg.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	cfg.PushGatewayURL = envString("PUSH_GATEWAY_URL", cfg.PushGatewayURL)
+	cfg.PushAPIKey = os.Getenv("PUSH_GATEWAY_API_KEY")
+	cfg.SMSGatewayURL = envString("SMS_GATEWAY_URL", cfg.SMSGatewayURL)
+	cfg.SMSAPIKey = os.Getenv("SMS_GATEWAY_API_KEY")
+	cfg.StreamKafkaBrokers = envCSV("KAFKA_BROKERS", cfg.StreamKafkaBrokers)
 	return cfg, nil
 }
 
+// LoadTemplates reads a YAML list of templates that override or extend the
+// built-in copy.
+func LoadTemplates(path string) ([]domain.Template, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+	var templates []domain.Template
+	if err := yaml.Unmarshal(raw, &templates); err != nil {
+		return nil, fmt.Errorf("parse templates: %w", err)
+	}
+	return templates, nil
+}
+
+func envString(name, fallback string) string {
+	if raw := os.Getenv(name); raw != "" {
+		return raw
+	}
+	return fallback
+}
+
+func envBool(name string, fallback bool) bool {
+	if raw := os.Getenv(name); raw != "" {
+		if v, err := strconv.ParseBool(raw); err == nil {
+			return v
+		}
+	}
+	return fallback
+}
+
 func envInt(name string, fallback int) int {
 	if raw := os.Getenv(name); raw != "" {
 		if v, err := strconv.Atoi(raw); err == nil {
@@ -59,3 +167,17 @@ func envInt(name string, fallback int) int {
 	}
 	return fallback
 }
+
+func envCSV(name string, fallback []string) []string {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback
+	}
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -9,61 +9,139 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/channels"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/postgres"
+	streamadapter "github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/stream"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/ports"
 	"google.golang.org/grpc"
 )
 
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
+	hub        *streamadapter.Hub
+	streamBus  ports.StreamBus
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	consumer := eventadapter.NewMemoryConsumer()
 	dlqPub := eventadapter.NewLoggingDLQPublisher()
+	var streamBus ports.StreamBus = streamadapter.NewMemoryBus()
+	if len(cfg.StreamKafkaBrokers) > 0 {
+		kafkaBus, err := streamadapter.NewKafkaBus(cfg.StreamKafkaBrokers, instanceID(), logger)
+		if err != nil {
+			return nil, err
+		}
+		streamBus = kafkaBus
+	}
+	hub := streamadapter.NewHub(logger)
+	templates := domain.NewTemplateCatalog(domain.DefaultTemplates()...)
+	if cfg.TemplatesPath != "" {
+		overrides, err := LoadTemplates(cfg.TemplatesPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range overrides {
+			templates.Put(t)
+		}
+	}
 	svc := application.NewService(application.Dependencies{
-		Config:        application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval},
+		Config: application.Config{
+			ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval,
+			DispatchMaxAttempts: cfg.DispatchMaxAttempts, DispatchRetryBase: cfg.DispatchRetryBase, DispatchBatchSize: cfg.DispatchBatchSize,
+		},
 		Notifications: repos.Notifications,
 		Preferences:   repos.Preferences,
 		Scheduled:     repos.Scheduled,
 		Idempotency:   repos.Idempotency,
 		EventDedup:    repos.EventDedup,
+		Deliveries:    repos.Deliveries,
+		Contacts:      repos.Contacts,
+		Senders:       newSenders(cfg, logger),
+		Templates:     templates,
+		Digests:       repos.Digests,
+		Stream:        streamBus,
 	})
-	handler := httpadapter.NewHandler(svc)
+	handler := httpadapter.NewHandler(svc, hub)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewNotificationInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		return nil, err
 	}
 	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker, hub: hub, streamBus: streamBus}, nil
 }
 
+// newSenders builds the channel adapters. Stub mode, or a channel without an
+// endpoint, falls back to a logging stub so local runs still record
+// deliveries end to end.
+func newSenders(cfg Config, logger *slog.Logger) []ports.ChannelSender {
+	email := ports.ChannelSender(channels.NewStubSender(domain.ChannelEmail, logger))
+	push := ports.ChannelSender(channels.NewStubSender(domain.ChannelPush, logger))
+	sms := ports.ChannelSender(channels.NewStubSender(domain.ChannelSMS, logger))
+	if !cfg.StubChannels {
+		if cfg.SMTPAddr != "" {
+			email = channels.NewSMTPSender(channels.SMTPConfig{Addr: cfg.SMTPAddr, From: cfg.SMTPFrom, Username: cfg.SMTPUsername, Password: cfg.SMTPPassword, UnsubscribeURL: cfg.UnsubscribeURL})
+		}
+		if cfg.PushGatewayURL != "" {
+			push = channels.NewPushGateway(channels.GatewayConfig{BaseURL: cfg.PushGatewayURL, APIKey: cfg.PushAPIKey})
+		}
+		if cfg.SMSGatewayURL != "" {
+			sms = channels.NewSMSGateway(channels.GatewayConfig{BaseURL: cfg.SMSGatewayURL, APIKey: cfg.SMSAPIKey, Sender: cfg.SMSSender})
+		}
+	}
+	return []ports.ChannelSender{email, push, sms}
+}
+
+// RunAPI serves HTTP and gRPC and runs the worker loop in the same process,
+// so notifications it creates reach this instance's stream clients even on
+// the in-process bus. Other replicas receive them through the event bus.
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
-	errCh := make(chan error, 2)
+	defer r.shutdownTelemetry()
+	defer r.closeStreamBus()
+	errCh := make(chan error, 4)
+	go func() {
+		if err := r.hub.Run(ctx, r.streamBus); err != nil {
+			errCh <- err
+		}
+	}()
+	go func() {
+		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- err
+		}
+	}()
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
@@ -86,9 +164,17 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 	return nil
 }
 
+// RunWorker runs only the worker loop. Its stream events reach API replicas
+// only through the event bus, so deployments that split the worker out must
+// set stream.kafka_brokers.
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
+	defer r.closeStreamBus()
+	if len(r.cfg.StreamKafkaBrokers) == 0 {
+		r.logger.WarnContext(ctx, "standalone worker on the in-process stream bus; stream clients will not see its notifications")
+	}
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -102,3 +188,25 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) closeStreamBus() {
+	if closer, ok := r.streamBus.(interface{ Close() error }); ok {
+		_ = closer.Close()
+	}
+}
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
+
+// instanceID names this process's stream consumer group: the host name
+// plus a random suffix, so restarts and co-located processes never share
+// one.
+func instanceID() string {
+	host, _ := os.Hostname()
+	return strings.Trim(host+"-"+uuid.NewString()[:8], "-")
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// FlushDigests turns every closed digest window into notifications and
+// returns how many were created. A window holding a single entry is sent as
+// the original notification; bursts of the same event type collapse into one
+// digest whose metadata carries the count and source notification events.
+// Each batch's notification ID is derived from its entries, so a flush that
+// fails after planning and before the entries are deleted re-plans the same
+// notification and deliveries on the next run instead of sending it twice.
+func (s *Service) FlushDigests(ctx context.Context) (int, error) {
+	if s.digests == nil {
+		return 0, nil
+	}
+	due, err := s.digests.ListDue(ctx, s.nowFn())
+	if err != nil {
+		return 0, err
+	}
+	type groupKey struct {
+		userID, eventType string
+		dueAt             time.Time
+	}
+	groups := map[groupKey][]domain.DigestEntry{}
+	keys := []groupKey{}
+	for _, entry := range due {
+		k := groupKey{entry.UserID, entry.EventType, entry.DueAt}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], entry)
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].dueAt.Before(keys[j].dueAt) })
+	created := 0
+	for _, k := range keys {
+		entries := groups[k]
+		if err := s.flushDigestGroup(ctx, entries); err != nil {
+			return created, err
+		}
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.EntryID)
+		}
+		if err := s.digests.Delete(ctx, ids); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+func (s *Service) flushDigestGroup(ctx context.Context, entries []domain.DigestEntry) error {
+	latest := entries[len(entries)-1]
+	prefs, err := s.preferences.GetByUserID(ctx, latest.UserID)
+	if err != nil {
+		prefs = domain.DefaultPreferences(latest.UserID, s.nowFn())
+	}
+	msg := domain.RenderedMessage{Locale: latest.Locale, Title: latest.Title, Body: latest.Body}
+	metadata := latest.Metadata
+	if len(entries) > 1 {
+		vars := map[string]string{
+			"count":  strconv.Itoa(len(entries)),
+			"label":  domain.HumanizeEventType(latest.EventType),
+			"latest": latest.Title,
+		}
+		if msg, err = s.templates.Render(domain.DigestEventType, prefs.Language, vars); err != nil {
+			return err
+		}
+		metadata = map[string]string{
+			"digest":       "true",
+			"digest_count": vars["count"],
+			"first_at":     entries[0].CreatedAt.UTC().Format(time.RFC3339),
+			"last_at":      latest.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	n := domain.Notification{
+		NotificationID:  digestNotificationID(entries),
+		UserID:          latest.UserID,
+		Type:            latest.EventType,
+		Title:           msg.Title,
+		Body:            msg.Body,
+		Metadata:        metadata,
+		SourceEventID:   latest.SourceEventID,
+		SourceEventType: latest.EventType,
+		CreatedAt:       s.nowFn(),
+	}
+	if prefs.InAppEnabled {
+		// An earlier flush of this batch already wrote its row; carry on to
+		// plan any deliveries that attempt did not get to.
+		switch err := s.notifications.Create(ctx, n); {
+		case err == nil:
+			s.publishNotification(ctx, n)
+		case !errors.Is(err, domain.ErrConflict):
+			return err
+		}
+	}
+	return s.planDeliveries(ctx, n, prefs, msg, nil)
+}
+
+// digestNotificationID derives the notification ID from the batch's entry
+// IDs so a re-flushed batch finds the row it already wrote.
+func digestNotificationID(entries []domain.DigestEntry) string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.EntryID)
+	}
+	sort.Strings(ids)
+	return "notif-" + uuid.NewSHA1(uuid.NameSpaceOID, []byte("digest:"+strings.Join(ids, ","))).String()
+}
@@ -0,0 +1,350 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// externalChannels are fanned out in this order after the in-app row.
+var externalChannels = []string{domain.ChannelEmail, domain.ChannelPush, domain.ChannelSMS}
+
+// planDeliveries creates one delivery per enabled channel and destination.
+// Deliveries planned inside the user's quiet hours are deferred to the end of
+// the window unless the event is urgent.
+func (s *Service) planDeliveries(ctx context.Context, n domain.Notification, prefs domain.Preferences, msg domain.RenderedMessage, vars map[string]string) error {
+	if s.deliveries == nil || s.contacts == nil {
+		return nil
+	}
+	contact, err := s.contacts.GetByUserID(ctx, n.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	now := s.nowFn()
+	status, nextAttempt := domain.DeliveryStatusPending, now
+	if until, quiet := prefs.QuietUntil(now); quiet && !domain.IsUrgentEvent(n.Type) {
+		status, nextAttempt = domain.DeliveryStatusDeferred, until
+	}
+	for _, channel := range externalChannels {
+		if !prefs.ChannelEnabled(channel) || s.senders[channel] == nil {
+			continue
+		}
+		for _, recipient := range contact.Recipients(channel) {
+			d := domain.Delivery{
+				DeliveryID:     newDeliveryID(n.NotificationID, channel, recipient),
+				NotificationID: n.NotificationID,
+				UserID:         n.UserID,
+				EventType:      n.Type,
+				Channel:        channel,
+				Recipient:      recipient,
+				Locale:         msg.Locale,
+				Subject:        msg.Title,
+				Body:           msg.Body,
+				Status:         status,
+				NextAttemptAt:  nextAttempt,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := s.deliveries.Create(ctx, d); err != nil && !errors.Is(err, domain.ErrConflict) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DispatchDue sends every delivery whose next attempt is due and returns how
+// many were attempted. Transient failures are retried with exponential
+// backoff up to DispatchMaxAttempts; rejected recipients are marked bounced
+// and their channel suppressed. A delivery whose state cannot be saved is
+// logged and skipped so the rest of the batch still goes out; the joined
+// errors are returned with the count.
+func (s *Service) DispatchDue(ctx context.Context) (int, error) {
+	if s.deliveries == nil {
+		return 0, nil
+	}
+	due, err := s.deliveries.ListDue(ctx, s.nowFn(), s.cfg.DispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, d := range due {
+		if err := s.dispatch(ctx, d); err != nil {
+			slog.Default().ErrorContext(ctx, "failed to record delivery attempt",
+				"service", s.cfg.ServiceName,
+				"operation", "dispatch_due",
+				"delivery_id", d.DeliveryID,
+				"channel", d.Channel,
+				"error", err,
+			)
+			errs = append(errs, err)
+		}
+	}
+	return len(due), errors.Join(errs...)
+}
+
+func (s *Service) dispatch(ctx context.Context, d domain.Delivery) error {
+	now := s.nowFn()
+	sender := s.senders[d.Channel]
+	if sender == nil {
+		d.Status, d.LastError, d.UpdatedAt = domain.DeliveryStatusFailed, "no sender for channel "+d.Channel, now
+		return s.deliveries.Update(ctx, d)
+	}
+	if contact, err := s.contacts.GetByUserID(ctx, d.UserID); err == nil {
+		if reason, suppressed := contact.Suppressed[d.Channel]; suppressed {
+			d.Status, d.LastError, d.UpdatedAt = domain.DeliveryStatusSuppressed, reason, now
+			return s.deliveries.Update(ctx, d)
+		}
+	}
+	d.Attempts++
+	providerID, err := sender.Send(ctx, domain.OutboundMessage{
+		DeliveryID: d.DeliveryID,
+		Channel:    d.Channel,
+		Recipient:  d.Recipient,
+		Subject:    d.Subject,
+		Body:       d.Body,
+		Locale:     d.Locale,
+		Data:       map[string]string{"notification_id": d.NotificationID, "event_type": d.EventType},
+	})
+	d.UpdatedAt = s.nowFn()
+	switch {
+	case err == nil:
+		sentAt := d.UpdatedAt
+		d.Status, d.ProviderMessageID, d.LastError, d.SentAt = domain.DeliveryStatusSent, providerID, "", &sentAt
+	case errors.Is(err, domain.ErrRecipientRejected):
+		d.Status, d.LastError = domain.DeliveryStatusBounced, err.Error()
+		if err := s.suppressChannel(ctx, d.UserID, d.Channel, d.Recipient, domain.FeedbackBounce); err != nil {
+			return err
+		}
+	case d.Attempts >= s.cfg.DispatchMaxAttempts:
+		d.Status, d.LastError = domain.DeliveryStatusFailed, err.Error()
+	default:
+		d.Status, d.LastError = domain.DeliveryStatusRetrying, err.Error()
+		d.NextAttemptAt = d.UpdatedAt.Add(s.retryDelay(d.Attempts))
+	}
+	return s.deliveries.Update(ctx, d)
+}
+
+func (s *Service) retryDelay(attempts int) time.Duration {
+	delay := s.cfg.DispatchRetryBase
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
+
+// ListDeliveries returns the external deliveries of a notification.
+func (s *Service) ListDeliveries(ctx context.Context, actor Actor, notificationID string) ([]domain.Delivery, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	notificationID = strings.TrimSpace(notificationID)
+	if notificationID == "" || s.deliveries == nil {
+		return nil, domain.ErrInvalidInput
+	}
+	rows, err := s.deliveries.ListByNotificationID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 && !canActForUser(actor, rows[0].UserID) {
+		return nil, domain.ErrForbidden
+	}
+	return rows, nil
+}
+
+// RecordDeliveryFeedback applies a provider webhook. Bounces and complaints
+// suppress the channel for the user; unsubscribes turn the channel off in
+// the user's preferences.
+func (s *Service) RecordDeliveryFeedback(ctx context.Context, actor Actor, input DeliveryFeedbackInput) (domain.Delivery, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.Delivery{}, domain.ErrUnauthorized
+	}
+	if role := strings.ToLower(strings.TrimSpace(actor.Role)); role != "admin" && role != "service" {
+		return domain.Delivery{}, domain.ErrForbidden
+	}
+	if s.deliveries == nil {
+		return domain.Delivery{}, domain.ErrNotFound
+	}
+	var (
+		d   domain.Delivery
+		err error
+	)
+	switch {
+	case strings.TrimSpace(input.DeliveryID) != "":
+		d, err = s.deliveries.GetByID(ctx, strings.TrimSpace(input.DeliveryID))
+	case strings.TrimSpace(input.ProviderMessageID) != "":
+		d, err = s.deliveries.GetByProviderMessageID(ctx, strings.TrimSpace(input.ProviderMessageID))
+	default:
+		return domain.Delivery{}, domain.ErrInvalidInput
+	}
+	if err != nil {
+		return domain.Delivery{}, err
+	}
+	now := s.nowFn()
+	kind := strings.ToLower(strings.TrimSpace(input.Kind))
+	switch kind {
+	case domain.FeedbackDelivered:
+		d.Status = domain.DeliveryStatusDelivered
+	case domain.FeedbackBounce, domain.FeedbackComplaint:
+		d.Status = domain.DeliveryStatusBounced
+		d.LastError = firstNonEmpty(input.Reason, kind)
+		if err := s.suppressChannel(ctx, d.UserID, d.Channel, d.Recipient, kind); err != nil {
+			return domain.Delivery{}, err
+		}
+	case domain.FeedbackUnsubscribe:
+		prefs, err := s.preferences.GetByUserID(ctx, d.UserID)
+		if err != nil {
+			prefs = domain.DefaultPreferences(d.UserID, now)
+		}
+		switch d.Channel {
+		case domain.ChannelEmail:
+			prefs.EmailEnabled = false
+		case domain.ChannelPush:
+			prefs.PushEnabled = false
+		case domain.ChannelSMS:
+			prefs.SMSEnabled = false
+		}
+		prefs.UpdatedAt = now
+		if err := s.preferences.Upsert(ctx, prefs); err != nil {
+			return domain.Delivery{}, err
+		}
+	default:
+		return domain.Delivery{}, domain.ErrInvalidInput
+	}
+	d.UpdatedAt = now
+	if err := s.deliveries.Update(ctx, d); err != nil {
+		return domain.Delivery{}, err
+	}
+	return d, nil
+}
+
+// suppressChannel stops future sends to recipient. Push tokens are dropped
+// individually because a user usually has several devices; email and SMS
+// suppress the whole channel until the user sets a new address.
+func (s *Service) suppressChannel(ctx context.Context, userID, channel, recipient, reason string) error {
+	contact, err := s.contacts.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if channel == domain.ChannelPush {
+		tokens := contact.PushTokens[:0:0]
+		for _, token := range contact.PushTokens {
+			if token != recipient {
+				tokens = append(tokens, token)
+			}
+		}
+		contact.PushTokens = tokens
+	} else {
+		suppressed := make(map[string]string, len(contact.Suppressed)+1)
+		for k, v := range contact.Suppressed {
+			suppressed[k] = v
+		}
+		suppressed[channel] = reason
+		contact.Suppressed = suppressed
+	}
+	contact.UpdatedAt = s.nowFn()
+	return s.contacts.Upsert(ctx, contact)
+}
+
+func (s *Service) GetContact(ctx context.Context, actor Actor) (domain.Contact, error) {
+	userID, err := s.resolveUser(actor, "")
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	if s.contacts == nil {
+		return domain.Contact{UserID: userID}, nil
+	}
+	row, err := s.contacts.GetByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Contact{UserID: userID}, nil
+	}
+	return row, err
+}
+
+// UpdateContact sets the destinations of a user. Changing an address lifts
+// its suppression.
+func (s *Service) UpdateContact(ctx context.Context, actor Actor, input UpdateContactInput) (domain.Contact, error) {
+	userID, err := s.resolveUser(actor, input.UserID)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	if s.contacts == nil {
+		return domain.Contact{}, domain.ErrInvalidInput
+	}
+	row, err := s.contacts.GetByUserID(ctx, userID)
+	if err != nil {
+		row = domain.Contact{UserID: userID}
+	}
+	suppressed := map[string]string{}
+	for k, v := range row.Suppressed {
+		suppressed[k] = v
+	}
+	if input.Email != nil {
+		email := strings.TrimSpace(*input.Email)
+		if email != "" && !strings.Contains(email, "@") {
+			return domain.Contact{}, domain.ErrInvalidInput
+		}
+		if email != row.Email {
+			delete(suppressed, domain.ChannelEmail)
+		}
+		row.Email = email
+	}
+	if input.Phone != nil {
+		phone := strings.TrimSpace(*input.Phone)
+		if phone != "" && !strings.HasPrefix(phone, "+") {
+			return domain.Contact{}, domain.ErrInvalidInput
+		}
+		if phone != row.Phone {
+			delete(suppressed, domain.ChannelSMS)
+		}
+		row.Phone = phone
+	}
+	if input.PushTokens != nil {
+		row.PushTokens = sanitizeStringSlice(input.PushTokens)
+	}
+	row.Suppressed = suppressed
+	row.UpdatedAt = s.nowFn()
+	if err := s.contacts.Upsert(ctx, row); err != nil {
+		return domain.Contact{}, err
+	}
+	return row, nil
+}
+
+// captureContact records the email of a newly registered user when M03 has
+// no address for them yet.
+func (s *Service) captureContact(ctx context.Context, userID string, vars map[string]string) {
+	email := vars["email"]
+	if s.contacts == nil || email == "" || !strings.Contains(email, "@") {
+		return
+	}
+	row, err := s.contacts.GetByUserID(ctx, userID)
+	if err == nil && row.Email != "" {
+		return
+	}
+	if err != nil {
+		row = domain.Contact{UserID: userID}
+	}
+	row.Email = email
+	row.UpdatedAt = s.nowFn()
+	_ = s.contacts.Upsert(ctx, row)
+}
+
+// newDeliveryID is derived from the notification, channel and recipient so
+// replanning a notification does not queue the same message twice.
+func newDeliveryID(notificationID, channel, recipient string) string {
+	return "dlv-" + uuid.NewSHA1(uuid.NameSpaceOID, []byte(notificationID+"|"+channel+"|"+recipient)).String()
+}
@@ -75,6 +75,7 @@ func (s *Service) MarkRead(ctx context.Context, actor Actor, notificationID stri
 	if err := s.notifications.Update(ctx, row); err != nil {
 		return domain.Notification{}, err
 	}
+	s.publishUnreadCount(ctx, row.UserID)
 	return row, nil
 }
 
@@ -97,6 +98,7 @@ func (s *Service) Archive(ctx context.Context, actor Actor, notificationID strin
 	if err := s.notifications.Update(ctx, row); err != nil {
 		return domain.Notification{}, err
 	}
+	s.publishUnreadCount(ctx, row.UserID)
 	return row, nil
 }
 
@@ -161,6 +163,9 @@ func (s *Service) BulkAction(ctx context.Context, actor Actor, input BulkActionI
 		}
 	}
 	failed := len(cleanIDs) - updated
+	if updated > 0 {
+		s.publishUnreadCount(ctx, userID)
+	}
 	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, map[string]int{"processed": updated, "failed": failed})
 	return updated, failed, nil
 }
@@ -216,6 +221,12 @@ func (s *Service) UpdatePreferences(ctx context.Context, actor Actor, input Upda
 	if input.BatchingEnabled != nil {
 		row.BatchingEnabled = *input.BatchingEnabled
 	}
+	if frequency := strings.ToLower(strings.TrimSpace(input.DigestFrequency)); frequency != "" {
+		if !domain.ValidDigestFrequency(frequency) {
+			return domain.Preferences{}, domain.ErrInvalidInput
+		}
+		row.DigestFrequency = frequency
+	}
 	if input.MutedTypes != nil {
 		row.MutedTypes = sanitizeStringSlice(input.MutedTypes)
 	}
@@ -322,3 +333,9 @@ func (s *Service) completeIdempotencyJSON(ctx context.Context, key string, code
 }
 
 func newNotificationID() string { return "notif-" + uuid.NewString() }
+
+// eventNotificationID derives the notification ID from the source event so
+// a redelivered event finds the row it already wrote.
+func eventNotificationID(eventID string) string {
+	return "notif-" + uuid.NewSHA1(uuid.NameSpaceOID, []byte(eventID)).String()
+}
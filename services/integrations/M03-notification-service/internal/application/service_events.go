@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -57,18 +58,73 @@ func (s *Service) ingestNotificationEvent(ctx context.Context, envelope contract
 	if userID == "" {
 		userID = "system"
 	}
+	prefs, err := s.preferences.GetByUserID(ctx, userID)
+	if err != nil {
+		prefs = domain.DefaultPreferences(userID, s.nowFn())
+	}
+	if prefs.IsMuted(envelope.EventType) {
+		return nil
+	}
+	vars := templateVars(payload)
+	msg, err := s.templates.Render(envelope.EventType, prefs.Language, vars)
+	if err != nil {
+		return err
+	}
+	if prefs.BatchingEnabled && s.digests != nil && domain.IsDigestibleEvent(envelope.EventType) {
+		now := s.nowFn()
+		return s.digests.Add(ctx, domain.DigestEntry{
+			EntryID:       "dig-" + envelope.EventID,
+			UserID:        userID,
+			EventType:     envelope.EventType,
+			Locale:        msg.Locale,
+			Title:         msg.Title,
+			Body:          msg.Body,
+			Metadata:      summarizePayload(payload),
+			SourceEventID: envelope.EventID,
+			CreatedAt:     now,
+			DueAt:         prefs.DigestDueAt(now),
+		})
+	}
 	n := domain.Notification{
-		NotificationID:  newNotificationID(),
+		NotificationID:  eventNotificationID(envelope.EventID),
 		UserID:          userID,
 		Type:            envelope.EventType,
-		Title:           humanizeEventType(envelope.EventType),
-		Body:            fmt.Sprintf("Notification generated from %s", envelope.EventType),
+		Title:           msg.Title,
+		Body:            msg.Body,
 		Metadata:        summarizePayload(payload),
 		SourceEventID:   envelope.EventID,
 		SourceEventType: envelope.EventType,
 		CreatedAt:       s.nowFn(),
 	}
-	return s.notifications.Create(ctx, n)
+	if prefs.InAppEnabled {
+		// A redelivered event already wrote its row; carry on to plan any
+		// deliveries the earlier attempt did not get to.
+		switch err := s.notifications.Create(ctx, n); {
+		case err == nil:
+			s.publishNotification(ctx, n)
+		case !errors.Is(err, domain.ErrConflict):
+			return err
+		}
+	}
+	if envelope.EventType == domain.EventUserRegistered {
+		s.captureContact(ctx, userID, vars)
+	}
+	return s.planDeliveries(ctx, n, prefs, msg, vars)
+}
+
+// templateVars flattens the top-level payload fields for templates.
+func templateVars(payload map[string]any) map[string]string {
+	out := make(map[string]string, len(payload))
+	for k, v := range payload {
+		switch v.(type) {
+		case map[string]any, []any:
+			continue
+		}
+		if s := asString(v); s != "" {
+			out[k] = s
+		}
+	}
+	return out
 }
 
 func validateEnvelope(event contracts.EventEnvelope) error {
@@ -126,9 +182,3 @@ func firstNonEmpty(values ...string) string {
 	}
 	return ""
 }
-
-func humanizeEventType(t string) string {
-	t = strings.ReplaceAll(t, ".", " ")
-	t = strings.ReplaceAll(t, "_", " ")
-	return strings.Title(t)
-}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// resumeLimit bounds how many missed notifications a reconnecting client
+// is replayed; older ones are reachable through the list endpoint.
+const resumeLimit = 50
+
+// NotificationsSince returns the notifications of the actor created after
+// lastNotificationID, oldest first, and the current unread count. An unknown
+// or empty ID replays nothing.
+func (s *Service) NotificationsSince(ctx context.Context, actor Actor, lastNotificationID string) ([]domain.Notification, int, error) {
+	userID, err := s.resolveUser(actor, "")
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.notifications.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	lastNotificationID = strings.TrimSpace(lastNotificationID)
+	if lastNotificationID == "" {
+		return nil, unread, nil
+	}
+	anchor, err := s.notifications.GetByID(ctx, lastNotificationID)
+	if err != nil || anchor.UserID != userID {
+		return nil, unread, nil
+	}
+	rows, _, err := s.notifications.ListByUserID(ctx, userID, domain.NotificationFilter{Page: 1, PageSize: resumeLimit})
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.Notification, 0, len(rows))
+	for _, row := range rows {
+		if row.NotificationID != anchor.NotificationID && row.CreatedAt.After(anchor.CreatedAt) {
+			out = append(out, row)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, unread, nil
+}
+
+// publishNotification and publishUnreadCount are best effort: clients that
+// miss an event catch up on reconnect or through the list endpoints.
+func (s *Service) publishNotification(ctx context.Context, n domain.Notification) {
+	if s.stream == nil {
+		return
+	}
+	unread, _ := s.notifications.CountUnread(ctx, n.UserID)
+	_ = s.stream.Publish(ctx, domain.StreamEvent{Kind: domain.StreamKindNotification, UserID: n.UserID, Notification: &n, UnreadCount: unread})
+}
+
+func (s *Service) publishUnreadCount(ctx context.Context, userID string) {
+	if s.stream == nil {
+		return
+	}
+	unread, err := s.notifications.CountUnread(ctx, userID)
+	if err != nil {
+		return
+	}
+	_ = s.stream.Publish(ctx, domain.StreamEvent{Kind: domain.StreamKindUnreadCount, UserID: userID, UnreadCount: unread})
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/ports"
 )
 
@@ -11,6 +12,11 @@ type Config struct {
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
+	// DispatchMaxAttempts bounds send attempts per delivery; retries back off
+	// exponentially from DispatchRetryBase.
+	DispatchMaxAttempts int
+	DispatchRetryBase   time.Duration
+	DispatchBatchSize   int
 }
 
 type Actor struct {
@@ -46,13 +52,34 @@ type UpdatePreferencesInput struct {
 	QuietHoursTZ      string
 	Language          string
 	BatchingEnabled   *bool
+	DigestFrequency   string
 	MutedTypes        []string
 }
 
+type UpdateContactInput struct {
+	UserID     string
+	Email      *string
+	Phone      *string
+	PushTokens []string
+}
+
+type DeliveryFeedbackInput struct {
+	DeliveryID        string
+	ProviderMessageID string
+	Kind              string
+	Reason            string
+}
+
 type Service struct {
 	cfg           Config
 	notifications ports.NotificationRepository
 	preferences   ports.PreferencesRepository
+	deliveries    ports.DeliveryRepository
+	contacts      ports.ContactRepository
+	senders       map[string]ports.ChannelSender
+	templates     *domain.TemplateCatalog
+	digests       ports.DigestRepository
+	stream        ports.StreamBus
 	scheduled     ports.ScheduledRepository
 	idempotency   ports.IdempotencyRepository
 	eventDedup    ports.EventDedupRepository
@@ -63,9 +90,20 @@ type Dependencies struct {
 	Config        Config
 	Notifications ports.NotificationRepository
 	Preferences   ports.PreferencesRepository
-	Scheduled     ports.ScheduledRepository
-	Idempotency   ports.IdempotencyRepository
-	EventDedup    ports.EventDedupRepository
+	Deliveries    ports.DeliveryRepository
+	Contacts      ports.ContactRepository
+	// Senders are the external channel adapters; channels without a sender
+	// are skipped.
+	Senders []ports.ChannelSender
+	// Templates defaults to domain.DefaultTemplates.
+	Templates *domain.TemplateCatalog
+	// Digests holds batched notifications; nil sends everything immediately.
+	Digests ports.DigestRepository
+	// Stream publishes inbox changes to connected clients; nil disables it.
+	Stream      ports.StreamBus
+	Scheduled   ports.ScheduledRepository
+	Idempotency ports.IdempotencyRepository
+	EventDedup  ports.EventDedupRepository
 }
 
 func NewService(deps Dependencies) *Service {
@@ -82,10 +120,33 @@ func NewService(deps Dependencies) *Service {
 	if cfg.ConsumerPollInterval <= 0 {
 		cfg.ConsumerPollInterval = 2 * time.Second
 	}
+	if cfg.DispatchMaxAttempts <= 0 {
+		cfg.DispatchMaxAttempts = 5
+	}
+	if cfg.DispatchRetryBase <= 0 {
+		cfg.DispatchRetryBase = 30 * time.Second
+	}
+	if cfg.DispatchBatchSize <= 0 {
+		cfg.DispatchBatchSize = 100
+	}
+	templates := deps.Templates
+	if templates == nil {
+		templates = domain.NewTemplateCatalog(domain.DefaultTemplates()...)
+	}
+	senders := map[string]ports.ChannelSender{}
+	for _, sender := range deps.Senders {
+		senders[sender.Channel()] = sender
+	}
 	return &Service{
 		cfg:           cfg,
 		notifications: deps.Notifications,
 		preferences:   deps.Preferences,
+		deliveries:    deps.Deliveries,
+		contacts:      deps.Contacts,
+		senders:       senders,
+		templates:     templates,
+		digests:       deps.Digests,
+		stream:        deps.Stream,
 		scheduled:     deps.Scheduled,
 		idempotency:   deps.Idempotency,
 		eventDedup:    deps.EventDedup,
@@ -67,6 +67,7 @@ type PreferencesResponse struct {
 	QuietHoursTZ      string   `json:"quiet_hours_timezone,omitempty"`
 	Language          string   `json:"language,omitempty"`
 	BatchingEnabled   bool     `json:"batching_enabled"`
+	DigestFrequency   string   `json:"digest_frequency,omitempty"`
 	MutedTypes        []string `json:"muted_types,omitempty"`
 	UpdatedAt         string   `json:"updated_at"`
 }
@@ -82,6 +83,7 @@ type UpdatePreferencesRequest struct {
 	QuietHoursTZ      string   `json:"quiet_hours_timezone,omitempty"`
 	Language          string   `json:"language,omitempty"`
 	BatchingEnabled   *bool    `json:"batching_enabled,omitempty"`
+	DigestFrequency   string   `json:"digest_frequency,omitempty"`
 	MutedTypes        []string `json:"muted_types,omitempty"`
 }
 
@@ -89,3 +91,45 @@ type DeleteScheduledResponse struct {
 	ScheduledID string `json:"scheduled_id"`
 	Cancelled   bool   `json:"cancelled"`
 }
+
+type ContactResponse struct {
+	UserID     string            `json:"user_id"`
+	Email      string            `json:"email,omitempty"`
+	Phone      string            `json:"phone,omitempty"`
+	PushTokens []string          `json:"push_tokens,omitempty"`
+	Suppressed map[string]string `json:"suppressed,omitempty"`
+	UpdatedAt  string            `json:"updated_at,omitempty"`
+}
+
+type UpdateContactRequest struct {
+	UserID     string   `json:"user_id,omitempty"`
+	Email      *string  `json:"email,omitempty"`
+	Phone      *string  `json:"phone,omitempty"`
+	PushTokens []string `json:"push_tokens,omitempty"`
+}
+
+type DeliveryItem struct {
+	DeliveryID        string `json:"delivery_id"`
+	NotificationID    string `json:"notification_id"`
+	Channel           string `json:"channel"`
+	Recipient         string `json:"recipient"`
+	Locale            string `json:"locale"`
+	Status            string `json:"status"`
+	Attempts          int    `json:"attempts"`
+	LastError         string `json:"last_error,omitempty"`
+	ProviderMessageID string `json:"provider_message_id,omitempty"`
+	NextAttemptAt     string `json:"next_attempt_at,omitempty"`
+	SentAt            string `json:"sent_at,omitempty"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+type ListDeliveriesResponse struct {
+	Items []DeliveryItem `json:"items"`
+}
+
+type DeliveryFeedbackRequest struct {
+	DeliveryID        string `json:"delivery_id,omitempty"`
+	ProviderMessageID string `json:"provider_message_id,omitempty"`
+	Kind              string `json:"kind"`
+	Reason            string `json:"reason,omitempty"`
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+	ChannelSMS   = "sms"
+)
+
+const (
+	DeliveryStatusPending    = "pending"
+	DeliveryStatusDeferred   = "deferred"
+	DeliveryStatusRetrying   = "retrying"
+	DeliveryStatusSent       = "sent"
+	DeliveryStatusDelivered  = "delivered"
+	DeliveryStatusFailed     = "failed"
+	DeliveryStatusBounced    = "bounced"
+	DeliveryStatusSuppressed = "suppressed"
+)
+
+const (
+	FeedbackDelivered   = "delivered"
+	FeedbackBounce      = "bounce"
+	FeedbackComplaint   = "complaint"
+	FeedbackUnsubscribe = "unsubscribe"
+)
+
+// Delivery tracks one notification sent over one external channel.
+type Delivery struct {
+	DeliveryID        string     `json:"delivery_id"`
+	NotificationID    string     `json:"notification_id"`
+	UserID            string     `json:"user_id"`
+	EventType         string     `json:"event_type"`
+	Channel           string     `json:"channel"`
+	Recipient         string     `json:"recipient"`
+	Locale            string     `json:"locale"`
+	Subject           string     `json:"subject,omitempty"`
+	Body              string     `json:"body"`
+	Status            string     `json:"status"`
+	Attempts          int        `json:"attempts"`
+	LastError         string     `json:"last_error,omitempty"`
+	ProviderMessageID string     `json:"provider_message_id,omitempty"`
+	NextAttemptAt     time.Time  `json:"next_attempt_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+}
+
+// IsDue reports whether the delivery is waiting for a send attempt at now.
+func (d Delivery) IsDue(now time.Time) bool {
+	switch d.Status {
+	case DeliveryStatusPending, DeliveryStatusDeferred, DeliveryStatusRetrying:
+		return !d.NextAttemptAt.After(now)
+	}
+	return false
+}
+
+// OutboundMessage is what a channel adapter sends.
+type OutboundMessage struct {
+	DeliveryID string
+	Channel    string
+	Recipient  string
+	Subject    string
+	Body       string
+	Locale     string
+	Data       map[string]string
+}
+
+// Contact holds the external destinations of a user. Suppressed maps a
+// channel to the reason it must not be used (bounce, complaint).
+type Contact struct {
+	UserID     string            `json:"user_id"`
+	Email      string            `json:"email,omitempty"`
+	Phone      string            `json:"phone,omitempty"`
+	PushTokens []string          `json:"push_tokens,omitempty"`
+	Suppressed map[string]string `json:"suppressed,omitempty"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// Recipients returns the destinations of channel that are not suppressed.
+func (c Contact) Recipients(channel string) []string {
+	if _, suppressed := c.Suppressed[channel]; suppressed {
+		return nil
+	}
+	switch channel {
+	case ChannelEmail:
+		if c.Email != "" {
+			return []string{c.Email}
+		}
+	case ChannelSMS:
+		if c.Phone != "" {
+			return []string{c.Phone}
+		}
+	case ChannelPush:
+		return append([]string(nil), c.PushTokens...)
+	}
+	return nil
+}
+
+// ChannelEnabled reports whether the user opted into channel.
+func (p Preferences) ChannelEnabled(channel string) bool {
+	switch channel {
+	case ChannelInApp:
+		return p.InAppEnabled
+	case ChannelEmail:
+		return p.EmailEnabled
+	case ChannelPush:
+		return p.PushEnabled
+	case ChannelSMS:
+		return p.SMSEnabled
+	}
+	return false
+}
+
+// IsMuted reports whether eventType is in MutedTypes.
+func (p Preferences) IsMuted(eventType string) bool {
+	for _, muted := range p.MutedTypes {
+		if muted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// QuietUntil returns the end of the quiet window containing now in the
+// user's timezone, or false when now is outside quiet hours. Windows may
+// wrap midnight ("22:00" to "07:00"); unparseable settings disable them.
+func (p Preferences) QuietUntil(now time.Time) (time.Time, bool) {
+	if !p.QuietHoursEnabled {
+		return time.Time{}, false
+	}
+	start, okStart := parseClock(p.QuietHoursStart)
+	end, okEnd := parseClock(p.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(p.QuietHoursTZ))
+	if err != nil || strings.TrimSpace(p.QuietHoursTZ) == "" {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	switch {
+	case start < end && minute >= start && minute < end:
+		return midnight.Add(time.Duration(end) * time.Minute).UTC(), true
+	case start > end && minute >= start:
+		return midnight.AddDate(0, 0, 1).Add(time.Duration(end) * time.Minute).UTC(), true
+	case start > end && minute < end:
+		return midnight.Add(time.Duration(end) * time.Minute).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(raw string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// urgentEvents bypass quiet hours because they are useless once delayed.
+var urgentEvents = map[string]bool{
+	EventAuth2FARequired: true,
+}
+
+func IsUrgentEvent(eventType string) bool { return urgentEvents[eventType] }
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// DigestEventType keys the templates used to title a digest.
+const DigestEventType = "notification.digest"
+
+// DigestDailyHour is the local hour at which daily digests go out.
+const DigestDailyHour = 8
+
+// DigestEntry is a rendered notification held back for a digest window.
+type DigestEntry struct {
+	EntryID       string            `json:"entry_id"`
+	UserID        string            `json:"user_id"`
+	EventType     string            `json:"event_type"`
+	Locale        string            `json:"locale"`
+	Title         string            `json:"title"`
+	Body          string            `json:"body"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	SourceEventID string            `json:"source_event_id"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DueAt         time.Time         `json:"due_at"`
+}
+
+// digestibleEvents are high-volume, low-urgency events worth collapsing.
+// Money movement, disputes and security events are always sent on their own.
+var digestibleEvents = map[string]bool{
+	EventCampaignBudgetUpdated: true,
+	EventCampaignCreated:       true,
+	EventCampaignLaunched:      true,
+	EventSubmissionApproved:    true,
+	EventSubmissionRejected:    true,
+}
+
+func IsDigestibleEvent(eventType string) bool { return digestibleEvents[eventType] }
+
+// ValidDigestFrequency reports whether frequency is hourly or daily.
+func ValidDigestFrequency(frequency string) bool {
+	return frequency == DigestHourly || frequency == DigestDaily
+}
+
+// DigestDueAt returns when the digest window containing now closes: the top
+// of the next hour, or the next DigestDailyHour in the user's timezone.
+func (p Preferences) DigestDueAt(now time.Time) time.Time {
+	if p.DigestFrequency != DigestDaily {
+		return now.UTC().Truncate(time.Hour).Add(time.Hour)
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(p.QuietHoursTZ))
+	if err != nil || strings.TrimSpace(p.QuietHoursTZ) == "" {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	due := time.Date(local.Year(), local.Month(), local.Day(), DigestDailyHour, 0, 0, 0, loc)
+	if !due.After(local) {
+		due = due.AddDate(0, 0, 1)
+	}
+	return due.UTC()
+}
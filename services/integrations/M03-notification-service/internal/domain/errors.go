@@ -14,4 +14,7 @@ var (
 	ErrUnsupportedEventType  = errors.New("unsupported_event_type")
 	ErrUnsupportedEventClass = errors.New("unsupported_event_class")
 	ErrPayloadTooLarge       = errors.New("payload_too_large")
+	// ErrRecipientRejected is wrapped by channel adapters when the provider
+	// permanently refuses a recipient (hard bounce, invalid token or number).
+	ErrRecipientRejected = errors.New("recipient_rejected")
 )
@@ -53,6 +53,7 @@ type Preferences struct {
 	QuietHoursTZ      string    `json:"quiet_hours_timezone,omitempty"`
 	Language          string    `json:"language,omitempty"`
 	BatchingEnabled   bool      `json:"batching_enabled"`
+	DigestFrequency   string    `json:"digest_frequency,omitempty"`
 	MutedTypes        []string  `json:"muted_types,omitempty"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
@@ -69,6 +70,7 @@ func DefaultPreferences(userID string, now time.Time) Preferences {
 		Language:          "en-US",
 		QuietHoursTZ:      "UTC",
 		BatchingEnabled:   false,
+		DigestFrequency:   DigestHourly,
 		UpdatedAt:         now.UTC(),
 	}
 }
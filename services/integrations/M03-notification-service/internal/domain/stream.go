@@ -0,0 +1,14 @@
+package domain
+
+const (
+	StreamKindNotification = "notification"
+	StreamKindUnreadCount  = "unread_count"
+)
+
+// StreamEvent is pushed to the connected clients of one user.
+type StreamEvent struct {
+	Kind         string        `json:"kind"`
+	UserID       string        `json:"user_id"`
+	Notification *Notification `json:"notification,omitempty"`
+	UnreadCount  int           `json:"unread_count"`
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultLocale is the last locale tried before the generic fallback.
+const DefaultLocale = "en-US"
+
+// Template is the copy of one event type in one locale. Title doubles as the
+// email subject and push title; SMS uses Body only. Both are text/template
+// sources rendered with the event payload fields.
+type Template struct {
+	EventType string `json:"event_type" yaml:"event_type"`
+	Locale    string `json:"locale" yaml:"locale"`
+	Title     string `json:"title" yaml:"title"`
+	Body      string `json:"body" yaml:"body"`
+}
+
+// RenderedMessage is a template applied to one event.
+type RenderedMessage struct {
+	Locale string
+	Title  string
+	Body   string
+}
+
+// TemplateCatalog holds templates keyed by event type and locale.
+type TemplateCatalog struct {
+	templates map[string]map[string]Template
+}
+
+func NewTemplateCatalog(templates ...Template) *TemplateCatalog {
+	c := &TemplateCatalog{templates: map[string]map[string]Template{}}
+	for _, t := range templates {
+		c.Put(t)
+	}
+	return c
+}
+
+// Put adds or replaces a template.
+func (c *TemplateCatalog) Put(t Template) {
+	locale := normalizeLocale(t.Locale)
+	if c.templates[t.EventType] == nil {
+		c.templates[t.EventType] = map[string]Template{}
+	}
+	t.Locale = locale
+	c.templates[t.EventType][locale] = t
+}
+
+// Resolve finds the template of eventType for locale, falling back from
+// "pt-BR" to "pt", then to DefaultLocale and its language, then to any
+// variant. The boolean is false when the event type has no templates.
+func (c *TemplateCatalog) Resolve(eventType, locale string) (Template, bool) {
+	variants := c.templates[eventType]
+	if len(variants) == 0 {
+		return Template{}, false
+	}
+	for _, candidate := range LocaleFallbacks(locale) {
+		if t, ok := variants[candidate]; ok {
+			return t, true
+		}
+	}
+	locales := make([]string, 0, len(variants))
+	for l := range variants {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return variants[locales[0]], true
+}
+
+// Render resolves and executes the template of eventType. Event types
+// without templates get a generic title derived from the type.
+func (c *TemplateCatalog) Render(eventType, locale string, vars map[string]string) (RenderedMessage, error) {
+	t, ok := c.Resolve(eventType, locale)
+	if !ok {
+		return RenderedMessage{
+			Locale: DefaultLocale,
+			Title:  HumanizeEventType(eventType),
+			Body:   fmt.Sprintf("Notification generated from %s", eventType),
+		}, nil
+	}
+	title, err := execute(t.Title, vars)
+	if err != nil {
+		return RenderedMessage{}, fmt.Errorf("render %s/%s title: %w", eventType, t.Locale, err)
+	}
+	body, err := execute(t.Body, vars)
+	if err != nil {
+		return RenderedMessage{}, fmt.Errorf("render %s/%s body: %w", eventType, t.Locale, err)
+	}
+	return RenderedMessage{Locale: t.Locale, Title: title, Body: body}, nil
+}
+
+// LocaleFallbacks lists the locales tried for locale, most specific first.
+func LocaleFallbacks(locale string) []string {
+	out := []string{}
+	add := func(l string) {
+		if l == "" {
+			return
+		}
+		for _, existing := range out {
+			if existing == l {
+				return
+			}
+		}
+		out = append(out, l)
+	}
+	for _, l := range []string{normalizeLocale(locale), DefaultLocale} {
+		add(l)
+		if lang, _, ok := strings.Cut(l, "-"); ok {
+			add(lang)
+		}
+	}
+	return out
+}
+
+// normalizeLocale canonicalises "pt_br" and "PT-br" to "pt-BR".
+func normalizeLocale(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	lang, region, ok := strings.Cut(locale, "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+func execute(source string, vars map[string]string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// HumanizeEventType turns "payout.failed" into "Payout Failed".
+func HumanizeEventType(t string) string {
+	words := strings.FieldsFunc(t, func(r rune) bool { return r == '.' || r == '_' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// DefaultTemplates is the built-in copy for every canonical input event.
+func DefaultTemplates() []Template {
+	return []Template{
+		{EventType: EventAuth2FARequired, Locale: "en-US", Title: "Your verification code", Body: "Use code {{.code}} to finish signing in. It expires in 10 minutes."},
+		{EventType: EventAuth2FARequired, Locale: "es", Title: "Tu código de verificación", Body: "Usa el código {{.code}} para terminar de iniciar sesión. Caduca en 10 minutos."},
+		{EventType: EventCampaignBudgetUpdated, Locale: "en-US", Title: "Campaign budget updated", Body: "The budget of campaign {{.campaign_id}} was updated."},
+		{EventType: EventCampaignCreated, Locale: "en-US", Title: "Campaign created", Body: "Campaign {{.campaign_id}} was created."},
+		{EventType: EventCampaignLaunched, Locale: "en-US", Title: "Campaign launched", Body: "Campaign {{.campaign_id}} is live."},
+		{EventType: EventCampaignLaunched, Locale: "es", Title: "Campaña lanzada", Body: "La campaña {{.campaign_id}} ya está activa."},
+		{EventType: EventDisputeCreated, Locale: "en-US", Title: "A dispute was opened", Body: "Dispute {{.dispute_id}} was opened{{if .reason}}: {{.reason}}{{end}}."},
+		{EventType: EventPayoutFailed, Locale: "en-US", Title: "Your payout failed", Body: "Payout {{.payout_id}} could not be completed{{if .reason}}: {{.reason}}{{end}}. Check your payout details to receive your funds."},
+		{EventType: EventPayoutFailed, Locale: "es", Title: "Tu pago ha fallado", Body: "No se pudo completar el pago {{.payout_id}}{{if .reason}}: {{.reason}}{{end}}. Revisa tus datos de cobro para recibir tus fondos."},
+		{EventType: EventPayoutFailed, Locale: "pt-BR", Title: "Seu pagamento falhou", Body: "O pagamento {{.payout_id}} não foi concluído{{if .reason}}: {{.reason}}{{end}}. Verifique seus dados de recebimento."},
+		{EventType: EventPayoutPaid, Locale: "en-US", Title: "Payout sent", Body: "Payout {{.payout_id}}{{if .amount}} of {{.amount}}{{end}} is on its way."},
+		{EventType: EventPayoutPaid, Locale: "es", Title: "Pago enviado", Body: "El pago {{.payout_id}}{{if .amount}} de {{.amount}}{{end}} está en camino."},
+		{EventType: EventSubmissionApproved, Locale: "en-US", Title: "Submission approved", Body: "Your submission {{.submission_id}} was approved."},
+		{EventType: EventSubmissionApproved, Locale: "es", Title: "Envío aprobado", Body: "Tu envío {{.submission_id}} fue aprobado."},
+		{EventType: EventSubmissionRejected, Locale: "en-US", Title: "Submission rejected", Body: "Your submission {{.submission_id}} was rejected{{if .reason}}: {{.reason}}{{end}}."},
+		{EventType: EventSubmissionRejected, Locale: "es", Title: "Envío rechazado", Body: "Tu envío {{.submission_id}} fue rechazado{{if .reason}}: {{.reason}}{{end}}."},
+		{EventType: EventTransactionFailed, Locale: "en-US", Title: "Transaction failed", Body: "Transaction {{.transaction_id}} failed{{if .reason}}: {{.reason}}{{end}}."},
+		{EventType: EventUserRegistered, Locale: "en-US", Title: "Welcome to ViralForge", Body: "Your account is ready."},
+		{EventType: EventUserRegistered, Locale: "es", Title: "Bienvenido a ViralForge", Body: "Tu cuenta está lista."},
+		{EventType: DigestEventType, Locale: "en-US", Title: "{{.count}} new: {{.label}}", Body: "You have {{.count}} new {{.label}} notifications. Latest: {{.latest}}"},
+		{EventType: DigestEventType, Locale: "es", Title: "{{.count}} nuevas: {{.label}}", Body: "Tienes {{.count}} notificaciones nuevas de {{.label}}. Última: {{.latest}}"},
+		{EventType: DigestEventType, Locale: "pt-BR", Title: "{{.count}} novas: {{.label}}", Body: "Você tem {{.count}} novas notificações de {{.label}}. Última: {{.latest}}"},
+	}
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// ChannelSender delivers messages over one external channel. Send returns the
+// provider message ID used to correlate bounce and delivery webhooks, and
+// wraps domain.ErrRecipientRejected for permanent recipient failures; other
+// errors are retried.
+type ChannelSender interface {
+	Channel() string
+	Send(ctx context.Context, msg domain.OutboundMessage) (string, error)
+}
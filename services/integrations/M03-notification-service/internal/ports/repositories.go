@@ -20,6 +20,26 @@ type PreferencesRepository interface {
 	Upsert(ctx context.Context, row domain.Preferences) error
 }
 
+type DigestRepository interface {
+	Add(ctx context.Context, row domain.DigestEntry) error
+	ListDue(ctx context.Context, now time.Time) ([]domain.DigestEntry, error)
+	Delete(ctx context.Context, entryIDs []string) error
+}
+
+type DeliveryRepository interface {
+	Create(ctx context.Context, row domain.Delivery) error
+	GetByID(ctx context.Context, deliveryID string) (domain.Delivery, error)
+	GetByProviderMessageID(ctx context.Context, providerMessageID string) (domain.Delivery, error)
+	Update(ctx context.Context, row domain.Delivery) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Delivery, error)
+	ListByNotificationID(ctx context.Context, notificationID string) ([]domain.Delivery, error)
+}
+
+type ContactRepository interface {
+	GetByUserID(ctx context.Context, userID string) (domain.Contact, error)
+	Upsert(ctx context.Context, row domain.Contact) error
+}
+
 type ScheduledRepository interface {
 	Delete(ctx context.Context, scheduledID string) (bool, error)
 }
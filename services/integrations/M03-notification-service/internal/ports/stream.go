@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+)
+
+// StreamBus fans stream events out to every API instance. Whichever process
+// changes a user's inbox publishes; each instance subscribes once and hands
+// events to its local subscribers.
+type StreamBus interface {
+	Publish(ctx context.Context, event domain.StreamEvent) error
+	Subscribe(ctx context.Context) (<-chan domain.StreamEvent, error)
+}
+
+// StreamSubscriber registers a connected client of userID. The returned
+// cancel func must be called when the client disconnects.
+type StreamSubscriber interface {
+	Subscribe(userID string) (<-chan domain.StreamEvent, func())
+}
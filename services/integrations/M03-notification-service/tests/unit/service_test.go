@@ -1,15 +1,27 @@
 package unit
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/channels"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/adapters/stream"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M03-notification-service/internal/ports"
 )
 
 func newService() *application.Service {
@@ -112,3 +124,478 @@ func TestHandlePhase6DependencyEvents(t *testing.T) {
 		t.Fatalf("expected 2 unread notifications for phase6 smoke, got len=%d unread=%d", len(items), unread)
 	}
 }
+
+func TestTemplateLocaleFallback(t *testing.T) {
+	catalog := domain.NewTemplateCatalog(domain.DefaultTemplates()...)
+	vars := map[string]string{"payout_id": "p-9", "reason": "account closed"}
+	cases := []struct {
+		locale     string
+		wantLocale string
+		wantTitle  string
+	}{
+		{"pt-BR", "pt-BR", "Seu pagamento falhou"},
+		{"pt_br", "pt-BR", "Seu pagamento falhou"},
+		{"es-MX", "es", "Tu pago ha fallado"},
+		{"fr-FR", "en-US", "Your payout failed"},
+		{"", "en-US", "Your payout failed"},
+	}
+	for _, tc := range cases {
+		msg, err := catalog.Render(domain.EventPayoutFailed, tc.locale, vars)
+		if err != nil {
+			t.Fatalf("render %q: %v", tc.locale, err)
+		}
+		if msg.Locale != tc.wantLocale || msg.Title != tc.wantTitle {
+			t.Fatalf("locale %q: got %s %q", tc.locale, msg.Locale, msg.Title)
+		}
+		if !strings.Contains(msg.Body, "p-9") || !strings.Contains(msg.Body, "account closed") {
+			t.Fatalf("locale %q: payload not rendered into %q", tc.locale, msg.Body)
+		}
+	}
+	msg, _ := catalog.Render("creator.tier_changed", "es", nil)
+	if msg.Title != "Creator Tier Changed" {
+		t.Fatalf("expected generic title, got %q", msg.Title)
+	}
+}
+
+func TestQuietHoursDeferUntilWindowEndsInUserTimezone(t *testing.T) {
+	prefs := domain.Preferences{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", QuietHoursTZ: "America/Sao_Paulo"}
+	// 02:30 UTC is 23:30 in São Paulo (UTC-3), inside the window.
+	until, quiet := prefs.QuietUntil(time.Date(2026, 3, 10, 2, 30, 0, 0, time.UTC))
+	if !quiet || !until.Equal(time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected deferral to 10:00 UTC, got %s quiet=%v", until, quiet)
+	}
+	if _, quiet := prefs.QuietUntil(time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)); quiet {
+		t.Fatal("noon local time must not be quiet")
+	}
+	if !domain.IsUrgentEvent(domain.EventAuth2FARequired) || domain.IsUrgentEvent(domain.EventPayoutFailed) {
+		t.Fatal("only 2FA codes bypass quiet hours")
+	}
+}
+
+type flakyDeliveries struct {
+	ports.DeliveryRepository
+	failChannel string
+}
+
+func (r flakyDeliveries) Update(ctx context.Context, row domain.Delivery) error {
+	if row.Channel == r.failChannel {
+		return errors.New("delivery store unavailable")
+	}
+	return r.DeliveryRepository.Update(ctx, row)
+}
+
+func TestDispatchDueContinuesPastFailedUpdate(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	email := channels.NewStubSender(domain.ChannelEmail, nil)
+	push := channels.NewStubSender(domain.ChannelPush, nil)
+	svc := application.NewService(application.Dependencies{
+		Notifications: repos.Notifications, Preferences: repos.Preferences, Scheduled: repos.Scheduled, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
+		Deliveries: flakyDeliveries{DeliveryRepository: repos.Deliveries, failChannel: domain.ChannelEmail}, Contacts: repos.Contacts, Senders: []ports.ChannelSender{email, push},
+	})
+	user := application.Actor{SubjectID: "u5", Role: "user"}
+	emailAddr := "creator5@example.com"
+	if _, err := svc.UpdateContact(ctx, user, application.UpdateContactInput{Email: &emailAddr, PushTokens: []string{"tok-5"}}); err != nil {
+		t.Fatalf("update contact: %v", err)
+	}
+	data, _ := json.Marshal(map[string]any{"user_id": "u5", "payout_id": "p-8"})
+	if err := svc.HandleCanonicalEvent(ctx, contracts.EventEnvelope{EventID: "evt-dispatch-2", EventType: domain.EventPayoutFailed, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.payout_id", PartitionKey: "p-8", SourceService: "M14", TraceID: "t", SchemaVersion: "v1", Data: data}); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	n, err := svc.DispatchDue(ctx)
+	if err == nil || n != 2 {
+		t.Fatalf("expected both deliveries attempted with the update error reported, got %d err=%v", n, err)
+	}
+	if len(push.Sent()) != 1 {
+		t.Fatalf("expected push sent despite the email update failure, got %+v", push.Sent())
+	}
+}
+
+// secondCreateFails fails the second delivery write once, as a crash between
+// two inserts would.
+type secondCreateFails struct {
+	ports.DeliveryRepository
+	mu    sync.Mutex
+	calls int
+}
+
+func (r *secondCreateFails) Create(ctx context.Context, row domain.Delivery) error {
+	r.mu.Lock()
+	r.calls++
+	fail := r.calls == 2
+	r.mu.Unlock()
+	if fail {
+		return errors.New("delivery store unavailable")
+	}
+	return r.DeliveryRepository.Create(ctx, row)
+}
+
+func TestRedeliveredEventDoesNotDuplicateNotificationOrDeliveries(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	deliveries := &secondCreateFails{DeliveryRepository: repos.Deliveries}
+	svc := application.NewService(application.Dependencies{
+		Notifications: repos.Notifications, Preferences: repos.Preferences, Scheduled: repos.Scheduled, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
+		Deliveries: deliveries, Contacts: repos.Contacts, Senders: []ports.ChannelSender{channels.NewStubSender(domain.ChannelEmail, nil), channels.NewStubSender(domain.ChannelPush, nil)},
+	})
+	user := application.Actor{SubjectID: "u6", Role: "user"}
+	emailAddr := "creator6@example.com"
+	if _, err := svc.UpdateContact(ctx, user, application.UpdateContactInput{Email: &emailAddr, PushTokens: []string{"tok-6"}}); err != nil {
+		t.Fatalf("update contact: %v", err)
+	}
+	data, _ := json.Marshal(map[string]any{"user_id": "u6", "payout_id": "p-9"})
+	event := contracts.EventEnvelope{EventID: "evt-retry-1", EventType: domain.EventPayoutFailed, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.payout_id", PartitionKey: "p-9", SourceService: "M14", TraceID: "t", SchemaVersion: "v1", Data: data}
+	if err := svc.HandleCanonicalEvent(ctx, event); err == nil {
+		t.Fatal("expected the failed delivery write to fail the event")
+	}
+	if err := svc.HandleCanonicalEvent(ctx, event); err != nil {
+		t.Fatalf("redeliver event: %v", err)
+	}
+	items, _, _, err := svc.ListNotifications(ctx, user, application.ListNotificationsInput{})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected one notification after redelivery, got %+v err=%v", items, err)
+	}
+	planned, err := svc.ListDeliveries(ctx, user, items[0].NotificationID)
+	if err != nil || len(planned) != 2 {
+		t.Fatalf("expected one email and one push delivery, got %+v err=%v", planned, err)
+	}
+}
+
+// fakeSMTPRelay accepts one message over STARTTLS and records whether the
+// message body arrived encrypted.
+func fakeSMTPRelay(t *testing.T, cert tls.Certificate) (string, <-chan bool) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	done := make(chan bool, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		encrypted := false
+		var w io.Writer = conn
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = w.Write([]byte(line + "\r\n")) }
+		reply("220 relay.test ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				if encrypted {
+					reply("250 relay.test")
+				} else {
+					reply("250-relay.test")
+					reply("250 STARTTLS")
+				}
+			case cmd == "STARTTLS":
+				reply("220 ready")
+				tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}})
+				if err := tlsConn.Handshake(); err != nil {
+					done <- false
+					return
+				}
+				encrypted, r, w = true, bufio.NewReader(tlsConn), tlsConn
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				reply("250 ok")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					body, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if body == ".\r\n" {
+						break
+					}
+				}
+				reply("250 queued")
+				done <- encrypted
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+	return ln.Addr().String(), done
+}
+
+func TestSMTPSenderUpgradesToSTARTTLS(t *testing.T) {
+	certSource := httptest.NewTLSServer(nil)
+	defer certSource.Close()
+	addr, done := fakeSMTPRelay(t, certSource.TLS.Certificates[0])
+	roots := x509.NewCertPool()
+	roots.AddCert(certSource.Certificate())
+	sender := channels.NewSMTPSender(channels.SMTPConfig{Addr: addr, From: "noreply@viralforge.test", TLS: &tls.Config{RootCAs: roots}})
+	id, err := sender.Send(context.Background(), domain.OutboundMessage{DeliveryID: "dlv-1", Recipient: "creator@example.com", Subject: "Hi", Body: "hello"})
+	if err != nil {
+		t.Fatalf("send over STARTTLS: %v", err)
+	}
+	if !strings.HasPrefix(id, "<dlv-1@") {
+		t.Fatalf("unexpected message id %q", id)
+	}
+	if encrypted := <-done; !encrypted {
+		t.Fatal("expected the message to be sent after the TLS upgrade")
+	}
+}
+
+func TestDispatchRetriesBouncesAndHonoursUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	email := channels.NewStubSender(domain.ChannelEmail, nil)
+	push := channels.NewStubSender(domain.ChannelPush, nil)
+	sms := channels.NewStubSender(domain.ChannelSMS, nil)
+	svc := application.NewService(application.Dependencies{
+		Config:        application.Config{DispatchRetryBase: time.Millisecond, DispatchMaxAttempts: 3},
+		Notifications: repos.Notifications, Preferences: repos.Preferences, Scheduled: repos.Scheduled, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
+		Deliveries: repos.Deliveries, Contacts: repos.Contacts, Senders: []ports.ChannelSender{email, push, sms},
+	})
+	user := application.Actor{SubjectID: "u4", Role: "user"}
+	emailAddr, phone := "creator@example.com", "+5511999990000"
+	if _, err := svc.UpdateContact(ctx, user, application.UpdateContactInput{Email: &emailAddr, Phone: &phone, PushTokens: []string{"tok-live", "tok-dead"}}); err != nil {
+		t.Fatalf("update contact: %v", err)
+	}
+	enabled := true
+	if _, err := svc.UpdatePreferences(ctx, user, application.UpdatePreferencesInput{SMSEnabled: &enabled, Language: "pt-BR"}); err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	push.Reject["tok-dead"] = true
+	sms.Fail[phone] = true
+
+	data, _ := json.Marshal(map[string]any{"user_id": "u4", "payout_id": "p-7"})
+	if err := svc.HandleCanonicalEvent(ctx, contracts.EventEnvelope{EventID: "evt-dispatch-1", EventType: domain.EventPayoutFailed, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.payout_id", PartitionKey: "p-7", SourceService: "M14", TraceID: "t", SchemaVersion: "v1", Data: data}); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	if n, err := svc.DispatchDue(ctx); err != nil || n != 4 {
+		t.Fatalf("expected 4 deliveries attempted, got %d err=%v", n, err)
+	}
+	if sent := email.Sent(); len(sent) != 1 || sent[0].Subject != "Seu pagamento falhou" {
+		t.Fatalf("expected localised email, got %+v", sent)
+	}
+	contact, _ := svc.GetContact(ctx, user)
+	if len(contact.PushTokens) != 1 || contact.PushTokens[0] != "tok-live" {
+		t.Fatalf("expected rejected push token dropped, got %v", contact.PushTokens)
+	}
+
+	sms.Fail[phone] = false
+	time.Sleep(5 * time.Millisecond)
+	if n, err := svc.DispatchDue(ctx); err != nil || n != 1 {
+		t.Fatalf("expected sms retry, got %d err=%v", n, err)
+	}
+	items, _, _, _ := svc.ListNotifications(ctx, user, application.ListNotificationsInput{})
+	deliveries, err := svc.ListDeliveries(ctx, user, items[0].NotificationID)
+	if err != nil {
+		t.Fatalf("list deliveries: %v", err)
+	}
+	statuses := map[string]string{}
+	var emailDelivery domain.Delivery
+	for _, d := range deliveries {
+		statuses[d.Channel+":"+d.Recipient] = d.Status
+		if d.Channel == domain.ChannelEmail {
+			emailDelivery = d
+		}
+	}
+	if statuses["sms:"+phone] != domain.DeliveryStatusSent || statuses["push:tok-dead"] != domain.DeliveryStatusBounced || statuses["push:tok-live"] != domain.DeliveryStatusSent {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+
+	service := application.Actor{SubjectID: "mailer", Role: "service"}
+	if _, err := svc.RecordDeliveryFeedback(ctx, user, application.DeliveryFeedbackInput{DeliveryID: emailDelivery.DeliveryID, Kind: "unsubscribe"}); err != domain.ErrForbidden {
+		t.Fatalf("expected users to be refused feedback, got %v", err)
+	}
+	if _, err := svc.RecordDeliveryFeedback(ctx, service, application.DeliveryFeedbackInput{ProviderMessageID: emailDelivery.ProviderMessageID, Kind: "unsubscribe"}); err != nil {
+		t.Fatalf("unsubscribe feedback: %v", err)
+	}
+	prefs, _ := svc.GetPreferences(ctx, user)
+	if prefs.EmailEnabled {
+		t.Fatal("expected unsubscribe to disable email")
+	}
+}
+
+func submissionApproved(eventID, userID, submissionID string) contracts.EventEnvelope {
+	data, _ := json.Marshal(map[string]any{"user_id": userID, "submission_id": submissionID})
+	return contracts.EventEnvelope{EventID: eventID, EventType: domain.EventSubmissionApproved, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.submission_id", PartitionKey: submissionID, SourceService: "M26", TraceID: "t", SchemaVersion: "v1", Data: data}
+}
+
+func TestDigestCollapsesBurstOfSameTypeNotifications(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{Notifications: repos.Notifications, Preferences: repos.Preferences, Scheduled: repos.Scheduled, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Digests: repos.Digests})
+	user := application.Actor{SubjectID: "u5", Role: "user"}
+	enabled := true
+	if _, err := svc.UpdatePreferences(ctx, user, application.UpdatePreferencesInput{BatchingEnabled: &enabled, DigestFrequency: "weekly"}); err != domain.ErrInvalidInput {
+		t.Fatalf("expected unsupported frequency to be rejected, got %v", err)
+	}
+	if _, err := svc.UpdatePreferences(ctx, user, application.UpdatePreferencesInput{BatchingEnabled: &enabled, DigestFrequency: "hourly"}); err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	for i := 0; i < 40; i++ {
+		id := "s-" + string(rune('A'+i%26)) + string(rune('a'+i/26))
+		if err := svc.HandleCanonicalEvent(ctx, submissionApproved("evt-digest-"+id, "u5", id)); err != nil {
+			t.Fatalf("handle event: %v", err)
+		}
+	}
+	payout, _ := json.Marshal(map[string]any{"user_id": "u5", "payout_id": "p-5"})
+	if err := svc.HandleCanonicalEvent(ctx, contracts.EventEnvelope{EventID: "evt-digest-payout", EventType: domain.EventPayoutFailed, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.payout_id", PartitionKey: "p-5", SourceService: "M14", TraceID: "t", SchemaVersion: "v1", Data: payout}); err != nil {
+		t.Fatalf("handle payout: %v", err)
+	}
+	items, _, _, _ := svc.ListNotifications(ctx, user, application.ListNotificationsInput{})
+	if len(items) != 1 || items[0].Type != domain.EventPayoutFailed {
+		t.Fatalf("expected only the payout failure before the window closes, got %d", len(items))
+	}
+	if n, err := svc.FlushDigests(ctx); err != nil || n != 0 {
+		t.Fatalf("expected open window to hold entries, got %d err=%v", n, err)
+	}
+
+	// Close the window by moving the held entries' due time into the past.
+	held, _ := repos.Digests.ListDue(ctx, time.Now().Add(48*time.Hour))
+	if len(held) != 40 {
+		t.Fatalf("expected 40 held entries, got %d", len(held))
+	}
+	past := time.Now().Add(-time.Minute)
+	for _, entry := range held {
+		_ = repos.Digests.Delete(ctx, []string{entry.EntryID})
+		entry.DueAt = past
+		_ = repos.Digests.Add(ctx, entry)
+	}
+	if n, err := svc.FlushDigests(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one digest, got %d err=%v", n, err)
+	}
+	items, _, unread, _ := svc.ListNotifications(ctx, user, application.ListNotificationsInput{Type: domain.EventSubmissionApproved})
+	if len(items) != 1 || unread != 2 {
+		t.Fatalf("expected a single digest notification, got len=%d unread=%d", len(items), unread)
+	}
+	if items[0].Metadata["digest_count"] != "40" || items[0].Title != "40 new: Submission Approved" {
+		t.Fatalf("unexpected digest %q %v", items[0].Title, items[0].Metadata)
+	}
+}
+
+func TestDigestDailyWindowClosesAtLocalMorning(t *testing.T) {
+	prefs := domain.Preferences{DigestFrequency: domain.DigestDaily, QuietHoursTZ: "Europe/Berlin"}
+	// 09:30 in Berlin (UTC+2 in summer) is past today's 08:00 cut-off.
+	due := prefs.DigestDueAt(time.Date(2026, 7, 1, 7, 30, 0, 0, time.UTC))
+	if !due.Equal(time.Date(2026, 7, 2, 6, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected next morning 06:00 UTC, got %s", due)
+	}
+	hourly := domain.Preferences{DigestFrequency: domain.DigestHourly}.DigestDueAt(time.Date(2026, 7, 1, 7, 30, 0, 0, time.UTC))
+	if !hourly.Equal(time.Date(2026, 7, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected top of next hour, got %s", hourly)
+	}
+}
+
+func TestFailedDigestFlushDoesNotSendTheDigestTwice(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	deliveries := &secondCreateFails{DeliveryRepository: repos.Deliveries}
+	svc := application.NewService(application.Dependencies{
+		Notifications: repos.Notifications, Preferences: repos.Preferences, Scheduled: repos.Scheduled, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Digests: repos.Digests,
+		Deliveries: deliveries, Contacts: repos.Contacts, Senders: []ports.ChannelSender{channels.NewStubSender(domain.ChannelEmail, nil), channels.NewStubSender(domain.ChannelPush, nil)},
+	})
+	user := application.Actor{SubjectID: "u7", Role: "user"}
+	emailAddr := "creator7@example.com"
+	if _, err := svc.UpdateContact(ctx, user, application.UpdateContactInput{Email: &emailAddr, PushTokens: []string{"tok-7"}}); err != nil {
+		t.Fatalf("update contact: %v", err)
+	}
+	enabled := true
+	if _, err := svc.UpdatePreferences(ctx, user, application.UpdatePreferencesInput{BatchingEnabled: &enabled, DigestFrequency: "hourly"}); err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	for _, id := range []string{"s-1", "s-2", "s-3"} {
+		if err := svc.HandleCanonicalEvent(ctx, submissionApproved("evt-flush-"+id, "u7", id)); err != nil {
+			t.Fatalf("handle event: %v", err)
+		}
+	}
+	held, _ := repos.Digests.ListDue(ctx, time.Now().Add(48*time.Hour))
+	past := time.Now().Add(-time.Minute)
+	for _, entry := range held {
+		_ = repos.Digests.Delete(ctx, []string{entry.EntryID})
+		entry.DueAt = past
+		_ = repos.Digests.Add(ctx, entry)
+	}
+	if _, err := svc.FlushDigests(ctx); err == nil {
+		t.Fatal("expected the failed delivery write to fail the flush")
+	}
+	if n, err := svc.FlushDigests(ctx); err != nil || n != 1 {
+		t.Fatalf("expected the retried flush to finish the batch, got %d err=%v", n, err)
+	}
+	items, _, _, _ := svc.ListNotifications(ctx, user, application.ListNotificationsInput{Type: domain.EventSubmissionApproved})
+	if len(items) != 1 || items[0].Metadata["digest_count"] != "3" {
+		t.Fatalf("expected a single digest after the retry, got %+v", items)
+	}
+	planned, err := svc.ListDeliveries(ctx, user, items[0].NotificationID)
+	if err != nil || len(planned) != 2 {
+		t.Fatalf("expected one email and one push delivery, got %+v err=%v", planned, err)
+	}
+}
+
+func TestStreamPublishesNotificationsUnreadCountsAndResumes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repos := postgres.NewRepositories()
+	bus := stream.NewMemoryBus()
+	events, err := bus.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	svc := application.NewService(application.Dependencies{Notifications: repos.Notifications, Preferences: repos.Preferences, Scheduled: repos.Scheduled, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Stream: bus})
+	next := func() domain.StreamEvent {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for stream event")
+			return domain.StreamEvent{}
+		}
+	}
+
+	if err := svc.HandleCanonicalEvent(ctx, submissionApproved("evt-stream-1", "u6", "s-1")); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	first := next()
+	if first.Kind != domain.StreamKindNotification || first.UserID != "u6" || first.UnreadCount != 1 {
+		t.Fatalf("unexpected first event %+v", first)
+	}
+	time.Sleep(time.Millisecond)
+	if err := svc.HandleCanonicalEvent(ctx, submissionApproved("evt-stream-2", "u6", "s-2")); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	second := next()
+
+	user := application.Actor{SubjectID: "u6", Role: "user"}
+	if _, err := svc.MarkRead(ctx, user, first.Notification.NotificationID); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if e := next(); e.Kind != domain.StreamKindUnreadCount || e.UnreadCount != 1 {
+		t.Fatalf("expected unread count 1, got %+v", e)
+	}
+
+	missed, unread, err := svc.NotificationsSince(ctx, user, first.Notification.NotificationID)
+	if err != nil || unread != 1 || len(missed) != 1 || missed[0].NotificationID != second.Notification.NotificationID {
+		t.Fatalf("expected resume to replay the second notification, got %d unread=%d err=%v", len(missed), unread, err)
+	}
+	if missed, _, _ := svc.NotificationsSince(ctx, application.Actor{SubjectID: "u7", Role: "user"}, first.Notification.NotificationID); len(missed) != 0 {
+		t.Fatal("resume must not replay another user's notifications")
+	}
+
+	hub := stream.NewHub(nil)
+	sub, unsubscribe := hub.Subscribe("u6")
+	hub.Broadcast(domain.StreamEvent{Kind: domain.StreamKindUnreadCount, UserID: "u7"})
+	hub.Broadcast(second)
+	if got := <-sub; got.Notification == nil || got.Notification.NotificationID != second.Notification.NotificationID {
+		t.Fatalf("hub delivered %+v", got)
+	}
+	unsubscribe()
+	if _, open := <-sub; open {
+		t.Fatal("expected channel closed after unsubscribe")
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/contracts"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
 )
 
 type Handler struct{ service *application.Service }
@@ -47,7 +49,7 @@ func (h *Handler) callback(w http.ResponseWriter, r *http.Request) {
 	if userID == "" {
 		userID = strings.TrimSpace(actor.SubjectID)
 	}
-	acc, err := h.service.OAuthCallback(r.Context(), actor, application.CallbackInput{Provider: chi.URLParam(r, "provider"), UserID: userID, Code: req.Code, State: req.State, Handle: req.Handle})
+	acc, err := h.service.OAuthCallback(r.Context(), actor, application.CallbackInput{Provider: chi.URLParam(r, "provider"), UserID: userID, Code: req.Code, State: req.State})
 	if err != nil {
 		code, e := mapDomainError(err)
 		writeError(w, code, e, err.Error(), requestIDFromContext(r.Context()))
@@ -94,7 +96,7 @@ func (h *Handler) followersSync(w http.ResponseWriter, r *http.Request) {
 	if userID == "" {
 		userID = strings.TrimSpace(actor.SubjectID)
 	}
-	row, err := h.service.RecordFollowersSync(r.Context(), actor, application.RecordFollowersSyncInput{SocialAccountID: chi.URLParam(r, "social_account_id"), UserID: userID, FollowerCount: req.FollowerCount})
+	row, err := h.service.RecordFollowersSync(r.Context(), actor, application.RecordFollowersSyncInput{SocialAccountID: chi.URLParam(r, "social_account_id"), UserID: userID})
 	if err != nil {
 		code, e := mapDomainError(err)
 		writeError(w, code, e, err.Error(), requestIDFromContext(r.Context()))
@@ -114,12 +116,93 @@ func (h *Handler) validatePost(w http.ResponseWriter, r *http.Request) {
 	if userID == "" {
 		userID = strings.TrimSpace(actor.SubjectID)
 	}
-	if err := h.service.ValidatePost(r.Context(), actor, application.PostValidationInput{UserID: userID, Platform: req.Platform, PostID: req.PostID}); err != nil {
+	reqs, err := toPostRequirements(req.Requirements)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	report, err := h.service.ValidatePost(r.Context(), actor, application.PostValidationInput{UserID: userID, Platform: req.Platform, PostID: req.PostID, CampaignID: req.CampaignID, Requirements: reqs})
+	if err != nil {
 		code, e := mapDomainError(err)
 		writeError(w, code, e, err.Error(), requestIDFromContext(r.Context()))
 		return
 	}
-	writeSuccess(w, http.StatusOK, "post validated", nil)
+	rules := make([]contracts.RuleResultResponse, 0, len(report.Rules))
+	for _, rule := range report.Rules {
+		rules = append(rules, contracts.RuleResultResponse{Rule: rule.Rule, Passed: rule.Passed, Detail: rule.Detail})
+	}
+	message := "post validated"
+	if !report.Passed {
+		message = "post failed verification"
+	}
+	writeSuccess(w, http.StatusOK, message, contracts.PostVerificationResponse{PostID: report.PostID, Platform: report.Platform, CampaignID: report.CampaignID, PostURL: report.PostURL, Passed: report.Passed, Rules: rules, CheckedAt: report.CheckedAt.UTC().Format(time.RFC3339)})
+}
+
+func (h *Handler) setCampaignRequirements(w http.ResponseWriter, r *http.Request) {
+	var req contracts.PostRequirementsRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	reqs, err := toPostRequirements(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	row, err := h.service.SetCampaignRequirements(r.Context(), actorFromContext(r.Context()), application.SetCampaignRequirementsInput{CampaignID: chi.URLParam(r, "campaign_id"), Requirements: reqs})
+	if err != nil {
+		code, e := mapDomainError(err)
+		writeError(w, code, e, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "campaign requirements saved", toCampaignRequirementsResponse(row))
+}
+
+func (h *Handler) getCampaignRequirements(w http.ResponseWriter, r *http.Request) {
+	row, err := h.service.GetCampaignRequirements(r.Context(), actorFromContext(r.Context()), chi.URLParam(r, "campaign_id"))
+	if err != nil {
+		code, e := mapDomainError(err)
+		writeError(w, code, e, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "campaign requirements", toCampaignRequirementsResponse(row))
+}
+
+func toPostRequirements(req contracts.PostRequirementsRequest) (domain.PostRequirements, error) {
+	reqs := domain.PostRequirements{
+		RequiredHashtags:  req.RequiredHashtags,
+		RequiredMentions:  req.RequiredMentions,
+		RequireDisclosure: req.RequireDisclosure,
+		DisclosureTags:    req.DisclosureTags,
+		MinDuration:       time.Duration(req.MinDurationSeconds) * time.Second,
+	}
+	if raw := strings.TrimSpace(req.PostedAfter); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return domain.PostRequirements{}, errors.New("posted_after must be RFC3339")
+		}
+		reqs.PostedAfter = &t
+	}
+	return reqs, nil
+}
+
+func toCampaignRequirementsResponse(row domain.CampaignRequirements) contracts.CampaignRequirementsResponse {
+	out := contracts.CampaignRequirementsResponse{
+		CampaignID: row.CampaignID,
+		Requirements: contracts.PostRequirementsRequest{
+			RequiredHashtags:   row.Requirements.RequiredHashtags,
+			RequiredMentions:   row.Requirements.RequiredMentions,
+			RequireDisclosure:  row.Requirements.RequireDisclosure,
+			DisclosureTags:     row.Requirements.DisclosureTags,
+			MinDurationSeconds: int(row.Requirements.MinDuration / time.Second),
+		},
+		UpdatedBy: row.UpdatedBy,
+		UpdatedAt: row.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if row.Requirements.PostedAfter != nil {
+		out.Requirements.PostedAfter = row.Requirements.PostedAfter.UTC().Format(time.RFC3339)
+	}
+	return out
 }
 
 func (h *Handler) complianceViolation(w http.ResponseWriter, r *http.Request) {
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/contracts"
@@ -45,6 +46,21 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusBadRequest, "unsupported_event"
 	case domain.ErrUnsupportedEventClass, domain.ErrInvalidEnvelope:
 		return http.StatusBadRequest, "invalid_event_envelope"
+	case domain.ErrInvalidOAuthState:
+		return http.StatusBadRequest, "invalid_oauth_state"
+	case domain.ErrAccountClaimed:
+		return http.StatusConflict, "account_claimed"
+	case domain.ErrRuleUnsupported:
+		return http.StatusUnprocessableEntity, "rule_unsupported"
+	}
+	// Provider errors arrive wrapped with the upstream detail.
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, domain.ErrProviderRejected):
+		return http.StatusBadRequest, "provider_rejected"
+	case errors.Is(err, domain.ErrProviderUnavailable):
+		return http.StatusBadGateway, "provider_unavailable"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
@@ -25,6 +25,8 @@ func registerSocialRoutes(r chi.Router, handler *Handler) {
 		r.Delete("/social/accounts/{social_account_id}", handler.disconnect)
 		r.Post("/social/accounts/{social_account_id}/followers-sync", handler.followersSync)
 		r.Post("/social/posts/validate", handler.validatePost)
+		r.Put("/social/campaigns/{campaign_id}/requirements", handler.setCampaignRequirements)
+		r.Get("/social/campaigns/{campaign_id}/requirements", handler.getCampaignRequirements)
 		r.Post("/social/posts/compliance-violation", handler.complianceViolation)
 	})
 }
@@ -17,6 +17,8 @@ type Repositories struct {
 	Idempotency *IdempotencyRepository
 	EventDedup  *EventDedupRepository
 	Outbox      *OutboxRepository
+	OAuthStates *OAuthStateRepository
+	Campaigns   *CampaignRequirementsRepository
 }
 
 func NewRepositories() *Repositories {
@@ -26,6 +28,8 @@ func NewRepositories() *Repositories {
 		Idempotency: &IdempotencyRepository{rows: map[string]ports.IdempotencyRecord{}},
 		EventDedup:  &EventDedupRepository{rows: map[string]eventDedupRow{}},
 		Outbox:      &OutboxRepository{rows: map[string]ports.OutboxRecord{}, order: []string{}},
+		OAuthStates: &OAuthStateRepository{rows: map[string]domain.OAuthState{}},
+		Campaigns:   &CampaignRequirementsRepository{rows: map[string]domain.CampaignRequirements{}},
 	}
 }
 
@@ -178,3 +182,89 @@ func (r *OutboxRepository) MarkSent(_ context.Context, recordID string, at time.
 	r.rows[recordID] = row
 	return nil
 }
+
+func (r *SocialAccountRepository) GetByProviderExternalID(_ context.Context, provider, externalID string) (domain.SocialAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.byID {
+		if row.Provider == provider && row.ExternalID != "" && row.ExternalID == externalID {
+			return row, nil
+		}
+	}
+	return domain.SocialAccount{}, domain.ErrNotFound
+}
+
+func (r *SocialAccountRepository) ListDueForSync(_ context.Context, before time.Time, limit int) ([]domain.SocialAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.SocialAccount, 0)
+	for _, row := range r.byID {
+		if row.Status != domain.AccountStatusActive {
+			continue
+		}
+		if row.LastSyncedAt == nil || row.LastSyncedAt.Before(before) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].LastSyncedAt == nil || out[j].LastSyncedAt == nil {
+			return out[i].LastSyncedAt == nil && out[j].LastSyncedAt != nil
+		}
+		return out[i].LastSyncedAt.Before(*out[j].LastSyncedAt)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+type OAuthStateRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.OAuthState
+}
+
+func (r *OAuthStateRepository) Put(_ context.Context, row domain.OAuthState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rows[row.State]; ok {
+		return domain.ErrConflict
+	}
+	r.rows[row.State] = row
+	return nil
+}
+
+func (r *OAuthStateRepository) Take(_ context.Context, state string, now time.Time) (domain.OAuthState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[state]
+	if !ok {
+		return domain.OAuthState{}, domain.ErrNotFound
+	}
+	delete(r.rows, state)
+	if !row.ExpiresAt.After(now) {
+		return domain.OAuthState{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+
+type CampaignRequirementsRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.CampaignRequirements
+}
+
+func (r *CampaignRequirementsRepository) Put(_ context.Context, row domain.CampaignRequirements) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[row.CampaignID] = row
+	return nil
+}
+
+func (r *CampaignRequirementsRepository) Get(_ context.Context, campaignID string) (domain.CampaignRequirements, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[strings.TrimSpace(campaignID)]
+	if !ok {
+		return domain.CampaignRequirements{}, domain.ErrNotFound
+	}
+	return row, nil
+}
@@ -0,0 +1,227 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+)
+
+// Endpoints locate the OAuth and API servers of a provider.
+type Endpoints struct {
+	AuthURL    string
+	TokenURL   string
+	APIBaseURL string
+}
+
+// Config is one OAuth application registered with a provider.
+type Config struct {
+	Provider     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Endpoints    Endpoints
+	Timeout      time.Duration
+}
+
+// Connector implements ports.ProviderConnector with the authorization-code
+// grant and S256 PKCE. Token requests are form-encoded per RFC 6749; the
+// profile call and response shape differ per provider.
+type Connector struct {
+	cfg    Config
+	spec   spec
+	client *http.Client
+	nowFn  func() time.Time
+}
+
+// NewConnector builds the connector of cfg.Provider ("instagram", "tiktok",
+// "youtube" or "twitter"). Empty endpoints default to the production ones.
+func NewConnector(cfg Config) (*Connector, error) {
+	sp, ok := specs[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider %q", cfg.Provider)
+	}
+	if cfg.Endpoints.AuthURL == "" {
+		cfg.Endpoints.AuthURL = sp.endpoints.AuthURL
+	}
+	if cfg.Endpoints.TokenURL == "" {
+		cfg.Endpoints.TokenURL = sp.endpoints.TokenURL
+	}
+	if cfg.Endpoints.APIBaseURL == "" {
+		cfg.Endpoints.APIBaseURL = sp.endpoints.APIBaseURL
+	}
+	cfg.Endpoints.APIBaseURL = strings.TrimRight(cfg.Endpoints.APIBaseURL, "/")
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = sp.scopes
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &Connector{
+		cfg:    cfg,
+		spec:   sp,
+		client: observability.WrapClient(&http.Client{Timeout: cfg.Timeout}),
+		nowFn:  func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+func (c *Connector) Provider() string { return c.cfg.Provider }
+
+func (c *Connector) AuthorizationURL(state, codeChallenge, redirectURI string) string {
+	q := url.Values{}
+	q.Set(c.spec.clientIDParam, c.cfg.ClientID)
+	q.Set("response_type", "code")
+	q.Set("redirect_uri", redirectURI)
+	q.Set("scope", strings.Join(c.cfg.Scopes, c.spec.scopeSeparator))
+	q.Set("state", state)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	for k, v := range c.spec.extraAuthParams {
+		q.Set(k, v)
+	}
+	sep := "?"
+	if strings.Contains(c.cfg.Endpoints.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.cfg.Endpoints.AuthURL + sep + q.Encode()
+}
+
+func (c *Connector) ExchangeCode(ctx context.Context, code, codeVerifier, redirectURI string) (domain.ProviderTokens, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("redirect_uri", redirectURI)
+	return c.token(ctx, form)
+}
+
+func (c *Connector) Refresh(ctx context.Context, refreshToken string) (domain.ProviderTokens, error) {
+	if refreshToken == "" {
+		return domain.ProviderTokens{}, fmt.Errorf("%w: no refresh token", domain.ErrProviderRejected)
+	}
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	tokens, err := c.token(ctx, form)
+	if err == nil && tokens.RefreshToken == "" {
+		// Providers that do not rotate refresh tokens omit them on refresh.
+		tokens.RefreshToken = refreshToken
+	}
+	return tokens, err
+}
+
+func (c *Connector) token(ctx context.Context, form url.Values) (domain.ProviderTokens, error) {
+	form.Set(c.spec.clientIDParam, c.cfg.ClientID)
+	if c.cfg.ClientSecret != "" {
+		form.Set("client_secret", c.cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoints.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.ProviderTokens{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	var out struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		Scope        string `json:"scope"`
+	}
+	if err := c.do(req, &out); err != nil {
+		return domain.ProviderTokens{}, err
+	}
+	if out.AccessToken == "" {
+		return domain.ProviderTokens{}, fmt.Errorf("%w: token response without access_token", domain.ErrProviderRejected)
+	}
+	tokens := domain.ProviderTokens{AccessToken: out.AccessToken, RefreshToken: out.RefreshToken, Scope: out.Scope}
+	if out.ExpiresIn > 0 {
+		exp := c.nowFn().Add(time.Duration(out.ExpiresIn) * time.Second)
+		tokens.ExpiresAt = &exp
+	}
+	return tokens, nil
+}
+
+func (c *Connector) FetchProfile(ctx context.Context, accessToken string) (domain.ProviderProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.Endpoints.APIBaseURL+c.spec.profilePath, nil)
+	if err != nil {
+		return domain.ProviderProfile{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	var raw json.RawMessage
+	if err := c.do(req, &raw); err != nil {
+		return domain.ProviderProfile{}, err
+	}
+	profile, err := c.spec.parseProfile(raw)
+	if err != nil {
+		return domain.ProviderProfile{}, fmt.Errorf("%w: %s profile: %v", domain.ErrProviderUnavailable, c.cfg.Provider, err)
+	}
+	if profile.ExternalID == "" || profile.Handle == "" {
+		return domain.ProviderProfile{}, fmt.Errorf("%w: %s profile without id or handle", domain.ErrProviderUnavailable, c.cfg.Provider)
+	}
+	return profile, nil
+}
+
+func (c *Connector) FetchPost(ctx context.Context, accessToken, postID string) (domain.ProviderPost, error) {
+	method, path, body := c.spec.postRequest(postID)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.cfg.Endpoints.APIBaseURL+path, reader)
+	if err != nil {
+		return domain.ProviderPost{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	var raw json.RawMessage
+	if err := c.do(req, &raw); err != nil {
+		return domain.ProviderPost{}, err
+	}
+	post, err := c.spec.parsePost(raw)
+	if err != nil {
+		return domain.ProviderPost{}, fmt.Errorf("%w: %s post: %v", domain.ErrProviderUnavailable, c.cfg.Provider, err)
+	}
+	if post.PostID == "" {
+		return domain.ProviderPost{}, fmt.Errorf("%w: %s post %s", domain.ErrNotFound, c.cfg.Provider, postID)
+	}
+	return post, nil
+}
+
+// do sends req and decodes a JSON body into dst. 400, 401 and 403 mean the
+// provider refused the grant or token and 404 an unknown object; anything
+// else non-2xx is treated as an outage.
+func (c *Connector) do(req *http.Request, dst any) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", domain.ErrProviderUnavailable, c.cfg.Provider, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", domain.ErrProviderUnavailable, c.cfg.Provider, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: %s: status %d: %s", domain.ErrProviderRejected, c.cfg.Provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s: status %d", domain.ErrNotFound, c.cfg.Provider, resp.StatusCode)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("%w: %s: status %d", domain.ErrProviderUnavailable, c.cfg.Provider, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("%w: %s: decode response: %v", domain.ErrProviderUnavailable, c.cfg.Provider, err)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MockAccount is a provider account served by MockServer.
+type MockAccount struct {
+	ExternalID string
+	Handle     string
+	Followers  int
+}
+
+// MockPost is a post owned by a MockAccount.
+type MockPost struct {
+	ID              string
+	AccountID       string
+	Caption         string
+	DurationSeconds int
+	BrandedContent  bool
+	PostedAt        time.Time
+}
+
+// MockServer is a local OAuth 2.0 + API server that speaks every supported
+// provider's profile format. The authorize endpoint approves immediately for
+// the account named by login_hint (or the first account), so the full
+// redirect flow runs without a browser. Point all provider endpoints at it
+// with MockEndpoints.
+type MockServer struct {
+	// TokenTTLSeconds is the expires_in of issued access tokens.
+	TokenTTLSeconds int
+
+	mu       sync.Mutex
+	accounts map[string]*MockAccount
+	order    []string
+	codes    map[string]mockGrant
+	access   map[string]string
+	refresh  map[string]string
+	posts    map[string]MockPost
+}
+
+type mockGrant struct {
+	accountID     string
+	codeChallenge string
+	redirectURI   string
+}
+
+func NewMockServer() *MockServer {
+	return &MockServer{
+		TokenTTLSeconds: 3600,
+		accounts:        map[string]*MockAccount{},
+		codes:           map[string]mockGrant{},
+		access:          map[string]string{},
+		refresh:         map[string]string{},
+		posts:           map[string]MockPost{},
+	}
+}
+
+// MockEndpoints returns the endpoints of a MockServer listening at baseURL.
+func MockEndpoints(baseURL string) Endpoints {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return Endpoints{AuthURL: baseURL + "/oauth/authorize", TokenURL: baseURL + "/oauth/token", APIBaseURL: baseURL}
+}
+
+func (m *MockServer) AddAccount(a MockAccount) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.accounts[a.ExternalID]; !ok {
+		m.order = append(m.order, a.ExternalID)
+	}
+	m.accounts[a.ExternalID] = &a
+}
+
+// AddPost publishes p on its account.
+func (m *MockServer) AddPost(p MockPost) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.posts[p.ID] = p
+}
+
+// SetFollowers changes the follower count reported for an account.
+func (m *MockServer) SetFollowers(externalID string, followers int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a, ok := m.accounts[externalID]; ok {
+		a.Followers = followers
+	}
+}
+
+// ExpireAccessTokens invalidates every access token so callers must refresh.
+func (m *MockServer) ExpireAccessTokens() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.access = map[string]string{}
+}
+
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/oauth/authorize":
+		m.authorize(w, r)
+	case "/oauth/token":
+		m.token(w, r)
+	case "/me", "/v2/user/info/", "/youtube/v3/channels", "/2/users/me":
+		m.profile(w, r)
+	case "/v2/video/query/", "/youtube/v3/videos":
+		m.post(w, r)
+	default:
+		if strings.HasPrefix(r.URL.Path, "/2/tweets/") || strings.Count(r.URL.Path, "/") == 1 {
+			m.post(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}
+}
+
+func (m *MockServer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI, state, challenge := q.Get("redirect_uri"), q.Get("state"), q.Get("code_challenge")
+	if redirectURI == "" || state == "" || challenge == "" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	accountID := q.Get("login_hint")
+	if accountID == "" && len(m.order) > 0 {
+		accountID = m.order[0]
+	}
+	if _, ok := m.accounts[accountID]; !ok {
+		m.mu.Unlock()
+		http.Error(w, "access_denied", http.StatusBadRequest)
+		return
+	}
+	code := "code-" + uuid.NewString()
+	m.codes[code] = mockGrant{accountID: accountID, codeChallenge: challenge, redirectURI: redirectURI}
+	m.mu.Unlock()
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	rq := target.Query()
+	rq.Set("code", code)
+	rq.Set("state", state)
+	target.RawQuery = rq.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (m *MockServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request")
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var accountID string
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		grant, ok := m.codes[r.PostForm.Get("code")]
+		delete(m.codes, r.PostForm.Get("code"))
+		if !ok || grant.redirectURI != r.PostForm.Get("redirect_uri") {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != grant.codeChallenge {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		accountID = grant.accountID
+	case "refresh_token":
+		id, ok := m.refresh[r.PostForm.Get("refresh_token")]
+		delete(m.refresh, r.PostForm.Get("refresh_token"))
+		if !ok {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		accountID = id
+	default:
+		writeOAuthError(w, "unsupported_grant_type")
+		return
+	}
+	access, refresh := "at-"+uuid.NewString(), "rt-"+uuid.NewString()
+	m.access[access] = accountID
+	m.refresh[refresh] = accountID
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"access_token": access, "refresh_token": refresh, "token_type": "Bearer", "expires_in": m.TokenTTLSeconds})
+}
+
+// account resolves the bearer token of r; callers hold m.mu.
+func (m *MockServer) account(r *http.Request) (*MockAccount, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	a, ok := m.accounts[m.access[token]]
+	return a, ok
+}
+
+func (m *MockServer) profile(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	a, ok := m.account(r)
+	var body any
+	if ok {
+		switch r.URL.Path {
+		case "/me":
+			body = map[string]any{"user_id": a.ExternalID, "username": a.Handle, "followers_count": a.Followers}
+		case "/v2/user/info/":
+			body = map[string]any{"data": map[string]any{"user": map[string]any{"open_id": a.ExternalID, "username": a.Handle, "follower_count": a.Followers}}}
+		case "/youtube/v3/channels":
+			body = map[string]any{"items": []any{map[string]any{"id": a.ExternalID, "snippet": map[string]any{"customUrl": a.Handle}, "statistics": map[string]any{"subscriberCount": strconv.Itoa(a.Followers)}}}}
+		case "/2/users/me":
+			body = map[string]any{"data": map[string]any{"id": a.ExternalID, "username": a.Handle, "public_metrics": map[string]any{"followers_count": a.Followers}}}
+		}
+	}
+	m.mu.Unlock()
+	if !ok {
+		http.Error(w, `{"error":"invalid_token"}`, http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// post serves a post in the shape of the provider owning the route. TikTok
+// only lists videos of the token owner; the others return any post.
+func (m *MockServer) post(w http.ResponseWriter, r *http.Request) {
+	var postID string
+	switch {
+	case r.URL.Path == "/v2/video/query/":
+		var req struct {
+			Filters struct {
+				VideoIDs []string `json:"video_ids"`
+			} `json:"filters"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Filters.VideoIDs) > 0 {
+			postID = req.Filters.VideoIDs[0]
+		}
+	case r.URL.Path == "/youtube/v3/videos":
+		postID = r.URL.Query().Get("id")
+	default:
+		postID = path.Base(r.URL.Path)
+	}
+	m.mu.Lock()
+	caller, ok := m.account(r)
+	p, found := m.posts[postID]
+	author := m.accounts[p.AccountID]
+	m.mu.Unlock()
+	if !ok {
+		http.Error(w, `{"error":"invalid_token"}`, http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path == "/v2/video/query/" {
+		videos := []any{}
+		if found && p.AccountID == caller.ExternalID {
+			videos = append(videos, map[string]any{"id": p.ID, "create_time": p.PostedAt.Unix(), "video_description": p.Caption, "duration": p.DurationSeconds})
+		}
+		writeMockJSON(w, map[string]any{"data": map[string]any{"videos": videos}})
+		return
+	}
+	if !found || author == nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case r.URL.Path == "/youtube/v3/videos":
+		writeMockJSON(w, map[string]any{"items": []any{map[string]any{
+			"id":                          p.ID,
+			"snippet":                     map[string]any{"channelId": author.ExternalID, "title": p.Caption, "publishedAt": p.PostedAt.UTC().Format(time.RFC3339)},
+			"contentDetails":              map[string]any{"duration": fmt.Sprintf("PT%dS", p.DurationSeconds)},
+			"paidProductPlacementDetails": map[string]any{"hasPaidProductPlacement": p.BrandedContent},
+		}}})
+	case strings.HasPrefix(r.URL.Path, "/2/tweets/"):
+		writeMockJSON(w, map[string]any{
+			"data":     map[string]any{"id": p.ID, "text": p.Caption, "author_id": author.ExternalID, "created_at": p.PostedAt.UTC().Format(time.RFC3339)},
+			"includes": map[string]any{"users": []any{map[string]any{"id": author.ExternalID, "username": author.Handle}}, "media": []any{map[string]any{"duration_ms": p.DurationSeconds * 1000}}},
+		})
+	default:
+		writeMockJSON(w, map[string]any{"id": p.ID, "caption": p.Caption, "timestamp": p.PostedAt.UTC().Format("2006-01-02T15:04:05-0700"), "username": author.Handle, "owner": map[string]any{"id": author.ExternalID}})
+	}
+}
+
+func writeMockJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeOAuthError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
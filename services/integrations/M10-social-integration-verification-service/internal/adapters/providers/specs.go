@@ -0,0 +1,307 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+)
+
+// spec captures what differs between providers.
+type spec struct {
+	endpoints       Endpoints
+	scopes          []string
+	scopeSeparator  string
+	clientIDParam   string
+	extraAuthParams map[string]string
+	profilePath     string
+	parseProfile    func(json.RawMessage) (domain.ProviderProfile, error)
+	postRequest     func(postID string) (method, path string, body []byte)
+	parsePost       func(json.RawMessage) (domain.ProviderPost, error)
+}
+
+var specs = map[string]spec{
+	"instagram": {
+		endpoints: Endpoints{
+			AuthURL:    "https://www.instagram.com/oauth/authorize",
+			TokenURL:   "https://api.instagram.com/oauth/access_token",
+			APIBaseURL: "https://graph.instagram.com",
+		},
+		scopes:         []string{"instagram_business_basic"},
+		scopeSeparator: ",",
+		clientIDParam:  "client_id",
+		profilePath:    "/me?fields=user_id,username,name,followers_count",
+		parseProfile: func(raw json.RawMessage) (domain.ProviderProfile, error) {
+			var out struct {
+				UserID         string `json:"user_id"`
+				ID             string `json:"id"`
+				Username       string `json:"username"`
+				Name           string `json:"name"`
+				FollowersCount int    `json:"followers_count"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderProfile{}, err
+			}
+			id := out.UserID
+			if id == "" {
+				id = out.ID
+			}
+			return domain.ProviderProfile{ExternalID: id, Handle: out.Username, DisplayName: out.Name, FollowerCount: out.FollowersCount}, nil
+		},
+		postRequest: func(postID string) (string, string, []byte) {
+			return http.MethodGet, "/" + url.PathEscape(postID) + "?fields=id,caption,timestamp,username,owner,permalink", nil
+		},
+		parsePost: func(raw json.RawMessage) (domain.ProviderPost, error) {
+			var out struct {
+				ID        string `json:"id"`
+				Caption   string `json:"caption"`
+				Timestamp string `json:"timestamp"`
+				Username  string `json:"username"`
+				Owner     struct {
+					ID string `json:"id"`
+				} `json:"owner"`
+				Permalink string `json:"permalink"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderPost{}, err
+			}
+			// Graph API timestamps use a colon-less offset (+0000).
+			postedAt, err := time.Parse("2006-01-02T15:04:05-0700", out.Timestamp)
+			if err != nil {
+				postedAt, _ = time.Parse(time.RFC3339, out.Timestamp)
+			}
+			return domain.ProviderPost{PostID: out.ID, AuthorExternalID: out.Owner.ID, AuthorHandle: out.Username, Caption: out.Caption, PostedAt: postedAt, URL: out.Permalink}, nil
+		},
+	},
+	"tiktok": {
+		endpoints: Endpoints{
+			AuthURL:    "https://www.tiktok.com/v2/auth/authorize/",
+			TokenURL:   "https://open.tiktokapis.com/v2/oauth/token/",
+			APIBaseURL: "https://open.tiktokapis.com",
+		},
+		scopes:         []string{"user.info.basic", "user.info.profile", "user.info.stats", "video.list"},
+		scopeSeparator: ",",
+		clientIDParam:  "client_key",
+		profilePath:    "/v2/user/info/?fields=open_id,username,display_name,follower_count",
+		parseProfile: func(raw json.RawMessage) (domain.ProviderProfile, error) {
+			var out struct {
+				Data struct {
+					User struct {
+						OpenID        string `json:"open_id"`
+						Username      string `json:"username"`
+						DisplayName   string `json:"display_name"`
+						FollowerCount int    `json:"follower_count"`
+					} `json:"user"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderProfile{}, err
+			}
+			u := out.Data.User
+			return domain.ProviderProfile{ExternalID: u.OpenID, Handle: u.Username, DisplayName: u.DisplayName, FollowerCount: u.FollowerCount}, nil
+		},
+		// video/query only returns videos of the token owner.
+		postRequest: func(postID string) (string, string, []byte) {
+			body, _ := json.Marshal(map[string]any{"filters": map[string]any{"video_ids": []string{postID}}})
+			return http.MethodPost, "/v2/video/query/?fields=id,create_time,video_description,duration,share_url", body
+		},
+		parsePost: func(raw json.RawMessage) (domain.ProviderPost, error) {
+			var out struct {
+				Data struct {
+					Videos []struct {
+						ID               string `json:"id"`
+						CreateTime       int64  `json:"create_time"`
+						VideoDescription string `json:"video_description"`
+						Duration         int    `json:"duration"`
+						ShareURL         string `json:"share_url"`
+					} `json:"videos"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderPost{}, err
+			}
+			if len(out.Data.Videos) == 0 {
+				return domain.ProviderPost{}, nil
+			}
+			v := out.Data.Videos[0]
+			return domain.ProviderPost{PostID: v.ID, OwnerScoped: true, Caption: v.VideoDescription, Duration: time.Duration(v.Duration) * time.Second, PostedAt: time.Unix(v.CreateTime, 0).UTC(), URL: v.ShareURL}, nil
+		},
+	},
+	"youtube": {
+		endpoints: Endpoints{
+			AuthURL:    "https://accounts.google.com/o/oauth2/v2/auth",
+			TokenURL:   "https://oauth2.googleapis.com/token",
+			APIBaseURL: "https://www.googleapis.com",
+		},
+		scopes:          []string{"https://www.googleapis.com/auth/youtube.readonly"},
+		scopeSeparator:  " ",
+		clientIDParam:   "client_id",
+		extraAuthParams: map[string]string{"access_type": "offline", "prompt": "consent"},
+		profilePath:     "/youtube/v3/channels?part=snippet,statistics&mine=true",
+		parseProfile: func(raw json.RawMessage) (domain.ProviderProfile, error) {
+			var out struct {
+				Items []struct {
+					ID      string `json:"id"`
+					Snippet struct {
+						Title     string `json:"title"`
+						CustomURL string `json:"customUrl"`
+					} `json:"snippet"`
+					Statistics struct {
+						SubscriberCount string `json:"subscriberCount"`
+					} `json:"statistics"`
+				} `json:"items"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderProfile{}, err
+			}
+			if len(out.Items) == 0 {
+				return domain.ProviderProfile{}, errors.New("no channel for this account")
+			}
+			ch := out.Items[0]
+			subscribers, _ := strconv.Atoi(ch.Statistics.SubscriberCount)
+			return domain.ProviderProfile{ExternalID: ch.ID, Handle: ch.Snippet.CustomURL, DisplayName: ch.Snippet.Title, FollowerCount: subscribers}, nil
+		},
+		postRequest: func(postID string) (string, string, []byte) {
+			return http.MethodGet, "/youtube/v3/videos?part=snippet,contentDetails,paidProductPlacementDetails&id=" + url.QueryEscape(postID), nil
+		},
+		parsePost: func(raw json.RawMessage) (domain.ProviderPost, error) {
+			var out struct {
+				Items []struct {
+					ID      string `json:"id"`
+					Snippet struct {
+						ChannelID   string    `json:"channelId"`
+						Title       string    `json:"title"`
+						Description string    `json:"description"`
+						PublishedAt time.Time `json:"publishedAt"`
+					} `json:"snippet"`
+					ContentDetails struct {
+						Duration string `json:"duration"`
+					} `json:"contentDetails"`
+					PaidProductPlacementDetails struct {
+						HasPaidProductPlacement bool `json:"hasPaidProductPlacement"`
+					} `json:"paidProductPlacementDetails"`
+				} `json:"items"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderPost{}, err
+			}
+			if len(out.Items) == 0 {
+				return domain.ProviderPost{}, nil
+			}
+			v := out.Items[0]
+			return domain.ProviderPost{
+				PostID:           v.ID,
+				AuthorExternalID: v.Snippet.ChannelID,
+				Caption:          v.Snippet.Title + "\n" + v.Snippet.Description,
+				Duration:         parseISODuration(v.ContentDetails.Duration),
+				BrandedContent:   v.PaidProductPlacementDetails.HasPaidProductPlacement,
+				PostedAt:         v.Snippet.PublishedAt,
+				URL:              "https://www.youtube.com/watch?v=" + v.ID,
+			}, nil
+		},
+	},
+	"twitter": {
+		endpoints: Endpoints{
+			AuthURL:    "https://x.com/i/oauth2/authorize",
+			TokenURL:   "https://api.x.com/2/oauth2/token",
+			APIBaseURL: "https://api.x.com",
+		},
+		scopes:         []string{"tweet.read", "users.read", "offline.access"},
+		scopeSeparator: " ",
+		clientIDParam:  "client_id",
+		profilePath:    "/2/users/me?user.fields=public_metrics",
+		parseProfile: func(raw json.RawMessage) (domain.ProviderProfile, error) {
+			var out struct {
+				Data struct {
+					ID            string `json:"id"`
+					Username      string `json:"username"`
+					Name          string `json:"name"`
+					PublicMetrics struct {
+						FollowersCount int `json:"followers_count"`
+					} `json:"public_metrics"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderProfile{}, err
+			}
+			return domain.ProviderProfile{ExternalID: out.Data.ID, Handle: out.Data.Username, DisplayName: out.Data.Name, FollowerCount: out.Data.PublicMetrics.FollowersCount}, nil
+		},
+		postRequest: func(postID string) (string, string, []byte) {
+			return http.MethodGet, "/2/tweets/" + url.PathEscape(postID) + "?tweet.fields=created_at,author_id,entities&expansions=author_id,attachments.media_keys&user.fields=username&media.fields=duration_ms", nil
+		},
+		parsePost: func(raw json.RawMessage) (domain.ProviderPost, error) {
+			var out struct {
+				Data struct {
+					ID        string    `json:"id"`
+					Text      string    `json:"text"`
+					AuthorID  string    `json:"author_id"`
+					CreatedAt time.Time `json:"created_at"`
+					Entities  struct {
+						Hashtags []struct {
+							Tag string `json:"tag"`
+						} `json:"hashtags"`
+						Mentions []struct {
+							Username string `json:"username"`
+						} `json:"mentions"`
+					} `json:"entities"`
+				} `json:"data"`
+				Includes struct {
+					Users []struct {
+						ID       string `json:"id"`
+						Username string `json:"username"`
+					} `json:"users"`
+					Media []struct {
+						DurationMS int64 `json:"duration_ms"`
+					} `json:"media"`
+				} `json:"includes"`
+			}
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return domain.ProviderPost{}, err
+			}
+			post := domain.ProviderPost{PostID: out.Data.ID, AuthorExternalID: out.Data.AuthorID, Caption: out.Data.Text, PostedAt: out.Data.CreatedAt}
+			for _, h := range out.Data.Entities.Hashtags {
+				post.Hashtags = append(post.Hashtags, h.Tag)
+			}
+			for _, m := range out.Data.Entities.Mentions {
+				post.Mentions = append(post.Mentions, m.Username)
+			}
+			for _, u := range out.Includes.Users {
+				if u.ID == out.Data.AuthorID {
+					post.AuthorHandle = u.Username
+					post.URL = "https://x.com/" + u.Username + "/status/" + out.Data.ID
+				}
+			}
+			for _, m := range out.Includes.Media {
+				if d := time.Duration(m.DurationMS) * time.Millisecond; d > post.Duration {
+					post.Duration = d
+				}
+			}
+			return post, nil
+		},
+	},
+}
+
+// SupportedProviders lists the providers NewConnector accepts.
+func SupportedProviders() []string { return []string{"instagram", "tiktok", "youtube", "twitter"} }
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODuration reads the ISO 8601 durations YouTube reports
+// ("PT1M30S"); anything else is zero.
+func parseISODuration(v string) time.Duration {
+	m := isoDurationPattern.FindStringSubmatch(v)
+	if m == nil {
+		return 0
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		n, _ := strconv.Atoi(m[i+1])
+		d += time.Duration(n) * unit
+	}
+	return d
+}
@@ -0,0 +1,69 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const tokenCipherPrefix = "v1."
+
+// AESGCMTokenCipher encrypts provider tokens with AES-256-GCM under a key
+// derived from the configured secret. Each value gets a random nonce.
+type AESGCMTokenCipher struct {
+	aead cipher.AEAD
+}
+
+func NewAESGCMTokenCipher(secret string) (*AESGCMTokenCipher, error) {
+	if strings.TrimSpace(secret) == "" {
+		return nil, errors.New("token encryption secret is required")
+	}
+	key := sha256.Sum256([]byte("m10-provider-tokens:" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMTokenCipher{aead: aead}, nil
+}
+
+func (c *AESGCMTokenCipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return tokenCipherPrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (c *AESGCMTokenCipher) Decrypt(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(ciphertext, tokenCipherPrefix) {
+		return "", errors.New("unsupported token ciphertext version")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(ciphertext, tokenCipherPrefix))
+	if err != nil {
+		return "", fmt.Errorf("decode token ciphertext: %w", err)
+	}
+	if len(raw) < c.aead.NonceSize() {
+		return "", errors.New("token ciphertext too short")
+	}
+	plain, err := c.aead.Open(nil, raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -17,6 +18,21 @@ type Config struct {
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
 	OutboxFlushBatchSize int
+
+	// OAuthRedirectURL is the frontend callback registered with every
+	// provider; "{provider}" is replaced per connection.
+	OAuthRedirectURL     string
+	ProviderMockBaseURL  string
+	Providers            map[string]ProviderCredentials
+	TokenEncryptionKey   string
+	FollowerSyncInterval time.Duration
+}
+
+// ProviderCredentials are the OAuth application of one provider. Secrets
+// are only read from the environment.
+type ProviderCredentials struct {
+	ClientID     string
+	ClientSecret string
 }
 
 type configFile struct {
@@ -25,10 +41,18 @@ type configFile struct {
 		HTTPPort int    `yaml:"http_port"`
 		GRPCPort int    `yaml:"grpc_port"`
 	} `yaml:"service"`
+	Providers struct {
+		RedirectURL                 string `yaml:"redirect_url"`
+		MockBaseURL                 string `yaml:"mock_base_url"`
+		FollowerSyncIntervalMinutes int    `yaml:"follower_sync_interval_minutes"`
+		Clients                     map[string]struct {
+			ClientID string `yaml:"client_id"`
+		} `yaml:"clients"`
+	} `yaml:"providers"`
 }
 
 func LoadConfig(path string) (Config, error) {
-	cfg := Config{ServiceID: "M10-Social-Integration-Verification-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second, OutboxFlushBatchSize: 100}
+	cfg := Config{ServiceID: "M10-Social-Integration-Verification-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second, OutboxFlushBatchSize: 100, Providers: map[string]ProviderCredentials{}, FollowerSyncInterval: 6 * time.Hour}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
 		if err := yaml.Unmarshal(raw, &f); err != nil {
@@ -37,6 +61,14 @@ func LoadConfig(path string) (Config, error) {
 		if f.Service.ID != "" { cfg.ServiceID = f.Service.ID }
 		if f.Service.HTTPPort > 0 { cfg.HTTPPort = f.Service.HTTPPort }
 		if f.Service.GRPCPort > 0 { cfg.GRPCPort = f.Service.GRPCPort }
+		cfg.OAuthRedirectURL = f.Providers.RedirectURL
+		cfg.ProviderMockBaseURL = f.Providers.MockBaseURL
+		if f.Providers.FollowerSyncIntervalMinutes > 0 {
+			cfg.FollowerSyncInterval = time.Duration(f.Providers.FollowerSyncIntervalMinutes) * time.Minute
+		}
+		for name, c := range f.Providers.Clients {
+			cfg.Providers[name] = ProviderCredentials{ClientID: c.ClientID}
+		}
 	}
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
@@ -44,6 +76,25 @@ func LoadConfig(path string) (Config, error) {
 	cfg.EventDedupTTL = time.Duration(envInt("EVENT_DEDUP_TTL_HOURS", int(cfg.EventDedupTTL.Hours()))) * time.Hour
 	cfg.ConsumerPollInterval = time.Duration(envInt("CONSUMER_POLL_SECONDS", int(cfg.ConsumerPollInterval.Seconds()))) * time.Second
 	cfg.OutboxFlushBatchSize = envInt("OUTBOX_FLUSH_BATCH_SIZE", cfg.OutboxFlushBatchSize)
+	cfg.OAuthRedirectURL = envString("SOCIAL_OAUTH_REDIRECT_URL", cfg.OAuthRedirectURL)
+	cfg.ProviderMockBaseURL = envString("SOCIAL_PROVIDER_MOCK_URL", cfg.ProviderMockBaseURL)
+	cfg.TokenEncryptionKey = os.Getenv("SOCIAL_TOKEN_ENCRYPTION_KEY")
+	cfg.FollowerSyncInterval = time.Duration(envInt("FOLLOWER_SYNC_INTERVAL_MINUTES", int(cfg.FollowerSyncInterval.Minutes()))) * time.Minute
+	for _, name := range []string{"instagram", "tiktok", "youtube", "twitter"} {
+		prefix := strings.ToUpper(name)
+		creds := cfg.Providers[name]
+		creds.ClientID = envString(prefix+"_CLIENT_ID", creds.ClientID)
+		creds.ClientSecret = os.Getenv(prefix + "_CLIENT_SECRET")
+		if creds.ClientID != "" {
+			cfg.Providers[name] = creds
+		}
+	}
 	return cfg, nil
 }
 func envInt(name string, fallback int) int { if raw := os.Getenv(name); raw != "" { if v, err := strconv.Atoi(raw); err == nil { return v } }; return fallback }
+func envString(name, fallback string) string {
+	if raw := strings.TrimSpace(os.Getenv(name)); raw != "" {
+		return raw
+	}
+	return fallback
+}
@@ -12,51 +12,100 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/providers"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/security"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
 	"google.golang.org/grpc"
 )
 
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil { return nil, err }
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	domainPub := eventadapter.NewMemoryDomainPublisher()
 	analyticsPub := eventadapter.NewMemoryAnalyticsPublisher()
 	dlqPub := eventadapter.NewLoggingDLQPublisher()
+	connectors, err := newConnectors(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var cipher ports.TokenCipher
+	if len(connectors) > 0 {
+		c, err := security.NewAESGCMTokenCipher(cfg.TokenEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("SOCIAL_TOKEN_ENCRYPTION_KEY: %w", err)
+		}
+		cipher = c
+	} else {
+		logger.Warn("no social providers configured; connect and follower sync are disabled")
+	}
 	svc := application.NewService(application.Dependencies{
-		Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval, OutboxFlushBatchSize: cfg.OutboxFlushBatchSize},
+		Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval, OutboxFlushBatchSize: cfg.OutboxFlushBatchSize, RedirectURL: cfg.OAuthRedirectURL, FollowerSyncInterval: cfg.FollowerSyncInterval},
 		Accounts: repos.Accounts, Metrics: repos.Metrics, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox,
+		OAuthStates: repos.OAuthStates, Campaigns: repos.Campaigns, Connectors: connectors, TokenCipher: cipher,
 		DomainEvents: domainPub, Analytics: analyticsPub, DLQ: dlqPub,
 	})
 	handler := httpadapter.NewHandler(svc)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewSocialIntegrationVerificationInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil { return nil, err }
 	worker := eventadapter.NewWorker(logger, nil, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+}
+
+// newConnectors builds a connector per provider with a client id. With a
+// mock base URL every provider is served by that mock instead.
+func newConnectors(cfg Config) ([]ports.ProviderConnector, error) {
+	out := make([]ports.ProviderConnector, 0, len(cfg.Providers))
+	for _, name := range providers.SupportedProviders() {
+		creds, ok := cfg.Providers[name]
+		pc := providers.Config{Provider: name, ClientID: creds.ClientID, ClientSecret: creds.ClientSecret}
+		if cfg.ProviderMockBaseURL != "" {
+			pc.Endpoints = providers.MockEndpoints(cfg.ProviderMockBaseURL)
+			if pc.ClientID == "" {
+				pc.ClientID = "mesh-dev"
+			}
+		} else if !ok || creds.ClientID == "" {
+			continue
+		}
+		c, err := providers.NewConnector(pc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
 	go func() { if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) { errCh <- err } }()
 	go func() { if err := r.grpcServer.Serve(r.grpcLis); err != nil { errCh <- err } }()
@@ -71,7 +120,16 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() { if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) { errCh <- err } }()
 	select { case <-ctx.Done(): return nil; case err := <-errCh: return err }
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
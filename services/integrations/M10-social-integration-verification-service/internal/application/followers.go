@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
+)
+
+// tokenRefreshSkew refreshes access tokens slightly before they expire.
+const tokenRefreshSkew = time.Minute
+
+// RecordFollowersSync syncs one account now. The follower count always
+// comes from the provider.
+func (s *Service) RecordFollowersSync(ctx context.Context, actor Actor, input RecordFollowersSyncInput) (domain.SocialMetric, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.SocialMetric{}, domain.ErrUnauthorized
+	}
+	input.SocialAccountID = strings.TrimSpace(input.SocialAccountID)
+	if input.SocialAccountID == "" {
+		return domain.SocialMetric{}, domain.ErrInvalidInput
+	}
+	acc, err := s.accounts.GetByID(ctx, input.SocialAccountID)
+	if err != nil {
+		return domain.SocialMetric{}, err
+	}
+	if !canActForUser(actor, acc.UserID) {
+		return domain.SocialMetric{}, domain.ErrForbidden
+	}
+	if acc.Status != domain.AccountStatusActive {
+		return domain.SocialMetric{}, domain.ErrConflict
+	}
+	return s.syncFollowers(ctx, acc, actor.RequestID)
+}
+
+// SyncDueFollowers syncs the active accounts whose last sync is older than
+// FollowerSyncInterval and returns how many succeeded. Provider failures
+// are skipped until the next interval; the first one is returned after the
+// batch completes.
+func (s *Service) SyncDueFollowers(ctx context.Context) (int, error) {
+	if len(s.connectors) == 0 {
+		return 0, nil
+	}
+	due, err := s.accounts.ListDueForSync(ctx, s.nowFn().Add(-s.cfg.FollowerSyncInterval), s.cfg.FollowerSyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	synced := 0
+	var firstErr error
+	for _, acc := range due {
+		if _, err := s.syncFollowers(ctx, acc, ""); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		synced++
+	}
+	return synced, firstErr
+}
+
+func (s *Service) syncFollowers(ctx context.Context, acc domain.SocialAccount, traceID string) (domain.SocialMetric, error) {
+	var profile domain.ProviderProfile
+	err := s.withAccessToken(ctx, &acc, traceID, func(connector ports.ProviderConnector, token string) error {
+		var err error
+		profile, err = connector.FetchProfile(ctx, token)
+		return err
+	})
+	now := s.nowFn()
+	acc.LastSyncedAt = &now
+	if err != nil {
+		if !errors.Is(err, domain.ErrProviderRejected) {
+			_ = s.accounts.Update(ctx, acc)
+		}
+		return domain.SocialMetric{}, err
+	}
+	if profile.ExternalID != acc.ExternalID {
+		return domain.SocialMetric{}, domain.ErrAccountClaimed
+	}
+	// Handles can be renamed on the provider; the stored one follows.
+	acc.Handle = profile.Handle
+	acc.UpdatedAt = now
+	if err := s.accounts.Update(ctx, acc); err != nil {
+		return domain.SocialMetric{}, err
+	}
+	row := domain.SocialMetric{MetricID: uuid.NewString(), SocialAccountID: acc.SocialAccountID, UserID: acc.UserID, Provider: acc.Provider, FollowerCount: profile.FollowerCount, SyncedAt: now}
+	if err := s.metrics.Append(ctx, row); err != nil {
+		return domain.SocialMetric{}, err
+	}
+	if err := s.enqueueFollowersSynced(ctx, row, traceID, now); err != nil {
+		return domain.SocialMetric{}, err
+	}
+	return row, nil
+}
@@ -0,0 +1,250 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
+)
+
+// ConnectStart begins an authorization-code+PKCE flow. The code verifier
+// stays server-side with the state; only the S256 challenge reaches the
+// provider.
+func (s *Service) ConnectStart(ctx context.Context, actor Actor, input ConnectInput) (ConnectResult, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return ConnectResult{}, domain.ErrUnauthorized
+	}
+	input.Provider = normalizeProvider(input.Provider)
+	input.UserID = strings.TrimSpace(input.UserID)
+	if input.UserID == "" || input.Provider == "" || !domain.IsValidProvider(input.Provider) {
+		return ConnectResult{}, domain.ErrInvalidInput
+	}
+	if !canActForUser(actor, input.UserID) {
+		return ConnectResult{}, domain.ErrForbidden
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return ConnectResult{}, domain.ErrIdempotencyRequired
+	}
+	connector, err := s.connector(input.Provider)
+	if err != nil {
+		return ConnectResult{}, err
+	}
+	requestHash := hashJSON(map[string]string{"op": "connect", "user_id": input.UserID, "provider": input.Provider})
+	if cached, ok, err := s.getIdempotentBody(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return ConnectResult{}, err
+	} else if ok {
+		var out ConnectResult
+		if err := json.Unmarshal(cached, &out); err == nil {
+			return out, nil
+		}
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return ConnectResult{}, err
+	}
+	verifier, challenge, err := newPKCE()
+	if err != nil {
+		return ConnectResult{}, err
+	}
+	now := s.nowFn()
+	state := uuid.NewString()
+	redirectURI := strings.ReplaceAll(s.cfg.RedirectURL, "{provider}", input.Provider)
+	if err := s.oauthStates.Put(ctx, domain.OAuthState{
+		State:        state,
+		UserID:       input.UserID,
+		Provider:     input.Provider,
+		RedirectURI:  redirectURI,
+		CodeVerifier: verifier,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.cfg.OAuthStateTTL),
+	}); err != nil {
+		return ConnectResult{}, err
+	}
+	res := ConnectResult{AuthURL: connector.AuthorizationURL(state, challenge, redirectURI), State: state}
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, res)
+	return res, nil
+}
+
+// OAuthCallback redeems the authorization code of a pending state, reads
+// the account identity from the provider and stores the encrypted tokens.
+// The handle is always the provider's; a provider account already active
+// for another user cannot be claimed.
+func (s *Service) OAuthCallback(ctx context.Context, actor Actor, input CallbackInput) (domain.SocialAccount, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.SocialAccount{}, domain.ErrUnauthorized
+	}
+	input.Provider = normalizeProvider(input.Provider)
+	input.UserID = strings.TrimSpace(input.UserID)
+	input.Code = strings.TrimSpace(input.Code)
+	input.State = strings.TrimSpace(input.State)
+	if input.UserID == "" || input.Provider == "" || input.Code == "" || input.State == "" || !domain.IsValidProvider(input.Provider) {
+		return domain.SocialAccount{}, domain.ErrInvalidInput
+	}
+	if !canActForUser(actor, input.UserID) {
+		return domain.SocialAccount{}, domain.ErrForbidden
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.SocialAccount{}, domain.ErrIdempotencyRequired
+	}
+	connector, err := s.connector(input.Provider)
+	if err != nil {
+		return domain.SocialAccount{}, err
+	}
+	requestHash := hashJSON(input)
+	if cached, ok, err := s.getIdempotentBody(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.SocialAccount{}, err
+	} else if ok {
+		var out domain.SocialAccount
+		if err := json.Unmarshal(cached, &out); err == nil {
+			return out, nil
+		}
+	}
+	now := s.nowFn()
+	pending, err := s.oauthStates.Take(ctx, input.State, now)
+	if err != nil || pending.Provider != input.Provider || pending.UserID != input.UserID {
+		return domain.SocialAccount{}, domain.ErrInvalidOAuthState
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.SocialAccount{}, err
+	}
+	tokens, err := connector.ExchangeCode(ctx, input.Code, pending.CodeVerifier, pending.RedirectURI)
+	if err != nil {
+		return domain.SocialAccount{}, err
+	}
+	profile, err := connector.FetchProfile(ctx, tokens.AccessToken)
+	if err != nil {
+		return domain.SocialAccount{}, err
+	}
+	if other, err := s.accounts.GetByProviderExternalID(ctx, input.Provider, profile.ExternalID); err == nil && other.UserID != input.UserID && other.Status == domain.AccountStatusActive {
+		return domain.SocialAccount{}, domain.ErrAccountClaimed
+	}
+	acc, err := s.accounts.GetByUserProvider(ctx, input.UserID, input.Provider)
+	exists := err == nil
+	if !exists {
+		acc = domain.SocialAccount{SocialAccountID: uuid.NewString(), UserID: input.UserID, Provider: input.Provider, ConnectedAt: now}
+	}
+	acc.ExternalID = profile.ExternalID
+	acc.Handle = profile.Handle
+	acc.Status = domain.AccountStatusActive
+	acc.DisconnectedAt = nil
+	if err := s.storeTokens(&acc, tokens); err != nil {
+		return domain.SocialAccount{}, err
+	}
+	if acc.ConnectedAt.IsZero() {
+		acc.ConnectedAt = now
+	}
+	acc.UpdatedAt = now
+	if exists {
+		if err := s.accounts.Update(ctx, acc); err != nil {
+			return domain.SocialAccount{}, err
+		}
+	} else {
+		if err := s.accounts.Create(ctx, acc); err != nil {
+			return domain.SocialAccount{}, err
+		}
+	}
+	if err := s.enqueueSocialAccountConnected(ctx, acc, actor.RequestID, now); err != nil {
+		return domain.SocialAccount{}, err
+	}
+	if err := s.enqueueSocialStatusChanged(ctx, acc.UserID, acc.Provider, acc.Status, actor.RequestID, now); err != nil {
+		return domain.SocialAccount{}, err
+	}
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, acc)
+	return acc, nil
+}
+
+func (s *Service) connector(provider string) (ports.ProviderConnector, error) {
+	c, ok := s.connectors[provider]
+	if !ok || s.oauthStates == nil || s.tokens == nil {
+		return nil, domain.ErrProviderUnavailable
+	}
+	return c, nil
+}
+
+// storeTokens encrypts tokens into acc.
+func (s *Service) storeTokens(acc *domain.SocialAccount, tokens domain.ProviderTokens) error {
+	access, err := s.tokens.Encrypt(tokens.AccessToken)
+	if err != nil {
+		return err
+	}
+	refresh, err := s.tokens.Encrypt(tokens.RefreshToken)
+	if err != nil {
+		return err
+	}
+	acc.AccessToken, acc.RefreshToken, acc.TokenExpiresAt = access, refresh, tokens.ExpiresAt
+	return nil
+}
+
+// accessToken returns a usable plaintext access token for acc, refreshing
+// and persisting new tokens when the current one expires within a minute
+// or force is set.
+func (s *Service) accessToken(ctx context.Context, connector ports.ProviderConnector, acc *domain.SocialAccount, force bool) (string, error) {
+	now := s.nowFn()
+	if !force && (acc.TokenExpiresAt == nil || acc.TokenExpiresAt.After(now.Add(tokenRefreshSkew))) {
+		return s.tokens.Decrypt(acc.AccessToken)
+	}
+	refresh, err := s.tokens.Decrypt(acc.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	tokens, err := connector.Refresh(ctx, refresh)
+	if err != nil {
+		return "", err
+	}
+	if err := s.storeTokens(acc, tokens); err != nil {
+		return "", err
+	}
+	acc.UpdatedAt = now
+	if err := s.accounts.Update(ctx, *acc); err != nil {
+		return "", err
+	}
+	return tokens.AccessToken, nil
+}
+
+// withAccessToken calls fn with a live token, refreshing once when the
+// provider rejects it. An account whose refresh is also rejected is marked
+// expired so the creator is asked to reconnect.
+func (s *Service) withAccessToken(ctx context.Context, acc *domain.SocialAccount, traceID string, fn func(connector ports.ProviderConnector, token string) error) error {
+	connector, err := s.connector(acc.Provider)
+	if err != nil {
+		return err
+	}
+	token, err := s.accessToken(ctx, connector, acc, false)
+	if err == nil {
+		err = fn(connector, token)
+		if errors.Is(err, domain.ErrProviderRejected) {
+			if token, err = s.accessToken(ctx, connector, acc, true); err == nil {
+				err = fn(connector, token)
+			}
+		}
+	}
+	if errors.Is(err, domain.ErrProviderRejected) && acc.Status == domain.AccountStatusActive {
+		now := s.nowFn()
+		acc.Status = domain.AccountStatusExpired
+		acc.UpdatedAt = now
+		if uerr := s.accounts.Update(ctx, *acc); uerr != nil {
+			return uerr
+		}
+		if eerr := s.enqueueSocialStatusChanged(ctx, acc.UserID, acc.Provider, acc.Status, traceID, now); eerr != nil {
+			return eerr
+		}
+	}
+	return err
+}
+
+// newPKCE returns an RFC 7636 code verifier and its S256 challenge.
+func newPKCE() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
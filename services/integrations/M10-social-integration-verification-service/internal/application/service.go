@@ -5,114 +5,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"strings"
-	"time"
 
-	"github.com/google/uuid"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
 )
 
-func (s *Service) ConnectStart(ctx context.Context, actor Actor, input ConnectInput) (ConnectResult, error) {
-	if strings.TrimSpace(actor.SubjectID) == "" {
-		return ConnectResult{}, domain.ErrUnauthorized
-	}
-	input.Provider = normalizeProvider(input.Provider)
-	input.UserID = strings.TrimSpace(input.UserID)
-	if input.UserID == "" || input.Provider == "" || !domain.IsValidProvider(input.Provider) {
-		return ConnectResult{}, domain.ErrInvalidInput
-	}
-	if !canActForUser(actor, input.UserID) {
-		return ConnectResult{}, domain.ErrForbidden
-	}
-	if strings.TrimSpace(actor.IdempotencyKey) == "" {
-		return ConnectResult{}, domain.ErrIdempotencyRequired
-	}
-	requestHash := hashJSON(map[string]string{"op": "connect", "user_id": input.UserID, "provider": input.Provider})
-	if cached, ok, err := s.getIdempotentBody(ctx, actor.IdempotencyKey, requestHash); err != nil {
-		return ConnectResult{}, err
-	} else if ok {
-		var out ConnectResult
-		if err := json.Unmarshal(cached, &out); err == nil {
-			return out, nil
-		}
-	}
-	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
-		return ConnectResult{}, err
-	}
-	state := uuid.NewString()
-	res := ConnectResult{AuthURL: fmt.Sprintf("https://oauth.%s.example/authorize?client_id=mesh-demo&state=%s", input.Provider, state), State: state}
-	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, res)
-	return res, nil
-}
-
-func (s *Service) OAuthCallback(ctx context.Context, actor Actor, input CallbackInput) (domain.SocialAccount, error) {
-	if strings.TrimSpace(actor.SubjectID) == "" {
-		return domain.SocialAccount{}, domain.ErrUnauthorized
-	}
-	input.Provider = normalizeProvider(input.Provider)
-	input.UserID = strings.TrimSpace(input.UserID)
-	input.Code = strings.TrimSpace(input.Code)
-	input.State = strings.TrimSpace(input.State)
-	input.Handle = strings.TrimSpace(input.Handle)
-	if input.UserID == "" || input.Provider == "" || input.Code == "" || input.State == "" || !domain.IsValidProvider(input.Provider) {
-		return domain.SocialAccount{}, domain.ErrInvalidInput
-	}
-	if !canActForUser(actor, input.UserID) {
-		return domain.SocialAccount{}, domain.ErrForbidden
-	}
-	if strings.TrimSpace(actor.IdempotencyKey) == "" {
-		return domain.SocialAccount{}, domain.ErrIdempotencyRequired
-	}
-	requestHash := hashJSON(input)
-	if cached, ok, err := s.getIdempotentBody(ctx, actor.IdempotencyKey, requestHash); err != nil {
-		return domain.SocialAccount{}, err
-	} else if ok {
-		var out domain.SocialAccount
-		if err := json.Unmarshal(cached, &out); err == nil {
-			return out, nil
-		}
-	}
-	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
-		return domain.SocialAccount{}, err
-	}
-	now := s.nowFn()
-	acc, err := s.accounts.GetByUserProvider(ctx, input.UserID, input.Provider)
-	if err != nil {
-		acc = domain.SocialAccount{SocialAccountID: uuid.NewString(), UserID: input.UserID, Provider: input.Provider, ConnectedAt: now}
-	}
-	exp := now.Add(24 * time.Hour)
-	if input.Handle == "" {
-		input.Handle = input.UserID + "_" + input.Provider
-	}
-	acc.Handle = input.Handle
-	acc.Status = domain.AccountStatusActive
-	acc.AccessToken = "enc_access_" + uuid.NewString()
-	acc.RefreshToken = "enc_refresh_" + uuid.NewString()
-	acc.TokenExpiresAt = &exp
-	if acc.ConnectedAt.IsZero() {
-		acc.ConnectedAt = now
-	}
-	acc.UpdatedAt = now
-	if _, err2 := s.accounts.GetByID(ctx, acc.SocialAccountID); err2 == nil {
-		if err := s.accounts.Update(ctx, acc); err != nil {
-			return domain.SocialAccount{}, err
-		}
-	} else {
-		if err := s.accounts.Create(ctx, acc); err != nil {
-			return domain.SocialAccount{}, err
-		}
-	}
-	if err := s.enqueueSocialAccountConnected(ctx, acc, actor.RequestID, now); err != nil {
-		return domain.SocialAccount{}, err
-	}
-	if err := s.enqueueSocialStatusChanged(ctx, acc.UserID, acc.Provider, acc.Status, actor.RequestID, now); err != nil {
-		return domain.SocialAccount{}, err
-	}
-	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, acc)
-	return acc, nil
-}
-
 func (s *Service) ListAccounts(ctx context.Context, actor Actor, userID string) ([]domain.SocialAccount, error) {
 	if strings.TrimSpace(actor.SubjectID) == "" {
 		return nil, domain.ErrUnauthorized
@@ -172,46 +70,76 @@ func (s *Service) DisconnectAccount(ctx context.Context, actor Actor, socialAcco
 	return acc, nil
 }
 
-func (s *Service) RecordFollowersSync(ctx context.Context, actor Actor, input RecordFollowersSyncInput) (domain.SocialMetric, error) {
+// ValidatePost fetches the post from the provider with the user's connected
+// account and checks it against the campaign requirements. A passing report
+// emits social.post.validated; a failing one is reported as a compliance
+// violation listing the failed rules.
+func (s *Service) ValidatePost(ctx context.Context, actor Actor, input PostValidationInput) (domain.PostVerificationReport, error) {
 	if strings.TrimSpace(actor.SubjectID) == "" {
-		return domain.SocialMetric{}, domain.ErrUnauthorized
+		return domain.PostVerificationReport{}, domain.ErrUnauthorized
 	}
-	input.SocialAccountID = strings.TrimSpace(input.SocialAccountID)
-	if input.SocialAccountID == "" || input.FollowerCount < 0 {
-		return domain.SocialMetric{}, domain.ErrInvalidInput
+	input.UserID = strings.TrimSpace(input.UserID)
+	input.Platform = normalizeProvider(input.Platform)
+	input.PostID = strings.TrimSpace(input.PostID)
+	input.CampaignID = strings.TrimSpace(input.CampaignID)
+	if input.UserID == "" || input.PostID == "" || !domain.IsValidProvider(input.Platform) || input.Requirements.MinDuration < 0 {
+		return domain.PostVerificationReport{}, domain.ErrInvalidInput
+	}
+	if !canActForUser(actor, input.UserID) {
+		return domain.PostVerificationReport{}, domain.ErrForbidden
 	}
-	acc, err := s.accounts.GetByID(ctx, input.SocialAccountID)
+	reqs, err := s.postRequirements(ctx, actor, input)
 	if err != nil {
-		return domain.SocialMetric{}, err
+		return domain.PostVerificationReport{}, err
 	}
-	if !canActForUser(actor, acc.UserID) {
-		return domain.SocialMetric{}, domain.ErrForbidden
+	input.Requirements = reqs
+	if len(domain.UncheckableRules(input.Platform, reqs)) > 0 {
+		return domain.PostVerificationReport{}, domain.ErrRuleUnsupported
 	}
-	now := s.nowFn()
-	row := domain.SocialMetric{MetricID: uuid.NewString(), SocialAccountID: acc.SocialAccountID, UserID: acc.UserID, Provider: acc.Provider, FollowerCount: input.FollowerCount, SyncedAt: now}
-	if err := s.metrics.Append(ctx, row); err != nil {
-		return domain.SocialMetric{}, err
+	acc, err := s.accounts.GetByUserProvider(ctx, input.UserID, input.Platform)
+	if err != nil {
+		return domain.PostVerificationReport{}, err
 	}
-	if err := s.enqueueFollowersSynced(ctx, row, actor.RequestID, now); err != nil {
-		return domain.SocialMetric{}, err
+	if acc.Status != domain.AccountStatusActive {
+		return domain.PostVerificationReport{}, domain.ErrConflict
 	}
-	return row, nil
-}
-
-func (s *Service) ValidatePost(ctx context.Context, actor Actor, input PostValidationInput) error {
-	if strings.TrimSpace(actor.SubjectID) == "" {
-		return domain.ErrUnauthorized
+	var post domain.ProviderPost
+	err = s.withAccessToken(ctx, &acc, actor.RequestID, func(connector ports.ProviderConnector, token string) error {
+		var err error
+		post, err = connector.FetchPost(ctx, token, input.PostID)
+		return err
+	})
+	if err != nil {
+		return domain.PostVerificationReport{}, err
 	}
-	input.UserID = strings.TrimSpace(input.UserID)
-	input.Platform = normalizeProvider(input.Platform)
-	input.PostID = strings.TrimSpace(input.PostID)
-	if input.UserID == "" || input.PostID == "" || !domain.IsValidProvider(input.Platform) {
-		return domain.ErrInvalidInput
+	now := s.nowFn()
+	report := domain.PostVerificationReport{
+		UserID:          acc.UserID,
+		SocialAccountID: acc.SocialAccountID,
+		Platform:        acc.Provider,
+		PostID:          input.PostID,
+		CampaignID:      input.CampaignID,
+		PostURL:         post.URL,
+		Rules:           domain.VerifyPost(post, acc, input.Requirements),
+		CheckedAt:       now,
+	}
+	report.Passed = len(report.FailedRules()) == 0
+	if report.Passed {
+		err = s.enqueuePostValidated(ctx, input, actor.RequestID, now)
+	} else {
+		err = s.enqueueComplianceViolation(ctx, ComplianceViolationInput{
+			UserID:      report.UserID,
+			Platform:    report.Platform,
+			PostID:      report.PostID,
+			Reason:      report.FailureSummary(),
+			CampaignID:  report.CampaignID,
+			FailedRules: report.FailedRules(),
+		}, actor.RequestID, now)
 	}
-	if !canActForUser(actor, input.UserID) {
-		return domain.ErrForbidden
+	if err != nil {
+		return domain.PostVerificationReport{}, err
 	}
-	return s.enqueuePostValidated(ctx, input, actor.RequestID, s.nowFn())
+	return report, nil
 }
 
 func (s *Service) ReportComplianceViolation(ctx context.Context, actor Actor, input ComplianceViolationInput) error {
@@ -231,6 +159,69 @@ func (s *Service) ReportComplianceViolation(ctx context.Context, actor Actor, in
 	return s.enqueueComplianceViolation(ctx, input, actor.RequestID, s.nowFn())
 }
 
+// postRequirements resolves the rules a post is checked against. Admin and
+// service callers may pass requirements directly; everyone else gets the
+// requirements registered for the campaign, or ownership only without one.
+func (s *Service) postRequirements(ctx context.Context, actor Actor, input PostValidationInput) (domain.PostRequirements, error) {
+	if isPrivileged(actor) && !input.Requirements.IsZero() {
+		return input.Requirements, nil
+	}
+	if !input.Requirements.IsZero() {
+		return domain.PostRequirements{}, domain.ErrForbidden
+	}
+	if input.CampaignID == "" {
+		return domain.PostRequirements{}, nil
+	}
+	if s.campaigns == nil {
+		return domain.PostRequirements{}, domain.ErrNotFound
+	}
+	row, err := s.campaigns.Get(ctx, input.CampaignID)
+	if err != nil {
+		return domain.PostRequirements{}, err
+	}
+	return row.Requirements, nil
+}
+
+// SetCampaignRequirements registers the rules creators' posts for a campaign
+// are validated against. Only admin and service callers may set them.
+func (s *Service) SetCampaignRequirements(ctx context.Context, actor Actor, input SetCampaignRequirementsInput) (domain.CampaignRequirements, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.CampaignRequirements{}, domain.ErrUnauthorized
+	}
+	if !isPrivileged(actor) {
+		return domain.CampaignRequirements{}, domain.ErrForbidden
+	}
+	campaignID := strings.TrimSpace(input.CampaignID)
+	if campaignID == "" || input.Requirements.MinDuration < 0 || s.campaigns == nil {
+		return domain.CampaignRequirements{}, domain.ErrInvalidInput
+	}
+	row := domain.CampaignRequirements{CampaignID: campaignID, Requirements: input.Requirements, UpdatedBy: actor.SubjectID, UpdatedAt: s.nowFn()}
+	if err := s.campaigns.Put(ctx, row); err != nil {
+		return domain.CampaignRequirements{}, err
+	}
+	return row, nil
+}
+
+// GetCampaignRequirements returns the rules registered for a campaign.
+func (s *Service) GetCampaignRequirements(ctx context.Context, actor Actor, campaignID string) (domain.CampaignRequirements, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.CampaignRequirements{}, domain.ErrUnauthorized
+	}
+	if s.campaigns == nil {
+		return domain.CampaignRequirements{}, domain.ErrNotFound
+	}
+	return s.campaigns.Get(ctx, strings.TrimSpace(campaignID))
+}
+
+func isPrivileged(actor Actor) bool {
+	switch strings.ToLower(strings.TrimSpace(actor.Role)) {
+	case "admin", "system", "service":
+		return true
+	default:
+		return false
+	}
+}
+
 func canActForUser(actor Actor, userID string) bool {
 	if strings.TrimSpace(actor.SubjectID) == strings.TrimSpace(userID) {
 		return true
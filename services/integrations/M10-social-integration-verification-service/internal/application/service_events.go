@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
@@ -80,7 +81,7 @@ func (s *Service) enqueueDomainEvent(ctx context.Context, eventType, traceID str
 		PartitionKeyPath: domain.CanonicalPartitionKeyPath(eventType),
 		PartitionKey:     partitionKey,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          traceID,
+		TraceID:          observability.TraceParent(ctx, traceID),
 		SchemaVersion:    "v1",
 		Data:             b,
 	}
@@ -97,10 +98,10 @@ func (s *Service) enqueueFollowersSynced(ctx context.Context, metric domain.Soci
 	return s.enqueueDomainEvent(ctx, domain.EventSocialFollowersSynced, traceID, contracts.SocialFollowersSyncedPayload{UserID: metric.UserID, Platform: metric.Provider, FollowerCount: metric.FollowerCount, SyncedAt: metric.SyncedAt.UTC().Format(time.RFC3339)}, metric.UserID, now)
 }
 func (s *Service) enqueuePostValidated(ctx context.Context, input PostValidationInput, traceID string, now time.Time) error {
-	return s.enqueueDomainEvent(ctx, domain.EventSocialPostValidated, traceID, contracts.SocialPostValidatedPayload{UserID: input.UserID, Platform: input.Platform, PostID: input.PostID, ValidatedAt: now.UTC().Format(time.RFC3339)}, input.UserID, now)
+	return s.enqueueDomainEvent(ctx, domain.EventSocialPostValidated, traceID, contracts.SocialPostValidatedPayload{UserID: input.UserID, Platform: input.Platform, PostID: input.PostID, ValidatedAt: now.UTC().Format(time.RFC3339), CampaignID: input.CampaignID}, input.UserID, now)
 }
 func (s *Service) enqueueComplianceViolation(ctx context.Context, input ComplianceViolationInput, traceID string, now time.Time) error {
-	return s.enqueueDomainEvent(ctx, domain.EventSocialComplianceViolation, traceID, contracts.SocialComplianceViolationPayload{UserID: input.UserID, Platform: input.Platform, PostID: input.PostID, ViolationAt: now.UTC().Format(time.RFC3339), Reason: input.Reason}, input.UserID, now)
+	return s.enqueueDomainEvent(ctx, domain.EventSocialComplianceViolation, traceID, contracts.SocialComplianceViolationPayload{UserID: input.UserID, Platform: input.Platform, PostID: input.PostID, ViolationAt: now.UTC().Format(time.RFC3339), Reason: input.Reason, CampaignID: input.CampaignID, FailedRules: input.FailedRules}, input.UserID, now)
 }
 
 func validateEnvelope(event contracts.EventEnvelope) error {
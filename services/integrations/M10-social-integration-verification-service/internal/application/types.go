@@ -3,6 +3,7 @@ package application
 import (
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
 )
 
@@ -12,6 +13,14 @@ type Config struct {
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
 	OutboxFlushBatchSize int
+	// OAuthStateTTL bounds the time between ConnectStart and the callback.
+	OAuthStateTTL time.Duration
+	// RedirectURL is the registered OAuth redirect; "{provider}" is replaced
+	// with the provider name.
+	RedirectURL string
+	// FollowerSyncInterval is how often each active account is re-synced.
+	FollowerSyncInterval  time.Duration
+	FollowerSyncBatchSize int
 }
 
 type Actor struct {
@@ -31,26 +40,36 @@ type CallbackInput struct {
 	UserID   string
 	Code     string
 	State    string
-	Handle   string
 }
 
 type RecordFollowersSyncInput struct {
 	SocialAccountID string
 	UserID          string
-	FollowerCount   int
 }
 
+// PostValidationInput names the post to check. Requirements are honoured
+// only from admin and service callers; creators are checked against the
+// requirements registered for CampaignID.
 type PostValidationInput struct {
-	UserID   string
-	Platform string
-	PostID   string
+	UserID       string
+	Platform     string
+	PostID       string
+	CampaignID   string
+	Requirements domain.PostRequirements
+}
+
+type SetCampaignRequirementsInput struct {
+	CampaignID   string
+	Requirements domain.PostRequirements
 }
 
 type ComplianceViolationInput struct {
-	UserID   string
-	Platform string
-	PostID   string
-	Reason   string
+	UserID      string
+	Platform    string
+	PostID      string
+	Reason      string
+	CampaignID  string
+	FailedRules []string
 }
 
 type ConnectResult struct {
@@ -66,6 +85,10 @@ type Service struct {
 	idempotency ports.IdempotencyRepository
 	eventDedup  ports.EventDedupRepository
 	outbox      ports.OutboxRepository
+	oauthStates ports.OAuthStateStore
+	campaigns   ports.CampaignRequirementsRepository
+	connectors  map[string]ports.ProviderConnector
+	tokens      ports.TokenCipher
 
 	domainEvents ports.DomainPublisher
 	analytics    ports.AnalyticsPublisher
@@ -81,6 +104,13 @@ type Dependencies struct {
 	Idempotency ports.IdempotencyRepository
 	EventDedup  ports.EventDedupRepository
 	Outbox      ports.OutboxRepository
+	OAuthStates ports.OAuthStateStore
+	Campaigns   ports.CampaignRequirementsRepository
+
+	// Connectors are keyed by their Provider(); providers without one cannot
+	// be connected.
+	Connectors  []ports.ProviderConnector
+	TokenCipher ports.TokenCipher
 
 	DomainEvents ports.DomainPublisher
 	Analytics    ports.AnalyticsPublisher
@@ -104,16 +134,36 @@ func NewService(deps Dependencies) *Service {
 	if cfg.OutboxFlushBatchSize <= 0 {
 		cfg.OutboxFlushBatchSize = 100
 	}
+	if cfg.OAuthStateTTL <= 0 {
+		cfg.OAuthStateTTL = 10 * time.Minute
+	}
+	if cfg.RedirectURL == "" {
+		cfg.RedirectURL = "http://localhost:3000/social/callback/{provider}"
+	}
+	if cfg.FollowerSyncInterval <= 0 {
+		cfg.FollowerSyncInterval = 6 * time.Hour
+	}
+	if cfg.FollowerSyncBatchSize <= 0 {
+		cfg.FollowerSyncBatchSize = 50
+	}
+	connectors := map[string]ports.ProviderConnector{}
+	for _, c := range deps.Connectors {
+		connectors[normalizeProvider(c.Provider())] = c
+	}
 	return &Service{
-		cfg:         cfg,
-		accounts:    deps.Accounts,
-		metrics:     deps.Metrics,
-		idempotency: deps.Idempotency,
-		eventDedup:  deps.EventDedup,
-		outbox:      deps.Outbox,
+		cfg:          cfg,
+		accounts:     deps.Accounts,
+		metrics:      deps.Metrics,
+		idempotency:  deps.Idempotency,
+		eventDedup:   deps.EventDedup,
+		outbox:       deps.Outbox,
+		oauthStates:  deps.OAuthStates,
+		campaigns:    deps.Campaigns,
+		connectors:   connectors,
+		tokens:       deps.TokenCipher,
 		domainEvents: deps.DomainEvents,
-		analytics:   deps.Analytics,
-		dlq:         deps.DLQ,
-		nowFn:       func() time.Time { return time.Now().UTC() },
+		analytics:    deps.Analytics,
+		dlq:          deps.DLQ,
+		nowFn:        func() time.Time { return time.Now().UTC() },
 	}
 }
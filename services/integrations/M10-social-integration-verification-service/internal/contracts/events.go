@@ -25,18 +25,21 @@ type SocialAccountConnectedPayload struct {
 }
 
 type SocialPostValidatedPayload struct {
-	UserID       string `json:"user_id"`
-	Platform     string `json:"platform"`
-	PostID       string `json:"post_id"`
-	ValidatedAt  string `json:"validated_at"`
+	UserID      string `json:"user_id"`
+	Platform    string `json:"platform"`
+	PostID      string `json:"post_id"`
+	ValidatedAt string `json:"validated_at"`
+	CampaignID  string `json:"campaign_id,omitempty"`
 }
 
 type SocialComplianceViolationPayload struct {
-	UserID       string `json:"user_id"`
-	Platform     string `json:"platform"`
-	PostID       string `json:"post_id"`
-	ViolationAt  string `json:"violation_at"`
-	Reason       string `json:"reason"`
+	UserID      string   `json:"user_id"`
+	Platform    string   `json:"platform"`
+	PostID      string   `json:"post_id"`
+	ViolationAt string   `json:"violation_at"`
+	Reason      string   `json:"reason"`
+	CampaignID  string   `json:"campaign_id,omitempty"`
+	FailedRules []string `json:"failed_rules,omitempty"`
 }
 
 type SocialStatusChangedPayload struct {
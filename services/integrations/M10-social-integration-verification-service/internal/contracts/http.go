@@ -33,6 +33,7 @@ type CallbackRequest struct {
 	UserID string `json:"user_id"`
 	Code   string `json:"code"`
 	State  string `json:"state"`
+	// Handle is ignored; the handle is read from the provider.
 	Handle string `json:"handle,omitempty"`
 }
 
@@ -61,9 +62,43 @@ type DisconnectResponse struct {
 }
 
 type PostValidationRequest struct {
-	UserID   string `json:"user_id"`
-	Platform string `json:"platform"`
-	PostID   string `json:"post_id"`
+	UserID       string                  `json:"user_id"`
+	Platform     string                  `json:"platform"`
+	PostID       string                  `json:"post_id"`
+	CampaignID   string                  `json:"campaign_id,omitempty"`
+	Requirements PostRequirementsRequest `json:"requirements"`
+}
+
+type PostRequirementsRequest struct {
+	RequiredHashtags   []string `json:"required_hashtags,omitempty"`
+	RequiredMentions   []string `json:"required_mentions,omitempty"`
+	RequireDisclosure  bool     `json:"require_disclosure,omitempty"`
+	DisclosureTags     []string `json:"disclosure_tags,omitempty"`
+	MinDurationSeconds int      `json:"min_duration_seconds,omitempty"`
+	PostedAfter        string   `json:"posted_after,omitempty"`
+}
+
+type CampaignRequirementsResponse struct {
+	CampaignID   string                  `json:"campaign_id"`
+	Requirements PostRequirementsRequest `json:"requirements"`
+	UpdatedBy    string                  `json:"updated_by"`
+	UpdatedAt    string                  `json:"updated_at"`
+}
+
+type PostVerificationResponse struct {
+	PostID     string               `json:"post_id"`
+	Platform   string               `json:"platform"`
+	CampaignID string               `json:"campaign_id,omitempty"`
+	PostURL    string               `json:"post_url,omitempty"`
+	Passed     bool                 `json:"passed"`
+	Rules      []RuleResultResponse `json:"rules"`
+	CheckedAt  string               `json:"checked_at"`
+}
+
+type RuleResultResponse struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
 }
 
 type ComplianceViolationRequest struct {
@@ -74,6 +109,7 @@ type ComplianceViolationRequest struct {
 }
 
 type FollowersSyncRequest struct {
-	UserID        string `json:"user_id"`
-	FollowerCount int    `json:"follower_count"`
+	UserID string `json:"user_id"`
+	// FollowerCount is ignored; the count is read from the provider.
+	FollowerCount int `json:"follower_count"`
 }
@@ -13,4 +13,9 @@ var (
 	ErrUnsupportedEventType  = errors.New("unsupported event type")
 	ErrUnsupportedEventClass = errors.New("unsupported event class")
 	ErrInvalidEnvelope       = errors.New("invalid envelope")
+	ErrInvalidOAuthState     = errors.New("invalid or expired oauth state")
+	ErrProviderRejected      = errors.New("provider rejected the request")
+	ErrProviderUnavailable   = errors.New("provider unavailable")
+	ErrAccountClaimed        = errors.New("provider account is connected to another user")
+	ErrRuleUnsupported       = errors.New("requirement cannot be checked on this provider")
 )
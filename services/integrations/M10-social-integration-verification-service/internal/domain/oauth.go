@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// OAuthState is the server-side half of an authorization-code+PKCE flow,
+// kept between ConnectStart and the callback. It is single use.
+type OAuthState struct {
+	State        string
+	UserID       string
+	Provider     string
+	RedirectURI  string
+	CodeVerifier string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// ProviderTokens are plaintext tokens as returned by a provider.
+type ProviderTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    *time.Time
+	Scope        string
+}
+
+// ProviderProfile is the account identity and audience reported by the
+// provider for an access token.
+type ProviderProfile struct {
+	ExternalID    string
+	Handle        string
+	DisplayName   string
+	FollowerCount int
+}
@@ -9,27 +9,32 @@ const (
 	AccountStatusRevoked = "revoked"
 )
 
+// SocialAccount is a provider account proven through OAuth. ExternalID and
+// Handle come from the provider profile, never from the client; AccessToken
+// and RefreshToken hold ciphertext.
 type SocialAccount struct {
-	SocialAccountID  string
-	UserID           string
-	Provider         string
-	Handle           string
-	Status           string
-	AccessToken      string
-	RefreshToken     string
-	TokenExpiresAt   *time.Time
-	ConnectedAt      time.Time
-	DisconnectedAt   *time.Time
-	UpdatedAt        time.Time
+	SocialAccountID string
+	UserID          string
+	Provider        string
+	ExternalID      string
+	Handle          string
+	Status          string
+	AccessToken     string
+	RefreshToken    string
+	TokenExpiresAt  *time.Time
+	LastSyncedAt    *time.Time
+	ConnectedAt     time.Time
+	DisconnectedAt  *time.Time
+	UpdatedAt       time.Time
 }
 
 type SocialMetric struct {
-	MetricID         string
-	SocialAccountID  string
-	UserID           string
-	Provider         string
-	FollowerCount    int
-	SyncedAt         time.Time
+	MetricID        string
+	SocialAccountID string
+	UserID          string
+	Provider        string
+	FollowerCount   int
+	SyncedAt        time.Time
 }
 
 func IsValidProvider(v string) bool {
@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	RuleOwnership   = "ownership"
+	RuleHashtag     = "hashtag"
+	RuleMention     = "mention"
+	RuleDisclosure  = "disclosure"
+	RuleMinDuration = "min_duration"
+	RulePostedAfter = "posted_after"
+)
+
+// DefaultDisclosureTags are accepted as an ad disclosure when a campaign
+// does not name its own.
+var DefaultDisclosureTags = []string{"ad", "sponsored", "paidpartnership", "advertisement"}
+
+var (
+	hashtagPattern = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+	mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.]+)`)
+)
+
+// ProviderPost is a post as read from the provider API. OwnerScoped is set
+// by providers that only ever return posts of the token owner.
+type ProviderPost struct {
+	PostID           string
+	AuthorExternalID string
+	AuthorHandle     string
+	OwnerScoped      bool
+	Caption          string
+	Hashtags         []string
+	Mentions         []string
+	Duration         time.Duration
+	BrandedContent   bool
+	PostedAt         time.Time
+	URL              string
+}
+
+// PostRequirements are the campaign rules a post is checked against.
+// Hashtags and mentions are compared case-insensitively without their
+// leading '#' or '@'.
+type PostRequirements struct {
+	RequiredHashtags  []string
+	RequiredMentions  []string
+	RequireDisclosure bool
+	DisclosureTags    []string
+	MinDuration       time.Duration
+	PostedAfter       *time.Time
+}
+
+// IsZero reports whether no rule beyond post ownership is set.
+func (r PostRequirements) IsZero() bool {
+	return len(r.RequiredHashtags) == 0 && len(r.RequiredMentions) == 0 && !r.RequireDisclosure &&
+		len(r.DisclosureTags) == 0 && r.MinDuration == 0 && r.PostedAfter == nil
+}
+
+// CampaignRequirements are the rules registered for a campaign by an admin
+// or the campaign's owning service. Creators' posts are validated against
+// these, never against requirements they send themselves.
+type CampaignRequirements struct {
+	CampaignID   string
+	Requirements PostRequirements
+	UpdatedBy    string
+	UpdatedAt    time.Time
+}
+
+// RuleResult is the outcome of one rule; Rule is the rule kind, suffixed
+// with its subject for per-tag rules ("hashtag:summer").
+type RuleResult struct {
+	Rule   string
+	Passed bool
+	Detail string
+}
+
+type PostVerificationReport struct {
+	UserID          string
+	SocialAccountID string
+	Platform        string
+	PostID          string
+	CampaignID      string
+	PostURL         string
+	Passed          bool
+	Rules           []RuleResult
+	CheckedAt       time.Time
+}
+
+// FailedRules returns the names of the rules that did not pass.
+func (r PostVerificationReport) FailedRules() []string {
+	out := make([]string, 0)
+	for _, rule := range r.Rules {
+		if !rule.Passed {
+			out = append(out, rule.Rule)
+		}
+	}
+	return out
+}
+
+// FailureSummary joins the details of failed rules into a violation reason.
+func (r PostVerificationReport) FailureSummary() string {
+	parts := make([]string, 0)
+	for _, rule := range r.Rules {
+		if !rule.Passed {
+			parts = append(parts, rule.Rule+": "+rule.Detail)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// VerifyPost evaluates post against the account it should belong to and the
+// campaign requirements. Ownership is always checked; the other rules only
+// when the requirement is set.
+func VerifyPost(post ProviderPost, acc SocialAccount, req PostRequirements) []RuleResult {
+	out := []RuleResult{verifyOwnership(post, acc)}
+	hashtags := tagSet(append(ExtractHashtags(post.Caption), post.Hashtags...))
+	mentions := tagSet(append(ExtractMentions(post.Caption), post.Mentions...))
+	for _, tag := range req.RequiredHashtags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		res := RuleResult{Rule: RuleHashtag + ":" + tag, Passed: hashtags[tag]}
+		if !res.Passed {
+			res.Detail = "missing #" + tag
+		}
+		out = append(out, res)
+	}
+	for _, handle := range req.RequiredMentions {
+		handle = NormalizeTag(handle)
+		if handle == "" {
+			continue
+		}
+		res := RuleResult{Rule: RuleMention + ":" + handle, Passed: mentions[handle]}
+		if !res.Passed {
+			res.Detail = "missing @" + handle
+		}
+		out = append(out, res)
+	}
+	if req.RequireDisclosure {
+		out = append(out, verifyDisclosure(post, hashtags, req.DisclosureTags))
+	}
+	if req.MinDuration > 0 {
+		res := RuleResult{Rule: RuleMinDuration, Passed: post.Duration >= req.MinDuration}
+		switch {
+		case post.Duration <= 0:
+			res.Passed = false
+			res.Detail = "post has no duration"
+		case !res.Passed:
+			res.Detail = fmt.Sprintf("duration %s is shorter than %s", post.Duration, req.MinDuration)
+		}
+		out = append(out, res)
+	}
+	if req.PostedAfter != nil {
+		res := RuleResult{Rule: RulePostedAfter, Passed: !post.PostedAt.IsZero() && !post.PostedAt.Before(*req.PostedAfter)}
+		if !res.Passed {
+			res.Detail = fmt.Sprintf("posted at %s, before %s", post.PostedAt.UTC().Format(time.RFC3339), req.PostedAfter.UTC().Format(time.RFC3339))
+			if post.PostedAt.IsZero() {
+				res.Detail = "post has no publish time"
+			}
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+// UncheckableRules returns the requirements provider's post API gives no
+// data for. Instagram's Graph API reports no media duration, so a
+// min_duration rule could never pass there.
+func UncheckableRules(provider string, req PostRequirements) []string {
+	out := make([]string, 0)
+	if req.MinDuration > 0 && provider == "instagram" {
+		out = append(out, RuleMinDuration)
+	}
+	return out
+}
+
+func verifyOwnership(post ProviderPost, acc SocialAccount) RuleResult {
+	res := RuleResult{Rule: RuleOwnership}
+	switch {
+	case post.OwnerScoped:
+		res.Passed = true
+	case post.AuthorExternalID != "" && acc.ExternalID != "":
+		res.Passed = post.AuthorExternalID == acc.ExternalID
+	case post.AuthorHandle != "":
+		res.Passed = NormalizeTag(post.AuthorHandle) == NormalizeTag(acc.Handle)
+	}
+	if !res.Passed {
+		author := post.AuthorHandle
+		if author == "" {
+			author = post.AuthorExternalID
+		}
+		res.Detail = fmt.Sprintf("post author %q is not @%s", author, NormalizeTag(acc.Handle))
+	}
+	return res
+}
+
+func verifyDisclosure(post ProviderPost, hashtags map[string]bool, accepted []string) RuleResult {
+	res := RuleResult{Rule: RuleDisclosure}
+	if post.BrandedContent {
+		res.Passed = true
+		return res
+	}
+	if len(accepted) == 0 {
+		accepted = DefaultDisclosureTags
+	}
+	for _, tag := range accepted {
+		if hashtags[NormalizeTag(tag)] {
+			res.Passed = true
+			return res
+		}
+	}
+	res.Detail = "no ad disclosure (#" + strings.Join(accepted, ", #") + ")"
+	return res
+}
+
+// NormalizeTag lower-cases a hashtag or handle and strips its '#' or '@'.
+func NormalizeTag(v string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(v), "#@"))
+}
+
+func ExtractHashtags(text string) []string { return extract(hashtagPattern, text) }
+
+func ExtractMentions(text string) []string {
+	out := extract(mentionPattern, text)
+	for i := range out {
+		out[i] = strings.TrimRight(out[i], ".")
+	}
+	return out
+}
+
+func extract(re *regexp.Regexp, text string) []string {
+	matches := re.FindAllStringSubmatch(text, -1)
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+func tagSet(tags []string) map[string]bool {
+	out := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if t = NormalizeTag(t); t != "" {
+			out[t] = true
+		}
+	}
+	return out
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+)
+
+// ProviderConnector talks to one social provider. Errors wrap
+// domain.ErrProviderRejected when the provider refused the code or token and
+// domain.ErrProviderUnavailable for transport or server failures; a post the
+// provider does not know wraps domain.ErrNotFound.
+type ProviderConnector interface {
+	Provider() string
+	AuthorizationURL(state, codeChallenge, redirectURI string) string
+	ExchangeCode(ctx context.Context, code, codeVerifier, redirectURI string) (domain.ProviderTokens, error)
+	Refresh(ctx context.Context, refreshToken string) (domain.ProviderTokens, error)
+	FetchProfile(ctx context.Context, accessToken string) (domain.ProviderProfile, error)
+	FetchPost(ctx context.Context, accessToken, postID string) (domain.ProviderPost, error)
+}
+
+// TokenCipher encrypts provider tokens at rest.
+type TokenCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// OAuthStateStore keeps pending PKCE state between authorize and callback.
+type OAuthStateStore interface {
+	Put(ctx context.Context, row domain.OAuthState) error
+	// Take returns and deletes the state so it cannot be replayed.
+	Take(ctx context.Context, state string, now time.Time) (domain.OAuthState, error)
+}
@@ -13,6 +13,14 @@ type SocialAccountRepository interface {
 	Update(ctx context.Context, row domain.SocialAccount) error
 	ListByUserID(ctx context.Context, userID string) ([]domain.SocialAccount, error)
 	GetByUserProvider(ctx context.Context, userID, provider string) (domain.SocialAccount, error)
+	GetByProviderExternalID(ctx context.Context, provider, externalID string) (domain.SocialAccount, error)
+	// ListDueForSync returns active accounts not synced since before.
+	ListDueForSync(ctx context.Context, before time.Time, limit int) ([]domain.SocialAccount, error)
+}
+
+type CampaignRequirementsRepository interface {
+	Put(ctx context.Context, row domain.CampaignRequirements) error
+	Get(ctx context.Context, campaignID string) (domain.CampaignRequirements, error)
 }
 
 type SocialMetricRepository interface {
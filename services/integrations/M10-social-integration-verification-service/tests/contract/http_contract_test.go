@@ -6,18 +6,36 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	eventadapter "github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/events"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/providers"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/security"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
 )
 
-func newRouter() http.Handler {
+func newRouter(t *testing.T) http.Handler {
+	t.Helper()
+	mock := providers.NewMockServer()
+	mock.AddAccount(providers.MockAccount{ExternalID: "ig-1", Handle: "creator", Followers: 1200})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	connector, err := providers.NewConnector(providers.Config{Provider: "instagram", ClientID: "client", ClientSecret: "secret", Endpoints: providers.MockEndpoints(server.URL)})
+	if err != nil {
+		t.Fatalf("NewConnector: %v", err)
+	}
+	cipher, err := security.NewAESGCMTokenCipher("test-key")
+	if err != nil {
+		t.Fatalf("NewAESGCMTokenCipher: %v", err)
+	}
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
 		Accounts: repos.Accounts, Metrics: repos.Metrics, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox,
+		OAuthStates: repos.OAuthStates, Connectors: []ports.ProviderConnector{connector}, TokenCipher: cipher,
 		DomainEvents: eventadapter.NewMemoryDomainPublisher(), Analytics: eventadapter.NewMemoryAnalyticsPublisher(), DLQ: eventadapter.NewLoggingDLQPublisher(),
 	})
 	return httpadapter.NewRouter(httpadapter.NewHandler(svc))
@@ -25,7 +43,7 @@ func newRouter() http.Handler {
 
 func TestConnectRouteSupportsVersionedAndUnversionedPaths(t *testing.T) {
 	t.Parallel()
-	router := newRouter()
+	router := newRouter(t)
 
 	paths := []string{
 		"/social/connect/instagram",
@@ -46,19 +64,42 @@ func TestConnectRouteSupportsVersionedAndUnversionedPaths(t *testing.T) {
 
 func TestCallbackResponseIncludesProvider(t *testing.T) {
 	t.Parallel()
-	router := newRouter()
+	router := newRouter(t)
+
+	start := jsonRequest(t, "/social/connect/instagram", map[string]any{})
+	start.Header.Set("Authorization", "Bearer user_1")
+	start.Header.Set("Idempotency-Key", "idem-connect-1")
+	startRes := httptest.NewRecorder()
+	router.ServeHTTP(startRes, start)
+	var started struct {
+		Data struct {
+			AuthURL string `json:"auth_url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(startRes.Body.Bytes(), &started); err != nil || started.Data.AuthURL == "" {
+		t.Fatalf("connect start: %d %s", startRes.Code, startRes.Body.String())
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	authRes, err := client.Get(started.Data.AuthURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	authRes.Body.Close()
+	redirect, err := url.Parse(authRes.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse redirect: %v", err)
+	}
 
 	req := jsonRequest(t, "/social/callback/instagram", map[string]any{
-		"code":   "oauth-code",
-		"state":  "state-1",
-		"handle": "creator",
+		"code":  redirect.Query().Get("code"),
+		"state": redirect.Query().Get("state"),
 	})
 	req.Header.Set("Authorization", "Bearer user_1")
 	req.Header.Set("Idempotency-Key", "idem-callback-1")
 	res := httptest.NewRecorder()
 	router.ServeHTTP(res, req)
 	if res.Code != http.StatusOK {
-		t.Fatalf("expected 200, got %d", res.Code)
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
 	}
 
 	var payload map[string]any
@@ -73,7 +114,7 @@ func TestCallbackResponseIncludesProvider(t *testing.T) {
 
 func TestErrorEnvelopeIncludesTopLevelCodeAndNestedError(t *testing.T) {
 	t.Parallel()
-	router := newRouter()
+	router := newRouter(t)
 
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/social/connect/instagram", bytes.NewBufferString("{"))
 	if err != nil {
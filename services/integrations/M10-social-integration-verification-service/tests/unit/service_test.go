@@ -2,29 +2,87 @@ package unit
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	eventadapter "github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/events"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/providers"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/adapters/security"
 	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M10-social-integration-verification-service/internal/ports"
 )
 
-func newService() (*application.Service, *postgres.Repositories) {
+func newService(t *testing.T) (*application.Service, *postgres.Repositories, *providers.MockServer) {
+	t.Helper()
+	mock := providers.NewMockServer()
+	mock.AddAccount(providers.MockAccount{ExternalID: "ig-1", Handle: "creator", Followers: 1200})
+	mock.AddAccount(providers.MockAccount{ExternalID: "ig-2", Handle: "other", Followers: 50})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	connectors := make([]ports.ProviderConnector, 0)
+	for _, name := range providers.SupportedProviders() {
+		c, err := providers.NewConnector(providers.Config{Provider: name, ClientID: "client", ClientSecret: "secret", Endpoints: providers.MockEndpoints(server.URL)})
+		if err != nil {
+			t.Fatalf("NewConnector(%s): %v", name, err)
+		}
+		connectors = append(connectors, c)
+	}
+	cipher, err := security.NewAESGCMTokenCipher("test-key")
+	if err != nil {
+		t.Fatalf("NewAESGCMTokenCipher: %v", err)
+	}
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
 		Accounts: repos.Accounts, Metrics: repos.Metrics, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox,
+		OAuthStates: repos.OAuthStates, Campaigns: repos.Campaigns, Connectors: connectors, TokenCipher: cipher,
 		DomainEvents: eventadapter.NewMemoryDomainPublisher(), Analytics: eventadapter.NewMemoryAnalyticsPublisher(), DLQ: eventadapter.NewLoggingDLQPublisher(),
 	})
-	return svc, repos
+	return svc, repos, mock
+}
+
+// connect runs ConnectStart, the provider's authorize redirect and the
+// callback for the mock account loginHint.
+func connect(t *testing.T, svc *application.Service, userID, loginHint string) (domain.SocialAccount, error) {
+	t.Helper()
+	ctx := context.Background()
+	actor := application.Actor{SubjectID: userID, Role: "user", RequestID: "req_" + userID, IdempotencyKey: "idem-connect-" + userID}
+	start, err := svc.ConnectStart(ctx, actor, application.ConnectInput{Provider: "instagram", UserID: userID})
+	if err != nil {
+		t.Fatalf("ConnectStart: %v", err)
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Get(start.AuthURL + "&login_hint=" + url.QueryEscape(loginHint))
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	res.Body.Close()
+	loc, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || loc.Query().Get("state") != start.State {
+		t.Fatalf("unexpected authorize redirect %q", res.Header.Get("Location"))
+	}
+	actor.IdempotencyKey = "idem-callback-" + userID
+	return svc.OAuthCallback(ctx, actor, application.CallbackInput{Provider: "instagram", UserID: userID, Code: loc.Query().Get("code"), State: start.State})
 }
 
 func TestOAuthCallbackEnqueuesCanonicalEvents(t *testing.T) {
-	svc, repos := newService()
-	actor := application.Actor{SubjectID: "user_1", Role: "user", RequestID: "req_1", IdempotencyKey: "idem-callback-1"}
-	_, err := svc.OAuthCallback(context.Background(), actor, application.CallbackInput{Provider: "instagram", UserID: "user_1", Code: "oauth-code", State: "state-1", Handle: "creator"})
+	svc, repos, _ := newService(t)
+	acc, err := connect(t, svc, "user_1", "ig-1")
 	if err != nil {
 		t.Fatalf("OAuthCallback: %v", err)
 	}
+	if acc.Handle != "creator" || acc.ExternalID != "ig-1" {
+		t.Fatalf("expected provider identity, got handle=%q external_id=%q", acc.Handle, acc.ExternalID)
+	}
+	if !strings.HasPrefix(acc.AccessToken, "v1.") || !strings.HasPrefix(acc.RefreshToken, "v1.") {
+		t.Fatalf("expected encrypted tokens, got %q / %q", acc.AccessToken, acc.RefreshToken)
+	}
 	pending, err := repos.Outbox.ListPending(context.Background(), 10)
 	if err != nil {
 		t.Fatalf("ListPending: %v", err)
@@ -35,7 +93,7 @@ func TestOAuthCallbackEnqueuesCanonicalEvents(t *testing.T) {
 }
 
 func TestConnectStartIdempotentReplay(t *testing.T) {
-	svc, _ := newService()
+	svc, _, _ := newService(t)
 	actor := application.Actor{SubjectID: "user_1", Role: "user", RequestID: "req_1", IdempotencyKey: "idem-connect-1"}
 	first, err := svc.ConnectStart(context.Background(), actor, application.ConnectInput{Provider: "instagram", UserID: "user_1"})
 	if err != nil {
@@ -48,4 +106,151 @@ func TestConnectStartIdempotentReplay(t *testing.T) {
 	if first.State != second.State || first.AuthURL != second.AuthURL {
 		t.Fatalf("expected idempotent replay to match; first=%+v second=%+v", first, second)
 	}
+	if !strings.Contains(first.AuthURL, "code_challenge_method=S256") || strings.Contains(first.AuthURL, "code_verifier") {
+		t.Fatalf("expected S256 challenge without verifier, got %s", first.AuthURL)
+	}
+}
+
+func TestOAuthCallbackRejectsUnknownState(t *testing.T) {
+	svc, _, _ := newService(t)
+	actor := application.Actor{SubjectID: "user_1", Role: "user", RequestID: "req_1", IdempotencyKey: "idem-callback-forged"}
+	_, err := svc.OAuthCallback(context.Background(), actor, application.CallbackInput{Provider: "instagram", UserID: "user_1", Code: "code", State: "forged"})
+	if !errors.Is(err, domain.ErrInvalidOAuthState) {
+		t.Fatalf("expected ErrInvalidOAuthState, got %v", err)
+	}
+}
+
+func TestOAuthCallbackPreventsClaimingConnectedAccount(t *testing.T) {
+	svc, _, _ := newService(t)
+	if _, err := connect(t, svc, "user_1", "ig-1"); err != nil {
+		t.Fatalf("first connect: %v", err)
+	}
+	if _, err := connect(t, svc, "user_2", "ig-1"); !errors.Is(err, domain.ErrAccountClaimed) {
+		t.Fatalf("expected ErrAccountClaimed, got %v", err)
+	}
+}
+
+func TestFollowersSyncRefreshesRejectedToken(t *testing.T) {
+	svc, repos, mock := newService(t)
+	acc, err := connect(t, svc, "user_1", "ig-1")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	mock.ExpireAccessTokens()
+	mock.SetFollowers("ig-1", 1500)
+	actor := application.Actor{SubjectID: "user_1", Role: "user", RequestID: "req_sync"}
+	row, err := svc.RecordFollowersSync(context.Background(), actor, application.RecordFollowersSyncInput{SocialAccountID: acc.SocialAccountID, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("RecordFollowersSync: %v", err)
+	}
+	if row.FollowerCount != 1500 {
+		t.Fatalf("expected provider follower count 1500, got %d", row.FollowerCount)
+	}
+	stored, _ := repos.Accounts.GetByID(context.Background(), acc.SocialAccountID)
+	if stored.AccessToken == acc.AccessToken || stored.LastSyncedAt == nil {
+		t.Fatalf("expected refreshed token and sync timestamp, got %+v", stored)
+	}
+}
+
+func TestSyncDueFollowersSkipsRecentlySynced(t *testing.T) {
+	svc, _, _ := newService(t)
+	if _, err := connect(t, svc, "user_1", "ig-1"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if n, err := svc.SyncDueFollowers(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 synced account, got %d (%v)", n, err)
+	}
+	if n, err := svc.SyncDueFollowers(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected nothing due after sync, got %d (%v)", n, err)
+	}
+}
+
+func lastOutboxEventType(t *testing.T, repos *postgres.Repositories) string {
+	t.Helper()
+	pending, err := repos.Outbox.ListPending(context.Background(), 100)
+	if err != nil || len(pending) == 0 {
+		t.Fatalf("ListPending: %d rows, %v", len(pending), err)
+	}
+	return pending[len(pending)-1].Envelope.EventType
+}
+
+func TestValidatePostPassesCampaignRequirements(t *testing.T) {
+	svc, repos, mock := newService(t)
+	if _, err := connect(t, svc, "user_1", "ig-1"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	campaignStart := time.Now().Add(-time.Hour)
+	if _, err := svc.SetCampaignRequirements(context.Background(), application.Actor{SubjectID: "campaign-service", Role: "service"}, application.SetCampaignRequirementsInput{
+		CampaignID:   "camp-1",
+		Requirements: domain.PostRequirements{RequiredHashtags: []string{"#summer"}, RequiredMentions: []string{"brand.co"}, RequireDisclosure: true, PostedAfter: &campaignStart},
+	}); err != nil {
+		t.Fatalf("SetCampaignRequirements: %v", err)
+	}
+	mock.AddPost(providers.MockPost{ID: "post-1", AccountID: "ig-1", Caption: "Summer drop with @Brand.Co #Summer #ad", PostedAt: time.Now()})
+	actor := application.Actor{SubjectID: "user_1", Role: "user", RequestID: "req_validate"}
+	report, err := svc.ValidatePost(context.Background(), actor, application.PostValidationInput{
+		UserID: "user_1", Platform: "instagram", PostID: "post-1", CampaignID: "camp-1",
+	})
+	if err != nil {
+		t.Fatalf("ValidatePost: %v", err)
+	}
+	if !report.Passed || len(report.Rules) != 5 {
+		t.Fatalf("expected 5 passing rules, got %+v", report.Rules)
+	}
+	if got := lastOutboxEventType(t, repos); got != domain.EventSocialPostValidated {
+		t.Fatalf("expected %s, got %s", domain.EventSocialPostValidated, got)
+	}
+}
+
+func TestValidatePostReportsComplianceViolation(t *testing.T) {
+	svc, repos, mock := newService(t)
+	if _, err := connect(t, svc, "user_1", "ig-1"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	mock.AddPost(providers.MockPost{ID: "post-2", AccountID: "ig-2", Caption: "not sponsored at all", PostedAt: time.Now()})
+	actor := application.Actor{SubjectID: "ops_1", Role: "admin", RequestID: "req_validate"}
+	// Instagram reports no duration, so min_duration is refused up front
+	// instead of failing every post.
+	if _, err := svc.ValidatePost(context.Background(), actor, application.PostValidationInput{
+		UserID: "user_1", Platform: "instagram", PostID: "post-2",
+		Requirements: domain.PostRequirements{RequiredHashtags: []string{"summer"}, MinDuration: 30 * time.Second},
+	}); !errors.Is(err, domain.ErrRuleUnsupported) {
+		t.Fatalf("expected min_duration to be unsupported on instagram, got %v", err)
+	}
+	report, err := svc.ValidatePost(context.Background(), actor, application.PostValidationInput{
+		UserID: "user_1", Platform: "instagram", PostID: "post-2",
+		Requirements: domain.PostRequirements{RequiredHashtags: []string{"summer"}, RequireDisclosure: true},
+	})
+	if err != nil {
+		t.Fatalf("ValidatePost: %v", err)
+	}
+	failed := strings.Join(report.FailedRules(), ",")
+	if report.Passed || failed != "ownership,hashtag:summer,disclosure" {
+		t.Fatalf("unexpected report passed=%v failed=%s", report.Passed, failed)
+	}
+	if got := lastOutboxEventType(t, repos); got != domain.EventSocialComplianceViolation {
+		t.Fatalf("expected %s, got %s", domain.EventSocialComplianceViolation, got)
+	}
+}
+
+func TestValidatePostRejectsCreatorSuppliedRequirements(t *testing.T) {
+	svc, _, mock := newService(t)
+	if _, err := connect(t, svc, "user_1", "ig-1"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	mock.AddPost(providers.MockPost{ID: "post-3", AccountID: "ig-1", Caption: "no tags", PostedAt: time.Now()})
+	actor := application.Actor{SubjectID: "user_1", Role: "user", RequestID: "req_validate"}
+	_, err := svc.ValidatePost(context.Background(), actor, application.PostValidationInput{
+		UserID: "user_1", Platform: "instagram", PostID: "post-3", CampaignID: "camp-1",
+		Requirements: domain.PostRequirements{MinDuration: time.Second},
+	})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden for creator-supplied requirements, got %v", err)
+	}
+	if _, err := svc.ValidatePost(context.Background(), actor, application.PostValidationInput{UserID: "user_1", Platform: "instagram", PostID: "post-3", CampaignID: "camp-unknown"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for unregistered campaign, got %v", err)
+	}
+	if _, err := svc.SetCampaignRequirements(context.Background(), actor, application.SetCampaignRequirementsInput{CampaignID: "camp-1"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected creators to be unable to set requirements, got %v", err)
+	}
 }
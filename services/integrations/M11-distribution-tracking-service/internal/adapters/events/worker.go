@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
@@ -41,7 +42,7 @@ func (w *Worker) Run(ctx context.Context) error {
 		case <-t.C:
 			if w.service != nil {
 				if err := w.service.RunPollCycle(ctx); err != nil {
-					return err
+					w.logger.WarnContext(ctx, "poll cycle incomplete", "error", err)
 				}
 				if err := w.service.FlushOutbox(ctx); err != nil {
 					return err
@@ -60,15 +61,18 @@ func (w *Worker) Run(ctx context.Context) error {
 			if e == nil {
 				continue
 			}
-			if err := w.service.HandleCanonicalEvent(ctx, *e); err != nil {
+			ectx, span := observability.StartEventSpan(ctx, e.EventType, e.TraceID)
+			err = w.service.HandleCanonicalEvent(ectx, *e)
+			observability.EndEventSpan(span, err)
+			if err != nil {
 				if e.EventClass == domain.CanonicalEventClassAnalyticsOnly {
-					w.logger.WarnContext(ctx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+					w.logger.WarnContext(ectx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 					continue
 				}
-				w.logger.ErrorContext(ctx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+				w.logger.ErrorContext(ectx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 				if w.dlqPublisher != nil {
 					now := time.Now().UTC()
-					_ = w.dlqPublisher.PublishDLQ(ctx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "distribution-tracking-service.dlq", TraceID: e.TraceID})
+					_ = w.dlqPublisher.PublishDLQ(ectx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "distribution-tracking-service.dlq", TraceID: e.TraceID})
 				}
 			}
 		}
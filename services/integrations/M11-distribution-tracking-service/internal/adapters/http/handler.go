@@ -73,15 +73,36 @@ func (h *Handler) getMetrics(w http.ResponseWriter, r *http.Request) {
 		resp.LastPolledAt = post.LastPolledAt.UTC().Format(time.RFC3339)
 	}
 	for _, s := range snaps {
-		resp.Snapshots = append(resp.Snapshots, contracts.MetricSnapshotResponse{SnapshotID: s.SnapshotID, TrackedPostID: s.TrackedPostID, Platform: s.Platform, Views: s.Views, Likes: s.Likes, Shares: s.Shares, Comments: s.Comments, PolledAt: s.PolledAt.UTC().Format(time.RFC3339)})
+		resp.Snapshots = append(resp.Snapshots, contracts.MetricSnapshotResponse{SnapshotID: s.SnapshotID, TrackedPostID: s.TrackedPostID, Platform: s.Platform, Views: s.Views, Likes: s.Likes, Shares: s.Shares, Comments: s.Comments, PolledAt: s.PolledAt.UTC().Format(time.RFC3339), ViewsReported: s.ViewsReported})
 	}
 	writeSuccess(w, http.StatusOK, "tracked post metrics", resp)
 }
 
+func (h *Handler) getAnomaly(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.GetTrackedPostAnomaly(r.Context(), actorFromContext(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	resp := contracts.AnomalyReportResponse{TrackedPostID: report.TrackedPostID, Platform: report.Platform, SuspicionScore: report.Score, Suspicious: report.Suspicious, Reasons: make([]contracts.AnomalyReasonResponse, 0, len(report.Reasons)), VelocityViewsPerHour: report.ViewsPerHour, AccelerationViewsPerHourSq: report.Acceleration, LikeViewRatio: report.LikeViewRatio, CommentViewRatio: report.CommentViewRatio, SnapshotCount: report.SnapshotCount, AnalyzedAt: report.AnalyzedAt.UTC().Format(time.RFC3339)}
+	for _, reason := range report.Reasons {
+		resp.Reasons = append(resp.Reasons, contracts.AnomalyReasonResponse{Code: reason.Code, Detail: reason.Detail, Weight: reason.Weight})
+	}
+	writeSuccess(w, http.StatusOK, "tracked post anomaly report", resp)
+}
+
 func toTrackedPostResponse(p domain.TrackedPost) contracts.TrackedPostResponse {
-	out := contracts.TrackedPostResponse{TrackedPostID: p.TrackedPostID, UserID: p.UserID, Platform: p.Platform, PostURL: p.PostURL, DistributionItemID: p.DistributionItemID, CampaignID: p.CampaignID, Status: string(p.Status), ValidationStatus: p.ValidationStatus, CreatedAt: p.CreatedAt.UTC().Format(time.RFC3339), UpdatedAt: p.UpdatedAt.UTC().Format(time.RFC3339)}
+	out := contracts.TrackedPostResponse{TrackedPostID: p.TrackedPostID, UserID: p.UserID, Platform: p.Platform, PostURL: p.PostURL, PlatformPostID: p.PlatformPostID, DistributionItemID: p.DistributionItemID, CampaignID: p.CampaignID, Status: string(p.Status), ValidationStatus: p.ValidationStatus, CreatedAt: p.CreatedAt.UTC().Format(time.RFC3339), UpdatedAt: p.UpdatedAt.UTC().Format(time.RFC3339), PollFailures: p.PollFailures, LastPollError: p.LastPollError}
 	if p.LastPolledAt != nil {
 		out.LastPolledAt = p.LastPolledAt.UTC().Format(time.RFC3339)
 	}
+	if p.NextPollAt != nil {
+		out.NextPollAt = p.NextPollAt.UTC().Format(time.RFC3339)
+	}
+	out.AnomalyScore = p.AnomalyScore
+	if p.AnomalyFlaggedAt != nil {
+		out.AnomalyFlaggedAt = p.AnomalyFlaggedAt.UTC().Format(time.RFC3339)
+	}
 	return out
 }
@@ -18,6 +18,7 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Post("/tracking/posts", handler.registerPost)
 			r.Get("/tracking/posts/{id}", handler.getPost)
 			r.Get("/tracking/posts/{id}/metrics", handler.getMetrics)
+			r.Get("/tracking/posts/{id}/anomaly", handler.getAnomaly)
 		})
 	})
 	return r
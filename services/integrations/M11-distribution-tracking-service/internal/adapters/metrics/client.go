@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return observability.WrapClient(&http.Client{Timeout: timeout})
+}
+
+// doJSON sends req and decodes the JSON body into dst, mapping platform
+// status codes onto the errors ports.MetricsFetcher documents.
+func doJSON(client *http.Client, platform string, req *http.Request, dst any) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", domain.ErrMetricsUnavailable, platform, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", domain.ErrMetricsUnavailable, platform, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w: %s: status %d", domain.ErrPostGone, platform, resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s: status %d", domain.ErrPostNotFound, platform, resp.StatusCode)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", domain.ErrRateLimited, platform)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("%w: %s: status %d: %s", domain.ErrMetricsUnavailable, platform, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("%w: %s: decode response: %v", domain.ErrMetricsUnavailable, platform, err)
+	}
+	return nil
+}
+
+// postID is the platform id stored at registration, re-derived for posts
+// registered before it was kept.
+func postID(post domain.TrackedPost) (string, error) {
+	if post.PlatformPostID != "" {
+		return post.PlatformPostID, nil
+	}
+	return domain.PlatformPostID(post.Platform, post.PostURL)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// Fake is a deterministic fetcher for tests and local runs. Unless Set or
+// Fail was called for a post URL, views follow a saturating curve seeded by
+// the URL and the post's age, so repeated polls see plausible growth.
+type Fake struct {
+	platform string
+	nowFn    func() time.Time
+
+	mu      sync.Mutex
+	fixed   map[string]domain.PostMetrics
+	failing map[string]error
+	calls   map[string]int
+}
+
+func NewFake(platform string) *Fake {
+	return &Fake{platform: platform, nowFn: func() time.Time { return time.Now().UTC() }, fixed: map[string]domain.PostMetrics{}, failing: map[string]error{}, calls: map[string]int{}}
+}
+
+func (f *Fake) Platform() string { return f.platform }
+
+// Set pins the metrics returned for postURL.
+func (f *Fake) Set(postURL string, m domain.PostMetrics) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fixed[postURL] = m
+	delete(f.failing, postURL)
+}
+
+// Fail makes fetches of postURL return err until Set is called.
+func (f *Fake) Fail(postURL string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing[postURL] = err
+}
+
+// Calls reports how often postURL was fetched.
+func (f *Fake) Calls(postURL string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[postURL]
+}
+
+func (f *Fake) FetchMetrics(_ context.Context, post domain.TrackedPost) (domain.PostMetrics, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[post.PostURL]++
+	if err, ok := f.failing[post.PostURL]; ok {
+		return domain.PostMetrics{}, err
+	}
+	if m, ok := f.fixed[post.PostURL]; ok {
+		return m, nil
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(post.PostURL))
+	seed := int(h.Sum32()%900) + 100
+	hours := f.nowFn().Sub(post.CreatedAt).Hours()
+	if hours < 0 {
+		hours = 0
+	}
+	views := seed + int(float64(seed*20)*hours/(hours+24))
+	return domain.PostMetrics{Views: views, Likes: views / 12, Shares: views / 150, Comments: views / 80}, nil
+}
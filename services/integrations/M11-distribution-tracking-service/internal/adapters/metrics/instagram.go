@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// instagramMediaPages bounds how many pages of the creator's recent media
+// are scanned for the tracked post.
+const instagramMediaPages = 4
+
+// Instagram reads like and comment counts through the Graph API's business
+// discovery, queried from the platform's own business account. The post's
+// oEmbed names its author; business discovery then lists the author's recent
+// media. Meta exposes no view count for other accounts' media, so Views is
+// always zero; snapshots mark it with ViewsReported (see domain.ReportsViews).
+type Instagram struct {
+	accessToken       string
+	businessAccountID string
+	baseURL           string
+	client            *http.Client
+}
+
+func NewInstagram(accessToken, businessAccountID, baseURL string, timeout time.Duration) *Instagram {
+	if baseURL == "" {
+		baseURL = "https://graph.facebook.com/v19.0"
+	}
+	return &Instagram{accessToken: accessToken, businessAccountID: businessAccountID, baseURL: strings.TrimRight(baseURL, "/"), client: newHTTPClient(timeout)}
+}
+
+func (i *Instagram) Platform() string { return "instagram" }
+
+func (i *Instagram) FetchMetrics(ctx context.Context, post domain.TrackedPost) (domain.PostMetrics, error) {
+	shortcode, err := postID(post)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	author, err := i.author(ctx, post.PostURL)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	after := ""
+	for page := 0; page < instagramMediaPages; page++ {
+		media := "media.limit(50)"
+		if after != "" {
+			media = "media.limit(50).after(" + after + ")"
+		}
+		fields := "business_discovery.username(" + author + "){" + media + "{permalink,like_count,comments_count}}"
+		req, err := i.request(ctx, "/"+url.PathEscape(i.businessAccountID)+"?fields="+url.QueryEscape(fields))
+		if err != nil {
+			return domain.PostMetrics{}, err
+		}
+		var out struct {
+			BusinessDiscovery struct {
+				Media struct {
+					Data []struct {
+						Permalink     string `json:"permalink"`
+						LikeCount     int    `json:"like_count"`
+						CommentsCount int    `json:"comments_count"`
+					} `json:"data"`
+					Paging struct {
+						Cursors struct {
+							After string `json:"after"`
+						} `json:"cursors"`
+					} `json:"paging"`
+				} `json:"media"`
+			} `json:"business_discovery"`
+		}
+		if err := doJSON(i.client, "instagram", req, &out); err != nil {
+			return domain.PostMetrics{}, err
+		}
+		for _, m := range out.BusinessDiscovery.Media.Data {
+			if code, err := domain.PlatformPostID("instagram", m.Permalink); err == nil && code == shortcode {
+				return domain.PostMetrics{Likes: m.LikeCount, Comments: m.CommentsCount}, nil
+			}
+		}
+		after = out.BusinessDiscovery.Media.Paging.Cursors.After
+		if after == "" {
+			break
+		}
+	}
+	// Only the newest pages are scanned, so an older post is not found
+	// rather than gone.
+	return domain.PostMetrics{}, fmt.Errorf("%w: instagram media %s not among @%s's recent posts", domain.ErrPostNotFound, shortcode, author)
+}
+
+// author resolves the username that published postURL.
+func (i *Instagram) author(ctx context.Context, postURL string) (string, error) {
+	req, err := i.request(ctx, "/instagram_oembed?fields=author_name&url="+url.QueryEscape(postURL))
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		AuthorName string `json:"author_name"`
+	}
+	if err := doJSON(i.client, "instagram", req, &out); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(out.AuthorName) == "" {
+		return "", fmt.Errorf("%w: instagram oembed has no author for %s", domain.ErrPostNotFound, postURL)
+	}
+	return strings.TrimSpace(out.AuthorName), nil
+}
+
+func (i *Instagram) request(ctx context.Context, pathAndQuery string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.baseURL+pathAndQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+i.accessToken)
+	return req, nil
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// TikTok reads video counters from the TikTok Research API with a
+// client-credentials access token.
+type TikTok struct {
+	accessToken string
+	baseURL     string
+	client      *http.Client
+	nowFn       func() time.Time
+}
+
+func NewTikTok(accessToken, baseURL string, timeout time.Duration) *TikTok {
+	if baseURL == "" {
+		baseURL = "https://open.tiktokapis.com"
+	}
+	return &TikTok{accessToken: accessToken, baseURL: strings.TrimRight(baseURL, "/"), client: newHTTPClient(timeout), nowFn: func() time.Time { return time.Now().UTC() }}
+}
+
+func (t *TikTok) Platform() string { return "tiktok" }
+
+func (t *TikTok) FetchMetrics(ctx context.Context, post domain.TrackedPost) (domain.PostMetrics, error) {
+	id, err := postID(post)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	// Queries need a date range of at most 30 days; the post was published
+	// before it was registered here.
+	end := t.nowFn()
+	start := post.CreatedAt.AddDate(0, 0, -7)
+	if end.Sub(start) > 30*24*time.Hour {
+		start = end.AddDate(0, 0, -30)
+	}
+	body, _ := json.Marshal(map[string]any{
+		"query":      map[string]any{"and": []any{map[string]any{"operation": "EQ", "field_name": "video_id", "field_values": []string{id}}}},
+		"start_date": start.Format("20060102"),
+		"end_date":   end.Format("20060102"),
+		"max_count":  1,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/v2/research/video/query/?fields=id,view_count,like_count,comment_count,share_count", bytes.NewReader(body))
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	var out struct {
+		Data struct {
+			Videos []struct {
+				ViewCount    int `json:"view_count"`
+				LikeCount    int `json:"like_count"`
+				CommentCount int `json:"comment_count"`
+				ShareCount   int `json:"share_count"`
+			} `json:"videos"`
+		} `json:"data"`
+	}
+	if err := doJSON(t.client, "tiktok", req, &out); err != nil {
+		return domain.PostMetrics{}, err
+	}
+	// A miss may only mean the video was published outside the queried
+	// window, so it is never taken as deletion.
+	if len(out.Data.Videos) == 0 {
+		return domain.PostMetrics{}, fmt.Errorf("%w: tiktok video %s", domain.ErrPostNotFound, id)
+	}
+	v := out.Data.Videos[0]
+	return domain.PostMetrics{Views: v.ViewCount, Likes: v.LikeCount, Shares: v.ShareCount, Comments: v.CommentCount}, nil
+}
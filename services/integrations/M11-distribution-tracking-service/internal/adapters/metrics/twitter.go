@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// Twitter reads public metrics of posts on X with an app-only bearer token.
+type Twitter struct {
+	bearerToken string
+	baseURL     string
+	client      *http.Client
+}
+
+func NewTwitter(bearerToken, baseURL string, timeout time.Duration) *Twitter {
+	if baseURL == "" {
+		baseURL = "https://api.x.com"
+	}
+	return &Twitter{bearerToken: bearerToken, baseURL: strings.TrimRight(baseURL, "/"), client: newHTTPClient(timeout)}
+}
+
+func (t *Twitter) Platform() string { return "twitter" }
+
+func (t *Twitter) FetchMetrics(ctx context.Context, post domain.TrackedPost) (domain.PostMetrics, error) {
+	id, err := postID(post)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+"/2/tweets/"+id+"?tweet.fields=public_metrics", nil)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.bearerToken)
+	var out struct {
+		Data struct {
+			PublicMetrics struct {
+				ImpressionCount int `json:"impression_count"`
+				LikeCount       int `json:"like_count"`
+				RetweetCount    int `json:"retweet_count"`
+				QuoteCount      int `json:"quote_count"`
+				ReplyCount      int `json:"reply_count"`
+			} `json:"public_metrics"`
+		} `json:"data"`
+		Errors []struct {
+			Type   string `json:"type"`
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := doJSON(t.client, "twitter", req, &out); err != nil {
+		return domain.PostMetrics{}, err
+	}
+	// Deleted and protected posts come back as 200 with a resource error.
+	if len(out.Errors) > 0 {
+		switch kind := out.Errors[0].Type; {
+		case strings.HasSuffix(kind, "/resource-not-found"), strings.HasSuffix(kind, "/not-authorized-for-resource"):
+			return domain.PostMetrics{}, fmt.Errorf("%w: twitter: %s", domain.ErrPostGone, out.Errors[0].Detail)
+		default:
+			return domain.PostMetrics{}, fmt.Errorf("%w: twitter: %s", domain.ErrMetricsUnavailable, kind)
+		}
+	}
+	m := out.Data.PublicMetrics
+	return domain.PostMetrics{Views: m.ImpressionCount, Likes: m.LikeCount, Shares: m.RetweetCount + m.QuoteCount, Comments: m.ReplyCount}, nil
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// YouTube reads video statistics from the YouTube Data API with an API
+// key. A videos.list call costs one quota unit.
+type YouTube struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewYouTube(apiKey, baseURL string, timeout time.Duration) *YouTube {
+	if baseURL == "" {
+		baseURL = "https://www.googleapis.com"
+	}
+	return &YouTube{apiKey: apiKey, baseURL: strings.TrimRight(baseURL, "/"), client: newHTTPClient(timeout)}
+}
+
+func (y *YouTube) Platform() string { return "youtube" }
+
+func (y *YouTube) FetchMetrics(ctx context.Context, post domain.TrackedPost) (domain.PostMetrics, error) {
+	id, err := postID(post)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	q := url.Values{"part": {"statistics"}, "id": {id}, "key": {y.apiKey}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, y.baseURL+"/youtube/v3/videos?"+q.Encode(), nil)
+	if err != nil {
+		return domain.PostMetrics{}, err
+	}
+	var out struct {
+		Items []struct {
+			Statistics struct {
+				ViewCount    string `json:"viewCount"`
+				LikeCount    string `json:"likeCount"`
+				CommentCount string `json:"commentCount"`
+			} `json:"statistics"`
+		} `json:"items"`
+	}
+	if err := doJSON(y.client, "youtube", req, &out); err != nil {
+		return domain.PostMetrics{}, err
+	}
+	// Deleted and private videos are simply absent from the result; the id
+	// was validated at registration, so absence is the API's answer for them.
+	if len(out.Items) == 0 {
+		return domain.PostMetrics{}, fmt.Errorf("%w: youtube video %s", domain.ErrPostGone, id)
+	}
+	st := out.Items[0].Statistics
+	views, _ := strconv.Atoi(st.ViewCount)
+	likes, _ := strconv.Atoi(st.LikeCount)
+	comments, _ := strconv.Atoi(st.CommentCount)
+	// The Data API does not expose share counts.
+	return domain.PostMetrics{Views: views, Likes: likes, Comments: comments}, nil
+}
@@ -70,7 +70,7 @@ func (r *TrackedPostRepository) FindByUserPlatformURL(_ context.Context, userID,
 	}
 	return domain.TrackedPost{}, domain.ErrNotFound
 }
-func (r *TrackedPostRepository) ListPollCandidates(_ context.Context, before time.Time, limit int) ([]domain.TrackedPost, error) {
+func (r *TrackedPostRepository) ListPollCandidates(_ context.Context, now time.Time, limit int) ([]domain.TrackedPost, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	items := make([]domain.TrackedPost, 0)
@@ -78,11 +78,22 @@ func (r *TrackedPostRepository) ListPollCandidates(_ context.Context, before tim
 		if row.Status == domain.TrackedPostStatusArchived {
 			continue
 		}
-		if row.LastPolledAt == nil || row.LastPolledAt.Before(before) {
+		if row.NextPollAt == nil || !row.NextPollAt.After(now) {
 			items = append(items, row)
 		}
 	}
-	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+	due := func(p domain.TrackedPost) time.Time {
+		if p.NextPollAt == nil {
+			return time.Time{}
+		}
+		return *p.NextPollAt
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !due(items[i]).Equal(due(items[j])) {
+			return due(items[i]).Before(due(items[j]))
+		}
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
 	if limit > 0 && len(items) > limit {
 		items = items[:limit]
 	}
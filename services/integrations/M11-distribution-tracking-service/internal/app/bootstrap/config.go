@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -18,6 +19,22 @@ type Config struct {
 	ConsumerPollInterval time.Duration
 	PollCadence          time.Duration
 	OutboxFlushBatchSize int
+	MinPollInterval      time.Duration
+	MaxPollInterval      time.Duration
+	FreshPostWindow      time.Duration
+	PlatformQuotas       map[string]int
+	// FakeMetrics serves platforms without credentials from the
+	// deterministic fake fetcher; never enable it where rewards are paid.
+	FakeMetrics        bool
+	YouTubeAPIKey      string
+	TwitterBearerToken string
+	TikTokAccessToken  string
+	// InstagramAccessToken and InstagramBusinessAccountID are the platform's
+	// own Instagram business account, used for business discovery.
+	InstagramAccessToken       string
+	InstagramBusinessAccountID string
+	// AnomalyThreshold is the suspicion score at which posts are flagged.
+	AnomalyThreshold float64
 }
 
 type configFile struct {
@@ -26,10 +43,20 @@ type configFile struct {
 		HTTPPort int    `yaml:"http_port"`
 		GRPCPort int    `yaml:"grpc_port"`
 	} `yaml:"service"`
+	Metrics struct {
+		Fake           bool           `yaml:"fake"`
+		MinPollMinutes int            `yaml:"min_poll_minutes"`
+		MaxPollMinutes int            `yaml:"max_poll_minutes"`
+		FreshPostHours int            `yaml:"fresh_post_hours"`
+		QuotasPerHour  map[string]int `yaml:"quotas_per_hour"`
+	} `yaml:"metrics"`
+	Anomaly struct {
+		Threshold float64 `yaml:"threshold"`
+	} `yaml:"anomaly"`
 }
 
 func LoadConfig(path string) (Config, error) {
-	cfg := Config{ServiceID: "M11-Distribution-Tracking-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second, PollCadence: 6 * time.Hour, OutboxFlushBatchSize: 100}
+	cfg := Config{ServiceID: "M11-Distribution-Tracking-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second, PollCadence: 6 * time.Hour, OutboxFlushBatchSize: 100, MinPollInterval: 15 * time.Minute, MaxPollInterval: 24 * time.Hour, FreshPostWindow: 24 * time.Hour, PlatformQuotas: map[string]int{}}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
 		if err := yaml.Unmarshal(raw, &f); err != nil {
@@ -44,6 +71,20 @@ func LoadConfig(path string) (Config, error) {
 		if f.Service.GRPCPort > 0 {
 			cfg.GRPCPort = f.Service.GRPCPort
 		}
+		cfg.FakeMetrics = f.Metrics.Fake
+		if f.Metrics.MinPollMinutes > 0 {
+			cfg.MinPollInterval = time.Duration(f.Metrics.MinPollMinutes) * time.Minute
+		}
+		if f.Metrics.MaxPollMinutes > 0 {
+			cfg.MaxPollInterval = time.Duration(f.Metrics.MaxPollMinutes) * time.Minute
+		}
+		if f.Metrics.FreshPostHours > 0 {
+			cfg.FreshPostWindow = time.Duration(f.Metrics.FreshPostHours) * time.Hour
+		}
+		for platform, limit := range f.Metrics.QuotasPerHour {
+			cfg.PlatformQuotas[platform] = limit
+		}
+		cfg.AnomalyThreshold = f.Anomaly.Threshold
 	}
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
@@ -52,6 +93,19 @@ func LoadConfig(path string) (Config, error) {
 	cfg.ConsumerPollInterval = time.Duration(envInt("CONSUMER_POLL_SECONDS", int(cfg.ConsumerPollInterval.Seconds()))) * time.Second
 	cfg.PollCadence = time.Duration(envInt("POLL_CADENCE_MINUTES", int(cfg.PollCadence.Minutes()))) * time.Minute
 	cfg.OutboxFlushBatchSize = envInt("OUTBOX_FLUSH_BATCH_SIZE", cfg.OutboxFlushBatchSize)
+	if raw := strings.TrimSpace(os.Getenv("METRICS_FAKE")); raw != "" {
+		cfg.FakeMetrics, _ = strconv.ParseBool(raw)
+	}
+	cfg.YouTubeAPIKey = os.Getenv("YOUTUBE_API_KEY")
+	cfg.TwitterBearerToken = os.Getenv("X_BEARER_TOKEN")
+	cfg.TikTokAccessToken = os.Getenv("TIKTOK_RESEARCH_TOKEN")
+	cfg.InstagramAccessToken = os.Getenv("INSTAGRAM_ACCESS_TOKEN")
+	cfg.InstagramBusinessAccountID = os.Getenv("INSTAGRAM_BUSINESS_ACCOUNT_ID")
+	if raw := strings.TrimSpace(os.Getenv("ANOMALY_THRESHOLD")); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			cfg.AnomalyThreshold = v
+		}
+	}
 	return cfg, nil
 }
 func envInt(name string, fallback int) int {
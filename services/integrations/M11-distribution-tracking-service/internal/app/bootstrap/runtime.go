@@ -12,52 +12,92 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/http"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/metrics"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/ports"
 	"google.golang.org/grpc"
 )
 
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	domainPub := eventadapter.NewMemoryDomainPublisher()
 	analyticsPub := eventadapter.NewMemoryAnalyticsPublisher()
 	dlqPub := eventadapter.NewLoggingDLQPublisher()
 	consumer := eventadapter.NewMemoryConsumer()
-	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval, PollCadence: cfg.PollCadence, OutboxFlushBatchSize: cfg.OutboxFlushBatchSize}, Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub, Analytics: analyticsPub, DLQ: dlqPub})
+	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval, PollCadence: cfg.PollCadence, OutboxFlushBatchSize: cfg.OutboxFlushBatchSize, MinPollInterval: cfg.MinPollInterval, MaxPollInterval: cfg.MaxPollInterval, FreshPostWindow: cfg.FreshPostWindow, PlatformQuotas: cfg.PlatformQuotas, AnomalyThreshold: cfg.AnomalyThreshold}, Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub, Analytics: analyticsPub, DLQ: dlqPub, Fetchers: newFetchers(cfg, logger)})
 	handler := httpadapter.NewHandler(svc)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewDistributionTrackingInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		return nil, err
 	}
 	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+}
+
+// newFetchers builds a metrics fetcher per platform with credentials. With
+// FakeMetrics the remaining platforms use the deterministic fake.
+func newFetchers(cfg Config, logger *slog.Logger) []ports.MetricsFetcher {
+	const timeout = 10 * time.Second
+	configured := map[string]ports.MetricsFetcher{}
+	if cfg.YouTubeAPIKey != "" {
+		configured["youtube"] = metrics.NewYouTube(cfg.YouTubeAPIKey, "", timeout)
+	}
+	if cfg.TwitterBearerToken != "" {
+		configured["twitter"] = metrics.NewTwitter(cfg.TwitterBearerToken, "", timeout)
+	}
+	if cfg.TikTokAccessToken != "" {
+		configured["tiktok"] = metrics.NewTikTok(cfg.TikTokAccessToken, "", timeout)
+	}
+	if cfg.InstagramAccessToken != "" && cfg.InstagramBusinessAccountID != "" {
+		configured["instagram"] = metrics.NewInstagram(cfg.InstagramAccessToken, cfg.InstagramBusinessAccountID, "", timeout)
+	}
+	out := make([]ports.MetricsFetcher, 0, 5)
+	for _, platform := range []string{"youtube", "twitter", "tiktok", "instagram", "facebook"} {
+		if f, ok := configured[platform]; ok {
+			out = append(out, f)
+			continue
+		}
+		if cfg.FakeMetrics {
+			logger.Warn("using fake metrics fetcher", "platform", platform)
+			out = append(out, metrics.NewFake(platform))
+		}
+	}
+	return out
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -84,6 +124,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -97,3 +138,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
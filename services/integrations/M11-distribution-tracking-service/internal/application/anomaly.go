@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// GetTrackedPostAnomaly analyses the full snapshot history of a post on
+// demand.
+func (s *Service) GetTrackedPostAnomaly(ctx context.Context, actor Actor, trackedPostID string) (domain.AnomalyReport, error) {
+	post, snaps, err := s.GetTrackedPostMetrics(ctx, actor, trackedPostID)
+	if err != nil {
+		return domain.AnomalyReport{}, err
+	}
+	report := domain.AnalyzeSnapshots(post.Platform, snaps, s.anomaly)
+	report.TrackedPostID = post.TrackedPostID
+	report.AnalyzedAt = s.nowFn()
+	return report, nil
+}
+
+// analyzePost re-scores post after a new snapshot and records the score on
+// it. It returns the report when the post should be announced as anomalous:
+// it is suspicious and its score rose since the last analysis, so a post is
+// not re-announced on every poll while it stays flagged.
+func (s *Service) analyzePost(ctx context.Context, post *domain.TrackedPost, now time.Time) (*domain.AnomalyReport, error) {
+	snaps, err := s.snapshots.ListByTrackedPostID(ctx, post.TrackedPostID)
+	if err != nil {
+		return nil, err
+	}
+	report := domain.AnalyzeSnapshots(post.Platform, snaps, s.anomaly)
+	report.TrackedPostID = post.TrackedPostID
+	report.AnalyzedAt = now
+	previous := post.AnomalyScore
+	post.AnomalyScore = report.Score
+	if !report.Suspicious || report.Score <= previous {
+		return nil, nil
+	}
+	if post.AnomalyFlaggedAt == nil {
+		post.AnomalyFlaggedAt = &now
+	}
+	return &report, nil
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/ports"
+)
+
+// RunPollCycle fetches metrics for posts whose next poll is due, most
+// overdue first. Fetch failures are recorded on the post and never stop the
+// cycle. Posts on a platform whose hourly budget is spent wait for the next
+// window; posts on a platform without a fetcher are re-checked every
+// MaxPollInterval. Only storage errors are returned, joined after every
+// post was attempted.
+func (s *Service) RunPollCycle(ctx context.Context) error {
+	if s.posts == nil || s.snapshots == nil {
+		return nil
+	}
+	now := s.nowFn()
+	posts, err := s.posts.ListPollCandidates(ctx, now, s.cfg.PollBatchSize)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, post := range posts {
+		if post.Status == domain.TrackedPostStatusArchived {
+			continue
+		}
+		platform := domain.PlatformKey(post.Platform)
+		fetcher, ok := s.fetchers[platform]
+		if !ok {
+			if err := s.deferPoll(ctx, post, now.Add(s.cadence.Max), "no metrics fetcher for "+platform); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if allowed, retryAt := s.quota.take(platform, now); !allowed {
+			if err := s.deferPoll(ctx, post, retryAt, ""); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := s.pollOnePost(ctx, fetcher, post); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) pollOnePost(ctx context.Context, fetcher ports.MetricsFetcher, post domain.TrackedPost) error {
+	metrics, err := fetcher.FetchMetrics(ctx, post)
+	now := s.nowFn()
+	if err != nil {
+		return s.recordPollFailure(ctx, post, err)
+	}
+	var prev *domain.MetricSnapshot
+	if latest, err := s.snapshots.LatestByTrackedPostID(ctx, post.TrackedPostID); err == nil {
+		prev = &latest
+	}
+	snap := domain.MetricSnapshot{SnapshotID: "ms-" + uuid.NewString(), TrackedPostID: post.TrackedPostID, Platform: post.Platform, Views: metrics.Views, Likes: metrics.Likes, Shares: metrics.Shares, Comments: metrics.Comments, PolledAt: now, ViewsReported: domain.ReportsViews(post.Platform)}
+	if err := s.snapshots.Append(ctx, snap); err != nil {
+		return err
+	}
+	next := now.Add(s.cadence.Next(post, prev, snap))
+	post.LastPolledAt = &now
+	post.NextPollAt = &next
+	post.PollFailures = 0
+	post.LastPollError = ""
+	post.UpdatedAt = now
+	if post.Status == domain.TrackedPostStatusPendingAttribution && strings.TrimSpace(post.DistributionItemID) != "" {
+		post.Status = domain.TrackedPostStatusActive
+	}
+	report, err := s.analyzePost(ctx, &post, now)
+	if err != nil {
+		return err
+	}
+	if err := s.posts.Update(ctx, post); err != nil {
+		return err
+	}
+	if err := s.enqueueTrackingMetricsUpdated(ctx, snap, post, now); err != nil {
+		return err
+	}
+	if report != nil {
+		return s.enqueueTrackingAnomalyDetected(ctx, post, *report)
+	}
+	return nil
+}
+
+// recordPollFailure archives posts the platform reported deleted or
+// private, defers the platform when it rate-limited us and otherwise backs
+// the post off exponentially. A lookup miss (ErrPostNotFound) is retried
+// like any other failure.
+func (s *Service) recordPollFailure(ctx context.Context, post domain.TrackedPost, fetchErr error) error {
+	now := s.nowFn()
+	post.UpdatedAt = now
+	post.LastPollError = fetchErr.Error()
+	switch {
+	case errors.Is(fetchErr, domain.ErrPostGone):
+		post.Status = domain.TrackedPostStatusArchived
+		post.NextPollAt = nil
+		if err := s.posts.Update(ctx, post); err != nil {
+			return err
+		}
+		return s.enqueueTrackingPostArchived(ctx, post, now)
+	case errors.Is(fetchErr, domain.ErrRateLimited):
+		retryAt := s.quota.exhaust(domain.PlatformKey(post.Platform), now)
+		post.NextPollAt = &retryAt
+		return s.posts.Update(ctx, post)
+	default:
+		post.PollFailures++
+		next := now.Add(s.cadence.Backoff(post.PollFailures))
+		post.NextPollAt = &next
+		return s.posts.Update(ctx, post)
+	}
+}
+
+// deferPoll moves a post's next poll without counting a failure.
+func (s *Service) deferPoll(ctx context.Context, post domain.TrackedPost, at time.Time, reason string) error {
+	post.NextPollAt = &at
+	if reason != "" {
+		post.LastPollError = reason
+	}
+	post.UpdatedAt = s.nowFn()
+	return s.posts.Update(ctx, post)
+}
@@ -26,6 +26,10 @@ func (s *Service) ValidatePost(ctx context.Context, actor Actor, in ValidatePost
 	if uid == "" || !domain.IsValidPlatform(platform) || !valid {
 		return contracts.ValidatePostResponse{}, domain.ErrInvalidInput
 	}
+	postID, err := domain.PlatformPostID(platform, normURL)
+	if err != nil {
+		return contracts.ValidatePostResponse{}, domain.ErrInvalidInput
+	}
 	requestHash := hashJSON(map[string]string{"op": "validate_post", "user_id": uid, "platform": platform, "post_url": normURL})
 	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return contracts.ValidatePostResponse{}, err
@@ -38,7 +42,7 @@ func (s *Service) ValidatePost(ctx context.Context, actor Actor, in ValidatePost
 	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return contracts.ValidatePostResponse{}, err
 	}
-	out := contracts.ValidatePostResponse{Valid: true, Platform: platform, NormalizedURL: normURL}
+	out := contracts.ValidatePostResponse{Valid: true, Platform: platform, NormalizedURL: normURL, PlatformPostID: postID}
 	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, out)
 	return out, nil
 }
@@ -56,6 +60,10 @@ func (s *Service) RegisterPost(ctx context.Context, actor Actor, in RegisterPost
 	if uid == "" || !domain.IsValidPlatform(platform) || !valid {
 		return domain.TrackedPost{}, false, domain.ErrInvalidInput
 	}
+	postID, err := domain.PlatformPostID(platform, normURL)
+	if err != nil {
+		return domain.TrackedPost{}, false, domain.ErrInvalidInput
+	}
 	requestHash := hashJSON(map[string]string{"op": "register_post", "user_id": uid, "platform": platform, "post_url": normURL, "distribution_item_id": strings.TrimSpace(in.DistributionItemID), "campaign_id": strings.TrimSpace(in.CampaignID)})
 	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.TrackedPost{}, false, err
@@ -78,7 +86,7 @@ func (s *Service) RegisterPost(ctx context.Context, actor Actor, in RegisterPost
 	if attributionPending {
 		status = domain.TrackedPostStatusPendingAttribution
 	}
-	post := domain.TrackedPost{TrackedPostID: "tp-" + uuid.NewString(), UserID: uid, Platform: platform, PostURL: normURL, DistributionItemID: strings.TrimSpace(in.DistributionItemID), CampaignID: strings.TrimSpace(in.CampaignID), Status: status, ValidationStatus: "validated", CreatedAt: now, UpdatedAt: now}
+	post := domain.TrackedPost{TrackedPostID: "tp-" + uuid.NewString(), UserID: uid, Platform: platform, PostURL: normURL, PlatformPostID: postID, DistributionItemID: strings.TrimSpace(in.DistributionItemID), CampaignID: strings.TrimSpace(in.CampaignID), Status: status, ValidationStatus: "validated", CreatedAt: now, UpdatedAt: now}
 	if err := s.posts.Create(ctx, post); err != nil {
 		return domain.TrackedPost{}, false, err
 	}
@@ -113,51 +121,6 @@ func (s *Service) GetTrackedPostMetrics(ctx context.Context, actor Actor, tracke
 	return post, snaps, err
 }
 
-func (s *Service) RunPollCycle(ctx context.Context) error {
-	if s.posts == nil || s.snapshots == nil {
-		return nil
-	}
-	before := s.nowFn().Add(-s.cfg.PollCadence)
-	posts, err := s.posts.ListPollCandidates(ctx, before, 100)
-	if err != nil {
-		return err
-	}
-	for _, post := range posts {
-		if post.Status == domain.TrackedPostStatusArchived {
-			continue
-		}
-		if err := s.pollOnePost(ctx, post); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (s *Service) pollOnePost(ctx context.Context, post domain.TrackedPost) error {
-	now := s.nowFn()
-	latest, err := s.snapshots.LatestByTrackedPostID(ctx, post.TrackedPostID)
-	views, likes, shares, comments := 100, 10, 2, 1
-	if err == nil {
-		views = latest.Views + 50
-		likes = latest.Likes + 5
-		shares = latest.Shares + 1
-		comments = latest.Comments + 1
-	}
-	snap := domain.MetricSnapshot{SnapshotID: "ms-" + uuid.NewString(), TrackedPostID: post.TrackedPostID, Platform: post.Platform, Views: views, Likes: likes, Shares: shares, Comments: comments, PolledAt: now}
-	if err := s.snapshots.Append(ctx, snap); err != nil {
-		return err
-	}
-	post.LastPolledAt = &now
-	post.UpdatedAt = now
-	if post.Status == domain.TrackedPostStatusPendingAttribution && strings.TrimSpace(post.DistributionItemID) != "" {
-		post.Status = domain.TrackedPostStatusActive
-	}
-	if err := s.posts.Update(ctx, post); err != nil {
-		return err
-	}
-	return s.enqueueTrackingMetricsUpdated(ctx, snap, post, now)
-}
-
 func normalizePostURL(raw string) (string, bool) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -168,7 +131,11 @@ func normalizePostURL(raw string) (string, bool) {
 		return "", false
 	}
 	u.Fragment = ""
-	return strings.ToLower(u.String()), true
+	// Only scheme and host are case-insensitive; platform post ids in the
+	// path and query (YouTube's v=) are not.
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), true
 }
 
 func canActForUser(actor Actor, userID string) bool {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/ports"
@@ -38,8 +39,8 @@ func (s *Service) enqueueTrackingMetricsUpdated(ctx context.Context, snap domain
 	if s.outbox == nil {
 		return nil
 	}
-	payload, _ := json.Marshal(contracts.TrackingMetricsUpdatedPayload{TrackedPostID: snap.TrackedPostID, Platform: snap.Platform, Views: snap.Views, Likes: snap.Likes, Shares: snap.Shares, Comments: snap.Comments, PolledAt: snap.PolledAt.UTC().Format(time.RFC3339)})
-	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: domain.EventTrackingMetricsUpdated, EventClass: domain.CanonicalEventClass(domain.EventTrackingMetricsUpdated), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(domain.EventTrackingMetricsUpdated), PartitionKey: post.TrackedPostID, SourceService: s.cfg.ServiceName, TraceID: nonEmptyTrace("poll-" + post.TrackedPostID), SchemaVersion: "v1", Data: payload}
+	payload, _ := json.Marshal(contracts.TrackingMetricsUpdatedPayload{TrackedPostID: snap.TrackedPostID, Platform: snap.Platform, Views: snap.Views, Likes: snap.Likes, Shares: snap.Shares, Comments: snap.Comments, PolledAt: snap.PolledAt.UTC().Format(time.RFC3339), ViewsReported: snap.ViewsReported})
+	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: domain.EventTrackingMetricsUpdated, EventClass: domain.CanonicalEventClass(domain.EventTrackingMetricsUpdated), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(domain.EventTrackingMetricsUpdated), PartitionKey: post.TrackedPostID, SourceService: s.cfg.ServiceName, TraceID: observability.TraceParent(ctx, "poll-"+post.TrackedPostID), SchemaVersion: "v1", Data: payload}
 	rec := ports.OutboxRecord{RecordID: uuid.NewString(), EventClass: env.EventClass, Envelope: env, CreatedAt: now}
 	return s.outbox.Enqueue(ctx, rec)
 }
@@ -49,7 +50,22 @@ func (s *Service) enqueueTrackingPostArchived(ctx context.Context, post domain.T
 		return nil
 	}
 	payload, _ := json.Marshal(contracts.TrackingPostArchivedPayload{TrackedPostID: post.TrackedPostID, ArchivedAt: now.UTC().Format(time.RFC3339)})
-	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: domain.EventTrackingPostArchived, EventClass: domain.CanonicalEventClass(domain.EventTrackingPostArchived), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(domain.EventTrackingPostArchived), PartitionKey: post.TrackedPostID, SourceService: s.cfg.ServiceName, TraceID: nonEmptyTrace("archive-" + post.TrackedPostID), SchemaVersion: "v1", Data: payload}
+	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: domain.EventTrackingPostArchived, EventClass: domain.CanonicalEventClass(domain.EventTrackingPostArchived), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(domain.EventTrackingPostArchived), PartitionKey: post.TrackedPostID, SourceService: s.cfg.ServiceName, TraceID: observability.TraceParent(ctx, "archive-"+post.TrackedPostID), SchemaVersion: "v1", Data: payload}
+	rec := ports.OutboxRecord{RecordID: uuid.NewString(), EventClass: env.EventClass, Envelope: env, CreatedAt: now}
+	return s.outbox.Enqueue(ctx, rec)
+}
+
+func (s *Service) enqueueTrackingAnomalyDetected(ctx context.Context, post domain.TrackedPost, report domain.AnomalyReport) error {
+	if s.outbox == nil {
+		return nil
+	}
+	reasons := make([]contracts.TrackingAnomalyReason, 0, len(report.Reasons))
+	for _, r := range report.Reasons {
+		reasons = append(reasons, contracts.TrackingAnomalyReason{Code: r.Code, Detail: r.Detail, Weight: r.Weight})
+	}
+	now := report.AnalyzedAt
+	payload, _ := json.Marshal(contracts.TrackingMetricsAnomalyDetectedPayload{TrackedPostID: post.TrackedPostID, UserID: post.UserID, Platform: post.Platform, CampaignID: post.CampaignID, DistributionItemID: post.DistributionItemID, SuspicionScore: report.Score, Reasons: reasons, VelocityViewsPerHour: report.ViewsPerHour, AccelerationViewsPerHourSq: report.Acceleration, LikeViewRatio: report.LikeViewRatio, CommentViewRatio: report.CommentViewRatio, SnapshotCount: report.SnapshotCount, DetectedAt: now.UTC().Format(time.RFC3339)})
+	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: domain.EventTrackingAnomaly, EventClass: domain.CanonicalEventClass(domain.EventTrackingAnomaly), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(domain.EventTrackingAnomaly), PartitionKey: post.TrackedPostID, SourceService: s.cfg.ServiceName, TraceID: observability.TraceParent(ctx, "anomaly-"+post.TrackedPostID), SchemaVersion: "v1", Data: payload}
 	rec := ports.OutboxRecord{RecordID: uuid.NewString(), EventClass: env.EventClass, Envelope: env, CreatedAt: now}
 	return s.outbox.Enqueue(ctx, rec)
 }
@@ -117,10 +133,3 @@ func validatePartitionKeyInvariant(event contracts.EventEnvelope, expectedPath s
 	}
 	return nil
 }
-
-func nonEmptyTrace(v string) string {
-	if strings.TrimSpace(v) == "" {
-		return uuid.NewString()
-	}
-	return v
-}
@@ -1,8 +1,10 @@
 package application
 
 import (
+	"sync"
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/ports"
 )
 
@@ -13,6 +15,17 @@ type Config struct {
 	ConsumerPollInterval time.Duration
 	PollCadence          time.Duration
 	OutboxFlushBatchSize int
+	// MinPollInterval and MaxPollInterval bound the adaptive cadence around
+	// PollCadence; FreshPostWindow is how long new posts poll at the minimum.
+	MinPollInterval time.Duration
+	MaxPollInterval time.Duration
+	FreshPostWindow time.Duration
+	PollBatchSize   int
+	// PlatformQuotas caps fetches per platform per hour; absent means
+	// unlimited.
+	PlatformQuotas map[string]int
+	// AnomalyThreshold is the suspicion score at which a post is flagged.
+	AnomalyThreshold float64
 }
 
 type Actor struct {
@@ -35,6 +48,10 @@ type Service struct {
 	domainEvents ports.DomainPublisher
 	analytics    ports.AnalyticsPublisher
 	dlq          ports.DLQPublisher
+	fetchers     map[string]ports.MetricsFetcher
+	cadence      domain.CadencePolicy
+	quota        *quotaTracker
+	anomaly      domain.AnomalyPolicy
 	nowFn        func() time.Time
 }
 
@@ -48,6 +65,7 @@ type Dependencies struct {
 	DomainEvents ports.DomainPublisher
 	Analytics    ports.AnalyticsPublisher
 	DLQ          ports.DLQPublisher
+	Fetchers     []ports.MetricsFetcher
 }
 
 func NewService(deps Dependencies) *Service {
@@ -70,5 +88,82 @@ func NewService(deps Dependencies) *Service {
 	if cfg.OutboxFlushBatchSize <= 0 {
 		cfg.OutboxFlushBatchSize = 100
 	}
-	return &Service{cfg: cfg, posts: deps.Posts, snapshots: deps.Snapshots, idempotency: deps.Idempotency, eventDedup: deps.EventDedup, outbox: deps.Outbox, domainEvents: deps.DomainEvents, analytics: deps.Analytics, dlq: deps.DLQ, nowFn: func() time.Time { return time.Now().UTC() }}
+	cadence := domain.DefaultCadencePolicy()
+	cadence.Base = cfg.PollCadence
+	if cfg.MinPollInterval > 0 {
+		cadence.Min = cfg.MinPollInterval
+	}
+	if cfg.MaxPollInterval > 0 {
+		cadence.Max = cfg.MaxPollInterval
+	}
+	if cfg.FreshPostWindow > 0 {
+		cadence.FreshWindow = cfg.FreshPostWindow
+	}
+	if cadence.Min > cadence.Base {
+		cadence.Min = cadence.Base
+	}
+	if cadence.Max < cadence.Base {
+		cadence.Max = cadence.Base
+	}
+	anomaly := domain.DefaultAnomalyPolicy()
+	if cfg.AnomalyThreshold > 0 {
+		anomaly.Threshold = cfg.AnomalyThreshold
+	}
+	if cfg.PollBatchSize <= 0 {
+		cfg.PollBatchSize = 100
+	}
+	fetchers := make(map[string]ports.MetricsFetcher, len(deps.Fetchers))
+	for _, f := range deps.Fetchers {
+		fetchers[domain.PlatformKey(f.Platform())] = f
+	}
+	return &Service{cfg: cfg, posts: deps.Posts, snapshots: deps.Snapshots, idempotency: deps.Idempotency, eventDedup: deps.EventDedup, outbox: deps.Outbox, domainEvents: deps.DomainEvents, analytics: deps.Analytics, dlq: deps.DLQ, fetchers: fetchers, cadence: cadence, quota: newQuotaTracker(cfg.PlatformQuotas, time.Hour), anomaly: anomaly, nowFn: func() time.Time { return time.Now().UTC() }}
+}
+
+// quotaTracker counts fetches per platform in fixed windows.
+type quotaTracker struct {
+	mu      sync.Mutex
+	limits  map[string]int
+	window  time.Duration
+	start   map[string]time.Time
+	used    map[string]int
+	blocked map[string]bool
+}
+
+func newQuotaTracker(limits map[string]int, window time.Duration) *quotaTracker {
+	q := &quotaTracker{limits: map[string]int{}, window: window, start: map[string]time.Time{}, used: map[string]int{}, blocked: map[string]bool{}}
+	for platform, limit := range limits {
+		q.limits[domain.PlatformKey(platform)] = limit
+	}
+	return q
+}
+
+// take reserves one fetch for platform. When the budget of the current
+// window is spent it returns false and the time the window resets.
+func (q *quotaTracker) take(platform string, now time.Time) (bool, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if now.Sub(q.start[platform]) >= q.window {
+		q.start[platform] = now
+		q.used[platform] = 0
+		delete(q.blocked, platform)
+	}
+	limit, limited := q.limits[platform]
+	if q.blocked[platform] || (limited && limit > 0 && q.used[platform] >= limit) {
+		return false, q.start[platform].Add(q.window)
+	}
+	q.used[platform]++
+	return true, time.Time{}
+}
+
+// exhaust blocks platform for the rest of the window after it rate-limited
+// us, returning when the window resets.
+func (q *quotaTracker) exhaust(platform string, now time.Time) time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if now.Sub(q.start[platform]) >= q.window {
+		q.start[platform] = now
+		q.used[platform] = 0
+	}
+	q.blocked[platform] = true
+	return q.start[platform].Add(q.window)
 }
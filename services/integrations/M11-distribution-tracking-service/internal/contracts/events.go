@@ -26,6 +26,7 @@ type TrackingMetricsUpdatedPayload struct {
 	Shares        int    `json:"shares"`
 	Comments      int    `json:"comments"`
 	PolledAt      string `json:"polled_at"`
+	ViewsReported bool   `json:"views_reported"`
 }
 
 type TrackingPostArchivedPayload struct {
@@ -33,6 +34,28 @@ type TrackingPostArchivedPayload struct {
 	ArchivedAt    string `json:"archived_at"`
 }
 
+type TrackingAnomalyReason struct {
+	Code   string  `json:"code"`
+	Detail string  `json:"detail"`
+	Weight float64 `json:"weight"`
+}
+
+type TrackingMetricsAnomalyDetectedPayload struct {
+	TrackedPostID              string                  `json:"tracked_post_id"`
+	UserID                     string                  `json:"user_id"`
+	Platform                   string                  `json:"platform"`
+	CampaignID                 string                  `json:"campaign_id,omitempty"`
+	DistributionItemID         string                  `json:"distribution_item_id,omitempty"`
+	SuspicionScore             float64                 `json:"suspicion_score"`
+	Reasons                    []TrackingAnomalyReason `json:"reasons"`
+	VelocityViewsPerHour       float64                 `json:"velocity_views_per_hour"`
+	AccelerationViewsPerHourSq float64                 `json:"acceleration_views_per_hour2"`
+	LikeViewRatio              float64                 `json:"like_view_ratio"`
+	CommentViewRatio           float64                 `json:"comment_view_ratio"`
+	SnapshotCount              int                     `json:"snapshot_count"`
+	DetectedAt                 string                  `json:"detected_at"`
+}
+
 type DLQRecord struct {
 	OriginalEvent EventEnvelope `json:"original_event"`
 	ErrorSummary  string        `json:"error_summary"`
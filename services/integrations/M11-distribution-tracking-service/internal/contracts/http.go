@@ -27,10 +27,11 @@ type ValidatePostRequest struct {
 }
 
 type ValidatePostResponse struct {
-	Valid         bool   `json:"valid"`
-	Platform      string `json:"platform"`
-	NormalizedURL string `json:"normalized_url"`
-	Reason        string `json:"reason,omitempty"`
+	Valid          bool   `json:"valid"`
+	Platform       string `json:"platform"`
+	NormalizedURL  string `json:"normalized_url"`
+	PlatformPostID string `json:"platform_post_id,omitempty"`
+	Reason         string `json:"reason,omitempty"`
 }
 
 type RegisterPostRequest struct {
@@ -42,17 +43,23 @@ type RegisterPostRequest struct {
 }
 
 type TrackedPostResponse struct {
-	TrackedPostID      string `json:"tracked_post_id"`
-	UserID             string `json:"user_id"`
-	Platform           string `json:"platform"`
-	PostURL            string `json:"post_url"`
-	DistributionItemID string `json:"distribution_item_id,omitempty"`
-	CampaignID         string `json:"campaign_id,omitempty"`
-	Status             string `json:"status"`
-	ValidationStatus   string `json:"validation_status"`
-	LastPolledAt       string `json:"last_polled_at,omitempty"`
-	CreatedAt          string `json:"created_at"`
-	UpdatedAt          string `json:"updated_at"`
+	TrackedPostID      string  `json:"tracked_post_id"`
+	UserID             string  `json:"user_id"`
+	Platform           string  `json:"platform"`
+	PostURL            string  `json:"post_url"`
+	PlatformPostID     string  `json:"platform_post_id,omitempty"`
+	DistributionItemID string  `json:"distribution_item_id,omitempty"`
+	CampaignID         string  `json:"campaign_id,omitempty"`
+	Status             string  `json:"status"`
+	ValidationStatus   string  `json:"validation_status"`
+	LastPolledAt       string  `json:"last_polled_at,omitempty"`
+	NextPollAt         string  `json:"next_poll_at,omitempty"`
+	PollFailures       int     `json:"poll_failures,omitempty"`
+	LastPollError      string  `json:"last_poll_error,omitempty"`
+	AnomalyScore       float64 `json:"anomaly_score,omitempty"`
+	AnomalyFlaggedAt   string  `json:"anomaly_flagged_at,omitempty"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
 }
 
 type MetricSnapshotResponse struct {
@@ -64,6 +71,7 @@ type MetricSnapshotResponse struct {
 	Shares        int    `json:"shares"`
 	Comments      int    `json:"comments"`
 	PolledAt      string `json:"polled_at"`
+	ViewsReported bool   `json:"views_reported"`
 }
 
 type MetricsListResponse struct {
@@ -71,3 +79,23 @@ type MetricsListResponse struct {
 	LastPolledAt  string                   `json:"last_polled_at,omitempty"`
 	Snapshots     []MetricSnapshotResponse `json:"snapshots"`
 }
+
+type AnomalyReasonResponse struct {
+	Code   string  `json:"code"`
+	Detail string  `json:"detail"`
+	Weight float64 `json:"weight"`
+}
+
+type AnomalyReportResponse struct {
+	TrackedPostID              string                  `json:"tracked_post_id"`
+	Platform                   string                  `json:"platform"`
+	SuspicionScore             float64                 `json:"suspicion_score"`
+	Suspicious                 bool                    `json:"suspicious"`
+	Reasons                    []AnomalyReasonResponse `json:"reasons"`
+	VelocityViewsPerHour       float64                 `json:"velocity_views_per_hour"`
+	AccelerationViewsPerHourSq float64                 `json:"acceleration_views_per_hour2"`
+	LikeViewRatio              float64                 `json:"like_view_ratio"`
+	CommentViewRatio           float64                 `json:"comment_view_ratio"`
+	SnapshotCount              int                     `json:"snapshot_count"`
+	AnalyzedAt                 string                  `json:"analyzed_at"`
+}
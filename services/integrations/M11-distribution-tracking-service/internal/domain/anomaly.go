@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	AnomalyViewSpike              = "view_spike"
+	AnomalySpikeWithoutEngagement = "spike_without_engagement"
+	AnomalyAcceleration           = "acceleration"
+	AnomalyLowLikeRatio           = "low_like_ratio"
+	AnomalyLowCommentRatio        = "low_comment_ratio"
+	AnomalyViewsDecreased         = "views_decreased"
+)
+
+// EngagementBaseline is the typical like/view and comment/view ratio of
+// organic posts on a platform.
+type EngagementBaseline struct {
+	LikeViewRatio    float64
+	CommentViewRatio float64
+}
+
+var platformBaselines = map[string]EngagementBaseline{
+	"tiktok":    {LikeViewRatio: 0.06, CommentViewRatio: 0.005},
+	"instagram": {LikeViewRatio: 0.05, CommentViewRatio: 0.004},
+	"youtube":   {LikeViewRatio: 0.035, CommentViewRatio: 0.003},
+	"twitter":   {LikeViewRatio: 0.015, CommentViewRatio: 0.002},
+	"facebook":  {LikeViewRatio: 0.03, CommentViewRatio: 0.003},
+}
+
+// BaselineFor returns the engagement baseline of platform, falling back to
+// the most conservative one.
+func BaselineFor(platform string) EngagementBaseline {
+	if b, ok := platformBaselines[PlatformKey(platform)]; ok {
+		return b
+	}
+	return platformBaselines["twitter"]
+}
+
+// AnomalyPolicy tunes AnalyzeSnapshots. Reason weights add up to the
+// suspicion score, capped at 1.
+type AnomalyPolicy struct {
+	// MinViews is the view count below which ratios are not judged.
+	MinViews int
+	// SpikeFactor flags an interval whose velocity exceeds this multiple of
+	// the median velocity before it.
+	SpikeFactor float64
+	// AccelerationFactor flags the latest interval when velocity grew by
+	// more than this multiple within one interval of an established post.
+	AccelerationFactor float64
+	// RatioFloor flags engagement below this fraction of the baseline.
+	RatioFloor float64
+	// Threshold is the score at which a post is suspicious.
+	Threshold float64
+}
+
+func DefaultAnomalyPolicy() AnomalyPolicy {
+	return AnomalyPolicy{MinViews: 1000, SpikeFactor: 5, AccelerationFactor: 10, RatioFloor: 0.2, Threshold: 0.5}
+}
+
+type AnomalyReason struct {
+	Code   string  `json:"code"`
+	Detail string  `json:"detail"`
+	Weight float64 `json:"weight"`
+}
+
+type AnomalyReport struct {
+	TrackedPostID string `json:"tracked_post_id"`
+	Platform      string `json:"platform"`
+	SnapshotCount int    `json:"snapshot_count"`
+	// ViewsPerHour is the velocity over the latest interval; Acceleration
+	// is its change from the interval before, in views per hour squared.
+	ViewsPerHour     float64         `json:"velocity_views_per_hour"`
+	Acceleration     float64         `json:"acceleration_views_per_hour2"`
+	LikeViewRatio    float64         `json:"like_view_ratio"`
+	CommentViewRatio float64         `json:"comment_view_ratio"`
+	Score            float64         `json:"score"`
+	Suspicious       bool            `json:"suspicious"`
+	Reasons          []AnomalyReason `json:"reasons"`
+	AnalyzedAt       time.Time       `json:"analyzed_at"`
+}
+
+type interval struct {
+	views, likes, comments int
+	hours                  float64
+	velocity               float64
+}
+
+// AnalyzeSnapshots scores the snapshot history of one post for signs of
+// purchased views: sudden steps in view velocity, spikes that bring no
+// likes or comments with them, and engagement ratios far below what organic
+// posts on the platform get.
+func AnalyzeSnapshots(platform string, snaps []MetricSnapshot, policy AnomalyPolicy) AnomalyReport {
+	rows := append([]MetricSnapshot(nil), snaps...)
+	sort.Slice(rows, func(i, j int) bool { return rows[i].PolledAt.Before(rows[j].PolledAt) })
+	report := AnomalyReport{Platform: platform, SnapshotCount: len(rows), Reasons: []AnomalyReason{}}
+	if len(rows) == 0 {
+		return report
+	}
+	report.TrackedPostID = rows[0].TrackedPostID
+	baseline := BaselineFor(platform)
+	add := func(code string, weight float64, format string, args ...any) {
+		report.Reasons = append(report.Reasons, AnomalyReason{Code: code, Detail: fmt.Sprintf(format, args...), Weight: weight})
+		report.Score += weight
+	}
+
+	steps := make([]interval, 0, len(rows))
+	for i := 1; i < len(rows); i++ {
+		hours := rows[i].PolledAt.Sub(rows[i-1].PolledAt).Hours()
+		if hours <= 0 {
+			continue
+		}
+		st := interval{views: rows[i].Views - rows[i-1].Views, likes: rows[i].Likes - rows[i-1].Likes, comments: rows[i].Comments - rows[i-1].Comments, hours: hours}
+		st.velocity = float64(st.views) / hours
+		steps = append(steps, st)
+	}
+	if n := len(steps); n > 0 {
+		report.ViewsPerHour = steps[n-1].velocity
+		if n > 1 {
+			report.Acceleration = (steps[n-1].velocity - steps[n-2].velocity) / steps[n-1].hours
+		}
+	}
+
+	decreased, spiked, bare := false, false, false
+	for i, st := range steps {
+		if st.views < 0 && !decreased {
+			decreased = true
+			add(AnomalyViewsDecreased, 0.2, "views dropped by %d; platforms remove invalid views", -st.views)
+		}
+		if i < 2 || st.views < policy.MinViews {
+			continue
+		}
+		median := medianVelocity(steps[:i])
+		if median < 1 {
+			median = 1
+		}
+		if !spiked && st.velocity > policy.SpikeFactor*median {
+			spiked = true
+			add(AnomalyViewSpike, 0.35, "%.0f views/h against a median of %.0f views/h", st.velocity, median)
+		}
+		if !bare && st.velocity > policy.SpikeFactor*median && float64(st.likes) < float64(st.views)*baseline.LikeViewRatio*policy.RatioFloor {
+			bare = true
+			add(AnomalySpikeWithoutEngagement, 0.3, "%d new views brought %d likes", st.views, st.likes)
+		}
+	}
+	if n := len(steps); n > 2 && !spiked {
+		prev := steps[n-2].velocity
+		if prev >= 1 && steps[n-1].views >= policy.MinViews && steps[n-1].velocity > policy.AccelerationFactor*prev {
+			add(AnomalyAcceleration, 0.2, "velocity rose from %.0f to %.0f views/h", prev, steps[n-1].velocity)
+		}
+	}
+
+	latest := rows[len(rows)-1]
+	if latest.Views > 0 {
+		report.LikeViewRatio = float64(latest.Likes) / float64(latest.Views)
+		report.CommentViewRatio = float64(latest.Comments) / float64(latest.Views)
+	}
+	if latest.Views >= policy.MinViews {
+		if report.LikeViewRatio < baseline.LikeViewRatio*policy.RatioFloor {
+			add(AnomalyLowLikeRatio, 0.25, "like/view %.4f against a %s baseline of %.4f", report.LikeViewRatio, PlatformKey(platform), baseline.LikeViewRatio)
+		}
+		if report.CommentViewRatio < baseline.CommentViewRatio*policy.RatioFloor {
+			add(AnomalyLowCommentRatio, 0.15, "comment/view %.4f against a %s baseline of %.4f", report.CommentViewRatio, PlatformKey(platform), baseline.CommentViewRatio)
+		}
+	}
+
+	if report.Score > 1 {
+		report.Score = 1
+	}
+	report.Suspicious = report.Score >= policy.Threshold
+	return report
+}
+
+func medianVelocity(steps []interval) float64 {
+	v := make([]float64, len(steps))
+	for i, st := range steps {
+		v[i] = st.velocity
+	}
+	sort.Float64s(v)
+	if len(v)%2 == 1 {
+		return v[len(v)/2]
+	}
+	return (v[len(v)/2-1] + v[len(v)/2]) / 2
+}
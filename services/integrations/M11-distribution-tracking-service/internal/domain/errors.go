@@ -13,4 +13,8 @@ var (
 	ErrInvalidEnvelope       = errors.New("invalid_event_envelope")
 	ErrUnsupportedEventType  = errors.New("unsupported_event_type")
 	ErrUnsupportedEventClass = errors.New("unsupported_event_class")
+	ErrPostGone              = errors.New("post_gone")
+	ErrPostNotFound          = errors.New("post_not_found")
+	ErrRateLimited           = errors.New("rate_limited")
+	ErrMetricsUnavailable    = errors.New("metrics_unavailable")
 )
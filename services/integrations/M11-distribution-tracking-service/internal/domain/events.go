@@ -9,6 +9,7 @@ const (
 const (
 	EventTrackingMetricsUpdated = "tracking.metrics.updated"
 	EventTrackingPostArchived   = "tracking.post.archived"
+	EventTrackingAnomaly        = "tracking.metrics.anomaly_detected"
 	EventDistributionPublished  = "distribution.published"
 	EventDistributionFailed     = "distribution.failed"
 )
@@ -26,6 +27,7 @@ var canonicalInputEvents = map[string]canonicalEventMeta{
 var canonicalEmittedEvents = map[string]canonicalEventMeta{
 	EventTrackingMetricsUpdated: {class: CanonicalEventClassDomain, partitionKeyPath: "data.tracked_post_id"},
 	EventTrackingPostArchived:   {class: CanonicalEventClassDomain, partitionKeyPath: "data.tracked_post_id"},
+	EventTrackingAnomaly:        {class: CanonicalEventClassDomain, partitionKeyPath: "data.tracked_post_id"},
 }
 
 func IsCanonicalInputEvent(eventType string) bool {
@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// PostMetrics are the engagement counters a platform reports for a post.
+type PostMetrics struct {
+	Views    int
+	Likes    int
+	Shares   int
+	Comments int
+}
+
+// CadencePolicy decides when a post is polled next. Fresh and fast-growing
+// posts are polled every Min; steady posts every Base; posts whose views
+// have stalled back off by doubling their last interval up to Max.
+type CadencePolicy struct {
+	Min         time.Duration
+	Base        time.Duration
+	Max         time.Duration
+	FreshWindow time.Duration
+	// FastGrowthPerHour and StaleGrowthPerHour are relative view growth
+	// per hour (0.05 = 5%/h).
+	FastGrowthPerHour  float64
+	StaleGrowthPerHour float64
+}
+
+func DefaultCadencePolicy() CadencePolicy {
+	return CadencePolicy{Min: 15 * time.Minute, Base: 6 * time.Hour, Max: 24 * time.Hour, FreshWindow: 24 * time.Hour, FastGrowthPerHour: 0.05, StaleGrowthPerHour: 0.005}
+}
+
+// Next returns the interval until the poll after cur. prev is the previous
+// snapshot of the same post, nil on the first poll.
+func (p CadencePolicy) Next(post TrackedPost, prev *MetricSnapshot, cur MetricSnapshot) time.Duration {
+	if prev == nil || cur.PolledAt.Sub(post.CreatedAt) < p.FreshWindow {
+		return p.Min
+	}
+	elapsed := cur.PolledAt.Sub(prev.PolledAt)
+	if elapsed <= 0 {
+		return p.Min
+	}
+	base := prev.Views
+	if base < 1 {
+		base = 1
+	}
+	rate := float64(cur.Views-prev.Views) / float64(base) / elapsed.Hours()
+	switch {
+	case rate >= p.FastGrowthPerHour:
+		return p.Min
+	case rate <= p.StaleGrowthPerHour:
+		return p.clamp(2*elapsed, p.Base)
+	default:
+		return p.Base
+	}
+}
+
+// Backoff is the retry interval after failures consecutive fetch errors.
+func (p CadencePolicy) Backoff(failures int) time.Duration {
+	d := p.Min
+	for i := 1; i < failures && d < p.Max; i++ {
+		d *= 2
+	}
+	return p.clamp(d, p.Min)
+}
+
+func (p CadencePolicy) clamp(d, floor time.Duration) time.Duration {
+	if d < floor {
+		d = floor
+	}
+	if d > p.Max {
+		d = p.Max
+	}
+	return d
+}
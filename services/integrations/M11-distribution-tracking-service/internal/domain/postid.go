@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PlatformPostID extracts the id the platform's API knows the post by from
+// its canonical URL. Short links (vm.tiktok.com, t.co, instagr.am) carry no
+// id and are rejected; platforms without a metrics fetcher return "".
+func PlatformPostID(platform, rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("%w: malformed post url", ErrInvalidInput)
+	}
+	var id string
+	switch PlatformKey(platform) {
+	case "tiktok":
+		id = numericPathID(u, "video")
+	case "twitter":
+		id = numericPathID(u, "status")
+	case "youtube":
+		id = youTubeVideoID(u)
+	case "instagram":
+		for _, marker := range []string{"p", "reel", "reels", "tv"} {
+			if id = pathID(u, marker); id != "" {
+				break
+			}
+		}
+	default:
+		return "", nil
+	}
+	if id == "" {
+		return "", fmt.Errorf("%w: no %s post id in %s", ErrInvalidInput, PlatformKey(platform), u.Host+u.Path)
+	}
+	return id, nil
+}
+
+// ReportsViews reports whether the platform's API exposes a view count for
+// posts of other accounts. Meta does not for Instagram media, so Instagram
+// snapshots carry zero views.
+func ReportsViews(platform string) bool {
+	return PlatformKey(platform) != "instagram"
+}
+
+// pathID returns the path segment following marker ("status", "video").
+func pathID(u *url.URL, marker string) string {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == marker {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+func numericPathID(u *url.URL, marker string) string {
+	id := pathID(u, marker)
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return ""
+	}
+	return id
+}
+
+// youTubeVideoID accepts watch?v=, youtu.be/, /shorts/, /live/ and /embed/
+// URLs.
+func youTubeVideoID(u *url.URL) string {
+	id := u.Query().Get("v")
+	if strings.HasSuffix(u.Hostname(), "youtu.be") {
+		id = strings.Trim(u.Path, "/")
+	}
+	for _, marker := range []string{"shorts", "live", "embed"} {
+		if id == "" {
+			id = pathID(u, marker)
+		}
+	}
+	return id
+}
@@ -18,13 +18,21 @@ type TrackedPost struct {
 	UserID             string            `json:"user_id"`
 	Platform           string            `json:"platform"`
 	PostURL            string            `json:"post_url"`
+	PlatformPostID     string            `json:"platform_post_id,omitempty"`
 	DistributionItemID string            `json:"distribution_item_id,omitempty"`
 	CampaignID         string            `json:"campaign_id,omitempty"`
 	Status             TrackedPostStatus `json:"status"`
 	ValidationStatus   string            `json:"validation_status"`
 	LastPolledAt       *time.Time        `json:"last_polled_at,omitempty"`
-	CreatedAt          time.Time         `json:"created_at"`
-	UpdatedAt          time.Time         `json:"updated_at"`
+	NextPollAt         *time.Time        `json:"next_poll_at,omitempty"`
+	PollFailures       int               `json:"poll_failures,omitempty"`
+	LastPollError      string            `json:"last_poll_error,omitempty"`
+	// AnomalyScore is the suspicion score of the latest analysis;
+	// AnomalyFlaggedAt is set once it first crossed the threshold.
+	AnomalyScore     float64    `json:"anomaly_score,omitempty"`
+	AnomalyFlaggedAt *time.Time `json:"anomaly_flagged_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 type MetricSnapshot struct {
@@ -36,6 +44,19 @@ type MetricSnapshot struct {
 	Shares        int       `json:"shares"`
 	Comments      int       `json:"comments"`
 	PolledAt      time.Time `json:"polled_at"`
+	// ViewsReported is false on platforms that expose no view count; Views
+	// is then zero and must not be paid or judged on.
+	ViewsReported bool `json:"views_reported"`
+}
+
+// PlatformKey folds platform aliases ("x" is "twitter") for fetcher and
+// quota lookups.
+func PlatformKey(p string) string {
+	p = strings.ToLower(strings.TrimSpace(p))
+	if p == "x" {
+		return "twitter"
+	}
+	return p
 }
 
 func IsValidPlatform(p string) bool {
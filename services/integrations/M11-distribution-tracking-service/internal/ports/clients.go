@@ -1,3 +1,16 @@
 package ports
 
-// Phase 0 implementation does not require concrete upstream owner_api clients yet.
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+)
+
+// MetricsFetcher reads engagement counters of posts on one platform.
+// Errors wrap domain.ErrPostGone when the post was deleted or made private,
+// domain.ErrRateLimited when the platform quota is exhausted and
+// domain.ErrMetricsUnavailable otherwise.
+type MetricsFetcher interface {
+	Platform() string
+	FetchMetrics(ctx context.Context, post domain.TrackedPost) (domain.PostMetrics, error)
+}
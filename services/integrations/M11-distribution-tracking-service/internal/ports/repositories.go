@@ -12,7 +12,9 @@ type TrackedPostRepository interface {
 	GetByID(ctx context.Context, trackedPostID string) (domain.TrackedPost, error)
 	Update(ctx context.Context, row domain.TrackedPost) error
 	FindByUserPlatformURL(ctx context.Context, userID, platform, postURL string) (domain.TrackedPost, error)
-	ListPollCandidates(ctx context.Context, before time.Time, limit int) ([]domain.TrackedPost, error)
+	// ListPollCandidates returns non-archived posts whose NextPollAt is at
+	// or before now (never-polled posts first), earliest due first.
+	ListPollCandidates(ctx context.Context, now time.Time, limit int) ([]domain.TrackedPost, error)
 }
 
 type MetricSnapshotRepository interface {
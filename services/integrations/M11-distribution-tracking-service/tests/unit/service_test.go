@@ -2,18 +2,25 @@ package unit
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	eventadapter "github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/events"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/metrics"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M11-distribution-tracking-service/internal/ports"
 )
 
 func TestRegisterAndPollEmitsTrackingMetricsUpdated(t *testing.T) {
 	repos := postgres.NewRepositories()
 	domainPub := eventadapter.NewMemoryDomainPublisher()
-	svc := application.NewService(application.Dependencies{Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub, Config: application.Config{PollCadence: time.Nanosecond}})
+	svc := application.NewService(application.Dependencies{Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub, Config: application.Config{PollCadence: time.Nanosecond}, Fetchers: []ports.MetricsFetcher{metrics.NewFake("tiktok")}})
 	actor := application.Actor{SubjectID: "u1", Role: "user", IdempotencyKey: "idem-1"}
 	post, _, err := svc.RegisterPost(context.Background(), actor, application.RegisterPostInput{UserID: "u1", Platform: "tiktok", PostURL: "https://tiktok.com/@user/video/123"})
 	if err != nil {
@@ -47,3 +54,230 @@ func TestRegisterAndPollEmitsTrackingMetricsUpdated(t *testing.T) {
 		t.Fatalf("expected tracked post and snapshots")
 	}
 }
+
+func registerPosts(t *testing.T, svc *application.Service, urls ...string) []domain.TrackedPost {
+	t.Helper()
+	out := make([]domain.TrackedPost, 0, len(urls))
+	for i, u := range urls {
+		actor := application.Actor{SubjectID: "u1", Role: "user", IdempotencyKey: "idem-" + string(rune('a'+i))}
+		post, _, err := svc.RegisterPost(context.Background(), actor, application.RegisterPostInput{UserID: "u1", Platform: "tiktok", PostURL: u, DistributionItemID: "di-1"})
+		if err != nil {
+			t.Fatalf("register %s: %v", u, err)
+		}
+		out = append(out, post)
+	}
+	return out
+}
+
+func TestPollCycleHandlesErrorsPerPost(t *testing.T) {
+	repos := postgres.NewRepositories()
+	fake := metrics.NewFake("tiktok")
+	svc := application.NewService(application.Dependencies{Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, Fetchers: []ports.MetricsFetcher{fake}})
+	posts := registerPosts(t, svc, "https://tiktok.com/@a/video/1", "https://tiktok.com/@a/video/2", "https://tiktok.com/@a/video/3")
+	fake.Set(posts[0].PostURL, domain.PostMetrics{Views: 1000, Likes: 80, Shares: 4, Comments: 9})
+	fake.Fail(posts[1].PostURL, domain.ErrMetricsUnavailable)
+	fake.Fail(posts[2].PostURL, domain.ErrPostGone)
+	if err := svc.RunPollCycle(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	ok, _ := repos.Snapshots.LatestByTrackedPostID(context.Background(), posts[0].TrackedPostID)
+	if ok.Views != 1000 || ok.Likes != 80 {
+		t.Fatalf("expected fetched metrics, got %+v", ok)
+	}
+	failed, _ := repos.Posts.GetByID(context.Background(), posts[1].TrackedPostID)
+	if failed.PollFailures != 1 || failed.NextPollAt == nil || failed.LastPollError == "" {
+		t.Fatalf("expected recorded failure with backoff, got %+v", failed)
+	}
+	gone, _ := repos.Posts.GetByID(context.Background(), posts[2].TrackedPostID)
+	if gone.Status != domain.TrackedPostStatusArchived {
+		t.Fatalf("expected deleted post archived, got %s", gone.Status)
+	}
+}
+
+func TestRegistrationExtractsPostIDAndLookupMissesAreRetried(t *testing.T) {
+	repos := postgres.NewRepositories()
+	tiktok, instagram := metrics.NewFake("tiktok"), metrics.NewFake("instagram")
+	svc := application.NewService(application.Dependencies{Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, Fetchers: []ports.MetricsFetcher{tiktok, instagram}})
+	actor := application.Actor{SubjectID: "u1", Role: "user", IdempotencyKey: "idem-short"}
+	if _, _, err := svc.RegisterPost(context.Background(), actor, application.RegisterPostInput{UserID: "u1", Platform: "tiktok", PostURL: "https://vm.tiktok.com/ZMabc123/"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected a short link without a video id to be rejected, got %v", err)
+	}
+	posts := registerPosts(t, svc, "https://tiktok.com/@a/video/7301")
+	if posts[0].PlatformPostID != "7301" {
+		t.Fatalf("expected the video id kept at registration, got %q", posts[0].PlatformPostID)
+	}
+	actor.IdempotencyKey = "idem-ig"
+	reel, _, err := svc.RegisterPost(context.Background(), actor, application.RegisterPostInput{UserID: "u1", Platform: "instagram", PostURL: "https://www.instagram.com/reel/Cxyz123/", DistributionItemID: "di-1"})
+	if err != nil || reel.PlatformPostID != "Cxyz123" {
+		t.Fatalf("expected the reel shortcode kept at registration, got %+v err=%v", reel, err)
+	}
+
+	tiktok.Fail(posts[0].PostURL, domain.ErrPostNotFound)
+	instagram.Set(reel.PostURL, domain.PostMetrics{Likes: 12, Comments: 3})
+	if err := svc.RunPollCycle(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	missed, _ := repos.Posts.GetByID(context.Background(), posts[0].TrackedPostID)
+	if missed.Status == domain.TrackedPostStatusArchived || missed.PollFailures != 1 || missed.NextPollAt == nil {
+		t.Fatalf("expected a lookup miss to be retried, not archived, got %+v", missed)
+	}
+	snap, err := repos.Snapshots.LatestByTrackedPostID(context.Background(), reel.TrackedPostID)
+	if err != nil || snap.ViewsReported || snap.Likes != 12 {
+		t.Fatalf("expected an instagram snapshot flagged as having no views, got %+v err=%v", snap, err)
+	}
+}
+
+func TestPollCycleRespectsPlatformQuota(t *testing.T) {
+	repos := postgres.NewRepositories()
+	fake := metrics.NewFake("tiktok")
+	svc := application.NewService(application.Dependencies{Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, Fetchers: []ports.MetricsFetcher{fake}, Config: application.Config{PlatformQuotas: map[string]int{"tiktok": 1}}})
+	posts := registerPosts(t, svc, "https://tiktok.com/@a/video/1", "https://tiktok.com/@a/video/2")
+	if err := svc.RunPollCycle(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if got := fake.Calls(posts[0].PostURL) + fake.Calls(posts[1].PostURL); got != 1 {
+		t.Fatalf("expected 1 fetch within quota, got %d", got)
+	}
+	for _, p := range posts {
+		row, _ := repos.Posts.GetByID(context.Background(), p.TrackedPostID)
+		if row.NextPollAt == nil || !row.NextPollAt.After(time.Now()) {
+			t.Fatalf("expected post %s scheduled in the future, got %v", p.TrackedPostID, row.NextPollAt)
+		}
+	}
+}
+
+func TestCadencePolicyAdaptsToGrowth(t *testing.T) {
+	policy := domain.DefaultCadencePolicy()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	post := domain.TrackedPost{CreatedAt: created}
+	snap := func(hours float64, views int) domain.MetricSnapshot {
+		return domain.MetricSnapshot{PolledAt: created.Add(time.Duration(hours * float64(time.Hour))), Views: views}
+	}
+	fresh := snap(1, 500)
+	if got := policy.Next(post, &domain.MetricSnapshot{PolledAt: created, Views: 100}, fresh); got != policy.Min {
+		t.Fatalf("fresh post: expected %s, got %s", policy.Min, got)
+	}
+	prev := snap(48, 10000)
+	if got := policy.Next(post, &prev, snap(54, 20000)); got != policy.Min {
+		t.Fatalf("fast growth: expected %s, got %s", policy.Min, got)
+	}
+	if got := policy.Next(post, &prev, snap(54, 10600)); got != policy.Base {
+		t.Fatalf("steady growth: expected %s, got %s", policy.Base, got)
+	}
+	if got := policy.Next(post, &prev, snap(58, 10010)); got != 20*time.Hour {
+		t.Fatalf("stale post: expected doubled interval 20h, got %s", got)
+	}
+	if got := policy.Backoff(10); got != policy.Max {
+		t.Fatalf("backoff: expected cap %s, got %s", policy.Max, got)
+	}
+}
+
+func TestAnalyzeSnapshotsFlagsPurchasedViews(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := func(last domain.MetricSnapshot) []domain.MetricSnapshot {
+		out := make([]domain.MetricSnapshot, 0, 5)
+		for i := 0; i < 4; i++ {
+			views := 2000 + 1000*i
+			out = append(out, domain.MetricSnapshot{TrackedPostID: "tp-1", Views: views, Likes: views * 6 / 100, Comments: views / 200, PolledAt: start.Add(time.Duration(i) * time.Hour)})
+		}
+		last.TrackedPostID = "tp-1"
+		last.PolledAt = start.Add(4 * time.Hour)
+		return append(out, last)
+	}
+	organic := domain.AnalyzeSnapshots("tiktok", history(domain.MetricSnapshot{Views: 6200, Likes: 372, Comments: 31}), domain.DefaultAnomalyPolicy())
+	if organic.Suspicious || len(organic.Reasons) != 0 {
+		t.Fatalf("expected organic growth to pass, got %+v", organic)
+	}
+	bought := domain.AnalyzeSnapshots("tiktok", history(domain.MetricSnapshot{Views: 90000, Likes: 190, Comments: 26}), domain.DefaultAnomalyPolicy())
+	if !bought.Suspicious || bought.Score < 0.9 {
+		t.Fatalf("expected purchased views flagged, got %+v", bought)
+	}
+	codes := map[string]bool{}
+	for _, r := range bought.Reasons {
+		codes[r.Code] = true
+	}
+	for _, want := range []string{domain.AnomalyViewSpike, domain.AnomalySpikeWithoutEngagement, domain.AnomalyLowLikeRatio} {
+		if !codes[want] {
+			t.Fatalf("expected reason %s, got %+v", want, bought.Reasons)
+		}
+	}
+	if bought.ViewsPerHour != 85000 || bought.Acceleration != 84000 {
+		t.Fatalf("unexpected velocity %.0f / acceleration %.0f", bought.ViewsPerHour, bought.Acceleration)
+	}
+}
+
+func TestPollEmitsAnomalyDetectedOnce(t *testing.T) {
+	repos := postgres.NewRepositories()
+	domainPub := eventadapter.NewMemoryDomainPublisher()
+	fake := metrics.NewFake("tiktok")
+	svc := application.NewService(application.Dependencies{Posts: repos.Posts, Snapshots: repos.Snapshots, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub, Fetchers: []ports.MetricsFetcher{fake}, Config: application.Config{PollCadence: time.Nanosecond}})
+	post := registerPosts(t, svc, "https://tiktok.com/@a/video/1")[0]
+	start := time.Now().UTC().Add(-4 * time.Hour)
+	for i := 0; i < 4; i++ {
+		views := 2000 + 1000*i
+		if err := repos.Snapshots.Append(context.Background(), domain.MetricSnapshot{SnapshotID: "seed-" + string(rune('a'+i)), TrackedPostID: post.TrackedPostID, Platform: "tiktok", Views: views, Likes: views * 6 / 100, Comments: views / 200, PolledAt: start.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("seed snapshot: %v", err)
+		}
+	}
+	fake.Set(post.PostURL, domain.PostMetrics{Views: 90000, Likes: 190, Comments: 26})
+	for i := 0; i < 2; i++ {
+		if err := svc.RunPollCycle(context.Background()); err != nil {
+			t.Fatalf("poll %d: %v", i, err)
+		}
+	}
+	if err := svc.FlushOutbox(context.Background()); err != nil {
+		t.Fatalf("flush outbox: %v", err)
+	}
+	anomalies := 0
+	for _, e := range domainPub.Events() {
+		if e.EventType == domain.EventTrackingAnomaly {
+			anomalies++
+			if e.PartitionKey != post.TrackedPostID || e.PartitionKeyPath != "data.tracked_post_id" {
+				t.Fatalf("unexpected partitioning: %+v", e)
+			}
+		}
+	}
+	if anomalies != 1 {
+		t.Fatalf("expected one anomaly event across two polls, got %d", anomalies)
+	}
+	row, _ := repos.Posts.GetByID(context.Background(), post.TrackedPostID)
+	if row.AnomalyFlaggedAt == nil || row.AnomalyScore < 0.5 {
+		t.Fatalf("expected post flagged, got %+v", row)
+	}
+	report, err := svc.GetTrackedPostAnomaly(context.Background(), application.Actor{SubjectID: "u1", Role: "user"}, post.TrackedPostID)
+	if err != nil || !report.Suspicious || report.SnapshotCount != 6 {
+		t.Fatalf("expected suspicious report over 6 snapshots, got %+v (%v)", report, err)
+	}
+}
+
+func TestInstagramFetcherFindsPostThroughBusinessDiscovery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ig-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/instagram_oembed":
+			_, _ = w.Write([]byte(`{"author_name":"creator"}`))
+		case r.URL.Path == "/1789" && strings.Contains(r.URL.Query().Get("fields"), ".after(c1)"):
+			_, _ = w.Write([]byte(`{"business_discovery":{"media":{"data":[{"permalink":"https://www.instagram.com/reel/Cxyz123/","like_count":42,"comments_count":7}]}}}`))
+		case r.URL.Path == "/1789" && strings.Contains(r.URL.Query().Get("fields"), "business_discovery.username(creator)"):
+			_, _ = w.Write([]byte(`{"business_discovery":{"media":{"data":[{"permalink":"https://www.instagram.com/p/Other1/","like_count":1,"comments_count":0}],"paging":{"cursors":{"after":"c1"}}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := metrics.NewInstagram("ig-token", "1789", server.URL, time.Second)
+	got, err := fetcher.FetchMetrics(context.Background(), domain.TrackedPost{Platform: "instagram", PostURL: "https://www.instagram.com/reel/Cxyz123/?igsh=abc"})
+	if err != nil {
+		t.Fatalf("fetch metrics: %v", err)
+	}
+	if got.Likes != 42 || got.Comments != 7 || got.Views != 0 {
+		t.Fatalf("unexpected instagram metrics %+v", got)
+	}
+	if _, err := fetcher.FetchMetrics(context.Background(), domain.TrackedPost{Platform: "instagram", PostURL: "https://www.instagram.com/p/Missing9/"}); !errors.Is(err, domain.ErrPostNotFound) {
+		t.Fatalf("expected a retryable miss for media outside recent posts, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ go 1.23
 require (
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/google/uuid v1.6.0
+	github.com/viralforge/mesh/platform v0.0.0
 	google.golang.org/grpc v1.75.1
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -18,3 +19,4 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 )
 
+replace github.com/viralforge/mesh/platform => ../../../platform
@@ -6,7 +6,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/viralforge/mesh/services/integrations/M30-social-integration-service/internal/domain"
@@ -215,8 +214,6 @@ func (s *Service) GetHealth(context.Context) (domain.HealthReport, error) {
 	}, nil
 }
 
-func (s *Service) RecordHTTPMetric(context.Context, string, string, int, time.Duration) {}
-
 func (s *Service) FlushOutbox(context.Context) error { return nil }
 
 func normalizeProvider(v string) string {
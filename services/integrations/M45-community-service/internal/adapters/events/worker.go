@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
@@ -19,25 +20,44 @@ type Worker struct {
 	dlqPublisher ports.DLQPublisher
 	service      *application.Service
 	pollInterval time.Duration
+
+	reconcileInterval time.Duration
 }
 
-func NewWorker(logger *slog.Logger, consumer ports.EventConsumer, dlqPublisher ports.DLQPublisher, service *application.Service, pollInterval time.Duration) *Worker {
+func NewWorker(logger *slog.Logger, consumer ports.EventConsumer, dlqPublisher ports.DLQPublisher, service *application.Service, pollInterval, reconcileInterval time.Duration) *Worker {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	if pollInterval <= 0 {
 		pollInterval = time.Second
 	}
-	return &Worker{logger: logger, consumer: consumer, dlqPublisher: dlqPublisher, service: service, pollInterval: pollInterval}
+	if reconcileInterval <= 0 {
+		reconcileInterval = 15 * time.Minute
+	}
+	return &Worker{logger: logger, consumer: consumer, dlqPublisher: dlqPublisher, service: service, pollInterval: pollInterval, reconcileInterval: reconcileInterval}
 }
 
 func (w *Worker) Run(ctx context.Context) error {
 	t := time.NewTicker(w.pollInterval)
 	defer t.Stop()
+	reconcile := time.NewTicker(w.reconcileInterval)
+	defer reconcile.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-reconcile.C:
+			if w.service == nil {
+				continue
+			}
+			res, err := w.service.Reconcile(ctx)
+			if err != nil {
+				w.logger.ErrorContext(ctx, "community reconciliation failed", "error", err)
+				continue
+			}
+			if res.DriftDetected > 0 || res.Failed > 0 {
+				w.logger.InfoContext(ctx, "community reconciliation", "integrations", res.Integrations, "drift_detected", res.DriftDetected, "drift_repaired", res.DriftRepaired, "unmanaged", res.Unmanaged, "failed", res.Failed)
+			}
 		case <-t.C:
 			if w.service != nil {
 				if err := w.service.FlushOutbox(ctx); err != nil {
@@ -57,15 +77,18 @@ func (w *Worker) Run(ctx context.Context) error {
 			if e == nil {
 				continue
 			}
-			if err := w.service.HandleCanonicalEvent(ctx, *e); err != nil {
+			ectx, span := observability.StartEventSpan(ctx, e.EventType, e.TraceID)
+			err = w.service.HandleCanonicalEvent(ectx, *e)
+			observability.EndEventSpan(span, err)
+			if err != nil {
 				if e.EventClass == domain.CanonicalEventClassAnalyticsOnly {
-					w.logger.WarnContext(ctx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+					w.logger.WarnContext(ectx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 					continue
 				}
-				w.logger.ErrorContext(ctx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+				w.logger.ErrorContext(ectx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 				if w.dlqPublisher != nil {
 					now := time.Now().UTC()
-					_ = w.dlqPublisher.PublishDLQ(ctx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "community-service.dlq", TraceID: e.TraceID})
+					_ = w.dlqPublisher.PublishDLQ(ectx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "community-service.dlq", TraceID: e.TraceID})
 				}
 			}
 		}
@@ -45,7 +45,58 @@ func (h *Handler) getIntegrationHealth(w http.ResponseWriter, r *http.Request) {
 		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
 		return
 	}
-	writeSuccess(w, http.StatusOK, "integration health", contracts.HealthCheckResponse{HealthCheckID: row.HealthCheckID, IntegrationID: row.IntegrationID, Status: string(row.Status), CheckedAt: row.CheckedAt.UTC().Format(time.RFC3339), LatencyMS: row.LatencyMS, HTTPStatusCode: row.HTTPStatusCode})
+	writeSuccess(w, http.StatusOK, "integration health", toHealthCheckResponse(row))
+}
+func (h *Handler) reconcileIntegration(w http.ResponseWriter, r *http.Request) {
+	row, err := h.service.ReconcileIntegration(r.Context(), actorFromContext(r.Context()), chi.URLParam(r, "integration_id"))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "integration reconciled", toHealthCheckResponse(row))
+}
+func (h *Handler) createMapping(w http.ResponseWriter, r *http.Request) {
+	var req contracts.CreateMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	row, err := h.service.CreateMapping(r.Context(), actorFromContext(r.Context()), application.CreateMappingInput{IntegrationID: chi.URLParam(r, "integration_id"), ProductID: req.ProductID, Tier: req.Tier, RoleConfig: req.RoleConfig})
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusCreated, "mapping created", contracts.ProductMappingResponse{MappingID: row.MappingID, ProductID: row.ProductID, IntegrationID: row.IntegrationID, Tier: row.Tier, RoleConfig: row.RoleConfig, Enabled: row.Enabled, CreatedAt: row.CreatedAt.UTC().Format(time.RFC3339)})
+}
+func (h *Handler) linkAccount(w http.ResponseWriter, r *http.Request) {
+	var req contracts.LinkAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	row, err := h.service.LinkAccount(r.Context(), actorFromContext(r.Context()), application.LinkAccountInput{Platform: req.Platform, ExternalUserID: req.ExternalUserID})
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusAccepted, "link code sent", contracts.AccountLinkResponse{UserID: row.UserID, Platform: row.Platform, ExternalUserID: row.ExternalUserID, ExpiresAt: row.ExpiresAt.UTC().Format(time.RFC3339)})
+}
+func (h *Handler) verifyAccountLink(w http.ResponseWriter, r *http.Request) {
+	var req contracts.VerifyAccountLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	row, err := h.service.VerifyAccountLink(r.Context(), actorFromContext(r.Context()), application.VerifyAccountLinkInput{Platform: req.Platform, Code: req.Code})
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusCreated, "account linked", contracts.CommunityAccountResponse{UserID: row.UserID, Platform: row.Platform, ExternalUserID: row.ExternalUserID, LinkedAt: row.LinkedAt.UTC().Format(time.RFC3339)})
 }
 func (h *Handler) manualGrant(w http.ResponseWriter, r *http.Request) {
 	var req contracts.ManualGrantRequest
@@ -98,5 +149,12 @@ func toIntegrationResponse(row domain.CommunityIntegration) contracts.CommunityI
 	return contracts.CommunityIntegrationResponse{IntegrationID: row.IntegrationID, CreatorID: row.CreatorID, Platform: row.Platform, Status: string(row.Status), CommunityName: row.CommunityName, Config: row.Config, CreatedAt: row.CreatedAt.UTC().Format(time.RFC3339), UpdatedAt: row.UpdatedAt.UTC().Format(time.RFC3339)}
 }
 func toGrantResponse(row domain.CommunityGrant) contracts.CommunityGrantResponse {
-	return contracts.CommunityGrantResponse{GrantID: row.GrantID, Status: string(row.Status), UserID: row.UserID, ProductID: row.ProductID, IntegrationID: row.IntegrationID, Tier: row.Tier, GrantedAt: row.GrantedAt.UTC().Format(time.RFC3339)}
+	out := contracts.CommunityGrantResponse{GrantID: row.GrantID, Status: string(row.Status), UserID: row.UserID, ProductID: row.ProductID, IntegrationID: row.IntegrationID, Tier: row.Tier, GrantedAt: row.GrantedAt.UTC().Format(time.RFC3339), InviteURL: row.InviteURL, RevocationReason: row.RevocationReason, SyncError: row.SyncError}
+	if row.RevokedAt != nil {
+		out.RevokedAt = row.RevokedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+func toHealthCheckResponse(row domain.CommunityHealthCheck) contracts.HealthCheckResponse {
+	return contracts.HealthCheckResponse{HealthCheckID: row.HealthCheckID, IntegrationID: row.IntegrationID, Status: string(row.Status), CheckedAt: row.CheckedAt.UTC().Format(time.RFC3339), LatencyMS: row.LatencyMS, HTTPStatusCode: row.HTTPStatusCode, ErrorMessage: row.ErrorMessage, DriftDetected: row.DriftDetected, DriftRepaired: row.DriftRepaired, Unmanaged: row.Unmanaged}
 }
@@ -35,6 +35,10 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusBadRequest, "idempotency_key_required"
 	case domain.ErrIdempotencyConflict, domain.ErrConflict:
 		return http.StatusConflict, "conflict"
+	case domain.ErrLinkCodeInvalid:
+		return http.StatusBadRequest, "link_code_invalid"
+	case domain.ErrAccountUnreachable:
+		return http.StatusUnprocessableEntity, "account_unreachable"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
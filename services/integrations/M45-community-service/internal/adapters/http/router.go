@@ -17,6 +17,10 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Post("/community/integrations", handler.connectIntegration)
 			r.Get("/community/integrations/{integration_id}", handler.getIntegration)
 			r.Get("/community/integrations/{integration_id}/health", handler.getIntegrationHealth)
+			r.Post("/community/integrations/{integration_id}/mappings", handler.createMapping)
+			r.Post("/community/integrations/{integration_id}/reconcile", handler.reconcileIntegration)
+			r.Post("/community/accounts", handler.linkAccount)
+			r.Post("/community/accounts/verify", handler.verifyAccountLink)
 			r.Post("/admin/community/grants", handler.manualGrant)
 			r.Get("/admin/community/grants/{grant_id}", handler.getGrant)
 			r.Get("/admin/audit-logs", handler.listAuditLogs)
@@ -0,0 +1,122 @@
+// Package platforms applies community grants through the Discord, Slack and
+// Telegram bot APIs.
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+)
+
+// Config points an adapter at a bot API. BaseURL is overridden in tests and
+// for API proxies.
+type Config struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func (c Config) withDefaults(baseURL string) Config {
+	if strings.TrimSpace(c.BaseURL) == "" {
+		c.BaseURL = baseURL
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return c
+}
+
+// doJSON sends body as JSON and decodes a JSON response into out. Only
+// transport failures are returned as errors; callers classify the status.
+func doJSON(ctx context.Context, client *http.Client, method, url string, header http.Header, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		// The URL is left out: Telegram carries the bot token in the path.
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("%w: %v", domain.ErrPlatformUnavailable, err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if out != nil && len(bytes.TrimSpace(raw)) > 0 {
+		_ = json.Unmarshal(raw, out)
+	}
+	return resp.StatusCode, nil
+}
+
+// statusError maps an HTTP status shared by the bot APIs to a domain error.
+func statusError(platform string, status int, detail string) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("%w: %s %d %s", domain.ErrPlatformAuth, platform, status, detail)
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", domain.ErrPlatformRateLimited, platform)
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%w: %s %s", domain.ErrNotMember, platform, detail)
+	default:
+		return fmt.Errorf("%w: %s %d %s", domain.ErrPlatformUnavailable, platform, status, detail)
+	}
+}
+
+// splitIDs reads a comma separated list of IDs from the first key of
+// config that is set.
+func splitIDs(config map[string]string, keys ...string) []string {
+	for _, key := range keys {
+		raw := strings.TrimSpace(config[key])
+		if raw == "" {
+			continue
+		}
+		out := []string{}
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func firstOf(configs []map[string]string, keys ...string) string {
+	for _, config := range configs {
+		for _, key := range keys {
+			if v := strings.TrimSpace(config[key]); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
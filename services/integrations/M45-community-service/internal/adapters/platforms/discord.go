@@ -0,0 +1,185 @@
+package platforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/ports"
+)
+
+// Discord grants access by assigning guild roles. The integration names the
+// guild (guild_id or server_id), the mapping the roles (role_ids or
+// role_id). Buyers who are not in the guild yet get the integration's
+// invite_url and the roles once reconciliation finds them there.
+type Discord struct{ cfg Config }
+
+func NewDiscord(cfg Config) *Discord {
+	return &Discord{cfg: cfg.withDefaults("https://discord.com/api/v10")}
+}
+
+type discordMember struct {
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Roles []string `json:"roles"`
+}
+
+func (d *Discord) Ping(ctx context.Context, integration domain.CommunityIntegration) error {
+	guild, err := d.guild(integration)
+	if err != nil {
+		return err
+	}
+	return d.call(ctx, http.MethodGet, "/guilds/"+guild, nil)
+}
+
+func (d *Discord) Grant(ctx context.Context, access ports.CommunityAccess) (ports.GrantReceipt, error) {
+	guild, roles, err := d.target(access.Integration, access.RoleConfig)
+	if err != nil {
+		return ports.GrantReceipt{}, err
+	}
+	for _, role := range roles {
+		if err := d.call(ctx, http.MethodPut, fmt.Sprintf("/guilds/%s/members/%s/roles/%s", guild, url.PathEscape(access.ExternalUserID), role), nil); err != nil {
+			return ports.GrantReceipt{InviteURL: access.Integration.Config["invite_url"]}, err
+		}
+	}
+	return ports.GrantReceipt{}, nil
+}
+
+func (d *Discord) Revoke(ctx context.Context, access ports.CommunityAccess) error {
+	guild, roles, err := d.target(access.Integration, access.RoleConfig)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		err := d.call(ctx, http.MethodDelete, fmt.Sprintf("/guilds/%s/members/%s/roles/%s", guild, url.PathEscape(access.ExternalUserID), role), nil)
+		if err != nil && !errors.Is(err, domain.ErrNotMember) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Discord) HasAccess(ctx context.Context, access ports.CommunityAccess) (bool, error) {
+	guild, roles, err := d.target(access.Integration, access.RoleConfig)
+	if err != nil {
+		return false, err
+	}
+	var member discordMember
+	err = d.call(ctx, http.MethodGet, fmt.Sprintf("/guilds/%s/members/%s", guild, url.PathEscape(access.ExternalUserID)), &member)
+	if errors.Is(err, domain.ErrNotMember) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return holdsAny(member.Roles, roles), nil
+}
+
+// ListMembers pages through the guild and returns members holding any of the
+// mapped roles.
+func (d *Discord) ListMembers(ctx context.Context, integration domain.CommunityIntegration, roleConfig map[string]string) ([]string, error) {
+	guild, roles, err := d.target(integration, roleConfig)
+	if err != nil {
+		return nil, err
+	}
+	out := []string{}
+	after := "0"
+	for {
+		var page []discordMember
+		if err := d.call(ctx, http.MethodGet, fmt.Sprintf("/guilds/%s/members?limit=1000&after=%s", guild, after), &page); err != nil {
+			return nil, err
+		}
+		for _, member := range page {
+			if holdsAny(member.Roles, roles) {
+				out = append(out, member.User.ID)
+			}
+		}
+		if len(page) < 1000 {
+			return out, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
+}
+
+// SendDirectMessage opens a DM channel with the user and posts text to it.
+// Discord only allows this for users who share a guild with the bot.
+func (d *Discord) SendDirectMessage(ctx context.Context, externalUserID, text string) error {
+	var channel struct {
+		ID string `json:"id"`
+	}
+	if err := d.do(ctx, http.MethodPost, "/users/@me/channels", map[string]string{"recipient_id": externalUserID}, &channel); err != nil {
+		return err
+	}
+	if channel.ID == "" {
+		return fmt.Errorf("%w: discord returned no dm channel", domain.ErrPlatformUnavailable)
+	}
+	return d.do(ctx, http.MethodPost, "/channels/"+url.PathEscape(channel.ID)+"/messages", map[string]string{"content": text}, nil)
+}
+
+func (d *Discord) guild(integration domain.CommunityIntegration) (string, error) {
+	guild := firstOf([]map[string]string{integration.Config}, "guild_id", "server_id")
+	if guild == "" {
+		return "", fmt.Errorf("%w: discord integration without guild_id", domain.ErrInvalidInput)
+	}
+	return url.PathEscape(guild), nil
+}
+
+func (d *Discord) target(integration domain.CommunityIntegration, roleConfig map[string]string) (string, []string, error) {
+	guild, err := d.guild(integration)
+	if err != nil {
+		return "", nil, err
+	}
+	roles := splitIDs(roleConfig, "role_ids", "role_id")
+	if len(roles) == 0 {
+		return "", nil, fmt.Errorf("%w: discord mapping without role_ids", domain.ErrInvalidInput)
+	}
+	for i := range roles {
+		roles[i] = url.PathEscape(roles[i])
+	}
+	return guild, roles, nil
+}
+
+func (d *Discord) call(ctx context.Context, method, path string, out any) error {
+	return d.do(ctx, method, path, nil, out)
+}
+
+// discordCannotMessageUser is Discord's error code for users who do not
+// accept direct messages from the bot.
+const discordCannotMessageUser = 50007
+
+func (d *Discord) do(ctx context.Context, method, path string, body, out any) error {
+	var failure struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	target := out
+	if target == nil {
+		target = &failure
+	}
+	status, err := doJSON(ctx, d.cfg.Client, method, d.cfg.BaseURL+path, http.Header{"Authorization": {"Bot " + d.cfg.Token}}, body, target)
+	if err != nil {
+		return err
+	}
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	if failure.Code == discordCannotMessageUser {
+		return fmt.Errorf("%w: discord %s", domain.ErrNotMember, failure.Message)
+	}
+	return statusError("discord", status, failure.Message)
+}
+
+func holdsAny(held, wanted []string) bool {
+	for _, h := range held {
+		for _, w := range wanted {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
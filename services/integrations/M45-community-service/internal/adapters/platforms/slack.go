@@ -0,0 +1,160 @@
+package platforms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/ports"
+)
+
+// Slack grants access by adding workspace members to the mapped channels
+// (channel_ids or channel_id). Buyers who are not in the workspace get the
+// integration's invite_url.
+type Slack struct{ cfg Config }
+
+func NewSlack(cfg Config) *Slack {
+	return &Slack{cfg: cfg.withDefaults("https://slack.com/api")}
+}
+
+type slackResponse struct {
+	OK       bool     `json:"ok"`
+	Error    string   `json:"error"`
+	Members  []string `json:"members"`
+	Metadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+func (s *Slack) Ping(ctx context.Context, _ domain.CommunityIntegration) error {
+	_, err := s.call(ctx, "auth.test", nil)
+	return err
+}
+
+func (s *Slack) Grant(ctx context.Context, access ports.CommunityAccess) (ports.GrantReceipt, error) {
+	channels, err := s.channels(access.RoleConfig)
+	if err != nil {
+		return ports.GrantReceipt{}, err
+	}
+	for _, channel := range channels {
+		_, err := s.call(ctx, "conversations.invite", map[string]any{"channel": channel, "users": access.ExternalUserID}, "already_in_channel")
+		if err != nil {
+			return ports.GrantReceipt{InviteURL: access.Integration.Config["invite_url"]}, err
+		}
+	}
+	return ports.GrantReceipt{}, nil
+}
+
+func (s *Slack) Revoke(ctx context.Context, access ports.CommunityAccess) error {
+	channels, err := s.channels(access.RoleConfig)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if _, err := s.call(ctx, "conversations.kick", map[string]any{"channel": channel, "user": access.ExternalUserID}, "not_in_channel", "user_not_found"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Slack) HasAccess(ctx context.Context, access ports.CommunityAccess) (bool, error) {
+	members, err := s.ListMembers(ctx, access.Integration, access.RoleConfig)
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if member == access.ExternalUserID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListMembers returns members of any mapped channel.
+func (s *Slack) ListMembers(ctx context.Context, _ domain.CommunityIntegration, roleConfig map[string]string) ([]string, error) {
+	channels, err := s.channels(roleConfig)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	out := []string{}
+	for _, channel := range channels {
+		cursor := ""
+		for {
+			q := url.Values{"channel": {channel}, "limit": {"1000"}}
+			if cursor != "" {
+				q.Set("cursor", cursor)
+			}
+			resp, err := s.call(ctx, "conversations.members?"+q.Encode(), nil)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range resp.Members {
+				if !seen[member] {
+					seen[member] = true
+					out = append(out, member)
+				}
+			}
+			if cursor = resp.Metadata.NextCursor; cursor == "" {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// SendDirectMessage posts text to the user's direct message channel with
+// the app.
+func (s *Slack) SendDirectMessage(ctx context.Context, externalUserID, text string) error {
+	resp, err := s.call(ctx, "chat.postMessage", map[string]any{"channel": externalUserID, "text": text})
+	if err != nil && resp.Error == "channel_not_found" {
+		return fmt.Errorf("%w: slack %s", domain.ErrNotMember, resp.Error)
+	}
+	return err
+}
+
+func (s *Slack) channels(roleConfig map[string]string) ([]string, error) {
+	channels := splitIDs(roleConfig, "channel_ids", "channel_id")
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("%w: slack mapping without channel_ids", domain.ErrInvalidInput)
+	}
+	return channels, nil
+}
+
+// call invokes a Web API method. Slack reports most failures with HTTP 200
+// and ok=false; the listed error codes count as success.
+func (s *Slack) call(ctx context.Context, method string, body any, okErrors ...string) (slackResponse, error) {
+	var out slackResponse
+	verb := http.MethodGet
+	if body != nil {
+		verb = http.MethodPost
+	}
+	status, err := doJSON(ctx, s.cfg.Client, verb, s.cfg.BaseURL+"/"+method, http.Header{"Authorization": {"Bearer " + s.cfg.Token}}, body, &out)
+	if err != nil {
+		return out, err
+	}
+	if status != http.StatusOK {
+		return out, statusError("slack", status, out.Error)
+	}
+	if out.OK {
+		return out, nil
+	}
+	for _, code := range okErrors {
+		if out.Error == code {
+			return out, nil
+		}
+	}
+	switch out.Error {
+	case "not_authed", "invalid_auth", "account_inactive", "token_revoked", "token_expired", "missing_scope":
+		return out, fmt.Errorf("%w: slack %s", domain.ErrPlatformAuth, out.Error)
+	case "ratelimited":
+		return out, fmt.Errorf("%w: slack", domain.ErrPlatformRateLimited)
+	case "user_not_found", "user_disabled":
+		return out, fmt.Errorf("%w: slack %s", domain.ErrNotMember, out.Error)
+	default:
+		return out, fmt.Errorf("%w: slack %s", domain.ErrPlatformUnavailable, out.Error)
+	}
+}
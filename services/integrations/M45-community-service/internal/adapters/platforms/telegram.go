@@ -0,0 +1,155 @@
+package platforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/ports"
+)
+
+// Telegram manages one group or channel (chat_id on the mapping or the
+// integration). Bots cannot add users to a chat, so a grant lifts any
+// earlier ban and issues a single-use invite link; a revocation removes the
+// user. Bots cannot list chat members either, so reconciliation checks
+// users one by one.
+type Telegram struct{ cfg Config }
+
+func NewTelegram(cfg Config) *Telegram {
+	return &Telegram{cfg: cfg.withDefaults("https://api.telegram.org")}
+}
+
+type telegramResponse[T any] struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Result      T      `json:"result"`
+}
+
+type telegramChatMember struct {
+	Status   string `json:"status"`
+	IsMember bool   `json:"is_member"`
+}
+
+func (t *Telegram) Ping(ctx context.Context, integration domain.CommunityIntegration) error {
+	chat, err := t.chat(integration, nil)
+	if err != nil {
+		return err
+	}
+	var out telegramResponse[map[string]any]
+	return t.call(ctx, "getChat", map[string]any{"chat_id": chat}, &out)
+}
+
+func (t *Telegram) Grant(ctx context.Context, access ports.CommunityAccess) (ports.GrantReceipt, error) {
+	chat, err := t.chat(access.Integration, access.RoleConfig)
+	if err != nil {
+		return ports.GrantReceipt{}, err
+	}
+	if held, err := t.HasAccess(ctx, access); err != nil || held {
+		return ports.GrantReceipt{}, err
+	}
+	var unban telegramResponse[bool]
+	if err := t.call(ctx, "unbanChatMember", map[string]any{"chat_id": chat, "user_id": access.ExternalUserID, "only_if_banned": true}, &unban); err != nil {
+		return ports.GrantReceipt{}, err
+	}
+	var link telegramResponse[struct {
+		InviteLink string `json:"invite_link"`
+	}]
+	if err := t.call(ctx, "createChatInviteLink", map[string]any{"chat_id": chat, "name": "grant " + access.ExternalUserID, "member_limit": 1}, &link); err != nil {
+		return ports.GrantReceipt{}, err
+	}
+	return ports.GrantReceipt{InviteURL: link.Result.InviteLink}, nil
+}
+
+// Revoke bans and immediately unbans the user, which removes them from the
+// chat without blocking a later purchase.
+func (t *Telegram) Revoke(ctx context.Context, access ports.CommunityAccess) error {
+	chat, err := t.chat(access.Integration, access.RoleConfig)
+	if err != nil {
+		return err
+	}
+	var out telegramResponse[bool]
+	if err := t.call(ctx, "banChatMember", map[string]any{"chat_id": chat, "user_id": access.ExternalUserID}, &out); err != nil && !errors.Is(err, domain.ErrNotMember) {
+		return err
+	}
+	return t.call(ctx, "unbanChatMember", map[string]any{"chat_id": chat, "user_id": access.ExternalUserID, "only_if_banned": true}, &out)
+}
+
+func (t *Telegram) HasAccess(ctx context.Context, access ports.CommunityAccess) (bool, error) {
+	chat, err := t.chat(access.Integration, access.RoleConfig)
+	if err != nil {
+		return false, err
+	}
+	var out telegramResponse[telegramChatMember]
+	err = t.call(ctx, "getChatMember", map[string]any{"chat_id": chat, "user_id": access.ExternalUserID}, &out)
+	if errors.Is(err, domain.ErrNotMember) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	switch out.Result.Status {
+	case "creator", "administrator", "member":
+		return true, nil
+	case "restricted":
+		return out.Result.IsMember, nil
+	default:
+		return false, nil
+	}
+}
+
+func (t *Telegram) ListMembers(context.Context, domain.CommunityIntegration, map[string]string) ([]string, error) {
+	return nil, domain.ErrMembersNotListable
+}
+
+// SendDirectMessage messages the user's private chat with the bot. Bots
+// cannot start that chat, so the user must have messaged the bot first.
+func (t *Telegram) SendDirectMessage(ctx context.Context, externalUserID, text string) error {
+	var out telegramResponse[map[string]any]
+	err := t.call(ctx, "sendMessage", map[string]any{"chat_id": externalUserID, "text": text}, &out)
+	if err != nil && (out.ErrorCode == http.StatusForbidden || strings.Contains(strings.ToLower(out.Description), "chat not found")) {
+		return fmt.Errorf("%w: telegram %s", domain.ErrNotMember, out.Description)
+	}
+	return err
+}
+
+func (t *Telegram) chat(integration domain.CommunityIntegration, roleConfig map[string]string) (string, error) {
+	chat := firstOf([]map[string]string{roleConfig, integration.Config}, "chat_id")
+	if chat == "" {
+		return "", fmt.Errorf("%w: telegram integration without chat_id", domain.ErrInvalidInput)
+	}
+	return chat, nil
+}
+
+// telegramFailure is the part of a Bot API response needed to classify an
+// error whatever the result type.
+type telegramFailure interface{ failure() (bool, int, string) }
+
+func (r *telegramResponse[T]) failure() (bool, int, string) {
+	return r.OK, r.ErrorCode, r.Description
+}
+
+func (t *Telegram) call(ctx context.Context, method string, body any, out telegramFailure) error {
+	status, err := doJSON(ctx, t.cfg.Client, http.MethodPost, t.cfg.BaseURL+"/bot"+t.cfg.Token+"/"+method, nil, body, out)
+	if err != nil {
+		return err
+	}
+	ok, code, description := out.failure()
+	if status == http.StatusOK && ok {
+		return nil
+	}
+	if code == 0 {
+		code = status
+	}
+	lower := strings.ToLower(description)
+	if code == http.StatusBadRequest && (strings.Contains(lower, "user not found") || strings.Contains(lower, "participant_id_invalid") || strings.Contains(lower, "member not found")) {
+		return fmt.Errorf("%w: telegram %s", domain.ErrNotMember, description)
+	}
+	if code == http.StatusBadRequest {
+		return fmt.Errorf("%w: telegram %s", domain.ErrPlatformUnavailable, description)
+	}
+	return statusError("telegram", code, description)
+}
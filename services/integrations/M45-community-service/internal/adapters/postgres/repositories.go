@@ -14,6 +14,8 @@ type Repositories struct {
 	Integrations *CommunityIntegrationRepository
 	Mappings     *ProductCommunityMappingRepository
 	Grants       *CommunityGrantRepository
+	Accounts     *CommunityAccountRepository
+	LinkCodes    *AccountLinkChallengeRepository
 	AuditLogs    *CommunityAuditLogRepository
 	HealthChecks *CommunityHealthCheckRepository
 	Idempotency  *IdempotencyRepository
@@ -25,6 +27,8 @@ func NewRepositories() *Repositories {
 		Integrations: &CommunityIntegrationRepository{rows: map[string]domain.CommunityIntegration{}},
 		Mappings:     &ProductCommunityMappingRepository{rows: map[string]domain.ProductCommunityMapping{}},
 		Grants:       &CommunityGrantRepository{rows: map[string]domain.CommunityGrant{}},
+		Accounts:     &CommunityAccountRepository{rows: map[string]domain.CommunityAccount{}},
+		LinkCodes:    &AccountLinkChallengeRepository{rows: map[string]domain.AccountLinkChallenge{}},
 		AuditLogs:    &CommunityAuditLogRepository{rows: []domain.CommunityAuditLog{}},
 		HealthChecks: &CommunityHealthCheckRepository{rows: []domain.CommunityHealthCheck{}},
 		Idempotency:  &IdempotencyRepository{rows: map[string]ports.IdempotencyRecord{}},
@@ -67,6 +71,16 @@ func (r *CommunityIntegrationRepository) ListByCreatorID(_ context.Context, crea
 	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
 	return out, nil
 }
+func (r *CommunityIntegrationRepository) List(_ context.Context) ([]domain.CommunityIntegration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.CommunityIntegration, 0, len(r.rows))
+	for _, row := range r.rows {
+		out = append(out, row)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
 func (r *CommunityIntegrationRepository) Update(_ context.Context, row domain.CommunityIntegration) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -104,6 +118,25 @@ func (r *ProductCommunityMappingRepository) FindByProductIntegration(_ context.C
 	return domain.ProductCommunityMapping{}, domain.ErrNotFound
 }
 
+func (r *ProductCommunityMappingRepository) ListByProductID(_ context.Context, productID string) ([]domain.ProductCommunityMapping, error) {
+	return r.list(func(row domain.ProductCommunityMapping) bool { return row.ProductID == productID }), nil
+}
+func (r *ProductCommunityMappingRepository) ListByIntegrationID(_ context.Context, integrationID string) ([]domain.ProductCommunityMapping, error) {
+	return r.list(func(row domain.ProductCommunityMapping) bool { return row.IntegrationID == integrationID }), nil
+}
+func (r *ProductCommunityMappingRepository) list(match func(domain.ProductCommunityMapping) bool) []domain.ProductCommunityMapping {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.ProductCommunityMapping{}
+	for _, row := range r.rows {
+		if match(row) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
 type CommunityGrantRepository struct {
 	mu   sync.Mutex
 	rows map[string]domain.CommunityGrant
@@ -155,6 +188,91 @@ func (r *CommunityGrantRepository) ListByUserID(_ context.Context, userID string
 	return out, nil
 }
 
+func (r *CommunityGrantRepository) ListByIntegrationID(_ context.Context, integrationID string) ([]domain.CommunityGrant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.CommunityGrant{}
+	for _, row := range r.rows {
+		if row.IntegrationID == integrationID {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+func (r *CommunityGrantRepository) Update(_ context.Context, row domain.CommunityGrant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rows[row.GrantID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.rows[row.GrantID] = row
+	return nil
+}
+
+type CommunityAccountRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.CommunityAccount
+}
+
+func (r *CommunityAccountRepository) Upsert(_ context.Context, row domain.CommunityAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ex := range r.rows {
+		if ex.Platform == row.Platform && ex.ExternalUserID == row.ExternalUserID && ex.UserID != row.UserID {
+			return domain.ErrConflict
+		}
+	}
+	r.rows[row.UserID+"|"+row.Platform] = row
+	return nil
+}
+func (r *CommunityAccountRepository) Get(_ context.Context, userID, platform string) (domain.CommunityAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[userID+"|"+platform]
+	if !ok {
+		return domain.CommunityAccount{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+func (r *CommunityAccountRepository) FindByExternalID(_ context.Context, platform, externalUserID string) (domain.CommunityAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rows {
+		if row.Platform == platform && row.ExternalUserID == externalUserID {
+			return row, nil
+		}
+	}
+	return domain.CommunityAccount{}, domain.ErrNotFound
+}
+
+type AccountLinkChallengeRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.AccountLinkChallenge
+}
+
+func (r *AccountLinkChallengeRepository) Upsert(_ context.Context, row domain.AccountLinkChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[row.UserID+"|"+row.Platform] = row
+	return nil
+}
+func (r *AccountLinkChallengeRepository) Get(_ context.Context, userID, platform string) (domain.AccountLinkChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rows[userID+"|"+platform]
+	if !ok {
+		return domain.AccountLinkChallenge{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+func (r *AccountLinkChallengeRepository) Delete(_ context.Context, userID, platform string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.rows, userID+"|"+platform)
+	return nil
+}
+
 type CommunityAuditLogRepository struct {
 	mu   sync.Mutex
 	rows []domain.CommunityAuditLog
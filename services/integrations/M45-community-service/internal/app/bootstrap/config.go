@@ -16,6 +16,17 @@ type Config struct {
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
+	ReconcileInterval    time.Duration
+	Discord              PlatformConfig
+	Slack                PlatformConfig
+	Telegram             PlatformConfig
+}
+
+// PlatformConfig holds a bot's credentials. A platform without a token has
+// no adapter and its grants are only tracked.
+type PlatformConfig struct {
+	Token   string
+	BaseURL string
 }
 
 type configFile struct {
@@ -27,7 +38,7 @@ type configFile struct {
 }
 
 func LoadConfig(path string) (Config, error) {
-	cfg := Config{ServiceID: "M45-Community-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second}
+	cfg := Config{ServiceID: "M45-Community-Service", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second, ReconcileInterval: 15 * time.Minute}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
 		if err := yaml.Unmarshal(raw, &f); err != nil {
@@ -48,6 +59,10 @@ func LoadConfig(path string) (Config, error) {
 	cfg.IdempotencyTTL = time.Duration(envInt("IDEMPOTENCY_TTL_HOURS", int(cfg.IdempotencyTTL.Hours()))) * time.Hour
 	cfg.EventDedupTTL = time.Duration(envInt("EVENT_DEDUP_TTL_HOURS", int(cfg.EventDedupTTL.Hours()))) * time.Hour
 	cfg.ConsumerPollInterval = time.Duration(envInt("CONSUMER_POLL_SECONDS", int(cfg.ConsumerPollInterval.Seconds()))) * time.Second
+	cfg.ReconcileInterval = time.Duration(envInt("COMMUNITY_RECONCILE_INTERVAL_MINUTES", int(cfg.ReconcileInterval.Minutes()))) * time.Minute
+	cfg.Discord = PlatformConfig{Token: os.Getenv("DISCORD_BOT_TOKEN"), BaseURL: os.Getenv("DISCORD_API_BASE_URL")}
+	cfg.Slack = PlatformConfig{Token: os.Getenv("SLACK_BOT_TOKEN"), BaseURL: os.Getenv("SLACK_API_BASE_URL")}
+	cfg.Telegram = PlatformConfig{Token: os.Getenv("TELEGRAM_BOT_TOKEN"), BaseURL: os.Getenv("TELEGRAM_API_BASE_URL")}
 	return cfg, nil
 }
 func envInt(name string, fallback int) int {
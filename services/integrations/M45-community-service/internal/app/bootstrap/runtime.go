@@ -12,50 +12,77 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/http"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/platforms"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/ports"
 	"google.golang.org/grpc"
 )
 
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	consumer := eventadapter.NewMemoryConsumer()
 	dlqPub := eventadapter.NewLoggingDLQPublisher()
-	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval}, Integrations: repos.Integrations, Mappings: repos.Mappings, Grants: repos.Grants, AuditLogs: repos.AuditLogs, HealthChecks: repos.HealthChecks, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup})
+	communityPlatforms := map[string]ports.CommunityPlatform{}
+	if cfg.Discord.Token != "" {
+		communityPlatforms[string(domain.PlatformDiscord)] = platforms.NewDiscord(platforms.Config{Token: cfg.Discord.Token, BaseURL: cfg.Discord.BaseURL})
+	}
+	if cfg.Slack.Token != "" {
+		communityPlatforms[string(domain.PlatformSlack)] = platforms.NewSlack(platforms.Config{Token: cfg.Slack.Token, BaseURL: cfg.Slack.BaseURL})
+	}
+	if cfg.Telegram.Token != "" {
+		communityPlatforms[string(domain.PlatformTelegram)] = platforms.NewTelegram(platforms.Config{Token: cfg.Telegram.Token, BaseURL: cfg.Telegram.BaseURL})
+	}
+	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval, ReconcileInterval: cfg.ReconcileInterval}, Integrations: repos.Integrations, Mappings: repos.Mappings, Grants: repos.Grants, Accounts: repos.Accounts, LinkCodes: repos.LinkCodes, Platforms: communityPlatforms, AuditLogs: repos.AuditLogs, HealthChecks: repos.HealthChecks, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup})
 	handler := httpadapter.NewHandler(svc)
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewCommunityInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		return nil, err
 	}
-	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval, cfg.ReconcileInterval)
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
-	errCh := make(chan error, 2)
+	defer r.shutdownTelemetry()
+	errCh := make(chan error, 3)
+	// Grants, accounts and mappings live in this process's repositories, so
+	// event consumption and reconciliation run alongside the API.
+	go func() {
+		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- err
+		}
+	}()
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
@@ -77,9 +104,14 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 	r.grpcServer.GracefulStop()
 	return nil
 }
+
+// RunWorker runs only the worker loop, against repositories the API process
+// cannot see; the API already runs the same loop.
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
+	r.logger.WarnContext(ctx, "standalone worker uses its own in-memory repositories; grants and reconciliation it runs are not visible to the API")
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -93,3 +125,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
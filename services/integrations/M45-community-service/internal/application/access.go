@@ -0,0 +1,641 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/ports"
+)
+
+// systemActor performs event-driven grants and reconciliation.
+var systemActor = Actor{SubjectID: "system", Role: "system"}
+
+// Account links are confirmed with a code the bot sends to the platform
+// account by direct message.
+const (
+	linkCodeAlphabet    = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLength      = 8
+	linkCodeTTL         = 15 * time.Minute
+	linkCodeMaxAttempts = 5
+)
+
+// LinkAccount starts linking the actor's account on a platform. The bot
+// messages the account a code, which VerifyAccountLink takes to prove the
+// actor controls it; nothing is linked until then.
+func (s *Service) LinkAccount(ctx context.Context, actor Actor, in LinkAccountInput) (domain.AccountLinkChallenge, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.AccountLinkChallenge{}, domain.ErrUnauthorized
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.AccountLinkChallenge{}, domain.ErrIdempotencyRequired
+	}
+	platformName := strings.ToLower(strings.TrimSpace(in.Platform))
+	externalID := strings.TrimSpace(in.ExternalUserID)
+	platform := s.platforms[platformName]
+	if !domain.IsValidPlatform(platformName) || platform == nil || externalID == "" || s.accounts == nil || s.linkCodes == nil {
+		return domain.AccountLinkChallenge{}, domain.ErrInvalidInput
+	}
+	requestHash := hashJSON(map[string]any{"op": "link_account", "user_id": actor.SubjectID, "platform": platformName, "external_user_id": externalID})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.AccountLinkChallenge{}, err
+	} else if ok {
+		var out domain.AccountLinkChallenge
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.AccountLinkChallenge{}, err
+	}
+	if account, err := s.accounts.FindByExternalID(ctx, platformName, externalID); err == nil && account.UserID != actor.SubjectID {
+		return domain.AccountLinkChallenge{}, domain.ErrConflict
+	}
+	code, err := newLinkCode()
+	if err != nil {
+		return domain.AccountLinkChallenge{}, err
+	}
+	now := s.nowFn()
+	row := domain.AccountLinkChallenge{UserID: actor.SubjectID, Platform: platformName, ExternalUserID: externalID, CodeHash: hashLinkCode(code), ExpiresAt: now.Add(linkCodeTTL), CreatedAt: now}
+	if err := platform.SendDirectMessage(ctx, externalID, "Your community link code is "+code+". Enter it where you asked to link this account; ignore this message if you did not."); err != nil {
+		if errors.Is(err, domain.ErrNotMember) {
+			return domain.AccountLinkChallenge{}, domain.ErrAccountUnreachable
+		}
+		return domain.AccountLinkChallenge{}, err
+	}
+	if err := s.linkCodes.Upsert(ctx, row); err != nil {
+		return domain.AccountLinkChallenge{}, err
+	}
+	_ = s.appendAudit(ctx, actor, "community.account.link_requested", row.UserID, "", "", "", "success", "", map[string]string{"platform": platformName, "external_user_id": externalID})
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 202, row)
+	return row, nil
+}
+
+// VerifyAccountLink links the platform account once the actor enters the
+// code the bot sent it, and applies any of their grants that were waiting
+// for it. A challenge is dropped after linkCodeMaxAttempts wrong codes.
+func (s *Service) VerifyAccountLink(ctx context.Context, actor Actor, in VerifyAccountLinkInput) (domain.CommunityAccount, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.CommunityAccount{}, domain.ErrUnauthorized
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.CommunityAccount{}, domain.ErrIdempotencyRequired
+	}
+	platform := strings.ToLower(strings.TrimSpace(in.Platform))
+	code := strings.ToUpper(strings.TrimSpace(in.Code))
+	if !domain.IsValidPlatform(platform) || code == "" || s.accounts == nil || s.linkCodes == nil {
+		return domain.CommunityAccount{}, domain.ErrInvalidInput
+	}
+	requestHash := hashJSON(map[string]any{"op": "verify_account_link", "user_id": actor.SubjectID, "platform": platform, "code": hashLinkCode(code)})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.CommunityAccount{}, err
+	} else if ok {
+		var out domain.CommunityAccount
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.CommunityAccount{}, err
+	}
+	challenge, err := s.linkCodes.Get(ctx, actor.SubjectID, platform)
+	if err != nil {
+		return domain.CommunityAccount{}, domain.ErrLinkCodeInvalid
+	}
+	now := s.nowFn()
+	if !now.Before(challenge.ExpiresAt) {
+		_ = s.linkCodes.Delete(ctx, actor.SubjectID, platform)
+		return domain.CommunityAccount{}, domain.ErrLinkCodeInvalid
+	}
+	if subtle.ConstantTimeCompare([]byte(hashLinkCode(code)), []byte(challenge.CodeHash)) != 1 {
+		challenge.Attempts++
+		if challenge.Attempts >= linkCodeMaxAttempts {
+			_ = s.linkCodes.Delete(ctx, actor.SubjectID, platform)
+		} else {
+			_ = s.linkCodes.Upsert(ctx, challenge)
+		}
+		return domain.CommunityAccount{}, domain.ErrLinkCodeInvalid
+	}
+	row := domain.CommunityAccount{UserID: actor.SubjectID, Platform: platform, ExternalUserID: challenge.ExternalUserID, LinkedAt: now}
+	if err := s.accounts.Upsert(ctx, row); err != nil {
+		return domain.CommunityAccount{}, err
+	}
+	_ = s.linkCodes.Delete(ctx, actor.SubjectID, platform)
+	_ = s.appendAudit(ctx, actor, "community.account.linked", row.UserID, "", "", "", "success", "", map[string]string{"platform": platform, "external_user_id": row.ExternalUserID})
+	grants, err := s.grants.ListByUserID(ctx, row.UserID)
+	if err != nil {
+		return domain.CommunityAccount{}, err
+	}
+	for _, grant := range grants {
+		if grant.Status != domain.GrantStatusPending {
+			continue
+		}
+		integration, mapping, err := s.grantTarget(ctx, grant)
+		if err != nil || integration.Platform != platform {
+			continue
+		}
+		s.applyGrant(ctx, &grant, integration, mapping)
+		if err := s.grants.Update(ctx, grant); err != nil {
+			return domain.CommunityAccount{}, err
+		}
+	}
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 201, row)
+	return row, nil
+}
+
+func newLinkCode() (string, error) {
+	raw := make([]byte, linkCodeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	for i, b := range raw {
+		raw[i] = linkCodeAlphabet[int(b)%len(linkCodeAlphabet)]
+	}
+	return string(raw), nil
+}
+
+func hashLinkCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateMapping maps a product to an integration. Buyers of the product get
+// the roles or channels named in RoleConfig.
+func (s *Service) CreateMapping(ctx context.Context, actor Actor, in CreateMappingInput) (domain.ProductCommunityMapping, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ProductCommunityMapping{}, domain.ErrUnauthorized
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.ProductCommunityMapping{}, domain.ErrIdempotencyRequired
+	}
+	in.IntegrationID = strings.TrimSpace(in.IntegrationID)
+	in.ProductID = strings.TrimSpace(in.ProductID)
+	in.Tier = strings.ToLower(strings.TrimSpace(in.Tier))
+	if in.Tier == "" {
+		in.Tier = "basic"
+	}
+	roleConfig := sanitizeMap(in.RoleConfig)
+	if in.IntegrationID == "" || in.ProductID == "" {
+		return domain.ProductCommunityMapping{}, domain.ErrInvalidInput
+	}
+	integration, err := s.integrations.GetByID(ctx, in.IntegrationID)
+	if err != nil {
+		return domain.ProductCommunityMapping{}, err
+	}
+	if strings.TrimSpace(actor.SubjectID) != integration.CreatorID && !isAdmin(actor) {
+		return domain.ProductCommunityMapping{}, domain.ErrForbidden
+	}
+	requestHash := hashJSON(map[string]any{"op": "create_mapping", "integration_id": in.IntegrationID, "product_id": in.ProductID, "tier": in.Tier, "role_config": roleConfig})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.ProductCommunityMapping{}, err
+	} else if ok {
+		var out domain.ProductCommunityMapping
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.ProductCommunityMapping{}, err
+	}
+	row := domain.ProductCommunityMapping{MappingID: "map_" + uuid.NewString(), ProductID: in.ProductID, IntegrationID: in.IntegrationID, Tier: in.Tier, RoleConfig: roleConfig, Enabled: true, CreatedAt: s.nowFn()}
+	if err := s.mappings.Create(ctx, row); err != nil {
+		return domain.ProductCommunityMapping{}, err
+	}
+	_ = s.appendAudit(ctx, actor, "community.mapping.created", "", row.IntegrationID, row.ProductID, "", "success", "", map[string]string{"tier": row.Tier})
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 201, row)
+	return row, nil
+}
+
+// purchase is a paid order for a product.
+type purchase struct {
+	UserID    string
+	ProductID string
+	OrderID   string
+	Amount    float64
+}
+
+// grantPurchase grants every enabled community mapped to the product once
+// per order. Grants that cannot be applied yet stay pending.
+func (s *Service) grantPurchase(ctx context.Context, p purchase) error {
+	mappings, err := s.mappings.ListByProductID(ctx, p.ProductID)
+	if err != nil {
+		return err
+	}
+	for _, mapping := range mappings {
+		if !mapping.Enabled {
+			continue
+		}
+		if _, err := s.grants.FindByOrderIntegration(ctx, p.OrderID, mapping.IntegrationID); err == nil {
+			continue
+		}
+		integration, err := s.integrations.GetByID(ctx, mapping.IntegrationID)
+		if err != nil {
+			return err
+		}
+		now := s.nowFn()
+		grant := domain.CommunityGrant{GrantID: "grant_" + uuid.NewString(), UserID: p.UserID, ProductID: p.ProductID, IntegrationID: mapping.IntegrationID, OrderID: p.OrderID, Tier: mapping.Tier, PaidAmount: p.Amount, Status: domain.GrantStatusPending, GrantedAt: now, CreatedAt: now, UpdatedAt: now}
+		s.applyGrant(ctx, &grant, integration, mapping)
+		if err := s.grants.Create(ctx, grant); err != nil {
+			return err
+		}
+		_ = s.appendAudit(ctx, systemActor, "community.grant.purchase", grant.UserID, grant.IntegrationID, grant.ProductID, grant.GrantID, grantOutcome(grant), "", map[string]string{"order_id": grant.OrderID, "tier": grant.Tier, "status": string(grant.Status), "sync_error": grant.SyncError})
+	}
+	return nil
+}
+
+// revokeGrants revokes the buyer's grants that match.
+func (s *Service) revokeGrants(ctx context.Context, userID, reason string, match func(domain.CommunityGrant) bool) error {
+	grants, err := s.grants.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		if grant.Status == domain.GrantStatusRevoked || !match(grant) {
+			continue
+		}
+		if _, err := s.revokeGrant(ctx, grant, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refundGrants records a refund against the order's grants and revokes
+// those it fully covers. Partial refunds keep access; a refund of unknown
+// amount, or a grant whose price is unknown, is treated as full.
+func (s *Service) refundGrants(ctx context.Context, userID, orderID string, amount float64, reason string) error {
+	grants, err := s.grants.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, grant := range grants {
+		if grant.Status == domain.GrantStatusRevoked || grant.OrderID != orderID {
+			continue
+		}
+		grant.RefundedAmount += amount
+		if amount <= 0 || grant.PaidAmount <= 0 || grant.RefundedAmount >= grant.PaidAmount-0.005 {
+			if _, err := s.revokeGrant(ctx, grant, reason); err != nil {
+				return err
+			}
+			continue
+		}
+		grant.UpdatedAt = s.nowFn()
+		if err := s.grants.Update(ctx, grant); err != nil {
+			return err
+		}
+		_ = s.appendAudit(ctx, systemActor, "community.grant.partially_refunded", grant.UserID, grant.IntegrationID, grant.ProductID, grant.GrantID, "success", reason, map[string]string{"order_id": grant.OrderID, "paid_amount": strconv.FormatFloat(grant.PaidAmount, 'f', 2, 64), "refunded_amount": strconv.FormatFloat(grant.RefundedAmount, 'f', 2, 64)})
+	}
+	return nil
+}
+
+// applyGrant gives the buyer platform access for a grant. Failures leave
+// the grant pending for reconciliation to retry.
+func (s *Service) applyGrant(ctx context.Context, grant *domain.CommunityGrant, integration domain.CommunityIntegration, mapping domain.ProductCommunityMapping) {
+	now := s.nowFn()
+	grant.UpdatedAt = now
+	platform := s.platforms[integration.Platform]
+	if platform == nil {
+		grant.Status, grant.SyncError = domain.GrantStatusActive, ""
+		return
+	}
+	externalID, err := s.externalUserID(ctx, grant.UserID, integration.Platform)
+	if err != nil {
+		grant.Status, grant.SyncError = domain.GrantStatusPending, err.Error()
+		return
+	}
+	receipt, err := platform.Grant(ctx, ports.CommunityAccess{Integration: integration, RoleConfig: mapping.RoleConfig, ExternalUserID: externalID})
+	grant.ExternalUserID, grant.LastSyncedAt = externalID, &now
+	if receipt.InviteURL != "" {
+		grant.InviteURL = receipt.InviteURL
+	}
+	if err != nil {
+		grant.Status, grant.SyncError = domain.GrantStatusPending, err.Error()
+		return
+	}
+	grant.Status, grant.SyncError = domain.GrantStatusActive, ""
+}
+
+// revokeGrant ends a grant and removes the platform access it gave, unless
+// another grant still entitles the buyer to the same roles. A platform
+// failure is kept on the grant; reconciliation removes the access later.
+func (s *Service) revokeGrant(ctx context.Context, grant domain.CommunityGrant, reason string) (domain.CommunityGrant, error) {
+	now := s.nowFn()
+	applied := grant.ExternalUserID != ""
+	grant.Status, grant.RevokedAt, grant.RevocationReason, grant.UpdatedAt = domain.GrantStatusRevoked, &now, reason, now
+	if applied {
+		if err := s.removeAccess(ctx, grant); err != nil {
+			grant.SyncError = err.Error()
+		} else {
+			grant.SyncError, grant.LastSyncedAt = "", &now
+		}
+	}
+	if err := s.grants.Update(ctx, grant); err != nil {
+		return domain.CommunityGrant{}, err
+	}
+	_ = s.appendAudit(ctx, systemActor, "community.grant.revoked", grant.UserID, grant.IntegrationID, grant.ProductID, grant.GrantID, grantOutcome(grant), reason, map[string]string{"order_id": grant.OrderID, "sync_error": grant.SyncError})
+	return grant, nil
+}
+
+func (s *Service) removeAccess(ctx context.Context, grant domain.CommunityGrant) error {
+	integration, mapping, err := s.grantTarget(ctx, grant)
+	if err != nil {
+		return err
+	}
+	platform := s.platforms[integration.Platform]
+	if platform == nil {
+		return nil
+	}
+	others, err := s.grants.ListByUserID(ctx, grant.UserID)
+	if err != nil {
+		return err
+	}
+	key := targetKey(mapping.RoleConfig)
+	for _, other := range others {
+		if other.GrantID == grant.GrantID || other.IntegrationID != grant.IntegrationID || !other.Entitled() {
+			continue
+		}
+		if _, otherMapping, err := s.grantTarget(ctx, other); err == nil && targetKey(otherMapping.RoleConfig) == key {
+			return nil
+		}
+	}
+	return platform.Revoke(ctx, ports.CommunityAccess{Integration: integration, RoleConfig: mapping.RoleConfig, ExternalUserID: grant.ExternalUserID})
+}
+
+// ReconcileIntegration runs reconciliation for one integration on demand.
+func (s *Service) ReconcileIntegration(ctx context.Context, actor Actor, integrationID string) (domain.CommunityHealthCheck, error) {
+	integration, err := s.GetIntegration(ctx, actor, integrationID)
+	if err != nil {
+		return domain.CommunityHealthCheck{}, err
+	}
+	if strings.TrimSpace(actor.SubjectID) != integration.CreatorID && !isAdmin(actor) {
+		return domain.CommunityHealthCheck{}, domain.ErrForbidden
+	}
+	if s.platforms[integration.Platform] == nil {
+		return domain.CommunityHealthCheck{}, domain.ErrInvalidInput
+	}
+	return s.reconcileIntegration(ctx, integration)
+}
+
+// Reconcile compares platform membership with the grants of every connected
+// integration, repairs drift and records a health check per integration.
+func (s *Service) Reconcile(ctx context.Context) (ReconcileResult, error) {
+	integrations, err := s.integrations.List(ctx)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+	out := ReconcileResult{}
+	for _, integration := range integrations {
+		if s.platforms[integration.Platform] == nil || integration.Status == domain.IntegrationStatusDisconnected {
+			continue
+		}
+		check, err := s.reconcileIntegration(ctx, integration)
+		if err != nil {
+			return out, err
+		}
+		out.Integrations++
+		out.DriftDetected += check.DriftDetected
+		out.DriftRepaired += check.DriftRepaired
+		out.Unmanaged += check.Unmanaged
+		if check.Status != domain.HealthStatusHealthy {
+			out.Failed++
+		}
+	}
+	return out, nil
+}
+
+// reconcileIntegration works per set of mappings that share roles or
+// channels, so a buyer entitled through any of them keeps the access:
+//   - entitled buyers without access get it back (pending grants are retried);
+//   - members without an entitlement lose it if they are linked buyers, and
+//     are counted as unmanaged otherwise (moderators, staff).
+//
+// Invite-based grants (Telegram) are not re-granted when the buyer has not
+// joined: that is not drift.
+func (s *Service) reconcileIntegration(ctx context.Context, integration domain.CommunityIntegration) (domain.CommunityHealthCheck, error) {
+	platform := s.platforms[integration.Platform]
+	started := time.Now()
+	check := domain.CommunityHealthCheck{HealthCheckID: "hc_" + uuid.NewString(), IntegrationID: integration.IntegrationID}
+	var failure error
+	fail := func(err error) {
+		if failure == nil {
+			failure = err
+		}
+	}
+	if err := platform.Ping(ctx, integration); err != nil {
+		fail(err)
+	} else if err := s.reconcileMembers(ctx, integration, platform, &check, fail); err != nil {
+		return domain.CommunityHealthCheck{}, err
+	}
+	now := s.nowFn()
+	check.CheckedAt, check.LatencyMS, check.Status = now, int(time.Since(started).Milliseconds()), domain.HealthStatusFor(failure)
+	if failure != nil {
+		check.ErrorMessage = failure.Error()
+	} else {
+		check.HTTPStatusCode = 200
+	}
+	integration.LastSyncAt, integration.UpdatedAt = &now, now
+	switch {
+	case failure == nil:
+		integration.Status = domain.IntegrationStatusActive
+	case errors.Is(failure, domain.ErrPlatformAuth):
+		integration.Status = domain.IntegrationStatusError
+	}
+	if err := s.integrations.Update(ctx, integration); err != nil {
+		return domain.CommunityHealthCheck{}, err
+	}
+	if s.healthChecks != nil {
+		if err := s.healthChecks.Append(ctx, check); err != nil {
+			return domain.CommunityHealthCheck{}, err
+		}
+	}
+	return check, nil
+}
+
+func (s *Service) reconcileMembers(ctx context.Context, integration domain.CommunityIntegration, platform ports.CommunityPlatform, check *domain.CommunityHealthCheck, fail func(error)) error {
+	mappings, err := s.mappings.ListByIntegrationID(ctx, integration.IntegrationID)
+	if err != nil {
+		return err
+	}
+	grants, err := s.grants.ListByIntegrationID(ctx, integration.IntegrationID)
+	if err != nil {
+		return err
+	}
+	type target struct {
+		roleConfig map[string]string
+		products   map[string]domain.ProductCommunityMapping
+	}
+	targets := map[string]*target{}
+	keys := []string{}
+	for _, mapping := range mappings {
+		if !mapping.Enabled {
+			continue
+		}
+		key := targetKey(mapping.RoleConfig)
+		if targets[key] == nil {
+			targets[key] = &target{roleConfig: mapping.RoleConfig, products: map[string]domain.ProductCommunityMapping{}}
+			keys = append(keys, key)
+		}
+		targets[key].products[mapping.ProductID] = mapping
+	}
+	for _, key := range keys {
+		t := targets[key]
+		entitled := map[string]bool{}
+		formerly := map[string]bool{}
+		for i := range grants {
+			grant := &grants[i]
+			mapping, ok := t.products[grant.ProductID]
+			if !ok {
+				continue
+			}
+			if !grant.Entitled() {
+				if grant.ExternalUserID != "" {
+					formerly[grant.ExternalUserID] = true
+				}
+				continue
+			}
+			if grant.Status == domain.GrantStatusPending {
+				s.applyGrant(ctx, grant, integration, mapping)
+				if err := s.grants.Update(ctx, *grant); err != nil {
+					return err
+				}
+			}
+			if grant.ExternalUserID != "" {
+				entitled[grant.ExternalUserID] = true
+			}
+		}
+		access := func(externalID string) ports.CommunityAccess {
+			return ports.CommunityAccess{Integration: integration, RoleConfig: t.roleConfig, ExternalUserID: externalID}
+		}
+		holders, err := platform.ListMembers(ctx, integration, t.roleConfig)
+		listable := err == nil
+		if err != nil && !errors.Is(err, domain.ErrMembersNotListable) {
+			fail(err)
+			continue
+		}
+		held := map[string]bool{}
+		for _, id := range holders {
+			held[id] = true
+		}
+		for i := range grants {
+			grant := grants[i]
+			if _, ok := t.products[grant.ProductID]; !ok || grant.Status != domain.GrantStatusActive || grant.ExternalUserID == "" || grant.InviteURL != "" || !grant.Entitled() {
+				continue
+			}
+			has := held[grant.ExternalUserID]
+			if !listable {
+				if has, err = platform.HasAccess(ctx, access(grant.ExternalUserID)); err != nil {
+					fail(err)
+					continue
+				}
+			}
+			if has {
+				continue
+			}
+			held[grant.ExternalUserID] = true
+			check.DriftDetected++
+			if _, err := platform.Grant(ctx, access(grant.ExternalUserID)); err != nil {
+				fail(err)
+				continue
+			}
+			check.DriftRepaired++
+			_ = s.appendAudit(ctx, systemActor, "community.drift.repaired", grant.UserID, integration.IntegrationID, grant.ProductID, grant.GrantID, "success", "missing_access", map[string]string{"action": "granted", "external_user_id": grant.ExternalUserID})
+		}
+		extras := []string{}
+		if listable {
+			extras = holders
+		} else {
+			for id := range formerly {
+				extras = append(extras, id)
+			}
+			sort.Strings(extras)
+		}
+		for _, id := range extras {
+			if entitled[id] {
+				continue
+			}
+			if !listable {
+				has, err := platform.HasAccess(ctx, access(id))
+				if err != nil {
+					fail(err)
+					continue
+				}
+				if !has {
+					continue
+				}
+			}
+			userID := ""
+			if s.accounts != nil {
+				if account, err := s.accounts.FindByExternalID(ctx, integration.Platform, id); err == nil {
+					userID = account.UserID
+				}
+			}
+			if userID == "" && !formerly[id] {
+				check.Unmanaged++
+				continue
+			}
+			check.DriftDetected++
+			if err := platform.Revoke(ctx, access(id)); err != nil {
+				fail(err)
+				continue
+			}
+			check.DriftRepaired++
+			_ = s.appendAudit(ctx, systemActor, "community.drift.repaired", userID, integration.IntegrationID, "", "", "success", "no_active_grant", map[string]string{"action": "revoked", "external_user_id": id})
+		}
+	}
+	return nil
+}
+
+// grantTarget loads the integration and mapping a grant was made through.
+func (s *Service) grantTarget(ctx context.Context, grant domain.CommunityGrant) (domain.CommunityIntegration, domain.ProductCommunityMapping, error) {
+	integration, err := s.integrations.GetByID(ctx, grant.IntegrationID)
+	if err != nil {
+		return domain.CommunityIntegration{}, domain.ProductCommunityMapping{}, err
+	}
+	mapping, err := s.mappings.FindByProductIntegration(ctx, grant.ProductID, grant.IntegrationID)
+	if err != nil {
+		return domain.CommunityIntegration{}, domain.ProductCommunityMapping{}, err
+	}
+	return integration, mapping, nil
+}
+
+func (s *Service) externalUserID(ctx context.Context, userID, platform string) (string, error) {
+	if s.accounts == nil {
+		return "", domain.ErrAccountNotLinked
+	}
+	account, err := s.accounts.Get(ctx, userID, platform)
+	if err != nil {
+		return "", domain.ErrAccountNotLinked
+	}
+	return account.ExternalUserID, nil
+}
+
+// targetKey identifies the roles or channels a role config grants.
+func targetKey(roleConfig map[string]string) string {
+	keys := make([]string, 0, len(roleConfig))
+	for k := range roleConfig {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + roleConfig[k] + ";")
+	}
+	return b.String()
+}
+
+func grantOutcome(grant domain.CommunityGrant) string {
+	if grant.SyncError != "" {
+		return "failure"
+	}
+	return "success"
+}
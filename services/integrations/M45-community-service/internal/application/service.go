@@ -106,19 +106,22 @@ func (s *Service) CreateManualGrant(ctx context.Context, actor Actor, in ManualG
 	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.CommunityGrant{}, err
 	}
-	if _, err := s.mappings.FindByProductIntegration(ctx, in.ProductID, in.IntegrationID); err != nil {
-		_ = s.mappings.Create(ctx, domain.ProductCommunityMapping{MappingID: "map_" + uuid.NewString(), ProductID: in.ProductID, IntegrationID: in.IntegrationID, Tier: in.Tier, RoleConfig: map[string]string{"source": "manual_grant_default"}, Enabled: true, CreatedAt: s.nowFn()})
+	mapping, err := s.mappings.FindByProductIntegration(ctx, in.ProductID, in.IntegrationID)
+	if err != nil {
+		mapping = domain.ProductCommunityMapping{MappingID: "map_" + uuid.NewString(), ProductID: in.ProductID, IntegrationID: in.IntegrationID, Tier: in.Tier, RoleConfig: map[string]string{"source": "manual_grant_default"}, Enabled: true, CreatedAt: s.nowFn()}
+		_ = s.mappings.Create(ctx, mapping)
 	}
 	if existing, err := s.grants.FindByOrderIntegration(ctx, orderID, in.IntegrationID); err == nil {
 		_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 201, existing)
 		return existing, nil
 	}
 	now := s.nowFn()
-	grant := domain.CommunityGrant{GrantID: "grant_" + uuid.NewString(), UserID: in.UserID, ProductID: in.ProductID, IntegrationID: in.IntegrationID, OrderID: orderID, Tier: in.Tier, Status: domain.GrantStatusActive, GrantedAt: now, CreatedAt: now, UpdatedAt: now}
+	grant := domain.CommunityGrant{GrantID: "grant_" + uuid.NewString(), UserID: in.UserID, ProductID: in.ProductID, IntegrationID: in.IntegrationID, OrderID: orderID, Tier: in.Tier, Status: domain.GrantStatusPending, GrantedAt: now, CreatedAt: now, UpdatedAt: now}
+	s.applyGrant(ctx, &grant, integration, mapping)
 	if err := s.grants.Create(ctx, grant); err != nil {
 		return domain.CommunityGrant{}, err
 	}
-	_ = s.appendAudit(ctx, actor, "community.grant.manual", in.UserID, integration.IntegrationID, in.ProductID, grant.GrantID, "success", in.Reason, map[string]string{"tier": in.Tier})
+	_ = s.appendAudit(ctx, actor, "community.grant.manual", in.UserID, integration.IntegrationID, in.ProductID, grant.GrantID, grantOutcome(grant), in.Reason, map[string]string{"tier": in.Tier, "status": string(grant.Status), "sync_error": grant.SyncError})
 	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 201, grant)
 	return grant, nil
 }
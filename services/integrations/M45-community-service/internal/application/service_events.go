@@ -2,22 +2,93 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
 )
 
-func (s *Service) HandleCanonicalEvent(_ context.Context, envelope contracts.EventEnvelope) error {
+func (s *Service) HandleCanonicalEvent(ctx context.Context, envelope contracts.EventEnvelope) error {
 	if err := validateEnvelope(envelope); err != nil {
 		return err
 	}
 	if !domain.IsCanonicalInputEvent(envelope.EventType) {
 		return domain.ErrUnsupportedEventType
 	}
+	if strings.TrimSpace(envelope.EventClass) != "" && envelope.EventClass != domain.CanonicalEventClass(envelope.EventType) {
+		return domain.ErrUnsupportedEventClass
+	}
+	if envelope.PartitionKeyPath != domain.CanonicalPartitionKeyPath(envelope.EventType) {
+		return domain.ErrInvalidEnvelope
+	}
+	if s.eventDedup != nil {
+		dup, err := s.eventDedup.IsDuplicate(ctx, envelope.EventID, s.nowFn())
+		if err != nil {
+			return err
+		}
+		if dup {
+			return nil
+		}
+	}
+	if err := s.applyInboundEvent(ctx, envelope); err != nil {
+		return err
+	}
+	if s.eventDedup != nil {
+		return s.eventDedup.MarkProcessed(ctx, envelope.EventID, envelope.EventType, s.nowFn().Add(s.cfg.EventDedupTTL))
+	}
+	return nil
+}
+
+func (s *Service) applyInboundEvent(ctx context.Context, envelope contracts.EventEnvelope) error {
+	switch envelope.EventType {
+	case domain.EventTransactionSucceeded:
+		var p contracts.TransactionSucceededPayload
+		if err := decodePayload(envelope, &p, &p.TransactionID, &p.UserID); err != nil {
+			return err
+		}
+		if strings.TrimSpace(p.ProductID) == "" {
+			return nil
+		}
+		return s.grantPurchase(ctx, purchase{UserID: p.UserID, ProductID: strings.TrimSpace(p.ProductID), OrderID: p.TransactionID, Amount: p.Amount})
+	case domain.EventTransactionRefunded, domain.EventTransactionChargeback:
+		var p contracts.TransactionReversalPayload
+		if err := decodePayload(envelope, &p, &p.TransactionID, &p.UserID); err != nil {
+			return err
+		}
+		reason := firstNonEmpty(p.Reason, strings.TrimPrefix(envelope.EventType, "transaction."))
+		if envelope.EventType == domain.EventTransactionRefunded {
+			return s.refundGrants(ctx, p.UserID, p.TransactionID, p.Amount, reason)
+		}
+		return s.revokeGrants(ctx, p.UserID, reason, func(grant domain.CommunityGrant) bool {
+			return grant.OrderID == p.TransactionID
+		})
+	}
+	return domain.ErrUnsupportedEventType
+}
+
+// decodePayload unmarshals the event data and checks that its transaction
+// matches the partition key and that it names the buyer.
+func decodePayload(envelope contracts.EventEnvelope, out any, transactionID, userID *string) error {
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return domain.ErrInvalidEnvelope
+	}
+	*transactionID, *userID = strings.TrimSpace(*transactionID), strings.TrimSpace(*userID)
+	if *transactionID == "" || *transactionID != strings.TrimSpace(envelope.PartitionKey) || *userID == "" {
+		return domain.ErrInvalidEnvelope
+	}
 	return nil
 }
 
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 func validateEnvelope(event contracts.EventEnvelope) error {
 	if strings.TrimSpace(event.EventID) == "" || strings.TrimSpace(event.EventType) == "" || event.OccurredAt.IsZero() {
 		return domain.ErrInvalidEnvelope
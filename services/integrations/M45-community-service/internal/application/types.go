@@ -11,6 +11,7 @@ type Config struct {
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
+	ReconcileInterval    time.Duration
 }
 
 type Actor struct {
@@ -34,15 +35,44 @@ type ManualGrantInput struct {
 	Tier          string
 }
 
+type LinkAccountInput struct {
+	Platform       string
+	ExternalUserID string
+}
+
+type VerifyAccountLinkInput struct {
+	Platform string
+	Code     string
+}
+
+type CreateMappingInput struct {
+	IntegrationID string
+	ProductID     string
+	Tier          string
+	RoleConfig    map[string]string
+}
+
+// ReconcileResult sums up a reconciliation run over all integrations.
+type ReconcileResult struct {
+	Integrations  int
+	DriftDetected int
+	DriftRepaired int
+	Unmanaged     int
+	Failed        int
+}
+
 type Service struct {
 	cfg          Config
 	integrations ports.CommunityIntegrationRepository
 	mappings     ports.ProductCommunityMappingRepository
 	grants       ports.CommunityGrantRepository
+	accounts     ports.CommunityAccountRepository
+	linkCodes    ports.AccountLinkChallengeRepository
 	auditLogs    ports.CommunityAuditLogRepository
 	healthChecks ports.CommunityHealthCheckRepository
 	idempotency  ports.IdempotencyRepository
 	eventDedup   ports.EventDedupRepository
+	platforms    map[string]ports.CommunityPlatform
 	nowFn        func() time.Time
 }
 
@@ -51,10 +81,15 @@ type Dependencies struct {
 	Integrations ports.CommunityIntegrationRepository
 	Mappings     ports.ProductCommunityMappingRepository
 	Grants       ports.CommunityGrantRepository
+	Accounts     ports.CommunityAccountRepository
+	LinkCodes    ports.AccountLinkChallengeRepository
 	AuditLogs    ports.CommunityAuditLogRepository
 	HealthChecks ports.CommunityHealthCheckRepository
 	Idempotency  ports.IdempotencyRepository
 	EventDedup   ports.EventDedupRepository
+	// Platforms applies grants per platform name. Integrations on a
+	// platform without an adapter are tracked here only.
+	Platforms map[string]ports.CommunityPlatform
 }
 
 func NewService(deps Dependencies) *Service {
@@ -71,5 +106,8 @@ func NewService(deps Dependencies) *Service {
 	if cfg.ConsumerPollInterval <= 0 {
 		cfg.ConsumerPollInterval = 2 * time.Second
 	}
-	return &Service{cfg: cfg, integrations: deps.Integrations, mappings: deps.Mappings, grants: deps.Grants, auditLogs: deps.AuditLogs, healthChecks: deps.HealthChecks, idempotency: deps.Idempotency, eventDedup: deps.EventDedup, nowFn: func() time.Time { return time.Now().UTC() }}
+	if cfg.ReconcileInterval <= 0 {
+		cfg.ReconcileInterval = 15 * time.Minute
+	}
+	return &Service{cfg: cfg, integrations: deps.Integrations, mappings: deps.Mappings, grants: deps.Grants, accounts: deps.Accounts, linkCodes: deps.LinkCodes, platforms: deps.Platforms, auditLogs: deps.AuditLogs, healthChecks: deps.HealthChecks, idempotency: deps.Idempotency, eventDedup: deps.EventDedup, nowFn: func() time.Time { return time.Now().UTC() }}
 }
@@ -28,3 +28,23 @@ type DLQRecord struct {
 	DLQTopic      string        `json:"dlq_topic,omitempty"`
 	TraceID       string        `json:"trace_id,omitempty"`
 }
+
+// TransactionSucceededPayload is the part of M39's transaction.succeeded
+// that M45 needs. Transactions without a product grant nothing.
+type TransactionSucceededPayload struct {
+	TransactionID string  `json:"transaction_id"`
+	UserID        string  `json:"user_id"`
+	ProductID     string  `json:"product_id,omitempty"`
+	Amount        float64 `json:"amount"`
+}
+
+// TransactionReversalPayload is shared by transaction.refunded and
+// transaction.charged_back. Amount is the amount reversed by this event.
+type TransactionReversalPayload struct {
+	TransactionID string  `json:"transaction_id"`
+	RefundID      string  `json:"refund_id,omitempty"`
+	ChargebackID  string  `json:"chargeback_id,omitempty"`
+	UserID        string  `json:"user_id"`
+	Amount        float64 `json:"amount"`
+	Reason        string  `json:"reason,omitempty"`
+}
@@ -43,13 +43,59 @@ type ManualGrantRequest struct {
 }
 
 type CommunityGrantResponse struct {
-	GrantID       string `json:"grant_id"`
-	Status        string `json:"status"`
-	UserID        string `json:"user_id"`
-	ProductID     string `json:"product_id"`
-	IntegrationID string `json:"integration_id"`
-	Tier          string `json:"tier"`
-	GrantedAt     string `json:"granted_at"`
+	GrantID          string `json:"grant_id"`
+	Status           string `json:"status"`
+	UserID           string `json:"user_id"`
+	ProductID        string `json:"product_id"`
+	IntegrationID    string `json:"integration_id"`
+	Tier             string `json:"tier"`
+	GrantedAt        string `json:"granted_at"`
+	InviteURL        string `json:"invite_url,omitempty"`
+	RevokedAt        string `json:"revoked_at,omitempty"`
+	RevocationReason string `json:"revocation_reason,omitempty"`
+	SyncError        string `json:"sync_error,omitempty"`
+}
+
+type LinkAccountRequest struct {
+	Platform       string `json:"platform"`
+	ExternalUserID string `json:"external_user_id"`
+}
+
+// AccountLinkResponse reports that a link code was sent to the platform
+// account; the code itself is only delivered there.
+type AccountLinkResponse struct {
+	UserID         string `json:"user_id"`
+	Platform       string `json:"platform"`
+	ExternalUserID string `json:"external_user_id"`
+	ExpiresAt      string `json:"expires_at"`
+}
+
+type VerifyAccountLinkRequest struct {
+	Platform string `json:"platform"`
+	Code     string `json:"code"`
+}
+
+type CommunityAccountResponse struct {
+	UserID         string `json:"user_id"`
+	Platform       string `json:"platform"`
+	ExternalUserID string `json:"external_user_id"`
+	LinkedAt       string `json:"linked_at"`
+}
+
+type CreateMappingRequest struct {
+	ProductID  string            `json:"product_id"`
+	Tier       string            `json:"tier,omitempty"`
+	RoleConfig map[string]string `json:"role_config"`
+}
+
+type ProductMappingResponse struct {
+	MappingID     string            `json:"mapping_id"`
+	ProductID     string            `json:"product_id"`
+	IntegrationID string            `json:"integration_id"`
+	Tier          string            `json:"tier"`
+	RoleConfig    map[string]string `json:"role_config,omitempty"`
+	Enabled       bool              `json:"enabled"`
+	CreatedAt     string            `json:"created_at"`
 }
 
 type AuditLogResponse struct {
@@ -78,4 +124,8 @@ type HealthCheckResponse struct {
 	CheckedAt      string `json:"checked_at"`
 	LatencyMS      int    `json:"latency_ms"`
 	HTTPStatusCode int    `json:"http_status_code,omitempty"`
+	ErrorMessage   string `json:"error_message,omitempty"`
+	DriftDetected  int    `json:"drift_detected"`
+	DriftRepaired  int    `json:"drift_repaired"`
+	Unmanaged      int    `json:"unmanaged"`
 }
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"time"
 )
@@ -72,6 +74,10 @@ type ProductCommunityMapping struct {
 	CreatedAt     time.Time         `json:"created_at"`
 }
 
+// CommunityGrant is a buyer's entitlement to a community. It stays pending
+// until the platform has applied it, for example while the buyer has not
+// linked their platform account yet. PaidAmount and RefundedAmount track
+// partial refunds of the order; access is only revoked once they cover it.
 type CommunityGrant struct {
 	GrantID          string      `json:"grant_id"`
 	UserID           string      `json:"user_id"`
@@ -79,14 +85,47 @@ type CommunityGrant struct {
 	IntegrationID    string      `json:"integration_id"`
 	OrderID          string      `json:"order_id"`
 	Tier             string      `json:"tier"`
+	PaidAmount       float64     `json:"paid_amount,omitempty"`
+	RefundedAmount   float64     `json:"refunded_amount,omitempty"`
 	Status           GrantStatus `json:"status"`
+	ExternalUserID   string      `json:"external_user_id,omitempty"`
+	InviteURL        string      `json:"invite_url,omitempty"`
 	GrantedAt        time.Time   `json:"granted_at"`
 	RevokedAt        *time.Time  `json:"revoked_at,omitempty"`
 	RevocationReason string      `json:"revocation_reason,omitempty"`
+	LastSyncedAt     *time.Time  `json:"last_synced_at,omitempty"`
+	SyncError        string      `json:"sync_error,omitempty"`
 	CreatedAt        time.Time   `json:"created_at"`
 	UpdatedAt        time.Time   `json:"updated_at"`
 }
 
+// Entitled reports whether the grant should currently give access.
+func (g CommunityGrant) Entitled() bool {
+	return g.Status == GrantStatusActive || g.Status == GrantStatusPending
+}
+
+// CommunityAccount links a platform user to a buyer so grants can be
+// applied to the right Discord, Slack or Telegram account.
+type CommunityAccount struct {
+	UserID         string    `json:"user_id"`
+	Platform       string    `json:"platform"`
+	ExternalUserID string    `json:"external_user_id"`
+	LinkedAt       time.Time `json:"linked_at"`
+}
+
+// AccountLinkChallenge is a link to a platform account that the buyer has
+// not proven yet. The bot sends the code to that account by direct message,
+// so only its owner can confirm the link. Only the code's SHA-256 is kept.
+type AccountLinkChallenge struct {
+	UserID         string    `json:"user_id"`
+	Platform       string    `json:"platform"`
+	ExternalUserID string    `json:"external_user_id"`
+	CodeHash       string    `json:"-"`
+	Attempts       int       `json:"-"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
 type CommunityAuditLog struct {
 	AuditLogID    string            `json:"audit_log_id"`
 	Timestamp     time.Time         `json:"timestamp"`
@@ -111,4 +150,25 @@ type CommunityHealthCheck struct {
 	LatencyMS      int          `json:"latency_ms"`
 	ErrorMessage   string       `json:"error_message,omitempty"`
 	HTTPStatusCode int          `json:"http_status_code,omitempty"`
+	// DriftDetected counts members whose platform access disagreed with
+	// their grants during reconciliation; DriftRepaired how many were fixed.
+	DriftDetected int `json:"drift_detected"`
+	DriftRepaired int `json:"drift_repaired"`
+	Unmanaged     int `json:"unmanaged"`
+}
+
+// HealthStatusFor classifies a platform error for a health check.
+func HealthStatusFor(err error) HealthStatus {
+	switch {
+	case err == nil:
+		return HealthStatusHealthy
+	case errors.Is(err, ErrPlatformAuth):
+		return HealthStatusTokenExpired
+	case errors.Is(err, ErrPlatformRateLimited):
+		return HealthStatusRateLimited
+	case errors.Is(err, context.DeadlineExceeded):
+		return HealthStatusTimeout
+	default:
+		return HealthStatusError
+	}
 }
@@ -13,4 +13,13 @@ var (
 	ErrInvalidEnvelope       = errors.New("invalid_event_envelope")
 	ErrUnsupportedEventType  = errors.New("unsupported_event_type")
 	ErrUnsupportedEventClass = errors.New("unsupported_event_class")
+
+	ErrPlatformAuth        = errors.New("platform_auth_failed")
+	ErrPlatformRateLimited = errors.New("platform_rate_limited")
+	ErrPlatformUnavailable = errors.New("platform_unavailable")
+	ErrMembersNotListable  = errors.New("platform_members_not_listable")
+	ErrNotMember           = errors.New("platform_member_not_found")
+	ErrAccountNotLinked    = errors.New("community_account_not_linked")
+	ErrAccountUnreachable  = errors.New("community_account_unreachable")
+	ErrLinkCodeInvalid     = errors.New("community_link_code_invalid")
 )
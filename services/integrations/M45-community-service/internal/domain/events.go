@@ -6,6 +6,36 @@ const (
 	CanonicalEventClassOps           = "ops"
 )
 
-func IsCanonicalInputEvent(string) bool       { return false }
-func CanonicalEventClass(string) string       { return "" }
-func CanonicalPartitionKeyPath(string) string { return "" }
+// Inbound events: purchases grant the community access mapped to the
+// product, refunds and chargebacks take it away.
+const (
+	EventTransactionSucceeded  = "transaction.succeeded"
+	EventTransactionRefunded   = "transaction.refunded"
+	EventTransactionChargeback = "transaction.charged_back"
+)
+
+func IsCanonicalInputEvent(eventType string) bool {
+	switch eventType {
+	case EventTransactionSucceeded, EventTransactionRefunded, EventTransactionChargeback:
+		return true
+	default:
+		return false
+	}
+}
+
+func CanonicalEventClass(eventType string) string {
+	if IsCanonicalInputEvent(eventType) {
+		return CanonicalEventClassDomain
+	}
+	return ""
+}
+
+// CanonicalPartitionKeyPath follows contracts/events: M39 keys every
+// transaction event by transaction, so a grant and its revocation are
+// applied in order.
+func CanonicalPartitionKeyPath(eventType string) string {
+	if IsCanonicalInputEvent(eventType) {
+		return "data.transaction_id"
+	}
+	return ""
+}
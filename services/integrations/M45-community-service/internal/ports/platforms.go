@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+)
+
+// CommunityAccess is one platform user's access to the roles or channels a
+// product mapping names in its RoleConfig.
+type CommunityAccess struct {
+	Integration    domain.CommunityIntegration
+	RoleConfig     map[string]string
+	ExternalUserID string
+}
+
+// GrantReceipt is what a platform hands back for a new grant. Platforms that
+// cannot add members directly return an invite the buyer has to follow.
+type GrantReceipt struct {
+	InviteURL string
+}
+
+// CommunityPlatform applies grants on one chat platform. Implementations
+// must be idempotent: granting access a user already holds, or revoking
+// access they do not hold, succeeds.
+type CommunityPlatform interface {
+	Ping(ctx context.Context, integration domain.CommunityIntegration) error
+	Grant(ctx context.Context, access CommunityAccess) (GrantReceipt, error)
+	Revoke(ctx context.Context, access CommunityAccess) error
+	HasAccess(ctx context.Context, access CommunityAccess) (bool, error)
+	// ListMembers returns the external IDs of everyone holding the access a
+	// role config names, or domain.ErrMembersNotListable.
+	ListMembers(ctx context.Context, integration domain.CommunityIntegration, roleConfig map[string]string) ([]string, error)
+	// SendDirectMessage messages the user from the bot, or returns
+	// domain.ErrNotMember when the bot cannot reach them.
+	SendDirectMessage(ctx context.Context, externalUserID, text string) error
+}
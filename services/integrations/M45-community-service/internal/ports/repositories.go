@@ -11,12 +11,15 @@ type CommunityIntegrationRepository interface {
 	Create(ctx context.Context, row domain.CommunityIntegration) error
 	GetByID(ctx context.Context, integrationID string) (domain.CommunityIntegration, error)
 	ListByCreatorID(ctx context.Context, creatorID string) ([]domain.CommunityIntegration, error)
+	List(ctx context.Context) ([]domain.CommunityIntegration, error)
 	Update(ctx context.Context, row domain.CommunityIntegration) error
 }
 
 type ProductCommunityMappingRepository interface {
 	Create(ctx context.Context, row domain.ProductCommunityMapping) error
 	FindByProductIntegration(ctx context.Context, productID, integrationID string) (domain.ProductCommunityMapping, error)
+	ListByProductID(ctx context.Context, productID string) ([]domain.ProductCommunityMapping, error)
+	ListByIntegrationID(ctx context.Context, integrationID string) ([]domain.ProductCommunityMapping, error)
 }
 
 type CommunityGrantRepository interface {
@@ -24,6 +27,20 @@ type CommunityGrantRepository interface {
 	GetByID(ctx context.Context, grantID string) (domain.CommunityGrant, error)
 	FindByOrderIntegration(ctx context.Context, orderID, integrationID string) (domain.CommunityGrant, error)
 	ListByUserID(ctx context.Context, userID string) ([]domain.CommunityGrant, error)
+	ListByIntegrationID(ctx context.Context, integrationID string) ([]domain.CommunityGrant, error)
+	Update(ctx context.Context, row domain.CommunityGrant) error
+}
+
+type CommunityAccountRepository interface {
+	Upsert(ctx context.Context, row domain.CommunityAccount) error
+	Get(ctx context.Context, userID, platform string) (domain.CommunityAccount, error)
+	FindByExternalID(ctx context.Context, platform, externalUserID string) (domain.CommunityAccount, error)
+}
+
+type AccountLinkChallengeRepository interface {
+	Upsert(ctx context.Context, row domain.AccountLinkChallenge) error
+	Get(ctx context.Context, userID, platform string) (domain.AccountLinkChallenge, error)
+	Delete(ctx context.Context, userID, platform string) error
 }
 
 type CommunityAuditLogRepository interface {
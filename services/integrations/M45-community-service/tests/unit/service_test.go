@@ -2,10 +2,22 @@ package unit
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/platforms"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/contracts"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M45-community-service/internal/ports"
 )
 
 func newService() *application.Service {
@@ -53,3 +65,476 @@ func TestManualGrantCreatesGrantAndAudit(t *testing.T) {
 		t.Fatalf("expected audit logs for connect + grant")
 	}
 }
+
+func newPlatformService(platformMap map[string]ports.CommunityPlatform) (*application.Service, *postgres.Repositories) {
+	repos := postgres.NewRepositories()
+	return application.NewService(application.Dependencies{Integrations: repos.Integrations, Mappings: repos.Mappings, Grants: repos.Grants, Accounts: repos.Accounts, LinkCodes: repos.LinkCodes, AuditLogs: repos.AuditLogs, HealthChecks: repos.HealthChecks, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Platforms: platformMap}), repos
+}
+
+// fakeDiscord is a guild behind a minimal Discord REST API.
+type fakeDiscord struct {
+	mu    sync.Mutex
+	url   string
+	roles map[string]map[string]bool
+	dms   map[string]string
+}
+
+func newFakeDiscord(t *testing.T, members ...string) (*fakeDiscord, *platforms.Discord) {
+	f := &fakeDiscord{roles: map[string]map[string]bool{}, dms: map[string]string{}}
+	for _, m := range members {
+		f.roles[m] = map[string]bool{}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.Header.Get("Authorization") != "Bot test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case len(parts) == 3 && parts[0] == "users":
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": "dm-" + body["recipient_id"]})
+		case len(parts) == 3 && parts[0] == "channels":
+			recipient := strings.TrimPrefix(parts[1], "dm-")
+			if f.roles[recipient] == nil {
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 50007, "message": "Cannot send messages to this user"})
+				return
+			}
+			f.dms[recipient] = body["content"]
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": "msg-1"})
+		case len(parts) == 2:
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": parts[1]})
+		case len(parts) == 3 && parts[2] == "members":
+			ids := []string{}
+			for id := range f.roles {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			out := []map[string]any{}
+			for _, id := range ids {
+				out = append(out, map[string]any{"user": map[string]string{"id": id}, "roles": f.held(id)})
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		case len(parts) == 4:
+			if f.roles[parts[3]] == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"user": map[string]string{"id": parts[3]}, "roles": f.held(parts[3])})
+		case len(parts) == 6:
+			if f.roles[parts[3]] == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			f.roles[parts[3]][parts[5]] = r.Method == http.MethodPut
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	f.url = srv.URL
+	return f, platforms.NewDiscord(platforms.Config{BaseURL: srv.URL, Token: "test-token"})
+}
+
+func (f *fakeDiscord) held(user string) []string {
+	out := []string{}
+	for role, ok := range f.roles[user] {
+		if ok {
+			out = append(out, role)
+		}
+	}
+	return out
+}
+
+func (f *fakeDiscord) lastDM(user string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dms[user]
+}
+
+func (f *fakeDiscord) hasRole(user, role string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.roles[user][role]
+}
+
+func (f *fakeDiscord) setRole(user, role string, on bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.roles[user][role] = on
+}
+
+// fakeSlack is one channel behind a minimal Slack Web API.
+type fakeSlack struct {
+	mu        sync.Mutex
+	workspace map[string]bool
+	channel   map[string]bool
+	dms       map[string]string
+}
+
+func newFakeSlack(t *testing.T, workspace ...string) (*fakeSlack, *platforms.Slack) {
+	f := &fakeSlack{workspace: map[string]bool{}, channel: map[string]bool{}, dms: map[string]string{}}
+	for _, m := range workspace {
+		f.workspace[m] = true
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		reply := map[string]any{"ok": true}
+		switch strings.Trim(r.URL.Path, "/") {
+		case "conversations.invite":
+			switch {
+			case !f.workspace[body["users"]]:
+				reply = map[string]any{"ok": false, "error": "user_not_found"}
+			case f.channel[body["users"]]:
+				reply = map[string]any{"ok": false, "error": "already_in_channel"}
+			default:
+				f.channel[body["users"]] = true
+			}
+		case "chat.postMessage":
+			if !f.workspace[body["channel"]] {
+				reply = map[string]any{"ok": false, "error": "channel_not_found"}
+			} else {
+				f.dms[body["channel"]] = body["text"]
+			}
+		case "conversations.kick":
+			delete(f.channel, body["user"])
+		case "conversations.members":
+			members := []string{}
+			for m := range f.channel {
+				members = append(members, m)
+			}
+			reply["members"] = members
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	return f, platforms.NewSlack(platforms.Config{BaseURL: srv.URL, Token: "xoxb-test"})
+}
+
+func (f *fakeSlack) lastDM(user string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dms[user]
+}
+
+// fakeTelegram is one chat behind a minimal Bot API.
+type fakeTelegram struct {
+	mu      sync.Mutex
+	status  map[string]string
+	dms     map[string]string
+	invites int
+}
+
+func newFakeTelegram(t *testing.T) (*fakeTelegram, *platforms.Telegram) {
+	f := &fakeTelegram{status: map[string]string{}, dms: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		user := fmt.Sprint(body["user_id"])
+		var result any = true
+		switch strings.TrimPrefix(r.URL.Path, "/bottg-token/") {
+		case "getChat":
+			result = map[string]any{"id": body["chat_id"]}
+		case "getChatMember":
+			if f.status[user] == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error_code": 400, "description": "Bad Request: user not found"})
+				return
+			}
+			result = map[string]any{"status": f.status[user]}
+		case "sendMessage":
+			f.dms[fmt.Sprint(body["chat_id"])] = fmt.Sprint(body["text"])
+			result = map[string]any{"message_id": 1}
+		case "createChatInviteLink":
+			f.invites++
+			result = map[string]any{"invite_link": fmt.Sprintf("https://t.me/+invite%d", f.invites)}
+		case "banChatMember":
+			f.status[user] = "kicked"
+		case "unbanChatMember":
+			if f.status[user] == "kicked" {
+				f.status[user] = "left"
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error_code": 404, "description": "Not Found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return f, platforms.NewTelegram(platforms.Config{BaseURL: srv.URL, Token: "tg-token"})
+}
+
+func (f *fakeTelegram) lastDM(user string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dms[user]
+}
+
+func (f *fakeTelegram) join(user string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status[user] = "member"
+}
+
+func (f *fakeTelegram) member(user string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status[user] == "member"
+}
+
+func communityEvent(id, eventType, transactionID string, data any) contracts.EventEnvelope {
+	raw, _ := json.Marshal(data)
+	return contracts.EventEnvelope{EventID: id, EventType: eventType, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.transaction_id", PartitionKey: transactionID, SourceService: "M39-Finance-Service", TraceID: "trace-" + id, SchemaVersion: "v1", Data: raw}
+}
+
+// setupCommunity connects an integration and maps prod-1 to roleConfig.
+func setupCommunity(t *testing.T, svc *application.Service, platform string, config, roleConfig map[string]string) domain.CommunityIntegration {
+	t.Helper()
+	creator := application.Actor{SubjectID: "creator-1", Role: "creator", IdempotencyKey: "idem-connect-" + platform}
+	integration, err := svc.ConnectIntegration(context.Background(), creator, application.ConnectIntegrationInput{Platform: platform, CommunityName: "Members", Config: config})
+	if err != nil {
+		t.Fatalf("connect integration: %v", err)
+	}
+	creator.IdempotencyKey = "idem-mapping-" + platform
+	if _, err := svc.CreateMapping(context.Background(), creator, application.CreateMappingInput{IntegrationID: integration.IntegrationID, ProductID: "prod-1", Tier: "vip", RoleConfig: roleConfig}); err != nil {
+		t.Fatalf("create mapping: %v", err)
+	}
+	return integration
+}
+
+// inbox reads the last direct message a fake bot sent to a user.
+type inbox interface{ lastDM(user string) string }
+
+// linkAccount links the account the way a buyer does: request a code, then
+// enter the one the bot sent to the account.
+func linkAccount(t *testing.T, svc *application.Service, box inbox, userID, platform, externalID string) {
+	t.Helper()
+	actor := application.Actor{SubjectID: userID, Role: "user", IdempotencyKey: "idem-link-" + userID + platform}
+	if _, err := svc.LinkAccount(context.Background(), actor, application.LinkAccountInput{Platform: platform, ExternalUserID: externalID}); err != nil {
+		t.Fatalf("link account: %v", err)
+	}
+	actor.IdempotencyKey = "idem-verify-" + userID + platform
+	if _, err := svc.VerifyAccountLink(context.Background(), actor, application.VerifyAccountLinkInput{Platform: platform, Code: linkCode(box.lastDM(externalID))}); err != nil {
+		t.Fatalf("verify account link: %v", err)
+	}
+}
+
+// linkCode picks the code out of the bot's message.
+func linkCode(message string) string {
+	fields := strings.Fields(message)
+	for i, f := range fields {
+		if f == "is" && i+1 < len(fields) {
+			return strings.TrimSuffix(fields[i+1], ".")
+		}
+	}
+	return ""
+}
+
+func userGrants(t *testing.T, repos *postgres.Repositories, userID string) []domain.CommunityGrant {
+	t.Helper()
+	grants, err := repos.Grants.ListByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("list grants: %v", err)
+	}
+	return grants
+}
+
+func TestPurchaseAndRefundEventsSyncDiscordRoles(t *testing.T) {
+	guild, discord := newFakeDiscord(t, "d-100")
+	svc, repos := newPlatformService(map[string]ports.CommunityPlatform{"discord": discord})
+	setupCommunity(t, svc, "discord", map[string]string{"guild_id": "g-1"}, map[string]string{"role_ids": "r-vip"})
+	linkAccount(t, svc, guild, "user-1", "discord", "d-100")
+
+	purchase := communityEvent("evt-1", "transaction.succeeded", "txn-1", map[string]any{"transaction_id": "txn-1", "user_id": "user-1", "amount": 49, "currency": "USD", "provider": "stripe", "product_id": "prod-1"})
+	for i := 0; i < 2; i++ {
+		if err := svc.HandleCanonicalEvent(context.Background(), purchase); err != nil {
+			t.Fatalf("purchase event: %v", err)
+		}
+	}
+	grants := userGrants(t, repos, "user-1")
+	if len(grants) != 1 || grants[0].Status != domain.GrantStatusActive || grants[0].Tier != "vip" || grants[0].OrderID != "txn-1" || !guild.hasRole("d-100", "r-vip") {
+		t.Fatalf("expected one active grant with the role assigned, got %+v", grants)
+	}
+	refund := communityEvent("evt-2", "transaction.refunded", "txn-1", map[string]any{"transaction_id": "txn-1", "refund_id": "ref-1", "user_id": "user-1", "amount": 49, "currency": "USD", "provider": "stripe"})
+	if err := svc.HandleCanonicalEvent(context.Background(), refund); err != nil {
+		t.Fatalf("refund event: %v", err)
+	}
+	grants = userGrants(t, repos, "user-1")
+	if grants[0].Status != domain.GrantStatusRevoked || grants[0].RevocationReason != "refunded" || guild.hasRole("d-100", "r-vip") {
+		t.Fatalf("expected refund to revoke grant and role, got %+v", grants[0])
+	}
+	wrongKey := communityEvent("evt-3", "transaction.succeeded", "txn-other", map[string]any{"transaction_id": "txn-3", "user_id": "user-1", "product_id": "prod-1"})
+	if err := svc.HandleCanonicalEvent(context.Background(), wrongKey); err != domain.ErrInvalidEnvelope {
+		t.Fatalf("expected partition key mismatch to be rejected, got %v", err)
+	}
+	userKeyed := communityEvent("evt-4", "transaction.succeeded", "user-1", map[string]any{"transaction_id": "txn-4", "user_id": "user-1", "product_id": "prod-1"})
+	userKeyed.PartitionKeyPath = "data.user_id"
+	if err := svc.HandleCanonicalEvent(context.Background(), userKeyed); err != domain.ErrInvalidEnvelope {
+		t.Fatalf("expected a partition path other than the contract's to be rejected, got %v", err)
+	}
+	noProduct := communityEvent("evt-5", "transaction.succeeded", "txn-5", map[string]any{"transaction_id": "txn-5", "user_id": "user-1", "campaign_id": "camp-1"})
+	if err := svc.HandleCanonicalEvent(context.Background(), noProduct); err != nil {
+		t.Fatalf("expected a transaction without a product to be accepted, got %v", err)
+	}
+	if grants := userGrants(t, repos, "user-1"); len(grants) != 1 {
+		t.Fatalf("expected no grant for a transaction without a product, got %+v", grants)
+	}
+}
+
+func TestPartialRefundKeepsAccessUntilThePurchaseIsCovered(t *testing.T) {
+	guild, discord := newFakeDiscord(t, "d-100")
+	svc, repos := newPlatformService(map[string]ports.CommunityPlatform{"discord": discord})
+	setupCommunity(t, svc, "discord", map[string]string{"guild_id": "g-1"}, map[string]string{"role_ids": "r-vip"})
+	linkAccount(t, svc, guild, "user-1", "discord", "d-100")
+	if err := svc.HandleCanonicalEvent(context.Background(), communityEvent("evt-p1", "transaction.succeeded", "txn-1", map[string]any{"transaction_id": "txn-1", "user_id": "user-1", "amount": 49, "currency": "USD", "provider": "stripe", "product_id": "prod-1"})); err != nil {
+		t.Fatalf("purchase event: %v", err)
+	}
+	partial := communityEvent("evt-p2", "transaction.refunded", "txn-1", map[string]any{"transaction_id": "txn-1", "refund_id": "ref-1", "user_id": "user-1", "amount": 20, "currency": "USD", "provider": "stripe"})
+	if err := svc.HandleCanonicalEvent(context.Background(), partial); err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+	grants := userGrants(t, repos, "user-1")
+	if grants[0].Status != domain.GrantStatusActive || grants[0].RefundedAmount != 20 || !guild.hasRole("d-100", "r-vip") {
+		t.Fatalf("expected a partial refund to keep access, got %+v", grants[0])
+	}
+	rest := communityEvent("evt-p3", "transaction.refunded", "txn-1", map[string]any{"transaction_id": "txn-1", "refund_id": "ref-2", "user_id": "user-1", "amount": 29, "currency": "USD", "provider": "stripe"})
+	if err := svc.HandleCanonicalEvent(context.Background(), rest); err != nil {
+		t.Fatalf("second refund: %v", err)
+	}
+	grants = userGrants(t, repos, "user-1")
+	if grants[0].Status != domain.GrantStatusRevoked || guild.hasRole("d-100", "r-vip") {
+		t.Fatalf("expected refunds covering the purchase to revoke access, got %+v", grants[0])
+	}
+}
+func TestLinkAccountRequiresCodeSentByBot(t *testing.T) {
+	guild, discord := newFakeDiscord(t, "d-1", "d-victim")
+	svc, repos := newPlatformService(map[string]ports.CommunityPlatform{"discord": discord})
+	ctx := context.Background()
+	actor := application.Actor{SubjectID: "user-1", Role: "user", IdempotencyKey: "idem-link-1"}
+	challenge, err := svc.LinkAccount(ctx, actor, application.LinkAccountInput{Platform: "discord", ExternalUserID: "d-victim"})
+	if err != nil || challenge.ExternalUserID != "d-victim" || challenge.ExpiresAt.IsZero() {
+		t.Fatalf("expected a link challenge, got %+v (%v)", challenge, err)
+	}
+	if code := linkCode(guild.lastDM("d-victim")); code == "" {
+		t.Fatalf("expected the bot to message the account a code, got %q", guild.lastDM("d-victim"))
+	}
+	if _, err := repos.Accounts.Get(ctx, "user-1", "discord"); err == nil {
+		t.Fatalf("expected nothing linked before the code is confirmed")
+	}
+	for i := 0; i < 5; i++ {
+		actor.IdempotencyKey = fmt.Sprintf("idem-guess-%d", i)
+		if _, err := svc.VerifyAccountLink(ctx, actor, application.VerifyAccountLinkInput{Platform: "discord", Code: fmt.Sprintf("WRONG%03d", i)}); err != domain.ErrLinkCodeInvalid {
+			t.Fatalf("expected a wrong code to be refused, got %v", err)
+		}
+	}
+	actor.IdempotencyKey = "idem-guess-right"
+	if _, err := svc.VerifyAccountLink(ctx, actor, application.VerifyAccountLinkInput{Platform: "discord", Code: linkCode(guild.lastDM("d-victim"))}); err != domain.ErrLinkCodeInvalid {
+		t.Fatalf("expected the challenge to be dropped after too many attempts, got %v", err)
+	}
+
+	actor.IdempotencyKey = "idem-link-stranger"
+	if _, err := svc.LinkAccount(ctx, actor, application.LinkAccountInput{Platform: "discord", ExternalUserID: "d-stranger"}); err != domain.ErrAccountUnreachable {
+		t.Fatalf("expected an account the bot cannot message to be refused, got %v", err)
+	}
+	linkAccount(t, svc, guild, "user-1", "discord", "d-1")
+	account, err := repos.Accounts.Get(ctx, "user-1", "discord")
+	if err != nil || account.ExternalUserID != "d-1" {
+		t.Fatalf("expected the confirmed account to be linked, got %+v (%v)", account, err)
+	}
+}
+
+func TestSlackGrantWaitsForLinkedAccount(t *testing.T) {
+	workspace, slack := newFakeSlack(t, "U-1")
+	svc, repos := newPlatformService(map[string]ports.CommunityPlatform{"slack": slack})
+	setupCommunity(t, svc, "slack", map[string]string{"team_id": "T-1"}, map[string]string{"channel_ids": "C-members"})
+	if err := svc.HandleCanonicalEvent(context.Background(), communityEvent("evt-s1", "transaction.succeeded", "txn-1", map[string]any{"transaction_id": "txn-1", "user_id": "user-1", "product_id": "prod-1"})); err != nil {
+		t.Fatalf("purchase event: %v", err)
+	}
+	grants := userGrants(t, repos, "user-1")
+	if len(grants) != 1 || grants[0].Status != domain.GrantStatusPending || grants[0].SyncError != domain.ErrAccountNotLinked.Error() {
+		t.Fatalf("expected pending grant until the account is linked, got %+v", grants)
+	}
+	linkAccount(t, svc, workspace, "user-1", "slack", "U-1")
+	grants = userGrants(t, repos, "user-1")
+	if grants[0].Status != domain.GrantStatusActive || !workspace.channel["U-1"] {
+		t.Fatalf("expected linking to apply the grant, got %+v", grants[0])
+	}
+}
+
+func TestTelegramInvitesAndChargeback(t *testing.T) {
+	chat, telegram := newFakeTelegram(t)
+	svc, repos := newPlatformService(map[string]ports.CommunityPlatform{"telegram": telegram})
+	setupCommunity(t, svc, "telegram", map[string]string{"chat_id": "-100200"}, map[string]string{"chat_id": "-100200"})
+	linkAccount(t, svc, chat, "user-1", "telegram", "555")
+	if err := svc.HandleCanonicalEvent(context.Background(), communityEvent("evt-t1", "transaction.succeeded", "txn-1", map[string]any{"transaction_id": "txn-1", "user_id": "user-1", "product_id": "prod-1"})); err != nil {
+		t.Fatalf("purchase event: %v", err)
+	}
+	grants := userGrants(t, repos, "user-1")
+	if grants[0].Status != domain.GrantStatusActive || grants[0].InviteURL != "https://t.me/+invite1" {
+		t.Fatalf("expected an invite link, got %+v", grants[0])
+	}
+	chat.join("555")
+	chargeback := communityEvent("evt-t2", "transaction.charged_back", "txn-1", map[string]any{"transaction_id": "txn-1", "chargeback_id": "cb-1", "user_id": "user-1", "reason": "fraudulent"})
+	if err := svc.HandleCanonicalEvent(context.Background(), chargeback); err != nil {
+		t.Fatalf("chargeback event: %v", err)
+	}
+	grants = userGrants(t, repos, "user-1")
+	if grants[0].Status != domain.GrantStatusRevoked || grants[0].RevocationReason != "fraudulent" || chat.member("555") {
+		t.Fatalf("expected user removed from chat, got %+v", grants[0])
+	}
+	cancel := communityEvent("evt-t3", "subscription.cancelled", "txn-1", map[string]any{"transaction_id": "txn-1", "user_id": "user-1"})
+	if err := svc.HandleCanonicalEvent(context.Background(), cancel); err != domain.ErrUnsupportedEventType {
+		t.Fatalf("expected subscription events, which have no contract or producer yet, to be refused, got %v", err)
+	}
+}
+
+func TestReconcileRepairsDriftAndRecordsHealth(t *testing.T) {
+	guild, discord := newFakeDiscord(t, "d-1", "d-2", "d-mod")
+	svc, repos := newPlatformService(map[string]ports.CommunityPlatform{"discord": discord})
+	integration := setupCommunity(t, svc, "discord", map[string]string{"guild_id": "g-1"}, map[string]string{"role_ids": "r-vip"})
+	linkAccount(t, svc, guild, "user-1", "discord", "d-1")
+	linkAccount(t, svc, guild, "user-2", "discord", "d-2")
+	if err := svc.HandleCanonicalEvent(context.Background(), communityEvent("evt-r1", "transaction.succeeded", "txn-1", map[string]any{"transaction_id": "txn-1", "user_id": "user-1", "product_id": "prod-1"})); err != nil {
+		t.Fatalf("purchase event: %v", err)
+	}
+	guild.setRole("d-1", "r-vip", false)  // removed by hand
+	guild.setRole("d-2", "r-vip", true)   // linked user who never bought
+	guild.setRole("d-mod", "r-vip", true) // moderator unknown to us
+
+	res, err := svc.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if res.Integrations != 1 || res.DriftDetected != 2 || res.DriftRepaired != 2 || res.Unmanaged != 1 {
+		t.Fatalf("unexpected reconcile result %+v", res)
+	}
+	if !guild.hasRole("d-1", "r-vip") || guild.hasRole("d-2", "r-vip") || !guild.hasRole("d-mod", "r-vip") {
+		t.Fatalf("expected drift repaired and unmanaged member left alone")
+	}
+	check, err := repos.HealthChecks.LatestByIntegrationID(context.Background(), integration.IntegrationID)
+	if err != nil || check.Status != domain.HealthStatusHealthy || check.DriftDetected != 2 || check.Unmanaged != 1 {
+		t.Fatalf("expected health check with drift counts, got %+v (%v)", check, err)
+	}
+
+	expired, expiredRepos := newPlatformService(map[string]ports.CommunityPlatform{"discord": platforms.NewDiscord(platforms.Config{BaseURL: guild.url, Token: "expired"})})
+	expiredIntegration := setupCommunity(t, expired, "discord", map[string]string{"guild_id": "g-1"}, map[string]string{"role_ids": "r-vip"})
+	if res, err := expired.Reconcile(context.Background()); err != nil || res.Failed != 1 {
+		t.Fatalf("expected a failed reconciliation, got %+v (%v)", res, err)
+	}
+	check, _ = expiredRepos.HealthChecks.LatestByIntegrationID(context.Background(), expiredIntegration.IntegrationID)
+	row, _ := expiredRepos.Integrations.GetByID(context.Background(), expiredIntegration.IntegrationID)
+	if check.Status != domain.HealthStatusTokenExpired || row.Status != domain.IntegrationStatusError || row.LastSyncAt == nil {
+		t.Fatalf("expected token_expired health and errored integration, got %+v / %s", check, row.Status)
+	}
+}
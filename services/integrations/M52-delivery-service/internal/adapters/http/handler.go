@@ -2,8 +2,14 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net"
 	"net/http"
+	"net/textproto"
 	"strconv"
 	"strings"
 	"time"
@@ -14,9 +20,33 @@ import (
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/domain"
 )
 
-type Handler struct{ service *application.Service }
+type Handler struct {
+	service *application.Service
+	edge    EdgeHeaders
+}
+
+// EdgeHeaders names the headers the edge sets on every request. Clients can
+// send any header, so each must be one the edge always overwrites.
+type EdgeHeaders struct {
+	// Country carries the client's GeoIP country. Empty disables the
+	// country anomaly rule.
+	Country string
+	// ClientIP carries the client's address, such as CF-Connecting-IP.
+	// For X-Forwarded-For, ProxyHops is the number of trusted proxies
+	// appending to it and the address is read that many entries from the
+	// right. Empty uses the connection's remote address.
+	ClientIP  string
+	ProxyHops int
+}
 
-func NewHandler(service *application.Service) *Handler { return &Handler{service: service} }
+// NewHandler builds the HTTP handler.
+func NewHandler(service *application.Service, edge EdgeHeaders) *Handler {
+	edge.Country, edge.ClientIP = strings.TrimSpace(edge.Country), strings.TrimSpace(edge.ClientIP)
+	if edge.ProxyHops < 1 {
+		edge.ProxyHops = 1
+	}
+	return &Handler{service: service, edge: edge}
+}
 
 func (h *Handler) upsertProductFile(w http.ResponseWriter, r *http.Request) {
 	var req contracts.UpsertProductFileRequest
@@ -30,6 +60,7 @@ func (h *Handler) upsertProductFile(w http.ResponseWriter, r *http.Request) {
 		FileName:    req.FileName,
 		ContentType: req.ContentType,
 		SizeBytes:   req.SizeBytes,
+		StorageKey:  req.StorageKey,
 		Status:      req.Status,
 	})
 	if err != nil {
@@ -72,15 +103,96 @@ func (h *Handler) getDownloadLink(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) downloadByToken(w http.ResponseWriter, r *http.Request) {
-	ip := clientIP(r)
-	out, err := h.service.DownloadByToken(r.Context(), application.DownloadRequest{Token: chi.URLParam(r, "token"), IPAddress: ip, RangeHeader: r.Header.Get("Range")})
+	ip := h.clientIP(r)
+	out, err := h.service.DownloadByToken(r.Context(), application.DownloadRequest{
+		Token:       chi.URLParam(r, "token"),
+		IPAddress:   ip,
+		Country:     h.clientCountry(r),
+		RangeHeader: r.Header.Get("Range"),
+		IfRange:     r.Header.Get("If-Range"),
+		HeadOnly:    r.Method == http.MethodHead,
+	})
+	if errors.Is(err, domain.ErrRangeNotSatisfiable) {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", out.BytesTotal))
+	}
 	if err != nil {
 		code, c := mapDomainError(err)
 		writeError(w, code, c, err.Error())
 		return
 	}
-	w.Header().Set("X-Mock-Delivery", "metadata")
-	writeSuccess(w, http.StatusOK, contracts.DownloadMetadataResponse{ProductID: out.ProductID, FileID: out.FileID, FileName: out.FileName, ContentType: out.ContentType, BytesTotal: out.BytesTotal, DownloadsRemaining: out.DownloadsRemaining})
+	if out.Content == nil {
+		w.Header().Set("X-Mock-Delivery", "metadata")
+		writeSuccess(w, http.StatusOK, contracts.DownloadMetadataResponse{ProductID: out.ProductID, FileID: out.FileID, FileName: out.FileName, ContentType: out.ContentType, BytesTotal: out.BytesTotal, DownloadsRemaining: out.DownloadsRemaining})
+		return
+	}
+	streamContent(w, r, out)
+}
+
+// streamContent writes the delivered file as a full (200), single-range
+// (206) or multipart/byteranges (206) response.
+func streamContent(w http.ResponseWriter, r *http.Request, out application.DownloadResult) {
+	contentType := out.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := w.Header()
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": out.FileName}))
+	header.Set("X-Downloads-Remaining", strconv.Itoa(out.DownloadsRemaining))
+	if out.ETag != "" {
+		header.Set("ETag", out.ETag)
+	}
+	if !out.ModifiedAt.IsZero() {
+		header.Set("Last-Modified", out.ModifiedAt.UTC().Format(http.TimeFormat))
+	}
+	headOnly := r.Method == http.MethodHead
+	switch len(out.Ranges) {
+	case 0:
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Length", strconv.FormatInt(out.BytesTotal, 10))
+		w.WriteHeader(http.StatusOK)
+		if !headOnly {
+			copyRange(w, r, out.Content, domain.ByteRange{Start: 0, End: out.BytesTotal - 1})
+		}
+	case 1:
+		rng := out.Ranges[0]
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Range", contentRange(rng, out.BytesTotal))
+		header.Set("Content-Length", strconv.FormatInt(rng.Length(), 10))
+		w.WriteHeader(http.StatusPartialContent)
+		if !headOnly {
+			copyRange(w, r, out.Content, rng)
+		}
+	default:
+		parts := multipart.NewWriter(w)
+		header.Set("Content-Type", "multipart/byteranges; boundary="+parts.Boundary())
+		w.WriteHeader(http.StatusPartialContent)
+		if headOnly {
+			return
+		}
+		for _, rng := range out.Ranges {
+			part, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}, "Content-Range": {contentRange(rng, out.BytesTotal)}})
+			if err != nil || !copyRange(part, r, out.Content, rng) {
+				return
+			}
+		}
+		_ = parts.Close()
+	}
+}
+
+func copyRange(w io.Writer, r *http.Request, content *application.Content, rng domain.ByteRange) bool {
+	body, err := content.Open(r.Context(), rng)
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+	_, err = io.Copy(w, body)
+	return err == nil
+}
+
+func contentRange(rng domain.ByteRange, size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", rng.Start, rng.End, size)
 }
 
 func (h *Handler) revokeLinks(w http.ResponseWriter, r *http.Request) {
@@ -99,14 +211,41 @@ func (h *Handler) revokeLinks(w http.ResponseWriter, r *http.Request) {
 }
 
 func toProductFileResponse(row domain.ProductFile) contracts.ProductFileResponse {
-	return contracts.ProductFileResponse{FileID: row.FileID, ProductID: row.ProductID, FileName: row.FileName, ContentType: row.ContentType, SizeBytes: row.SizeBytes, Status: row.Status, CreatedAt: row.CreatedAt.UTC().Format(time.RFC3339), UpdatedAt: row.UpdatedAt.UTC().Format(time.RFC3339)}
+	return contracts.ProductFileResponse{FileID: row.FileID, ProductID: row.ProductID, FileName: row.FileName, ContentType: row.ContentType, SizeBytes: row.SizeBytes, StorageKey: row.StorageKey, Status: row.Status, CreatedAt: row.CreatedAt.UTC().Format(time.RFC3339), UpdatedAt: row.UpdatedAt.UTC().Format(time.RFC3339)}
 }
 
-func clientIP(r *http.Request) string {
-	if raw := strings.TrimSpace(r.Header.Get("X-Forwarded-For")); raw != "" {
-		parts := strings.Split(raw, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
+// clientCountry reads the ISO country code the edge resolved for the
+// client, if any.
+func (h *Handler) clientCountry(r *http.Request) string {
+	if h.edge.Country == "" {
+		return ""
+	}
+	if v := strings.TrimSpace(r.Header.Get(h.edge.Country)); v != "" && !strings.EqualFold(v, "XX") {
+		return v
+	}
+	return ""
+}
+
+// clientIP reads the client's address from the configured edge header. The
+// leftmost X-Forwarded-For entry is whatever the client sent, so only the
+// entry written by the outermost trusted proxy is used. Missing or
+// malformed values fall back to the connection's address.
+func (h *Handler) clientIP(r *http.Request) string {
+	if h.edge.ClientIP != "" {
+		var candidate string
+		if values := r.Header.Values(h.edge.ClientIP); http.CanonicalHeaderKey(h.edge.ClientIP) == "X-Forwarded-For" {
+			var hops []string
+			for _, v := range values {
+				hops = append(hops, strings.Split(v, ",")...)
+			}
+			if len(hops) >= h.edge.ProxyHops {
+				candidate = hops[len(hops)-h.edge.ProxyHops]
+			}
+		} else if len(values) == 1 {
+			candidate = values[0]
+		}
+		if ip := net.ParseIP(strings.TrimSpace(candidate)); ip != nil {
+			return ip.String()
 		}
 	}
 	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
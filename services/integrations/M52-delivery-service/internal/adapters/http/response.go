@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/contracts"
@@ -43,7 +44,11 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusForbidden, "DOWNLOAD_LIMIT_REACHED"
 	case domain.ErrRateLimited:
 		return http.StatusTooManyRequests, "RATE_LIMITED"
-	default:
-		return http.StatusInternalServerError, "INTERNAL_ERROR"
+	case domain.ErrRangeNotSatisfiable:
+		return http.StatusRequestedRangeNotSatisfiable, "RANGE_NOT_SATISFIABLE"
 	}
+	if errors.Is(err, domain.ErrWatermarkFailed) {
+		return http.StatusUnprocessableEntity, "WATERMARK_FAILED"
+	}
+	return http.StatusInternalServerError, "INTERNAL_ERROR"
 }
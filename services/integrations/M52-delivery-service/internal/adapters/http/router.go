@@ -16,6 +16,7 @@ func NewRouter(handler *Handler) http.Handler {
 		writeSuccess(w, http.StatusOK, map[string]string{"status": "ready"})
 	})
 	r.Get("/download/{token}", handler.downloadByToken)
+	r.Head("/download/{token}", handler.downloadByToken)
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware)
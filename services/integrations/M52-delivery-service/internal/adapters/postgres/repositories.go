@@ -175,6 +175,18 @@ func (r *DownloadEventRepository) LastByTokenUser(_ context.Context, tokenID, us
 	return *best, nil
 }
 
+func (r *DownloadEventRepository) ListByTokenSince(_ context.Context, tokenID string, since time.Time) ([]domain.DownloadEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.DownloadEvent{}
+	for _, row := range r.rows {
+		if row.TokenID == tokenID && !row.Timestamp.Before(since) {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
 type DownloadRevocationAuditRepository struct {
 	mu   sync.Mutex
 	rows []domain.DownloadRevocationAudit
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/ports"
+)
+
+// LocalFS serves product files from a directory. Keys are slash-separated
+// paths below the root and can never escape it.
+type LocalFS struct {
+	root string
+}
+
+func NewLocalFS(root string) *LocalFS { return &LocalFS{root: root} }
+
+func (s *LocalFS) Stat(_ context.Context, key string) (ports.BlobObject, error) {
+	info, err := os.Stat(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ports.BlobObject{}, domain.ErrNotFound
+		}
+		return ports.BlobObject{}, err
+	}
+	if info.IsDir() {
+		return ports.BlobObject{}, domain.ErrNotFound
+	}
+	return ports.BlobObject{
+		Key:        key,
+		Size:       info.Size(),
+		ETag:       fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		ModifiedAt: info.ModTime().UTC(),
+	}, nil
+}
+
+func (s *LocalFS) OpenRange(_ context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, length), f}, nil
+}
+
+func (s *LocalFS) path(key string) string {
+	clean := path.Clean("/" + strings.TrimSpace(key))
+	return filepath.Join(s.root, filepath.FromSlash(clean))
+}
@@ -6,16 +6,33 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/storage"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	ServiceID            string
-	HTTPPort             int
-	GRPCPort             int
-	PublicBaseURL        string
-	DefaultTokenTTL      time.Duration
-	DefaultMaxDownloads  int
+	ServiceID           string
+	HTTPPort            int
+	GRPCPort            int
+	PublicBaseURL       string
+	DefaultTokenTTL     time.Duration
+	DefaultMaxDownloads int
+	AnomalyWindow       time.Duration
+	AnomalyMaxIPs       int
+	AnomalyMaxCountries int
+	// CountryHeader is the header the edge sets to the client's GeoIP
+	// country code.
+	CountryHeader string
+	// ClientIPHeader is the header the edge sets to the client's address;
+	// for X-Forwarded-For, TrustedProxyHops counts the proxies appending
+	// to it. Empty uses the connection's address.
+	ClientIPHeader   string
+	TrustedProxyHops int
+	// BlobStore selects where product files are streamed from: "local"
+	// (BlobLocalRoot) or "s3" (S3). "none" serves download metadata only.
+	BlobStore            string
+	BlobLocalRoot        string
+	S3                   storage.S3Config
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
@@ -37,6 +54,15 @@ func LoadConfig(path string) (Config, error) {
 		PublicBaseURL:        "http://localhost:8080",
 		DefaultTokenTTL:      24 * time.Hour,
 		DefaultMaxDownloads:  5,
+		AnomalyWindow:        time.Hour,
+		AnomalyMaxIPs:        5,
+		AnomalyMaxCountries:  2,
+		CountryHeader:        "CF-IPCountry",
+		ClientIPHeader:       "CF-Connecting-IP",
+		TrustedProxyHops:     1,
+		BlobStore:            "local",
+		BlobLocalRoot:        "data/files",
+		S3:                   storage.S3Config{Region: "us-east-1"},
 		IdempotencyTTL:       7 * 24 * time.Hour,
 		EventDedupTTL:        7 * 24 * time.Hour,
 		ConsumerPollInterval: 2 * time.Second,
@@ -61,6 +87,21 @@ func LoadConfig(path string) (Config, error) {
 	cfg.PublicBaseURL = envString("PUBLIC_BASE_URL", cfg.PublicBaseURL)
 	cfg.DefaultTokenTTL = time.Duration(envInt("DELIVERY_TOKEN_TTL_HOURS", int(cfg.DefaultTokenTTL.Hours()))) * time.Hour
 	cfg.DefaultMaxDownloads = envInt("DELIVERY_MAX_DOWNLOADS", cfg.DefaultMaxDownloads)
+	cfg.AnomalyWindow = time.Duration(envInt("DOWNLOAD_ANOMALY_WINDOW_MINUTES", int(cfg.AnomalyWindow.Minutes()))) * time.Minute
+	cfg.AnomalyMaxIPs = envInt("DOWNLOAD_ANOMALY_MAX_IPS", cfg.AnomalyMaxIPs)
+	cfg.AnomalyMaxCountries = envInt("DOWNLOAD_ANOMALY_MAX_COUNTRIES", cfg.AnomalyMaxCountries)
+	cfg.CountryHeader = envString("DOWNLOAD_COUNTRY_HEADER", cfg.CountryHeader)
+	cfg.ClientIPHeader = envString("DOWNLOAD_CLIENT_IP_HEADER", cfg.ClientIPHeader)
+	cfg.TrustedProxyHops = envInt("DOWNLOAD_TRUSTED_PROXY_HOPS", cfg.TrustedProxyHops)
+	cfg.BlobStore = envString("BLOB_STORE", cfg.BlobStore)
+	cfg.BlobLocalRoot = envString("BLOB_LOCAL_ROOT", cfg.BlobLocalRoot)
+	cfg.S3.Endpoint = envString("S3_ENDPOINT", cfg.S3.Endpoint)
+	cfg.S3.Region = envString("S3_REGION", cfg.S3.Region)
+	cfg.S3.Bucket = envString("S3_BUCKET", cfg.S3.Bucket)
+	cfg.S3.AccessKeyID = envString("S3_ACCESS_KEY_ID", cfg.S3.AccessKeyID)
+	cfg.S3.SecretAccessKey = envString("S3_SECRET_ACCESS_KEY", cfg.S3.SecretAccessKey)
+	cfg.S3.SessionToken = envString("S3_SESSION_TOKEN", cfg.S3.SessionToken)
+	cfg.S3.PathStyle = envString("S3_PATH_STYLE", "false") == "true"
 	cfg.IdempotencyTTL = time.Duration(envInt("IDEMPOTENCY_TTL_HOURS", int(cfg.IdempotencyTTL.Hours()))) * time.Hour
 	cfg.EventDedupTTL = time.Duration(envInt("EVENT_DEDUP_TTL_HOURS", int(cfg.EventDedupTTL.Hours()))) * time.Hour
 	cfg.ConsumerPollInterval = time.Duration(envInt("CONSUMER_POLL_SECONDS", int(cfg.ConsumerPollInterval.Seconds()))) * time.Second
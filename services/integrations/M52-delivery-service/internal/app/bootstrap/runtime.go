@@ -12,53 +12,75 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/storage"
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/ports"
 	"google.golang.org/grpc"
 )
 
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	consumer := eventadapter.NewMemoryConsumer()
 	dlqPub := eventadapter.NewLoggingDLQPublisher()
+	var blobs ports.BlobStore
+	switch cfg.BlobStore {
+	case "local":
+		blobs = storage.NewLocalFS(cfg.BlobLocalRoot)
+	case "s3":
+		if cfg.S3.Endpoint == "" || cfg.S3.Bucket == "" {
+			return nil, fmt.Errorf("blob store s3 requires S3_ENDPOINT and S3_BUCKET")
+		}
+		blobs = storage.NewS3(cfg.S3, nil)
+	case "none", "":
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.BlobStore)
+	}
 	svc := application.NewService(application.Dependencies{
-		Config: application.Config{ServiceName: cfg.ServiceID, PublicBaseURL: cfg.PublicBaseURL, DefaultTokenTTL: cfg.DefaultTokenTTL, DefaultMaxDownloads: cfg.DefaultMaxDownloads, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval},
-		Files:  repos.Files, Tokens: repos.Tokens, Downloads: repos.Downloads, Revocations: repos.Revocations, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
+		Config: application.Config{ServiceName: cfg.ServiceID, PublicBaseURL: cfg.PublicBaseURL, DefaultTokenTTL: cfg.DefaultTokenTTL, DefaultMaxDownloads: cfg.DefaultMaxDownloads, AnomalyWindow: cfg.AnomalyWindow, AnomalyMaxIPs: cfg.AnomalyMaxIPs, AnomalyMaxCountries: cfg.AnomalyMaxCountries, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval},
+		Files:  repos.Files, Tokens: repos.Tokens, Downloads: repos.Downloads, Revocations: repos.Revocations, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Blobs: blobs,
 	})
-	handler := httpadapter.NewHandler(svc)
+	handler := httpadapter.NewHandler(svc, httpadapter.EdgeHeaders{Country: cfg.CountryHeader, ClientIP: cfg.ClientIPHeader, ProxyHops: cfg.TrustedProxyHops})
 	router := httpadapter.NewRouter(handler)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: router, ReadHeaderTimeout: 5 * time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(router), ReadHeaderTimeout: 5 * time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewDeliveryInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		return nil, err
 	}
 	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -85,6 +107,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -98,3 +121,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
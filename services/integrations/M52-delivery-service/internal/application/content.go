@@ -0,0 +1,256 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/ports"
+)
+
+// Content is a product file as delivered to one buyer. A watermarked file
+// is the stored object cut at some offset and followed by a generated tail,
+// so it can still be streamed and ranged without being buffered.
+type Content struct {
+	Size        int64
+	ETag        string
+	ModifiedAt  time.Time
+	Watermarked bool
+
+	blobs ports.BlobStore
+	key   string
+	cut   int64
+	tail  []byte
+}
+
+// Open streams the inclusive byte range r of the delivered content.
+func (c *Content) Open(ctx context.Context, r domain.ByteRange) (io.ReadCloser, error) {
+	if r.Start < 0 || r.End >= c.Size || r.End < r.Start {
+		return nil, domain.ErrRangeNotSatisfiable
+	}
+	readers := []io.Reader{}
+	var closer io.Closer = io.NopCloser(nil)
+	if r.Start < c.cut {
+		end := min64(r.End, c.cut-1)
+		body, err := c.blobs.OpenRange(ctx, c.key, r.Start, end-r.Start+1)
+		if err != nil {
+			return nil, err
+		}
+		readers, closer = append(readers, body), body
+	}
+	if r.End >= c.cut {
+		from := max64(r.Start, c.cut) - c.cut
+		readers = append(readers, bytes.NewReader(c.tail[from:r.End-c.cut+1]))
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(readers...), closer}, nil
+}
+
+// matchesIfRange reports whether an If-Range validator still describes the
+// content, in which case the Range header applies. Only strong entity tags
+// and exact Last-Modified dates match.
+func (c *Content) matchesIfRange(ifRange string) bool {
+	ifRange = strings.TrimSpace(ifRange)
+	switch {
+	case ifRange == "":
+		return true
+	case strings.HasPrefix(ifRange, "W/"):
+		return false
+	case strings.HasPrefix(ifRange, `"`):
+		return c.ETag != "" && ifRange == c.ETag
+	}
+	at, err := http.ParseTime(ifRange)
+	return err == nil && !c.ModifiedAt.IsZero() && at.Equal(c.ModifiedAt.Truncate(time.Second))
+}
+
+// tailWindow is how much of a file is read to find the structures a
+// watermark rewrites: a ZIP end of central directory with its maximum
+// comment, or a PDF trailer.
+const tailWindow = 22 + 65535
+
+func (s *Service) openContent(ctx context.Context, file domain.ProductFile, token domain.DownloadToken) (*Content, error) {
+	key := storageKey(file)
+	obj, err := s.blobs.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c := &Content{Size: obj.Size, ETag: obj.ETag, ModifiedAt: obj.ModifiedAt, blobs: s.blobs, key: key, cut: obj.Size}
+	kind := file.WatermarkKind()
+	if kind == "" {
+		return c, nil
+	}
+	readAt := func(offset, length int64) ([]byte, error) {
+		length = min64(length, obj.Size-offset)
+		if offset < 0 || length <= 0 {
+			return nil, fmt.Errorf("read outside file")
+		}
+		body, err := s.blobs.OpenRange(ctx, key, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	tailStart := max64(0, obj.Size-tailWindow)
+	tail, err := readAt(tailStart, obj.Size-tailStart)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrWatermarkFailed, err)
+	}
+	mark := watermark{BuyerID: token.UserID, TokenID: token.TokenID, ProductID: token.ProductID}
+	var cut int64
+	var appended []byte
+	switch kind {
+	case domain.WatermarkKindPDF:
+		cut, appended, err = watermarkPDF(tail, obj.Size, readAt, mark)
+	case domain.WatermarkKindZIP:
+		cut, appended, err = watermarkZIP(tail, tailStart, obj.Size, mark)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrWatermarkFailed, err)
+	}
+	c.cut, c.tail, c.Size, c.Watermarked = cut, appended, cut+int64(len(appended)), true
+	sum := sha256.Sum256([]byte(obj.ETag + "|" + token.UserID + "|" + token.TokenID))
+	c.ETag = `"wm-` + hex.EncodeToString(sum[:16]) + `"`
+	return c, nil
+}
+
+// watermark identifies the buyer a copy was delivered to. Its rendering is
+// deterministic so every request for the same token yields the same bytes
+// and a resumed download lines up with what was already received.
+type watermark struct {
+	BuyerID   string
+	TokenID   string
+	ProductID string
+}
+
+func (m watermark) text() string {
+	return fmt.Sprintf("Licensed to %s (token %s, product %s)", m.BuyerID, m.TokenID, m.ProductID)
+}
+
+var (
+	pdfStartXref = regexp.MustCompile(`startxref\s+(\d+)`)
+	pdfSize      = regexp.MustCompile(`/Size\s+(\d+)`)
+	pdfRoot      = regexp.MustCompile(`/Root\s+(\d+\s+\d+\s+R)`)
+	pdfID        = regexp.MustCompile(`/ID\s*\[[^\]]*\]`)
+)
+
+// watermarkPDF appends an incremental update whose document information
+// dictionary carries the buyer. The original bytes are left untouched. The
+// new Info dictionary replaces any existing one.
+func watermarkPDF(tail []byte, size int64, readAt func(offset, length int64) ([]byte, error), m watermark) (int64, []byte, error) {
+	matches := pdfStartXref.FindAllSubmatchIndex(tail, -1)
+	if len(matches) == 0 {
+		return 0, nil, fmt.Errorf("pdf: startxref not found")
+	}
+	last := matches[len(matches)-1]
+	prev, err := strconv.ParseInt(string(tail[last[2]:last[3]]), 10, 64)
+	if err != nil || prev <= 0 || prev >= size {
+		return 0, nil, fmt.Errorf("pdf: invalid startxref")
+	}
+	// Classic files keep the trailer right before startxref; with
+	// cross-reference streams the trailer keys sit in the stream's own
+	// dictionary at the startxref offset.
+	dict := []byte(nil)
+	if t := bytes.LastIndex(tail[:last[0]], []byte("trailer")); t >= 0 {
+		dict = tail[t:last[0]]
+	} else if dict, err = readAt(prev, 4096); err != nil {
+		return 0, nil, err
+	}
+	if bytes.Contains(dict, []byte("/Encrypt")) {
+		return 0, nil, fmt.Errorf("pdf: encrypted documents are not supported")
+	}
+	sizeMatch, rootMatch := pdfSize.FindSubmatch(dict), pdfRoot.FindSubmatch(dict)
+	if sizeMatch == nil || rootMatch == nil {
+		return 0, nil, fmt.Errorf("pdf: trailer without /Size or /Root")
+	}
+	objNum, err := strconv.ParseInt(string(sizeMatch[1]), 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString("\n")
+	objOffset := size + int64(b.Len())
+	fmt.Fprintf(&b, "%d 0 obj\n<< /Producer (M52-Delivery-Service) /BuyerID %s /DeliveryToken %s /Keywords %s >>\nendobj\n", objNum, pdfString(m.BuyerID), pdfString(m.TokenID), pdfString(m.text()))
+	xrefOffset := size + int64(b.Len())
+	fmt.Fprintf(&b, "xref\n%d 1\n%010d 00000 n \ntrailer\n<< /Size %d /Root %s /Info %d 0 R /Prev %d", objNum, objOffset, objNum+1, rootMatch[1], objNum, prev)
+	if id := pdfID.Find(dict); id != nil {
+		b.WriteString(" ")
+		b.Write(id)
+	}
+	fmt.Fprintf(&b, " >>\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return size, b.Bytes(), nil
+}
+
+func pdfString(v string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+const zipEOCDSignature = 0x06054b50
+
+// watermarkZIP rewrites the archive comment, the last field of the end of
+// central directory record, leaving every entry as it was.
+func watermarkZIP(tail []byte, tailStart, size int64, m watermark) (int64, []byte, error) {
+	for i := len(tail) - 22; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) != zipEOCDSignature {
+			continue
+		}
+		commentLen := int64(binary.LittleEndian.Uint16(tail[i+20:]))
+		if tailStart+int64(i)+22+commentLen != size {
+			continue
+		}
+		comment := m.text()
+		out := make([]byte, 2+len(comment))
+		binary.LittleEndian.PutUint16(out, uint16(len(comment)))
+		copy(out[2:], comment)
+		return tailStart + int64(i) + 20, out, nil
+	}
+	return 0, nil, fmt.Errorf("zip: end of central directory not found")
+}
+
+func storageKey(file domain.ProductFile) string {
+	if key := strings.TrimSpace(file.StorageKey); key != "" {
+		return key
+	}
+	return file.ProductID + "/" + file.FileName
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -25,6 +26,7 @@ func (s *Service) UpsertProductFile(ctx context.Context, actor Actor, in UpsertP
 	in.FileName = strings.TrimSpace(in.FileName)
 	in.ContentType = strings.TrimSpace(in.ContentType)
 	in.Status = strings.TrimSpace(in.Status)
+	in.StorageKey = strings.Trim(strings.TrimSpace(in.StorageKey), "/")
 	if in.ProductID == "" || in.FileName == "" || in.SizeBytes <= 0 {
 		return domain.ProductFile{}, domain.ErrInvalidInput
 	}
@@ -37,7 +39,22 @@ func (s *Service) UpsertProductFile(ctx context.Context, actor Actor, in UpsertP
 	if in.FileID == "" {
 		in.FileID = "file_" + uuid.NewString()
 	}
-	requestHash := hashJSON(map[string]any{"op": "upsert_product_file", "actor": actor.SubjectID, "product_id": in.ProductID, "file_id": in.FileID, "file_name": in.FileName, "content_type": in.ContentType, "size_bytes": in.SizeBytes, "status": in.Status})
+	key := in.StorageKey
+	if key == "" {
+		key = in.ProductID + "/" + in.FileName
+	}
+	if !validStorageKey(in.ProductID, key) {
+		return domain.ProductFile{}, domain.ErrInvalidInput
+	}
+	existing, err := s.files.GetByProductID(ctx, in.ProductID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return domain.ProductFile{}, err
+	}
+	owned := err == nil
+	if owned && existing.SellerID != actor.SubjectID && !isAdminOrSupport(actor) {
+		return domain.ProductFile{}, domain.ErrForbidden
+	}
+	requestHash := hashJSON(map[string]any{"op": "upsert_product_file", "actor": actor.SubjectID, "product_id": in.ProductID, "file_id": in.FileID, "file_name": in.FileName, "content_type": in.ContentType, "size_bytes": in.SizeBytes, "storage_key": in.StorageKey, "status": in.Status})
 	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.ProductFile{}, err
 	} else if ok {
@@ -50,8 +67,9 @@ func (s *Service) UpsertProductFile(ctx context.Context, actor Actor, in UpsertP
 		return domain.ProductFile{}, err
 	}
 	now := s.nowFn()
-	row := domain.ProductFile{FileID: in.FileID, ProductID: in.ProductID, FileName: in.FileName, ContentType: in.ContentType, SizeBytes: in.SizeBytes, Status: in.Status, CreatedAt: now, UpdatedAt: now}
-	if existing, err := s.files.GetByProductID(ctx, in.ProductID); err == nil {
+	row := domain.ProductFile{FileID: in.FileID, ProductID: in.ProductID, FileName: in.FileName, ContentType: in.ContentType, SizeBytes: in.SizeBytes, StorageKey: in.StorageKey, Status: in.Status, SellerID: actor.SubjectID, CreatedAt: now, UpdatedAt: now}
+	if owned {
+		row.SellerID = existing.SellerID
 		row.CreatedAt = existing.CreatedAt
 	}
 	if err := s.files.Upsert(ctx, row); err != nil {
@@ -117,14 +135,21 @@ func (s *Service) GetDownloadLink(ctx context.Context, actor Actor, in GetDownlo
 	return out, nil
 }
 
+// DownloadByToken authorises a download and, when a blob store is
+// configured, prepares the content to stream. A request without a range, or
+// whose first range starts at byte zero, is a new download and uses one up;
+// a range further in resumes an earlier download. Resumes are bounded by a
+// byte budget of MaxDownloads copies of the file.
 func (s *Service) DownloadByToken(ctx context.Context, in DownloadRequest) (DownloadResult, error) {
 	in.Token = strings.TrimSpace(in.Token)
+	in.IPAddress = strings.TrimSpace(in.IPAddress)
+	in.Country = strings.ToUpper(strings.TrimSpace(in.Country))
 	if in.Token == "" {
 		return DownloadResult{}, domain.ErrInvalidInput
 	}
 	now := s.nowFn()
-	if ip := strings.TrimSpace(in.IPAddress); ip != "" && s.downloads != nil {
-		count, err := s.downloads.CountByIPSince(ctx, ip, now.Add(-1*time.Minute))
+	if in.IPAddress != "" && s.downloads != nil && !in.HeadOnly {
+		count, err := s.downloads.CountByIPSince(ctx, in.IPAddress, now.Add(-1*time.Minute))
 		if err != nil {
 			return DownloadResult{}, err
 		}
@@ -142,22 +167,105 @@ func (s *Service) DownloadByToken(ctx context.Context, in DownloadRequest) (Down
 	if now.After(token.ExpiresAt) {
 		return DownloadResult{}, domain.ErrTokenExpired
 	}
-	if token.DownloadCount >= token.MaxDownloads {
-		return DownloadResult{}, domain.ErrDownloadLimitReached
-	}
 	file, err := s.files.GetByProductID(ctx, token.ProductID)
 	if err != nil {
 		return DownloadResult{}, err
 	}
-	token.DownloadCount++
+	out := DownloadResult{ProductID: file.ProductID, FileID: file.FileID, FileName: file.FileName, ContentType: file.ContentType, BytesTotal: file.SizeBytes, DownloadsRemaining: max(0, token.MaxDownloads-token.DownloadCount)}
+	if s.blobs != nil {
+		content, err := s.openContent(ctx, file, token)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+		out.Content, out.BytesTotal, out.ETag, out.ModifiedAt, out.Watermarked = content, content.Size, content.ETag, content.ModifiedAt, content.Watermarked
+		if in.RangeHeader != "" && content.matchesIfRange(in.IfRange) {
+			if out.Ranges, err = domain.ParseRange(in.RangeHeader, content.Size); err != nil {
+				return out, err
+			}
+		}
+	}
+	resumed := len(out.Ranges) > 0 && out.Ranges[0].Start > 0
+	served := out.BytesTotal
+	if len(out.Ranges) > 0 {
+		served = 0
+		for _, r := range out.Ranges {
+			served += r.Length()
+		}
+	}
+	switch {
+	case !resumed && token.DownloadCount >= token.MaxDownloads:
+		return DownloadResult{}, domain.ErrDownloadLimitReached
+	case resumed && (token.DownloadCount == 0 || token.BytesServed+served > int64(token.MaxDownloads)*out.BytesTotal):
+		return DownloadResult{}, domain.ErrDownloadLimitReached
+	}
+	if in.HeadOnly {
+		return out, nil
+	}
+	if reason, err := s.detectAnomaly(ctx, token, in, now); err != nil {
+		return DownloadResult{}, err
+	} else if reason != "" {
+		if err := s.revokeToken(ctx, token, reason, domain.RevokedBySystem, now); err != nil {
+			return DownloadResult{}, err
+		}
+		return DownloadResult{}, domain.ErrAccessRevoked
+	}
+	if !resumed {
+		token.DownloadCount++
+	}
+	token.BytesServed += served
 	token.LastDownloadAt = &now
 	if err := s.tokens.Update(ctx, token); err != nil {
 		return DownloadResult{}, err
 	}
+	status := domain.DownloadStatusCompleted
+	if resumed {
+		status = domain.DownloadStatusPartial
+	}
 	if s.downloads != nil {
-		_ = s.downloads.Append(ctx, domain.DownloadEvent{DownloadID: "dl_" + uuid.NewString(), TokenID: token.TokenID, ProductID: token.ProductID, UserID: token.UserID, IPAddress: strings.TrimSpace(in.IPAddress), Timestamp: now, DownloadStatus: "completed", BytesTotal: file.SizeBytes, BytesDownloaded: file.SizeBytes, DurationMillis: 50})
+		_ = s.downloads.Append(ctx, domain.DownloadEvent{DownloadID: "dl_" + uuid.NewString(), TokenID: token.TokenID, ProductID: token.ProductID, UserID: token.UserID, IPAddress: in.IPAddress, Country: in.Country, Timestamp: now, DownloadStatus: status, BytesTotal: out.BytesTotal, BytesDownloaded: served, Watermarked: out.Watermarked})
+	}
+	out.DownloadsRemaining = max(0, token.MaxDownloads-token.DownloadCount)
+	return out, nil
+}
+
+// detectAnomaly looks at where a token has been used from within the
+// anomaly window, this request included, and names the rule it breaks.
+func (s *Service) detectAnomaly(ctx context.Context, token domain.DownloadToken, in DownloadRequest, now time.Time) (string, error) {
+	if s.downloads == nil {
+		return "", nil
+	}
+	events, err := s.downloads.ListByTokenSince(ctx, token.TokenID, now.Add(-s.cfg.AnomalyWindow))
+	if err != nil {
+		return "", err
+	}
+	ips, countries := map[string]bool{}, map[string]bool{}
+	for _, ev := range append(events, domain.DownloadEvent{IPAddress: in.IPAddress, Country: in.Country}) {
+		if ev.IPAddress != "" {
+			ips[ev.IPAddress] = true
+		}
+		if ev.Country != "" {
+			countries[ev.Country] = true
+		}
+	}
+	switch {
+	case len(ips) > s.cfg.AnomalyMaxIPs:
+		return fmt.Sprintf("anomaly: downloads from %d distinct IP addresses within %s", len(ips), s.cfg.AnomalyWindow), nil
+	case len(countries) > s.cfg.AnomalyMaxCountries:
+		return fmt.Sprintf("anomaly: downloads from %d distinct countries within %s", len(countries), s.cfg.AnomalyWindow), nil
+	}
+	return "", nil
+}
+
+func (s *Service) revokeToken(ctx context.Context, row domain.DownloadToken, reason, revokedBy string, now time.Time) error {
+	row.Revoked = true
+	row.RevokedAt = &now
+	if err := s.tokens.Update(ctx, row); err != nil {
+		return err
+	}
+	if s.revocations != nil {
+		_ = s.revocations.Append(ctx, domain.DownloadRevocationAudit{RevocationID: "rev_" + uuid.NewString(), TokenID: row.TokenID, ProductID: row.ProductID, UserID: row.UserID, RevokedAt: now, Reason: reason, RevokedBy: revokedBy})
 	}
-	return DownloadResult{ProductID: file.ProductID, FileID: file.FileID, FileName: file.FileName, ContentType: file.ContentType, BytesTotal: file.SizeBytes, DownloadsRemaining: max(0, token.MaxDownloads-token.DownloadCount)}, nil
+	return nil
 }
 
 func (s *Service) RevokeLinks(ctx context.Context, actor Actor, in RevokeLinksInput) (RevokeLinksResult, error) {
@@ -198,15 +306,10 @@ func (s *Service) RevokeLinks(ctx context.Context, actor Actor, in RevokeLinksIn
 		if row.Revoked {
 			continue
 		}
-		row.Revoked = true
-		row.RevokedAt = &now
-		if err := s.tokens.Update(ctx, row); err != nil {
+		if err := s.revokeToken(ctx, row, in.Reason, actor.SubjectID, now); err != nil {
 			return RevokeLinksResult{}, err
 		}
 		revoked++
-		if s.revocations != nil {
-			_ = s.revocations.Append(ctx, domain.DownloadRevocationAudit{RevocationID: "rev_" + uuid.NewString(), TokenID: row.TokenID, ProductID: row.ProductID, UserID: row.UserID, RevokedAt: now, Reason: in.Reason, RevokedBy: actor.SubjectID})
-		}
 	}
 	out := RevokeLinksResult{ProductID: in.ProductID, UserID: in.UserID, RevokedCount: revoked, RevokedAt: now}
 	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, out)
@@ -231,6 +334,21 @@ func (s *Service) downloadLinkFromToken(token domain.DownloadToken, file domain.
 	}
 }
 
+// validStorageKey reports whether key names an object inside the product's
+// own prefix, so a seller cannot point their product at another seller's
+// files.
+func validStorageKey(productID, key string) bool {
+	if strings.ContainsAny(productID, "/\\") || !strings.HasPrefix(key, productID+"/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(key, productID+"/"), "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
 func isAdminOrSupport(actor Actor) bool {
 	r := strings.ToLower(strings.TrimSpace(actor.Role))
 	return r == "admin" || r == "support"
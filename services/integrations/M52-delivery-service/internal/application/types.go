@@ -3,14 +3,21 @@ package application
 import (
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/ports"
 )
 
 type Config struct {
-	ServiceName          string
-	PublicBaseURL        string
-	DefaultTokenTTL      time.Duration
-	DefaultMaxDownloads  int
+	ServiceName         string
+	PublicBaseURL       string
+	DefaultTokenTTL     time.Duration
+	DefaultMaxDownloads int
+	// A token is revoked automatically once its downloads within
+	// AnomalyWindow come from more than AnomalyMaxIPs distinct addresses or
+	// more than AnomalyMaxCountries distinct countries.
+	AnomalyWindow        time.Duration
+	AnomalyMaxIPs        int
+	AnomalyMaxCountries  int
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
@@ -29,6 +36,7 @@ type UpsertProductFileInput struct {
 	FileName    string
 	ContentType string
 	SizeBytes   int64
+	StorageKey  string
 	Status      string
 }
 
@@ -41,7 +49,12 @@ type GetDownloadLinkInput struct {
 type DownloadRequest struct {
 	Token       string
 	IPAddress   string
+	Country     string
 	RangeHeader string
+	IfRange     string
+	// HeadOnly describes the download without serving it, so it neither
+	// uses up a download nor counts towards anomaly detection.
+	HeadOnly bool
 }
 
 type RevokeLinksInput struct {
@@ -76,6 +89,13 @@ type DownloadResult struct {
 	ContentType        string
 	BytesTotal         int64
 	DownloadsRemaining int
+	ETag               string
+	ModifiedAt         time.Time
+	Watermarked        bool
+	// Ranges is empty when the whole file is served. Content is nil when no
+	// blob store is configured and only metadata can be returned.
+	Ranges  []domain.ByteRange
+	Content *Content
 }
 
 type Service struct {
@@ -86,6 +106,7 @@ type Service struct {
 	revocations ports.DownloadRevocationAuditRepository
 	idempotency ports.IdempotencyRepository
 	eventDedup  ports.EventDedupRepository
+	blobs       ports.BlobStore
 	nowFn       func() time.Time
 }
 
@@ -97,6 +118,7 @@ type Dependencies struct {
 	Revocations ports.DownloadRevocationAuditRepository
 	Idempotency ports.IdempotencyRepository
 	EventDedup  ports.EventDedupRepository
+	Blobs       ports.BlobStore
 }
 
 func NewService(deps Dependencies) *Service {
@@ -113,6 +135,15 @@ func NewService(deps Dependencies) *Service {
 	if cfg.DefaultMaxDownloads <= 0 {
 		cfg.DefaultMaxDownloads = 5
 	}
+	if cfg.AnomalyWindow <= 0 {
+		cfg.AnomalyWindow = time.Hour
+	}
+	if cfg.AnomalyMaxIPs <= 0 {
+		cfg.AnomalyMaxIPs = 5
+	}
+	if cfg.AnomalyMaxCountries <= 0 {
+		cfg.AnomalyMaxCountries = 2
+	}
 	if cfg.IdempotencyTTL <= 0 {
 		cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
@@ -130,6 +161,7 @@ func NewService(deps Dependencies) *Service {
 		revocations: deps.Revocations,
 		idempotency: deps.Idempotency,
 		eventDedup:  deps.EventDedup,
+		blobs:       deps.Blobs,
 		nowFn:       func() time.Time { return time.Now().UTC() },
 	}
 }
@@ -16,6 +16,7 @@ type UpsertProductFileRequest struct {
 	FileName    string `json:"file_name"`
 	ContentType string `json:"content_type"`
 	SizeBytes   int64  `json:"size_bytes"`
+	StorageKey  string `json:"storage_key,omitempty"`
 	Status      string `json:"status,omitempty"`
 }
 
@@ -25,6 +26,7 @@ type ProductFileResponse struct {
 	FileName    string `json:"file_name"`
 	ContentType string `json:"content_type"`
 	SizeBytes   int64  `json:"size_bytes"`
+	StorageKey  string `json:"storage_key,omitempty"`
 	Status      string `json:"status"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
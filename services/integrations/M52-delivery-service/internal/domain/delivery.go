@@ -1,6 +1,25 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+const (
+	WatermarkKindPDF = "pdf"
+	WatermarkKindZIP = "zip"
+)
+
+// Download statuses. A partial download served a byte range that did not
+// start at the beginning of the file, i.e. a resumed transfer.
+const (
+	DownloadStatusCompleted = "completed"
+	DownloadStatusPartial   = "partial"
+)
+
+// RevokedBySystem marks revocations made by anomaly detection rather than
+// by a member of staff.
+const RevokedBySystem = "system"
 
 type ProductFile struct {
 	FileID      string    `json:"file_id"`
@@ -8,11 +27,32 @@ type ProductFile struct {
 	FileName    string    `json:"file_name"`
 	ContentType string    `json:"content_type"`
 	SizeBytes   int64     `json:"size_bytes"`
+	StorageKey  string    `json:"storage_key"`
 	Status      string    `json:"status"`
+	SellerID    string    `json:"seller_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Watermarkable reports whether the file is stamped with the buyer's
+// identity at delivery time. Only PDFs and ZIP archives are.
+func (f ProductFile) Watermarkable() bool {
+	return f.WatermarkKind() != ""
+}
+
+func (f ProductFile) WatermarkKind() string {
+	ct := strings.ToLower(strings.TrimSpace(f.ContentType))
+	name := strings.ToLower(f.FileName)
+	switch {
+	case ct == "application/pdf" || strings.HasSuffix(name, ".pdf"):
+		return WatermarkKindPDF
+	case ct == "application/zip" || ct == "application/x-zip-compressed" || strings.HasSuffix(name, ".zip"):
+		return WatermarkKindZIP
+	default:
+		return ""
+	}
+}
+
 type DownloadToken struct {
 	TokenID        string     `json:"token_id"`
 	Token          string     `json:"token"`
@@ -22,6 +62,7 @@ type DownloadToken struct {
 	ExpiresAt      time.Time  `json:"expires_at"`
 	DownloadCount  int        `json:"download_count"`
 	MaxDownloads   int        `json:"max_downloads"`
+	BytesServed    int64      `json:"bytes_served"`
 	SingleUse      bool       `json:"single_use"`
 	Revoked        bool       `json:"revoked"`
 	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
@@ -34,11 +75,13 @@ type DownloadEvent struct {
 	ProductID       string    `json:"product_id"`
 	UserID          string    `json:"user_id,omitempty"`
 	IPAddress       string    `json:"ip_address"`
+	Country         string    `json:"country,omitempty"`
 	Timestamp       time.Time `json:"timestamp"`
 	DownloadStatus  string    `json:"download_status"`
 	BytesTotal      int64     `json:"bytes_total"`
 	BytesDownloaded int64     `json:"bytes_downloaded"`
 	DurationMillis  int64     `json:"duration_millis"`
+	Watermarked     bool      `json:"watermarked"`
 }
 
 type DownloadRevocationAudit struct {
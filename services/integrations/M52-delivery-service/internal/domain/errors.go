@@ -17,4 +17,6 @@ var (
 	ErrAccessRevoked         = errors.New("access_revoked")
 	ErrRateLimited           = errors.New("rate_limited")
 	ErrDownloadLimitReached  = errors.New("download_limit_reached")
+	ErrRangeNotSatisfiable   = errors.New("range_not_satisfiable")
+	ErrWatermarkFailed       = errors.New("watermark_failed")
 )
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxByteRanges caps how many ranges one request may ask for. Requests for
+// more are served in full rather than as a multipart response.
+const MaxByteRanges = 16
+
+// ByteRange is an inclusive byte range of a representation.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+func (r ByteRange) Length() int64 { return r.End - r.Start + 1 }
+
+// ParseRange interprets an RFC 7233 Range header against a representation of
+// size bytes. It returns no ranges when the header is absent, malformed or
+// not worth honouring, meaning the whole representation should be sent, and
+// ErrRangeNotSatisfiable when every range lies past the end. Overlapping and
+// adjacent ranges are merged.
+func ParseRange(header string, size int64) ([]ByteRange, error) {
+	header = strings.TrimSpace(header)
+	unit, spec, ok := strings.Cut(header, "=")
+	if header == "" || !ok || strings.TrimSpace(strings.ToLower(unit)) != "bytes" {
+		return nil, nil
+	}
+	parts := strings.Split(spec, ",")
+	if len(parts) > MaxByteRanges {
+		return nil, nil
+	}
+	out := []ByteRange{}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		first, last, ok := strings.Cut(part, "-")
+		if part == "" || !ok {
+			return nil, nil
+		}
+		first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+		var r ByteRange
+		switch {
+		case first == "":
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, nil
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = ByteRange{Start: size - n, End: size - 1}
+		default:
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, nil
+			}
+			end := size - 1
+			if last != "" {
+				if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+					return nil, nil
+				}
+			}
+			if start >= size {
+				continue
+			}
+			if end >= size {
+				end = size - 1
+			}
+			r = ByteRange{Start: start, End: end}
+		}
+		out = append(out, r)
+	}
+	if len(out) == 0 {
+		return nil, ErrRangeNotSatisfiable
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start < out[j].Start })
+	merged := []ByteRange{out[0]}
+	for _, r := range out[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End+1 {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BlobObject describes a stored file. ETag is the store's entity tag,
+// quoted as in HTTP.
+type BlobObject struct {
+	Key        string
+	Size       int64
+	ETag       string
+	ModifiedAt time.Time
+}
+
+// BlobStore is where product files live. OpenRange reads length bytes
+// starting at offset; implementations must return domain.ErrNotFound for
+// missing keys.
+type BlobStore interface {
+	Stat(ctx context.Context, key string) (BlobObject, error)
+	OpenRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+}
@@ -25,6 +25,7 @@ type DownloadEventRepository interface {
 	Append(ctx context.Context, row domain.DownloadEvent) error
 	CountByIPSince(ctx context.Context, ip string, since time.Time) (int, error)
 	LastByTokenUser(ctx context.Context, tokenID, userID string) (domain.DownloadEvent, error)
+	ListByTokenSince(ctx context.Context, tokenID string, since time.Time) ([]domain.DownloadEvent, error)
 }
 
 type DownloadRevocationAuditRepository interface {
@@ -1,11 +1,24 @@
 package unit
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	httpadapter "github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/adapters/storage"
 	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M52-delivery-service/internal/domain"
 )
 
 func newService() *application.Service {
@@ -61,3 +74,258 @@ func TestRevokeLinksBlocksDownload(t *testing.T) {
 		t.Fatalf("expected revoked token failure")
 	}
 }
+
+func newStreamingService(t *testing.T, cfg application.Config) (*application.Service, *postgres.Repositories, string) {
+	t.Helper()
+	root := t.TempDir()
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{
+		Config: cfg,
+		Files:  repos.Files, Tokens: repos.Tokens, Downloads: repos.Downloads, Revocations: repos.Revocations, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup,
+		Blobs: storage.NewLocalFS(root),
+	})
+	return svc, repos, root
+}
+
+func TestUpsertProductFileIsScopedToSellerAndProduct(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	seller := application.Actor{SubjectID: "seller-1", Role: "creator", IdempotencyKey: "idem-own-1"}
+	row, err := svc.UpsertProductFile(ctx, seller, application.UpsertProductFileInput{ProductID: "prod-own", FileName: "guide.pdf", SizeBytes: 10})
+	if err != nil || row.SellerID != "seller-1" {
+		t.Fatalf("expected seller to own the file, got %+v (%v)", row, err)
+	}
+	other := application.Actor{SubjectID: "seller-2", Role: "creator", IdempotencyKey: "idem-own-2"}
+	if _, err := svc.UpsertProductFile(ctx, other, application.UpsertProductFileInput{ProductID: "prod-own", FileName: "guide.pdf", SizeBytes: 10}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected another seller to be refused, got %v", err)
+	}
+	for i, key := range []string{"prod-victim/secret.zip", "prod-mine/../prod-victim/secret.zip", "prod-mine//secret.zip"} {
+		actor := application.Actor{SubjectID: "seller-2", Role: "creator", IdempotencyKey: fmt.Sprintf("idem-key-%d", i)}
+		if _, err := svc.UpsertProductFile(ctx, actor, application.UpsertProductFileInput{ProductID: "prod-mine", FileName: "secret.zip", SizeBytes: 10, StorageKey: key}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("expected storage key %q to be refused, got %v", key, err)
+		}
+	}
+	actor := application.Actor{SubjectID: "seller-2", Role: "creator", IdempotencyKey: "idem-key-ok"}
+	if _, err := svc.UpsertProductFile(ctx, actor, application.UpsertProductFileInput{ProductID: "prod-mine", FileName: "secret.zip", SizeBytes: 10, StorageKey: "/prod-mine/v2/secret.zip"}); err != nil {
+		t.Fatalf("expected a key under the product prefix to be accepted, got %v", err)
+	}
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-own-admin"}
+	row, err = svc.UpsertProductFile(ctx, admin, application.UpsertProductFileInput{ProductID: "prod-own", FileName: "guide-v2.pdf", SizeBytes: 12})
+	if err != nil || row.SellerID != "seller-1" {
+		t.Fatalf("expected admin edit to keep the seller, got %+v (%v)", row, err)
+	}
+}
+
+// publishFile stores data under productID/fileName and returns a link for buyer.
+func publishFile(t *testing.T, svc *application.Service, root, productID, fileName, contentType, buyer string, data []byte) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, productID), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, productID, fileName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-file-" + productID}
+	if _, err := svc.UpsertProductFile(context.Background(), admin, application.UpsertProductFileInput{ProductID: productID, FileName: fileName, ContentType: contentType, SizeBytes: int64(len(data))}); err != nil {
+		t.Fatalf("upsert file: %v", err)
+	}
+	link, err := svc.GetDownloadLink(context.Background(), application.Actor{SubjectID: buyer, Role: "creator", IdempotencyKey: "idem-link-" + productID}, application.GetDownloadLinkInput{ProductID: productID, MaxDownloads: 2})
+	if err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+	return link.Token
+}
+
+func readAll(t *testing.T, out application.DownloadResult) []byte {
+	t.Helper()
+	ranges := out.Ranges
+	if len(ranges) == 0 {
+		ranges = []domain.ByteRange{{Start: 0, End: out.BytesTotal - 1}}
+	}
+	var buf bytes.Buffer
+	for _, r := range ranges {
+		body, err := out.Content.Open(context.Background(), r)
+		if err != nil {
+			t.Fatalf("open range: %v", err)
+		}
+		_, _ = io.Copy(&buf, body)
+		_ = body.Close()
+	}
+	return buf.Bytes()
+}
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		header string
+		want   []domain.ByteRange
+		err    error
+	}{
+		{header: "", want: nil},
+		{header: "bytes=0-9", want: []domain.ByteRange{{Start: 0, End: 9}}},
+		{header: "bytes=90-", want: []domain.ByteRange{{Start: 90, End: 99}}},
+		{header: "bytes=-5", want: []domain.ByteRange{{Start: 95, End: 99}}},
+		{header: "bytes=50-500", want: []domain.ByteRange{{Start: 50, End: 99}}},
+		{header: "bytes=20-29, 0-4,25-40", want: []domain.ByteRange{{Start: 0, End: 4}, {Start: 20, End: 40}}},
+		{header: "bytes=200-300", err: domain.ErrRangeNotSatisfiable},
+		{header: "items=0-9", want: nil},
+		{header: "bytes=9-0", want: nil},
+	}
+	for _, tc := range cases {
+		got, err := domain.ParseRange(tc.header, 100)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%q: expected error %v, got %v", tc.header, tc.err, err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Fatalf("%q: expected %v, got %v", tc.header, tc.want, got)
+		}
+	}
+}
+
+func TestRangeDownloadsResumeWithoutUsingUpDownloads(t *testing.T) {
+	svc, _, root := newStreamingService(t, application.Config{})
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	token := publishFile(t, svc, root, "prod-r", "video.bin", "application/octet-stream", "user-r", data)
+
+	if _, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", RangeHeader: "bytes=500-"}); !errors.Is(err, domain.ErrDownloadLimitReached) {
+		t.Fatalf("expected a resume before any download to be refused, got %v", err)
+	}
+	first, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", RangeHeader: "bytes=0-499"})
+	if err != nil {
+		t.Fatalf("first range: %v", err)
+	}
+	if first.DownloadsRemaining != 1 || !bytes.Equal(readAll(t, first), data[:500]) {
+		t.Fatalf("unexpected first range: remaining %d", first.DownloadsRemaining)
+	}
+	resumed, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", RangeHeader: "bytes=500-", IfRange: first.ETag})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if resumed.DownloadsRemaining != 1 || !bytes.Equal(readAll(t, resumed), data[500:]) {
+		t.Fatalf("expected resume to return the rest without using a download, remaining %d", resumed.DownloadsRemaining)
+	}
+	multi, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", RangeHeader: "bytes=0-1,998-"})
+	if err != nil {
+		t.Fatalf("multi range: %v", err)
+	}
+	if len(multi.Ranges) != 2 || string(readAll(t, multi)) != "0189" {
+		t.Fatalf("unexpected multi range result %v", multi.Ranges)
+	}
+	stale, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", RangeHeader: "bytes=500-", IfRange: `"stale"`})
+	if !errors.Is(err, domain.ErrDownloadLimitReached) {
+		t.Fatalf("expected a stale If-Range to become a full, over-limit download, got %v (%v)", err, stale.Ranges)
+	}
+	if _, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", RangeHeader: "bytes=5000-"}); !errors.Is(err, domain.ErrRangeNotSatisfiable) {
+		t.Fatalf("expected unsatisfiable range, got %v", err)
+	}
+}
+
+func TestDownloadsAreWatermarkedPerBuyer(t *testing.T) {
+	svc, _, root := newStreamingService(t, application.Config{})
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	f, _ := zw.Create("readme.txt")
+	_, _ = f.Write([]byte("hello"))
+	_ = zw.SetComment("original")
+	_ = zw.Close()
+	zipToken := publishFile(t, svc, root, "prod-zip", "pack.zip", "application/zip", "buyer-42", archive.Bytes())
+	out, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: zipToken, IPAddress: "10.0.0.2"})
+	if err != nil {
+		t.Fatalf("zip download: %v", err)
+	}
+	body := readAll(t, out)
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("watermarked zip unreadable: %v", err)
+	}
+	if !out.Watermarked || !strings.Contains(zr.Comment, "buyer-42") || len(zr.File) != 1 {
+		t.Fatalf("expected buyer in zip comment, got %q", zr.Comment)
+	}
+	rc, _ := zr.File[0].Open()
+	content, _ := io.ReadAll(rc)
+	if string(content) != "hello" {
+		t.Fatalf("expected entries untouched, got %q", content)
+	}
+
+	pdf := "%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n2 0 obj\n<< /Type /Pages /Kids [] /Count 0 >>\nendobj\n"
+	xref := len(pdf)
+	pdf += fmt.Sprintf("xref\n0 3\n0000000000 65535 f \n%010d 00000 n \n%010d 00000 n \ntrailer\n<< /Size 3 /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", 9, strings.Index(pdf, "2 0 obj"), xref)
+	pdfToken := publishFile(t, svc, root, "prod-pdf", "guide.pdf", "application/pdf", "buyer-43", []byte(pdf))
+	out, err = svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: pdfToken, IPAddress: "10.0.0.2"})
+	if err != nil {
+		t.Fatalf("pdf download: %v", err)
+	}
+	body = readAll(t, out)
+	if !bytes.HasPrefix(body, []byte(pdf)) || !bytes.Contains(body, []byte("/BuyerID (buyer-43)")) || !bytes.Contains(body, []byte("/Prev "+fmt.Sprint(xref))) {
+		t.Fatalf("expected incremental update naming the buyer, got %q", body[len(pdf):])
+	}
+	tail, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: pdfToken, IPAddress: "10.0.0.2", RangeHeader: fmt.Sprintf("bytes=%d-", len(pdf)-4), IfRange: out.ETag})
+	if err != nil {
+		t.Fatalf("resume pdf: %v", err)
+	}
+	if !bytes.Equal(readAll(t, tail), body[len(pdf)-4:]) {
+		t.Fatalf("expected resumed bytes to match the watermarked copy")
+	}
+}
+
+func TestTokenUsedFromManyCountriesIsRevoked(t *testing.T) {
+	svc, repos, root := newStreamingService(t, application.Config{AnomalyMaxIPs: 3, AnomalyMaxCountries: 2})
+	token := publishFile(t, svc, root, "prod-a", "book.epub", "application/epub+zip", "user-a", []byte("book"))
+	_, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", Country: "de"})
+	if err != nil {
+		t.Fatalf("first download: %v", err)
+	}
+	if _, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.2", Country: "US", RangeHeader: "bytes=-1"}); err != nil {
+		t.Fatalf("second country: %v", err)
+	}
+	if _, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.3", Country: "BR", RangeHeader: "bytes=-1"}); !errors.Is(err, domain.ErrAccessRevoked) {
+		t.Fatalf("expected third country to revoke the token, got %v", err)
+	}
+	if _, err := svc.DownloadByToken(context.Background(), application.DownloadRequest{Token: token, IPAddress: "10.0.0.1", Country: "DE", RangeHeader: "bytes=-1"}); !errors.Is(err, domain.ErrAccessRevoked) {
+		t.Fatalf("expected token to stay revoked, got %v", err)
+	}
+	audits, _ := repos.Revocations.ListByProductUser(context.Background(), "prod-a", "user-a")
+	if len(audits) != 1 || audits[0].RevokedBy != domain.RevokedBySystem || !strings.Contains(audits[0].Reason, "3 distinct countries") {
+		t.Fatalf("expected one system revocation audit, got %+v", audits)
+	}
+}
+
+func TestSpoofedForwardedForDoesNotRevokeToken(t *testing.T) {
+	svc, repos, root := newStreamingService(t, application.Config{AnomalyMaxIPs: 2})
+	token := publishFile(t, svc, root, "prod-a", "book.epub", "application/epub+zip", "user-a", []byte("a book long enough to resume"))
+	router := httpadapter.NewRouter(httpadapter.NewHandler(svc, httpadapter.EdgeHeaders{ClientIP: "X-Forwarded-For", ProxyHops: 1}))
+	for i := 0; i < 4; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/download/"+token, nil)
+		// The client makes up the leftmost entries; the edge appends the
+		// address it saw.
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d, 203.0.113.7", i+1))
+		want := http.StatusOK
+		if i > 0 {
+			req.Header.Set("Range", "bytes=-1")
+			want = http.StatusPartialContent
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("download %d: expected %d, got %d %s", i, want, rec.Code, rec.Body.String())
+		}
+	}
+	if audits, _ := repos.Revocations.ListByProductUser(context.Background(), "prod-a", "user-a"); len(audits) != 0 {
+		t.Fatalf("expected spoofed addresses not to count as distinct clients, got %+v", audits)
+	}
+
+	router = httpadapter.NewRouter(httpadapter.NewHandler(svc, httpadapter.EdgeHeaders{ClientIP: "CF-Connecting-IP"}))
+	for i, ip := range []string{"203.0.113.8", "203.0.113.9"} {
+		req := httptest.NewRequest(http.MethodGet, "/download/"+token, nil)
+		req.Header.Set("CF-Connecting-IP", ip)
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.Header.Set("Range", "bytes=-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if i == 1 && !strings.Contains(rec.Body.String(), "ACCESS_DENIED") {
+			t.Fatalf("expected a third edge-reported address to revoke the token, got %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	if audits, _ := repos.Revocations.ListByProductUser(context.Background(), "prod-a", "user-a"); len(audits) != 1 {
+		t.Fatalf("expected one revocation from the edge header, got %+v", audits)
+	}
+}
@@ -2,6 +2,8 @@ package http
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"net"
 	"net/http"
 	"strconv"
@@ -48,12 +50,46 @@ func (h *Handler) renderEmbed(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	setEmbedSecurityHeaders(w)
+	setEmbedSecurityHeaders(w, out.FrameAncestors)
+	w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="alternate"; type="application/json+oembed"; title=%q`, out.OEmbedJSONURL, out.Title))
+	w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="alternate"; type="text/xml+oembed"; title=%q`, out.OEmbedXMLURL, out.Title))
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(out.HTML))
 }
 
+// oembed serves the oEmbed document for a pasted URL. Private content
+// answers 401 and unknown URLs 404, as the spec asks of providers.
+func (h *Handler) oembed(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	maxWidth, _ := strconv.Atoi(strings.TrimSpace(q.Get("maxwidth")))
+	maxHeight, _ := strconv.Atoi(strings.TrimSpace(q.Get("maxheight")))
+	out, err := h.service.OEmbed(r.Context(), application.OEmbedInput{URL: q.Get("url"), Format: q.Get("format"), MaxWidth: maxWidth, MaxHeight: maxHeight})
+	if err != nil {
+		code, c := mapDomainError(err)
+		if err == domain.ErrEmbeddingDisabled {
+			code, c = http.StatusUnauthorized, "unauthorized"
+		}
+		writeError(w, code, c, err.Error())
+		return
+	}
+	resp := contracts.OEmbedResponse{Type: out.Type, Version: out.Version, Title: out.Title, ProviderName: out.ProviderName, ProviderURL: out.ProviderURL, HTML: out.HTML, Width: out.Width, Height: out.Height, CacheAge: out.CacheAge}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if out.Format == application.OEmbedFormatXML {
+		raw, err := xml.Marshal(resp)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(xml.Header))
+		_, _ = w.Write(raw)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func (h *Handler) getSettings(w http.ResponseWriter, r *http.Request) {
 	entityType := chi.URLParam(r, "entity_type")
 	entityID := chi.URLParam(r, "entity_id")
@@ -70,11 +106,12 @@ func (h *Handler) getSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	embedCode, _ := h.service.GenerateEmbedCode(r.Context(), entityType, entityID)
+	ampCode, _ := h.service.GenerateAMPEmbedCode(r.Context(), entityType, entityID)
 	writeSuccess(w, http.StatusOK, contracts.EmbedSettingsResponse{
 		EntityType: settings.EntityType, EntityID: settings.EntityID,
 		AllowEmbedding: settings.AllowEmbedding, DefaultTheme: settings.DefaultTheme, PrimaryColor: settings.PrimaryColor,
 		CustomButtonText: settings.CustomButtonText, AutoPlayVideo: settings.AutoPlayVideo, ShowCreatorInfo: settings.ShowCreatorInfo,
-		WhitelistedDomains: settings.WhitelistedDomains, EmbedCode: embedCode, UpdatedAt: settings.UpdatedAt.UTC().Format(time.RFC3339),
+		WhitelistedDomains: settings.WhitelistedDomains, EmbedCode: embedCode, AMPEmbedCode: ampCode, UpdatedAt: settings.UpdatedAt.UTC().Format(time.RFC3339),
 		Metrics: contracts.SettingsMetrics{TotalImpressions: analytics.TotalImpressions, TotalInteractions: analytics.TotalInteractions, ClickThroughRate: analytics.ClickThroughRate, TopReferrers: toTopReferrers(analytics.ByReferrer)},
 	})
 }
@@ -96,7 +133,8 @@ func (h *Handler) postSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	embedCode, _ := h.service.GenerateEmbedCode(r.Context(), row.EntityType, row.EntityID)
-	writeSuccess(w, http.StatusOK, contracts.EmbedSettingsResponse{EntityType: row.EntityType, EntityID: row.EntityID, AllowEmbedding: row.AllowEmbedding, DefaultTheme: row.DefaultTheme, PrimaryColor: row.PrimaryColor, CustomButtonText: row.CustomButtonText, AutoPlayVideo: row.AutoPlayVideo, ShowCreatorInfo: row.ShowCreatorInfo, WhitelistedDomains: row.WhitelistedDomains, EmbedCode: embedCode, UpdatedAt: row.UpdatedAt.UTC().Format(time.RFC3339)})
+	ampCode, _ := h.service.GenerateAMPEmbedCode(r.Context(), row.EntityType, row.EntityID)
+	writeSuccess(w, http.StatusOK, contracts.EmbedSettingsResponse{EntityType: row.EntityType, EntityID: row.EntityID, AllowEmbedding: row.AllowEmbedding, DefaultTheme: row.DefaultTheme, PrimaryColor: row.PrimaryColor, CustomButtonText: row.CustomButtonText, AutoPlayVideo: row.AutoPlayVideo, ShowCreatorInfo: row.ShowCreatorInfo, WhitelistedDomains: row.WhitelistedDomains, EmbedCode: embedCode, AMPEmbedCode: ampCode, UpdatedAt: row.UpdatedAt.UTC().Format(time.RFC3339)})
 }
 
 func (h *Handler) getAnalytics(w http.ResponseWriter, r *http.Request) {
@@ -143,9 +181,11 @@ func toTopReferrers(in []application.ReferrerMetric) []contracts.ReferrerMetric
 	return out
 }
 
-func setEmbedSecurityHeaders(w http.ResponseWriter) {
-	w.Header().Set("X-Frame-Options", "ALLOW-FROM https://platform.com")
-	w.Header().Set("Content-Security-Policy", "frame-ancestors 'self' embed.platform.com;")
+// setEmbedSecurityHeaders restricts who may frame the embed to the entity's
+// allow-list. X-Frame-Options cannot express a list, so frame-ancestors is
+// the only framing control sent.
+func setEmbedSecurityHeaders(w http.ResponseWriter, frameAncestors []string) {
+	w.Header().Set("Content-Security-Policy", "frame-ancestors "+strings.Join(frameAncestors, " ")+";")
 	w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.Header().Set("X-XSS-Protection", "1; mode=block")
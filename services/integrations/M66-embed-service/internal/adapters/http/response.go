@@ -37,6 +37,8 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusConflict, "conflict"
 	case domain.ErrRateLimited:
 		return http.StatusTooManyRequests, "rate_limit_exceeded"
+	case domain.ErrUnsupportedFormat:
+		return http.StatusNotImplemented, "unsupported_format"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
@@ -16,6 +16,7 @@ func NewRouter(handler *Handler) http.Handler {
 		writeSuccess(w, http.StatusOK, map[string]string{"status": "ready"})
 	})
 	r.Get("/embed/{entity_type}/{entity_id}", handler.renderEmbed)
+	r.Get("/oembed", handler.oembed)
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware)
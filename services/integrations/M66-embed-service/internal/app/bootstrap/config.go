@@ -14,6 +14,8 @@ type Config struct {
 	HTTPPort             int
 	GRPCPort             int
 	EmbedBaseURL         string
+	PublicBaseURL        string
+	ProviderName         string
 	CacheTTL             time.Duration
 	PerIPLimitPerHour    int
 	PerEmbedLimitPerHour int
@@ -31,7 +33,7 @@ type configFile struct {
 }
 
 func LoadConfig(path string) (Config, error) {
-	cfg := Config{ServiceID: "M66-Embed-Service", HTTPPort: 8080, GRPCPort: 9090, EmbedBaseURL: "https://embed.platform.com", CacheTTL: 5 * time.Minute, PerIPLimitPerHour: 1000, PerEmbedLimitPerHour: 100, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second}
+	cfg := Config{ServiceID: "M66-Embed-Service", HTTPPort: 8080, GRPCPort: 9090, EmbedBaseURL: "https://embed.platform.com", PublicBaseURL: "https://platform.com", ProviderName: "Platform", CacheTTL: 5 * time.Minute, PerIPLimitPerHour: 1000, PerEmbedLimitPerHour: 100, IdempotencyTTL: 7 * 24 * time.Hour, EventDedupTTL: 7 * 24 * time.Hour, ConsumerPollInterval: 2 * time.Second}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
 		if err := yaml.Unmarshal(raw, &f); err != nil {
@@ -50,6 +52,8 @@ func LoadConfig(path string) (Config, error) {
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
 	cfg.EmbedBaseURL = envString("EMBED_BASE_URL", cfg.EmbedBaseURL)
+	cfg.PublicBaseURL = envString("PUBLIC_BASE_URL", cfg.PublicBaseURL)
+	cfg.ProviderName = envString("OEMBED_PROVIDER_NAME", cfg.ProviderName)
 	cfg.CacheTTL = time.Duration(envInt("CACHE_TTL_SECONDS", int(cfg.CacheTTL.Seconds()))) * time.Second
 	cfg.PerIPLimitPerHour = envInt("RATE_LIMIT_PER_IP", cfg.PerIPLimitPerHour)
 	cfg.PerEmbedLimitPerHour = envInt("RATE_LIMIT_PER_EMBED", cfg.PerEmbedLimitPerHour)
@@ -12,6 +12,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/adapters/http"
@@ -23,36 +24,42 @@ import (
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 	repos := postgres.NewRepositories()
 	opsPub := eventadapter.NewMemoryOpsPublisher()
-	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, EmbedBaseURL: cfg.EmbedBaseURL, CacheTTL: cfg.CacheTTL, PerIPLimitPerHour: cfg.PerIPLimitPerHour, PerEmbedLimitPerHour: cfg.PerEmbedLimitPerHour, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval}, Settings: repos.Settings, Cache: repos.Cache, Impressions: repos.Impressions, Interactions: repos.Interactions, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Ops: opsPub})
+	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, EmbedBaseURL: cfg.EmbedBaseURL, PublicBaseURL: cfg.PublicBaseURL, ProviderName: cfg.ProviderName, CacheTTL: cfg.CacheTTL, PerIPLimitPerHour: cfg.PerIPLimitPerHour, PerEmbedLimitPerHour: cfg.PerEmbedLimitPerHour, IdempotencyTTL: cfg.IdempotencyTTL, EventDedupTTL: cfg.EventDedupTTL, ConsumerPollInterval: cfg.ConsumerPollInterval}, Settings: repos.Settings, Cache: repos.Cache, Impressions: repos.Impressions, Interactions: repos.Interactions, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Ops: opsPub})
 	handler := httpadapter.NewHandler(svc)
-	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: httpadapter.NewRouter(handler), ReadHeaderTimeout: 5 * time.Second}
-	grpcServer := grpc.NewServer()
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(httpadapter.NewRouter(handler)), ReadHeaderTimeout: 5 * time.Second}
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewEmbedInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		return nil, err
 	}
 	worker := eventadapter.NewWorker(logger, eventadapter.NewMemoryConsumer(), eventadapter.NewLoggingDLQPublisher(), svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -78,6 +85,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -91,3 +99,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/domain"
+)
+
+const (
+	OEmbedFormatJSON = "json"
+	OEmbedFormatXML  = "xml"
+)
+
+const (
+	defaultEmbedWidth  = 600
+	defaultEmbedHeight = 600
+)
+
+// iframeSandbox keeps embeds away from the host page: scripts run only in
+// our origin and links open in a new, unsandboxed tab.
+const iframeSandbox = "allow-scripts allow-same-origin allow-popups allow-popups-to-escape-sandbox"
+
+// OEmbed answers an oEmbed request for a platform or embed URL. Consumers
+// that cannot fit the default size pass maxwidth/maxheight and get an iframe
+// no larger than that; the resize protocol grows it back to its content.
+func (s *Service) OEmbed(ctx context.Context, in OEmbedInput) (OEmbedResult, error) {
+	format := strings.ToLower(strings.TrimSpace(in.Format))
+	if format == "" {
+		format = OEmbedFormatJSON
+	}
+	if format != OEmbedFormatJSON && format != OEmbedFormatXML {
+		return OEmbedResult{}, domain.ErrUnsupportedFormat
+	}
+	if in.MaxWidth < 0 || in.MaxHeight < 0 {
+		return OEmbedResult{}, domain.ErrInvalidInput
+	}
+	entityType, entityID, ok := s.resolveEmbedURL(in.URL)
+	if !ok {
+		return OEmbedResult{}, domain.ErrNotFound
+	}
+	settings, err := s.GetOrDefaultSettings(ctx, entityType, entityID)
+	if err != nil {
+		return OEmbedResult{}, err
+	}
+	if !settings.AllowEmbedding {
+		return OEmbedResult{}, domain.ErrEmbeddingDisabled
+	}
+	width, height := defaultEmbedWidth, defaultEmbedHeight
+	if in.MaxWidth > 0 && width > in.MaxWidth {
+		width = in.MaxWidth
+	}
+	if in.MaxHeight > 0 && height > in.MaxHeight {
+		height = in.MaxHeight
+	}
+	title := embedTitle(entityType, entityID)
+	return OEmbedResult{
+		Format:       format,
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        title,
+		ProviderName: s.cfg.ProviderName,
+		ProviderURL:  strings.TrimRight(s.cfg.PublicBaseURL, "/"),
+		HTML:         s.iframeSnippet(settings, title, fmt.Sprint(width), height),
+		Width:        width,
+		Height:       height,
+		CacheAge:     int(s.cfg.CacheTTL.Seconds()),
+	}, nil
+}
+
+// GenerateAMPEmbedCode is the amp-iframe variant of the embed code. AMP
+// pages need the amp-iframe extension script; the embed asks AMP for its
+// height through the embed-size message.
+func (s *Service) GenerateAMPEmbedCode(ctx context.Context, entityType, entityID string) (string, error) {
+	settings, err := s.GetOrDefaultSettings(ctx, entityType, entityID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`<amp-iframe width="%d" height="%d" layout="responsive" sandbox="%s" resizable frameborder="0" src="%s"><div overflow tabindex="0" role="button" aria-label="Show more">Show more</div></amp-iframe>`, defaultEmbedWidth, defaultEmbedHeight, iframeSandbox, html.EscapeString(s.embedSrc(settings))), nil
+}
+
+// OEmbedURL is the discovery URL for an entity's oEmbed document.
+func (s *Service) OEmbedURL(entityType, entityID, format string) string {
+	target := fmt.Sprintf("%s/embed/%s/%s", strings.TrimRight(s.cfg.EmbedBaseURL, "/"), url.PathEscape(entityType), url.PathEscape(entityID))
+	return fmt.Sprintf("%s/oembed?url=%s&format=%s", strings.TrimRight(s.cfg.EmbedBaseURL, "/"), url.QueryEscape(target), format)
+}
+
+// resolveEmbedURL maps a URL pasted into a CMS to the entity it shows.
+// Both embed URLs (/embed/campaign/ABC) and public platform URLs
+// (/campaigns/ABC, /en/campaign/ABC) are recognised on either host.
+func (s *Service) resolveEmbedURL(raw string) (string, string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", false
+	}
+	host := bareHost(u.Hostname())
+	known := false
+	for _, base := range []string{s.cfg.EmbedBaseURL, s.cfg.PublicBaseURL} {
+		if b, err := url.Parse(base); err == nil && bareHost(b.Hostname()) == host {
+			known = true
+		}
+	}
+	if !known {
+		return "", "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		entityType := strings.TrimSuffix(strings.ToLower(segments[i]), "s")
+		entityID, err := url.PathUnescape(segments[i+1])
+		if err == nil && domain.IsValidEntityType(entityType) && strings.TrimSpace(entityID) != "" {
+			return entityType, entityID, true
+		}
+	}
+	return "", "", false
+}
+
+func (s *Service) embedSrc(settings domain.EmbedSettings) string {
+	return fmt.Sprintf("%s/embed/%s/%s?theme=%s&color=%s", strings.TrimRight(s.cfg.EmbedBaseURL, "/"), url.PathEscape(settings.EntityType), url.PathEscape(settings.EntityID), url.QueryEscape(settings.DefaultTheme), url.QueryEscape(settings.PrimaryColor))
+}
+
+// iframeSnippet renders the sandboxed iframe plus the host-side half of the
+// resize protocol: it listens for embed_resize messages from the embed
+// origin and sets the height of the iframe that sent them. The listener is
+// installed once per page however many embeds it holds.
+func (s *Service) iframeSnippet(settings domain.EmbedSettings, title, width string, height int) string {
+	origin := strings.TrimRight(s.cfg.EmbedBaseURL, "/")
+	if u, err := url.Parse(s.cfg.EmbedBaseURL); err == nil && u.Host != "" {
+		origin = u.Scheme + "://" + u.Host
+	}
+	return fmt.Sprintf(`<iframe width="%s" height="%d" src="%s" title="%s" sandbox="%s" frameborder="0" allowfullscreen loading="lazy" data-embed-resize></iframe>`+
+		`<script>(function(){if(window.__embedResize)return;window.__embedResize=true;window.addEventListener('message',function(e){if(e.origin!==%q||!e.data||e.data.event_type!=='embed_resize'||!(e.data.height>0))return;var frames=document.querySelectorAll('iframe[data-embed-resize]');for(var i=0;i<frames.length;i++){if(frames[i].contentWindow===e.source){frames[i].style.height=Math.ceil(e.data.height)+'px';}}});})();</script>`,
+		html.EscapeString(width), height, html.EscapeString(s.embedSrc(settings)), html.EscapeString(title), iframeSandbox, origin)
+}
+
+func embedTitle(entityType, entityID string) string {
+	if entityType == "" {
+		return entityID
+	}
+	return strings.ToUpper(entityType[:1]) + entityType[1:] + " " + entityID
+}
+
+func bareHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/domain"
 )
@@ -64,6 +65,7 @@ func (s *Service) RenderEmbed(ctx context.Context, in RenderEmbedInput) (Rendere
 	if buttonText == "" {
 		buttonText = defaultButtonText(in.EntityType)
 	}
+	rendered := RenderedEmbed{FrameAncestors: settings.FrameAncestors(), OEmbedJSONURL: s.OEmbedURL(in.EntityType, in.EntityID, OEmbedFormatJSON), OEmbedXMLURL: s.OEmbedURL(in.EntityType, in.EntityID, OEmbedFormatXML), Title: embedTitle(in.EntityType, in.EntityID)}
 	cacheKey := cacheKeyFor(in.EntityType, in.EntityID, themeUsed, colorUsed, buttonText, in.Language, in.AutoPlay)
 	if s.cache != nil {
 		if cached, err := s.cache.Get(ctx, cacheKey, now); err == nil && cached.HTML != "" {
@@ -78,10 +80,11 @@ func (s *Service) RenderEmbed(ctx context.Context, in RenderEmbedInput) (Rendere
 					"ip_anonymized":      ipMasked,
 				}, in.RequestID)
 			}
-			return RenderedEmbed{HTML: cached.HTML}, nil
+			rendered.HTML = cached.HTML
+			return rendered, nil
 		}
 	}
-	htmlDoc := renderHTML(in.EntityType, in.EntityID, themeUsed, colorUsed, buttonText, in.AutoPlay, in.Language)
+	htmlDoc := renderHTML(in.EntityType, in.EntityID, themeUsed, colorUsed, buttonText, in.AutoPlay, in.Language, rendered)
 	if s.cache != nil {
 		_ = s.cache.Put(ctx, domain.EmbedCache{CacheKey: cacheKey, EntityType: in.EntityType, EntityID: in.EntityID, HTML: htmlDoc, CreatedAt: now, ExpiresAt: now.Add(s.cfg.CacheTTL)})
 	}
@@ -96,7 +99,8 @@ func (s *Service) RenderEmbed(ctx context.Context, in RenderEmbedInput) (Rendere
 			"ip_anonymized":      ipMasked,
 		}, in.RequestID)
 	}
-	return RenderedEmbed{HTML: htmlDoc}, nil
+	rendered.HTML = htmlDoc
+	return rendered, nil
 }
 
 func (s *Service) GetOrDefaultSettings(ctx context.Context, entityType, entityID string) (domain.EmbedSettings, error) {
@@ -131,7 +135,11 @@ func (s *Service) UpdateSettings(ctx context.Context, actor Actor, in UpdateEmbe
 	}
 	in.PrimaryColor = normalizeColor(in.PrimaryColor)
 	in.CustomButtonText = normalizeButtonText(in.CustomButtonText)
-	requestHash := hashJSON(map[string]any{"op": "update_settings", "actor": actor.SubjectID, "entity_type": in.EntityType, "entity_id": in.EntityID, "allow": in.AllowEmbedding, "theme": in.DefaultTheme, "color": in.PrimaryColor, "button": in.CustomButtonText, "autoplay": in.AutoPlayVideo, "show_creator_info": in.ShowCreatorInfo, "domains": normalizeDomains(in.WhitelistedDomains)})
+	domains, err := normalizeDomains(in.WhitelistedDomains)
+	if err != nil {
+		return domain.EmbedSettings{}, err
+	}
+	requestHash := hashJSON(map[string]any{"op": "update_settings", "actor": actor.SubjectID, "entity_type": in.EntityType, "entity_id": in.EntityID, "allow": in.AllowEmbedding, "theme": in.DefaultTheme, "color": in.PrimaryColor, "button": in.CustomButtonText, "autoplay": in.AutoPlayVideo, "show_creator_info": in.ShowCreatorInfo, "domains": domains})
 	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.EmbedSettings{}, err
 	} else if ok {
@@ -168,7 +176,7 @@ func (s *Service) UpdateSettings(ctx context.Context, actor Actor, in UpdateEmbe
 		row.ShowCreatorInfo = *in.ShowCreatorInfo
 	}
 	if in.WhitelistedDomains != nil {
-		row.WhitelistedDomains = normalizeDomains(in.WhitelistedDomains)
+		row.WhitelistedDomains = domains
 	}
 	row.UpdatedAt = now
 	row.UpdatedBy = actor.SubjectID
@@ -268,11 +276,13 @@ func (s *Service) GenerateEmbedCode(ctx context.Context, entityType, entityID st
 	if err != nil {
 		return "", err
 	}
-	src := fmt.Sprintf("%s/embed/%s/%s?theme=%s&color=%s", strings.TrimRight(s.cfg.EmbedBaseURL, "/"), settings.EntityType, settings.EntityID, url.QueryEscape(settings.DefaultTheme), url.QueryEscape(settings.PrimaryColor))
-	return fmt.Sprintf(`<iframe width="100%%" height="600" src="%s" frameborder="0" allowfullscreen loading="lazy"></iframe>`, src), nil
+	return s.iframeSnippet(settings, embedTitle(settings.EntityType, settings.EntityID), "100%", defaultEmbedHeight), nil
 }
 
-func renderHTML(entityType, entityID, theme, color, buttonText string, autoPlay bool, language string) string {
+// renderHTML builds the embed document. Its script reports impressions and
+// clicks to the host page and keeps the iframe sized to the content, both
+// through embed_resize for our snippet and the AMP embed-size message.
+func renderHTML(entityType, entityID, theme, color, buttonText string, autoPlay bool, language string, meta RenderedEmbed) string {
 	bg := "#ffffff"
 	fg := "#111827"
 	border := "#e5e7eb"
@@ -284,11 +294,12 @@ func renderHTML(entityType, entityID, theme, color, buttonText string, autoPlay
 	if color == "" {
 		color = "#5B21B6"
 	}
-	title := strings.Title(entityType) + " " + entityID
+	title := meta.Title
 	cta := html.EscapeString(buttonText)
 	_ = autoPlay
 	_ = language
-	return fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="UTF-8"><meta name="viewport" content="width=device-width, initial-scale=1.0"><title>Embed - %s</title><style>body{margin:0;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;background:%s;color:%s}.embed-container{background:%s;border:1px solid %s;border-radius:8px;padding:24px;margin:8px}.cta-button{background-color:%s;color:white;padding:12px 24px;border-radius:6px;border:0;cursor:pointer}</style></head><body><div class="embed-container"><h2>%s</h2><p>Embeddable %s content preview.</p><button class="cta-button" onclick="handleCTA('cta_clicked')">%s</button></div><script>(function(){window.parent.postMessage({event_type:'embed_impression',entity_id:%q,entity_type:%q,timestamp:new Date().toISOString()},'*');window.handleCTA=function(action){window.parent.postMessage({event_type:'embed_click',entity_id:%q,entity_type:%q,action:action,timestamp:new Date().toISOString()},'*');};})();</script></body></html>`, html.EscapeString(title), bg, fg, bg, border, color, html.EscapeString(title), html.EscapeString(entityType), cta, entityID, entityType, entityID, entityType)
+	discovery := fmt.Sprintf(`<link rel="alternate" type="application/json+oembed" href="%s" title="%s"><link rel="alternate" type="text/xml+oembed" href="%s" title="%s">`, html.EscapeString(meta.OEmbedJSONURL), html.EscapeString(title), html.EscapeString(meta.OEmbedXMLURL), html.EscapeString(title))
+	return fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="UTF-8"><meta name="viewport" content="width=device-width, initial-scale=1.0"><title>Embed - %s</title>%s<style>body{margin:0;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;background:%s;color:%s}.embed-container{background:%s;border:1px solid %s;border-radius:8px;padding:24px;margin:8px}.cta-button{background-color:%s;color:white;padding:12px 24px;border-radius:6px;border:0;cursor:pointer}</style></head><body><div class="embed-container"><h2>%s</h2><p>Embeddable %s content preview.</p><button class="cta-button" onclick="handleCTA('cta_clicked')">%s</button></div><script>(function(){window.parent.postMessage({event_type:'embed_impression',entity_id:%q,entity_type:%q,timestamp:new Date().toISOString()},'*');window.handleCTA=function(action){window.parent.postMessage({event_type:'embed_click',entity_id:%q,entity_type:%q,action:action,timestamp:new Date().toISOString()},'*');};var lastHeight=0;function resize(){var h=Math.ceil(document.body.scrollHeight);if(h===lastHeight)return;lastHeight=h;window.parent.postMessage({event_type:'embed_resize',entity_id:%q,entity_type:%q,height:h},'*');window.parent.postMessage({sentinel:'amp',type:'embed-size',height:h},'*');}window.addEventListener('load',resize);if(window.ResizeObserver){new ResizeObserver(resize).observe(document.body);}})();</script></body></html>`, html.EscapeString(title), discovery, bg, fg, bg, border, color, html.EscapeString(title), html.EscapeString(entityType), cta, entityID, entityType, entityID, entityType, entityID, entityType)
 }
 
 func normalizeTheme(v string) string {
@@ -356,7 +367,7 @@ func normalizeLanguage(v string) string {
 	}
 	return v
 }
-func normalizeDomains(in []string) []string {
+func normalizeDomains(in []string) ([]string, error) {
 	out := make([]string, 0, len(in))
 	seen := map[string]struct{}{}
 	for _, d := range in {
@@ -366,6 +377,9 @@ func normalizeDomains(in []string) []string {
 		if d == "" {
 			continue
 		}
+		if !domain.IsValidHostSource(d) {
+			return nil, domain.ErrInvalidInput
+		}
 		if _, ok := seen[d]; ok {
 			continue
 		}
@@ -373,7 +387,7 @@ func normalizeDomains(in []string) []string {
 		out = append(out, d)
 	}
 	sort.Strings(out)
-	return out
+	return out, nil
 }
 func parseReferrerDomain(ref string) string {
 	ref = strings.TrimSpace(ref)
@@ -481,7 +495,7 @@ func (s *Service) publishOpsEvent(ctx context.Context, eventType string, data an
 		PartitionKeyPath: "envelope.source_service",
 		PartitionKey:     s.cfg.ServiceName,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          traceID,
+		TraceID:          observability.TraceParent(ctx, traceID),
 		SchemaVersion:    "v1",
 		Data:             payload,
 	}
@@ -9,6 +9,8 @@ import (
 type Config struct {
 	ServiceName          string
 	EmbedBaseURL         string
+	PublicBaseURL        string
+	ProviderName         string
 	CacheTTL             time.Duration
 	PerIPLimitPerHour    int
 	PerEmbedLimitPerHour int
@@ -90,8 +92,35 @@ type AnalyticsResult struct {
 	Trend             []TrendPoint
 }
 
+// RenderedEmbed is the embed document together with what the HTTP layer
+// needs to frame and advertise it.
 type RenderedEmbed struct {
-	HTML string
+	HTML           string
+	FrameAncestors []string
+	OEmbedJSONURL  string
+	OEmbedXMLURL   string
+	Title          string
+}
+
+type OEmbedInput struct {
+	URL       string
+	Format    string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// OEmbedResult is a "rich" oEmbed 1.0 response.
+type OEmbedResult struct {
+	Format       string
+	Type         string
+	Version      string
+	Title        string
+	ProviderName string
+	ProviderURL  string
+	HTML         string
+	Width        int
+	Height       int
+	CacheAge     int
 }
 
 type Service struct {
@@ -125,6 +154,12 @@ func NewService(deps Dependencies) *Service {
 	if cfg.EmbedBaseURL == "" {
 		cfg.EmbedBaseURL = "https://embed.platform.com"
 	}
+	if cfg.PublicBaseURL == "" {
+		cfg.PublicBaseURL = "https://platform.com"
+	}
+	if cfg.ProviderName == "" {
+		cfg.ProviderName = "Platform"
+	}
 	if cfg.CacheTTL <= 0 {
 		cfg.CacheTTL = 5 * time.Minute
 	}
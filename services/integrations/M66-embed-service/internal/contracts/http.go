@@ -1,5 +1,7 @@
 package contracts
 
+import "encoding/xml"
+
 type SuccessResponse struct {
 	Status string `json:"status"`
 	Data   any    `json:"data,omitempty"`
@@ -51,6 +53,7 @@ type EmbedSettingsResponse struct {
 	ShowCreatorInfo    bool            `json:"show_creator_info"`
 	WhitelistedDomains []string        `json:"whitelisted_domains"`
 	EmbedCode          string          `json:"embed_code"`
+	AMPEmbedCode       string          `json:"amp_embed_code"`
 	Metrics            SettingsMetrics `json:"metrics"`
 	UpdatedAt          string          `json:"updated_at,omitempty"`
 }
@@ -77,3 +80,18 @@ type EmbedAnalyticsResponse struct {
 	ByReferrer []ReferrerMetric `json:"by_referrer"`
 	Trend      []TrendPoint     `json:"trend"`
 }
+
+// OEmbedResponse is served bare, without the success envelope, as the
+// oEmbed spec requires. The XML form uses the same field names.
+type OEmbedResponse struct {
+	XMLName      xml.Name `json:"-" xml:"oembed"`
+	Type         string   `json:"type" xml:"type"`
+	Version      string   `json:"version" xml:"version"`
+	Title        string   `json:"title,omitempty" xml:"title,omitempty"`
+	ProviderName string   `json:"provider_name" xml:"provider_name"`
+	ProviderURL  string   `json:"provider_url" xml:"provider_url"`
+	HTML         string   `json:"html" xml:"html"`
+	Width        int      `json:"width" xml:"width"`
+	Height       int      `json:"height" xml:"height"`
+	CacheAge     int      `json:"cache_age,omitempty" xml:"cache_age,omitempty"`
+}
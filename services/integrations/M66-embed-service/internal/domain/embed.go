@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 const (
 	EntityTypeWhop     = "whop"
@@ -34,6 +37,45 @@ type EmbedSettings struct {
 	UpdatedBy          string    `json:"updated_by"`
 }
 
+// FrameAncestors lists the CSP frame-ancestors sources for the entity's
+// embed. Without whitelisted domains any site may frame it; otherwise only
+// the platform itself and the listed domains may. Entries that are not
+// plain host patterns are dropped so they cannot inject CSP directives, and
+// a whitelist with no usable entry refuses all framing rather than opening
+// it up.
+func (s EmbedSettings) FrameAncestors() []string {
+	if len(s.WhitelistedDomains) == 0 {
+		return []string{"*"}
+	}
+	out := []string{}
+	for _, d := range s.WhitelistedDomains {
+		if IsValidHostSource(d) {
+			out = append(out, d)
+		}
+	}
+	if len(out) == 0 {
+		return []string{"'none'"}
+	}
+	return append([]string{"'self'"}, out...)
+}
+
+// IsValidHostSource reports whether v is a lower-case CSP host source: a
+// host with an optional port, or a single leading "*." wildcard.
+func IsValidHostSource(v string) bool {
+	if v == "" || strings.Trim(v, "*.") == "" {
+		return false
+	}
+	for _, ch := range v {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9', ch == '.', ch == '-', ch == ':':
+		case ch == '*' && strings.HasPrefix(v, "*.") && strings.Count(v, "*") == 1:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 type EmbedCache struct {
 	CacheKey   string    `json:"cache_key"`
 	EntityType string    `json:"entity_type"`
@@ -14,4 +14,5 @@ var (
 	ErrUnsupportedEventType = errors.New("unsupported_event_type")
 	ErrRateLimited          = errors.New("rate_limited")
 	ErrEmbeddingDisabled    = errors.New("embedding_disabled")
+	ErrUnsupportedFormat    = errors.New("unsupported_format")
 )
@@ -2,10 +2,12 @@ package unit
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M66-embed-service/internal/domain"
 )
 
 func newService() *application.Service {
@@ -47,3 +49,80 @@ func TestRenderEmbedLogsImpressionAndAnalytics(t *testing.T) {
 		t.Fatalf("expected 1 impression, got %d", analytics.TotalImpressions)
 	}
 }
+
+func TestOEmbedResolvesPastedURLs(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	out, err := svc.OEmbed(ctx, application.OEmbedInput{URL: "https://www.platform.com/campaigns/ABC123", MaxWidth: 400})
+	if err != nil {
+		t.Fatalf("oembed: %v", err)
+	}
+	if out.Type != "rich" || out.Version != "1.0" || out.Format != application.OEmbedFormatJSON {
+		t.Fatalf("unexpected oembed envelope: %+v", out)
+	}
+	if out.Width != 400 || out.Height != 600 {
+		t.Fatalf("expected width capped by maxwidth, got %dx%d", out.Width, out.Height)
+	}
+	if !strings.Contains(out.HTML, `src="https://embed.platform.com/embed/campaign/ABC123?`) || !strings.Contains(out.HTML, `sandbox="allow-scripts`) || !strings.Contains(out.HTML, "embed_resize") {
+		t.Fatalf("expected sandboxed iframe with resize listener, got %s", out.HTML)
+	}
+	if _, err := svc.OEmbed(ctx, application.OEmbedInput{URL: "https://embed.platform.com/embed/clip/C1", Format: "xml", MaxHeight: 300}); err != nil {
+		t.Fatalf("xml oembed for embed url: %v", err)
+	}
+	if _, err := svc.OEmbed(ctx, application.OEmbedInput{URL: "https://platform.com/campaigns/ABC123", Format: "yaml"}); err != domain.ErrUnsupportedFormat {
+		t.Fatalf("expected unsupported format, got %v", err)
+	}
+	if _, err := svc.OEmbed(ctx, application.OEmbedInput{URL: "https://example.com/campaigns/ABC123"}); err != domain.ErrNotFound {
+		t.Fatalf("expected foreign host to be unknown, got %v", err)
+	}
+
+	allow := false
+	actor := application.Actor{SubjectID: "creator-1", Role: "creator", IdempotencyKey: "idem-private"}
+	if _, err := svc.UpdateSettings(ctx, actor, application.UpdateEmbedSettingsInput{EntityType: "campaign", EntityID: "PRIVATE", AllowEmbedding: &allow}); err != nil {
+		t.Fatalf("disable embedding: %v", err)
+	}
+	if _, err := svc.OEmbed(ctx, application.OEmbedInput{URL: "https://platform.com/campaign/PRIVATE"}); err != domain.ErrEmbeddingDisabled {
+		t.Fatalf("expected disabled embed to be refused, got %v", err)
+	}
+}
+
+func TestRenderEmbedFramingAndDiscovery(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	out, err := svc.RenderEmbed(ctx, application.RenderEmbedInput{EntityType: "campaign", EntityID: "ABC123"})
+	if err != nil {
+		t.Fatalf("render embed: %v", err)
+	}
+	if len(out.FrameAncestors) != 1 || out.FrameAncestors[0] != "*" {
+		t.Fatalf("expected any site to frame without an allow-list, got %v", out.FrameAncestors)
+	}
+	if !strings.Contains(out.HTML, `type="application/json+oembed"`) || !strings.Contains(out.HTML, "sentinel:'amp'") {
+		t.Fatalf("expected oembed discovery and resize messages in the document")
+	}
+
+	actor := application.Actor{SubjectID: "creator-1", Role: "creator", IdempotencyKey: "idem-domains"}
+	if _, err := svc.UpdateSettings(ctx, actor, application.UpdateEmbedSettingsInput{EntityType: "campaign", EntityID: "ABC123", WhitelistedDomains: []string{"https://MyBlog.com/", "evil.com; script-src *"}}); err != domain.ErrInvalidInput {
+		t.Fatalf("expected directive injection to be refused, got %v", err)
+	}
+	actor.IdempotencyKey = "idem-domains-2"
+	if _, err := svc.UpdateSettings(ctx, actor, application.UpdateEmbedSettingsInput{EntityType: "campaign", EntityID: "ABC123", WhitelistedDomains: []string{"https://MyBlog.com/", "*.partner.io"}}); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	out, err = svc.RenderEmbed(ctx, application.RenderEmbedInput{EntityType: "campaign", EntityID: "ABC123"})
+	if err != nil {
+		t.Fatalf("render embed: %v", err)
+	}
+	if got := strings.Join(out.FrameAncestors, " "); got != "'self' *.partner.io myblog.com" {
+		t.Fatalf("unexpected frame ancestors %q", got)
+	}
+
+	legacy := domain.EmbedSettings{WhitelistedDomains: []string{"evil.com; script-src *"}}
+	if got := strings.Join(legacy.FrameAncestors(), " "); got != "'none'" {
+		t.Fatalf("expected an unusable allow-list to refuse framing, got %q", got)
+	}
+
+	amp, err := svc.GenerateAMPEmbedCode(ctx, "campaign", "ABC123")
+	if err != nil || !strings.HasPrefix(amp, "<amp-iframe") || !strings.Contains(amp, "resizable") {
+		t.Fatalf("unexpected amp embed code %q (%v)", amp, err)
+	}
+}
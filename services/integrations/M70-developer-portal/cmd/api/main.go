@@ -1,19 +1,34 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	platformgrpc "github.com/viralforge/mesh/platform/grpc"
+	"github.com/viralforge/mesh/platform/observability"
+	grpcadapter "github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/application"
 )
 
 func main() {
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv("M70-Developer-Portal", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
+
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
+		Config: application.Config{
+			RotationGracePeriod: time.Duration(envInt("API_KEY_ROTATION_GRACE_HOURS", 24)) * time.Hour,
+		},
 		Developers:  repos.Developers,
 		Sessions:    repos.Sessions,
 		APIKeys:     repos.APIKeys,
@@ -21,9 +36,24 @@ func main() {
 		Webhooks:    repos.Webhooks,
 		Deliveries:  repos.Deliveries,
 		Usage:       repos.Usage,
+		KeyUsage:    repos.KeyUsage,
 		Audit:       repos.Audit,
 		Idempotency: repos.Idempotency,
 	})
+
+	// The gateway verifies API keys over gRPC against the same in-memory
+	// repositories the HTTP API writes to, so both run in this process.
+	grpcServer, err := platformgrpc.NewServer(platformgrpc.ServerConfigFromEnv("M70-Developer-Portal", envInt("GRPC_PORT", 9090)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcadapter.Register(grpcServer.GRPC(), grpcadapter.NewDeveloperInternalServer(svc))
+	go func() {
+		if err := grpcServer.ListenAndServe(context.Background()); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	router := httpadapter.NewRouter(httpadapter.NewHandler(svc))
 	addr := strings.TrimSpace(os.Getenv("PORT"))
 	if addr == "" {
@@ -33,7 +63,14 @@ func main() {
 		addr = ":" + addr
 	}
 	log.Printf("M70-Developer-Portal listening on %s", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := http.ListenAndServe(addr, observability.HTTPMiddleware("M70-Developer-Portal")(router)); err != nil {
 		log.Fatal(err)
 	}
 }
+
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name))); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
@@ -1,3 +1,21 @@
 module github.com/viralforge/mesh/services/integrations/M70-developer-portal
 
 go 1.23
+
+require (
+	github.com/viralforge/mesh/contracts v0.0.0
+	github.com/viralforge/mesh/platform v0.0.0
+	google.golang.org/grpc v1.75.1
+)
+
+require (
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b // indirect
+	google.golang.org/protobuf v1.36.8 // indirect
+)
+
+replace github.com/viralforge/mesh/platform => ../../../platform
+
+replace github.com/viralforge/mesh/contracts => ../../../contracts
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	developerv1 "github.com/viralforge/mesh/contracts/gen/go/developer/v1"
+	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeveloperInternalServer lets the gateway authenticate calls made with
+// developer API keys.
+type DeveloperInternalServer struct {
+	developerv1.UnimplementedDeveloperInternalServiceServer
+	service *application.Service
+}
+
+// NewDeveloperInternalServer constructs the gRPC developer portal adapter.
+func NewDeveloperInternalServer(service *application.Service) *DeveloperInternalServer {
+	return &DeveloperInternalServer{service: service}
+}
+
+// Register binds the developer internal service to a gRPC registrar.
+func Register(server grpc.ServiceRegistrar, svc *DeveloperInternalServer) {
+	developerv1.RegisterDeveloperInternalServiceServer(server, svc)
+}
+
+func (s *DeveloperInternalServer) VerifyAPIKey(ctx context.Context, req *developerv1.VerifyAPIKeyRequest) (*developerv1.VerifyAPIKeyResponse, error) {
+	if req.GetApiKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing api_key")
+	}
+	out, err := s.service.VerifyAPIKey(ctx, application.VerifyAPIKeyInput{
+		Key:      req.GetApiKey(),
+		Scope:    req.GetScope(),
+		ClientIP: req.GetClientIp(),
+		Origin:   req.GetOrigin(),
+	})
+	if err != nil {
+		return nil, verifyError(err)
+	}
+	resp := &developerv1.VerifyAPIKeyResponse{
+		Valid:       true,
+		KeyId:       out.Key.KeyID,
+		DeveloperId: out.Developer.DeveloperID,
+		Tier:        out.Developer.Tier,
+		Scopes:      out.Key.Scopes,
+		DailyQuota:  out.DailyQuota,
+		DailyUsage:  out.DailyUsage,
+	}
+	if out.Key.ExpiresAt != nil {
+		resp.ExpiresAt = out.Key.ExpiresAt.Unix()
+	}
+	return resp, nil
+}
+
+func verifyError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAPIKey), errors.Is(err, domain.ErrAPIKeyExpired):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, "daily quota exceeded")
+	case errors.Is(err, domain.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "verify api key: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,10 +45,23 @@ func (h *Handler) createAPIKey(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
 		return
 	}
-	row, err := h.service.CreateAPIKey(r.Context(), actorFromContext(r.Context()), application.CreateAPIKeyInput{
-		DeveloperID: req.DeveloperID,
-		Label:       req.Label,
-	})
+	in := application.CreateAPIKeyInput{
+		DeveloperID:    req.DeveloperID,
+		Label:          req.Label,
+		Scopes:         req.Scopes,
+		AllowedIPs:     req.AllowedIPs,
+		AllowedOrigins: req.AllowedOrigins,
+	}
+	if strings.TrimSpace(req.ExpiresAt) != "" {
+		expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(req.ExpiresAt))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", "expires_at must be RFC3339", requestIDFromContext(r.Context()))
+			return
+		}
+		expiresAt = expiresAt.UTC()
+		in.ExpiresAt = &expiresAt
+	}
+	row, err := h.service.CreateAPIKey(r.Context(), actorFromContext(r.Context()), in)
 	if err != nil {
 		status, code := mapDomainError(err)
 		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
@@ -82,6 +96,25 @@ func (h *Handler) revokeAPIKey(w http.ResponseWriter, r *http.Request) {
 	writeSuccess(w, http.StatusOK, "api key revoked", toAPIKeyResponse(row))
 }
 
+func (h *Handler) getUsageDashboard(w http.ResponseWriter, r *http.Request) {
+	days := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("days")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", "days must be a number", requestIDFromContext(r.Context()))
+			return
+		}
+		days = n
+	}
+	out, err := h.service.GetUsageDashboard(r.Context(), actorFromContext(r.Context()), r.URL.Query().Get("developer_id"), days)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "usage dashboard", toUsageDashboardResponse(out))
+}
+
 func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
 	var req contracts.CreateWebhookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -135,12 +168,26 @@ func toSessionResponse(row domain.DeveloperSession) contracts.SessionResponse {
 
 func toAPIKeyResponse(row domain.APIKey) contracts.APIKeyResponse {
 	out := contracts.APIKeyResponse{
-		KeyID:       row.KeyID,
-		DeveloperID: row.DeveloperID,
-		Label:       row.Label,
-		MaskedKey:   row.MaskedKey,
-		Status:      row.Status,
-		CreatedAt:   row.CreatedAt.UTC().Format(time.RFC3339),
+		KeyID:          row.KeyID,
+		DeveloperID:    row.DeveloperID,
+		Label:          row.Label,
+		Prefix:         row.Prefix,
+		MaskedKey:      row.MaskedKey,
+		Key:            row.Secret,
+		Scopes:         row.Scopes,
+		AllowedIPs:     row.AllowedIPs,
+		AllowedOrigins: row.AllowedOrigins,
+		Status:         row.Status,
+		CreatedAt:      row.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if row.ExpiresAt != nil {
+		out.ExpiresAt = row.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if row.GraceUntil != nil {
+		out.GraceUntil = row.GraceUntil.UTC().Format(time.RFC3339)
+	}
+	if row.LastUsedAt != nil {
+		out.LastUsedAt = row.LastUsedAt.UTC().Format(time.RFC3339)
 	}
 	if row.RevokedAt != nil {
 		out.RevokedAt = row.RevokedAt.UTC().Format(time.RFC3339)
@@ -148,6 +195,25 @@ func toAPIKeyResponse(row domain.APIKey) contracts.APIKeyResponse {
 	return out
 }
 
+func toUsageDashboardResponse(row application.UsageDashboard) contracts.UsageDashboardResponse {
+	out := contracts.UsageDashboardResponse{
+		DeveloperID:    row.DeveloperID,
+		Tier:           row.Tier,
+		DailyQuota:     row.DailyQuota,
+		UsedToday:      row.UsedToday,
+		RemainingToday: row.RemainingToday,
+		Days:           make([]contracts.DailyUsageResponse, 0, len(row.Days)),
+		Keys:           make([]contracts.KeyUsageResponse, 0, len(row.Keys)),
+	}
+	for _, day := range row.Days {
+		out.Days = append(out.Days, contracts.DailyUsageResponse{Day: day.Day, Requests: day.Requests, ByKey: day.ByKey})
+	}
+	for _, key := range row.Keys {
+		out.Keys = append(out.Keys, contracts.KeyUsageResponse{Key: toAPIKeyResponse(key.Key), Requests: key.Requests})
+	}
+	return out
+}
+
 func toWebhookResponse(row domain.Webhook) contracts.WebhookResponse {
 	return contracts.WebhookResponse{
 		WebhookID:   row.WebhookID,
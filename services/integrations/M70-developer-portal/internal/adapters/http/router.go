@@ -40,6 +40,13 @@ func NewRouter(handler *Handler) http.Handler {
 		}
 		handler.revokeAPIKey(w, r)
 	})))
+	mux.Handle("/api/v1/developers/usage", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.getUsageDashboard(w, r)
+	})))
 	createWebhook := authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,6 +18,7 @@ type Repositories struct {
 	Webhooks    *WebhookRepository
 	Deliveries  *WebhookDeliveryRepository
 	Usage       *UsageRepository
+	KeyUsage    *APIKeyUsageRepository
 	Audit       *AuditRepository
 	Idempotency *IdempotencyRepository
 }
@@ -25,11 +27,12 @@ func NewRepositories() *Repositories {
 	return &Repositories{
 		Developers:  &DeveloperRepository{rowsByID: map[string]domain.Developer{}},
 		Sessions:    &SessionRepository{rowsByID: map[string]domain.DeveloperSession{}},
-		APIKeys:     &APIKeyRepository{rowsByID: map[string]domain.APIKey{}},
+		APIKeys:     &APIKeyRepository{rowsByID: map[string]domain.APIKey{}, idByPrefix: map[string]string{}},
 		Rotations:   &APIKeyRotationRepository{rowsByID: map[string]domain.APIKeyRotation{}},
 		Webhooks:    &WebhookRepository{rowsByID: map[string]domain.Webhook{}},
 		Deliveries:  &WebhookDeliveryRepository{rowsByID: map[string]domain.WebhookDelivery{}},
 		Usage:       &UsageRepository{rowsByDeveloperID: map[string]domain.DeveloperUsage{}},
+		KeyUsage:    &APIKeyUsageRepository{rows: map[apiKeyUsageKey]domain.APIKeyUsage{}, totals: map[string]int64{}},
 		Audit:       &AuditRepository{rows: make([]domain.AuditLog, 0, 128)},
 		Idempotency: &IdempotencyRepository{rows: map[string]ports.IdempotencyRecord{}},
 	}
@@ -76,8 +79,9 @@ func (r *SessionRepository) Create(_ context.Context, row domain.DeveloperSessio
 }
 
 type APIKeyRepository struct {
-	mu       sync.Mutex
-	rowsByID map[string]domain.APIKey
+	mu         sync.Mutex
+	rowsByID   map[string]domain.APIKey
+	idByPrefix map[string]string
 }
 
 func (r *APIKeyRepository) Create(_ context.Context, row domain.APIKey) error {
@@ -86,7 +90,48 @@ func (r *APIKeyRepository) Create(_ context.Context, row domain.APIKey) error {
 	if _, ok := r.rowsByID[row.KeyID]; ok {
 		return domain.ErrConflict
 	}
+	if _, ok := r.idByPrefix[row.Prefix]; ok && row.Prefix != "" {
+		return domain.ErrConflict
+	}
 	r.rowsByID[row.KeyID] = row
+	if row.Prefix != "" {
+		r.idByPrefix[row.Prefix] = row.KeyID
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) GetByPrefix(_ context.Context, prefix string) (domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rowsByID[r.idByPrefix[prefix]]
+	if !ok {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	return row, nil
+}
+
+func (r *APIKeyRepository) ListByDeveloperID(_ context.Context, developerID string) ([]domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.APIKey, 0)
+	for _, row := range r.rowsByID {
+		if row.DeveloperID == developerID {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(_ context.Context, keyID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rowsByID[keyID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	row.LastUsedAt = &at
+	r.rowsByID[keyID] = row
 	return nil
 }
 
@@ -187,6 +232,51 @@ func (r *UsageRepository) GetByDeveloperID(_ context.Context, developerID string
 	return row, nil
 }
 
+type apiKeyUsageKey struct {
+	developerID, keyID, day string
+}
+
+type APIKeyUsageRepository struct {
+	mu     sync.Mutex
+	rows   map[apiKeyUsageKey]domain.APIKeyUsage
+	totals map[string]int64
+}
+
+func (r *APIKeyUsageRepository) Consume(_ context.Context, developerID, keyID, day string, limit int64, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	totalKey := developerID + "|" + day
+	if limit > 0 && r.totals[totalKey] >= limit {
+		return r.totals[totalKey], domain.ErrQuotaExceeded
+	}
+	k := apiKeyUsageKey{developerID: developerID, keyID: keyID, day: day}
+	row := r.rows[k]
+	row.DeveloperID, row.KeyID, row.Day = developerID, keyID, day
+	row.Requests++
+	row.UpdatedAt = at
+	r.rows[k] = row
+	r.totals[totalKey]++
+	return r.totals[totalKey], nil
+}
+
+func (r *APIKeyUsageRepository) ListByDeveloperID(_ context.Context, developerID, fromDay, toDay string) ([]domain.APIKeyUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.APIKeyUsage, 0)
+	for k, row := range r.rows {
+		if k.developerID == developerID && k.day >= fromDay && k.day <= toDay {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Day != out[j].Day {
+			return out[i].Day < out[j].Day
+		}
+		return out[i].KeyID < out[j].KeyID
+	})
+	return out, nil
+}
+
 type AuditRepository struct {
 	mu   sync.Mutex
 	rows []domain.AuditLog
@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/domain"
+)
+
+const maxUsageDashboardDays = 90
+
+// VerifyAPIKey authenticates a call presented to the gateway and meters it
+// against the developer's daily quota. Rejected calls are not counted.
+func (s *Service) VerifyAPIKey(ctx context.Context, in VerifyAPIKeyInput) (APIKeyVerification, error) {
+	prefix, secret, ok := domain.ParseAPIKey(in.Key)
+	if !ok {
+		return APIKeyVerification{}, domain.ErrInvalidAPIKey
+	}
+	key, err := s.apiKeys.GetByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrNotFound) {
+		return APIKeyVerification{}, domain.ErrInvalidAPIKey
+	} else if err != nil {
+		return APIKeyVerification{}, err
+	}
+	if !key.Matches(secret) {
+		return APIKeyVerification{}, domain.ErrInvalidAPIKey
+	}
+	now := s.nowFn()
+	if err := key.UsableAt(now); err != nil {
+		return APIKeyVerification{}, err
+	}
+	if !key.AllowsScope(in.Scope) {
+		return APIKeyVerification{}, fmt.Errorf("%w: scope %q is not granted to this key", domain.ErrForbidden, strings.TrimSpace(in.Scope))
+	}
+	if !key.AllowsIP(in.ClientIP) {
+		return APIKeyVerification{}, fmt.Errorf("%w: client ip is not allowed for this key", domain.ErrForbidden)
+	}
+	if !key.AllowsOrigin(in.Origin) {
+		return APIKeyVerification{}, fmt.Errorf("%w: origin is not allowed for this key", domain.ErrForbidden)
+	}
+	developer, err := s.developers.GetByID(ctx, key.DeveloperID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return APIKeyVerification{}, domain.ErrInvalidAPIKey
+	} else if err != nil {
+		return APIKeyVerification{}, err
+	}
+	if developer.Status != domain.DeveloperStatusActive {
+		return APIKeyVerification{}, fmt.Errorf("%w: developer account is %s", domain.ErrInvalidAPIKey, developer.Status)
+	}
+
+	out := APIKeyVerification{Key: key, Developer: developer, DailyQuota: domain.DailyQuotaFor(developer.Tier)}
+	if s.keyUsage != nil {
+		used, err := s.keyUsage.Consume(ctx, developer.DeveloperID, key.KeyID, domain.UsageDay(now), out.DailyQuota, now)
+		if err != nil {
+			return APIKeyVerification{}, err
+		}
+		out.DailyUsage = used
+		s.recordDailyTotal(ctx, developer.DeveloperID, used, out.DailyQuota, now)
+	}
+	if err := s.apiKeys.TouchLastUsed(ctx, key.KeyID, now); err != nil {
+		return APIKeyVerification{}, err
+	}
+	out.Key.LastUsedAt = &now
+	return out, nil
+}
+
+// GetUsageDashboard returns the developer's daily call counts per key for
+// the last days UTC days, with today's quota headroom.
+func (s *Service) GetUsageDashboard(ctx context.Context, actor Actor, developerID string, days int) (UsageDashboard, error) {
+	developerID, err := s.resolveDeveloperID(actor, developerID)
+	if err != nil {
+		return UsageDashboard{}, err
+	}
+	if days == 0 {
+		days = 7
+	}
+	if days < 1 || days > maxUsageDashboardDays {
+		return UsageDashboard{}, fmt.Errorf("%w: days must be between 1 and %d", domain.ErrInvalidInput, maxUsageDashboardDays)
+	}
+	developer, err := s.developers.GetByID(ctx, developerID)
+	if err != nil {
+		return UsageDashboard{}, err
+	}
+	keys, err := s.apiKeys.ListByDeveloperID(ctx, developerID)
+	if err != nil {
+		return UsageDashboard{}, err
+	}
+
+	now := s.nowFn()
+	today := domain.UsageDay(now)
+	out := UsageDashboard{
+		DeveloperID: developerID,
+		Tier:        developer.Tier,
+		DailyQuota:  domain.DailyQuotaFor(developer.Tier),
+		Days:        make([]DailyUsage, 0, days),
+		Keys:        make([]KeyUsageSummary, 0, len(keys)),
+	}
+	index := make(map[string]int, days)
+	for i := days - 1; i >= 0; i-- {
+		day := domain.UsageDay(now.AddDate(0, 0, -i))
+		index[day] = len(out.Days)
+		out.Days = append(out.Days, DailyUsage{Day: day, ByKey: map[string]int64{}})
+	}
+	perKey := map[string]int64{}
+	if s.keyUsage != nil {
+		rows, err := s.keyUsage.ListByDeveloperID(ctx, developerID, out.Days[0].Day, today)
+		if err != nil {
+			return UsageDashboard{}, err
+		}
+		for _, row := range rows {
+			i, ok := index[row.Day]
+			if !ok {
+				continue
+			}
+			out.Days[i].Requests += row.Requests
+			out.Days[i].ByKey[row.KeyID] += row.Requests
+			perKey[row.KeyID] += row.Requests
+		}
+	}
+	for _, key := range keys {
+		out.Keys = append(out.Keys, KeyUsageSummary{Key: key, Requests: perKey[key.KeyID]})
+	}
+	out.UsedToday = out.Days[len(out.Days)-1].Requests
+	out.RemainingToday = max(out.DailyQuota-out.UsedToday, 0)
+	return out, nil
+}
+
+// recordDailyTotal keeps the developer usage row in step with the rollup so
+// it always describes the current UTC day.
+func (s *Service) recordDailyTotal(ctx context.Context, developerID string, used, quota int64, now time.Time) {
+	if s.usage == nil {
+		return
+	}
+	row, err := s.usage.GetByDeveloperID(ctx, developerID)
+	if err != nil {
+		row = domain.DeveloperUsage{UsageID: nextID("usage"), DeveloperID: developerID}
+	}
+	row.CurrentUsage = int(used)
+	row.RateLimit = int(quota)
+	row.PeriodStart = now.Truncate(24 * time.Hour)
+	row.PeriodEnd = row.PeriodStart.Add(24 * time.Hour)
+	_ = s.usage.CreateOrUpdate(ctx, row)
+}
+
+// newAPIKey issues a key with a random lookup prefix and secret. Only the
+// returned value carries the plaintext in Secret.
+func newAPIKey(developerID, label string, now time.Time) (domain.APIKey, error) {
+	prefix, err := randomHex(6)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	salt, err := randomHex(16)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return domain.APIKey{}, err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(raw)
+	return domain.APIKey{
+		KeyID:       nextID("key"),
+		DeveloperID: developerID,
+		Label:       label,
+		Prefix:      prefix,
+		MaskedKey:   domain.MaskAPIKey(prefix),
+		Salt:        salt,
+		KeyHash:     domain.HashAPIKeySecret(salt, secret),
+		Status:      domain.APIKeyStatusActive,
+		CreatedAt:   now,
+		Secret:      domain.FormatAPIKey(prefix, secret),
+	}, nil
+}
+
+func withoutSecret(row domain.APIKey) domain.APIKey {
+	row.Secret = ""
+	return row
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -52,7 +52,7 @@ func (s *Service) RegisterDeveloper(ctx context.Context, actor Actor, in Registe
 		DeveloperID: nextID("dev"),
 		Email:       in.Email,
 		AppName:     in.AppName,
-		Tier:        "free",
+		Tier:        domain.TierFree,
 		Status:      domain.DeveloperStatusActive,
 		CreatedAt:   now,
 	}
@@ -75,9 +75,9 @@ func (s *Service) RegisterDeveloper(ctx context.Context, actor Actor, in Registe
 			UsageID:      nextID("usage"),
 			DeveloperID:  developer.DeveloperID,
 			CurrentUsage: 0,
-			RateLimit:    100,
-			PeriodStart:  now.Truncate(time.Hour),
-			PeriodEnd:    now.Truncate(time.Hour).Add(time.Hour),
+			RateLimit:    int(domain.DailyQuotaFor(developer.Tier)),
+			PeriodStart:  now.Truncate(24 * time.Hour),
+			PeriodEnd:    now.Truncate(24 * time.Hour).Add(24 * time.Hour),
 		})
 	}
 	s.appendAudit(ctx, developer.DeveloperID, "developer.account_created", developer.DeveloperID, nil)
@@ -103,7 +103,30 @@ func (s *Service) CreateAPIKey(ctx context.Context, actor Actor, in CreateAPIKey
 	if in.Label == "" {
 		return domain.APIKey{}, domain.ErrInvalidInput
 	}
-	requestHash := hashJSON(map[string]string{"op": "create_api_key", "developer_id": developerID, "label": in.Label})
+	scopes, err := domain.NormalizeScopes(in.Scopes)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	allowedIPs, err := domain.NormalizeAllowedIPs(in.AllowedIPs)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	allowedOrigins, err := domain.NormalizeAllowedOrigins(in.AllowedOrigins)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(s.nowFn()) {
+		return domain.APIKey{}, fmt.Errorf("%w: expires_at must be in the future", domain.ErrInvalidInput)
+	}
+	requestHash := hashJSON(map[string]any{
+		"op":              "create_api_key",
+		"developer_id":    developerID,
+		"label":           in.Label,
+		"scopes":          scopes,
+		"allowed_ips":     allowedIPs,
+		"allowed_origins": allowedOrigins,
+		"expires_at":      in.ExpiresAt,
+	})
 	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.APIKey{}, err
 	} else if ok {
@@ -118,15 +141,15 @@ func (s *Service) CreateAPIKey(ctx context.Context, actor Actor, in CreateAPIKey
 	if _, err := s.developers.GetByID(ctx, developerID); err != nil {
 		return domain.APIKey{}, err
 	}
-	row := domain.APIKey{
-		KeyID:       nextID("key"),
-		DeveloperID: developerID,
-		Label:       in.Label,
-		MaskedKey:   "vk_live_" + developerID + "_****",
-		Status:      domain.APIKeyStatusActive,
-		CreatedAt:   s.nowFn(),
+	row, err := newAPIKey(developerID, in.Label, s.nowFn())
+	if err != nil {
+		return domain.APIKey{}, err
 	}
-	if err := s.apiKeys.Create(ctx, row); err != nil {
+	row.Scopes = scopes
+	row.AllowedIPs = allowedIPs
+	row.AllowedOrigins = allowedOrigins
+	row.ExpiresAt = in.ExpiresAt
+	if err := s.apiKeys.Create(ctx, withoutSecret(row)); err != nil {
 		return domain.APIKey{}, err
 	}
 	s.appendAudit(ctx, developerID, "developer.key_generated", row.KeyID, nil)
@@ -168,19 +191,30 @@ func (s *Service) RotateAPIKey(ctx context.Context, actor Actor, keyID string) (
 	if !canAccessDeveloper(actor, oldKey.DeveloperID) {
 		return domain.APIKeyRotation{}, domain.APIKey{}, domain.APIKey{}, authorizeError(actor)
 	}
-	oldKey.Status = domain.APIKeyStatusDeprecated
-	if err := s.apiKeys.Update(ctx, oldKey); err != nil {
+	if oldKey.Status != domain.APIKeyStatusActive {
+		return domain.APIKeyRotation{}, domain.APIKey{}, domain.APIKey{}, fmt.Errorf("%w: only active keys can be rotated", domain.ErrConflict)
+	}
+	now := s.nowFn()
+	newKey, err := newAPIKey(oldKey.DeveloperID, oldKey.Label+" (rotated)", now)
+	if err != nil {
 		return domain.APIKeyRotation{}, domain.APIKey{}, domain.APIKey{}, err
 	}
-	newKey := domain.APIKey{
-		KeyID:       nextID("key"),
-		DeveloperID: oldKey.DeveloperID,
-		Label:       oldKey.Label + " (rotated)",
-		MaskedKey:   "vk_live_" + oldKey.DeveloperID + "_****",
-		Status:      domain.APIKeyStatusActive,
-		CreatedAt:   s.nowFn(),
+	newKey.Scopes = oldKey.Scopes
+	newKey.AllowedIPs = oldKey.AllowedIPs
+	newKey.AllowedOrigins = oldKey.AllowedOrigins
+	if oldKey.ExpiresAt != nil {
+		expiresAt := now.Add(oldKey.ExpiresAt.Sub(oldKey.CreatedAt))
+		newKey.ExpiresAt = &expiresAt
 	}
-	if err := s.apiKeys.Create(ctx, newKey); err != nil {
+	if err := s.apiKeys.Create(ctx, withoutSecret(newKey)); err != nil {
+		return domain.APIKeyRotation{}, domain.APIKey{}, domain.APIKey{}, err
+	}
+	// The old key keeps authenticating until the grace period ends so
+	// clients can roll the new one out without downtime.
+	graceUntil := now.Add(s.cfg.RotationGracePeriod)
+	oldKey.Status = domain.APIKeyStatusDeprecated
+	oldKey.GraceUntil = &graceUntil
+	if err := s.apiKeys.Update(ctx, oldKey); err != nil {
 		return domain.APIKeyRotation{}, domain.APIKey{}, domain.APIKey{}, err
 	}
 	rotation := domain.APIKeyRotation{
@@ -188,12 +222,12 @@ func (s *Service) RotateAPIKey(ctx context.Context, actor Actor, keyID string) (
 		OldKeyID:    oldKey.KeyID,
 		NewKeyID:    newKey.KeyID,
 		DeveloperID: oldKey.DeveloperID,
-		CreatedAt:   s.nowFn(),
+		CreatedAt:   now,
 	}
 	if err := s.rotations.Create(ctx, rotation); err != nil {
 		return domain.APIKeyRotation{}, domain.APIKey{}, domain.APIKey{}, err
 	}
-	s.appendAudit(ctx, oldKey.DeveloperID, "developer.key_rotated", rotation.RotationID, nil)
+	s.appendAudit(ctx, oldKey.DeveloperID, "developer.key_rotated", rotation.RotationID, map[string]string{"grace_until": graceUntil.Format(time.RFC3339)})
 	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, struct {
 		Rotation domain.APIKeyRotation `json:"rotation"`
 		OldKey   domain.APIKey         `json:"old_key"`
@@ -3,12 +3,16 @@ package application
 import (
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/ports"
 )
 
 type Config struct {
 	ServiceName    string
 	IdempotencyTTL time.Duration
+	// RotationGracePeriod is how long a rotated key keeps working next to
+	// its replacement.
+	RotationGracePeriod time.Duration
 }
 
 type Actor struct {
@@ -24,8 +28,50 @@ type RegisterDeveloperInput struct {
 }
 
 type CreateAPIKeyInput struct {
-	DeveloperID string
-	Label       string
+	DeveloperID    string
+	Label          string
+	Scopes         []string
+	AllowedIPs     []string
+	AllowedOrigins []string
+	ExpiresAt      *time.Time
+}
+
+// VerifyAPIKeyInput is a call presented to the gateway. Scope, ClientIP and
+// Origin are checked against the key's restrictions.
+type VerifyAPIKeyInput struct {
+	Key      string
+	Scope    string
+	ClientIP string
+	Origin   string
+}
+
+type APIKeyVerification struct {
+	Key        domain.APIKey
+	Developer  domain.Developer
+	DailyQuota int64
+	DailyUsage int64
+}
+
+type DailyUsage struct {
+	Day      string
+	Requests int64
+	ByKey    map[string]int64
+}
+
+type KeyUsageSummary struct {
+	Key      domain.APIKey
+	Requests int64
+}
+
+// UsageDashboard covers the last Days UTC days up to and including today.
+type UsageDashboard struct {
+	DeveloperID    string
+	Tier           string
+	DailyQuota     int64
+	UsedToday      int64
+	RemainingToday int64
+	Days           []DailyUsage
+	Keys           []KeyUsageSummary
 }
 
 type CreateWebhookInput struct {
@@ -43,6 +89,7 @@ type Service struct {
 	webhooks    ports.WebhookRepository
 	deliveries  ports.WebhookDeliveryRepository
 	usage       ports.UsageRepository
+	keyUsage    ports.APIKeyUsageRepository
 	audit       ports.AuditRepository
 	idempotency ports.IdempotencyRepository
 	nowFn       func() time.Time
@@ -57,6 +104,7 @@ type Dependencies struct {
 	Webhooks    ports.WebhookRepository
 	Deliveries  ports.WebhookDeliveryRepository
 	Usage       ports.UsageRepository
+	KeyUsage    ports.APIKeyUsageRepository
 	Audit       ports.AuditRepository
 	Idempotency ports.IdempotencyRepository
 }
@@ -69,6 +117,9 @@ func NewService(deps Dependencies) *Service {
 	if cfg.IdempotencyTTL <= 0 {
 		cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
+	if cfg.RotationGracePeriod <= 0 {
+		cfg.RotationGracePeriod = 24 * time.Hour
+	}
 	return &Service{
 		cfg:         cfg,
 		developers:  deps.Developers,
@@ -78,6 +129,7 @@ func NewService(deps Dependencies) *Service {
 		webhooks:    deps.Webhooks,
 		deliveries:  deps.Deliveries,
 		usage:       deps.Usage,
+		keyUsage:    deps.KeyUsage,
 		audit:       deps.Audit,
 		idempotency: deps.Idempotency,
 		nowFn:       func() time.Time { return time.Now().UTC() },
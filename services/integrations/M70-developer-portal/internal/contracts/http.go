@@ -26,8 +26,12 @@ type RegisterDeveloperRequest struct {
 }
 
 type CreateAPIKeyRequest struct {
-	DeveloperID string `json:"developer_id,omitempty"`
-	Label       string `json:"label"`
+	DeveloperID    string   `json:"developer_id,omitempty"`
+	Label          string   `json:"label"`
+	Scopes         []string `json:"scopes,omitempty"`
+	AllowedIPs     []string `json:"allowed_ips,omitempty"`
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+	ExpiresAt      string   `json:"expires_at,omitempty"`
 }
 
 type CreateWebhookRequest struct {
@@ -63,10 +67,19 @@ type APIKeyResponse struct {
 	KeyID       string `json:"key_id"`
 	DeveloperID string `json:"developer_id"`
 	Label       string `json:"label"`
+	Prefix      string `json:"prefix"`
 	MaskedKey   string `json:"masked_key"`
-	Status      string `json:"status"`
-	CreatedAt   string `json:"created_at"`
-	RevokedAt   string `json:"revoked_at,omitempty"`
+	// Key is the plaintext key, present only in the response that issues it.
+	Key            string   `json:"key,omitempty"`
+	Scopes         []string `json:"scopes"`
+	AllowedIPs     []string `json:"allowed_ips,omitempty"`
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+	Status         string   `json:"status"`
+	CreatedAt      string   `json:"created_at"`
+	ExpiresAt      string   `json:"expires_at,omitempty"`
+	GraceUntil     string   `json:"grace_until,omitempty"`
+	LastUsedAt     string   `json:"last_used_at,omitempty"`
+	RevokedAt      string   `json:"revoked_at,omitempty"`
 }
 
 type APIKeyRotationResponse struct {
@@ -93,3 +106,24 @@ type WebhookDeliveryResponse struct {
 	TestEvent  bool   `json:"test_event"`
 	CreatedAt  string `json:"created_at"`
 }
+
+type DailyUsageResponse struct {
+	Day      string           `json:"day"`
+	Requests int64            `json:"requests"`
+	ByKey    map[string]int64 `json:"by_key"`
+}
+
+type KeyUsageResponse struct {
+	Key      APIKeyResponse `json:"key"`
+	Requests int64          `json:"requests"`
+}
+
+type UsageDashboardResponse struct {
+	DeveloperID    string               `json:"developer_id"`
+	Tier           string               `json:"tier"`
+	DailyQuota     int64                `json:"daily_quota"`
+	UsedToday      int64                `json:"used_today"`
+	RemainingToday int64                `json:"remaining_today"`
+	Days           []DailyUsageResponse `json:"days"`
+	Keys           []KeyUsageResponse   `json:"keys"`
+}
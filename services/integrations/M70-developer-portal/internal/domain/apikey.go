@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every issued key. The full key reads
+// vk_live_<prefix>_<secret>; the prefix is stored in clear for lookup and
+// only a salted hash of the secret is kept.
+const APIKeyPrefix = "vk_live_"
+
+// ScopeAll grants every scope. Keys created without scopes get it so
+// existing integrations keep working.
+const ScopeAll = "*"
+
+const (
+	TierFree       = "free"
+	TierPro        = "pro"
+	TierEnterprise = "enterprise"
+)
+
+// dailyQuotas is the number of verified calls a developer may make per UTC
+// day across all of their keys.
+var dailyQuotas = map[string]int64{
+	TierFree:       1_000,
+	TierPro:        50_000,
+	TierEnterprise: 1_000_000,
+}
+
+var scopePattern = regexp.MustCompile(`^[a-z0-9_.-]+(:[a-z0-9_.*-]+)*$`)
+
+// DailyQuotaFor returns the daily call quota of tier; unknown tiers get the
+// free quota.
+func DailyQuotaFor(tier string) int64 {
+	if q, ok := dailyQuotas[strings.ToLower(strings.TrimSpace(tier))]; ok {
+		return q
+	}
+	return dailyQuotas[TierFree]
+}
+
+// UsageDay is the rollup bucket of t: its UTC date.
+func UsageDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// FormatAPIKey joins a lookup prefix and secret into the key handed to the
+// developer.
+func FormatAPIKey(prefix, secret string) string {
+	return APIKeyPrefix + prefix + "_" + secret
+}
+
+// MaskAPIKey renders a key for display without its secret.
+func MaskAPIKey(prefix string) string {
+	return APIKeyPrefix + prefix + "_****"
+}
+
+// ParseAPIKey splits a presented key into prefix and secret.
+func ParseAPIKey(raw string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(raw), APIKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+// HashAPIKeySecret is the stored form of secret.
+func HashAPIKeySecret(salt, secret string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether secret hashes to the stored hash, in constant time.
+func (k APIKey) Matches(secret string) bool {
+	got := HashAPIKeySecret(k.Salt, secret)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(k.KeyHash)) == 1
+}
+
+// UsableAt reports why the key cannot authenticate a call at now. A
+// deprecated key keeps working until its rotation grace period ends.
+func (k APIKey) UsableAt(now time.Time) error {
+	switch k.Status {
+	case APIKeyStatusActive:
+	case APIKeyStatusDeprecated:
+		if k.GraceUntil == nil || !now.Before(*k.GraceUntil) {
+			return fmt.Errorf("%w: rotated key past its grace period", ErrAPIKeyExpired)
+		}
+	default:
+		return fmt.Errorf("%w: key is %s", ErrInvalidAPIKey, k.Status)
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// AllowsScope reports whether the key grants scope. A granted "name:*"
+// covers every "name:<action>"; an empty scope asks for none.
+func (k APIKey) AllowsScope(scope string) bool {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if scope == "" {
+		return true
+	}
+	for _, granted := range k.Scopes {
+		if granted == ScopeAll || granted == scope {
+			return true
+		}
+		if base, ok := strings.CutSuffix(granted, ":*"); ok && strings.HasPrefix(scope, base+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsIP reports whether ip is inside the key's allow list. An empty list
+// allows any caller; a restricted key rejects calls without a client IP.
+func (k APIKey) AllowsIP(ip string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, entry := range k.AllowedIPs {
+		if prefix, err := netip.ParsePrefix(entry); err == nil && prefix.Contains(addr) {
+			return true
+		}
+		if allowed, err := netip.ParseAddr(entry); err == nil && allowed.Unmap() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOrigin reports whether origin matches the key's allow list. Entries
+// are exact origins or "https://*.example.com" for any subdomain.
+func (k APIKey) AllowsOrigin(origin string) bool {
+	if len(k.AllowedOrigins) == 0 {
+		return true
+	}
+	got, err := NormalizeOrigin(origin)
+	if err != nil {
+		return false
+	}
+	for _, entry := range k.AllowedOrigins {
+		if entry == got {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(entry, "://*."); ok {
+			if rest, ok := strings.CutPrefix(got, scheme+"://"); ok && strings.HasSuffix(rest, "."+host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NormalizeScopes lower-cases, validates and de-duplicates scopes; no
+// scopes means ScopeAll.
+func NormalizeScopes(scopes []string) ([]string, error) {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		if s != ScopeAll && !scopePattern.MatchString(s) {
+			return nil, fmt.Errorf("%w: invalid scope %q", ErrInvalidInput, s)
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	if len(out) == 0 {
+		return []string{ScopeAll}, nil
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// NormalizeAllowedIPs validates IP addresses and CIDR ranges.
+func NormalizeAllowedIPs(entries []string) ([]string, error) {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(e); err == nil {
+			out = append(out, prefix.Masked().String())
+			continue
+		}
+		addr, err := netip.ParseAddr(e)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid ip or cidr %q", ErrInvalidInput, e)
+		}
+		out = append(out, addr.Unmap().String())
+	}
+	return out, nil
+}
+
+// NormalizeAllowedOrigins validates origins, keeping a leading "*." host
+// wildcard.
+func NormalizeAllowedOrigins(entries []string) ([]string, error) {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		wildcard := false
+		if scheme, host, ok := strings.Cut(e, "://*."); ok {
+			wildcard = true
+			e = scheme + "://" + host
+		}
+		origin, err := NormalizeOrigin(e)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid origin %q", ErrInvalidInput, e)
+		}
+		if wildcard {
+			scheme, host, _ := strings.Cut(origin, "://")
+			origin = scheme + "://*." + host
+		}
+		out = append(out, origin)
+	}
+	return out, nil
+}
+
+// NormalizeOrigin reduces an origin or URL to scheme://host[:port].
+func NormalizeOrigin(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if (scheme != "http" && scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("origin needs an http(s) scheme and host")
+	}
+	return scheme + "://" + strings.ToLower(u.Host), nil
+}
@@ -35,13 +35,25 @@ type DeveloperSession struct {
 }
 
 type APIKey struct {
-	KeyID       string     `json:"key_id"`
-	DeveloperID string     `json:"developer_id"`
-	Label       string     `json:"label"`
-	MaskedKey   string     `json:"masked_key"`
-	Status      string     `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	KeyID          string     `json:"key_id"`
+	DeveloperID    string     `json:"developer_id"`
+	Label          string     `json:"label"`
+	Prefix         string     `json:"prefix"`
+	MaskedKey      string     `json:"masked_key"`
+	Salt           string     `json:"-"`
+	KeyHash        string     `json:"-"`
+	Scopes         []string   `json:"scopes"`
+	AllowedIPs     []string   `json:"allowed_ips,omitempty"`
+	AllowedOrigins []string   `json:"allowed_origins,omitempty"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	GraceUntil     *time.Time `json:"grace_until,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	// Secret is the plaintext key, set only on the value returned when the
+	// key is issued. It is never stored.
+	Secret string `json:"-"`
 }
 
 type APIKeyRotation struct {
@@ -78,6 +90,15 @@ type DeveloperUsage struct {
 	PeriodEnd    time.Time `json:"period_end"`
 }
 
+// APIKeyUsage is the number of calls one key made on one UTC day.
+type APIKeyUsage struct {
+	DeveloperID string    `json:"developer_id"`
+	KeyID       string    `json:"key_id"`
+	Day         string    `json:"day"`
+	Requests    int64     `json:"requests"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 type AuditLog struct {
 	AuditID     string            `json:"audit_id"`
 	DeveloperID string            `json:"developer_id"`
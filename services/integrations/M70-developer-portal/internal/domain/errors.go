@@ -10,4 +10,7 @@ var (
 	ErrConflict            = errors.New("conflict")
 	ErrIdempotencyRequired = errors.New("idempotency_key_required")
 	ErrIdempotencyConflict = errors.New("idempotency_conflict")
+	ErrInvalidAPIKey       = errors.New("invalid_api_key")
+	ErrAPIKeyExpired       = errors.New("api_key_expired")
+	ErrQuotaExceeded       = errors.New("quota_exceeded")
 )
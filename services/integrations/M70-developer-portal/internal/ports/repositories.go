@@ -19,7 +19,12 @@ type SessionRepository interface {
 type APIKeyRepository interface {
 	Create(ctx context.Context, row domain.APIKey) error
 	GetByID(ctx context.Context, keyID string) (domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (domain.APIKey, error)
+	ListByDeveloperID(ctx context.Context, developerID string) ([]domain.APIKey, error)
 	Update(ctx context.Context, row domain.APIKey) error
+	// TouchLastUsed records a verified call without rewriting the rest of
+	// the key, so it cannot undo a concurrent revoke.
+	TouchLastUsed(ctx context.Context, keyID string, at time.Time) error
 }
 
 type APIKeyRotationRepository interface {
@@ -40,6 +45,16 @@ type UsageRepository interface {
 	GetByDeveloperID(ctx context.Context, developerID string) (domain.DeveloperUsage, error)
 }
 
+type APIKeyUsageRepository interface {
+	// Consume counts one call of keyID on day unless the developer's calls
+	// across all keys on that day already reached limit (zero means no
+	// limit), in which case it returns domain.ErrQuotaExceeded. It returns
+	// the developer's total for the day including this call.
+	Consume(ctx context.Context, developerID, keyID, day string, limit int64, at time.Time) (int64, error)
+	// ListByDeveloperID returns the rollups of days in [fromDay, toDay].
+	ListByDeveloperID(ctx context.Context, developerID, fromDay, toDay string) ([]domain.APIKeyUsage, error)
+}
+
 type AuditRepository interface {
 	Append(ctx context.Context, row domain.AuditLog) error
 }
@@ -2,7 +2,10 @@ package unit
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M70-developer-portal/internal/application"
@@ -108,3 +111,149 @@ func TestAPIKeyLifecycleAndWebhook(t *testing.T) {
 		t.Fatalf("unexpected delivery: %+v", delivery)
 	}
 }
+
+func newMeteredService(grace time.Duration) *application.Service {
+	repos := postgres.NewRepositories()
+	return application.NewService(application.Dependencies{
+		Config:      application.Config{RotationGracePeriod: grace},
+		Developers:  repos.Developers,
+		Sessions:    repos.Sessions,
+		APIKeys:     repos.APIKeys,
+		Rotations:   repos.Rotations,
+		Webhooks:    repos.Webhooks,
+		Deliveries:  repos.Deliveries,
+		Usage:       repos.Usage,
+		KeyUsage:    repos.KeyUsage,
+		Audit:       repos.Audit,
+		Idempotency: repos.Idempotency,
+	})
+}
+
+func TestVerifyAPIKeyRestrictionsAndRotationGrace(t *testing.T) {
+	ctx := context.Background()
+	svc := newMeteredService(50 * time.Millisecond)
+	dev, _, err := svc.RegisterDeveloper(ctx, application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-register-verify"}, application.RegisterDeveloperInput{
+		Email:   "verify@example.com",
+		AppName: "Verify App",
+	})
+	if err != nil {
+		t.Fatalf("register developer: %v", err)
+	}
+	owner := application.Actor{SubjectID: dev.DeveloperID, Role: "developer", IdempotencyKey: "idem-key-verify"}
+	key, err := svc.CreateAPIKey(ctx, owner, application.CreateAPIKeyInput{
+		Label:          "Restricted",
+		Scopes:         []string{"campaigns:*", "payouts:read"},
+		AllowedIPs:     []string{"10.0.0.0/8"},
+		AllowedOrigins: []string{"https://*.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+	if !strings.HasPrefix(key.Secret, "vk_live_"+key.Prefix+"_") || key.KeyHash == "" || strings.Contains(key.KeyHash, key.Secret) {
+		t.Fatalf("expected a prefixed plaintext key and a salted hash: %+v", key)
+	}
+	replay, err := svc.CreateAPIKey(ctx, owner, application.CreateAPIKeyInput{
+		Label:          "Restricted",
+		Scopes:         []string{"campaigns:*", "payouts:read"},
+		AllowedIPs:     []string{"10.0.0.0/8"},
+		AllowedOrigins: []string{"https://*.example.com"},
+	})
+	if err != nil || replay.KeyID != key.KeyID || replay.Secret != "" {
+		t.Fatalf("replay must return the key without its secret: %+v err=%v", replay, err)
+	}
+
+	ok := application.VerifyAPIKeyInput{Key: key.Secret, Scope: "campaigns:write", ClientIP: "10.1.2.3", Origin: "https://app.example.com"}
+	out, err := svc.VerifyAPIKey(ctx, ok)
+	if err != nil {
+		t.Fatalf("verify key: %v", err)
+	}
+	if out.Developer.DeveloperID != dev.DeveloperID || out.DailyUsage != 1 || out.DailyQuota != domain.DailyQuotaFor(domain.TierFree) {
+		t.Fatalf("unexpected verification: %+v", out)
+	}
+	rejected := []struct {
+		name string
+		in   application.VerifyAPIKeyInput
+		want error
+	}{
+		{"wrong secret", application.VerifyAPIKeyInput{Key: "vk_live_" + key.Prefix + "_nope"}, domain.ErrInvalidAPIKey},
+		{"scope", application.VerifyAPIKeyInput{Key: key.Secret, Scope: "payouts:write", ClientIP: "10.1.2.3", Origin: "https://app.example.com"}, domain.ErrForbidden},
+		{"ip", application.VerifyAPIKeyInput{Key: key.Secret, Scope: "payouts:read", ClientIP: "192.168.1.1", Origin: "https://app.example.com"}, domain.ErrForbidden},
+		{"origin", application.VerifyAPIKeyInput{Key: key.Secret, Scope: "payouts:read", ClientIP: "10.1.2.3", Origin: "https://example.org"}, domain.ErrForbidden},
+	}
+	for _, tc := range rejected {
+		if _, err := svc.VerifyAPIKey(ctx, tc.in); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	_, oldKey, newKey, err := svc.RotateAPIKey(ctx, application.Actor{SubjectID: dev.DeveloperID, Role: "developer", IdempotencyKey: "idem-rotate-verify"}, key.KeyID)
+	if err != nil {
+		t.Fatalf("rotate key: %v", err)
+	}
+	if oldKey.GraceUntil == nil || newKey.Secret == "" || len(newKey.Scopes) != 2 {
+		t.Fatalf("unexpected rotation: old=%+v new=%+v", oldKey, newKey)
+	}
+	if _, err := svc.VerifyAPIKey(ctx, ok); err != nil {
+		t.Fatalf("old key must work during the grace period: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, err := svc.VerifyAPIKey(ctx, ok); !errors.Is(err, domain.ErrAPIKeyExpired) {
+		t.Fatalf("old key after grace: got %v", err)
+	}
+	ok.Key = newKey.Secret
+	if _, err := svc.VerifyAPIKey(ctx, ok); err != nil {
+		t.Fatalf("verify new key: %v", err)
+	}
+	if _, err := svc.RevokeAPIKey(ctx, application.Actor{SubjectID: dev.DeveloperID, Role: "developer"}, newKey.KeyID); err != nil {
+		t.Fatalf("revoke key: %v", err)
+	}
+	if _, err := svc.VerifyAPIKey(ctx, ok); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Fatalf("revoked key: got %v", err)
+	}
+}
+
+func TestDailyQuotaAndUsageDashboard(t *testing.T) {
+	ctx := context.Background()
+	svc := newMeteredService(0)
+	dev, _, err := svc.RegisterDeveloper(ctx, application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-register-quota"}, application.RegisterDeveloperInput{
+		Email:   "quota@example.com",
+		AppName: "Quota App",
+	})
+	if err != nil {
+		t.Fatalf("register developer: %v", err)
+	}
+	owner := application.Actor{SubjectID: dev.DeveloperID, Role: "developer"}
+	var secrets []string
+	for i, label := range []string{"server", "mobile"} {
+		owner.IdempotencyKey = "idem-quota-key-" + label
+		key, err := svc.CreateAPIKey(ctx, owner, application.CreateAPIKeyInput{Label: label})
+		if err != nil {
+			t.Fatalf("create key %d: %v", i, err)
+		}
+		secrets = append(secrets, key.Secret)
+	}
+	quota := domain.DailyQuotaFor(domain.TierFree)
+	for i := int64(0); i < quota; i++ {
+		if _, err := svc.VerifyAPIKey(ctx, application.VerifyAPIKeyInput{Key: secrets[i%2]}); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if _, err := svc.VerifyAPIKey(ctx, application.VerifyAPIKeyInput{Key: secrets[0]}); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("expected quota to be enforced across keys, got %v", err)
+	}
+
+	dash, err := svc.GetUsageDashboard(ctx, owner, "", 3)
+	if err != nil {
+		t.Fatalf("usage dashboard: %v", err)
+	}
+	today := dash.Days[len(dash.Days)-1]
+	if len(dash.Days) != 3 || dash.UsedToday != quota || dash.RemainingToday != 0 || today.Requests != quota || len(today.ByKey) != 2 {
+		t.Fatalf("unexpected dashboard: %+v", dash)
+	}
+	if len(dash.Keys) != 2 || dash.Keys[0].Requests+dash.Keys[1].Requests != quota || dash.Keys[0].Key.LastUsedAt == nil {
+		t.Fatalf("unexpected key summaries: %+v", dash.Keys)
+	}
+	if _, err := svc.GetUsageDashboard(ctx, application.Actor{SubjectID: "someone-else", Role: "developer"}, dev.DeveloperID, 7); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected other developers to be denied, got %v", err)
+	}
+}
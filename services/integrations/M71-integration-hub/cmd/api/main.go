@@ -1,19 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/adapters/actions"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/application"
 )
 
 func main() {
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv("M71-Integration-Hub", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
+
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
+		Config: application.Config{
+			ExecutionLease: time.Duration(envInt("WORKFLOW_EXECUTION_LEASE_SECONDS", 120)) * time.Second,
+		},
 		Integrations: repos.Integrations,
 		Credentials:  repos.Credentials,
 		Workflows:    repos.Workflows,
@@ -23,7 +37,16 @@ func main() {
 		Analytics:    repos.Analytics,
 		Logs:         repos.Logs,
 		Idempotency:  repos.Idempotency,
+		Executors: actions.New(actions.Config{
+			Timeout:           time.Duration(envInt("WORKFLOW_ACTION_TIMEOUT_SECONDS", 15)) * time.Second,
+			NotificationURL:   os.Getenv("NOTIFICATION_INTAKE_URL"),
+			WebhookManagerURL: os.Getenv("WEBHOOK_MANAGER_URL"),
+			AllowedHosts:      strings.Split(os.Getenv("WORKFLOW_ACTION_ALLOWED_HOSTS"), ","),
+		}),
 	})
+	// Executions live in this process's repositories, so delays and retries
+	// are resumed here rather than in cmd/worker.
+	go resumeExecutions(context.Background(), svc, time.Duration(envInt("WORKFLOW_RESUME_INTERVAL_SECONDS", 5))*time.Second)
 	router := httpadapter.NewRouter(httpadapter.NewHandler(svc))
 	addr := strings.TrimSpace(os.Getenv("PORT"))
 	if addr == "" {
@@ -33,7 +56,29 @@ func main() {
 		addr = ":" + addr
 	}
 	log.Printf("M71-Integration-Hub listening on %s", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := http.ListenAndServe(addr, observability.HTTPMiddleware("M71-Integration-Hub")(router)); err != nil {
 		log.Fatal(err)
 	}
 }
+
+func resumeExecutions(ctx context.Context, svc *application.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := svc.ResumeDueExecutions(ctx); err != nil {
+				log.Printf("resume workflow executions: %v", err)
+			}
+		}
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name))); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,33 @@
+package actions
+
+import (
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+type Config struct {
+	Timeout time.Duration
+	// NotificationURL is the notification intake endpoint; without it
+	// notification steps fail.
+	NotificationURL string
+	// WebhookManagerURL is the M72 base URL; without it m72_webhook steps
+	// fail.
+	WebhookManagerURL string
+	// AllowedHosts are internal hosts http_request and chat_post steps may
+	// reach; every other non-public address is refused.
+	AllowedHosts []string
+}
+
+// New returns the built-in executors the configuration allows.
+func New(cfg Config) []ports.ActionExecutor {
+	out := []ports.ActionExecutor{NewHTTPRequest(cfg.Timeout, cfg.AllowedHosts...), NewChatPost(cfg.Timeout, cfg.AllowedHosts...)}
+	if strings.TrimSpace(cfg.NotificationURL) != "" {
+		out = append(out, NewNotification(cfg.NotificationURL, cfg.Timeout))
+	}
+	if strings.TrimSpace(cfg.WebhookManagerURL) != "" {
+		out = append(out, NewWebhook(cfg.WebhookManagerURL, cfg.Timeout))
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+// ChatPost posts a message to a Slack-compatible incoming webhook. Input:
+// webhook_url, text and an optional channel. The webhook URL is reached under
+// the same address rules as HTTPRequest.
+type ChatPost struct {
+	client *http.Client
+}
+
+func NewChatPost(timeout time.Duration, allowHosts ...string) *ChatPost {
+	return &ChatPost{client: newGuardedClient(timeout, allowHosts)}
+}
+
+func (a *ChatPost) Type() string { return domain.StepTypeChatPost }
+
+func (a *ChatPost) Execute(ctx context.Context, req ports.ActionRequest) (map[string]any, error) {
+	target, err := required(req, "webhook_url")
+	if err != nil {
+		return nil, err
+	}
+	text, err := required(req, "text")
+	if err != nil {
+		return nil, err
+	}
+	msg := map[string]string{"text": text}
+	channel := strings.TrimSpace(req.Input["channel"])
+	if channel != "" {
+		msg["channel"] = channel
+	}
+	body, _ := json.Marshal(msg)
+	status, _, err := send(ctx, a.client, http.MethodPost, target, body, map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"status_code": status, "channel": channel}, nil
+}
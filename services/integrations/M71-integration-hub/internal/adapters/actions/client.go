@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+const maxResponseBytes = 1 << 20
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return observability.WrapClient(&http.Client{Timeout: timeout})
+}
+
+// send performs one action request and maps the outcome onto the errors
+// ports.ActionExecutor documents: 4xx other than 408 and 429 is rejected,
+// everything else that is not 2xx is retried.
+func send(ctx context.Context, client *http.Client, method, url string, body []byte, headers map[string]string) (int, any, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", domain.ErrActionRejected, err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if errors.Is(err, errBlockedAddress) {
+		return 0, nil, fmt.Errorf("%w: %v", domain.ErrActionRejected, err)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", domain.ErrActionUnavailable, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("%w: read response: %v", domain.ErrActionUnavailable, err)
+	}
+	decoded := decodeBody(raw)
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return resp.StatusCode, decoded, fmt.Errorf("%w: status %d", domain.ErrActionUnavailable, resp.StatusCode)
+	case resp.StatusCode >= 400:
+		return resp.StatusCode, decoded, fmt.Errorf("%w: status %d: %s", domain.ErrActionRejected, resp.StatusCode, truncate(strings.TrimSpace(string(raw)), 200))
+	case resp.StatusCode >= 300:
+		return resp.StatusCode, decoded, fmt.Errorf("%w: unexpected status %d", domain.ErrActionRejected, resp.StatusCode)
+	}
+	return resp.StatusCode, decoded, nil
+}
+
+// decodeBody returns JSON bodies decoded so later steps can reference their
+// fields, and anything else as a string.
+func decodeBody(raw []byte) any {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err == nil {
+		return v
+	}
+	return truncate(string(raw), 4096)
+}
+
+func truncate(v string, n int) string {
+	if len(v) <= n {
+		return v
+	}
+	return v[:n]
+}
+
+// required returns the trimmed input value or a rejection naming the key.
+func required(req ports.ActionRequest, key string) (string, error) {
+	v := strings.TrimSpace(req.Input[key])
+	if v == "" {
+		return "", fmt.Errorf("%w: input %q is required", domain.ErrActionRejected, key)
+	}
+	return v, nil
+}
+
+// jsonValue parses v as JSON, falling back to the plain string so template
+// output that is not JSON is still delivered.
+func jsonValue(v string) any {
+	var out any
+	if err := json.Unmarshal([]byte(v), &out); err == nil {
+		return out
+	}
+	return v
+}
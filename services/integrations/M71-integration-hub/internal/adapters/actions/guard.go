@@ -0,0 +1,82 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+)
+
+// errBlockedAddress marks a dial to an address workflow-supplied URLs may
+// not reach.
+var errBlockedAddress = errors.New("destination address not allowed")
+
+// blockedPrefixes are non-public ranges net/netip does not classify as
+// private, loopback or link-local.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// newGuardedClient returns a client for URLs taken from workflow input. The
+// destination is checked after DNS resolution on every dial, redirects
+// included, so only public addresses are reachable. Hosts in allowHosts,
+// matched on the URL's host name or literal IP, skip the check; operators
+// list internal endpoints there.
+func newGuardedClient(timeout time.Duration, allowHosts []string) *http.Client {
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	allowed := map[string]bool{}
+	for _, host := range allowHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowed[host] = true
+		}
+	}
+	open := &net.Dialer{Timeout: timeout}
+	guarded := &net.Dialer{Timeout: timeout, Control: func(_, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errBlockedAddress, address)
+		}
+		if !publicAddress(addrPort.Addr()) {
+			return fmt.Errorf("%w: %s", errBlockedAddress, addrPort.Addr())
+		}
+		return nil
+	}}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would dial on the workflow's behalf and bypass the check.
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && allowed[strings.ToLower(host)] {
+			return open.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+	return observability.WrapClient(&http.Client{Timeout: timeout, Transport: transport})
+}
+
+// publicAddress reports whether ip is a globally routable unicast address:
+// not loopback, private, link-local (cloud metadata included), multicast or
+// unspecified.
+func publicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
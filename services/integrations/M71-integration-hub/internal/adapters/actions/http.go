@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+// HTTPRequest calls an arbitrary URL. Input: url, method (POST), body,
+// content_type (application/json) and header.<Name> for extra headers. Only
+// public addresses and the allowHosts given at construction are reachable.
+type HTTPRequest struct {
+	client *http.Client
+}
+
+func NewHTTPRequest(timeout time.Duration, allowHosts ...string) *HTTPRequest {
+	return &HTTPRequest{client: newGuardedClient(timeout, allowHosts)}
+}
+
+func (a *HTTPRequest) Type() string { return domain.StepTypeHTTPRequest }
+
+func (a *HTTPRequest) Execute(ctx context.Context, req ports.ActionRequest) (map[string]any, error) {
+	target, err := required(req, "url")
+	if err != nil {
+		return nil, err
+	}
+	if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: invalid url %q", domain.ErrActionRejected, target)
+	}
+	method := strings.ToUpper(strings.TrimSpace(req.Input["method"]))
+	if method == "" {
+		method = http.MethodPost
+	}
+	headers := map[string]string{"Content-Type": "application/json", "Idempotency-Key": req.IdempotencyKey}
+	if ct := strings.TrimSpace(req.Input["content_type"]); ct != "" {
+		headers["Content-Type"] = ct
+	}
+	for k, v := range req.Input {
+		if name, ok := strings.CutPrefix(k, "header."); ok && name != "" {
+			headers[name] = v
+		}
+	}
+	status, body, err := send(ctx, a.client, method, target, []byte(req.Input["body"]), headers)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"status_code": status, "body": body}, nil
+}
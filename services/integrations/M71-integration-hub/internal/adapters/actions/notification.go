@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+// Notification hands an in-app notification to the notification service's
+// intake endpoint. Input: title, body, optional user_id (the workflow
+// owner), type and data (JSON).
+type Notification struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewNotification(endpoint string, timeout time.Duration) *Notification {
+	return &Notification{endpoint: strings.TrimSpace(endpoint), client: newHTTPClient(timeout)}
+}
+
+func (a *Notification) Type() string { return domain.StepTypeNotification }
+
+func (a *Notification) Execute(ctx context.Context, req ports.ActionRequest) (map[string]any, error) {
+	title, err := required(req, "title")
+	if err != nil {
+		return nil, err
+	}
+	userID := strings.TrimSpace(req.Input["user_id"])
+	if userID == "" {
+		userID = req.UserID
+	}
+	typ := strings.TrimSpace(req.Input["type"])
+	if typ == "" {
+		typ = "workflow"
+	}
+	msg := map[string]any{"user_id": userID, "type": typ, "title": title, "body": req.Input["body"], "source": "M71-Integration-Hub", "source_id": req.IdempotencyKey}
+	if data := strings.TrimSpace(req.Input["data"]); data != "" {
+		msg["data"] = jsonValue(data)
+	}
+	body, _ := json.Marshal(msg)
+	status, resp, err := send(ctx, a.client, http.MethodPost, a.endpoint, body, map[string]string{"Content-Type": "application/json", "Idempotency-Key": req.IdempotencyKey})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"status_code": status, "user_id": userID, "response": resp}, nil
+}
@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+// Webhook delivers a payload through an M72 webhook on behalf of the
+// workflow owner, so M72 signs, records and retries the delivery. Input:
+// webhook_id and payload (JSON).
+type Webhook struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewWebhook(baseURL string, timeout time.Duration) *Webhook {
+	return &Webhook{baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"), client: newHTTPClient(timeout)}
+}
+
+func (a *Webhook) Type() string { return domain.StepTypeWebhook }
+
+func (a *Webhook) Execute(ctx context.Context, req ports.ActionRequest) (map[string]any, error) {
+	webhookID, err := required(req, "webhook_id")
+	if err != nil {
+		return nil, err
+	}
+	body, _ := json.Marshal(map[string]any{"payload": jsonValue(req.Input["payload"])})
+	target := a.baseURL + "/api/v1/webhooks/" + url.PathEscape(webhookID) + "/test"
+	headers := map[string]string{
+		"Content-Type":    "application/json",
+		"Authorization":   "Bearer " + req.UserID,
+		"X-Actor-Role":    "user",
+		"Idempotency-Key": req.IdempotencyKey,
+	}
+	status, resp, err := send(ctx, a.client, http.MethodPost, target, body, headers)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]any{"status_code": status, "webhook_id": webhookID}
+	if envelope, ok := resp.(map[string]any); ok {
+		if data, ok := envelope["data"]; ok {
+			out["delivery"] = data
+		}
+	}
+	return out, nil
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -46,6 +47,8 @@ func (h *Handler) createWorkflow(w http.ResponseWriter, r *http.Request) {
 		TriggerEventType:    req.TriggerEventType,
 		ActionType:          req.ActionType,
 		IntegrationID:       req.IntegrationID,
+		Steps:               toDomainSteps(req.Steps),
+		Filter:              toDomainCondition(req.Filter),
 	})
 	if err != nil {
 		status, code := mapDomainError(err)
@@ -66,7 +69,14 @@ func (h *Handler) publishWorkflow(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) testWorkflow(w http.ResponseWriter, r *http.Request) {
-	row, err := h.service.TestWorkflow(r.Context(), actorFromContext(r.Context()), strings.TrimSpace(r.PathValue("id")))
+	var req contracts.TestWorkflowRequest
+	if r.Method == http.MethodPost && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+			return
+		}
+	}
+	row, err := h.service.TestWorkflow(r.Context(), actorFromContext(r.Context()), strings.TrimSpace(r.PathValue("id")), req.SamplePayload)
 	if err != nil {
 		status, code := mapDomainError(err)
 		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
@@ -75,6 +85,60 @@ func (h *Handler) testWorkflow(w http.ResponseWriter, r *http.Request) {
 	writeSuccess(w, http.StatusOK, "workflow test executed", toExecutionResponse(row))
 }
 
+func (h *Handler) listExecutions(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	rows, err := h.service.ListExecutions(r.Context(), actorFromContext(r.Context()), strings.TrimSpace(r.PathValue("id")), limit)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	resp := contracts.WorkflowExecutionListResponse{Executions: make([]contracts.WorkflowExecutionResponse, 0, len(rows))}
+	for _, row := range rows {
+		resp.Executions = append(resp.Executions, toExecutionResponse(row))
+	}
+	writeSuccess(w, http.StatusOK, "workflow executions", resp)
+}
+
+func (h *Handler) getExecution(w http.ResponseWriter, r *http.Request) {
+	row, err := h.service.GetExecution(r.Context(), actorFromContext(r.Context()), strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "workflow execution", toExecutionResponse(row))
+}
+
+func (h *Handler) resumeExecution(w http.ResponseWriter, r *http.Request) {
+	row, err := h.service.ResumeExecution(r.Context(), actorFromContext(r.Context()), strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "workflow execution resumed", toExecutionResponse(row))
+}
+
+func (h *Handler) triggerEvent(w http.ResponseWriter, r *http.Request) {
+	var req contracts.TriggerEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	rows, err := h.service.TriggerEvent(r.Context(), actorFromContext(r.Context()), req.EventType, req.Payload)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	resp := contracts.WorkflowExecutionListResponse{Executions: make([]contracts.WorkflowExecutionResponse, 0, len(rows))}
+	for _, row := range rows {
+		resp.Executions = append(resp.Executions, toExecutionResponse(row))
+	}
+	writeSuccess(w, http.StatusAccepted, "event dispatched", resp)
+}
+
 func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
 	var req contracts.CreateWebhookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -139,19 +203,99 @@ func toWorkflowResponse(row domain.Workflow) contracts.WorkflowResponse {
 		TriggerEventType:    row.TriggerEventType,
 		ActionType:          row.ActionType,
 		IntegrationID:       row.IntegrationID,
+		Filter:              toContractCondition(row.Filter),
+		Steps:               toContractSteps(row.Steps),
 		Status:              row.Status,
 		CreatedAt:           row.CreatedAt.UTC().Format(time.RFC3339),
 	}
 }
 
 func toExecutionResponse(row domain.WorkflowExecution) contracts.WorkflowExecutionResponse {
-	return contracts.WorkflowExecutionResponse{
-		ExecutionID: row.ExecutionID,
-		WorkflowID:  row.WorkflowID,
-		Status:      row.Status,
-		TestRun:     row.TestRun,
-		StartedAt:   row.StartedAt.UTC().Format(time.RFC3339),
+	out := contracts.WorkflowExecutionResponse{
+		ExecutionID:      row.ExecutionID,
+		WorkflowID:       row.WorkflowID,
+		UserID:           row.UserID,
+		Status:           row.Status,
+		TestRun:          row.TestRun,
+		TriggerEventType: row.TriggerEventType,
+		TriggerPayload:   row.TriggerPayload,
+		Steps:            make([]contracts.StepRunResponse, 0, len(row.Steps)),
+		Logs:             make([]contracts.ExecutionLogResponse, 0, len(row.Logs)),
+		Error:            row.Error,
+		NextRunAt:        formatTime(row.NextRunAt),
+		StartedAt:        row.StartedAt.UTC().Format(time.RFC3339),
+		FinishedAt:       formatTime(row.FinishedAt),
+	}
+	for _, st := range row.Steps {
+		out.Steps = append(out.Steps, contracts.StepRunResponse{StepID: st.StepID, Type: st.Type, Status: st.Status, Attempts: st.Attempts, Output: st.Output, Error: st.Error, NextAttemptAt: formatTime(st.NextAttemptAt), FinishedAt: formatTime(st.FinishedAt)})
+	}
+	for _, l := range row.Logs {
+		out.Logs = append(out.Logs, contracts.ExecutionLogResponse{At: l.At.UTC().Format(time.RFC3339), StepID: l.StepID, Message: l.Message})
+	}
+	return out
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func toDomainSteps(in []contracts.WorkflowStep) []domain.WorkflowStep {
+	out := make([]domain.WorkflowStep, 0, len(in))
+	for _, st := range in {
+		step := domain.WorkflowStep{StepID: st.StepID, Type: st.Type, DependsOn: st.DependsOn, Condition: toDomainCondition(st.Condition), Input: st.Input, Delay: time.Duration(st.DelaySeconds) * time.Second}
+		if st.Retry != nil {
+			step.Retry = domain.RetryPolicy{MaxAttempts: st.Retry.MaxAttempts, Backoff: time.Duration(st.Retry.BackoffSeconds) * time.Second}
+		}
+		out = append(out, step)
+	}
+	return out
+}
+
+func toContractSteps(in []domain.WorkflowStep) []contracts.WorkflowStep {
+	out := make([]contracts.WorkflowStep, 0, len(in))
+	for _, st := range in {
+		out = append(out, contracts.WorkflowStep{
+			StepID:       st.StepID,
+			Type:         st.Type,
+			DependsOn:    st.DependsOn,
+			Condition:    toContractCondition(st.Condition),
+			Input:        st.Input,
+			DelaySeconds: int(st.Delay / time.Second),
+			Retry:        &contracts.RetryPolicy{MaxAttempts: st.Retry.MaxAttempts, BackoffSeconds: int(st.Retry.Backoff / time.Second)},
+		})
+	}
+	return out
+}
+
+func toDomainCondition(in *contracts.Condition) *domain.Condition {
+	if in == nil {
+		return nil
+	}
+	out := &domain.Condition{}
+	for _, p := range in.All {
+		out.All = append(out.All, domain.Predicate{Path: p.Path, Op: p.Op, Value: p.Value})
+	}
+	for _, p := range in.Any {
+		out.Any = append(out.Any, domain.Predicate{Path: p.Path, Op: p.Op, Value: p.Value})
+	}
+	return out
+}
+
+func toContractCondition(in *domain.Condition) *contracts.Condition {
+	if in == nil {
+		return nil
+	}
+	out := &contracts.Condition{}
+	for _, p := range in.All {
+		out.All = append(out.All, contracts.Predicate{Path: p.Path, Op: p.Op, Value: p.Value})
+	}
+	for _, p := range in.Any {
+		out.Any = append(out.Any, contracts.Predicate{Path: p.Path, Op: p.Op, Value: p.Value})
 	}
+	return out
 }
 
 func toWebhookResponse(row domain.Webhook) contracts.WebhookResponse {
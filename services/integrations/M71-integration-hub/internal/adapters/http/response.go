@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/contracts"
@@ -44,6 +45,10 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusBadRequest, "idempotency_key_required"
 	case domain.ErrIdempotencyConflict, domain.ErrConflict:
 		return http.StatusConflict, "conflict"
+	}
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest, "invalid_input"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
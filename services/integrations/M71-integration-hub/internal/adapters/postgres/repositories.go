@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -110,6 +113,19 @@ func (r *WorkflowRepository) Update(_ context.Context, row domain.Workflow) erro
 	return nil
 }
 
+func (r *WorkflowRepository) ListPublishedByTrigger(_ context.Context, eventType string) ([]domain.Workflow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.Workflow, 0)
+	for _, row := range r.rowsByID {
+		if row.Status == domain.WorkflowStatusPublished && strings.EqualFold(row.TriggerEventType, eventType) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
 type WorkflowExecutionRepository struct {
 	mu       sync.Mutex
 	rowsByID map[string]domain.WorkflowExecution
@@ -121,10 +137,96 @@ func (r *WorkflowExecutionRepository) Create(_ context.Context, row domain.Workf
 	if _, ok := r.rowsByID[row.ExecutionID]; ok {
 		return domain.ErrConflict
 	}
-	r.rowsByID[row.ExecutionID] = row
+	r.rowsByID[row.ExecutionID] = cloneExecution(row)
+	return nil
+}
+
+func (r *WorkflowExecutionRepository) GetByID(_ context.Context, executionID string) (domain.WorkflowExecution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.rowsByID[executionID]
+	if !ok {
+		return domain.WorkflowExecution{}, domain.ErrNotFound
+	}
+	return cloneExecution(row), nil
+}
+
+func (r *WorkflowExecutionRepository) Update(_ context.Context, row domain.WorkflowExecution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rowsByID[row.ExecutionID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.rowsByID[row.ExecutionID] = cloneExecution(row)
 	return nil
 }
 
+func (r *WorkflowExecutionRepository) ListByWorkflowID(_ context.Context, workflowID string, limit int) ([]domain.WorkflowExecution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.WorkflowExecution, 0)
+	for _, row := range r.rowsByID {
+		if row.WorkflowID == workflowID {
+			out = append(out, cloneExecution(row))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *WorkflowExecutionRepository) ClaimDue(_ context.Context, now, leaseUntil time.Time, limit int) ([]domain.WorkflowExecution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.WorkflowExecution, 0)
+	for _, row := range r.rowsByID {
+		if dueAt(row, now) != nil {
+			out = append(out, cloneExecution(row))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return dueAt(out[i], now).Before(*dueAt(out[j], now)) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	for i := range out {
+		lease := leaseUntil
+		out[i].LeaseExpiresAt = &lease
+		r.rowsByID[out[i].ExecutionID] = cloneExecution(out[i])
+	}
+	return out, nil
+}
+
+// dueAt is when an execution became claimable, or nil if it is not: a
+// waiting execution once its NextRunAt passes and a running one once its
+// lease lapses. A live lease holds either.
+func dueAt(row domain.WorkflowExecution, now time.Time) *time.Time {
+	if row.LeaseExpiresAt != nil && row.LeaseExpiresAt.After(now) {
+		return nil
+	}
+	var at *time.Time
+	switch row.Status {
+	case domain.ExecutionStatusWaiting:
+		at = row.NextRunAt
+	case domain.ExecutionStatusRunning:
+		at = row.LeaseExpiresAt
+	}
+	if at == nil || at.After(now) {
+		return nil
+	}
+	return at
+}
+
+// cloneExecution deep-copies step runs and logs so callers never share
+// slices with the stored row, as they would not with a database.
+func cloneExecution(row domain.WorkflowExecution) domain.WorkflowExecution {
+	raw, _ := json.Marshal(row)
+	var out domain.WorkflowExecution
+	_ = json.Unmarshal(raw, &out)
+	return out
+}
+
 type WebhookRepository struct {
 	mu       sync.Mutex
 	rowsByID map[string]domain.Webhook
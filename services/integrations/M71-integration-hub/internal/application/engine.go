@@ -0,0 +1,421 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
+)
+
+// TriggerEvent starts an execution of every published workflow listening for
+// eventType whose filter matches the payload. Only platform services and
+// admins deliver events.
+func (s *Service) TriggerEvent(ctx context.Context, actor Actor, eventType string, payload json.RawMessage) ([]domain.WorkflowExecution, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if role := strings.ToLower(strings.TrimSpace(actor.Role)); role != "admin" && role != "service" {
+		return nil, domain.ErrForbidden
+	}
+	eventType = strings.TrimSpace(eventType)
+	trigger, err := decodePayload(payload)
+	if eventType == "" || err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	workflows, err := s.workflows.ListPublishedByTrigger(ctx, eventType)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.WorkflowExecution, 0, len(workflows))
+	for _, wf := range workflows {
+		if !wf.Filter.Evaluate(map[string]any{"trigger": trigger}) {
+			continue
+		}
+		exec, err := s.startExecution(ctx, wf, eventType, payload, false)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, exec)
+	}
+	return out, nil
+}
+
+// TestWorkflow runs a workflow against a sample payload without side
+// effects: actions render their input and report it instead of running,
+// and delays are not waited out.
+func (s *Service) TestWorkflow(ctx context.Context, actor Actor, workflowID string, sample json.RawMessage) (domain.WorkflowExecution, error) {
+	workflowID = strings.TrimSpace(workflowID)
+	if workflowID == "" {
+		return domain.WorkflowExecution{}, domain.ErrInvalidInput
+	}
+	if len(sample) == 0 {
+		sample = json.RawMessage(`{}`)
+	}
+	if _, err := decodePayload(sample); err != nil {
+		return domain.WorkflowExecution{}, domain.ErrInvalidInput
+	}
+	row, err := s.workflows.GetByID(ctx, workflowID)
+	if err != nil {
+		return domain.WorkflowExecution{}, err
+	}
+	if !canAccessUser(actor, row.UserID) {
+		return domain.WorkflowExecution{}, authorizeError(actor)
+	}
+	return s.startExecution(ctx, row, row.TriggerEventType, sample, true)
+}
+
+func (s *Service) GetExecution(ctx context.Context, actor Actor, executionID string) (domain.WorkflowExecution, error) {
+	exec, err := s.executions.GetByID(ctx, strings.TrimSpace(executionID))
+	if err != nil {
+		return domain.WorkflowExecution{}, err
+	}
+	if !canAccessUser(actor, exec.UserID) {
+		return domain.WorkflowExecution{}, authorizeError(actor)
+	}
+	return exec, nil
+}
+
+func (s *Service) ListExecutions(ctx context.Context, actor Actor, workflowID string, limit int) ([]domain.WorkflowExecution, error) {
+	wf, err := s.workflows.GetByID(ctx, strings.TrimSpace(workflowID))
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessUser(actor, wf.UserID) {
+		return nil, authorizeError(actor)
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.executions.ListByWorkflowID(ctx, wf.WorkflowID, limit)
+}
+
+// ResumeExecution retries a failed execution from its failed steps with a
+// fresh attempt budget; steps that already succeeded are not run again.
+func (s *Service) ResumeExecution(ctx context.Context, actor Actor, executionID string) (domain.WorkflowExecution, error) {
+	exec, err := s.GetExecution(ctx, actor, executionID)
+	if err != nil {
+		return domain.WorkflowExecution{}, err
+	}
+	if exec.Status != domain.ExecutionStatusFailed {
+		return domain.WorkflowExecution{}, domain.ErrConflict
+	}
+	wf, err := s.workflows.GetByID(ctx, exec.WorkflowID)
+	if err != nil {
+		return domain.WorkflowExecution{}, err
+	}
+	now := s.nowFn()
+	for i := range exec.Steps {
+		if exec.Steps[i].Status == domain.StepStatusFailed {
+			exec.Steps[i] = domain.StepRun{StepID: exec.Steps[i].StepID, Type: exec.Steps[i].Type, Status: domain.StepStatusPending}
+		}
+	}
+	exec.Error = ""
+	exec.FinishedAt = nil
+	exec.Status = domain.ExecutionStatusRunning
+	logExecution(&exec, now, "", "resumed by "+actor.SubjectID)
+	return s.advance(ctx, wf, exec)
+}
+
+// ResumeDueExecutions continues executions whose delay or retry wait has
+// passed, and reclaims running executions whose worker stopped renewing
+// their lease, as one that crashed mid-run would. It returns how many were
+// advanced; one failing execution does not stop the others.
+func (s *Service) ResumeDueExecutions(ctx context.Context) (int, error) {
+	now := s.nowFn()
+	due, err := s.executions.ClaimDue(ctx, now, now.Add(s.cfg.ExecutionLease), s.cfg.ResumeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	n := 0
+	for _, exec := range due {
+		if exec.Status == domain.ExecutionStatusRunning {
+			logExecution(&exec, now, "", "reclaimed after its lease expired")
+		}
+		wf, err := s.workflows.GetByID(ctx, exec.WorkflowID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("execution %s: %w", exec.ExecutionID, err))
+			continue
+		}
+		if _, err := s.advance(ctx, wf, exec); err != nil {
+			errs = append(errs, fmt.Errorf("execution %s: %w", exec.ExecutionID, err))
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+func (s *Service) startExecution(ctx context.Context, wf domain.Workflow, eventType string, payload json.RawMessage, testRun bool) (domain.WorkflowExecution, error) {
+	now := s.nowFn()
+	exec := domain.WorkflowExecution{
+		ExecutionID:      nextID("exec"),
+		WorkflowID:       wf.WorkflowID,
+		UserID:           wf.UserID,
+		Status:           domain.ExecutionStatusRunning,
+		TestRun:          testRun,
+		TriggerEventType: eventType,
+		TriggerPayload:   payload,
+		Steps:            make([]domain.StepRun, 0, len(wf.Steps)),
+		Logs:             []domain.ExecutionLog{},
+		StartedAt:        now,
+	}
+	s.renewLease(&exec, now)
+	for _, st := range wf.Steps {
+		exec.Steps = append(exec.Steps, domain.StepRun{StepID: st.StepID, Type: st.Type, Status: domain.StepStatusPending})
+	}
+	if testRun {
+		logExecution(&exec, now, "", "test run: actions are not executed")
+		if trigger, _ := decodePayload(payload); !wf.Filter.Evaluate(map[string]any{"trigger": trigger}) {
+			exec.Status = domain.ExecutionStatusFiltered
+			exec.FinishedAt = &now
+			logExecution(&exec, now, "", "sample payload does not match the workflow filter")
+			if err := s.executions.Create(ctx, exec); err != nil {
+				return domain.WorkflowExecution{}, err
+			}
+			return exec, nil
+		}
+	}
+	if err := s.executions.Create(ctx, exec); err != nil {
+		return domain.WorkflowExecution{}, err
+	}
+	return s.advance(ctx, wf, exec)
+}
+
+// advance runs every step whose dependencies are done, in dependency order,
+// persisting the execution after each one, until the workflow finishes,
+// fails or only waiting steps remain. Each write renews the execution's
+// lease.
+func (s *Service) advance(ctx context.Context, wf domain.Workflow, exec domain.WorkflowExecution) (domain.WorkflowExecution, error) {
+	trigger, _ := decodePayload(exec.TriggerPayload)
+	runs := make(map[string]*domain.StepRun, len(exec.Steps))
+	for i := range exec.Steps {
+		runs[exec.Steps[i].StepID] = &exec.Steps[i]
+	}
+	exec.Status = domain.ExecutionStatusRunning
+	exec.NextRunAt = nil
+	for progressed := true; progressed && exec.Status == domain.ExecutionStatusRunning; {
+		progressed = false
+		for _, st := range wf.Steps {
+			run := runs[st.StepID]
+			if run == nil || run.Terminal() {
+				continue
+			}
+			now := s.nowFn()
+			if run.Status == domain.StepStatusWaiting && run.NextAttemptAt != nil && run.NextAttemptAt.After(now) {
+				continue
+			}
+			ready, skip := dependencyState(st, runs)
+			if !ready {
+				continue
+			}
+			scope := executionScope(exec, trigger)
+			switch {
+			case skip:
+				finishStep(run, domain.StepStatusSkipped, now)
+				logExecution(&exec, now, st.StepID, "skipped: an upstream step was skipped")
+			case run.Status == domain.StepStatusPending && !st.Condition.Evaluate(scope):
+				finishStep(run, domain.StepStatusSkipped, now)
+				logExecution(&exec, now, st.StepID, "skipped: condition not met")
+			case st.Type == domain.StepTypeDelay:
+				s.runDelay(&exec, st, run, now)
+			default:
+				s.runAction(ctx, wf, &exec, st, run, scope)
+			}
+			progressed = true
+			s.renewLease(&exec, s.nowFn())
+			if err := s.executions.Update(ctx, exec); err != nil {
+				return exec, err
+			}
+			if exec.Status != domain.ExecutionStatusRunning {
+				break
+			}
+		}
+	}
+	s.settle(&exec)
+	if err := s.executions.Update(ctx, exec); err != nil {
+		return exec, err
+	}
+	if exec.FinishedAt != nil && !exec.TestRun {
+		s.appendLog(ctx, wf.IntegrationID, "workflow_executed", exec.Status)
+	}
+	return exec, nil
+}
+
+func (s *Service) runDelay(exec *domain.WorkflowExecution, st domain.WorkflowStep, run *domain.StepRun, now time.Time) {
+	switch {
+	case exec.TestRun:
+		finishStep(run, domain.StepStatusSuccess, now)
+		logExecution(exec, now, st.StepID, fmt.Sprintf("delay of %s not waited in test run", st.Delay))
+	case run.Status == domain.StepStatusPending:
+		at := now.Add(st.Delay)
+		run.Status = domain.StepStatusWaiting
+		run.NextAttemptAt = &at
+		logExecution(exec, now, st.StepID, fmt.Sprintf("waiting %s until %s", st.Delay, at.Format(time.RFC3339)))
+	default:
+		run.NextAttemptAt = nil
+		finishStep(run, domain.StepStatusSuccess, now)
+		logExecution(exec, now, st.StepID, "delay elapsed")
+	}
+}
+
+func (s *Service) runAction(ctx context.Context, wf domain.Workflow, exec *domain.WorkflowExecution, st domain.WorkflowStep, run *domain.StepRun, scope map[string]any) {
+	run.Attempts++
+	input := domain.RenderInput(st.Input, scope)
+	if exec.TestRun {
+		rendered := make(map[string]any, len(input))
+		for k, v := range input {
+			rendered[k] = v
+		}
+		run.Output = map[string]any{"dry_run": true, "input": rendered}
+		finishStep(run, domain.StepStatusSuccess, s.nowFn())
+		logExecution(exec, s.nowFn(), st.StepID, "would run "+st.Type)
+		return
+	}
+	var (
+		out map[string]any
+		err error
+	)
+	if executor, ok := s.executors[st.Type]; ok {
+		out, err = executor.Execute(ctx, ports.ActionRequest{
+			ExecutionID:    exec.ExecutionID,
+			WorkflowID:     wf.WorkflowID,
+			StepID:         st.StepID,
+			UserID:         wf.UserID,
+			IntegrationID:  wf.IntegrationID,
+			Attempt:        run.Attempts,
+			IdempotencyKey: exec.ExecutionID + ":" + st.StepID,
+			Input:          input,
+		})
+	} else {
+		err = fmt.Errorf("%w: %s", domain.ErrExecutorMissing, st.Type)
+	}
+	now := s.nowFn()
+	if err == nil {
+		run.Output = out
+		run.Error = ""
+		run.NextAttemptAt = nil
+		finishStep(run, domain.StepStatusSuccess, now)
+		logExecution(exec, now, st.StepID, fmt.Sprintf("%s succeeded on attempt %d", st.Type, run.Attempts))
+		return
+	}
+	run.Error = err.Error()
+	permanent := errors.Is(err, domain.ErrActionRejected) || errors.Is(err, domain.ErrExecutorMissing)
+	if permanent || run.Attempts >= st.Retry.MaxAttempts {
+		run.NextAttemptAt = nil
+		finishStep(run, domain.StepStatusFailed, now)
+		exec.Status = domain.ExecutionStatusFailed
+		exec.Error = fmt.Sprintf("step %s: %s", st.StepID, err)
+		logExecution(exec, now, st.StepID, fmt.Sprintf("attempt %d failed, giving up: %s", run.Attempts, err))
+		return
+	}
+	at := now.Add(st.Retry.Wait(run.Attempts))
+	run.Status = domain.StepStatusWaiting
+	run.NextAttemptAt = &at
+	logExecution(exec, now, st.StepID, fmt.Sprintf("attempt %d failed, retrying at %s: %s", run.Attempts, at.Format(time.RFC3339), err))
+}
+
+// renewLease extends the lease of the execution being advanced.
+func (s *Service) renewLease(exec *domain.WorkflowExecution, now time.Time) {
+	at := now.Add(s.cfg.ExecutionLease)
+	exec.LeaseExpiresAt = &at
+}
+
+// settle derives the execution status once no step can make progress and
+// releases its lease.
+func (s *Service) settle(exec *domain.WorkflowExecution) {
+	now := s.nowFn()
+	exec.LeaseExpiresAt = nil
+	if exec.Status == domain.ExecutionStatusFailed {
+		exec.FinishedAt = &now
+		return
+	}
+	var next *time.Time
+	for i := range exec.Steps {
+		run := exec.Steps[i]
+		if run.Terminal() {
+			continue
+		}
+		if run.NextAttemptAt != nil && (next == nil || run.NextAttemptAt.Before(*next)) {
+			at := *run.NextAttemptAt
+			next = &at
+		}
+	}
+	switch {
+	case next != nil:
+		exec.Status = domain.ExecutionStatusWaiting
+		exec.NextRunAt = next
+	default:
+		exec.Status = domain.ExecutionStatusSuccess
+		exec.FinishedAt = &now
+		logExecution(exec, now, "", "workflow completed")
+	}
+}
+
+// dependencyState reports whether every dependency of st is done and
+// whether any of them was skipped, which skips st as well.
+func dependencyState(st domain.WorkflowStep, runs map[string]*domain.StepRun) (ready, skip bool) {
+	for _, dep := range st.DependsOn {
+		run := runs[dep]
+		if run == nil || !run.Terminal() {
+			return false, false
+		}
+		if run.Status != domain.StepStatusSuccess {
+			skip = true
+		}
+	}
+	return true, skip
+}
+
+// executionScope is what templates and conditions can reference:
+// trigger.*, steps.<id>.output.*, steps.<id>.status and execution.*.
+func executionScope(exec domain.WorkflowExecution, trigger map[string]any) map[string]any {
+	steps := make(map[string]any, len(exec.Steps))
+	for _, run := range exec.Steps {
+		output := map[string]any{}
+		for k, v := range run.Output {
+			output[k] = v
+		}
+		steps[run.StepID] = map[string]any{"status": run.Status, "output": output}
+	}
+	return map[string]any{
+		"trigger":   trigger,
+		"steps":     steps,
+		"execution": map[string]any{"id": exec.ExecutionID, "workflow_id": exec.WorkflowID, "user_id": exec.UserID, "event_type": exec.TriggerEventType},
+	}
+}
+
+func finishStep(run *domain.StepRun, status string, now time.Time) {
+	run.Status = status
+	run.FinishedAt = &now
+}
+
+func logExecution(exec *domain.WorkflowExecution, at time.Time, stepID, message string) {
+	exec.Logs = append(exec.Logs, domain.ExecutionLog{At: at, StepID: stepID, Message: message})
+}
+
+func decodePayload(raw json.RawMessage) (map[string]any, error) {
+	out := map[string]any{}
+	if len(raw) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// primaryAction names a multi-step workflow by its first action step.
+func primaryAction(steps []domain.WorkflowStep) string {
+	for _, st := range steps {
+		if st.Type != domain.StepTypeDelay {
+			return st.Type
+		}
+	}
+	return domain.StepTypeDelay
+}
@@ -96,9 +96,26 @@ func (s *Service) CreateWorkflow(ctx context.Context, actor Actor, in CreateWork
 	in.ActionType = strings.TrimSpace(in.ActionType)
 	in.WorkflowDescription = strings.TrimSpace(in.WorkflowDescription)
 	in.IntegrationID = strings.TrimSpace(in.IntegrationID)
-	if in.WorkflowName == "" || in.TriggerEventType == "" || in.ActionType == "" {
+	if in.WorkflowName == "" || in.TriggerEventType == "" {
 		return domain.Workflow{}, domain.ErrInvalidInput
 	}
+	steps := in.Steps
+	if len(steps) == 0 {
+		if in.ActionType == "" {
+			return domain.Workflow{}, domain.ErrInvalidInput
+		}
+		steps = []domain.WorkflowStep{{StepID: "action", Type: in.ActionType}}
+	}
+	steps, err = domain.ValidateSteps(steps)
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+	if err := domain.ValidateCondition(in.Filter); err != nil {
+		return domain.Workflow{}, err
+	}
+	if in.ActionType == "" {
+		in.ActionType = primaryAction(steps)
+	}
 	if in.IntegrationID != "" {
 		intg, err := s.integrations.GetByID(ctx, in.IntegrationID)
 		if err != nil {
@@ -108,7 +125,7 @@ func (s *Service) CreateWorkflow(ctx context.Context, actor Actor, in CreateWork
 			return domain.Workflow{}, domain.ErrForbidden
 		}
 	}
-	requestHash := hashJSON(map[string]string{"op": "create_workflow", "user_id": userID, "workflow_name": in.WorkflowName, "trigger": in.TriggerEventType, "action": in.ActionType, "integration_id": in.IntegrationID})
+	requestHash := hashJSON(map[string]any{"op": "create_workflow", "user_id": userID, "workflow_name": in.WorkflowName, "trigger": in.TriggerEventType, "action": in.ActionType, "integration_id": in.IntegrationID, "steps": steps, "filter": in.Filter})
 	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.Workflow{}, err
 	} else if ok {
@@ -129,6 +146,8 @@ func (s *Service) CreateWorkflow(ctx context.Context, actor Actor, in CreateWork
 		TriggerEventType:    in.TriggerEventType,
 		ActionType:          in.ActionType,
 		IntegrationID:       in.IntegrationID,
+		Filter:              in.Filter,
+		Steps:               steps,
 		Status:              domain.WorkflowStatusDraft,
 		CreatedAt:           s.nowFn(),
 	}
@@ -175,32 +194,6 @@ func (s *Service) PublishWorkflow(ctx context.Context, actor Actor, workflowID s
 	return row, nil
 }
 
-func (s *Service) TestWorkflow(ctx context.Context, actor Actor, workflowID string) (domain.WorkflowExecution, error) {
-	workflowID = strings.TrimSpace(workflowID)
-	if workflowID == "" {
-		return domain.WorkflowExecution{}, domain.ErrInvalidInput
-	}
-	row, err := s.workflows.GetByID(ctx, workflowID)
-	if err != nil {
-		return domain.WorkflowExecution{}, err
-	}
-	if !canAccessUser(actor, row.UserID) {
-		return domain.WorkflowExecution{}, authorizeError(actor)
-	}
-	exec := domain.WorkflowExecution{
-		ExecutionID: nextID("exec"),
-		WorkflowID:  workflowID,
-		Status:      domain.ExecutionStatusSuccess,
-		TestRun:     true,
-		StartedAt:   s.nowFn(),
-	}
-	if err := s.executions.Create(ctx, exec); err != nil {
-		return domain.WorkflowExecution{}, err
-	}
-	s.appendLog(ctx, row.IntegrationID, "workflow_executed", "success")
-	return exec, nil
-}
-
 func (s *Service) CreateWebhook(ctx context.Context, actor Actor, in CreateWebhookInput) (domain.Webhook, error) {
 	userID, err := s.resolveUser(actor, in.UserID)
 	if err != nil {
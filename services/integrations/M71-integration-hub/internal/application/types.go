@@ -3,12 +3,19 @@ package application
 import (
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
 )
 
 type Config struct {
 	ServiceName    string
 	IdempotencyTTL time.Duration
+	// ResumeBatchSize caps how many due executions one resume pass picks up.
+	ResumeBatchSize int
+	// ExecutionLease is how long a running execution may go without a step
+	// being persisted before another resume pass reclaims it. It must
+	// outlast the slowest single action.
+	ExecutionLease time.Duration
 }
 
 type Actor struct {
@@ -31,6 +38,10 @@ type CreateWorkflowInput struct {
 	TriggerEventType    string
 	ActionType          string
 	IntegrationID       string
+	// Steps defines a multi-step workflow; without steps ActionType becomes
+	// a single step.
+	Steps  []domain.WorkflowStep
+	Filter *domain.Condition
 }
 
 type CreateWebhookInput struct {
@@ -50,6 +61,7 @@ type Service struct {
 	analytics    ports.AnalyticsRepository
 	logs         ports.IntegrationLogRepository
 	idempotency  ports.IdempotencyRepository
+	executors    map[string]ports.ActionExecutor
 	nowFn        func() time.Time
 }
 
@@ -64,6 +76,9 @@ type Dependencies struct {
 	Analytics    ports.AnalyticsRepository
 	Logs         ports.IntegrationLogRepository
 	Idempotency  ports.IdempotencyRepository
+	// Executors run action steps, keyed by their Type(); steps without one
+	// fail.
+	Executors []ports.ActionExecutor
 }
 
 func NewService(deps Dependencies) *Service {
@@ -74,6 +89,16 @@ func NewService(deps Dependencies) *Service {
 	if cfg.IdempotencyTTL <= 0 {
 		cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
+	if cfg.ResumeBatchSize <= 0 {
+		cfg.ResumeBatchSize = 100
+	}
+	if cfg.ExecutionLease <= 0 {
+		cfg.ExecutionLease = 2 * time.Minute
+	}
+	executors := map[string]ports.ActionExecutor{}
+	for _, e := range deps.Executors {
+		executors[e.Type()] = e
+	}
 	return &Service{
 		cfg:          cfg,
 		integrations: deps.Integrations,
@@ -85,6 +110,7 @@ func NewService(deps Dependencies) *Service {
 		analytics:    deps.Analytics,
 		logs:         deps.Logs,
 		idempotency:  deps.Idempotency,
+		executors:    executors,
 		nowFn:        func() time.Time { return time.Now().UTC() },
 	}
 }
@@ -1,5 +1,7 @@
 package contracts
 
+import "encoding/json"
+
 type SuccessResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message,omitempty"`
@@ -30,8 +32,46 @@ type CreateWorkflowRequest struct {
 	WorkflowName        string `json:"workflow_name"`
 	WorkflowDescription string `json:"workflow_description,omitempty"`
 	TriggerEventType    string `json:"trigger_event_type"`
-	ActionType          string `json:"action_type"`
+	ActionType          string `json:"action_type,omitempty"`
 	IntegrationID       string `json:"integration_id,omitempty"`
+	// Steps makes a multi-step workflow; ActionType alone creates one step.
+	Steps  []WorkflowStep `json:"steps,omitempty"`
+	Filter *Condition     `json:"filter,omitempty"`
+}
+
+type Predicate struct {
+	Path  string `json:"path"`
+	Op    string `json:"op"`
+	Value string `json:"value,omitempty"`
+}
+
+type Condition struct {
+	All []Predicate `json:"all,omitempty"`
+	Any []Predicate `json:"any,omitempty"`
+}
+
+type RetryPolicy struct {
+	MaxAttempts    int `json:"max_attempts,omitempty"`
+	BackoffSeconds int `json:"backoff_seconds,omitempty"`
+}
+
+type WorkflowStep struct {
+	StepID       string            `json:"step_id"`
+	Type         string            `json:"type"`
+	DependsOn    []string          `json:"depends_on,omitempty"`
+	Condition    *Condition        `json:"condition,omitempty"`
+	Input        map[string]string `json:"input,omitempty"`
+	DelaySeconds int               `json:"delay_seconds,omitempty"`
+	Retry        *RetryPolicy      `json:"retry,omitempty"`
+}
+
+type TestWorkflowRequest struct {
+	SamplePayload json.RawMessage `json:"sample_payload,omitempty"`
+}
+
+type TriggerEventRequest struct {
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
 }
 
 type CreateWebhookRequest struct {
@@ -50,23 +90,54 @@ type IntegrationResponse struct {
 }
 
 type WorkflowResponse struct {
-	WorkflowID          string `json:"workflow_id"`
-	UserID              string `json:"user_id"`
-	WorkflowName        string `json:"workflow_name"`
-	WorkflowDescription string `json:"workflow_description,omitempty"`
-	TriggerEventType    string `json:"trigger_event_type"`
-	ActionType          string `json:"action_type"`
-	IntegrationID       string `json:"integration_id,omitempty"`
-	Status              string `json:"status"`
-	CreatedAt           string `json:"created_at"`
+	WorkflowID          string         `json:"workflow_id"`
+	UserID              string         `json:"user_id"`
+	WorkflowName        string         `json:"workflow_name"`
+	WorkflowDescription string         `json:"workflow_description,omitempty"`
+	TriggerEventType    string         `json:"trigger_event_type"`
+	ActionType          string         `json:"action_type"`
+	IntegrationID       string         `json:"integration_id,omitempty"`
+	Filter              *Condition     `json:"filter,omitempty"`
+	Steps               []WorkflowStep `json:"steps"`
+	Status              string         `json:"status"`
+	CreatedAt           string         `json:"created_at"`
 }
 
 type WorkflowExecutionResponse struct {
-	ExecutionID string `json:"execution_id"`
-	WorkflowID  string `json:"workflow_id"`
-	Status      string `json:"status"`
-	TestRun     bool   `json:"test_run"`
-	StartedAt   string `json:"started_at"`
+	ExecutionID      string                 `json:"execution_id"`
+	WorkflowID       string                 `json:"workflow_id"`
+	UserID           string                 `json:"user_id"`
+	Status           string                 `json:"status"`
+	TestRun          bool                   `json:"test_run"`
+	TriggerEventType string                 `json:"trigger_event_type,omitempty"`
+	TriggerPayload   json.RawMessage        `json:"trigger_payload,omitempty"`
+	Steps            []StepRunResponse      `json:"steps"`
+	Logs             []ExecutionLogResponse `json:"logs"`
+	Error            string                 `json:"error,omitempty"`
+	NextRunAt        string                 `json:"next_run_at,omitempty"`
+	StartedAt        string                 `json:"started_at"`
+	FinishedAt       string                 `json:"finished_at,omitempty"`
+}
+
+type StepRunResponse struct {
+	StepID        string         `json:"step_id"`
+	Type          string         `json:"type"`
+	Status        string         `json:"status"`
+	Attempts      int            `json:"attempts"`
+	Output        map[string]any `json:"output,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	NextAttemptAt string         `json:"next_attempt_at,omitempty"`
+	FinishedAt    string         `json:"finished_at,omitempty"`
+}
+
+type ExecutionLogResponse struct {
+	At      string `json:"at"`
+	StepID  string `json:"step_id,omitempty"`
+	Message string `json:"message"`
+}
+
+type WorkflowExecutionListResponse struct {
+	Executions []WorkflowExecutionResponse `json:"executions"`
 }
 
 type WebhookResponse struct {
@@ -10,4 +10,11 @@ var (
 	ErrConflict            = errors.New("conflict")
 	ErrIdempotencyRequired = errors.New("idempotency_key_required")
 	ErrIdempotencyConflict = errors.New("idempotency_conflict")
+	// ErrActionRejected marks an action failure retrying cannot fix, such
+	// as a 4xx from the target.
+	ErrActionRejected = errors.New("action_rejected")
+	// ErrActionUnavailable is a transient action failure that is retried.
+	ErrActionUnavailable = errors.New("action_unavailable")
+	// ErrExecutorMissing means no executor is configured for a step type.
+	ErrExecutorMissing = errors.New("executor_missing")
 )
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 const (
 	IntegrationStatusConnected = "connected"
@@ -10,7 +13,17 @@ const (
 	WorkflowStatusDraft     = "draft"
 	WorkflowStatusPublished = "published"
 
-	ExecutionStatusSuccess = "success"
+	ExecutionStatusRunning  = "running"
+	ExecutionStatusWaiting  = "waiting"
+	ExecutionStatusSuccess  = "success"
+	ExecutionStatusFailed   = "failed"
+	ExecutionStatusFiltered = "filtered"
+
+	StepStatusPending = "pending"
+	StepStatusWaiting = "waiting"
+	StepStatusSuccess = "success"
+	StepStatusFailed  = "failed"
+	StepStatusSkipped = "skipped"
 )
 
 type Integration struct {
@@ -31,23 +44,61 @@ type APICredential struct {
 }
 
 type Workflow struct {
-	WorkflowID          string    `json:"workflow_id"`
-	UserID              string    `json:"user_id"`
-	WorkflowName        string    `json:"workflow_name"`
-	WorkflowDescription string    `json:"workflow_description"`
-	TriggerEventType    string    `json:"trigger_event_type"`
-	ActionType          string    `json:"action_type"`
-	IntegrationID       string    `json:"integration_id,omitempty"`
-	Status              string    `json:"status"`
-	CreatedAt           time.Time `json:"created_at"`
+	WorkflowID          string `json:"workflow_id"`
+	UserID              string `json:"user_id"`
+	WorkflowName        string `json:"workflow_name"`
+	WorkflowDescription string `json:"workflow_description"`
+	TriggerEventType    string `json:"trigger_event_type"`
+	ActionType          string `json:"action_type"`
+	IntegrationID       string `json:"integration_id,omitempty"`
+	// Filter must hold on the trigger payload for an execution to start.
+	Filter    *Condition     `json:"filter,omitempty"`
+	Steps     []WorkflowStep `json:"steps"`
+	Status    string         `json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
 }
 
+// WorkflowExecution is one run of a workflow. Step runs are persisted after
+// every step so a waiting or failed execution resumes where it stopped.
 type WorkflowExecution struct {
-	ExecutionID string    `json:"execution_id"`
-	WorkflowID  string    `json:"workflow_id"`
-	Status      string    `json:"status"`
-	TestRun     bool      `json:"test_run"`
-	StartedAt   time.Time `json:"started_at"`
+	ExecutionID      string          `json:"execution_id"`
+	WorkflowID       string          `json:"workflow_id"`
+	UserID           string          `json:"user_id"`
+	Status           string          `json:"status"`
+	TestRun          bool            `json:"test_run"`
+	TriggerEventType string          `json:"trigger_event_type"`
+	TriggerPayload   json.RawMessage `json:"trigger_payload"`
+	Steps            []StepRun       `json:"steps"`
+	Logs             []ExecutionLog  `json:"logs"`
+	Error            string          `json:"error,omitempty"`
+	NextRunAt        *time.Time      `json:"next_run_at,omitempty"`
+	StartedAt        time.Time       `json:"started_at"`
+	FinishedAt       *time.Time      `json:"finished_at,omitempty"`
+	// LeaseExpiresAt is renewed by the worker advancing a running
+	// execution; once it passes, the worker is presumed dead and another
+	// may claim the execution.
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+}
+
+type StepRun struct {
+	StepID        string         `json:"step_id"`
+	Type          string         `json:"type"`
+	Status        string         `json:"status"`
+	Attempts      int            `json:"attempts"`
+	Output        map[string]any `json:"output,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	NextAttemptAt *time.Time     `json:"next_attempt_at,omitempty"`
+	FinishedAt    *time.Time     `json:"finished_at,omitempty"`
+}
+
+func (r StepRun) Terminal() bool {
+	return r.Status == StepStatusSuccess || r.Status == StepStatusFailed || r.Status == StepStatusSkipped
+}
+
+type ExecutionLog struct {
+	At      time.Time `json:"at"`
+	StepID  string    `json:"step_id,omitempty"`
+	Message string    `json:"message"`
 }
 
 type Webhook struct {
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Predicate operators.
+const (
+	OpEquals    = "eq"
+	OpNotEquals = "neq"
+	OpGreater   = "gt"
+	OpGreaterEq = "gte"
+	OpLess      = "lt"
+	OpLessEq    = "lte"
+	OpContains  = "contains"
+	OpExists    = "exists"
+	OpIn        = "in"
+	OpNotExists = "not_exists"
+)
+
+var predicateOps = map[string]bool{OpEquals: true, OpNotEquals: true, OpGreater: true, OpGreaterEq: true, OpLess: true, OpLessEq: true, OpContains: true, OpExists: true, OpIn: true, OpNotExists: true}
+
+var templatePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// Predicate compares the value at Path with Value. Numbers compare
+// numerically when both sides parse as numbers; OpIn takes a comma
+// separated list.
+type Predicate struct {
+	Path  string `json:"path"`
+	Op    string `json:"op"`
+	Value string `json:"value,omitempty"`
+}
+
+// Condition holds when every All predicate and at least one Any predicate
+// (if there are any) match. A nil condition always holds.
+type Condition struct {
+	All []Predicate `json:"all,omitempty"`
+	Any []Predicate `json:"any,omitempty"`
+}
+
+func (c *Condition) validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, p := range append(append([]Predicate(nil), c.All...), c.Any...) {
+		if strings.TrimSpace(p.Path) == "" {
+			return fmt.Errorf("predicate without path")
+		}
+		if !predicateOps[p.Op] {
+			return fmt.Errorf("unknown operator %q", p.Op)
+		}
+	}
+	return nil
+}
+
+// ValidateCondition reports a malformed trigger filter.
+func ValidateCondition(c *Condition) error {
+	if err := c.validate(); err != nil {
+		return fmt.Errorf("%w: filter: %s", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+func (c *Condition) Evaluate(scope map[string]any) bool {
+	if c == nil {
+		return true
+	}
+	for _, p := range c.All {
+		if !p.Evaluate(scope) {
+			return false
+		}
+	}
+	if len(c.Any) == 0 {
+		return true
+	}
+	for _, p := range c.Any {
+		if p.Evaluate(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Predicate) Evaluate(scope map[string]any) bool {
+	v, ok := Lookup(scope, p.Path)
+	switch p.Op {
+	case OpExists:
+		return ok && v != nil
+	case OpNotExists:
+		return !ok || v == nil
+	}
+	if !ok {
+		return p.Op == OpNotEquals
+	}
+	got := stringify(v)
+	switch p.Op {
+	case OpEquals:
+		return compare(got, p.Value) == 0
+	case OpNotEquals:
+		return compare(got, p.Value) != 0
+	case OpGreater:
+		return numeric(got, p.Value) && compare(got, p.Value) > 0
+	case OpGreaterEq:
+		return numeric(got, p.Value) && compare(got, p.Value) >= 0
+	case OpLess:
+		return numeric(got, p.Value) && compare(got, p.Value) < 0
+	case OpLessEq:
+		return numeric(got, p.Value) && compare(got, p.Value) <= 0
+	case OpContains:
+		if list, isList := v.([]any); isList {
+			for _, item := range list {
+				if stringify(item) == p.Value {
+					return true
+				}
+			}
+			return false
+		}
+		return strings.Contains(got, p.Value)
+	case OpIn:
+		for _, option := range strings.Split(p.Value, ",") {
+			if compare(got, strings.TrimSpace(option)) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Lookup resolves a dotted path ("trigger.user.id", "steps.fetch.items.0")
+// through nested maps and lists.
+func Lookup(scope map[string]any, path string) (any, bool) {
+	var cur any = scope
+	for _, part := range strings.Split(strings.TrimSpace(path), ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Render replaces every {{path}} in tmpl with the value found in scope.
+// Objects and lists render as JSON; missing paths render empty.
+func Render(tmpl string, scope map[string]any) string {
+	return templatePattern.ReplaceAllStringFunc(tmpl, func(m string) string {
+		v, ok := Lookup(scope, templatePattern.FindStringSubmatch(m)[1])
+		if !ok || v == nil {
+			return ""
+		}
+		return stringify(v)
+	})
+}
+
+// RenderInput renders every value of a step input.
+func RenderInput(input map[string]string, scope map[string]any) map[string]string {
+	out := make(map[string]string, len(input))
+	for k, v := range input {
+		out[k] = Render(v, scope)
+	}
+	return out
+}
+
+func stringify(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	case nil:
+		return ""
+	case map[string]any, []any:
+		raw, _ := json.Marshal(t)
+		return string(raw)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func numeric(a, b string) bool {
+	_, errA := strconv.ParseFloat(a, 64)
+	_, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil
+}
+
+func compare(a, b string) int {
+	if numeric(a, b) {
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
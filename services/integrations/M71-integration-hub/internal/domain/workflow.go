@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Built-in step types. Every type except StepTypeDelay is an action run by
+// the executor registered for it.
+const (
+	StepTypeHTTPRequest  = "http_request"
+	StepTypeChatPost     = "chat_post"
+	StepTypeNotification = "notification"
+	StepTypeWebhook      = "m72_webhook"
+	StepTypeDelay        = "delay"
+)
+
+var stepTypes = map[string]bool{StepTypeHTTPRequest: true, StepTypeChatPost: true, StepTypeNotification: true, StepTypeWebhook: true, StepTypeDelay: true}
+
+// legacyActionTypes maps the action names single-action workflows were
+// created with onto built-in step types.
+var legacyActionTypes = map[string]string{
+	"send_slack_message": StepTypeChatPost,
+	"send_chat_message":  StepTypeChatPost,
+	"send_notification":  StepTypeNotification,
+	"send_webhook":       StepTypeWebhook,
+	"webhook":            StepTypeWebhook,
+	"http":               StepTypeHTTPRequest,
+}
+
+var stepIDPattern = regexp.MustCompile(`^[a-z0-9_][a-z0-9_-]{0,63}$`)
+
+// NormalizeStepType resolves legacy action names; ok is false for types no
+// executor can run.
+func NormalizeStepType(v string) (string, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if alias, ok := legacyActionTypes[v]; ok {
+		v = alias
+	}
+	return v, stepTypes[v]
+}
+
+// RetryPolicy bounds how often a failing action is attempted. Attempt n
+// waits Backoff·2^(n-1) after the previous one.
+type RetryPolicy struct {
+	MaxAttempts int           `json:"max_attempts"`
+	Backoff     time.Duration `json:"backoff"`
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, Backoff: 30 * time.Second}
+}
+
+// Wait is the pause before the attempt following attempt n.
+func (p RetryPolicy) Wait(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 10 {
+		attempt = 10
+	}
+	return p.Backoff * time.Duration(1<<(attempt-1))
+}
+
+// WorkflowStep is one node of a workflow DAG. Input values are templates
+// rendered against the trigger payload and earlier step outputs before the
+// action runs; a step whose Condition is false is skipped together with
+// everything downstream of it.
+type WorkflowStep struct {
+	StepID    string            `json:"step_id"`
+	Type      string            `json:"type"`
+	DependsOn []string          `json:"depends_on,omitempty"`
+	Condition *Condition        `json:"condition,omitempty"`
+	Input     map[string]string `json:"input,omitempty"`
+	Delay     time.Duration     `json:"delay,omitempty"`
+	Retry     RetryPolicy       `json:"retry"`
+}
+
+// ValidateSteps checks step ids, types, dependencies and delays and returns
+// the steps in dependency order with retry defaults filled in.
+func ValidateSteps(steps []WorkflowStep) ([]WorkflowStep, error) {
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%w: workflow has no steps", ErrInvalidInput)
+	}
+	byID := make(map[string]WorkflowStep, len(steps))
+	order := make([]string, 0, len(steps))
+	for _, st := range steps {
+		st.StepID = strings.TrimSpace(st.StepID)
+		if !stepIDPattern.MatchString(st.StepID) {
+			return nil, fmt.Errorf("%w: invalid step id %q", ErrInvalidInput, st.StepID)
+		}
+		if _, dup := byID[st.StepID]; dup {
+			return nil, fmt.Errorf("%w: duplicate step id %q", ErrInvalidInput, st.StepID)
+		}
+		typ, ok := NormalizeStepType(st.Type)
+		if !ok {
+			return nil, fmt.Errorf("%w: step %q has unknown type %q", ErrInvalidInput, st.StepID, st.Type)
+		}
+		st.Type = typ
+		if typ == StepTypeDelay && st.Delay <= 0 {
+			return nil, fmt.Errorf("%w: delay step %q needs a positive delay", ErrInvalidInput, st.StepID)
+		}
+		if st.Retry.MaxAttempts <= 0 {
+			st.Retry.MaxAttempts = DefaultRetryPolicy().MaxAttempts
+		}
+		if st.Retry.Backoff <= 0 {
+			st.Retry.Backoff = DefaultRetryPolicy().Backoff
+		}
+		if err := st.Condition.validate(); err != nil {
+			return nil, fmt.Errorf("%w: step %q: %s", ErrInvalidInput, st.StepID, err)
+		}
+		byID[st.StepID] = st
+		order = append(order, st.StepID)
+	}
+	for _, st := range byID {
+		for _, dep := range st.DependsOn {
+			if _, ok := byID[dep]; !ok {
+				return nil, fmt.Errorf("%w: step %q depends on unknown step %q", ErrInvalidInput, st.StepID, dep)
+			}
+		}
+	}
+
+	// Kahn's algorithm, keeping the submitted order among ready steps so the
+	// result is stable.
+	indegree := make(map[string]int, len(byID))
+	for _, id := range order {
+		indegree[id] = len(byID[id].DependsOn)
+	}
+	out := make([]WorkflowStep, 0, len(byID))
+	done := make(map[string]bool, len(byID))
+	for len(out) < len(order) {
+		progressed := false
+		for _, id := range order {
+			if done[id] || indegree[id] > 0 {
+				continue
+			}
+			done[id] = true
+			progressed = true
+			out = append(out, byID[id])
+			for _, other := range order {
+				for _, dep := range byID[other].DependsOn {
+					if dep == id {
+						indegree[other]--
+					}
+				}
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("%w: workflow steps contain a cycle", ErrInvalidInput)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,23 @@
+package ports
+
+import "context"
+
+// ActionRequest is one attempt of a workflow action step. IdempotencyKey is
+// stable across attempts of the same step so targets can drop duplicates.
+type ActionRequest struct {
+	ExecutionID    string
+	WorkflowID     string
+	StepID         string
+	UserID         string
+	IntegrationID  string
+	Attempt        int
+	IdempotencyKey string
+	Input          map[string]string
+}
+
+// ActionExecutor runs one built-in action type. Errors wrapping
+// domain.ErrActionRejected are not retried; any other error is.
+type ActionExecutor interface {
+	Type() string
+	Execute(ctx context.Context, req ActionRequest) (map[string]any, error)
+}
@@ -20,10 +20,19 @@ type WorkflowRepository interface {
 	Create(ctx context.Context, row domain.Workflow) error
 	GetByID(ctx context.Context, workflowID string) (domain.Workflow, error)
 	Update(ctx context.Context, row domain.Workflow) error
+	ListPublishedByTrigger(ctx context.Context, eventType string) ([]domain.Workflow, error)
 }
 
 type WorkflowExecutionRepository interface {
 	Create(ctx context.Context, row domain.WorkflowExecution) error
+	GetByID(ctx context.Context, executionID string) (domain.WorkflowExecution, error)
+	Update(ctx context.Context, row domain.WorkflowExecution) error
+	ListByWorkflowID(ctx context.Context, workflowID string, limit int) ([]domain.WorkflowExecution, error)
+	// ClaimDue leases, until leaseUntil, waiting executions whose NextRunAt
+	// has passed and running executions whose lease has expired, and
+	// returns them oldest first. Executions another worker holds a live
+	// lease on are not returned.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WorkflowExecution, error)
 }
 
 type WebhookRepository interface {
@@ -2,11 +2,20 @@ package unit
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/adapters/actions"
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M71-integration-hub/internal/ports"
 )
 
 func TestAuthorizeIntegrationAndIdempotentReplay(t *testing.T) {
@@ -82,7 +91,7 @@ func TestWorkflowPublishAndTest(t *testing.T) {
 	if published.Status != domain.WorkflowStatusPublished {
 		t.Fatalf("unexpected published workflow: %+v", published)
 	}
-	exec, err := svc.TestWorkflow(context.Background(), application.Actor{SubjectID: "user-2", Role: "user"}, workflow.WorkflowID)
+	exec, err := svc.TestWorkflow(context.Background(), application.Actor{SubjectID: "user-2", Role: "user"}, workflow.WorkflowID, nil)
 	if err != nil {
 		t.Fatalf("test workflow: %v", err)
 	}
@@ -126,3 +135,238 @@ func TestWebhookAndChatPostMessage(t *testing.T) {
 		t.Fatalf("unexpected message output")
 	}
 }
+
+type scriptedExecutor struct {
+	typ      string
+	failures int
+	calls    []ports.ActionRequest
+}
+
+func (e *scriptedExecutor) Type() string { return e.typ }
+
+func (e *scriptedExecutor) Execute(_ context.Context, req ports.ActionRequest) (map[string]any, error) {
+	e.calls = append(e.calls, req)
+	if len(e.calls) <= e.failures {
+		return nil, domain.ErrActionUnavailable
+	}
+	return map[string]any{"message_id": "msg-" + req.StepID}, nil
+}
+
+func newWorkflowService(repos *postgres.Repositories, executors ...ports.ActionExecutor) *application.Service {
+	return application.NewService(application.Dependencies{
+		Integrations: repos.Integrations,
+		Workflows:    repos.Workflows,
+		Executions:   repos.Executions,
+		Logs:         repos.Logs,
+		Idempotency:  repos.Idempotency,
+		Executors:    executors,
+	})
+}
+
+func publishWorkflow(t *testing.T, svc *application.Service, in application.CreateWorkflowInput) domain.Workflow {
+	t.Helper()
+	wf, err := svc.CreateWorkflow(context.Background(), application.Actor{SubjectID: "user-4", Role: "user", IdempotencyKey: "idem-create-" + in.WorkflowName}, in)
+	if err != nil {
+		t.Fatalf("create workflow: %v", err)
+	}
+	if _, err := svc.PublishWorkflow(context.Background(), application.Actor{SubjectID: "user-4", Role: "user", IdempotencyKey: "idem-publish-" + in.WorkflowName}, wf.WorkflowID); err != nil {
+		t.Fatalf("publish workflow: %v", err)
+	}
+	return wf
+}
+
+func TestWorkflowDAGFiltersBranchesAndTemplates(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(raw))
+		_, _ = w.Write([]byte(`{"ticket":"T-9"}`))
+	}))
+	defer srv.Close()
+	repos := postgres.NewRepositories()
+	chat := &scriptedExecutor{typ: domain.StepTypeChatPost}
+	svc := newWorkflowService(repos, actions.NewHTTPRequest(time.Second, "127.0.0.1"), chat)
+	publishWorkflow(t, svc, application.CreateWorkflowInput{
+		WorkflowName:     "big-payouts",
+		TriggerEventType: "payout.paid",
+		Filter:           &domain.Condition{All: []domain.Predicate{{Path: "trigger.currency", Op: domain.OpEquals, Value: "USD"}}},
+		Steps: []domain.WorkflowStep{
+			{StepID: "announce", Type: "chat_post", DependsOn: []string{"ticket"}, Input: map[string]string{"text": "{{trigger.user.name}} got {{trigger.amount}} ({{steps.ticket.output.body.ticket}})"}},
+			{StepID: "ticket", Type: "http_request", Input: map[string]string{"url": srv.URL, "body": `{"user":"{{trigger.user.id}}"}`}},
+			{StepID: "escalate", Type: "chat_post", DependsOn: []string{"ticket"}, Condition: &domain.Condition{All: []domain.Predicate{{Path: "trigger.amount", Op: domain.OpGreater, Value: "1000"}}}},
+			{StepID: "follow_up", Type: "chat_post", DependsOn: []string{"escalate"}},
+		},
+	})
+	service := application.Actor{SubjectID: "m05", Role: "service"}
+	execs, err := svc.TriggerEvent(context.Background(), service, "payout.paid", json.RawMessage(`{"currency":"USD","amount":250,"user":{"id":"u-7","name":"Ada"}}`))
+	if err != nil || len(execs) != 1 {
+		t.Fatalf("trigger: %v (%d executions)", err, len(execs))
+	}
+	exec := execs[0]
+	if exec.Status != domain.ExecutionStatusSuccess {
+		t.Fatalf("expected success, got %s: %+v", exec.Status, exec.Logs)
+	}
+	status := map[string]string{}
+	for _, run := range exec.Steps {
+		status[run.StepID] = run.Status
+	}
+	if status["ticket"] != domain.StepStatusSuccess || status["announce"] != domain.StepStatusSuccess || status["escalate"] != domain.StepStatusSkipped || status["follow_up"] != domain.StepStatusSkipped {
+		t.Fatalf("unexpected step statuses: %v", status)
+	}
+	if len(bodies) != 1 || bodies[0] != `{"user":"u-7"}` {
+		t.Fatalf("unexpected http bodies: %v", bodies)
+	}
+	if len(chat.calls) != 1 || chat.calls[0].Input["text"] != "Ada got 250 (T-9)" {
+		t.Fatalf("unexpected chat calls: %+v", chat.calls)
+	}
+	if execs, _ := svc.TriggerEvent(context.Background(), service, "payout.paid", json.RawMessage(`{"currency":"EUR"}`)); len(execs) != 0 {
+		t.Fatalf("expected filter to drop EUR payout, got %d executions", len(execs))
+	}
+	if _, err := svc.CreateWorkflow(context.Background(), application.Actor{SubjectID: "user-4", Role: "user", IdempotencyKey: "idem-cycle"}, application.CreateWorkflowInput{
+		WorkflowName:     "cycle",
+		TriggerEventType: "payout.paid",
+		Steps:            []domain.WorkflowStep{{StepID: "a", Type: "chat_post", DependsOn: []string{"b"}}, {StepID: "b", Type: "chat_post", DependsOn: []string{"a"}}},
+	}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected cyclic workflow rejected, got %v", err)
+	}
+}
+
+func TestWorkflowRetriesAndDelaysResume(t *testing.T) {
+	repos := postgres.NewRepositories()
+	chat := &scriptedExecutor{typ: domain.StepTypeChatPost, failures: 1}
+	svc := newWorkflowService(repos, chat)
+	publishWorkflow(t, svc, application.CreateWorkflowInput{
+		WorkflowName:     "welcome",
+		TriggerEventType: "user.registered",
+		Steps: []domain.WorkflowStep{
+			{StepID: "wait", Type: "delay", Delay: 5 * time.Millisecond},
+			{StepID: "greet", Type: "chat_post", DependsOn: []string{"wait"}, Retry: domain.RetryPolicy{MaxAttempts: 2, Backoff: 5 * time.Millisecond}},
+		},
+	})
+	execs, err := svc.TriggerEvent(context.Background(), application.Actor{SubjectID: "m01", Role: "service"}, "user.registered", json.RawMessage(`{"user_id":"u-1"}`))
+	if err != nil || len(execs) != 1 {
+		t.Fatalf("trigger: %v", err)
+	}
+	if execs[0].Status != domain.ExecutionStatusWaiting || execs[0].NextRunAt == nil {
+		t.Fatalf("expected execution waiting on delay, got %+v", execs[0])
+	}
+	for i := 0; i < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if _, err := svc.ResumeDueExecutions(context.Background()); err != nil {
+			t.Fatalf("resume: %v", err)
+		}
+	}
+	exec, err := svc.GetExecution(context.Background(), application.Actor{SubjectID: "user-4", Role: "user"}, execs[0].ExecutionID)
+	if err != nil {
+		t.Fatalf("get execution: %v", err)
+	}
+	if exec.Status != domain.ExecutionStatusSuccess || exec.Steps[1].Attempts != 2 || len(chat.calls) != 2 {
+		t.Fatalf("expected success on second attempt, got %s after %d calls: %+v", exec.Status, len(chat.calls), exec.Logs)
+	}
+	if chat.calls[0].IdempotencyKey != chat.calls[1].IdempotencyKey {
+		t.Fatalf("expected a stable idempotency key across attempts")
+	}
+}
+
+func TestCrashedExecutionIsReclaimedAfterItsLeaseExpires(t *testing.T) {
+	repos := postgres.NewRepositories()
+	chat := &scriptedExecutor{typ: domain.StepTypeChatPost}
+	svc := newWorkflowService(repos, chat)
+	publishWorkflow(t, svc, application.CreateWorkflowInput{
+		WorkflowName:     "welcome",
+		TriggerEventType: "user.registered",
+		Steps:            []domain.WorkflowStep{{StepID: "greet", Type: "chat_post"}},
+	})
+	execs, err := svc.TriggerEvent(context.Background(), application.Actor{SubjectID: "m01", Role: "service"}, "user.registered", json.RawMessage(`{"user_id":"u-1"}`))
+	if err != nil || len(execs) != 1 {
+		t.Fatalf("trigger: %v", err)
+	}
+	// Put the execution back the way a worker that died mid-step leaves it:
+	// running, with its step still pending.
+	stuck := execs[0]
+	stuck.Status = domain.ExecutionStatusRunning
+	stuck.FinishedAt = nil
+	stuck.Steps[0] = domain.StepRun{StepID: "greet", Type: "chat_post", Status: domain.StepStatusPending}
+	live := time.Now().Add(time.Minute)
+	stuck.LeaseExpiresAt = &live
+	if err := repos.Executions.Update(context.Background(), stuck); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if n, err := svc.ResumeDueExecutions(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected a live lease to hold the execution, resumed %d (%v)", n, err)
+	}
+
+	expired := time.Now().Add(-time.Second)
+	stuck.LeaseExpiresAt = &expired
+	if err := repos.Executions.Update(context.Background(), stuck); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if n, err := svc.ResumeDueExecutions(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected the expired execution to be reclaimed, resumed %d (%v)", n, err)
+	}
+	exec, err := svc.GetExecution(context.Background(), application.Actor{SubjectID: "user-4", Role: "user"}, stuck.ExecutionID)
+	if err != nil {
+		t.Fatalf("get execution: %v", err)
+	}
+	if exec.Status != domain.ExecutionStatusSuccess || exec.LeaseExpiresAt != nil || len(chat.calls) != 2 {
+		t.Fatalf("expected the reclaimed execution to finish and release its lease, got %s after %d calls: %+v", exec.Status, len(chat.calls), exec.Logs)
+	}
+	if chat.calls[0].IdempotencyKey != chat.calls[1].IdempotencyKey {
+		t.Fatalf("expected the rerun step to reuse its idempotency key")
+	}
+}
+
+func TestWorkflowTestRunHasNoSideEffects(t *testing.T) {
+	repos := postgres.NewRepositories()
+	chat := &scriptedExecutor{typ: domain.StepTypeChatPost}
+	svc := newWorkflowService(repos, chat)
+	wf := publishWorkflow(t, svc, application.CreateWorkflowInput{
+		WorkflowName:     "dry",
+		TriggerEventType: "submission.approved",
+		Steps: []domain.WorkflowStep{
+			{StepID: "wait", Type: "delay", Delay: time.Hour},
+			{StepID: "notify", Type: "chat_post", DependsOn: []string{"wait"}, Input: map[string]string{"text": "approved {{trigger.submission_id}}"}},
+		},
+	})
+	exec, err := svc.TestWorkflow(context.Background(), application.Actor{SubjectID: "user-4", Role: "user"}, wf.WorkflowID, json.RawMessage(`{"submission_id":"s-1"}`))
+	if err != nil {
+		t.Fatalf("test workflow: %v", err)
+	}
+	if !exec.TestRun || exec.Status != domain.ExecutionStatusSuccess || len(chat.calls) != 0 {
+		t.Fatalf("expected dry run without calls, got %s with %d calls", exec.Status, len(chat.calls))
+	}
+	input, _ := exec.Steps[1].Output["input"].(map[string]any)
+	if input["text"] != "approved s-1" {
+		t.Fatalf("expected rendered input in dry run output, got %+v", exec.Steps[1].Output)
+	}
+}
+
+func TestHTTPRequestRefusesInternalAddresses(t *testing.T) {
+	hits := 0
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer internal.Close()
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusFound)
+	}))
+	defer redirector.Close()
+	_, port, _ := strings.Cut(strings.TrimPrefix(redirector.URL, "http://"), ":")
+
+	for name, tc := range map[string]struct {
+		executor ports.ActionExecutor
+		url      string
+	}{
+		"loopback": {actions.NewHTTPRequest(time.Second), internal.URL},
+		"metadata": {actions.NewHTTPRequest(time.Second), "http://169.254.169.254/latest/meta-data/"},
+		"redirect": {actions.NewHTTPRequest(time.Second, "localhost"), "http://localhost:" + port + "/"},
+	} {
+		_, err := tc.executor.Execute(context.Background(), ports.ActionRequest{Input: map[string]string{"url": tc.url, "method": "GET"}})
+		if !errors.Is(err, domain.ErrActionRejected) {
+			t.Fatalf("%s: expected rejection, got %v", name, err)
+		}
+	}
+	if hits != 0 {
+		t.Fatalf("internal server was reached %d times", hits)
+	}
+}
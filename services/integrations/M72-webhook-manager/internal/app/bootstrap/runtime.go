@@ -2,11 +2,13 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	stdhttp "net/http"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	transporthttp "github.com/viralforge/mesh/services/integrations/M72-webhook-manager/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M72-webhook-manager/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M72-webhook-manager/internal/application"
@@ -14,6 +16,7 @@ import (
 
 type Runtime struct {
 	httpServer *stdhttp.Server
+	telemetry  *observability.Telemetry
 }
 
 func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
@@ -21,6 +24,10 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	if err != nil {
 		return nil, err
 	}
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, cfg.Version))
+	if err != nil {
+		return nil, err
+	}
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
 		Config: application.Config{
@@ -37,10 +44,10 @@ func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	router := transporthttp.NewRouter(handler)
 	s := &stdhttp.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler:           router,
+		Handler:           observability.HTTPMiddleware(cfg.ServiceID)(router),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
-	return &Runtime{httpServer: s}, nil
+	return &Runtime{httpServer: s, telemetry: telemetry}, nil
 }
 
 func (r *Runtime) Run(ctx context.Context) error {
@@ -57,5 +64,5 @@ func (r *Runtime) Run(ctx context.Context) error {
 	}
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return r.httpServer.Shutdown(shutdownCtx)
+	return errors.Join(r.httpServer.Shutdown(shutdownCtx), r.telemetry.Shutdown(shutdownCtx))
 }
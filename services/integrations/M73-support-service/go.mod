@@ -1,3 +1,7 @@
 module github.com/viralforge/mesh/services/integrations/M73-support-service
 
 go 1.23
+
+require github.com/viralforge/mesh/platform v0.0.0
+
+replace github.com/viralforge/mesh/platform => ../../../platform
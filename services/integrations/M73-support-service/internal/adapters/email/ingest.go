@@ -0,0 +1,152 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
+
+// intakeActor is the identity mail pulled in by the service itself acts as.
+var intakeActor = application.Actor{SubjectID: "email-intake", Role: "system"}
+
+// Ingester parses raw messages and hands them to the support service.
+type Ingester struct {
+	service *application.Service
+}
+
+func NewIngester(service *application.Service) *Ingester {
+	return &Ingester{service: service}
+}
+
+func (i *Ingester) Ingest(ctx context.Context, raw []byte) (application.InboundEmailResult, error) {
+	msg, err := Parse(raw)
+	if err != nil {
+		return application.InboundEmailResult{}, err
+	}
+	return i.service.ProcessInboundEmail(ctx, intakeActor, msg)
+}
+
+// Permanent reports whether retrying err can never succeed.
+func Permanent(err error) bool {
+	return errors.Is(err, ErrMalformedMessage) || errors.Is(err, domain.ErrInvalidInput)
+}
+
+// MaildirPoller ingests messages delivered to a Maildir. Processed messages
+// move to cur/ flagged seen; messages that can never be processed, including
+// ones larger than MaxBytes, move to cur/ flagged for review. Transient
+// failures stay in new/ for the next poll.
+type MaildirPoller struct {
+	Dir      string
+	Interval time.Duration
+	MaxBytes int64
+	Ingester *Ingester
+}
+
+func (p *MaildirPoller) Run(ctx context.Context) {
+	for _, sub := range []string{"new", "cur", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(p.Dir, sub), 0o750); err != nil {
+			log.Printf("maildir %s: %v", p.Dir, err)
+			return
+		}
+	}
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		p.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll processes every message waiting in new/ and returns how many were
+// moved out of it.
+func (p *MaildirPoller) Poll(ctx context.Context) int {
+	entries, err := os.ReadDir(filepath.Join(p.Dir, "new"))
+	if err != nil {
+		log.Printf("maildir %s: %v", p.Dir, err)
+		return 0
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	moved := 0
+	for _, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		src := filepath.Join(p.Dir, "new", name)
+		raw, err := p.read(src)
+		if err != nil && !errors.Is(err, errMessageTooLarge) {
+			log.Printf("maildir read %s: %v", name, err)
+			continue
+		}
+		flag := "S"
+		if err != nil {
+			log.Printf("maildir skipped %s: %v", name, err)
+			flag = "F"
+		} else if result, err := p.Ingester.Ingest(ctx, raw); err != nil {
+			if !Permanent(err) {
+				log.Printf("maildir ingest %s: %v (will retry)", name, err)
+				continue
+			}
+			log.Printf("maildir ingest %s: %v", name, err)
+			flag = "F"
+		} else if result.Action == domain.EmailActionIgnored {
+			log.Printf("maildir ignored %s: %s", name, result.Reason)
+		}
+		if err := os.Rename(src, filepath.Join(p.Dir, "cur", name+":2,"+flag)); err != nil {
+			log.Printf("maildir move %s: %v", name, err)
+			continue
+		}
+		moved++
+	}
+	return moved
+}
+
+var errMessageTooLarge = errors.New("message too large")
+
+// read loads one message, refusing it without reading the body when it is
+// larger than MaxBytes.
+func (p *MaildirPoller) read(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	limit := p.MaxBytes
+	if limit <= 0 {
+		limit = 25 << 20
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > limit {
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d", errMessageTooLarge, info.Size(), limit)
+	}
+	raw, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > limit {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", errMessageTooLarge, limit)
+	}
+	return raw, nil
+}
@@ -0,0 +1,220 @@
+package email
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
+
+// maxPartDepth bounds multipart nesting so a crafted message cannot recurse
+// without end.
+const maxPartDepth = 10
+
+var (
+	ErrMalformedMessage = errors.New("malformed email message")
+
+	messageIDPattern = regexp.MustCompile(`<[^<>\s]+>`)
+	wordDecoder      = &mime.WordDecoder{CharsetReader: charsetReader}
+)
+
+// Parse reads a raw RFC 5322 message into an InboundEmail. Multipart bodies
+// are walked for the text and HTML alternatives and for attachments; an
+// HTML-only message gets a plain text rendering.
+func Parse(raw []byte) (domain.InboundEmail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return domain.InboundEmail{}, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	out := domain.InboundEmail{
+		Headers:    map[string][]string(msg.Header),
+		Subject:    decodeHeader(msg.Header.Get("Subject")),
+		MessageID:  firstMessageID(msg.Header.Get("Message-Id")),
+		InReplyTo:  messageIDPattern.FindAllString(msg.Header.Get("In-Reply-To"), -1),
+		References: messageIDPattern.FindAllString(msg.Header.Get("References"), -1),
+		ReceivedAt: time.Now().UTC(),
+	}
+	addresses := &mail.AddressParser{WordDecoder: wordDecoder}
+	from, err := addresses.Parse(msg.Header.Get("From"))
+	if err != nil {
+		return domain.InboundEmail{}, fmt.Errorf("%w: from: %v", ErrMalformedMessage, err)
+	}
+	out.From = strings.ToLower(from.Address)
+	out.FromName = from.Name
+	if to, err := addresses.ParseList(msg.Header.Get("To")); err == nil {
+		for _, a := range to {
+			out.To = append(out.To, strings.ToLower(a.Address))
+		}
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		out.ReceivedAt = date.UTC()
+	}
+	if out.MessageID == "" {
+		sum := sha256.Sum256(raw)
+		out.MessageID = "<" + hex.EncodeToString(sum[:16]) + "@generated.m73>"
+	}
+
+	if err := walkPart(&out, textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return domain.InboundEmail{}, err
+	}
+	out.TextBody = strings.TrimSpace(out.TextBody)
+	if out.TextBody == "" && out.HTMLBody != "" {
+		out.TextBody = HTMLToText(out.HTMLBody)
+	}
+	return out, nil
+}
+
+func walkPart(out *domain.InboundEmail, header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxPartDepth {
+		return fmt.Errorf("%w: multipart nesting too deep", ErrMalformedMessage)
+	}
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		boundary := params["boundary"]
+		if boundary == "" {
+			return fmt.Errorf("%w: multipart without boundary", ErrMalformedMessage)
+		}
+		reader := multipart.NewReader(body, boundary)
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+			}
+			if err := walkPart(out, part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := decodeHeader(dispParams["filename"])
+	if filename == "" {
+		filename = decodeHeader(params["name"])
+	}
+	isAttachment := disposition == "attachment" || filename != "" || mediaType == "message/rfc822"
+	switch {
+	case !isAttachment && mediaType == "text/plain" && out.TextBody == "":
+		out.TextBody = toUTF8(data, params["charset"])
+	case !isAttachment && mediaType == "text/html" && out.HTMLBody == "":
+		out.HTMLBody = toUTF8(data, params["charset"])
+	case isAttachment || !strings.HasPrefix(mediaType, "text/"):
+		if filename == "" {
+			filename = "attachment"
+			if mediaType == "message/rfc822" {
+				filename = "message.eml"
+			}
+		}
+		out.Attachments = append(out.Attachments, domain.InboundAttachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Data:        data,
+		})
+	}
+	return nil
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+func decodeHeader(v string) string {
+	if decoded, err := wordDecoder.DecodeHeader(v); err == nil {
+		return strings.TrimSpace(decoded)
+	}
+	return strings.TrimSpace(v)
+}
+
+func firstMessageID(v string) string {
+	return messageIDPattern.FindString(v)
+}
+
+// charsetReader covers the charsets besides UTF-8 that mail clients still
+// send; anything else is read as UTF-8 and cleaned by toUTF8.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(toUTF8(data, charset)), nil
+}
+
+func toUTF8(data []byte, charset string) string {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return strings.ToValidUTF8(string(data), "�")
+}
+
+var (
+	htmlDropPattern  = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlBreakPattern = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/tr|/h[1-6]|/blockquote)[^>]*>`)
+	htmlItemPattern  = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlQuotePattern = regexp.MustCompile(`(?is)<blockquote[^>]*>(.*?)</blockquote>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRunPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText renders an HTML body as plain text. Block elements become line
+// breaks and blockquotes are prefixed with "> " so quoted history is
+// recognised like in plain text mail.
+func HTMLToText(src string) string {
+	s := htmlDropPattern.ReplaceAllString(src, "")
+	s = htmlQuotePattern.ReplaceAllStringFunc(s, func(block string) string {
+		inner := htmlQuotePattern.FindStringSubmatch(block)[1]
+		inner = htmlTagPattern.ReplaceAllString(htmlBreakPattern.ReplaceAllString(inner, "\n"), "")
+		lines := strings.Split(strings.TrimSpace(inner), "\n")
+		for i, line := range lines {
+			lines[i] = "> " + strings.TrimSpace(line)
+		}
+		return "\n" + strings.Join(lines, "\n") + "\n"
+	})
+	s = htmlItemPattern.ReplaceAllString(s, "- ")
+	s = htmlBreakPattern.ReplaceAllString(s, "\n")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankRunPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
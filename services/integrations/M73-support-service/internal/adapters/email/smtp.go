@@ -0,0 +1,174 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const (
+	smtpMaxRecipients = 100
+	smtpIdleTimeout   = 5 * time.Minute
+)
+
+// SMTPServer accepts mail for the support desk over plain SMTP. It is meant
+// to sit behind the edge MTA on an internal address and does no relaying,
+// authentication or TLS.
+type SMTPServer struct {
+	Addr     string
+	Hostname string
+	MaxBytes int64
+	Ingester *Ingester
+}
+
+// ListenAndServe serves until ctx is cancelled.
+func (s *SMTPServer) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+func (s *SMTPServer) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+		go s.serveConn(ctx, conn)
+	}
+}
+
+type smtpSession struct {
+	helo       bool
+	mail       bool
+	recipients int
+}
+
+func (s *SMTPServer) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(code int, msg string) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(smtpIdleTimeout))
+		return tp.PrintfLine("%d %s", code, msg) == nil
+	}
+	if !reply(220, s.Hostname+" ESMTP support intake") {
+		return
+	}
+	var sess smtpSession
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(smtpIdleTimeout))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			sess = smtpSession{helo: true}
+			reply(250, s.Hostname)
+		case "EHLO":
+			sess = smtpSession{helo: true}
+			_ = tp.PrintfLine("250-%s", s.Hostname)
+			_ = tp.PrintfLine("250-SIZE %d", s.MaxBytes)
+			reply(250, "8BITMIME")
+		case "MAIL":
+			if !sess.helo {
+				reply(503, "5.5.1 send HELO first")
+				continue
+			}
+			if !validPath(arg, "FROM:") {
+				reply(501, "5.5.4 syntax: MAIL FROM:<address>")
+				continue
+			}
+			sess.mail, sess.recipients = true, 0
+			reply(250, "2.1.0 ok")
+		case "RCPT":
+			if !sess.mail {
+				reply(503, "5.5.1 send MAIL first")
+				continue
+			}
+			if !validPath(arg, "TO:") {
+				reply(501, "5.5.4 syntax: RCPT TO:<address>")
+				continue
+			}
+			if sess.recipients >= smtpMaxRecipients {
+				reply(452, "4.5.3 too many recipients")
+				continue
+			}
+			sess.recipients++
+			reply(250, "2.1.5 ok")
+		case "DATA":
+			if sess.recipients == 0 {
+				reply(503, "5.5.1 send RCPT first")
+				continue
+			}
+			reply(354, "end data with <CR><LF>.<CR><LF>")
+			code, msg := s.receive(ctx, tp)
+			reply(code, msg)
+			sess = smtpSession{helo: true}
+		case "RSET":
+			sess = smtpSession{helo: sess.helo}
+			reply(250, "2.0.0 ok")
+		case "NOOP":
+			reply(250, "2.0.0 ok")
+		case "QUIT":
+			reply(221, "2.0.0 bye")
+			return
+		default:
+			reply(502, "5.5.2 command not implemented")
+		}
+	}
+}
+
+// receive reads one message body and ingests it, returning the SMTP reply.
+// Transient failures answer 451 so the sending MTA retries.
+func (s *SMTPServer) receive(ctx context.Context, tp *textproto.Conn) (int, string) {
+	dot := tp.DotReader()
+	raw, err := io.ReadAll(io.LimitReader(dot, s.MaxBytes+1))
+	if err != nil {
+		return 451, "4.3.0 read failed"
+	}
+	if int64(len(raw)) > s.MaxBytes {
+		_, _ = io.Copy(io.Discard, dot)
+		return 552, fmt.Sprintf("5.3.4 message exceeds %d bytes", s.MaxBytes)
+	}
+	result, err := s.Ingester.Ingest(ctx, raw)
+	if err != nil {
+		if Permanent(err) {
+			return 554, "5.6.0 " + err.Error()
+		}
+		log.Printf("smtp ingest: %v", err)
+		return 451, "4.3.0 temporary failure, try again later"
+	}
+	return 250, "2.0.0 " + result.Action + " " + result.TicketID
+}
+
+// validPath checks a "FROM:<addr> PARAMS" style argument; the null path
+// "<>" is allowed.
+func validPath(arg, prefix string) bool {
+	arg = strings.TrimSpace(arg)
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return false
+	}
+	rest := strings.TrimSpace(arg[len(prefix):])
+	return strings.HasPrefix(rest, "<") && strings.Contains(rest, ">")
+}
@@ -0,0 +1,39 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore writes attachments below a directory on local disk.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("attachment dir: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put stores data under key, a slash separated relative path. The file is
+// written to a temporary name first so readers never see partial content.
+func (s *LocalStore) Put(_ context.Context, key string, data []byte) error {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if clean == "." || filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+		return fmt.Errorf("invalid attachment key %q", key)
+	}
+	target := filepath.Join(s.dir, clean)
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
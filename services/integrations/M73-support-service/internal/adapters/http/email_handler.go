@@ -0,0 +1,55 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	emailadapter "github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/email"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
+
+const maxInboundEmailBytes = 25 << 20
+
+// inboundEmail accepts one raw RFC 5322 message as the request body, for
+// mail gateways that deliver by webhook.
+func (h *Handler) inboundEmail(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboundEmailBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "payload_too_large", "message exceeds 25 MiB", requestIDFromContext(r.Context()))
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_input", "unreadable body", requestIDFromContext(r.Context()))
+		return
+	}
+	msg, err := emailadapter.Parse(raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	result, err := h.service.ProcessInboundEmail(r.Context(), actor, msg)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	status := http.StatusOK
+	if result.Action == domain.EmailActionCreated {
+		status = http.StatusCreated
+	}
+	writeSuccess(w, status, "", result)
+}
+
+func (h *Handler) listTicketAttachments(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	items, err := h.service.ListTicketAttachments(r.Context(), actor, r.PathValue("id"))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", items)
+}
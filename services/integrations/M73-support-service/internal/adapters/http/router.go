@@ -64,6 +64,14 @@ func NewRouter(handler *Handler) http.Handler {
 		handler.addReply(w, r)
 	})))
 
+	mux.Handle("/api/v1/support/tickets/{id}/attachments", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.listTicketAttachments(w, r)
+	})))
+
 	mux.Handle("/api/v1/support/tickets/{id}/csat", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
@@ -100,6 +108,44 @@ func NewRouter(handler *Handler) http.Handler {
 		handler.assignTicket(w, r)
 	}))))
 
+	mux.Handle("/api/v1/support/admin/tickets/{id}/auto-assign", authMiddleware(adminRoles(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.autoAssignTicket(w, r)
+	}))))
+
+	mux.Handle("/api/v1/support/admin/sla/policies", authMiddleware(adminRoles(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handler.listSLAPolicies(w, r)
+		case http.MethodPost:
+			handler.upsertSLAPolicy(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+		}
+	}))))
+
+	mux.Handle("/api/v1/support/admin/sla/escalation-rules", authMiddleware(adminRoles(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handler.listEscalationRules(w, r)
+		case http.MethodPost:
+			handler.upsertEscalationRule(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+		}
+	}))))
+
+	mux.Handle("/api/v1/support/admin/sla/report", authMiddleware(adminRoles(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.slaReport(w, r)
+	}))))
+
 	mux.Handle("/api/internal/tickets/create-from-email", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
@@ -108,5 +154,13 @@ func NewRouter(handler *Handler) http.Handler {
 		handler.createTicketFromEmail(w, r)
 	})))
 
+	mux.Handle("/api/internal/email/inbound", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.inboundEmail(w, r)
+	})))
+
 	return requestIDMiddleware(mux)
 }
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/contracts"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
+
+func (h *Handler) autoAssignTicket(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	ticket, err := h.service.AutoAssignTicket(r.Context(), actor, r.PathValue("id"))
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", ticket)
+}
+
+func (h *Handler) listSLAPolicies(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	items, err := h.service.ListSLAPolicies(r.Context(), actor)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", items)
+}
+
+func (h *Handler) upsertSLAPolicy(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	var req contracts.UpsertSLAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	input := application.UpsertSLAPolicyInput{
+		PolicyID:            req.PolicyID,
+		Name:                req.Name,
+		Priority:            req.Priority,
+		Category:            req.Category,
+		FirstResponseTarget: time.Duration(req.FirstResponseMinutes) * time.Minute,
+		ResolutionTarget:    time.Duration(req.ResolutionMinutes) * time.Minute,
+		BusinessHoursOnly:   req.BusinessHoursOnly,
+		Active:              req.Active,
+	}
+	if hours := req.BusinessHours; hours != nil {
+		input.BusinessHours = &domain.BusinessHours{
+			Timezone: hours.Timezone,
+			Start:    hours.Start,
+			End:      hours.End,
+			Holidays: hours.Holidays,
+		}
+		for _, d := range hours.Weekdays {
+			input.BusinessHours.Weekdays = append(input.BusinessHours.Weekdays, time.Weekday(d))
+		}
+	}
+	policy, err := h.service.UpsertSLAPolicy(r.Context(), actor, input)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", policy)
+}
+
+func (h *Handler) listEscalationRules(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	items, err := h.service.ListEscalationRules(r.Context(), actor)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", items)
+}
+
+func (h *Handler) upsertEscalationRule(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	var req contracts.UpsertEscalationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	rule, err := h.service.UpsertEscalationRule(r.Context(), actor, application.UpsertEscalationRuleInput{
+		RuleID:     req.RuleID,
+		Name:       req.Name,
+		Clock:      req.Clock,
+		Priority:   req.Priority,
+		Category:   req.Category,
+		Before:     time.Duration(req.BeforeMinutes) * time.Minute,
+		Action:     req.Action,
+		TargetRole: req.TargetRole,
+		Active:     req.Active,
+	})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", rule)
+}
+
+func (h *Handler) slaReport(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	var from, to time.Time
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		raw := strings.TrimSpace(r.URL.Query().Get(p.name))
+		if raw == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", p.name+" must be an RFC3339 timestamp", requestIDFromContext(r.Context()))
+			return
+		}
+		*p.dst = v.UTC()
+	}
+	report, err := h.service.GetSLAReport(r.Context(), actor, from, to)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", report)
+}
@@ -15,6 +15,10 @@ type Repositories struct {
 	Replies     *ReplyRepository
 	CSAT        *CSATRepository
 	Agents      *AgentRepository
+	SLAPolicies *SLAPolicyRepository
+	Escalations *EscalationRuleRepository
+	Emails      *EmailMessageRepository
+	Attachments *AttachmentRepository
 	Idempotency *IdempotencyRepository
 }
 
@@ -25,11 +29,27 @@ func NewRepositories() *Repositories {
 		"agent-senior":    {AgentID: "agent-senior", Role: "senior_agent", SkillTags: []string{"billing", "technical", "refund", "account", "partner program", "other"}, Active: true},
 		"manager-support": {AgentID: "manager-support", Role: "support_manager", SkillTags: []string{"management"}, Active: true},
 	}
+	// Urgent tickets run on wall-clock time; normal and low priority ones
+	// only count business hours.
+	policies := map[string]domain.SLAPolicy{
+		"sla-critical": {PolicyID: "sla-critical", Name: "Critical", Priority: "critical", FirstResponseTarget: 4 * time.Hour, ResolutionTarget: 24 * time.Hour, Active: true},
+		"sla-high":     {PolicyID: "sla-high", Name: "High", Priority: "high", FirstResponseTarget: 12 * time.Hour, ResolutionTarget: 72 * time.Hour, Active: true},
+		"sla-normal":   {PolicyID: "sla-normal", Name: "Normal", Priority: "normal", FirstResponseTarget: 24 * time.Hour, ResolutionTarget: 120 * time.Hour, BusinessHoursOnly: true, Active: true},
+		"sla-low":      {PolicyID: "sla-low", Name: "Low", Priority: "low", FirstResponseTarget: 24 * time.Hour, ResolutionTarget: 240 * time.Hour, BusinessHoursOnly: true, Active: true},
+	}
+	rules := map[string]domain.EscalationRule{
+		"esc-first-response-at-risk": {RuleID: "esc-first-response-at-risk", Name: "First response at risk", Clock: domain.SLAClockFirstResponse, Before: time.Hour, Action: domain.EscalationActionReassign, TargetRole: "senior_agent", Active: true},
+		"esc-resolution-at-risk":     {RuleID: "esc-resolution-at-risk", Name: "Resolution at risk", Clock: domain.SLAClockResolution, Before: 4 * time.Hour, Action: domain.EscalationActionBumpPriority, Active: true},
+	}
 	return &Repositories{
 		Tickets:     &TicketRepository{rows: map[string]domain.Ticket{}},
 		Replies:     &ReplyRepository{rows: map[string][]domain.TicketReply{}},
 		CSAT:        &CSATRepository{rows: map[string][]domain.CSATRating{}},
 		Agents:      &AgentRepository{rows: agents},
+		SLAPolicies: &SLAPolicyRepository{rows: policies},
+		Escalations: &EscalationRuleRepository{rows: rules},
+		Emails:      &EmailMessageRepository{rows: map[string]domain.EmailMessage{}},
+		Attachments: &AttachmentRepository{rows: map[string][]domain.EmailAttachment{}},
 		Idempotency: &IdempotencyRepository{rows: map[string]domain.IdempotencyRecord{}},
 	}
 }
@@ -45,7 +65,7 @@ func (r *TicketRepository) Create(_ context.Context, ticket domain.Ticket) error
 	if _, ok := r.rows[ticket.TicketID]; ok {
 		return domain.ErrConflict
 	}
-	r.rows[ticket.TicketID] = ticket
+	r.rows[ticket.TicketID] = cloneTicket(ticket)
 	return nil
 }
 
@@ -55,7 +75,7 @@ func (r *TicketRepository) Update(_ context.Context, ticket domain.Ticket) error
 	if _, ok := r.rows[ticket.TicketID]; !ok {
 		return domain.ErrNotFound
 	}
-	r.rows[ticket.TicketID] = ticket
+	r.rows[ticket.TicketID] = cloneTicket(ticket)
 	return nil
 }
 
@@ -66,7 +86,7 @@ func (r *TicketRepository) Get(_ context.Context, ticketID string) (domain.Ticke
 	if !ok {
 		return domain.Ticket{}, domain.ErrNotFound
 	}
-	return ticket, nil
+	return cloneTicket(ticket), nil
 }
 
 func (r *TicketRepository) Search(_ context.Context, filter domain.SearchFilter) ([]domain.Ticket, error) {
@@ -107,10 +127,50 @@ func (r *TicketRepository) Search(_ context.Context, filter domain.SearchFilter)
 		items = items[:limit]
 	}
 	out := make([]domain.Ticket, len(items))
-	copy(out, items)
+	for i, ticket := range items {
+		out[i] = cloneTicket(ticket)
+	}
+	return out, nil
+}
+
+func (r *TicketRepository) ListActive(_ context.Context) ([]domain.Ticket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.Ticket, 0, len(r.rows))
+	for _, ticket := range r.rows {
+		switch ticket.Status {
+		case "resolved", "closed", "deleted":
+			continue
+		}
+		out = append(out, cloneTicket(ticket))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
 	return out, nil
 }
 
+func (r *TicketRepository) ListCreatedBetween(_ context.Context, from, to time.Time) ([]domain.Ticket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.Ticket, 0, len(r.rows))
+	for _, ticket := range r.rows {
+		if ticket.CreatedAt.Before(from) || !ticket.CreatedAt.Before(to) {
+			continue
+		}
+		out = append(out, cloneTicket(ticket))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+func cloneTicket(ticket domain.Ticket) domain.Ticket {
+	ticket.SLA = ticket.SLA.Clone()
+	return ticket
+}
+
 type ReplyRepository struct {
 	mu   sync.Mutex
 	rows map[string][]domain.TicketReply
@@ -191,6 +251,134 @@ func (r *AgentRepository) Upsert(_ context.Context, agent domain.Agent) error {
 	return nil
 }
 
+type SLAPolicyRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.SLAPolicy
+}
+
+func (r *SLAPolicyRepository) List(_ context.Context) ([]domain.SLAPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]domain.SLAPolicy, 0, len(r.rows))
+	for _, policy := range r.rows {
+		items = append(items, policy)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].PolicyID < items[j].PolicyID
+	})
+	return items, nil
+}
+
+func (r *SLAPolicyRepository) Get(_ context.Context, policyID string) (domain.SLAPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	policy, ok := r.rows[strings.TrimSpace(policyID)]
+	if !ok {
+		return domain.SLAPolicy{}, domain.ErrNotFound
+	}
+	return policy, nil
+}
+
+func (r *SLAPolicyRepository) Upsert(_ context.Context, policy domain.SLAPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[policy.PolicyID] = policy
+	return nil
+}
+
+type EscalationRuleRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.EscalationRule
+}
+
+func (r *EscalationRuleRepository) List(_ context.Context) ([]domain.EscalationRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]domain.EscalationRule, 0, len(r.rows))
+	for _, rule := range r.rows {
+		items = append(items, rule)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].RuleID < items[j].RuleID
+	})
+	return items, nil
+}
+
+func (r *EscalationRuleRepository) Get(_ context.Context, ruleID string) (domain.EscalationRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rows[strings.TrimSpace(ruleID)]
+	if !ok {
+		return domain.EscalationRule{}, domain.ErrNotFound
+	}
+	return rule, nil
+}
+
+func (r *EscalationRuleRepository) Upsert(_ context.Context, rule domain.EscalationRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[rule.RuleID] = rule
+	return nil
+}
+
+type EmailMessageRepository struct {
+	mu   sync.Mutex
+	rows map[string]domain.EmailMessage
+}
+
+func (r *EmailMessageRepository) Get(_ context.Context, messageID string) (domain.EmailMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msg, ok := r.rows[strings.TrimSpace(messageID)]
+	if !ok {
+		return domain.EmailMessage{}, domain.ErrNotFound
+	}
+	return msg, nil
+}
+
+func (r *EmailMessageRepository) Add(_ context.Context, msg domain.EmailMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rows[msg.MessageID]; ok {
+		return domain.ErrConflict
+	}
+	r.rows[msg.MessageID] = msg
+	return nil
+}
+
+func (r *EmailMessageRepository) CountFromSince(_ context.Context, sender string, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, msg := range r.rows {
+		if msg.Sender == sender && !msg.ReceivedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type AttachmentRepository struct {
+	mu   sync.Mutex
+	rows map[string][]domain.EmailAttachment
+}
+
+func (r *AttachmentRepository) Add(_ context.Context, attachment domain.EmailAttachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[attachment.TicketID] = append(r.rows[attachment.TicketID], attachment)
+	return nil
+}
+
+func (r *AttachmentRepository) ListByTicket(_ context.Context, ticketID string) ([]domain.EmailAttachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := r.rows[strings.TrimSpace(ticketID)]
+	out := make([]domain.EmailAttachment, len(items))
+	copy(out, items)
+	return out, nil
+}
+
 type IdempotencyRepository struct {
 	mu   sync.Mutex
 	rows map[string]domain.IdempotencyRecord
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +16,26 @@ type Config struct {
 	HTTPPort       int
 	GRPCPort       int
 	IdempotencyTTL time.Duration
+	// SLASweepInterval is how often open tickets are checked for SLA
+	// breaches and escalations.
+	SLASweepInterval       time.Duration
+	MaxOpenTicketsPerAgent int
+	BusinessTimezone       string
+	BusinessDays           []time.Weekday
+	BusinessStart          string
+	BusinessEnd            string
+	Holidays               []string
+
+	// Inbound email. The Maildir poller and SMTP listener only start when
+	// their path or address is set.
+	SupportAddress            string
+	Maildir                   string
+	MaildirPollInterval       time.Duration
+	SMTPAddr                  string
+	MaxEmailBytes             int64
+	AttachmentDir             string
+	MaxAttachmentBytes        int64
+	MaxEmailsPerSenderPerHour int
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -24,6 +45,20 @@ func LoadConfig(path string) (Config, error) {
 		HTTPPort:       8080,
 		GRPCPort:       9090,
 		IdempotencyTTL: 7 * 24 * time.Hour,
+
+		SLASweepInterval:       time.Minute,
+		MaxOpenTicketsPerAgent: 20,
+		BusinessTimezone:       "UTC",
+		BusinessDays:           []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		BusinessStart:          "09:00",
+		BusinessEnd:            "17:00",
+
+		SupportAddress:            "support@viralforge.io",
+		MaildirPollInterval:       30 * time.Second,
+		MaxEmailBytes:             25 << 20,
+		AttachmentDir:             filepath.Join(os.TempDir(), "m73-attachments"),
+		MaxAttachmentBytes:        10 << 20,
+		MaxEmailsPerSenderPerHour: 30,
 	}
 	if path != "" {
 		if err := parseConfigFile(path, &cfg); err != nil {
@@ -34,6 +69,16 @@ func LoadConfig(path string) (Config, error) {
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
 	cfg.Version = envString("SERVICE_VERSION", cfg.Version)
 	cfg.IdempotencyTTL = time.Duration(envInt("IDEMPOTENCY_TTL_HOURS", int(cfg.IdempotencyTTL.Hours()))) * time.Hour
+	cfg.SLASweepInterval = time.Duration(envInt("SLA_SWEEP_INTERVAL_SECONDS", int(cfg.SLASweepInterval.Seconds()))) * time.Second
+	cfg.MaxOpenTicketsPerAgent = envInt("MAX_OPEN_TICKETS_PER_AGENT", cfg.MaxOpenTicketsPerAgent)
+	cfg.BusinessTimezone = envString("SLA_BUSINESS_TIMEZONE", cfg.BusinessTimezone)
+	if raw := os.Getenv("SLA_HOLIDAYS"); raw != "" {
+		cfg.Holidays = splitList(raw)
+	}
+	cfg.SupportAddress = envString("SUPPORT_EMAIL_ADDRESS", cfg.SupportAddress)
+	cfg.Maildir = envString("EMAIL_MAILDIR", cfg.Maildir)
+	cfg.SMTPAddr = envString("EMAIL_SMTP_ADDR", cfg.SMTPAddr)
+	cfg.AttachmentDir = envString("EMAIL_ATTACHMENT_DIR", cfg.AttachmentDir)
 	return cfg, nil
 }
 
@@ -85,6 +130,62 @@ func parseConfigFile(path string, cfg *Config) error {
 			if v, err := strconv.Atoi(value); err == nil && v > 0 {
 				cfg.IdempotencyTTL = time.Duration(v) * time.Hour
 			}
+		case "sla.sweep_interval_seconds":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.SLASweepInterval = time.Duration(v) * time.Second
+			}
+		case "sla.max_open_tickets_per_agent":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.MaxOpenTicketsPerAgent = v
+			}
+		case "sla.business_timezone":
+			if value != "" {
+				cfg.BusinessTimezone = value
+			}
+		case "sla.business_days":
+			if days, err := parseWeekdays(value); err != nil {
+				return err
+			} else if len(days) > 0 {
+				cfg.BusinessDays = days
+			}
+		case "sla.business_start":
+			if value != "" {
+				cfg.BusinessStart = value
+			}
+		case "sla.business_end":
+			if value != "" {
+				cfg.BusinessEnd = value
+			}
+		case "sla.holidays":
+			cfg.Holidays = splitList(value)
+		case "email.support_address":
+			if value != "" {
+				cfg.SupportAddress = value
+			}
+		case "email.maildir":
+			cfg.Maildir = value
+		case "email.maildir_poll_seconds":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.MaildirPollInterval = time.Duration(v) * time.Second
+			}
+		case "email.smtp_addr":
+			cfg.SMTPAddr = value
+		case "email.max_message_mb":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.MaxEmailBytes = int64(v) << 20
+			}
+		case "email.attachment_dir":
+			if value != "" {
+				cfg.AttachmentDir = value
+			}
+		case "email.max_attachment_mb":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.MaxAttachmentBytes = int64(v) << 20
+			}
+		case "email.max_per_sender_per_hour":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.MaxEmailsPerSenderPerHour = v
+			}
 		}
 	}
 	if err := scanner.Err(); err != nil {
@@ -93,6 +194,34 @@ func parseConfigFile(path string, cfg *Config) error {
 	return nil
 }
 
+// splitList parses a comma separated value, optionally in [brackets].
+func splitList(raw string) []string {
+	raw = strings.Trim(strings.TrimSpace(raw), "[]")
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), `"'`); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func parseWeekdays(raw string) ([]time.Weekday, error) {
+	names := map[string]time.Weekday{
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+		"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	}
+	var out []time.Weekday
+	for _, item := range splitList(raw) {
+		day, ok := names[strings.ToLower(item)]
+		if !ok {
+			return nil, fmt.Errorf("config: unknown business day %q", item)
+		}
+		out = append(out, day)
+	}
+	return out, nil
+}
+
 func envInt(name string, fallback int) int {
 	if raw := os.Getenv(name); raw != "" {
 		if v, err := strconv.Atoi(raw); err == nil {
@@ -2,40 +2,95 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	stdhttp "net/http"
+	"strings"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
+	emailadapter "github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/email"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/filestore"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
 )
 
 type Runtime struct {
-	httpServer *stdhttp.Server
+	httpServer    *stdhttp.Server
+	telemetry     *observability.Telemetry
+	service       *application.Service
+	sweepInterval time.Duration
+	maildir       *emailadapter.MaildirPoller
+	smtp          *emailadapter.SMTPServer
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, cfg.Version))
+	if err != nil {
+		return nil, err
+	}
+	hours := &domain.BusinessHours{
+		Timezone: cfg.BusinessTimezone,
+		Weekdays: cfg.BusinessDays,
+		Start:    cfg.BusinessStart,
+		End:      cfg.BusinessEnd,
+		Holidays: cfg.Holidays,
+	}
+	if err := hours.Validate(); err != nil {
+		return nil, fmt.Errorf("sla business hours: %w", err)
+	}
+	store, err := filestore.NewLocalStore(cfg.AttachmentDir)
+	if err != nil {
+		return nil, err
+	}
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
-		Config:      application.Config{ServiceName: cfg.ServiceID, Version: cfg.Version, IdempotencyTTL: cfg.IdempotencyTTL},
+		Config: application.Config{
+			ServiceName:            cfg.ServiceID,
+			Version:                cfg.Version,
+			IdempotencyTTL:         cfg.IdempotencyTTL,
+			MaxOpenTicketsPerAgent: cfg.MaxOpenTicketsPerAgent,
+			BusinessHours:          hours,
+
+			SupportAddress:            cfg.SupportAddress,
+			MaxAttachmentBytes:        cfg.MaxAttachmentBytes,
+			MaxEmailsPerSenderPerHour: cfg.MaxEmailsPerSenderPerHour,
+		},
 		Tickets:     repos.Tickets,
 		Replies:     repos.Replies,
 		CSAT:        repos.CSAT,
 		Agents:      repos.Agents,
+		SLAPolicies: repos.SLAPolicies,
+		Escalations: repos.Escalations,
+		Emails:      repos.Emails,
+		Attachments: repos.Attachments,
 		Idempotency: repos.Idempotency,
+
+		AttachmentStore: store,
 	})
 	router := httpadapter.NewRouter(httpadapter.NewHandler(svc))
 	server := &stdhttp.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler:           router,
+		Handler:           observability.HTTPMiddleware(cfg.ServiceID)(router),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
-	return &Runtime{httpServer: server}, nil
+	rt := &Runtime{httpServer: server, telemetry: telemetry, service: svc, sweepInterval: cfg.SLASweepInterval}
+	ingester := emailadapter.NewIngester(svc)
+	if cfg.Maildir != "" {
+		rt.maildir = &emailadapter.MaildirPoller{Dir: cfg.Maildir, Interval: cfg.MaildirPollInterval, MaxBytes: cfg.MaxEmailBytes, Ingester: ingester}
+	}
+	if cfg.SMTPAddr != "" {
+		_, host, _ := strings.Cut(cfg.SupportAddress, "@")
+		rt.smtp = &emailadapter.SMTPServer{Addr: cfg.SMTPAddr, Hostname: host, MaxBytes: cfg.MaxEmailBytes, Ingester: ingester}
+	}
+	return rt, nil
 }
 
 func (r *Runtime) Run(ctx context.Context) error {
@@ -45,6 +100,19 @@ func (r *Runtime) Run(ctx context.Context) error {
 			errCh <- err
 		}
 	}()
+	// The sweep shares the in-memory repositories with the HTTP API, so it
+	// runs in this process rather than in cmd/worker.
+	go r.sweepSLAs(ctx)
+	if r.maildir != nil {
+		go r.maildir.Run(ctx)
+	}
+	if r.smtp != nil {
+		go func() {
+			if err := r.smtp.ListenAndServe(ctx); err != nil {
+				errCh <- fmt.Errorf("smtp: %w", err)
+			}
+		}()
+	}
 	select {
 	case <-ctx.Done():
 	case err := <-errCh:
@@ -54,5 +122,25 @@ func (r *Runtime) Run(ctx context.Context) error {
 	}
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return r.httpServer.Shutdown(shutdownCtx)
+	return errors.Join(r.httpServer.Shutdown(shutdownCtx), r.telemetry.Shutdown(shutdownCtx))
+}
+
+func (r *Runtime) sweepSLAs(ctx context.Context) {
+	ticker := time.NewTicker(r.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := r.service.RunSLASweep(ctx)
+			if err != nil {
+				log.Printf("sla sweep: %v", err)
+				continue
+			}
+			if result.Breached > 0 || result.Escalated > 0 {
+				log.Printf("sla sweep: checked=%d breached=%d escalated=%d", result.Checked, result.Breached, result.Escalated)
+			}
+		}
+	}
 }
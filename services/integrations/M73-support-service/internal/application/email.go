@@ -0,0 +1,234 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
+
+const (
+	maxAttachmentsPerEmail = 20
+	emptyEmailBody         = "(no message body)"
+	emptyEmailSubject      = "(no subject)"
+)
+
+// ProcessInboundEmail turns a parsed inbound message into a ticket or a
+// reply on the ticket it answers. Machine generated mail, looping senders
+// and messages seen before are acknowledged without touching tickets.
+func (s *Service) ProcessInboundEmail(ctx context.Context, actor Actor, email domain.InboundEmail) (InboundEmailResult, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return InboundEmailResult{}, domain.ErrUnauthorized
+	}
+	if s.emails == nil {
+		return InboundEmailResult{}, fmt.Errorf("%w: email intake is not configured", domain.ErrNotFound)
+	}
+	sender := strings.ToLower(strings.TrimSpace(email.From))
+	if sender == "" || strings.TrimSpace(email.MessageID) == "" {
+		return InboundEmailResult{}, fmt.Errorf("%w: message needs a sender and message id", domain.ErrInvalidInput)
+	}
+	result := InboundEmailResult{MessageID: email.MessageID}
+	if seen, err := s.emails.Get(ctx, email.MessageID); err == nil {
+		result.Action, result.TicketID, result.ReplyID = domain.EmailActionDuplicate, seen.TicketID, seen.ReplyID
+		return result, nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return InboundEmailResult{}, err
+	}
+	if reason := domain.AutoResponseReason(email, s.cfg.SupportAddress); reason != "" {
+		result.Action, result.Reason = domain.EmailActionIgnored, reason
+		return result, nil
+	}
+	now := s.nowFn()
+	if limit := s.cfg.MaxEmailsPerSenderPerHour; limit > 0 {
+		n, err := s.emails.CountFromSince(ctx, sender, now.Add(-time.Hour))
+		if err != nil {
+			return InboundEmailResult{}, err
+		}
+		if n >= limit {
+			result.Action, result.Reason = domain.EmailActionIgnored, fmt.Sprintf("mail loop: more than %d messages from sender in an hour", limit)
+			return result, nil
+		}
+	}
+
+	body := domain.StripQuotedReply(email.TextBody)
+	if utf8.RuneCountInString(body) < 3 {
+		body = emptyEmailBody
+	}
+	// Retries of a message that failed halfway reuse the same ticket and
+	// reply through these idempotency keys.
+	idempotencyKey := "email:" + email.MessageID
+	ticket, threaded, err := s.threadTicket(ctx, email, sender)
+	if err != nil {
+		return InboundEmailResult{}, err
+	}
+	if threaded {
+		reply, err := s.addReply(ctx, Actor{SubjectID: sender, Role: "user", IdempotencyKey: idempotencyKey}, ticket.TicketID, AddReplyInput{ReplyType: "public", Body: body}, email.MessageID)
+		if err != nil {
+			return InboundEmailResult{}, err
+		}
+		result.Action, result.TicketID, result.ReplyID = domain.EmailActionThreaded, ticket.TicketID, reply.ReplyID
+	} else {
+		subject := truncateRunes(strings.TrimSpace(email.Subject), 200)
+		if utf8.RuneCountInString(subject) < 3 {
+			subject = emptyEmailSubject
+		}
+		if utf8.RuneCountInString(body) < 10 {
+			body = emptyEmailBody
+		}
+		created, err := s.CreateTicketFromEmail(ctx, Actor{SubjectID: actor.SubjectID, IdempotencyKey: idempotencyKey}, CreateFromEmailInput{
+			SenderEmail: sender,
+			Subject:     subject,
+			Description: truncateRunes(body, 5000),
+		})
+		if err != nil {
+			return InboundEmailResult{}, err
+		}
+		result.Action, result.TicketID = domain.EmailActionCreated, created.TicketID
+	}
+
+	if result.Attachments, err = s.storeAttachments(ctx, result.TicketID, result.ReplyID, email, now); err != nil {
+		return InboundEmailResult{}, err
+	}
+	err = s.emails.Add(ctx, domain.EmailMessage{
+		MessageID:  email.MessageID,
+		TicketID:   result.TicketID,
+		ReplyID:    result.ReplyID,
+		Sender:     sender,
+		ReceivedAt: now,
+	})
+	if err != nil && !errors.Is(err, domain.ErrConflict) {
+		return InboundEmailResult{}, err
+	}
+	return result, nil
+}
+
+func (s *Service) ListTicketAttachments(ctx context.Context, actor Actor, ticketID string) ([]domain.EmailAttachment, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if _, err := s.tickets.Get(ctx, ticketID); err != nil {
+		return nil, err
+	}
+	if s.attachments == nil {
+		return []domain.EmailAttachment{}, nil
+	}
+	return s.attachments.ListByTicket(ctx, ticketID)
+}
+
+// threadTicket finds the open ticket a message answers, by its reference
+// headers first and the subject token second. Only the ticket's own
+// requester can thread onto it; closed tickets get a fresh ticket.
+func (s *Service) threadTicket(ctx context.Context, email domain.InboundEmail, sender string) (domain.Ticket, bool, error) {
+	ids := slices.Clone(email.InReplyTo)
+	for i := len(email.References) - 1; i >= 0; i-- {
+		ids = append(ids, email.References[i])
+	}
+	var candidates []string
+	for _, id := range ids {
+		if msg, err := s.emails.Get(ctx, id); err == nil {
+			candidates = append(candidates, msg.TicketID)
+		} else if !errors.Is(err, domain.ErrNotFound) {
+			return domain.Ticket{}, false, err
+		}
+		if ticketID := domain.TicketIDFromMessageID(id); ticketID != "" {
+			candidates = append(candidates, ticketID)
+		}
+	}
+	if token := domain.TicketTokenFromSubject(email.Subject); token != "" {
+		candidates = append(candidates, token)
+	}
+	for _, ticketID := range candidates {
+		ticket, err := s.tickets.Get(ctx, ticketID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return domain.Ticket{}, false, err
+		}
+		if ticket.Status == "closed" || ticket.Status == "deleted" || !strings.EqualFold(ticket.UserID, sender) {
+			continue
+		}
+		return ticket, true, nil
+	}
+	return domain.Ticket{}, false, nil
+}
+
+func (s *Service) storeAttachments(ctx context.Context, ticketID, replyID string, email domain.InboundEmail, now time.Time) ([]domain.EmailAttachment, error) {
+	out := make([]domain.EmailAttachment, 0, len(email.Attachments))
+	for i, att := range email.Attachments {
+		sum := sha256.Sum256(att.Data)
+		row := domain.EmailAttachment{
+			AttachmentID: newID("att"),
+			TicketID:     ticketID,
+			ReplyID:      replyID,
+			MessageID:    email.MessageID,
+			Filename:     att.Filename,
+			ContentType:  att.ContentType,
+			Size:         int64(len(att.Data)),
+			SHA256:       hex.EncodeToString(sum[:]),
+			Status:       domain.AttachmentStatusRejected,
+			CreatedAt:    now,
+		}
+		contentType, reason := domain.CheckAttachment(att, s.cfg.MaxAttachmentBytes, s.cfg.AllowedAttachmentTypes)
+		switch {
+		case i >= maxAttachmentsPerEmail:
+			row.RejectReason = fmt.Sprintf("more than %d attachments", maxAttachmentsPerEmail)
+		case reason != "":
+			row.RejectReason = reason
+		case s.attachmentStore == nil:
+			row.RejectReason = "attachment storage is not configured"
+		default:
+			row.ContentType = contentType
+			row.StorageKey = ticketID + "/" + row.AttachmentID + "-" + safeFilename(att.Filename)
+			if err := s.attachmentStore.Put(ctx, row.StorageKey, att.Data); err != nil {
+				return nil, fmt.Errorf("store attachment %s: %w", att.Filename, err)
+			}
+			row.Status = domain.AttachmentStatusStored
+		}
+		if s.attachments != nil {
+			if err := s.attachments.Add(ctx, row); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// messageHost is the domain outbound Message-IDs are minted under.
+func (s *Service) messageHost() string {
+	if _, host, ok := strings.Cut(s.cfg.SupportAddress, "@"); ok && host != "" {
+		return host
+	}
+	return "support.m73.local"
+}
+
+func safeFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	out := strings.TrimLeft(b.String(), ".")
+	if out == "" {
+		out = "attachment"
+	}
+	return truncateRunes(out, 100)
+}
+
+func truncateRunes(v string, n int) string {
+	if utf8.RuneCountInString(v) <= n {
+		return v
+	}
+	return string([]rune(v)[:n])
+}
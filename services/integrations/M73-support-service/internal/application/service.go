@@ -20,8 +20,14 @@ type Service struct {
 	replies     ports.ReplyRepository
 	csat        ports.CSATRepository
 	agents      ports.AgentRepository
+	slaPolicies ports.SLAPolicyRepository
+	escalations ports.EscalationRuleRepository
+	emails      ports.EmailMessageRepository
+	attachments ports.AttachmentRepository
 	idempotency ports.IdempotencyRepository
 	nowFn       func() time.Time
+
+	attachmentStore ports.AttachmentStore
 }
 
 var idCounter uint64
@@ -32,7 +38,13 @@ type Dependencies struct {
 	Replies     ports.ReplyRepository
 	CSAT        ports.CSATRepository
 	Agents      ports.AgentRepository
+	SLAPolicies ports.SLAPolicyRepository
+	Escalations ports.EscalationRuleRepository
+	Emails      ports.EmailMessageRepository
+	Attachments ports.AttachmentRepository
 	Idempotency ports.IdempotencyRepository
+
+	AttachmentStore ports.AttachmentStore
 }
 
 func NewService(deps Dependencies) *Service {
@@ -42,12 +54,38 @@ func NewService(deps Dependencies) *Service {
 		replies:     deps.Replies,
 		csat:        deps.CSAT,
 		agents:      deps.Agents,
+		slaPolicies: deps.SLAPolicies,
+		escalations: deps.Escalations,
+		emails:      deps.Emails,
+		attachments: deps.Attachments,
 		idempotency: deps.Idempotency,
-		nowFn:       time.Now().UTC,
+		nowFn:       func() time.Time { return time.Now().UTC() },
+
+		attachmentStore: deps.AttachmentStore,
 	}
 	if s.cfg.IdempotencyTTL == 0 {
 		s.cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
+	if s.cfg.MaxOpenTicketsPerAgent == 0 {
+		s.cfg.MaxOpenTicketsPerAgent = 20
+	}
+	if s.cfg.MaxAttachmentBytes == 0 {
+		s.cfg.MaxAttachmentBytes = 10 << 20
+	}
+	if len(s.cfg.AllowedAttachmentTypes) == 0 {
+		s.cfg.AllowedAttachmentTypes = []string{
+			"image/png", "image/jpeg", "image/gif", "image/webp",
+			"application/pdf", "text/plain", "text/csv", "application/zip", "message/rfc822",
+		}
+	}
+	if s.cfg.BusinessHours == nil {
+		s.cfg.BusinessHours = &domain.BusinessHours{
+			Timezone: "UTC",
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			Start:    "09:00",
+			End:      "17:00",
+		}
+	}
 	return s
 }
 
@@ -93,12 +131,14 @@ func (s *Service) CreateTicket(ctx context.Context, actor Actor, input CreateTic
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
-	if agent, ok, err := s.pickAgent(ctx, category); err != nil {
+	if err := s.startSLA(ctx, &ticket, now); err != nil {
+		return domain.Ticket{}, err
+	}
+	if agent, ok, err := s.pickAgent(ctx, domain.AssignmentRequest{Category: category}); err != nil {
 		return domain.Ticket{}, err
 	} else if ok {
 		ticket.AssignedAgentID = agent.AgentID
-		agent.OpenTicketCount++
-		_ = s.agents.Upsert(ctx, agent)
+		s.adjustAgentLoad(ctx, agent.AgentID, 1)
 	}
 	if err := s.tickets.Create(ctx, ticket); err != nil {
 		return domain.Ticket{}, err
@@ -166,6 +206,8 @@ func (s *Service) UpdateTicket(ctx context.Context, actor Actor, ticketID string
 	if !ticket.DeletedAt.IsZero() || ticket.Status == "deleted" {
 		return domain.Ticket{}, domain.ErrNotFound
 	}
+	now := s.nowFn()
+	prevStatus := ticket.Status
 	if status := strings.TrimSpace(input.Status); status != "" {
 		status = normalizeTicketStatus(status)
 		if status == "" {
@@ -173,16 +215,20 @@ func (s *Service) UpdateTicket(ctx context.Context, actor Actor, ticketID string
 		}
 		ticket.Status = status
 		if status == "closed" {
-			ticket.ClosedAt = s.nowFn()
+			ticket.ClosedAt = now
 		}
 	}
 	if sub := strings.TrimSpace(input.SubStatus); sub != "" {
-		ticket.SubStatus = sub
+		ticket.SubStatus = normalizeSubStatus(sub)
 	}
-	if priority := strings.TrimSpace(input.Priority); priority != "" {
+	if priority := strings.TrimSpace(input.Priority); priority != "" && normalizePriority(priority) != ticket.Priority {
 		ticket.Priority = normalizePriority(priority)
+		if err := s.retargetSLA(ctx, &ticket, now); err != nil {
+			return domain.Ticket{}, err
+		}
 	}
-	ticket.UpdatedAt = s.nowFn()
+	s.syncTicketState(ctx, &ticket, prevStatus, now)
+	ticket.UpdatedAt = now
 	ticket.LastActivityAt = ticket.UpdatedAt
 	if err := s.tickets.Update(ctx, ticket); err != nil {
 		return domain.Ticket{}, err
@@ -201,8 +247,10 @@ func (s *Service) DeleteTicket(ctx context.Context, actor Actor, ticketID string
 	}
 	if ticket.Status != "deleted" {
 		now := s.nowFn()
+		prevStatus := ticket.Status
 		ticket.Status = "deleted"
 		ticket.DeletedAt = now
+		s.syncTicketState(ctx, &ticket, prevStatus, now)
 		ticket.UpdatedAt = now
 		ticket.LastActivityAt = now
 		if err := s.tickets.Update(ctx, ticket); err != nil {
@@ -240,7 +288,7 @@ func (s *Service) AssignTicket(ctx context.Context, actor Actor, ticketID string
 	if err != nil {
 		return domain.Ticket{}, err
 	}
-	ticket.AssignedAgentID = strings.TrimSpace(input.AgentID)
+	s.moveAssignment(ctx, &ticket, strings.TrimSpace(input.AgentID))
 	ticket.UpdatedAt = s.nowFn()
 	ticket.LastActivityAt = ticket.UpdatedAt
 	if err := s.tickets.Update(ctx, ticket); err != nil {
@@ -251,6 +299,13 @@ func (s *Service) AssignTicket(ctx context.Context, actor Actor, ticketID string
 }
 
 func (s *Service) AddReply(ctx context.Context, actor Actor, ticketID string, input AddReplyInput) (domain.TicketReply, error) {
+	return s.addReply(ctx, actor, ticketID, input, "")
+}
+
+// addReply records a reply; messageID is set for replies that arrived by
+// email. Public agent replies get the Message-ID their notification mail is
+// sent with so customer answers thread back.
+func (s *Service) addReply(ctx context.Context, actor Actor, ticketID string, input AddReplyInput, messageID string) (domain.TicketReply, error) {
 	if strings.TrimSpace(actor.SubjectID) == "" {
 		return domain.TicketReply{}, domain.ErrUnauthorized
 	}
@@ -287,11 +342,19 @@ func (s *Service) AddReply(ctx context.Context, actor Actor, ticketID string, in
 		AuthorID:  actor.SubjectID,
 		ReplyType: replyType,
 		Body:      body,
+		Channel:   "api",
 		CreatedAt: now,
 	}
+	if messageID != "" {
+		reply.Channel = "email"
+		reply.MessageID = messageID
+	} else if isAgent(actor.Role) && replyType == "public" {
+		reply.MessageID = domain.ReplyMessageID(ticket.TicketID, reply.ReplyID, s.messageHost())
+	}
 	if err := s.replies.Add(ctx, reply); err != nil {
 		return domain.TicketReply{}, err
 	}
+	prevStatus := ticket.Status
 	if ticket.Status == "resolved" && !isAgent(actor.Role) {
 		ticket.Status = "open"
 		ticket.SubStatus = "new"
@@ -299,9 +362,13 @@ func (s *Service) AddReply(ctx context.Context, actor Actor, ticketID string, in
 	if isAgent(actor.Role) && ticket.FirstResponseAt.IsZero() && replyType == "public" {
 		ticket.FirstResponseAt = now
 		ticket.SubStatus = "awaiting_response"
+		if ticket.SLA != nil {
+			ticket.SLA.Responded(now)
+		}
 	} else if !isAgent(actor.Role) {
 		ticket.SubStatus = "new"
 	}
+	s.syncTicketState(ctx, &ticket, prevStatus, now)
 	ticket.LastActivityAt = now
 	ticket.UpdatedAt = now
 	if err := s.tickets.Update(ctx, ticket); err != nil {
@@ -374,7 +441,8 @@ func (s *Service) completeIdempotent(ctx context.Context, key, requestHash strin
 	})
 }
 
-func (s *Service) pickAgent(ctx context.Context, category string) (domain.Agent, bool, error) {
+// pickAgent returns the best ranked agent for req.
+func (s *Service) pickAgent(ctx context.Context, req domain.AssignmentRequest) (domain.Agent, bool, error) {
 	if s.agents == nil {
 		return domain.Agent{}, false, nil
 	}
@@ -382,23 +450,70 @@ func (s *Service) pickAgent(ctx context.Context, category string) (domain.Agent,
 	if err != nil {
 		return domain.Agent{}, false, err
 	}
-	want := strings.ToLower(strings.TrimSpace(category))
-	for _, agent := range agents {
-		if !agent.Active || agent.OpenTicketCount >= 20 {
-			continue
+	req.MaxOpenTickets = s.cfg.MaxOpenTicketsPerAgent
+	ranked := domain.RankAgents(agents, req)
+	if len(ranked) == 0 {
+		return domain.Agent{}, false, nil
+	}
+	return ranked[0], true, nil
+}
+
+// moveAssignment assigns the ticket to agentID, moving the open load from
+// the previous assignee when the ticket still counts as open.
+func (s *Service) moveAssignment(ctx context.Context, ticket *domain.Ticket, agentID string) {
+	if ticket.AssignedAgentID == agentID {
+		return
+	}
+	if !isClosedStatus(ticket.Status) {
+		s.adjustAgentLoad(ctx, ticket.AssignedAgentID, -1)
+		s.adjustAgentLoad(ctx, agentID, 1)
+	}
+	ticket.AssignedAgentID = agentID
+}
+
+func (s *Service) adjustAgentLoad(ctx context.Context, agentID string, delta int) {
+	if s.agents == nil || agentID == "" {
+		return
+	}
+	agent, err := s.agents.Get(ctx, agentID)
+	if err != nil {
+		return
+	}
+	agent.OpenTicketCount = max(agent.OpenTicketCount+delta, 0)
+	_ = s.agents.Upsert(ctx, agent)
+}
+
+// syncTicketState follows a status or sub-status change with the SLA clocks
+// and the assignee's open load.
+func (s *Service) syncTicketState(ctx context.Context, ticket *domain.Ticket, prevStatus string, now time.Time) {
+	wasClosed, closed := isClosedStatus(prevStatus), isClosedStatus(ticket.Status)
+	switch {
+	case closed && !wasClosed:
+		s.adjustAgentLoad(ctx, ticket.AssignedAgentID, -1)
+		if ticket.Status == "resolved" {
+			ticket.ResolvedAt = now
 		}
-		for _, skill := range agent.SkillTags {
-			if strings.ToLower(strings.TrimSpace(skill)) == want {
-				return agent, true, nil
-			}
+		if ticket.SLA != nil {
+			ticket.SLA.Resolved(now)
 		}
-	}
-	for _, agent := range agents {
-		if agent.Active && agent.OpenTicketCount < 20 && strings.Contains(strings.ToLower(agent.Role), "agent") {
-			return agent, true, nil
+	case !closed && wasClosed:
+		s.adjustAgentLoad(ctx, ticket.AssignedAgentID, 1)
+		ticket.ResolvedAt = time.Time{}
+		if ticket.SLA != nil {
+			ticket.SLA.Reopened(now)
 		}
 	}
-	return domain.Agent{}, false, nil
+	if ticket.SLA == nil || closed {
+		return
+	}
+	if ticket.SubStatus == domain.SubStatusPendingCustomer {
+		ticket.SLA.Pause(now)
+	} else {
+		ticket.SLA.Resume(now)
+	}
+	if due := ticket.SLA.FirstResponse.DueAt; !due.IsZero() {
+		ticket.SLAResponseDueAt = due
+	}
 }
 
 func validateCreateTicket(input CreateTicketInput) error {
@@ -447,6 +562,20 @@ func normalizePriority(raw string) string {
 	}
 }
 
+// normalizeSubStatus keeps free-form sub-statuses but folds the spellings
+// of pending customer, which pauses SLA clocks.
+func normalizeSubStatus(raw string) string {
+	sub := strings.TrimSpace(raw)
+	if strings.ReplaceAll(strings.ToLower(sub), "-", "_") == domain.SubStatusPendingCustomer {
+		return domain.SubStatusPendingCustomer
+	}
+	return sub
+}
+
+func isClosedStatus(status string) bool {
+	return status == "resolved" || status == "closed" || status == "deleted"
+}
+
 func normalizeTicketStatus(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "open", "pending", "on_hold", "resolved", "closed", "deleted":
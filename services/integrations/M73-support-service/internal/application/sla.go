@@ -0,0 +1,477 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
+
+const (
+	defaultSLAReportWindow = 30 * 24 * time.Hour
+	maxSLAReportWindow     = 366 * 24 * time.Hour
+)
+
+func (s *Service) ListSLAPolicies(ctx context.Context, actor Actor) ([]domain.SLAPolicy, error) {
+	if err := requireManager(actor); err != nil {
+		return nil, err
+	}
+	if s.slaPolicies == nil {
+		return []domain.SLAPolicy{}, nil
+	}
+	return s.slaPolicies.List(ctx)
+}
+
+// UpsertSLAPolicy creates a policy or replaces the one named by PolicyID.
+// Open tickets keep the targets they started with.
+func (s *Service) UpsertSLAPolicy(ctx context.Context, actor Actor, input UpsertSLAPolicyInput) (domain.SLAPolicy, error) {
+	if err := requireManager(actor); err != nil {
+		return domain.SLAPolicy{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.SLAPolicy{}, domain.ErrIdempotencyRequired
+	}
+	if s.slaPolicies == nil {
+		return domain.SLAPolicy{}, domain.ErrNotFound
+	}
+	requestHash := hashJSON(input)
+	if rec, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.SLAPolicy{}, err
+	} else if ok {
+		var policy domain.SLAPolicy
+		_ = json.Unmarshal(rec, &policy)
+		return policy, nil
+	}
+	now := s.nowFn()
+	policy := domain.SLAPolicy{PolicyID: newID("sla"), CreatedAt: now, Active: true}
+	if id := strings.TrimSpace(input.PolicyID); id != "" {
+		existing, err := s.slaPolicies.Get(ctx, id)
+		if err != nil {
+			return domain.SLAPolicy{}, err
+		}
+		policy = existing
+	}
+	policy.Name = strings.TrimSpace(input.Name)
+	policy.Priority = ""
+	if p := strings.TrimSpace(input.Priority); p != "" {
+		policy.Priority = normalizePriority(p)
+	}
+	policy.Category = ""
+	if c := strings.TrimSpace(input.Category); c != "" {
+		if policy.Category = normalizeCategory(c); policy.Category == "" {
+			return domain.SLAPolicy{}, fmt.Errorf("%w: unknown category %q", domain.ErrInvalidInput, c)
+		}
+	}
+	policy.FirstResponseTarget = input.FirstResponseTarget
+	policy.ResolutionTarget = input.ResolutionTarget
+	policy.BusinessHoursOnly = input.BusinessHoursOnly
+	policy.BusinessHours = input.BusinessHours
+	if input.Active != nil {
+		policy.Active = *input.Active
+	}
+	policy.UpdatedAt = now
+	if err := policy.Validate(); err != nil {
+		return domain.SLAPolicy{}, err
+	}
+	if err := s.slaPolicies.Upsert(ctx, policy); err != nil {
+		return domain.SLAPolicy{}, err
+	}
+	_ = s.completeIdempotent(ctx, actor.IdempotencyKey, requestHash, policy)
+	return policy, nil
+}
+
+func (s *Service) ListEscalationRules(ctx context.Context, actor Actor) ([]domain.EscalationRule, error) {
+	if err := requireManager(actor); err != nil {
+		return nil, err
+	}
+	if s.escalations == nil {
+		return []domain.EscalationRule{}, nil
+	}
+	return s.escalations.List(ctx)
+}
+
+func (s *Service) UpsertEscalationRule(ctx context.Context, actor Actor, input UpsertEscalationRuleInput) (domain.EscalationRule, error) {
+	if err := requireManager(actor); err != nil {
+		return domain.EscalationRule{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.EscalationRule{}, domain.ErrIdempotencyRequired
+	}
+	if s.escalations == nil {
+		return domain.EscalationRule{}, domain.ErrNotFound
+	}
+	requestHash := hashJSON(input)
+	if rec, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.EscalationRule{}, err
+	} else if ok {
+		var rule domain.EscalationRule
+		_ = json.Unmarshal(rec, &rule)
+		return rule, nil
+	}
+	now := s.nowFn()
+	rule := domain.EscalationRule{RuleID: newID("esc"), CreatedAt: now, Active: true}
+	if id := strings.TrimSpace(input.RuleID); id != "" {
+		existing, err := s.escalations.Get(ctx, id)
+		if err != nil {
+			return domain.EscalationRule{}, err
+		}
+		rule = existing
+	}
+	rule.Name = strings.TrimSpace(input.Name)
+	rule.Clock = strings.ToLower(strings.TrimSpace(input.Clock))
+	rule.Action = strings.ToLower(strings.TrimSpace(input.Action))
+	rule.Before = input.Before
+	rule.TargetRole = strings.ToLower(strings.TrimSpace(input.TargetRole))
+	rule.Priority = ""
+	if p := strings.TrimSpace(input.Priority); p != "" {
+		rule.Priority = normalizePriority(p)
+	}
+	rule.Category = ""
+	if c := strings.TrimSpace(input.Category); c != "" {
+		if rule.Category = normalizeCategory(c); rule.Category == "" {
+			return domain.EscalationRule{}, fmt.Errorf("%w: unknown category %q", domain.ErrInvalidInput, c)
+		}
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+	rule.UpdatedAt = now
+	if err := rule.Validate(); err != nil {
+		return domain.EscalationRule{}, err
+	}
+	if err := s.escalations.Upsert(ctx, rule); err != nil {
+		return domain.EscalationRule{}, err
+	}
+	_ = s.completeIdempotent(ctx, actor.IdempotencyKey, requestHash, rule)
+	return rule, nil
+}
+
+// AutoAssignTicket hands the ticket to the best ranked agent other than its
+// current assignee.
+func (s *Service) AutoAssignTicket(ctx context.Context, actor Actor, ticketID string) (domain.Ticket, error) {
+	if err := requireManager(actor); err != nil {
+		return domain.Ticket{}, err
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.Ticket{}, domain.ErrIdempotencyRequired
+	}
+	requestHash := hashJSON(map[string]any{"ticket_id": strings.TrimSpace(ticketID), "action": "auto_assign"})
+	if rec, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Ticket{}, err
+	} else if ok {
+		var ticket domain.Ticket
+		_ = json.Unmarshal(rec, &ticket)
+		return ticket, nil
+	}
+	ticket, err := s.tickets.Get(ctx, ticketID)
+	if err != nil {
+		return domain.Ticket{}, err
+	}
+	if isClosedStatus(ticket.Status) {
+		return domain.Ticket{}, fmt.Errorf("%w: ticket is %s", domain.ErrConflict, ticket.Status)
+	}
+	agent, ok, err := s.pickAgent(ctx, domain.AssignmentRequest{Category: ticket.Category, ExcludeAgentID: ticket.AssignedAgentID})
+	if err != nil {
+		return domain.Ticket{}, err
+	}
+	if !ok {
+		return domain.Ticket{}, fmt.Errorf("%w: no agent has capacity for %s", domain.ErrConflict, ticket.Category)
+	}
+	s.moveAssignment(ctx, &ticket, agent.AgentID)
+	ticket.UpdatedAt = s.nowFn()
+	ticket.LastActivityAt = ticket.UpdatedAt
+	if err := s.tickets.Update(ctx, ticket); err != nil {
+		return domain.Ticket{}, err
+	}
+	_ = s.completeIdempotent(ctx, actor.IdempotencyKey, requestHash, ticket)
+	return ticket, nil
+}
+
+// RunSLASweep marks breached clocks on open tickets and fires escalation
+// rules whose threshold has been reached. Each rule fires at most once per
+// ticket.
+func (s *Service) RunSLASweep(ctx context.Context) (SLASweepResult, error) {
+	tickets, err := s.tickets.ListActive(ctx)
+	if err != nil {
+		return SLASweepResult{}, err
+	}
+	var rules []domain.EscalationRule
+	if s.escalations != nil {
+		if rules, err = s.escalations.List(ctx); err != nil {
+			return SLASweepResult{}, err
+		}
+	}
+	now := s.nowFn()
+	var result SLASweepResult
+	for _, ticket := range tickets {
+		if ticket.SLA == nil {
+			continue
+		}
+		result.Checked++
+		breached := ticket.SLA.CheckBreaches(now)
+		result.Breached += len(breached)
+		changed := len(breached) > 0
+		for _, rule := range rules {
+			if !rule.Matches(ticket) || ticket.SLA.Escalated(rule.RuleID) {
+				continue
+			}
+			clock := ticket.SLA.Clock(rule.Clock)
+			if clock == nil || !clock.Running() || clock.Remaining(now, ticket.SLA.BusinessHours) > rule.Before {
+				continue
+			}
+			detail, err := s.escalate(ctx, &ticket, rule)
+			if err != nil {
+				return result, err
+			}
+			ticket.SLA.Escalations = append(ticket.SLA.Escalations, domain.SLAEscalation{
+				RuleID:  rule.RuleID,
+				Clock:   rule.Clock,
+				Action:  rule.Action,
+				Detail:  detail,
+				FiredAt: now,
+			})
+			result.Escalated++
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		ticket.UpdatedAt = now
+		if err := s.tickets.Update(ctx, ticket); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// escalate applies rule to the ticket and describes what changed. A bumped
+// priority keeps the ticket's SLA targets so the escalation does not itself
+// cause a breach.
+func (s *Service) escalate(ctx context.Context, ticket *domain.Ticket, rule domain.EscalationRule) (string, error) {
+	switch rule.Action {
+	case domain.EscalationActionBumpPriority:
+		next := domain.NextPriority(ticket.Priority)
+		if next == ticket.Priority {
+			return "priority already " + next, nil
+		}
+		detail := fmt.Sprintf("priority %s -> %s", ticket.Priority, next)
+		ticket.Priority = next
+		return detail, nil
+	case domain.EscalationActionReassign:
+		agent, ok, err := s.pickAgent(ctx, domain.AssignmentRequest{
+			Category:       ticket.Category,
+			ExcludeAgentID: ticket.AssignedAgentID,
+			Role:           rule.TargetRole,
+		})
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "no agent available", nil
+		}
+		detail := fmt.Sprintf("reassigned %s -> %s", valueOr(ticket.AssignedAgentID, "unassigned"), agent.AgentID)
+		s.moveAssignment(ctx, ticket, agent.AgentID)
+		return detail, nil
+	}
+	return "", fmt.Errorf("%w: unknown escalation action %q", domain.ErrInvalidInput, rule.Action)
+}
+
+// GetSLAReport summarises SLA compliance and CSAT for tickets created in
+// [from, to). Zero bounds default to the last 30 days.
+func (s *Service) GetSLAReport(ctx context.Context, actor Actor, from, to time.Time) (SLAReport, error) {
+	if err := requireManager(actor); err != nil {
+		return SLAReport{}, err
+	}
+	now := s.nowFn()
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultSLAReportWindow)
+	}
+	if !from.Before(to) || to.Sub(from) > maxSLAReportWindow {
+		return SLAReport{}, fmt.Errorf("%w: report window must be positive and at most 366 days", domain.ErrInvalidInput)
+	}
+	tickets, err := s.tickets.ListCreatedBetween(ctx, from, to)
+	if err != nil {
+		return SLAReport{}, err
+	}
+
+	overall := newReportAccumulator("all")
+	byPriority := map[string]*reportAccumulator{}
+	byCategory := map[string]*reportAccumulator{}
+	for _, ticket := range tickets {
+		if ticket.Status == "deleted" {
+			continue
+		}
+		var ratings []domain.CSATRating
+		if s.csat != nil {
+			if ratings, err = s.csat.ListByTicket(ctx, ticket.TicketID); err != nil {
+				return SLAReport{}, err
+			}
+		}
+		for _, acc := range []*reportAccumulator{
+			overall,
+			accumulatorFor(byPriority, ticket.Priority),
+			accumulatorFor(byCategory, ticket.Category),
+		} {
+			acc.add(ticket, ratings, now)
+		}
+	}
+	return SLAReport{
+		From:       from,
+		To:         to,
+		Overall:    overall.row(),
+		ByPriority: reportRows(byPriority),
+		ByCategory: reportRows(byCategory),
+	}, nil
+}
+
+type reportAccumulator struct {
+	out           SLAReportRow
+	firstResponse time.Duration
+	responded     int
+	resolution    time.Duration
+	resolved      int
+	csatTotal     int
+}
+
+func newReportAccumulator(key string) *reportAccumulator {
+	return &reportAccumulator{out: SLAReportRow{Key: key}}
+}
+
+func accumulatorFor(m map[string]*reportAccumulator, key string) *reportAccumulator {
+	acc, ok := m[key]
+	if !ok {
+		acc = newReportAccumulator(key)
+		m[key] = acc
+	}
+	return acc
+}
+
+func (a *reportAccumulator) add(ticket domain.Ticket, ratings []domain.CSATRating, now time.Time) {
+	a.out.Tickets++
+	for _, r := range ratings {
+		a.out.CSATResponses++
+		a.csatTotal += r.Rating
+	}
+	if ticket.SLA == nil {
+		return
+	}
+	a.out.Escalations += len(ticket.SLA.Escalations)
+	if countClock(&a.out.FirstResponse, ticket.SLA.FirstResponse, now) {
+		a.firstResponse += ticket.SLA.FirstResponse.Elapsed
+		a.responded++
+	}
+	if countClock(&a.out.Resolution, ticket.SLA.Resolution, now) {
+		a.resolution += ticket.SLA.Resolution.Elapsed
+		a.resolved++
+	}
+}
+
+// countClock adds one clock to stat and reports whether it has completed.
+// A running clock past its deadline counts as breached even before the
+// sweep has marked it.
+func countClock(stat *SLAComplianceStat, clock domain.SLAClock, now time.Time) bool {
+	breached := clock.Breached() || (clock.Running() && !now.Before(clock.DueAt))
+	switch {
+	case breached:
+		stat.Breached++
+	case clock.Completed():
+		stat.Met++
+	default:
+		stat.Pending++
+	}
+	return clock.Completed()
+}
+
+func (a *reportAccumulator) row() SLAReportRow {
+	out := a.out
+	out.FirstResponse.ComplianceRate = complianceRate(out.FirstResponse)
+	out.Resolution.ComplianceRate = complianceRate(out.Resolution)
+	if a.responded > 0 {
+		out.AvgFirstResponseMinutes = (a.firstResponse / time.Duration(a.responded)).Minutes()
+	}
+	if a.resolved > 0 {
+		out.AvgResolutionMinutes = (a.resolution / time.Duration(a.resolved)).Minutes()
+	}
+	if out.CSATResponses > 0 {
+		out.CSATAverage = float64(a.csatTotal) / float64(out.CSATResponses)
+	}
+	return out
+}
+
+func complianceRate(stat SLAComplianceStat) float64 {
+	if decided := stat.Met + stat.Breached; decided > 0 {
+		return float64(stat.Met) / float64(decided)
+	}
+	return 0
+}
+
+func reportRows(m map[string]*reportAccumulator) []SLAReportRow {
+	out := make([]SLAReportRow, 0, len(m))
+	for _, acc := range m {
+		out = append(out, acc.row())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// startSLA attaches the matching policy's clocks to a new ticket. Tickets
+// matching no active policy keep only the legacy response due date.
+func (s *Service) startSLA(ctx context.Context, ticket *domain.Ticket, now time.Time) error {
+	policy, ok, err := s.matchPolicy(ctx, ticket.Priority, ticket.Category)
+	if err != nil || !ok {
+		return err
+	}
+	ticket.SLA = domain.NewTicketSLA(policy, policy.Calendar(s.cfg.BusinessHours), now)
+	ticket.SLAResponseDueAt = ticket.SLA.FirstResponse.DueAt
+	return nil
+}
+
+// retargetSLA moves an open ticket onto the policy of its new priority,
+// keeping the time already spent.
+func (s *Service) retargetSLA(ctx context.Context, ticket *domain.Ticket, now time.Time) error {
+	if ticket.SLA == nil {
+		return s.startSLA(ctx, ticket, now)
+	}
+	policy, ok, err := s.matchPolicy(ctx, ticket.Priority, ticket.Category)
+	if err != nil || !ok {
+		return err
+	}
+	ticket.SLA.Retarget(policy, now)
+	return nil
+}
+
+func (s *Service) matchPolicy(ctx context.Context, priority, category string) (domain.SLAPolicy, bool, error) {
+	if s.slaPolicies == nil {
+		return domain.SLAPolicy{}, false, nil
+	}
+	policies, err := s.slaPolicies.List(ctx)
+	if err != nil {
+		return domain.SLAPolicy{}, false, err
+	}
+	policy, ok := domain.MatchSLAPolicy(policies, priority, category)
+	return policy, ok, nil
+}
+
+func requireManager(actor Actor) error {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ErrUnauthorized
+	}
+	if !isManager(actor.Role) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
@@ -1,11 +1,26 @@
 package application
 
-import "time"
+import (
+	"time"
+
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
+)
 
 type Config struct {
 	ServiceName    string
 	Version        string
 	IdempotencyTTL time.Duration
+	// MaxOpenTicketsPerAgent stops auto-assignment to agents at capacity.
+	MaxOpenTicketsPerAgent int
+	// BusinessHours is the calendar of policies that count business hours
+	// without defining their own.
+	BusinessHours *domain.BusinessHours
+	// SupportAddress is the desk's own mailbox; mail from it or carrying
+	// it in X-Loop is a loop. Its domain names outbound Message-IDs.
+	SupportAddress            string
+	MaxAttachmentBytes        int64
+	AllowedAttachmentTypes    []string
+	MaxEmailsPerSenderPerHour int
 }
 
 type Actor struct {
@@ -60,3 +75,73 @@ type SearchTicketsInput struct {
 	AssignedTo string
 	Limit      int
 }
+
+type UpsertSLAPolicyInput struct {
+	PolicyID            string                `json:"policy_id,omitempty"`
+	Name                string                `json:"name"`
+	Priority            string                `json:"priority,omitempty"`
+	Category            string                `json:"category,omitempty"`
+	FirstResponseTarget time.Duration         `json:"first_response_target"`
+	ResolutionTarget    time.Duration         `json:"resolution_target"`
+	BusinessHoursOnly   bool                  `json:"business_hours_only"`
+	BusinessHours       *domain.BusinessHours `json:"business_hours,omitempty"`
+	Active              *bool                 `json:"active,omitempty"`
+}
+
+type UpsertEscalationRuleInput struct {
+	RuleID     string        `json:"rule_id,omitempty"`
+	Name       string        `json:"name"`
+	Clock      string        `json:"clock"`
+	Priority   string        `json:"priority,omitempty"`
+	Category   string        `json:"category,omitempty"`
+	Before     time.Duration `json:"before"`
+	Action     string        `json:"action"`
+	TargetRole string        `json:"target_role,omitempty"`
+	Active     *bool         `json:"active,omitempty"`
+}
+
+type SLASweepResult struct {
+	Checked   int `json:"checked"`
+	Breached  int `json:"breached"`
+	Escalated int `json:"escalated"`
+}
+
+// SLAComplianceStat counts one SLA clock across tickets. ComplianceRate is
+// Met over Met plus Breached and stays zero until a clock is decided.
+type SLAComplianceStat struct {
+	Met            int     `json:"met"`
+	Breached       int     `json:"breached"`
+	Pending        int     `json:"pending"`
+	ComplianceRate float64 `json:"compliance_rate"`
+}
+
+type SLAReportRow struct {
+	Key                     string            `json:"key"`
+	Tickets                 int               `json:"tickets"`
+	FirstResponse           SLAComplianceStat `json:"first_response"`
+	Resolution              SLAComplianceStat `json:"resolution"`
+	AvgFirstResponseMinutes float64           `json:"avg_first_response_minutes"`
+	AvgResolutionMinutes    float64           `json:"avg_resolution_minutes"`
+	Escalations             int               `json:"escalations"`
+	CSATResponses           int               `json:"csat_responses"`
+	CSATAverage             float64           `json:"csat_average"`
+}
+
+// SLAReport covers tickets created in [From, To). Averages are business
+// time on each ticket's SLA calendar.
+type SLAReport struct {
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Overall    SLAReportRow   `json:"overall"`
+	ByPriority []SLAReportRow `json:"by_priority"`
+	ByCategory []SLAReportRow `json:"by_category"`
+}
+
+type InboundEmailResult struct {
+	Action      string                   `json:"action"`
+	Reason      string                   `json:"reason,omitempty"`
+	MessageID   string                   `json:"message_id"`
+	TicketID    string                   `json:"ticket_id,omitempty"`
+	ReplyID     string                   `json:"reply_id,omitempty"`
+	Attachments []domain.EmailAttachment `json:"attachments,omitempty"`
+}
@@ -56,3 +56,35 @@ type SubmitCSATRequest struct {
 	Rating          int    `json:"rating"`
 	FeedbackComment string `json:"feedback_comment,omitempty"`
 }
+
+type BusinessHoursRequest struct {
+	Timezone string   `json:"timezone"`
+	Weekdays []int    `json:"weekdays"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Holidays []string `json:"holidays,omitempty"`
+}
+
+type UpsertSLAPolicyRequest struct {
+	PolicyID             string                `json:"policy_id,omitempty"`
+	Name                 string                `json:"name"`
+	Priority             string                `json:"priority,omitempty"`
+	Category             string                `json:"category,omitempty"`
+	FirstResponseMinutes int                   `json:"first_response_minutes"`
+	ResolutionMinutes    int                   `json:"resolution_minutes"`
+	BusinessHoursOnly    bool                  `json:"business_hours_only"`
+	BusinessHours        *BusinessHoursRequest `json:"business_hours,omitempty"`
+	Active               *bool                 `json:"active,omitempty"`
+}
+
+type UpsertEscalationRuleRequest struct {
+	RuleID        string `json:"rule_id,omitempty"`
+	Name          string `json:"name"`
+	Clock         string `json:"clock"`
+	Priority      string `json:"priority,omitempty"`
+	Category      string `json:"category,omitempty"`
+	BeforeMinutes int    `json:"before_minutes"`
+	Action        string `json:"action"`
+	TargetRole    string `json:"target_role,omitempty"`
+	Active        *bool  `json:"active,omitempty"`
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	EmailActionCreated   = "created"
+	EmailActionThreaded  = "threaded"
+	EmailActionIgnored   = "ignored"
+	EmailActionDuplicate = "duplicate"
+)
+
+const (
+	AttachmentStatusStored   = "stored"
+	AttachmentStatusRejected = "rejected"
+)
+
+// InboundEmail is a parsed RFC 5322 message. Addresses are lower-cased and
+// message ids keep their angle brackets.
+type InboundEmail struct {
+	MessageID   string
+	InReplyTo   []string
+	References  []string
+	From        string
+	FromName    string
+	To          []string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Headers     map[string][]string
+	Attachments []InboundAttachment
+	ReceivedAt  time.Time
+}
+
+type InboundAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// EmailAttachment is the stored or rejected copy of an inbound attachment.
+type EmailAttachment struct {
+	AttachmentID string    `json:"attachment_id"`
+	TicketID     string    `json:"ticket_id"`
+	ReplyID      string    `json:"reply_id,omitempty"`
+	MessageID    string    `json:"message_id"`
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	StorageKey   string    `json:"-"`
+	Status       string    `json:"status"`
+	RejectReason string    `json:"reject_reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// EmailMessage links a processed inbound message to the ticket it landed on,
+// for de-duplication, threading and per-sender loop limits.
+type EmailMessage struct {
+	MessageID  string    `json:"message_id"`
+	TicketID   string    `json:"ticket_id"`
+	ReplyID    string    `json:"reply_id,omitempty"`
+	Sender     string    `json:"sender"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+var (
+	ticketTokenPattern = regexp.MustCompile(`\[(TKT-\d{8}-[0-9a-z]+)\]`)
+	// Outbound reply ids read <reply-id.ticket-id@domain>.
+	replyMessageIDPattern = regexp.MustCompile(`^<[^.<>@]+\.(TKT-\d{8}-[0-9a-z]+)@[^<>]+>$`)
+	quoteHeaderPattern    = regexp.MustCompile(`(?i)^(on\s.+wrote:|am\s.+schrieb.*:|le\s.+a\s+écrit\s*:|-+\s*original message\s*-+|_{5,}|from:\s.+)$`)
+)
+
+// TicketTokenFromSubject returns the ticket id carried as "[TKT-...]" in a
+// subject line.
+func TicketTokenFromSubject(subject string) string {
+	if m := ticketTokenPattern.FindStringSubmatch(subject); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// SubjectWithTicketToken tags an outbound subject so replies thread even
+// when clients drop the reference headers.
+func SubjectWithTicketToken(subject, ticketID string) string {
+	if TicketTokenFromSubject(subject) == ticketID {
+		return subject
+	}
+	return strings.TrimSpace(subject) + " [" + ticketID + "]"
+}
+
+// ReplyMessageID is the Message-ID outbound mail for a reply is sent with.
+func ReplyMessageID(ticketID, replyID, host string) string {
+	return "<" + replyID + "." + ticketID + "@" + host + ">"
+}
+
+// TicketIDFromMessageID recovers the ticket of a ReplyMessageID.
+func TicketIDFromMessageID(id string) string {
+	if m := replyMessageIDPattern.FindStringSubmatch(strings.TrimSpace(id)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// AutoResponseReason reports why a message looks machine generated, or ""
+// for a message written by a person. Such mail never opens or updates
+// tickets so auto-replies cannot ping-pong with the desk.
+func AutoResponseReason(email InboundEmail, supportAddress string) string {
+	header := func(name string) string {
+		if v := email.Headers[http.CanonicalHeaderKey(name)]; len(v) > 0 {
+			return strings.ToLower(strings.TrimSpace(v[0]))
+		}
+		return ""
+	}
+	if v := header("Auto-Submitted"); v != "" && v != "no" {
+		return "auto-submitted: " + v
+	}
+	switch header("Precedence") {
+	case "bulk", "junk", "list", "auto_reply":
+		return "precedence: " + header("Precedence")
+	}
+	for _, name := range []string{"X-Autoreply", "X-Autorespond", "X-Auto-Response-Suppress", "List-Id", "List-Unsubscribe", "Feedback-ID"} {
+		if header(name) != "" {
+			return "header " + strings.ToLower(name)
+		}
+	}
+	if supportAddress != "" {
+		if header("X-Loop") == strings.ToLower(supportAddress) || email.From == strings.ToLower(supportAddress) {
+			return "mail loop: sent by this support desk"
+		}
+	}
+	if header("Return-Path") == "<>" {
+		return "bounce: empty return path"
+	}
+	local, _, _ := strings.Cut(email.From, "@")
+	switch local {
+	case "mailer-daemon", "postmaster", "noreply", "no-reply", "donotreply", "do-not-reply":
+		return "sender " + local
+	}
+	return ""
+}
+
+// StripQuotedReply drops the quoted history and signature from a reply,
+// keeping what the sender wrote on top. A message that is only a quote is
+// returned unchanged.
+func StripQuotedReply(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if line == "-- " || trimmed == "--" {
+			break
+		}
+		if quoteHeaderPattern.MatchString(trimmed) {
+			break
+		}
+		// Gmail wraps long "On ... wrote:" lines in two.
+		if strings.HasPrefix(strings.ToLower(trimmed), "on ") && i+1 < len(lines) && strings.HasSuffix(strings.TrimSpace(lines[i+1]), "wrote:") {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		out = append(out, line)
+	}
+	stripped := strings.TrimSpace(strings.Join(out, "\n"))
+	if stripped == "" {
+		return strings.TrimSpace(text)
+	}
+	return stripped
+}
+
+var blockedAttachmentExtensions = map[string]bool{
+	".exe": true, ".bat": true, ".cmd": true, ".com": true, ".scr": true, ".js": true,
+	".vbs": true, ".msi": true, ".jar": true, ".ps1": true, ".sh": true, ".dll": true,
+}
+
+// CheckAttachment validates an inbound attachment against the size cap and
+// the allowed MIME types, comparing the declared type with the sniffed
+// content. It returns the effective type, or a reason to reject.
+func CheckAttachment(att InboundAttachment, maxBytes int64, allowed []string) (string, string) {
+	if maxBytes > 0 && int64(len(att.Data)) > maxBytes {
+		return "", "attachment exceeds size limit"
+	}
+	if blockedAttachmentExtensions[strings.ToLower(path.Ext(att.Filename))] {
+		return "", "executable attachments are not accepted"
+	}
+	sniffed, _, _ := strings.Cut(http.DetectContentType(att.Data), ";")
+	declared := strings.ToLower(strings.TrimSpace(att.ContentType))
+	if declared == "" || declared == "application/octet-stream" {
+		declared = sniffed
+	}
+	allowedType := false
+	for _, a := range allowed {
+		if strings.EqualFold(a, declared) {
+			allowedType = true
+			break
+		}
+	}
+	if !allowedType {
+		return "", "content type " + declared + " is not allowed"
+	}
+	family, _, _ := strings.Cut(declared, "/")
+	switch {
+	case family == "image" || declared == "application/pdf" || declared == "application/zip":
+		if sniffed != declared && !(family == "image" && strings.HasPrefix(sniffed, "image/")) {
+			return "", "content does not match declared type " + declared
+		}
+	case family == "text":
+		if !strings.HasPrefix(sniffed, "text/") {
+			return "", "content does not match declared type " + declared
+		}
+	}
+	return declared, ""
+}
@@ -3,33 +3,39 @@ package domain
 import "time"
 
 type Ticket struct {
-	TicketID         string    `json:"ticket_id"`
-	UserID           string    `json:"user_id"`
-	Subject          string    `json:"subject"`
-	Description      string    `json:"description"`
-	Category         string    `json:"category"`
-	Priority         string    `json:"priority"`
-	Status           string    `json:"status"`
-	SubStatus        string    `json:"sub_status"`
-	Channel          string    `json:"channel"`
-	EntityType       string    `json:"entity_type,omitempty"`
-	EntityID         string    `json:"entity_id,omitempty"`
-	AssignedAgentID  string    `json:"assigned_agent_id,omitempty"`
-	SLAResponseDueAt time.Time `json:"sla_response_due_at"`
-	FirstResponseAt  time.Time `json:"first_response_at,omitempty"`
-	ClosedAt         time.Time `json:"closed_at,omitempty"`
-	DeletedAt        time.Time `json:"deleted_at,omitempty"`
-	LastActivityAt   time.Time `json:"last_activity_at"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	TicketID         string     `json:"ticket_id"`
+	UserID           string     `json:"user_id"`
+	Subject          string     `json:"subject"`
+	Description      string     `json:"description"`
+	Category         string     `json:"category"`
+	Priority         string     `json:"priority"`
+	Status           string     `json:"status"`
+	SubStatus        string     `json:"sub_status"`
+	Channel          string     `json:"channel"`
+	EntityType       string     `json:"entity_type,omitempty"`
+	EntityID         string     `json:"entity_id,omitempty"`
+	AssignedAgentID  string     `json:"assigned_agent_id,omitempty"`
+	SLAResponseDueAt time.Time  `json:"sla_response_due_at"`
+	SLA              *TicketSLA `json:"sla,omitempty"`
+	FirstResponseAt  time.Time  `json:"first_response_at,omitempty"`
+	ResolvedAt       time.Time  `json:"resolved_at,omitempty"`
+	ClosedAt         time.Time  `json:"closed_at,omitempty"`
+	DeletedAt        time.Time  `json:"deleted_at,omitempty"`
+	LastActivityAt   time.Time  `json:"last_activity_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
+// TicketReply.MessageID threads email: it is the id of the inbound message,
+// or the id outbound mail for a public agent reply is sent with.
 type TicketReply struct {
 	ReplyID   string    `json:"reply_id"`
 	TicketID  string    `json:"ticket_id"`
 	AuthorID  string    `json:"author_id"`
 	ReplyType string    `json:"reply_type"`
 	Body      string    `json:"body"`
+	Channel   string    `json:"channel,omitempty"`
+	MessageID string    `json:"message_id,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// AssignmentRequest describes the agent a ticket needs.
+type AssignmentRequest struct {
+	Category string
+	// MaxOpenTickets caps an agent's load; zero means no cap.
+	MaxOpenTickets int
+	// ExcludeAgentID skips the current assignee when reassigning.
+	ExcludeAgentID string
+	// Role, when set, only considers agents holding that role.
+	Role string
+}
+
+// RankAgents orders the agents able to take a ticket. Agents whose skill
+// tags match the category come first, then any other agent-role member;
+// within each group the lightest open load wins.
+func RankAgents(agents []Agent, req AssignmentRequest) []Agent {
+	want := strings.ToLower(strings.TrimSpace(req.Category))
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	type candidate struct {
+		agent Agent
+		tier  int
+	}
+	candidates := make([]candidate, 0, len(agents))
+	for _, agent := range agents {
+		if !agent.Active || agent.AgentID == req.ExcludeAgentID {
+			continue
+		}
+		if req.MaxOpenTickets > 0 && agent.OpenTicketCount >= req.MaxOpenTickets {
+			continue
+		}
+		agentRole := strings.ToLower(agent.Role)
+		if role != "" && agentRole != role {
+			continue
+		}
+		switch {
+		case agent.HasSkill(want):
+			candidates = append(candidates, candidate{agent: agent, tier: 0})
+		case strings.Contains(agentRole, "agent"):
+			candidates = append(candidates, candidate{agent: agent, tier: 1})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if a.agent.OpenTicketCount != b.agent.OpenTicketCount {
+			return a.agent.OpenTicketCount < b.agent.OpenTicketCount
+		}
+		return a.agent.AgentID < b.agent.AgentID
+	})
+	out := make([]Agent, len(candidates))
+	for i, c := range candidates {
+		out[i] = c.agent
+	}
+	return out
+}
+
+func (a Agent) HasSkill(skill string) bool {
+	if skill == "" {
+		return false
+	}
+	for _, tag := range a.SkillTags {
+		if strings.ToLower(strings.TrimSpace(tag)) == skill {
+			return true
+		}
+	}
+	return false
+}
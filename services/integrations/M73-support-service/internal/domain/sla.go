@@ -0,0 +1,499 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const SubStatusPendingCustomer = "pending_customer"
+
+// SLA clock names, used by escalation rules and the compliance report.
+const (
+	SLAClockFirstResponse = "first_response"
+	SLAClockResolution    = "resolution"
+)
+
+// maxCalendarDays bounds the day-by-day walk of business hours so a
+// calendar without working days cannot loop forever.
+const maxCalendarDays = 3 * 366
+
+// BusinessHours is the working calendar an SLA clock runs on. A nil
+// calendar means the clock runs around the clock.
+type BusinessHours struct {
+	Timezone string         `json:"timezone"`
+	Weekdays []time.Weekday `json:"weekdays"`
+	// Start and End are the opening and closing time, "HH:MM" local time.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Holidays are closed dates, "YYYY-MM-DD" local time.
+	Holidays []string `json:"holidays,omitempty"`
+}
+
+func (b *BusinessHours) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if _, err := time.LoadLocation(b.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidInput, b.Timezone)
+	}
+	if len(b.Weekdays) == 0 {
+		return fmt.Errorf("%w: business hours need at least one weekday", ErrInvalidInput)
+	}
+	for _, d := range b.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return fmt.Errorf("%w: invalid weekday %d", ErrInvalidInput, d)
+		}
+	}
+	start, okStart := clockMinutes(b.Start)
+	end, okEnd := clockMinutes(b.End)
+	if !okStart || !okEnd || start >= end {
+		return fmt.Errorf("%w: business hours need start before end as HH:MM", ErrInvalidInput)
+	}
+	for _, h := range b.Holidays {
+		if _, err := time.Parse("2006-01-02", h); err != nil {
+			return fmt.Errorf("%w: invalid holiday %q", ErrInvalidInput, h)
+		}
+	}
+	return nil
+}
+
+// Add returns the instant d of business time after from.
+func (b *BusinessHours) Add(from time.Time, d time.Duration) time.Time {
+	if b == nil || d <= 0 {
+		return from.Add(max(d, 0))
+	}
+	loc, start, end := b.resolve()
+	cur := from.In(loc)
+	for i := 0; i < maxCalendarDays; i++ {
+		open, close, ok := b.window(cur, loc, start, end)
+		if ok && cur.Before(close) {
+			if cur.Before(open) {
+				cur = open
+			}
+			if left := close.Sub(cur); d <= left {
+				return cur.Add(d).UTC()
+			} else {
+				d -= left
+			}
+		}
+		y, m, day := cur.Date()
+		cur = time.Date(y, m, day+1, 0, 0, 0, 0, loc)
+	}
+	return cur.Add(d).UTC()
+}
+
+// Between returns the business time elapsed from a to b.
+func (b *BusinessHours) Between(a, c time.Time) time.Duration {
+	if !c.After(a) {
+		return 0
+	}
+	if b == nil {
+		return c.Sub(a)
+	}
+	loc, start, end := b.resolve()
+	cur, stop := a.In(loc), c.In(loc)
+	var total time.Duration
+	for i := 0; i < maxCalendarDays && cur.Before(stop); i++ {
+		open, close, ok := b.window(cur, loc, start, end)
+		if ok {
+			lo, hi := later(cur, open), earlier(stop, close)
+			if hi.After(lo) {
+				total += hi.Sub(lo)
+			}
+		}
+		y, m, day := cur.Date()
+		cur = time.Date(y, m, day+1, 0, 0, 0, 0, loc)
+	}
+	return total
+}
+
+func (b *BusinessHours) resolve() (*time.Location, int, int) {
+	loc, err := time.LoadLocation(b.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, _ := clockMinutes(b.Start)
+	end, _ := clockMinutes(b.End)
+	return loc, start, end
+}
+
+// window is the opening interval of the local day containing t.
+func (b *BusinessHours) window(t time.Time, loc *time.Location, start, end int) (time.Time, time.Time, bool) {
+	y, m, d := t.Date()
+	if !b.workingDay(t) {
+		return time.Time{}, time.Time{}, false
+	}
+	open := time.Date(y, m, d, start/60, start%60, 0, 0, loc)
+	close := time.Date(y, m, d, end/60, end%60, 0, 0, loc)
+	return open, close, true
+}
+
+func (b *BusinessHours) workingDay(t time.Time) bool {
+	day := t.Format("2006-01-02")
+	for _, h := range b.Holidays {
+		if h == day {
+			return false
+		}
+	}
+	for _, w := range b.Weekdays {
+		if w == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func clockMinutes(v string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(v))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// SLAPolicy sets the targets of tickets matching Priority and Category; an
+// empty field matches any value and the most specific active policy wins.
+// Targets count wall-clock time unless BusinessHoursOnly is set, in which
+// case they run on BusinessHours or the service's default calendar.
+type SLAPolicy struct {
+	PolicyID            string         `json:"policy_id"`
+	Name                string         `json:"name"`
+	Priority            string         `json:"priority,omitempty"`
+	Category            string         `json:"category,omitempty"`
+	FirstResponseTarget time.Duration  `json:"first_response_target"`
+	ResolutionTarget    time.Duration  `json:"resolution_target"`
+	BusinessHoursOnly   bool           `json:"business_hours_only"`
+	BusinessHours       *BusinessHours `json:"business_hours,omitempty"`
+	Active              bool           `json:"active"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// Calendar returns the calendar the policy's clocks run on; nil means
+// around the clock.
+func (p SLAPolicy) Calendar(fallback *BusinessHours) *BusinessHours {
+	if !p.BusinessHoursOnly {
+		return nil
+	}
+	if p.BusinessHours != nil {
+		return p.BusinessHours
+	}
+	return fallback
+}
+
+func (p SLAPolicy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: policy name is required", ErrInvalidInput)
+	}
+	if p.FirstResponseTarget <= 0 || p.ResolutionTarget <= 0 {
+		return fmt.Errorf("%w: targets must be positive", ErrInvalidInput)
+	}
+	if p.ResolutionTarget < p.FirstResponseTarget {
+		return fmt.Errorf("%w: resolution target is shorter than first response target", ErrInvalidInput)
+	}
+	return p.BusinessHours.Validate()
+}
+
+// Specificity ranks how well the policy matches a ticket; -1 means it does
+// not apply. A category match outweighs a priority match so category
+// policies override the per-priority defaults.
+func (p SLAPolicy) Specificity(priority, category string) int {
+	if !p.Active {
+		return -1
+	}
+	score := 0
+	if p.Category != "" {
+		if !strings.EqualFold(p.Category, category) {
+			return -1
+		}
+		score += 2
+	}
+	if p.Priority != "" {
+		if !strings.EqualFold(p.Priority, priority) {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// MatchSLAPolicy returns the most specific active policy for a ticket.
+func MatchSLAPolicy(policies []SLAPolicy, priority, category string) (SLAPolicy, bool) {
+	best, bestScore := SLAPolicy{}, -1
+	for _, p := range policies {
+		score := p.Specificity(priority, category)
+		if score > bestScore || (score == bestScore && score >= 0 && p.PolicyID < best.PolicyID) {
+			best, bestScore = p, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// SLAClock measures business time spent against one target. Elapsed holds
+// the time banked before RunningSince; a paused or stopped clock has a zero
+// RunningSince.
+type SLAClock struct {
+	Target       time.Duration `json:"target"`
+	Elapsed      time.Duration `json:"elapsed"`
+	RunningSince time.Time     `json:"running_since,omitempty"`
+	DueAt        time.Time     `json:"due_at,omitempty"`
+	CompletedAt  time.Time     `json:"completed_at,omitempty"`
+	BreachedAt   time.Time     `json:"breached_at,omitempty"`
+}
+
+func (c *SLAClock) Running() bool   { return !c.RunningSince.IsZero() }
+func (c *SLAClock) Completed() bool { return !c.CompletedAt.IsZero() }
+func (c *SLAClock) Breached() bool  { return !c.BreachedAt.IsZero() }
+
+func (c *SLAClock) start(now time.Time, hours *BusinessHours) {
+	if c.Running() || c.Completed() {
+		return
+	}
+	c.RunningSince = now
+	c.DueAt = hours.Add(now, max(c.Target-c.Elapsed, 0))
+}
+
+func (c *SLAClock) pause(now time.Time, hours *BusinessHours) {
+	if !c.Running() {
+		return
+	}
+	c.checkBreach(now)
+	c.Elapsed += hours.Between(c.RunningSince, now)
+	c.RunningSince = time.Time{}
+	c.DueAt = time.Time{}
+}
+
+func (c *SLAClock) stop(now time.Time, hours *BusinessHours) {
+	if c.Completed() {
+		return
+	}
+	c.pause(now, hours)
+	if c.Elapsed > c.Target && !c.Breached() {
+		c.BreachedAt = now
+	}
+	c.CompletedAt = now
+}
+
+func (c *SLAClock) reopen(now time.Time, hours *BusinessHours) {
+	c.CompletedAt = time.Time{}
+	c.start(now, hours)
+}
+
+func (c *SLAClock) checkBreach(now time.Time) bool {
+	if c.Breached() || !c.Running() || now.Before(c.DueAt) {
+		return false
+	}
+	c.BreachedAt = c.DueAt
+	return true
+}
+
+// Remaining is the business time left before the clock breaches; it is
+// negative once breached.
+func (c *SLAClock) Remaining(now time.Time, hours *BusinessHours) time.Duration {
+	left := c.Target - c.Elapsed
+	if c.Running() {
+		left -= hours.Between(c.RunningSince, now)
+	}
+	return left
+}
+
+// SLAEscalation records an escalation rule that fired on a ticket.
+type SLAEscalation struct {
+	RuleID  string    `json:"rule_id"`
+	Clock   string    `json:"clock"`
+	Action  string    `json:"action"`
+	Detail  string    `json:"detail"`
+	FiredAt time.Time `json:"fired_at"`
+}
+
+// TicketSLA is the SLA state of one ticket. The policy's calendar is copied
+// so later policy edits do not move existing deadlines.
+type TicketSLA struct {
+	PolicyID      string          `json:"policy_id"`
+	BusinessHours *BusinessHours  `json:"business_hours,omitempty"`
+	FirstResponse SLAClock        `json:"first_response"`
+	Resolution    SLAClock        `json:"resolution"`
+	Paused        bool            `json:"paused"`
+	Escalations   []SLAEscalation `json:"escalations,omitempty"`
+}
+
+// NewTicketSLA starts both clocks of policy at now on calendar.
+func NewTicketSLA(policy SLAPolicy, calendar *BusinessHours, now time.Time) *TicketSLA {
+	sla := &TicketSLA{
+		PolicyID:      policy.PolicyID,
+		BusinessHours: calendar,
+		FirstResponse: SLAClock{Target: policy.FirstResponseTarget},
+		Resolution:    SLAClock{Target: policy.ResolutionTarget},
+	}
+	sla.FirstResponse.start(now, sla.BusinessHours)
+	sla.Resolution.start(now, sla.BusinessHours)
+	return sla
+}
+
+// Clock returns the named clock.
+func (s *TicketSLA) Clock(name string) *SLAClock {
+	switch name {
+	case SLAClockFirstResponse:
+		return &s.FirstResponse
+	case SLAClockResolution:
+		return &s.Resolution
+	}
+	return nil
+}
+
+// Pause stops both clocks while the ticket waits on the customer.
+func (s *TicketSLA) Pause(now time.Time) {
+	s.FirstResponse.pause(now, s.BusinessHours)
+	s.Resolution.pause(now, s.BusinessHours)
+	s.Paused = true
+}
+
+func (s *TicketSLA) Resume(now time.Time) {
+	if !s.Paused {
+		return
+	}
+	s.Paused = false
+	s.FirstResponse.start(now, s.BusinessHours)
+	s.Resolution.start(now, s.BusinessHours)
+}
+
+func (s *TicketSLA) Responded(now time.Time) {
+	s.FirstResponse.stop(now, s.BusinessHours)
+}
+
+// Resolved stops the resolution clock, and the first response clock if no
+// agent ever replied.
+func (s *TicketSLA) Resolved(now time.Time) {
+	s.FirstResponse.stop(now, s.BusinessHours)
+	s.Resolution.stop(now, s.BusinessHours)
+	s.Paused = false
+}
+
+func (s *TicketSLA) Reopened(now time.Time) {
+	s.Resolution.reopen(now, s.BusinessHours)
+}
+
+// Retarget applies the targets of policy, keeping the time already spent
+// and the ticket's calendar.
+func (s *TicketSLA) Retarget(policy SLAPolicy, now time.Time) {
+	for _, c := range []struct {
+		clock  *SLAClock
+		target time.Duration
+	}{{&s.FirstResponse, policy.FirstResponseTarget}, {&s.Resolution, policy.ResolutionTarget}} {
+		running := c.clock.Running()
+		c.clock.pause(now, s.BusinessHours)
+		c.clock.Target = c.target
+		if running {
+			c.clock.start(now, s.BusinessHours)
+		}
+	}
+	s.PolicyID = policy.PolicyID
+}
+
+// CheckBreaches marks running clocks whose deadline passed and returns
+// their names.
+func (s *TicketSLA) CheckBreaches(now time.Time) []string {
+	var out []string
+	if s.FirstResponse.checkBreach(now) {
+		out = append(out, SLAClockFirstResponse)
+	}
+	if s.Resolution.checkBreach(now) {
+		out = append(out, SLAClockResolution)
+	}
+	return out
+}
+
+func (s *TicketSLA) Escalated(ruleID string) bool {
+	for _, e := range s.Escalations {
+		if e.RuleID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	EscalationActionBumpPriority = "bump_priority"
+	EscalationActionReassign     = "reassign"
+)
+
+// EscalationRule fires once per ticket when the named clock has Before or
+// less business time left. Paused clocks never escalate.
+type EscalationRule struct {
+	RuleID   string        `json:"rule_id"`
+	Name     string        `json:"name"`
+	Clock    string        `json:"clock"`
+	Priority string        `json:"priority,omitempty"`
+	Category string        `json:"category,omitempty"`
+	Before   time.Duration `json:"before"`
+	Action   string        `json:"action"`
+	// TargetRole limits reassignment to agents holding this role.
+	TargetRole string    `json:"target_role,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (r EscalationRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: rule name is required", ErrInvalidInput)
+	}
+	if r.Clock != SLAClockFirstResponse && r.Clock != SLAClockResolution {
+		return fmt.Errorf("%w: clock must be %s or %s", ErrInvalidInput, SLAClockFirstResponse, SLAClockResolution)
+	}
+	if r.Action != EscalationActionBumpPriority && r.Action != EscalationActionReassign {
+		return fmt.Errorf("%w: action must be %s or %s", ErrInvalidInput, EscalationActionBumpPriority, EscalationActionReassign)
+	}
+	if r.Before < 0 {
+		return fmt.Errorf("%w: before must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Matches reports whether the rule covers the ticket's priority and category.
+func (r EscalationRule) Matches(ticket Ticket) bool {
+	if !r.Active {
+		return false
+	}
+	if r.Priority != "" && !strings.EqualFold(r.Priority, ticket.Priority) {
+		return false
+	}
+	return r.Category == "" || strings.EqualFold(r.Category, ticket.Category)
+}
+
+// NextPriority is the priority one step above p; critical stays critical.
+func NextPriority(p string) string {
+	switch p {
+	case "low":
+		return "normal"
+	case "normal":
+		return "high"
+	default:
+		return "critical"
+	}
+}
+
+// Clone returns a deep copy so stored tickets do not share SLA state.
+func (s *TicketSLA) Clone() *TicketSLA {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.Escalations = append([]SLAEscalation(nil), s.Escalations...)
+	return &out
+}
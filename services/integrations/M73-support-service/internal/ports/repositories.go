@@ -12,6 +12,9 @@ type TicketRepository interface {
 	Update(ctx context.Context, ticket domain.Ticket) error
 	Get(ctx context.Context, ticketID string) (domain.Ticket, error)
 	Search(ctx context.Context, filter domain.SearchFilter) ([]domain.Ticket, error)
+	// ListActive returns tickets that are not resolved, closed or deleted.
+	ListActive(ctx context.Context) ([]domain.Ticket, error)
+	ListCreatedBetween(ctx context.Context, from, to time.Time) ([]domain.Ticket, error)
 }
 
 type ReplyRepository interface {
@@ -30,6 +33,34 @@ type AgentRepository interface {
 	Upsert(ctx context.Context, agent domain.Agent) error
 }
 
+type SLAPolicyRepository interface {
+	List(ctx context.Context) ([]domain.SLAPolicy, error)
+	Get(ctx context.Context, policyID string) (domain.SLAPolicy, error)
+	Upsert(ctx context.Context, policy domain.SLAPolicy) error
+}
+
+type EscalationRuleRepository interface {
+	List(ctx context.Context) ([]domain.EscalationRule, error)
+	Get(ctx context.Context, ruleID string) (domain.EscalationRule, error)
+	Upsert(ctx context.Context, rule domain.EscalationRule) error
+}
+
+type EmailMessageRepository interface {
+	Get(ctx context.Context, messageID string) (domain.EmailMessage, error)
+	Add(ctx context.Context, msg domain.EmailMessage) error
+	CountFromSince(ctx context.Context, sender string, since time.Time) (int, error)
+}
+
+type AttachmentRepository interface {
+	Add(ctx context.Context, attachment domain.EmailAttachment) error
+	ListByTicket(ctx context.Context, ticketID string) ([]domain.EmailAttachment, error)
+}
+
+// AttachmentStore keeps attachment bytes outside the database.
+type AttachmentStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
 type IdempotencyRepository interface {
 	Get(ctx context.Context, key string, now time.Time) (*domain.IdempotencyRecord, error)
 	Upsert(ctx context.Context, rec domain.IdempotencyRecord) error
@@ -20,6 +20,10 @@ func newRouter() http.Handler {
 		Replies:     repos.Replies,
 		CSAT:        repos.CSAT,
 		Agents:      repos.Agents,
+		SLAPolicies: repos.SLAPolicies,
+		Escalations: repos.Escalations,
+		Emails:      repos.Emails,
+		Attachments: repos.Attachments,
 		Idempotency: repos.Idempotency,
 	})
 	return httpadapter.NewRouter(httpadapter.NewHandler(svc))
@@ -187,3 +191,66 @@ func TestSupportAdminRoutesEnforceRoleScope(t *testing.T) {
 		t.Fatalf("expected forbidden for user role on admin search route: status=%d body=%s", rr.Code, rr.Body.String())
 	}
 }
+
+func TestSLAAdminRoutes(t *testing.T) {
+	router := newRouter()
+	policyReq := httptest.NewRequest(http.MethodPost, "/api/v1/support/admin/sla/policies", strings.NewReader(`{"name":"Refund","category":"Refund","first_response_minutes":60,"resolution_minutes":480,"business_hours_only":true,"business_hours":{"timezone":"Europe/Berlin","weekdays":[1,2,3,4,5],"start":"08:00","end":"18:00","holidays":["2026-12-25"]}}`))
+	policyReq.Header.Set("Authorization", "Bearer manager-1")
+	policyReq.Header.Set("X-Actor-Role", "support_manager")
+	policyReq.Header.Set("Idempotency-Key", "idem-policy")
+	policyRR := httptest.NewRecorder()
+	router.ServeHTTP(policyRR, policyReq)
+	if policyRR.Code != http.StatusOK {
+		t.Fatalf("upsert sla policy failed: status=%d body=%s", policyRR.Code, policyRR.Body.String())
+	}
+
+	badReq := httptest.NewRequest(http.MethodPost, "/api/v1/support/admin/sla/escalation-rules", strings.NewReader(`{"name":"Broken","clock":"eventually","action":"bump_priority"}`))
+	badReq.Header.Set("Authorization", "Bearer manager-1")
+	badReq.Header.Set("X-Actor-Role", "support_manager")
+	badReq.Header.Set("Idempotency-Key", "idem-bad-rule")
+	badRR := httptest.NewRecorder()
+	router.ServeHTTP(badRR, badReq)
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid rule to be rejected: status=%d body=%s", badRR.Code, badRR.Body.String())
+	}
+
+	reportReq := httptest.NewRequest(http.MethodGet, "/api/v1/support/admin/sla/report", nil)
+	reportReq.Header.Set("Authorization", "Bearer manager-1")
+	reportReq.Header.Set("X-Actor-Role", "support_manager")
+	reportRR := httptest.NewRecorder()
+	router.ServeHTTP(reportRR, reportReq)
+	if reportRR.Code != http.StatusOK {
+		t.Fatalf("sla report failed: status=%d body=%s", reportRR.Code, reportRR.Body.String())
+	}
+
+	userReq := httptest.NewRequest(http.MethodGet, "/api/v1/support/admin/sla/report", nil)
+	userReq.Header.Set("Authorization", "Bearer user-1")
+	userReq.Header.Set("X-Actor-Role", "user")
+	userRR := httptest.NewRecorder()
+	router.ServeHTTP(userRR, userReq)
+	if userRR.Code != http.StatusForbidden {
+		t.Fatalf("expected forbidden for user role on sla report: status=%d body=%s", userRR.Code, userRR.Body.String())
+	}
+}
+
+func TestInboundEmailRoute(t *testing.T) {
+	router := newRouter()
+	raw := "From: jane@example.com\r\nSubject: Cannot log in\r\nMessage-ID: <contract-1@example.com>\r\n\r\nI cannot log in since the last update.\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/internal/email/inbound", strings.NewReader(raw))
+	req.Header.Set("Authorization", "Bearer mail-gateway")
+	req.Header.Set("X-Actor-Role", "system")
+	req.Header.Set("Content-Type", "message/rfc822")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("inbound email failed: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	badReq := httptest.NewRequest(http.MethodPost, "/api/internal/email/inbound", strings.NewReader("no headers here"))
+	badReq.Header.Set("Authorization", "Bearer mail-gateway")
+	badRR := httptest.NewRecorder()
+	router.ServeHTTP(badRR, badReq)
+	if badRR.Code != http.StatusBadRequest {
+		t.Fatalf("expected malformed message to be rejected: status=%d body=%s", badRR.Code, badRR.Body.String())
+	}
+}
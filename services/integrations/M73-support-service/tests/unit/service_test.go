@@ -2,10 +2,20 @@ package unit
 
 import (
 	"context"
+	"encoding/base64"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	emailadapter "github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/email"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/filestore"
 	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M73-support-service/internal/domain"
 )
 
 func newService() *application.Service {
@@ -15,6 +25,10 @@ func newService() *application.Service {
 		Replies:     repos.Replies,
 		CSAT:        repos.CSAT,
 		Agents:      repos.Agents,
+		SLAPolicies: repos.SLAPolicies,
+		Escalations: repos.Escalations,
+		Emails:      repos.Emails,
+		Attachments: repos.Attachments,
 		Idempotency: repos.Idempotency,
 	})
 }
@@ -69,3 +83,429 @@ func TestResolvedTicketReopensOnUserReply(t *testing.T) {
 		t.Fatalf("expected ticket reopened, got status=%s sub_status=%s", updated.Status, updated.SubStatus)
 	}
 }
+
+func TestBusinessHoursSkipWeekendsAndHolidays(t *testing.T) {
+	hours := &domain.BusinessHours{
+		Timezone: "UTC",
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Start:    "09:00",
+		End:      "17:00",
+		Holidays: []string{"2026-10-19"},
+	}
+	if err := hours.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	friday := time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC)
+	due := hours.Add(friday, 2*time.Hour)
+	if want := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC); !due.Equal(want) {
+		t.Fatalf("expected due %s, got %s", want, due)
+	}
+	if got := hours.Between(friday, due); got != 2*time.Hour {
+		t.Fatalf("expected 2h of business time, got %s", got)
+	}
+}
+
+func TestSLAClockPausesWhilePendingCustomer(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	manager := application.Actor{SubjectID: "manager-1", Role: "support_manager", IdempotencyKey: "idem-policy"}
+	if _, err := svc.UpsertSLAPolicy(ctx, manager, application.UpsertSLAPolicyInput{
+		Name:                "Account fast lane",
+		Category:            "Account",
+		FirstResponseTarget: 150 * time.Millisecond,
+		ResolutionTarget:    time.Minute,
+	}); err != nil {
+		t.Fatalf("upsert policy: %v", err)
+	}
+	created, err := svc.CreateTicket(ctx, application.Actor{SubjectID: "user-3", IdempotencyKey: "idem-create-3"}, application.CreateTicketInput{
+		Subject:     "Account locked",
+		Description: "I cannot log in to my account after the reset.",
+		Category:    "Account",
+	})
+	if err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+	if created.SLA == nil || created.SLA.FirstResponse.Target != 150*time.Millisecond {
+		t.Fatalf("expected account policy on ticket, got %+v", created.SLA)
+	}
+	agent := application.Actor{SubjectID: "agent-technical", Role: "agent", IdempotencyKey: "idem-pending"}
+	if _, err := svc.UpdateTicket(ctx, agent, created.TicketID, application.UpdateTicketInput{SubStatus: "pending-customer"}); err != nil {
+		t.Fatalf("set pending customer: %v", err)
+	}
+	time.Sleep(250 * time.Millisecond)
+	if _, err := svc.RunSLASweep(ctx); err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	paused, _ := svc.GetTicket(ctx, agent, created.TicketID)
+	if !paused.SLA.Paused || paused.SLA.FirstResponse.Breached() {
+		t.Fatalf("expected paused clock without breach, got %+v", paused.SLA.FirstResponse)
+	}
+
+	if _, err := svc.AddReply(ctx, application.Actor{SubjectID: "user-3", Role: "user", IdempotencyKey: "idem-reply-3"}, created.TicketID, application.AddReplyInput{Body: "Here are the details you asked for."}); err != nil {
+		t.Fatalf("user reply: %v", err)
+	}
+	time.Sleep(250 * time.Millisecond)
+	result, err := svc.RunSLASweep(ctx)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if result.Breached != 1 {
+		t.Fatalf("expected one breach, got %+v", result)
+	}
+	breached, _ := svc.GetTicket(ctx, agent, created.TicketID)
+	if breached.SLA.Paused || !breached.SLA.FirstResponse.Breached() {
+		t.Fatalf("expected running breached clock, got %+v", breached.SLA.FirstResponse)
+	}
+	clock := breached.SLA.FirstResponse
+	if spent := clock.Target - clock.Remaining(time.Now().UTC(), nil); spent >= 400*time.Millisecond {
+		t.Fatalf("paused time counted against the clock: spent %s", spent)
+	}
+}
+
+func TestAutoAssignmentBalancesLoadAcrossSkilledAgents(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	create := func(i int) string {
+		ticket, err := svc.CreateTicket(ctx, application.Actor{SubjectID: "user-4", IdempotencyKey: "idem-balance-" + string(rune('a'+i))}, application.CreateTicketInput{
+			Subject:     "Billing question",
+			Description: "My invoice total does not match the plan.",
+			Category:    "Billing",
+		})
+		if err != nil {
+			t.Fatalf("create ticket %d: %v", i, err)
+		}
+		return ticket.AssignedAgentID
+	}
+	got := []string{create(0), create(1), create(2)}
+	want := []string{"agent-billing", "agent-senior", "agent-billing"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("assignment %d: expected %s, got %s (all: %v)", i, want[i], got[i], got)
+		}
+	}
+
+	tickets, err := svc.SearchTickets(ctx, application.Actor{SubjectID: "agent-billing"}, application.SearchTicketsInput{AssignedTo: "agent-billing"})
+	if err != nil || len(tickets) != 2 {
+		t.Fatalf("expected two billing tickets, got %d (%v)", len(tickets), err)
+	}
+	if _, err := svc.UpdateTicket(ctx, application.Actor{SubjectID: "agent-billing", Role: "agent", IdempotencyKey: "idem-resolve-balance"}, tickets[0].TicketID, application.UpdateTicketInput{Status: "resolved"}); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if next := create(3); next != "agent-billing" {
+		t.Fatalf("expected resolved ticket to free agent-billing, got %s", next)
+	}
+}
+
+func TestEscalationAndSLAReport(t *testing.T) {
+	svc := newService()
+	ctx := context.Background()
+	manager := application.Actor{SubjectID: "manager-1", Role: "support_manager"}
+	withKey := func(key string) application.Actor {
+		a := manager
+		a.IdempotencyKey = key
+		return a
+	}
+	if _, err := svc.UpsertSLAPolicy(ctx, withKey("idem-partner-policy"), application.UpsertSLAPolicyInput{
+		Name:                "Partner program",
+		Category:            "partner program",
+		FirstResponseTarget: 2 * time.Hour,
+		ResolutionTarget:    8 * time.Hour,
+	}); err != nil {
+		t.Fatalf("upsert policy: %v", err)
+	}
+	off := false
+	if _, err := svc.UpsertEscalationRule(ctx, withKey("idem-rule-off"), application.UpsertEscalationRuleInput{
+		RuleID: "esc-resolution-at-risk", Name: "Resolution at risk", Clock: "resolution", Before: 4 * time.Hour, Action: "bump_priority", Active: &off,
+	}); err != nil {
+		t.Fatalf("disable seeded rule: %v", err)
+	}
+	if _, err := svc.UpsertEscalationRule(ctx, withKey("idem-rule-partner"), application.UpsertEscalationRuleInput{
+		Name: "Partner resolution", Clock: "resolution", Category: "Partner Program", Before: 9 * time.Hour, Action: "bump_priority",
+	}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	if _, err := svc.UpsertEscalationRule(ctx, application.Actor{SubjectID: "agent-senior", Role: "senior_agent", IdempotencyKey: "idem-rule-agent"}, application.UpsertEscalationRuleInput{
+		Name: "Nope", Clock: "resolution", Action: "bump_priority",
+	}); err != domain.ErrForbidden {
+		t.Fatalf("expected forbidden for agents, got %v", err)
+	}
+
+	created, err := svc.CreateTicket(ctx, application.Actor{SubjectID: "user-5", IdempotencyKey: "idem-create-5"}, application.CreateTicketInput{
+		Subject:     "Partner payout",
+		Description: "My partner program payout was not credited.",
+		Category:    "Partner Program",
+	})
+	if err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.RunSLASweep(ctx); err != nil {
+			t.Fatalf("sweep: %v", err)
+		}
+	}
+	escalated, _ := svc.GetTicket(ctx, manager, created.TicketID)
+	if escalated.Priority != "high" || len(escalated.SLA.Escalations) != 1 {
+		t.Fatalf("expected one priority bump, got priority=%s escalations=%+v", escalated.Priority, escalated.SLA.Escalations)
+	}
+	if escalated.SLA.Resolution.Target != 8*time.Hour {
+		t.Fatalf("escalation must keep SLA targets, got %s", escalated.SLA.Resolution.Target)
+	}
+
+	if _, err := svc.AddReply(ctx, application.Actor{SubjectID: "agent-senior", Role: "senior_agent", IdempotencyKey: "idem-reply-5"}, created.TicketID, application.AddReplyInput{Body: "Looking into the payout now."}); err != nil {
+		t.Fatalf("agent reply: %v", err)
+	}
+	if _, err := svc.SubmitCSAT(ctx, application.Actor{SubjectID: "user-5", IdempotencyKey: "idem-csat-5"}, created.TicketID, application.SubmitCSATInput{Rating: 4}); err != nil {
+		t.Fatalf("csat: %v", err)
+	}
+	report, err := svc.GetSLAReport(ctx, manager, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	overall := report.Overall
+	if overall.Tickets != 1 || overall.FirstResponse.Met != 1 || overall.FirstResponse.ComplianceRate != 1 || overall.Resolution.Pending != 1 {
+		t.Fatalf("unexpected compliance: %+v", overall)
+	}
+	if overall.Escalations != 1 || overall.CSATResponses != 1 || overall.CSATAverage != 4 {
+		t.Fatalf("unexpected escalation or csat totals: %+v", overall)
+	}
+	if len(report.ByCategory) != 1 || report.ByCategory[0].Key != "Partner Program" {
+		t.Fatalf("unexpected category breakdown: %+v", report.ByCategory)
+	}
+}
+
+var pngBytes = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func newEmailService(t *testing.T, cfg application.Config) (*application.Service, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := filestore.NewLocalStore(dir)
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	repos := postgres.NewRepositories()
+	return application.NewService(application.Dependencies{
+		Config:          cfg,
+		Tickets:         repos.Tickets,
+		Replies:         repos.Replies,
+		CSAT:            repos.CSAT,
+		Agents:          repos.Agents,
+		SLAPolicies:     repos.SLAPolicies,
+		Escalations:     repos.Escalations,
+		Emails:          repos.Emails,
+		Attachments:     repos.Attachments,
+		Idempotency:     repos.Idempotency,
+		AttachmentStore: store,
+	}), dir
+}
+
+func mimeMessage(headers, body string) []byte {
+	return []byte(strings.ReplaceAll(headers+"\n"+body, "\n", "\r\n"))
+}
+
+func TestParseMultipartEmail(t *testing.T) {
+	raw := mimeMessage(`From: "Jane Doe" <Jane@Example.com>
+To: support@viralforge.io
+Subject: =?UTF-8?Q?Payout_f=C3=BCr_M=C3=A4rz?=
+Message-ID: <abc123@example.com>
+In-Reply-To: <rpl-1.TKT-20261018-x1@viralforge.io>
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+`, `--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+The payout is still missing =E2=80=94 see screenshot.
+
+On Mon, Oct 12, 2026 at 9:00 AM Support <support@viralforge.io> wrote:
+> We are looking into it.
+--inner
+Content-Type: text/html; charset=utf-8
+
+<p>The payout is still missing</p>
+--inner--
+--outer
+Content-Type: image/png; name="shot.png"
+Content-Disposition: attachment; filename="shot.png"
+Content-Transfer-Encoding: base64
+
+`+base64.StdEncoding.EncodeToString(pngBytes)+`
+--outer--
+`)
+	msg, err := emailadapter.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if msg.From != "jane@example.com" || msg.FromName != "Jane Doe" || msg.Subject != "Payout für März" {
+		t.Fatalf("unexpected headers: from=%q name=%q subject=%q", msg.From, msg.FromName, msg.Subject)
+	}
+	if msg.MessageID != "<abc123@example.com>" || len(msg.InReplyTo) != 1 {
+		t.Fatalf("unexpected threading headers: %q %v", msg.MessageID, msg.InReplyTo)
+	}
+	if got := domain.StripQuotedReply(msg.TextBody); got != "The payout is still missing — see screenshot." {
+		t.Fatalf("unexpected stripped body: %q", got)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Filename != "shot.png" || string(msg.Attachments[0].Data) != string(pngBytes) {
+		t.Fatalf("unexpected attachments: %+v", msg.Attachments)
+	}
+
+	htmlOnly, err := emailadapter.Parse(mimeMessage("From: a@example.com\nSubject: Hi\nContent-Type: text/html\n", `<html><head><style>p{}</style></head><body><p>Hello &amp; thanks</p><blockquote>old<br>text</blockquote></body></html>`))
+	if err != nil {
+		t.Fatalf("parse html: %v", err)
+	}
+	if htmlOnly.TextBody != "Hello & thanks\n\n> old\n> text" {
+		t.Fatalf("unexpected html rendering: %q", htmlOnly.TextBody)
+	}
+}
+
+func TestInboundEmailThreadsRepliesAndChecksAttachments(t *testing.T) {
+	svc, dir := newEmailService(t, application.Config{SupportAddress: "support@viralforge.io"})
+	ctx := context.Background()
+	intake := application.Actor{SubjectID: "email-intake", Role: "system"}
+	ingest := func(raw []byte) application.InboundEmailResult {
+		t.Helper()
+		msg, err := emailadapter.Parse(raw)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		result, err := svc.ProcessInboundEmail(ctx, intake, msg)
+		if err != nil {
+			t.Fatalf("process: %v", err)
+		}
+		return result
+	}
+
+	created := ingest(mimeMessage("From: jane@example.com\nSubject: Upload broken\nMessage-ID: <m1@example.com>\n", "My uploads fail with error 500 since yesterday."))
+	if created.Action != domain.EmailActionCreated || created.TicketID == "" {
+		t.Fatalf("expected ticket to be created, got %+v", created)
+	}
+	if again := ingest(mimeMessage("From: jane@example.com\nSubject: Upload broken\nMessage-ID: <m1@example.com>\n", "My uploads fail with error 500 since yesterday.")); again.Action != domain.EmailActionDuplicate || again.TicketID != created.TicketID {
+		t.Fatalf("expected duplicate delivery to be skipped, got %+v", again)
+	}
+	agentReply, err := svc.AddReply(ctx, application.Actor{SubjectID: "agent-technical", Role: "agent", IdempotencyKey: "idem-agent-email"}, created.TicketID, application.AddReplyInput{Body: "Could you send a screenshot?"})
+	if err != nil || agentReply.MessageID == "" {
+		t.Fatalf("agent reply: %+v %v", agentReply, err)
+	}
+
+	reply := ingest(mimeMessage("From: Jane <jane@example.com>\nSubject: Re: Upload broken\nMessage-ID: <m2@example.com>\nIn-Reply-To: "+agentReply.MessageID+"\nContent-Type: multipart/mixed; boundary=b\n", `--b
+Content-Type: text/plain
+
+Screenshot attached.
+
+> Could you send a screenshot?
+--b
+Content-Type: image/png
+Content-Disposition: attachment; filename=shot.png
+Content-Transfer-Encoding: base64
+
+`+base64.StdEncoding.EncodeToString(pngBytes)+`
+--b
+Content-Type: application/octet-stream
+Content-Disposition: attachment; filename=tool.exe
+
+MZ
+--b
+Content-Type: image/png
+Content-Disposition: attachment; filename=fake.png
+
+not really a png
+--b--
+`))
+	if reply.Action != domain.EmailActionThreaded || reply.TicketID != created.TicketID || reply.ReplyID == "" {
+		t.Fatalf("expected reply threaded onto ticket, got %+v", reply)
+	}
+	if len(reply.Attachments) != 3 || reply.Attachments[0].Status != domain.AttachmentStatusStored ||
+		reply.Attachments[1].Status != domain.AttachmentStatusRejected || reply.Attachments[2].Status != domain.AttachmentStatusRejected {
+		t.Fatalf("unexpected attachment outcomes: %+v", reply.Attachments)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(reply.Attachments[0].StorageKey))); err != nil || string(data) != string(pngBytes) {
+		t.Fatalf("stored attachment mismatch: %v", err)
+	}
+	tokenReply := ingest(mimeMessage("From: jane@example.com\nSubject: Re: Upload broken ["+created.TicketID+"]\nMessage-ID: <m3@example.com>\n", "Any news on this?"))
+	if tokenReply.Action != domain.EmailActionThreaded || tokenReply.TicketID != created.TicketID {
+		t.Fatalf("expected subject token to thread, got %+v", tokenReply)
+	}
+	stranger := ingest(mimeMessage("From: mallory@example.com\nSubject: Re: ["+created.TicketID+"]\nMessage-ID: <m4@example.com>\n", "Please change the payout account."))
+	if stranger.Action != domain.EmailActionCreated || stranger.TicketID == created.TicketID {
+		t.Fatalf("expected other senders to get their own ticket, got %+v", stranger)
+	}
+}
+
+func TestInboundEmailIgnoresAutoRepliesAndLoops(t *testing.T) {
+	svc, _ := newEmailService(t, application.Config{SupportAddress: "support@viralforge.io", MaxEmailsPerSenderPerHour: 2})
+	ctx := context.Background()
+	intake := application.Actor{SubjectID: "email-intake", Role: "system"}
+	cases := []struct {
+		headers string
+		want    string
+	}{
+		{"From: jane@example.com\nAuto-Submitted: auto-replied\n", domain.EmailActionIgnored},
+		{"From: jane@example.com\nX-Loop: support@viralforge.io\n", domain.EmailActionIgnored},
+		{"From: MAILER-DAEMON@example.com\n", domain.EmailActionIgnored},
+		{"From: bob@example.com\n", domain.EmailActionCreated},
+		{"From: bob@example.com\n", domain.EmailActionCreated},
+		{"From: bob@example.com\n", domain.EmailActionIgnored},
+	}
+	for i, tc := range cases {
+		raw := mimeMessage(tc.headers+"Subject: Out of office\nMessage-ID: <loop-"+string(rune('a'+i))+"@example.com>\n", "I am away until Monday, thanks.")
+		msg, err := emailadapter.Parse(raw)
+		if err != nil {
+			t.Fatalf("case %d parse: %v", i, err)
+		}
+		result, err := svc.ProcessInboundEmail(ctx, intake, msg)
+		if err != nil {
+			t.Fatalf("case %d process: %v", i, err)
+		}
+		if result.Action != tc.want {
+			t.Fatalf("case %d: expected %s, got %+v", i, tc.want, result)
+		}
+	}
+}
+
+func TestEmailIntakeFromSMTPAndMaildir(t *testing.T) {
+	svc, _ := newEmailService(t, application.Config{SupportAddress: "support@viralforge.io"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ingester := emailadapter.NewIngester(svc)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &emailadapter.SMTPServer{Hostname: "viralforge.io", MaxBytes: 1 << 20, Ingester: ingester}
+	go func() { _ = server.Serve(ctx, ln) }()
+	msg := mimeMessage("From: smtp-user@example.com\nTo: support@viralforge.io\nSubject: Sent over SMTP\nMessage-ID: <smtp-1@example.com>\n", "This arrived through the SMTP listener.")
+	if err := smtp.SendMail(ln.Addr().String(), nil, "smtp-user@example.com", []string{"support@viralforge.io"}, msg); err != nil {
+		t.Fatalf("send mail: %v", err)
+	}
+
+	dir := t.TempDir()
+	for _, sub := range []string{"new", "cur", "tmp"} {
+		_ = os.MkdirAll(filepath.Join(dir, sub), 0o750)
+	}
+	_ = os.WriteFile(filepath.Join(dir, "new", "1.eml"), mimeMessage("From: maildir-user@example.com\nSubject: From Maildir\nMessage-ID: <md-1@example.com>\n", "This arrived through the Maildir poller."), 0o640)
+	_ = os.WriteFile(filepath.Join(dir, "new", "2.eml"), []byte("not an email"), 0o640)
+	_ = os.WriteFile(filepath.Join(dir, "new", "3.eml"), mimeMessage("From: big-user@example.com\nSubject: Too big\nMessage-ID: <md-3@example.com>\n", strings.Repeat("x", 4096)), 0o640)
+	poller := &emailadapter.MaildirPoller{Dir: dir, Interval: time.Hour, MaxBytes: 2048, Ingester: ingester}
+	if moved := poller.Poll(ctx); moved != 3 {
+		t.Fatalf("expected every message moved out of new/, got %d", moved)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cur", "1.eml:2,S")); err != nil {
+		t.Fatalf("expected processed message flagged seen: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cur", "2.eml:2,F")); err != nil {
+		t.Fatalf("expected malformed message flagged for review: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cur", "3.eml:2,F")); err != nil {
+		t.Fatalf("expected oversized message flagged for review: %v", err)
+	}
+
+	for _, sender := range []string{"smtp-user@example.com", "maildir-user@example.com"} {
+		tickets, err := svc.SearchTickets(ctx, application.Actor{SubjectID: "manager-1"}, application.SearchTicketsInput{UserID: sender})
+		if err != nil || len(tickets) != 1 || tickets[0].Channel != "email" {
+			t.Fatalf("expected one email ticket for %s, got %d (%v)", sender, len(tickets), err)
+		}
+	}
+}
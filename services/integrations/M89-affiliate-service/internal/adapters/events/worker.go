@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/application"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
@@ -19,24 +20,43 @@ type Worker struct {
 	dlqPublisher ports.DLQPublisher
 	service      *application.Service
 	pollInterval time.Duration
+
+	settleInterval time.Duration
 }
 
-func NewWorker(logger *slog.Logger, consumer ports.EventConsumer, dlqPublisher ports.DLQPublisher, service *application.Service, pollInterval time.Duration) *Worker {
+func NewWorker(logger *slog.Logger, consumer ports.EventConsumer, dlqPublisher ports.DLQPublisher, service *application.Service, pollInterval, settleInterval time.Duration) *Worker {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	if pollInterval <= 0 {
 		pollInterval = time.Second
 	}
-	return &Worker{logger: logger, consumer: consumer, dlqPublisher: dlqPublisher, service: service, pollInterval: pollInterval}
+	if settleInterval <= 0 {
+		settleInterval = time.Hour
+	}
+	return &Worker{logger: logger, consumer: consumer, dlqPublisher: dlqPublisher, service: service, pollInterval: pollInterval, settleInterval: settleInterval}
 }
 func (w *Worker) Run(ctx context.Context) error {
 	t := time.NewTicker(w.pollInterval)
 	defer t.Stop()
+	settle := time.NewTicker(w.settleInterval)
+	defer settle.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-settle.C:
+			if w.service == nil {
+				continue
+			}
+			res, err := w.service.SettleEarnings(ctx)
+			if err != nil {
+				w.logger.ErrorContext(ctx, "earnings settlement failed", "error", err)
+				continue
+			}
+			if res.Released > 0 || len(res.Payouts) > 0 {
+				w.logger.InfoContext(ctx, "earnings settled", "released", res.Released, "payouts", len(res.Payouts))
+			}
 		case <-t.C:
 			if w.service != nil {
 				if err := w.service.FlushOutbox(ctx); err != nil {
@@ -56,15 +76,18 @@ func (w *Worker) Run(ctx context.Context) error {
 			if e == nil {
 				continue
 			}
-			if err := w.service.HandleCanonicalEvent(ctx, *e); err != nil {
+			ectx, span := observability.StartEventSpan(ctx, e.EventType, e.TraceID)
+			err = w.service.HandleCanonicalEvent(ectx, *e)
+			observability.EndEventSpan(span, err)
+			if err != nil {
 				if e.EventClass == domain.CanonicalEventClassAnalyticsOnly {
-					w.logger.WarnContext(ctx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+					w.logger.WarnContext(ectx, "analytics-only event dropped", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 					continue
 				}
-				w.logger.ErrorContext(ctx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
+				w.logger.ErrorContext(ectx, "canonical event failed", "event_type", e.EventType, "event_id", e.EventID, "error", err)
 				if w.dlqPublisher != nil {
 					now := time.Now().UTC()
-					_ = w.dlqPublisher.PublishDLQ(ctx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "affiliate-service.dlq", TraceID: e.TraceID})
+					_ = w.dlqPublisher.PublishDLQ(ectx, contracts.DLQRecord{OriginalEvent: *e, ErrorSummary: err.Error(), RetryCount: 1, FirstSeenAt: now, LastErrorAt: now, SourceTopic: e.EventType, DLQTopic: "affiliate-service.dlq", TraceID: e.TraceID})
 				}
 			}
 		}
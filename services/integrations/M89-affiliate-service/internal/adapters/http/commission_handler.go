@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/contracts"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
+)
+
+func (h *Handler) listCommissionPlans(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.service.ListCommissionPlans(r.Context(), actorFromContext(r.Context()))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	items := make([]contracts.CommissionPlanResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toCommissionPlanResponse(row))
+	}
+	writeSuccess(w, http.StatusOK, contracts.CommissionPlanListResponse{Items: items})
+}
+
+func (h *Handler) upsertCommissionPlan(w http.ResponseWriter, r *http.Request) {
+	var req contracts.CommissionPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body")
+		return
+	}
+	in := application.UpsertCommissionPlanInput{
+		PlanID:        req.PlanID,
+		Name:          req.Name,
+		Period:        req.Period,
+		BaseRate:      req.BaseRate,
+		ProductRates:  req.ProductRates,
+		CampaignRates: req.CampaignRates,
+	}
+	for _, tier := range req.Tiers {
+		in.Tiers = append(in.Tiers, domain.CommissionTier{MinVolume: tier.MinVolume, Rate: tier.Rate})
+	}
+	for _, bonus := range req.Bonuses {
+		in.Bonuses = append(in.Bonuses, domain.CommissionBonus{Name: bonus.Name, MinVolume: bonus.MinVolume, Amount: bonus.Amount})
+	}
+	if req.HoldDays != nil {
+		hold := time.Duration(*req.HoldDays) * 24 * time.Hour
+		in.HoldPeriod = &hold
+	}
+	row, err := h.service.UpsertCommissionPlan(r.Context(), actorFromContext(r.Context()), in)
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	writeSuccess(w, http.StatusOK, toCommissionPlanResponse(row))
+}
+
+func (h *Handler) assignCommissionPlan(w http.ResponseWriter, r *http.Request) {
+	var req contracts.AssignPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body")
+		return
+	}
+	row, err := h.service.AssignCommissionPlan(r.Context(), actorFromContext(r.Context()), application.AssignCommissionPlanInput{
+		AffiliateID: chi.URLParam(r, "affiliate_id"),
+		PlanID:      req.PlanID,
+	})
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	writeSuccess(w, http.StatusOK, contracts.AssignPlanResponse{
+		AffiliateID: row.AffiliateID,
+		PlanID:      row.PlanID,
+		UpdatedAt:   row.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *Handler) getLedger(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.service.GetLedger(r.Context(), actorFromContext(r.Context()))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	writeSuccess(w, http.StatusOK, toLedgerResponse("", rows))
+}
+
+func (h *Handler) getAffiliateLedger(w http.ResponseWriter, r *http.Request) {
+	affiliateID := chi.URLParam(r, "affiliate_id")
+	rows, err := h.service.GetAffiliateLedger(r.Context(), actorFromContext(r.Context()), affiliateID)
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	writeSuccess(w, http.StatusOK, toLedgerResponse(affiliateID, rows))
+}
+
+func (h *Handler) settleEarnings(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.RunSettlement(r.Context(), actorFromContext(r.Context()))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	total := 0.0
+	for _, payout := range out.Payouts {
+		total += payout.Amount
+	}
+	writeSuccess(w, http.StatusOK, contracts.SettlementResponse{Released: out.Released, PayoutsQueued: len(out.Payouts), PayoutTotal: total})
+}
+
+func toCommissionPlanResponse(row domain.CommissionPlan) contracts.CommissionPlanResponse {
+	out := contracts.CommissionPlanResponse{
+		PlanID:        row.PlanID,
+		Name:          row.Name,
+		Period:        row.Period,
+		BaseRate:      row.BaseRate,
+		Tiers:         make([]contracts.CommissionTierRequest, 0, len(row.Tiers)),
+		ProductRates:  row.ProductRates,
+		CampaignRates: row.CampaignRates,
+		Bonuses:       make([]contracts.CommissionBonusRequest, 0, len(row.Bonuses)),
+		HoldDays:      int(row.HoldPeriod / (24 * time.Hour)),
+		UpdatedAt:     row.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, tier := range row.Tiers {
+		out.Tiers = append(out.Tiers, contracts.CommissionTierRequest{MinVolume: tier.MinVolume, Rate: tier.Rate})
+	}
+	for _, bonus := range row.Bonuses {
+		out.Bonuses = append(out.Bonuses, contracts.CommissionBonusRequest{Name: bonus.Name, MinVolume: bonus.MinVolume, Amount: bonus.Amount})
+	}
+	return out
+}
+
+func toLedgerResponse(affiliateID string, rows []domain.LedgerEntry) contracts.LedgerResponse {
+	items := make([]contracts.LedgerEntryResponse, 0, len(rows))
+	for _, row := range rows {
+		if affiliateID == "" {
+			affiliateID = row.AffiliateID
+		}
+		items = append(items, contracts.LedgerEntryResponse{
+			EntryID:        row.EntryID,
+			Type:           row.Type,
+			Amount:         row.Amount,
+			Currency:       row.Currency,
+			EarningID:      row.EarningID,
+			PayoutID:       row.PayoutID,
+			OrderID:        row.OrderID,
+			Reference:      row.Reference,
+			Reason:         row.Reason,
+			BalancePending: row.BalancePending,
+			BalancePayable: row.BalancePayable,
+			BalancePaid:    row.BalancePaid,
+			CreatedAt:      row.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return contracts.LedgerResponse{AffiliateID: affiliateID, Items: items}
+}
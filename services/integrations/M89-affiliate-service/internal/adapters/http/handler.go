@@ -72,6 +72,7 @@ func (h *Handler) getDashboard(w http.ResponseWriter, r *http.Request) {
 		TotalAttributions: out.TotalAttributions,
 		ConversionRate:    out.ConversionRate,
 		PendingEarnings:   out.PendingEarnings,
+		PayableEarnings:   out.PayableEarnings,
 		PaidEarnings:      out.PaidEarnings,
 		TopLinks:          top,
 	})
@@ -87,11 +88,15 @@ func (h *Handler) listEarnings(w http.ResponseWriter, r *http.Request) {
 	items := make([]contracts.EarningResponse, 0, len(rows))
 	for _, row := range rows {
 		items = append(items, contracts.EarningResponse{
-			EarningID: row.EarningID,
-			OrderID:   row.OrderID,
-			Amount:    row.Amount,
-			Status:    row.Status,
-			CreatedAt: row.CreatedAt.UTC().Format(time.RFC3339),
+			EarningID:   row.EarningID,
+			OrderID:     row.OrderID,
+			Kind:        row.Kind,
+			Amount:      row.Amount,
+			ClawedBack:  row.ClawedBack,
+			Rate:        row.Rate,
+			Status:      row.Status,
+			AvailableAt: row.AvailableAt.UTC().Format(time.RFC3339),
+			CreatedAt:   row.CreatedAt.UTC().Format(time.RFC3339),
 		})
 	}
 	writeSuccess(w, http.StatusOK, contracts.EarningsListResponse{Items: items})
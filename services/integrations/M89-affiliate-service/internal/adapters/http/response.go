@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/contracts"
@@ -42,6 +43,14 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusConflict, "conflict"
 	case domain.ErrUnsupportedEventType:
 		return http.StatusBadRequest, "unsupported_event_type"
+	}
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest, "invalid_input"
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict, "conflict"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
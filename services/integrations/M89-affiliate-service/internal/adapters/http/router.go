@@ -24,9 +24,15 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Post("/affiliates/links", handler.createReferralLink)
 			r.Get("/affiliates/dashboard", handler.getDashboard)
 			r.Get("/affiliates/earnings", handler.listEarnings)
+			r.Get("/affiliates/ledger", handler.getLedger)
 			r.Post("/affiliates/exports", handler.createExport)
 			r.Post("/admin/affiliates/{affiliate_id}/suspend", handler.suspendAffiliate)
 			r.Post("/admin/affiliates/{affiliate_id}/attributions", handler.manualAttribution)
+			r.Put("/admin/affiliates/{affiliate_id}/plan", handler.assignCommissionPlan)
+			r.Get("/admin/affiliates/{affiliate_id}/ledger", handler.getAffiliateLedger)
+			r.Get("/admin/commission-plans", handler.listCommissionPlans)
+			r.Post("/admin/commission-plans", handler.upsertCommissionPlan)
+			r.Post("/admin/earnings/settle", handler.settleEarnings)
 		})
 	})
 	return r
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"maps"
 	"sort"
 	"strings"
 	"sync"
@@ -18,6 +19,8 @@ type Repositories struct {
 	Attributions *ReferralAttributionRepository
 	Earnings     *AffiliateEarningRepository
 	Payouts      *AffiliatePayoutRepository
+	Plans        *CommissionPlanRepository
+	Ledger       *AffiliateLedgerRepository
 	AuditLogs    *AffiliateAuditLogRepository
 	Idempotency  *IdempotencyRepository
 	EventDedup   *EventDedupRepository
@@ -28,10 +31,12 @@ func NewRepositories() *Repositories {
 	return &Repositories{
 		Affiliates:   &AffiliateRepository{byID: map[string]domain.Affiliate{}, byUserID: map[string]string{}},
 		Links:        &ReferralLinkRepository{byID: map[string]domain.ReferralLink{}, byToken: map[string]string{}},
-		Clicks:       &ReferralClickRepository{byID: map[string]domain.ReferralClick{}, byAffiliate: map[string][]string{}},
-		Attributions: &ReferralAttributionRepository{byID: map[string]domain.ReferralAttribution{}, byOrderID: map[string]string{}, byAffiliate: map[string][]string{}},
+		Clicks:       &ReferralClickRepository{byID: map[string]domain.ReferralClick{}, byAffiliate: map[string][]string{}, byCookie: map[string][]string{}},
+		Attributions: &ReferralAttributionRepository{byID: map[string]domain.ReferralAttribution{}, byOrderID: map[string][]string{}, byAffiliate: map[string][]string{}},
 		Earnings:     &AffiliateEarningRepository{byID: map[string]domain.AffiliateEarning{}, byAffiliate: map[string][]string{}},
 		Payouts:      &AffiliatePayoutRepository{byID: map[string]domain.AffiliatePayout{}, byAffiliate: map[string][]string{}},
+		Plans:        &CommissionPlanRepository{byID: map[string]domain.CommissionPlan{}},
+		Ledger:       &AffiliateLedgerRepository{byAffiliate: map[string][]domain.LedgerEntry{}},
 		AuditLogs:    &AffiliateAuditLogRepository{rows: []domain.AffiliateAuditLog{}},
 		Idempotency:  &IdempotencyRepository{rows: map[string]ports.IdempotencyRecord{}},
 		EventDedup:   &EventDedupRepository{rows: map[string]time.Time{}},
@@ -149,6 +154,7 @@ type ReferralClickRepository struct {
 	mu          sync.Mutex
 	byID        map[string]domain.ReferralClick
 	byAffiliate map[string][]string
+	byCookie    map[string][]string
 }
 
 func (r *ReferralClickRepository) Append(_ context.Context, row domain.ReferralClick) error {
@@ -159,6 +165,9 @@ func (r *ReferralClickRepository) Append(_ context.Context, row domain.ReferralC
 	}
 	r.byID[row.ClickID] = row
 	r.byAffiliate[row.AffiliateID] = append(r.byAffiliate[row.AffiliateID], row.ClickID)
+	if row.CookieID != "" {
+		r.byCookie[row.CookieID] = append(r.byCookie[row.CookieID], row.ClickID)
+	}
 	return nil
 }
 func (r *ReferralClickRepository) GetByID(_ context.Context, clickID string) (domain.ReferralClick, error) {
@@ -194,40 +203,88 @@ func (r *ReferralClickRepository) CountByLinkID(_ context.Context, linkID string
 	}
 	return count, nil
 }
+func (r *ReferralClickRepository) ListByCookieID(_ context.Context, cookieID string, from, to time.Time) ([]domain.ReferralClick, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.ReferralClick{}
+	for _, id := range r.byCookie[strings.TrimSpace(cookieID)] {
+		if row, ok := r.byID[id]; ok && !row.ClickedAt.Before(from) && !row.ClickedAt.After(to) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ClickedAt.Before(out[j].ClickedAt) })
+	return out, nil
+}
+func (r *ReferralClickRepository) ListByFingerprint(_ context.Context, ipHash, userAgentHash string, from, to time.Time) ([]domain.ReferralClick, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.ReferralClick{}
+	for _, row := range r.byID {
+		if row.IPHash == ipHash && row.UserAgentHash == userAgentHash && !row.ClickedAt.Before(from) && !row.ClickedAt.After(to) {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ClickedAt.Before(out[j].ClickedAt) })
+	return out, nil
+}
 
 type ReferralAttributionRepository struct {
 	mu          sync.Mutex
 	byID        map[string]domain.ReferralAttribution
-	byOrderID   map[string]string
+	byOrderID   map[string][]string
 	byAffiliate map[string][]string
 }
 
+// Create rejects a second active attribution of the same order to the same
+// affiliate; an order split across affiliates has one row per affiliate.
 func (r *ReferralAttributionRepository) Create(_ context.Context, row domain.ReferralAttribution) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, ok := r.byID[row.AttributionID]; ok {
 		return domain.ErrConflict
 	}
-	if _, ok := r.byOrderID[row.OrderID]; ok {
-		return domain.ErrConflict
+	for _, id := range r.byOrderID[row.OrderID] {
+		if ex := r.byID[id]; ex.AffiliateID == row.AffiliateID && ex.Status != domain.AttributionStatusOverridden {
+			return domain.ErrConflict
+		}
 	}
 	r.byID[row.AttributionID] = row
-	r.byOrderID[row.OrderID] = row.AttributionID
+	r.byOrderID[row.OrderID] = append(r.byOrderID[row.OrderID], row.AttributionID)
 	r.byAffiliate[row.AffiliateID] = append(r.byAffiliate[row.AffiliateID], row.AttributionID)
 	return nil
 }
-func (r *ReferralAttributionRepository) GetByOrderID(_ context.Context, orderID string) (domain.ReferralAttribution, error) {
+func (r *ReferralAttributionRepository) Update(_ context.Context, row domain.ReferralAttribution) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	id, ok := r.byOrderID[strings.TrimSpace(orderID)]
-	if !ok {
-		return domain.ReferralAttribution{}, domain.ErrNotFound
+	if _, ok := r.byID[row.AttributionID]; !ok {
+		return domain.ErrNotFound
 	}
-	row, ok := r.byID[id]
-	if !ok {
-		return domain.ReferralAttribution{}, domain.ErrNotFound
+	r.byID[row.AttributionID] = row
+	return nil
+}
+func (r *ReferralAttributionRepository) ListByOrderID(_ context.Context, orderID string) ([]domain.ReferralAttribution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := r.byOrderID[strings.TrimSpace(orderID)]
+	out := make([]domain.ReferralAttribution, 0, len(ids))
+	for _, id := range ids {
+		if row, ok := r.byID[id]; ok {
+			out = append(out, row)
+		}
 	}
-	return row, nil
+	return out, nil
+}
+func (r *ReferralAttributionRepository) ListByConversionID(_ context.Context, conversionID string) ([]domain.ReferralAttribution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.ReferralAttribution{}
+	for _, row := range r.byID {
+		if row.ConversionID == conversionID {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AttributionID < out[j].AttributionID })
+	return out, nil
 }
 func (r *ReferralAttributionRepository) ListByAffiliateID(_ context.Context, affiliateID string) ([]domain.ReferralAttribution, error) {
 	r.mu.Lock()
@@ -259,6 +316,27 @@ func (r *AffiliateEarningRepository) Create(_ context.Context, row domain.Affili
 	r.byAffiliate[row.AffiliateID] = append(r.byAffiliate[row.AffiliateID], row.EarningID)
 	return nil
 }
+func (r *AffiliateEarningRepository) Update(_ context.Context, row domain.AffiliateEarning) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byID[row.EarningID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.byID[row.EarningID] = row
+	return nil
+}
+func (r *AffiliateEarningRepository) ListByAttributionID(_ context.Context, attributionID string) ([]domain.AffiliateEarning, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.AffiliateEarning{}
+	for _, row := range r.byID {
+		if row.AttributionID == attributionID {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
 func (r *AffiliateEarningRepository) ListByAffiliateID(_ context.Context, affiliateID string) ([]domain.AffiliateEarning, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -272,13 +350,30 @@ func (r *AffiliateEarningRepository) ListByAffiliateID(_ context.Context, affili
 	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
 	return out, nil
 }
+func (r *AffiliateEarningRepository) ListByStatus(_ context.Context, status string) ([]domain.AffiliateEarning, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.AffiliateEarning{}
+	for _, row := range r.byID {
+		if row.Status == status {
+			out = append(out, row)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].EarningID < out[j].EarningID
+	})
+	return out, nil
+}
 func (r *AffiliateEarningRepository) SumByAffiliateAndStatus(_ context.Context, affiliateID, status string) (float64, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	total := 0.0
 	for _, id := range r.byAffiliate[affiliateID] {
 		if row, ok := r.byID[id]; ok && row.Status == status {
-			total += row.Amount
+			total += row.Net()
 		}
 	}
 	return total, nil
@@ -314,6 +409,72 @@ func (r *AffiliatePayoutRepository) ListByAffiliateID(_ context.Context, affilia
 	return out, nil
 }
 
+type CommissionPlanRepository struct {
+	mu   sync.Mutex
+	byID map[string]domain.CommissionPlan
+}
+
+func (r *CommissionPlanRepository) Upsert(_ context.Context, row domain.CommissionPlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byID[row.PlanID] = cloneCommissionPlan(row)
+	return nil
+}
+func (r *CommissionPlanRepository) GetByID(_ context.Context, planID string) (domain.CommissionPlan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.byID[strings.TrimSpace(planID)]
+	if !ok {
+		return domain.CommissionPlan{}, domain.ErrNotFound
+	}
+	return cloneCommissionPlan(row), nil
+}
+func (r *CommissionPlanRepository) List(_ context.Context) ([]domain.CommissionPlan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.CommissionPlan, 0, len(r.byID))
+	for _, row := range r.byID {
+		out = append(out, cloneCommissionPlan(row))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PlanID < out[j].PlanID })
+	return out, nil
+}
+
+func cloneCommissionPlan(row domain.CommissionPlan) domain.CommissionPlan {
+	row.Tiers = append([]domain.CommissionTier(nil), row.Tiers...)
+	row.Bonuses = append([]domain.CommissionBonus(nil), row.Bonuses...)
+	row.ProductRates = maps.Clone(row.ProductRates)
+	row.CampaignRates = maps.Clone(row.CampaignRates)
+	return row
+}
+
+type AffiliateLedgerRepository struct {
+	mu          sync.Mutex
+	byAffiliate map[string][]domain.LedgerEntry
+}
+
+func (r *AffiliateLedgerRepository) Append(_ context.Context, row domain.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byAffiliate[row.AffiliateID] = append(r.byAffiliate[row.AffiliateID], row)
+	return nil
+}
+func (r *AffiliateLedgerRepository) ListByAffiliateID(_ context.Context, affiliateID string) ([]domain.LedgerEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.LedgerEntry{}, r.byAffiliate[affiliateID]...), nil
+}
+func (r *AffiliateLedgerRepository) HasReference(_ context.Context, affiliateID, reference string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.byAffiliate[affiliateID] {
+		if row.Reference == reference {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 type AffiliateAuditLogRepository struct {
 	mu   sync.Mutex
 	rows []domain.AffiliateAuditLog
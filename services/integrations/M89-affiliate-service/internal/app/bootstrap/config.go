@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -23,6 +24,12 @@ type Config struct {
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
 	OutboxFlushBatchSize int
+	AttributionModel     string
+	AttributionLookback  time.Duration
+	FingerprintLookback  time.Duration
+	TimeDecayHalfLife    time.Duration
+	EarningHoldPeriod    time.Duration
+	SettleInterval       time.Duration
 }
 
 type configFile struct {
@@ -43,6 +50,16 @@ type configFile struct {
 		ConsumerPollSeconds    int `yaml:"consumer_poll_seconds"`
 		OutboxFlushBatchSize   int `yaml:"outbox_flush_batch_size"`
 	} `yaml:"runtime"`
+	Attribution struct {
+		Model                    string `yaml:"model"`
+		LookbackHours            int    `yaml:"lookback_hours"`
+		FingerprintLookbackHours int    `yaml:"fingerprint_lookback_hours"`
+		TimeDecayHalfLifeHours   int    `yaml:"time_decay_half_life_hours"`
+	} `yaml:"attribution"`
+	Earnings struct {
+		HoldDays              *int `yaml:"hold_days"`
+		SettleIntervalMinutes int  `yaml:"settle_interval_minutes"`
+	} `yaml:"earnings"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -58,6 +75,12 @@ func LoadConfig(path string) (Config, error) {
 		EventDedupTTL:        7 * 24 * time.Hour,
 		ConsumerPollInterval: 2 * time.Second,
 		OutboxFlushBatchSize: 100,
+		AttributionModel:     domain.AttributionModelLastClick,
+		AttributionLookback:  30 * 24 * time.Hour,
+		FingerprintLookback:  24 * time.Hour,
+		TimeDecayHalfLife:    7 * 24 * time.Hour,
+		EarningHoldPeriod:    30 * 24 * time.Hour,
+		SettleInterval:       time.Hour,
 	}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
@@ -97,6 +120,24 @@ func LoadConfig(path string) (Config, error) {
 		if f.Runtime.OutboxFlushBatchSize > 0 {
 			cfg.OutboxFlushBatchSize = f.Runtime.OutboxFlushBatchSize
 		}
+		if f.Attribution.Model != "" {
+			cfg.AttributionModel = f.Attribution.Model
+		}
+		if f.Attribution.LookbackHours > 0 {
+			cfg.AttributionLookback = time.Duration(f.Attribution.LookbackHours) * time.Hour
+		}
+		if f.Attribution.FingerprintLookbackHours > 0 {
+			cfg.FingerprintLookback = time.Duration(f.Attribution.FingerprintLookbackHours) * time.Hour
+		}
+		if f.Attribution.TimeDecayHalfLifeHours > 0 {
+			cfg.TimeDecayHalfLife = time.Duration(f.Attribution.TimeDecayHalfLifeHours) * time.Hour
+		}
+		if f.Earnings.HoldDays != nil && *f.Earnings.HoldDays >= 0 {
+			cfg.EarningHoldPeriod = time.Duration(*f.Earnings.HoldDays) * 24 * time.Hour
+		}
+		if f.Earnings.SettleIntervalMinutes > 0 {
+			cfg.SettleInterval = time.Duration(f.Earnings.SettleIntervalMinutes) * time.Minute
+		}
 	}
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
@@ -108,6 +149,17 @@ func LoadConfig(path string) (Config, error) {
 	cfg.EventDedupTTL = time.Duration(envInt("EVENT_DEDUP_TTL_HOURS", int(cfg.EventDedupTTL.Hours()))) * time.Hour
 	cfg.ConsumerPollInterval = time.Duration(envInt("CONSUMER_POLL_SECONDS", int(cfg.ConsumerPollInterval.Seconds()))) * time.Second
 	cfg.OutboxFlushBatchSize = envInt("OUTBOX_FLUSH_BATCH_SIZE", cfg.OutboxFlushBatchSize)
+	cfg.AttributionModel = domain.NormalizeAttributionModel(envString("ATTRIBUTION_MODEL", cfg.AttributionModel))
+	cfg.AttributionLookback = time.Duration(envInt("ATTRIBUTION_LOOKBACK_HOURS", int(cfg.AttributionLookback.Hours()))) * time.Hour
+	cfg.FingerprintLookback = time.Duration(envInt("ATTRIBUTION_FINGERPRINT_LOOKBACK_HOURS", int(cfg.FingerprintLookback.Hours()))) * time.Hour
+	cfg.EarningHoldPeriod = time.Duration(envInt("EARNING_HOLD_DAYS", int(cfg.EarningHoldPeriod.Hours()/24))) * 24 * time.Hour
+	cfg.SettleInterval = time.Duration(envInt("EARNING_SETTLE_INTERVAL_MINUTES", int(cfg.SettleInterval.Minutes()))) * time.Minute
+	if !domain.IsAttributionModel(cfg.AttributionModel) {
+		return Config{}, fmt.Errorf("attribution model %q is not one of last_click, first_click, linear, time_decay", cfg.AttributionModel)
+	}
+	if cfg.FingerprintLookback > cfg.AttributionLookback {
+		return Config{}, fmt.Errorf("attribution fingerprint lookback must not exceed the lookback window")
+	}
 
 	if isProductionRuntime() {
 		if strings.TrimSpace(cfg.PublicBaseURL) == "" {
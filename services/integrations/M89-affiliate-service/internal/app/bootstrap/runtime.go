@@ -12,6 +12,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/adapters/http"
@@ -23,18 +24,23 @@ import (
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 
 	repos := postgres.NewRepositories()
@@ -54,9 +60,14 @@ func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
 			EventDedupTTL:        cfg.EventDedupTTL,
 			ConsumerPollInterval: cfg.ConsumerPollInterval,
 			OutboxFlushBatchSize: cfg.OutboxFlushBatchSize,
+			AttributionModel:     cfg.AttributionModel,
+			AttributionLookback:  cfg.AttributionLookback,
+			FingerprintLookback:  cfg.FingerprintLookback,
+			TimeDecayHalfLife:    cfg.TimeDecayHalfLife,
+			EarningHoldPeriod:    cfg.EarningHoldPeriod,
 		},
 		Affiliates: repos.Affiliates, Links: repos.Links, Clicks: repos.Clicks, Attributions: repos.Attributions,
-		Earnings: repos.Earnings, Payouts: repos.Payouts, AuditLogs: repos.AuditLogs, Idempotency: repos.Idempotency,
+		Earnings: repos.Earnings, Payouts: repos.Payouts, Plans: repos.Plans, Ledger: repos.Ledger, AuditLogs: repos.AuditLogs, Idempotency: repos.Idempotency,
 		EventDedup: repos.EventDedup, Outbox: repos.Outbox,
 		DomainEvents: domainPub, Analytics: analyticsPub, DLQ: dlqPub,
 	})
@@ -65,25 +76,33 @@ func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
 	router := httpadapter.NewRouter(handler)
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler:           router,
+		Handler:           observability.HTTPMiddleware(cfg.ServiceID)(router),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewAffiliateInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		return nil, err
 	}
 
-	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval, cfg.SettleInterval)
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
-	errCh := make(chan error, 2)
+	defer r.shutdownTelemetry()
+	errCh := make(chan error, 3)
+	// Attribution writes to this process's repositories, so the event
+	// consumer and settlement run alongside the API.
+	go func() {
+		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- err
+		}
+	}()
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
@@ -106,9 +125,13 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 	return nil
 }
 
+// RunWorker runs only the worker loop, against repositories the API process
+// cannot see; the API already runs the same loop.
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
+	r.logger.WarnContext(ctx, "standalone worker uses its own in-memory repositories; attributions and settlements it makes are not visible to the API")
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -122,3 +145,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
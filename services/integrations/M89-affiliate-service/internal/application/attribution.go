@@ -0,0 +1,268 @@
+package application
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
+)
+
+const systemActorID = "system"
+
+// conversionClockSkew lets a conversion stamped with second precision by the
+// payments side still see a click recorded earlier in that same second.
+const conversionClockSkew = time.Second
+
+// emptyHash is what TrackReferralClick stores when a click arrives without an
+// IP or user agent; it must never count as a fingerprint match.
+var emptyHash = sha256Hex("")
+
+// AttributeConversion resolves a conversion to the referral clicks that led
+// to it and credits the affiliates behind them under the configured model.
+// Clicks are matched on the referral cookie first; only when the cookie finds
+// nothing does it fall back to the IP and user agent fingerprint, within a
+// shorter window. Orders that already carry an attribution, automatic or
+// manual, are left alone, apart from accruing any commission an earlier
+// attempt stored the attribution for but failed to book.
+func (s *Service) AttributeConversion(ctx context.Context, in ConversionInput) (ConversionResult, error) {
+	in.ConversionID = strings.TrimSpace(in.ConversionID)
+	in.OrderID = strings.TrimSpace(in.OrderID)
+	in.ProductID = strings.TrimSpace(in.ProductID)
+	in.UserID = strings.TrimSpace(in.UserID)
+	in.CookieID = strings.TrimSpace(in.CookieID)
+	in.Currency = strings.ToUpper(strings.TrimSpace(in.Currency))
+	if in.OrderID == "" {
+		in.OrderID = in.ConversionID
+	}
+	if in.ConversionID == "" {
+		in.ConversionID = in.OrderID
+	}
+	if in.OrderID == "" || in.Amount <= 0 {
+		return ConversionResult{}, domain.ErrInvalidInput
+	}
+	if in.Currency == "" {
+		in.Currency = "USD"
+	}
+	now := s.nowFn()
+	if in.ConvertedAt.IsZero() || in.ConvertedAt.After(now) {
+		in.ConvertedAt = now
+	}
+	result := ConversionResult{ConversionID: in.ConversionID, OrderID: in.OrderID, Model: s.cfg.AttributionModel}
+
+	existing, err := s.attributions.ListByOrderID(ctx, in.OrderID)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	if active := activeAttributions(existing); len(active) > 0 {
+		for _, attr := range active {
+			if _, err := s.resumeAccrual(ctx, attr, in.TraceID, now); err != nil {
+				return ConversionResult{}, err
+			}
+		}
+		result.Outcome, result.Attributions = domain.ConversionOutcomeDuplicateOrder, active
+		return result, nil
+	}
+
+	clicks, matchType, err := s.matchClicks(ctx, in)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	result.MatchType = matchType
+	eligible, affiliates, selfReferred, err := s.eligibleClicks(ctx, clicks, in)
+	if err != nil {
+		return ConversionResult{}, err
+	}
+	if len(eligible) == 0 {
+		result.Outcome = domain.ConversionOutcomeUnmatched
+		if selfReferred {
+			result.Outcome = domain.ConversionOutcomeSelfReferral
+		}
+		return result, nil
+	}
+
+	shares := domain.SplitAttribution(s.cfg.AttributionModel, eligible, in.ConvertedAt, s.cfg.TimeDecayHalfLife)
+	amounts := splitAmount(in.Amount, shares)
+	for i, share := range shares {
+		attr := domain.ReferralAttribution{
+			AttributionID: "attr_" + uuid.NewString(),
+			AffiliateID:   share.AffiliateID,
+			ClickID:       share.ClickID,
+			ConversionID:  in.ConversionID,
+			OrderID:       in.OrderID,
+			ProductID:     in.ProductID,
+			Amount:        amounts[i],
+			Currency:      in.Currency,
+			Model:         s.cfg.AttributionModel,
+			MatchType:     matchType,
+			Weight:        math.Round(share.Weight*10000) / 10000,
+			Status:        domain.AttributionStatusActive,
+			AttributedAt:  now,
+		}
+		if err := s.creditAttribution(ctx, affiliates[share.AffiliateID], attr, in.TraceID, now); err != nil {
+			return ConversionResult{}, err
+		}
+		_ = s.appendAudit(ctx, attr.AffiliateID, "affiliate.attribution.auto", systemActorID, "", map[string]string{"order_id": attr.OrderID, "click_id": attr.ClickID, "model": attr.Model, "match_type": attr.MatchType})
+		result.Attributions = append(result.Attributions, attr)
+	}
+	result.Outcome = domain.ConversionOutcomeAttributed
+	return result, nil
+}
+
+func (s *Service) matchClicks(ctx context.Context, in ConversionInput) ([]domain.ReferralClick, string, error) {
+	until := in.ConvertedAt.Add(conversionClockSkew)
+	if in.CookieID != "" {
+		clicks, err := s.clicks.ListByCookieID(ctx, in.CookieID, in.ConvertedAt.Add(-s.cfg.AttributionLookback), until)
+		if err != nil || len(clicks) > 0 {
+			return clicks, domain.MatchTypeCookie, err
+		}
+	}
+	ipHash := fingerprintHash(in.IPHash, in.ClientIP)
+	uaHash := fingerprintHash(in.UserAgentHash, in.UserAgent)
+	if ipHash == "" || uaHash == "" {
+		return nil, "", nil
+	}
+	clicks, err := s.clicks.ListByFingerprint(ctx, ipHash, uaHash, in.ConvertedAt.Add(-s.cfg.FingerprintLookback), until)
+	if err != nil || len(clicks) == 0 {
+		return nil, "", err
+	}
+	return clicks, domain.MatchTypeFingerprint, nil
+}
+
+// eligibleClicks drops clicks whose affiliate is not active or is the buyer
+// themselves. The second return value holds the affiliates that remain.
+func (s *Service) eligibleClicks(ctx context.Context, clicks []domain.ReferralClick, in ConversionInput) ([]domain.ReferralClick, map[string]domain.Affiliate, bool, error) {
+	affiliates := map[string]domain.Affiliate{}
+	skipped := map[string]bool{}
+	selfReferred := false
+	out := make([]domain.ReferralClick, 0, len(clicks))
+	for _, click := range clicks {
+		if skipped[click.AffiliateID] {
+			continue
+		}
+		if _, ok := affiliates[click.AffiliateID]; !ok {
+			aff, err := s.affiliates.GetByID(ctx, click.AffiliateID)
+			if err != nil && err != domain.ErrNotFound {
+				return nil, nil, false, err
+			}
+			switch {
+			case err == domain.ErrNotFound || aff.Status != "active":
+				skipped[click.AffiliateID] = true
+				continue
+			case in.UserID != "" && aff.UserID == in.UserID:
+				skipped[click.AffiliateID], selfReferred = true, true
+				_ = s.appendAudit(ctx, aff.AffiliateID, "affiliate.attribution.self_referral_blocked", systemActorID, "", map[string]string{"order_id": in.OrderID, "click_id": click.ClickID})
+				continue
+			}
+			affiliates[click.AffiliateID] = aff
+		}
+		out = append(out, click)
+	}
+	return out, affiliates, selfReferred, nil
+}
+
+// creditAttribution stores an attribution and accrues the affiliate's
+// commission for it. If the accrual fails the attribution stays behind;
+// resumeAccrual books it when the conversion or request is retried.
+func (s *Service) creditAttribution(ctx context.Context, aff domain.Affiliate, attr domain.ReferralAttribution, traceID string, now time.Time) error {
+	if err := s.attributions.Create(ctx, attr); err != nil {
+		return err
+	}
+	return s.accrueAttribution(ctx, aff, attr, traceID, now)
+}
+
+// resumeAccrual accrues the commission of a stored attribution that has no
+// commission earning yet, and reports whether it had to.
+func (s *Service) resumeAccrual(ctx context.Context, attr domain.ReferralAttribution, traceID string, now time.Time) (bool, error) {
+	earnings, err := s.earnings.ListByAttributionID(ctx, attr.AttributionID)
+	if err != nil {
+		return false, err
+	}
+	for _, earning := range earnings {
+		if earning.Kind == domain.EarningKindCommission {
+			return false, nil
+		}
+	}
+	aff, err := s.affiliates.GetByID(ctx, attr.AffiliateID)
+	if err != nil {
+		return false, err
+	}
+	return true, s.accrueAttribution(ctx, aff, attr, traceID, now)
+}
+
+func (s *Service) accrueAttribution(ctx context.Context, aff domain.Affiliate, attr domain.ReferralAttribution, traceID string, now time.Time) error {
+	earning, err := s.accrueCommission(ctx, &aff, attr, traceID, now)
+	if err != nil {
+		return err
+	}
+	_ = s.enqueueAffiliateAttributionCreated(ctx, attr, traceID, now)
+	_ = s.enqueueAffiliateEarningCalculated(ctx, earning, traceID, now)
+	return nil
+}
+
+// overrideAttributions retires the automatic attributions of an order that
+// an admin is attributing by hand and claws back what they earned.
+func (s *Service) overrideAttributions(ctx context.Context, rows []domain.ReferralAttribution, actorID, reason string, now time.Time) error {
+	for _, attr := range activeAttributions(rows) {
+		attr.Status = domain.AttributionStatusOverridden
+		if err := s.attributions.Update(ctx, attr); err != nil {
+			return err
+		}
+		earnings, err := s.earnings.ListByAttributionID(ctx, attr.AttributionID)
+		if err != nil {
+			return err
+		}
+		aff, err := s.affiliates.GetByID(ctx, attr.AffiliateID)
+		if err != nil {
+			return err
+		}
+		for _, earning := range earnings {
+			if earning.Kind != domain.EarningKindCommission {
+				continue
+			}
+			if _, err := s.clawBack(ctx, &aff, earning, remaining(earning, earnings), "override:"+attr.AttributionID, reason, "", now); err != nil {
+				return err
+			}
+		}
+		_ = s.appendAudit(ctx, attr.AffiliateID, "affiliate.attribution.overridden", actorID, reason, map[string]string{"order_id": attr.OrderID, "attribution_id": attr.AttributionID})
+	}
+	return nil
+}
+
+func activeAttributions(rows []domain.ReferralAttribution) []domain.ReferralAttribution {
+	out := []domain.ReferralAttribution{}
+	for _, row := range rows {
+		if row.Status != domain.AttributionStatusOverridden {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// splitAmount spreads total over the shares in cents, giving any rounding
+// remainder to the largest share so the parts always add up to the order.
+func splitAmount(total float64, shares []domain.AttributionShare) []float64 {
+	out := make([]float64, len(shares))
+	rest := total
+	for i := 1; i < len(shares); i++ {
+		out[i] = round2(total * shares[i].Weight)
+		rest -= out[i]
+	}
+	if len(out) > 0 {
+		out[0] = math.Round(rest*100) / 100
+	}
+	return out
+}
+
+func fingerprintHash(hash, raw string) string {
+	hash = strings.TrimSpace(hash)
+	if hash == "" && strings.TrimSpace(raw) != "" {
+		hash = sha256Hex(strings.TrimSpace(raw))
+	}
+	if hash == emptyHash {
+		return ""
+	}
+	return hash
+}
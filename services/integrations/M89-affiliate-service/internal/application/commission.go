@@ -0,0 +1,586 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
+)
+
+func (s *Service) UpsertCommissionPlan(ctx context.Context, actor Actor, in UpsertCommissionPlanInput) (domain.CommissionPlan, error) {
+	if err := requireAdmin(actor); err != nil {
+		return domain.CommissionPlan{}, err
+	}
+	if s.plans == nil {
+		return domain.CommissionPlan{}, domain.ErrNotFound
+	}
+	plan := domain.CommissionPlan{
+		PlanID:        strings.TrimSpace(in.PlanID),
+		Name:          strings.TrimSpace(in.Name),
+		Period:        strings.ToLower(strings.TrimSpace(in.Period)),
+		BaseRate:      in.BaseRate,
+		Tiers:         in.Tiers,
+		ProductRates:  in.ProductRates,
+		CampaignRates: in.CampaignRates,
+		Bonuses:       in.Bonuses,
+		HoldPeriod:    s.cfg.EarningHoldPeriod,
+	}
+	if plan.Period == "" {
+		plan.Period = domain.CommissionPeriodMonth
+	}
+	if in.HoldPeriod != nil {
+		plan.HoldPeriod = *in.HoldPeriod
+	}
+	if err := plan.Validate(); err != nil {
+		return domain.CommissionPlan{}, err
+	}
+	requestHash := hashJSON(map[string]any{"op": "upsert_commission_plan", "plan": plan})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.CommissionPlan{}, err
+	} else if ok {
+		var out domain.CommissionPlan
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.CommissionPlan{}, err
+	}
+	now := s.nowFn()
+	plan.CreatedAt, plan.UpdatedAt = now, now
+	if existing, err := s.plans.GetByID(ctx, plan.PlanID); err == nil {
+		plan.CreatedAt = existing.CreatedAt
+	}
+	if err := s.plans.Upsert(ctx, plan); err != nil {
+		return domain.CommissionPlan{}, err
+	}
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, plan)
+	return plan, nil
+}
+
+func (s *Service) ListCommissionPlans(ctx context.Context, actor Actor) ([]domain.CommissionPlan, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return nil, domain.ErrForbidden
+	}
+	if s.plans == nil {
+		return []domain.CommissionPlan{}, nil
+	}
+	return s.plans.List(ctx)
+}
+
+func (s *Service) AssignCommissionPlan(ctx context.Context, actor Actor, in AssignCommissionPlanInput) (domain.Affiliate, error) {
+	if err := requireAdmin(actor); err != nil {
+		return domain.Affiliate{}, err
+	}
+	in.AffiliateID = strings.TrimSpace(in.AffiliateID)
+	in.PlanID = strings.TrimSpace(in.PlanID)
+	if in.AffiliateID == "" || in.PlanID == "" {
+		return domain.Affiliate{}, domain.ErrInvalidInput
+	}
+	requestHash := hashJSON(map[string]any{"op": "assign_commission_plan", "affiliate_id": in.AffiliateID, "plan_id": in.PlanID})
+	if raw, ok, err := s.getIdempotent(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Affiliate{}, err
+	} else if ok {
+		var out domain.Affiliate
+		if json.Unmarshal(raw, &out) == nil {
+			return out, nil
+		}
+	}
+	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
+		return domain.Affiliate{}, err
+	}
+	if s.plans == nil {
+		return domain.Affiliate{}, domain.ErrNotFound
+	}
+	if _, err := s.plans.GetByID(ctx, in.PlanID); err != nil {
+		return domain.Affiliate{}, err
+	}
+	aff, err := s.affiliates.GetByID(ctx, in.AffiliateID)
+	if err != nil {
+		return domain.Affiliate{}, err
+	}
+	previous := aff.PlanID
+	aff.PlanID = in.PlanID
+	aff.UpdatedAt = s.nowFn()
+	if err := s.affiliates.Update(ctx, aff); err != nil {
+		return domain.Affiliate{}, err
+	}
+	_ = s.appendAudit(ctx, aff.AffiliateID, "affiliate.plan.assigned", actor.SubjectID, "", map[string]string{"plan_id": in.PlanID, "previous_plan_id": previous})
+	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 200, aff)
+	return aff, nil
+}
+
+// GetLedger returns the calling affiliate's ledger, oldest entry first.
+func (s *Service) GetLedger(ctx context.Context, actor Actor) ([]domain.LedgerEntry, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	aff, err := s.ensureAffiliate(ctx, actor.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	return s.listLedger(ctx, aff.AffiliateID)
+}
+
+func (s *Service) GetAffiliateLedger(ctx context.Context, actor Actor, affiliateID string) ([]domain.LedgerEntry, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return nil, domain.ErrForbidden
+	}
+	aff, err := s.affiliates.GetByID(ctx, strings.TrimSpace(affiliateID))
+	if err != nil {
+		return nil, err
+	}
+	return s.listLedger(ctx, aff.AffiliateID)
+}
+
+func (s *Service) listLedger(ctx context.Context, affiliateID string) ([]domain.LedgerEntry, error) {
+	if s.ledger == nil {
+		return []domain.LedgerEntry{}, nil
+	}
+	return s.ledger.ListByAffiliateID(ctx, affiliateID)
+}
+
+// ProcessReversal claws back the commission earned on a refunded or charged
+// back transaction, in proportion to the amount reversed. Earnings that have
+// not been paid are reduced, and reversed outright once nothing is left.
+// Paid earnings cannot be taken back, so a negative clawback earning carries
+// the amount into the affiliate's next payout instead. Period bonuses the
+// reversal pushes the affiliate's volume back under are clawed back too.
+func (s *Service) ProcessReversal(ctx context.Context, in ReversalInput) (ReversalResult, error) {
+	in.TransactionID = strings.TrimSpace(in.TransactionID)
+	in.ReversalID = strings.TrimSpace(in.ReversalID)
+	in.Reason = strings.TrimSpace(in.Reason)
+	if in.ReversalID == "" {
+		in.ReversalID = strings.TrimSpace(in.EventID)
+	}
+	if in.TransactionID == "" || in.ReversalID == "" || in.Amount < 0 {
+		return ReversalResult{}, domain.ErrInvalidInput
+	}
+	result := ReversalResult{TransactionID: in.TransactionID}
+	attrs, err := s.attributionsForTransaction(ctx, in.TransactionID)
+	if err != nil || len(attrs) == 0 {
+		return result, err
+	}
+	orderTotal := 0.0
+	for _, attr := range attrs {
+		orderTotal += attr.Amount
+	}
+	fraction := 1.0
+	if in.Amount > 0 && in.Amount < orderTotal {
+		fraction = in.Amount / orderTotal
+	}
+	ref := in.Kind + ":" + in.ReversalID
+	now := s.nowFn()
+	for _, attr := range attrs {
+		if attr.HasReversal(ref) {
+			continue
+		}
+		aff, err := s.affiliates.GetByID(ctx, attr.AffiliateID)
+		if err != nil {
+			return ReversalResult{}, err
+		}
+		attr.RefundedAmount = math.Min(attr.Amount, round2(attr.RefundedAmount+attr.Amount*fraction))
+		attr.Reversals = append(attr.Reversals, ref)
+		if err := s.attributions.Update(ctx, attr); err != nil {
+			return ReversalResult{}, err
+		}
+		result.Attributions++
+		earnings, err := s.earnings.ListByAttributionID(ctx, attr.AttributionID)
+		if err != nil {
+			return ReversalResult{}, err
+		}
+		for _, earning := range earnings {
+			if earning.Kind != domain.EarningKindCommission {
+				continue
+			}
+			amount := math.Min(round2(earning.Amount*fraction), remaining(earning, earnings))
+			clawedBack, err := s.clawBack(ctx, &aff, earning, amount, ref, in.Reason, in.TraceID, now)
+			if err != nil {
+				return ReversalResult{}, err
+			}
+			switch {
+			case clawedBack && earning.Status == domain.EarningStatusPaid:
+				result.Clawbacks++
+				result.ClawedBack += amount
+			case clawedBack:
+				result.Reversed++
+				result.ClawedBack += amount
+			}
+		}
+		lost, err := s.clawBackLostBonuses(ctx, &aff, attr.AttributedAt, ref, in.Reason, in.TraceID, now)
+		if err != nil {
+			return ReversalResult{}, err
+		}
+		result.ClawedBack += lost
+		_ = s.appendAudit(ctx, aff.AffiliateID, "affiliate.earning.clawed_back", systemActorID, in.Reason, map[string]string{"order_id": attr.OrderID, "reference": ref})
+	}
+	result.ClawedBack = round2(result.ClawedBack)
+	return result, nil
+}
+
+// clawBackLostBonuses takes back the bonuses of the period containing at
+// whose volume threshold the affiliate no longer reaches, net of refunds,
+// and returns the amount taken.
+func (s *Service) clawBackLostBonuses(ctx context.Context, aff *domain.Affiliate, at time.Time, ref, reason, traceID string, now time.Time) (float64, error) {
+	earnings, err := s.earnings.ListByAffiliateID(ctx, aff.AffiliateID)
+	if err != nil {
+		return 0, err
+	}
+	taken := 0.0
+	for _, earning := range earnings {
+		if earning.Kind != domain.EarningKindBonus || s.plans == nil {
+			continue
+		}
+		plan, err := s.plans.GetByID(ctx, earning.PlanID)
+		if err != nil {
+			continue
+		}
+		prefix := "bonus:" + plan.PlanID + ":" + plan.PeriodKey(at) + ":"
+		name, ok := strings.CutPrefix(earning.Reference, prefix)
+		if !ok {
+			continue
+		}
+		volume, err := s.volumeInPeriod(ctx, aff.AffiliateID, plan, at)
+		if err != nil {
+			return 0, err
+		}
+		if reached(plan.BonusesReached(volume), name) {
+			continue
+		}
+		amount := remaining(earning, earnings)
+		clawedBack, err := s.clawBack(ctx, aff, earning, amount, ref, reason, traceID, now)
+		if err != nil {
+			return 0, err
+		}
+		if clawedBack {
+			taken += amount
+		}
+	}
+	return taken, nil
+}
+
+func reached(bonuses []domain.CommissionBonus, name string) bool {
+	for _, bonus := range bonuses {
+		if bonus.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// clawBack takes amount back from one earning and reports whether anything
+// was taken. A paid earning is left as it was paid; the amount is booked as
+// a negative clawback earning against the next payout instead.
+func (s *Service) clawBack(ctx context.Context, aff *domain.Affiliate, earning domain.AffiliateEarning, amount float64, ref, reason, traceID string, now time.Time) (bool, error) {
+	if amount <= 0 {
+		return false, nil
+	}
+	entry := domain.LedgerEntry{Type: domain.LedgerEntryReversed, Amount: -amount, Currency: earning.Currency, EarningID: earning.EarningID, OrderID: earning.OrderID, Reference: ref, Reason: reason}
+	switch earning.Status {
+	case domain.EarningStatusPending, domain.EarningStatusPayable:
+		status := earning.Status
+		earning.ClawedBack = round2(earning.ClawedBack + amount)
+		if earning.Net() <= 0 {
+			earning.Status = domain.EarningStatusReversed
+		}
+		earning.UpdatedAt = now
+		if err := s.earnings.Update(ctx, earning); err != nil {
+			return false, err
+		}
+		pending, payable := -amount, 0.0
+		if status == domain.EarningStatusPayable {
+			pending, payable = 0, -amount
+		}
+		if err := s.post(ctx, aff, entry, pending, payable, 0, now); err != nil {
+			return false, err
+		}
+		_ = s.enqueueAffiliateEarningCalculated(ctx, earning, traceID, now)
+	case domain.EarningStatusPaid:
+		clawback := domain.AffiliateEarning{EarningID: "earn_" + uuid.NewString(), AffiliateID: aff.AffiliateID, AttributionID: earning.AttributionID, OrderID: earning.OrderID, Kind: domain.EarningKindClawback, PlanID: earning.PlanID, Reference: earning.EarningID, Amount: -amount, Currency: earning.Currency, Status: domain.EarningStatusPayable, AvailableAt: now, CreatedAt: now, UpdatedAt: now}
+		if err := s.earnings.Create(ctx, clawback); err != nil {
+			return false, err
+		}
+		entry.Type, entry.EarningID = domain.LedgerEntryClawback, clawback.EarningID
+		if err := s.post(ctx, aff, entry, 0, -amount, 0, now); err != nil {
+			return false, err
+		}
+		_ = s.enqueueAffiliateEarningCalculated(ctx, clawback, traceID, now)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// remaining is what can still be clawed back from a commission earning:
+// its net amount less any clawback earnings already booked against it.
+func remaining(earning domain.AffiliateEarning, related []domain.AffiliateEarning) float64 {
+	left := earning.Net()
+	for _, row := range related {
+		if row.Kind == domain.EarningKindClawback && row.Reference == earning.EarningID {
+			left += row.Amount
+		}
+	}
+	return round2(math.Max(left, 0))
+}
+
+// SettleEarnings releases earnings whose hold period has ended and queues a
+// payout for every affiliate whose payable balance, net of clawbacks, has
+// reached the payout threshold.
+func (s *Service) SettleEarnings(ctx context.Context) (SettlementResult, error) {
+	now := s.nowFn()
+	result := SettlementResult{Payouts: []domain.AffiliatePayout{}}
+	pending, err := s.earnings.ListByStatus(ctx, domain.EarningStatusPending)
+	if err != nil {
+		return SettlementResult{}, err
+	}
+	for _, earning := range pending {
+		if earning.AvailableAt.After(now) {
+			continue
+		}
+		aff, err := s.affiliates.GetByID(ctx, earning.AffiliateID)
+		if err != nil {
+			return SettlementResult{}, err
+		}
+		earning.Status = domain.EarningStatusPayable
+		earning.UpdatedAt = now
+		if err := s.earnings.Update(ctx, earning); err != nil {
+			return SettlementResult{}, err
+		}
+		net := round2(earning.Net())
+		if err := s.post(ctx, &aff, domain.LedgerEntry{Type: domain.LedgerEntryReleased, Amount: net, Currency: earning.Currency, EarningID: earning.EarningID, OrderID: earning.OrderID}, -net, net, 0, now); err != nil {
+			return SettlementResult{}, err
+		}
+		result.Released++
+	}
+
+	payable, err := s.earnings.ListByStatus(ctx, domain.EarningStatusPayable)
+	if err != nil {
+		return SettlementResult{}, err
+	}
+	type payoutKey struct{ affiliateID, currency string }
+	groups := map[payoutKey][]domain.AffiliateEarning{}
+	keys := []payoutKey{}
+	for _, earning := range payable {
+		key := payoutKey{earning.AffiliateID, earning.Currency}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], earning)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].affiliateID != keys[j].affiliateID {
+			return keys[i].affiliateID < keys[j].affiliateID
+		}
+		return keys[i].currency < keys[j].currency
+	})
+	for _, key := range keys {
+		net := 0.0
+		for _, earning := range groups[key] {
+			net += earning.Net()
+		}
+		net = round2(net)
+		if net <= 0 || net < s.cfg.PayoutThreshold || s.payouts == nil {
+			continue
+		}
+		aff, err := s.affiliates.GetByID(ctx, key.affiliateID)
+		if err != nil {
+			return SettlementResult{}, err
+		}
+		if aff.Status != "active" {
+			continue
+		}
+		payout := domain.AffiliatePayout{PayoutID: "pay_" + uuid.NewString(), AffiliateID: aff.AffiliateID, Amount: net, Currency: key.currency, Status: "queued", QueuedAt: now, UpdatedAt: now}
+		if err := s.payouts.Create(ctx, payout); err != nil {
+			return SettlementResult{}, err
+		}
+		for _, earning := range groups[key] {
+			earning.Status = domain.EarningStatusPaid
+			earning.PayoutID = payout.PayoutID
+			earning.UpdatedAt = now
+			if err := s.earnings.Update(ctx, earning); err != nil {
+				return SettlementResult{}, err
+			}
+		}
+		if err := s.post(ctx, &aff, domain.LedgerEntry{Type: domain.LedgerEntryPayout, Amount: -net, Currency: key.currency, PayoutID: payout.PayoutID}, 0, -net, net, now); err != nil {
+			return SettlementResult{}, err
+		}
+		_ = s.enqueueAffiliatePayoutQueued(ctx, payout, uuid.NewString(), now)
+		result.Payouts = append(result.Payouts, payout)
+	}
+	return result, nil
+}
+
+// RunSettlement lets an admin settle earnings on demand instead of waiting
+// for the worker.
+func (s *Service) RunSettlement(ctx context.Context, actor Actor) (SettlementResult, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return SettlementResult{}, domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return SettlementResult{}, domain.ErrForbidden
+	}
+	return s.SettleEarnings(ctx)
+}
+
+// accrueCommission books the commission for a new attribution under the
+// affiliate's plan, plus any period bonus the attribution unlocks.
+func (s *Service) accrueCommission(ctx context.Context, aff *domain.Affiliate, attr domain.ReferralAttribution, traceID string, now time.Time) (domain.AffiliateEarning, error) {
+	plan := s.planFor(ctx, *aff)
+	volume, err := s.periodVolume(ctx, aff.AffiliateID, plan, now)
+	if err != nil {
+		return domain.AffiliateEarning{}, err
+	}
+	rate, basis := plan.Rate(volume, attr.ProductID, s.campaignFor(ctx, attr.ClickID))
+	earning := domain.AffiliateEarning{EarningID: "earn_" + uuid.NewString(), AffiliateID: aff.AffiliateID, AttributionID: attr.AttributionID, OrderID: attr.OrderID, Kind: domain.EarningKindCommission, PlanID: plan.PlanID, Rate: rate, RateBasis: basis, Amount: round2(attr.Amount * rate), Currency: attr.Currency, Status: domain.EarningStatusPending, AvailableAt: now.Add(plan.HoldPeriod), CreatedAt: now, UpdatedAt: now}
+	if err := s.earnings.Create(ctx, earning); err != nil {
+		return domain.AffiliateEarning{}, err
+	}
+	if err := s.post(ctx, aff, domain.LedgerEntry{Type: domain.LedgerEntryAccrued, Amount: earning.Amount, Currency: earning.Currency, EarningID: earning.EarningID, OrderID: earning.OrderID, Reason: basis}, earning.Amount, 0, 0, now); err != nil {
+		return domain.AffiliateEarning{}, err
+	}
+	for _, bonus := range plan.BonusesReached(volume) {
+		ref := "bonus:" + plan.PlanID + ":" + plan.PeriodKey(now) + ":" + bonus.Name
+		if s.ledger == nil {
+			break
+		}
+		if seen, err := s.ledger.HasReference(ctx, aff.AffiliateID, ref); err != nil {
+			return domain.AffiliateEarning{}, err
+		} else if seen {
+			continue
+		}
+		reward := domain.AffiliateEarning{EarningID: "earn_" + uuid.NewString(), AffiliateID: aff.AffiliateID, Kind: domain.EarningKindBonus, PlanID: plan.PlanID, Reference: ref, Amount: bonus.Amount, Currency: attr.Currency, Status: domain.EarningStatusPending, AvailableAt: now.Add(plan.HoldPeriod), CreatedAt: now, UpdatedAt: now}
+		if err := s.earnings.Create(ctx, reward); err != nil {
+			return domain.AffiliateEarning{}, err
+		}
+		if err := s.post(ctx, aff, domain.LedgerEntry{Type: domain.LedgerEntryBonus, Amount: reward.Amount, Currency: reward.Currency, EarningID: reward.EarningID, Reference: ref, Reason: bonus.Name}, reward.Amount, 0, 0, now); err != nil {
+			return domain.AffiliateEarning{}, err
+		}
+		_ = s.enqueueAffiliateEarningCalculated(ctx, reward, traceID, now)
+	}
+	return earning, nil
+}
+
+// planFor returns the affiliate's commission plan. Affiliates without one
+// fall back to the "default" plan if an admin has defined it, and otherwise
+// to a flat plan at their own default rate.
+func (s *Service) planFor(ctx context.Context, aff domain.Affiliate) domain.CommissionPlan {
+	if s.plans != nil {
+		for _, id := range []string{aff.PlanID, domain.DefaultCommissionPlanID} {
+			if id == "" {
+				continue
+			}
+			if plan, err := s.plans.GetByID(ctx, id); err == nil {
+				return plan
+			}
+		}
+	}
+	return domain.CommissionPlan{PlanID: domain.DefaultCommissionPlanID, Name: "Default", Period: domain.CommissionPeriodMonth, BaseRate: aff.DefaultRate, HoldPeriod: s.cfg.EarningHoldPeriod}
+}
+
+// periodVolume is the order value attributed to the affiliate in the plan's
+// current period, net of refunds.
+func (s *Service) periodVolume(ctx context.Context, affiliateID string, plan domain.CommissionPlan, now time.Time) (float64, error) {
+	return s.volumeInPeriod(ctx, affiliateID, plan, now)
+}
+
+// volumeInPeriod is the order value attributed to the affiliate in the plan
+// period containing at, net of refunds.
+func (s *Service) volumeInPeriod(ctx context.Context, affiliateID string, plan domain.CommissionPlan, at time.Time) (float64, error) {
+	attrs, err := s.attributions.ListByAffiliateID(ctx, affiliateID)
+	if err != nil {
+		return 0, err
+	}
+	key := plan.PeriodKey(at)
+	volume := 0.0
+	for _, attr := range activeAttributions(attrs) {
+		if plan.PeriodKey(attr.AttributedAt) == key {
+			volume += attr.Amount - attr.RefundedAmount
+		}
+	}
+	return round2(volume), nil
+}
+
+func (s *Service) campaignFor(ctx context.Context, clickID string) string {
+	if clickID == "" {
+		return ""
+	}
+	click, err := s.clicks.GetByID(ctx, clickID)
+	if err != nil {
+		return ""
+	}
+	link, err := s.links.GetByID(ctx, click.LinkID)
+	if err != nil {
+		return ""
+	}
+	return link.UTMCampaign
+}
+
+func (s *Service) attributionsForTransaction(ctx context.Context, transactionID string) ([]domain.ReferralAttribution, error) {
+	seen := map[string]bool{}
+	out := []domain.ReferralAttribution{}
+	add := func(rows []domain.ReferralAttribution) {
+		for _, row := range activeAttributions(rows) {
+			if !seen[row.AttributionID] {
+				seen[row.AttributionID] = true
+				out = append(out, row)
+			}
+		}
+	}
+	rows, err := s.attributions.ListByConversionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	add(rows)
+	// Manual attributions name the order, which for checkout is the
+	// transaction itself.
+	if rows, err = s.attributions.ListByOrderID(ctx, transactionID); err != nil {
+		return nil, err
+	}
+	add(rows)
+	return out, nil
+}
+
+// post applies balance deltas to the affiliate and records the movement in
+// the ledger. Every change to an affiliate's balances goes through here.
+func (s *Service) post(ctx context.Context, aff *domain.Affiliate, entry domain.LedgerEntry, pending, payable, paid float64, now time.Time) error {
+	aff.BalancePending = round2(aff.BalancePending + pending)
+	aff.BalancePayable = round2(aff.BalancePayable + payable)
+	aff.BalancePaid = round2(aff.BalancePaid + paid)
+	aff.UpdatedAt = now
+	if err := s.affiliates.Update(ctx, *aff); err != nil {
+		return err
+	}
+	if s.ledger == nil {
+		return nil
+	}
+	entry.EntryID = "led_" + uuid.NewString()
+	entry.AffiliateID = aff.AffiliateID
+	entry.Amount = round2(entry.Amount)
+	entry.BalancePending, entry.BalancePayable, entry.BalancePaid = aff.BalancePending, aff.BalancePayable, aff.BalancePaid
+	entry.CreatedAt = now
+	return s.ledger.Append(ctx, entry)
+}
+
+func requireAdmin(actor Actor) error {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ErrUnauthorized
+	}
+	if !isAdmin(actor) {
+		return domain.ErrForbidden
+	}
+	if strings.TrimSpace(actor.IdempotencyKey) == "" {
+		return domain.ErrIdempotencyRequired
+	}
+	return nil
+}
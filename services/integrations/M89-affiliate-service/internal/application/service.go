@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"math"
 	"strings"
 
 	"github.com/google/uuid"
@@ -86,8 +87,10 @@ func (s *Service) GetDashboard(ctx context.Context, actor Actor) (Dashboard, err
 	if err != nil {
 		return Dashboard{}, err
 	}
-	pending, _ := s.earnings.SumByAffiliateAndStatus(ctx, aff.AffiliateID, "pending")
-	paid, _ := s.earnings.SumByAffiliateAndStatus(ctx, aff.AffiliateID, "paid")
+	attrs = activeAttributions(attrs)
+	pending, _ := s.earnings.SumByAffiliateAndStatus(ctx, aff.AffiliateID, domain.EarningStatusPending)
+	payable, _ := s.earnings.SumByAffiliateAndStatus(ctx, aff.AffiliateID, domain.EarningStatusPayable)
+	paid, _ := s.earnings.SumByAffiliateAndStatus(ctx, aff.AffiliateID, domain.EarningStatusPaid)
 	links, _ := s.links.ListByAffiliateID(ctx, aff.AffiliateID)
 	countByLink := map[string]int{}
 	for _, c := range clicks {
@@ -110,7 +113,7 @@ func (s *Service) GetDashboard(ctx context.Context, actor Actor) (Dashboard, err
 	if len(clicks) > 0 {
 		conv = round2(float64(len(attrs)) / float64(len(clicks)) * 100)
 	}
-	return Dashboard{AffiliateID: aff.AffiliateID, TotalReferrals: len(links), TotalClicks: len(clicks), TotalAttributions: len(attrs), ConversionRate: conv, PendingEarnings: pending, PaidEarnings: paid, TopLinks: top}, nil
+	return Dashboard{AffiliateID: aff.AffiliateID, TotalReferrals: len(links), TotalClicks: len(clicks), TotalAttributions: len(attrs), ConversionRate: conv, PendingEarnings: round2(pending), PayableEarnings: round2(payable), PaidEarnings: round2(paid), TopLinks: top}, nil
 }
 
 func (s *Service) ListEarnings(ctx context.Context, actor Actor) ([]domain.AffiliateEarning, error) {
@@ -235,36 +238,45 @@ func (s *Service) RecordAttribution(ctx context.Context, actor Actor, in RecordA
 	if err := s.reserveIdempotency(ctx, actor.IdempotencyKey, requestHash); err != nil {
 		return domain.ReferralAttribution{}, err
 	}
-	if _, err := s.attributions.GetByOrderID(ctx, in.OrderID); err == nil {
-		return domain.ReferralAttribution{}, domain.ErrConflict
-	}
-	aff, err := s.affiliates.GetByID(ctx, in.AffiliateID)
+	// A manual attribution overrides whatever was attributed automatically,
+	// but never another manual one.
+	existing, err := s.attributions.ListByOrderID(ctx, in.OrderID)
 	if err != nil {
 		return domain.ReferralAttribution{}, err
 	}
 	now := s.nowFn()
-	attr := domain.ReferralAttribution{AttributionID: "attr_" + uuid.NewString(), AffiliateID: in.AffiliateID, ClickID: in.ClickID, ConversionID: in.ConversionID, OrderID: in.OrderID, Amount: in.Amount, Currency: in.Currency, AttributedAt: now}
-	if err := s.attributions.Create(ctx, attr); err != nil {
+	for _, row := range activeAttributions(existing) {
+		if row.MatchType != domain.MatchTypeManual {
+			continue
+		}
+		// A retry of a request whose accrual failed finds its own row
+		// without a commission; finish it instead of refusing the order.
+		if row.AffiliateID == in.AffiliateID && row.ConversionID == in.ConversionID && row.Amount == in.Amount && row.Currency == in.Currency {
+			if resumed, err := s.resumeAccrual(ctx, row, actor.RequestID, now); err != nil {
+				return domain.ReferralAttribution{}, err
+			} else if resumed {
+				_ = s.appendAudit(ctx, row.AffiliateID, "affiliate.attribution.recorded", actor.SubjectID, in.Reason, map[string]string{"order_id": row.OrderID, "conversion_id": row.ConversionID})
+				_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 201, row)
+				return row, nil
+			}
+		}
+		return domain.ReferralAttribution{}, domain.ErrConflict
+	}
+	if _, err := s.affiliates.GetByID(ctx, in.AffiliateID); err != nil {
 		return domain.ReferralAttribution{}, err
 	}
-	earningAmount := round2(in.Amount * aff.DefaultRate)
-	earning := domain.AffiliateEarning{EarningID: "earn_" + uuid.NewString(), AffiliateID: aff.AffiliateID, AttributionID: attr.AttributionID, OrderID: attr.OrderID, Amount: earningAmount, Currency: attr.Currency, Status: "pending", CreatedAt: now, UpdatedAt: now}
-	if err := s.earnings.Create(ctx, earning); err != nil {
+	if err := s.overrideAttributions(ctx, existing, actor.SubjectID, in.Reason, now); err != nil {
 		return domain.ReferralAttribution{}, err
 	}
-	aff.BalancePending = round2(aff.BalancePending + earningAmount)
-	aff.UpdatedAt = now
-	if err := s.affiliates.Update(ctx, aff); err != nil {
+	aff, err := s.affiliates.GetByID(ctx, in.AffiliateID)
+	if err != nil {
 		return domain.ReferralAttribution{}, err
 	}
-	if aff.BalancePending >= s.cfg.PayoutThreshold && s.payouts != nil {
-		payout := domain.AffiliatePayout{PayoutID: "pay_" + uuid.NewString(), AffiliateID: aff.AffiliateID, Amount: aff.BalancePending, Currency: attr.Currency, Status: "queued", QueuedAt: now, UpdatedAt: now}
-		_ = s.payouts.Create(ctx, payout)
-		_ = s.enqueueAffiliatePayoutQueued(ctx, payout, uuid.NewString(), now)
+	attr := domain.ReferralAttribution{AttributionID: "attr_" + uuid.NewString(), AffiliateID: in.AffiliateID, ClickID: in.ClickID, ConversionID: in.ConversionID, OrderID: in.OrderID, Amount: in.Amount, Currency: in.Currency, MatchType: domain.MatchTypeManual, Weight: 1, Status: domain.AttributionStatusActive, AttributedAt: now}
+	if err := s.creditAttribution(ctx, aff, attr, actor.RequestID, now); err != nil {
+		return domain.ReferralAttribution{}, err
 	}
 	_ = s.appendAudit(ctx, aff.AffiliateID, "affiliate.attribution.recorded", actor.SubjectID, in.Reason, map[string]string{"order_id": attr.OrderID, "conversion_id": attr.ConversionID})
-	_ = s.enqueueAffiliateAttributionCreated(ctx, attr, uuid.NewString(), now)
-	_ = s.enqueueAffiliateEarningCalculated(ctx, earning, uuid.NewString(), now)
 	_ = s.completeIdempotencyJSON(ctx, actor.IdempotencyKey, 201, attr)
 	return attr, nil
 }
@@ -296,7 +308,7 @@ func (s *Service) appendAudit(ctx context.Context, affiliateID, action, actorID,
 }
 
 func isAdmin(actor Actor) bool  { return strings.ToLower(strings.TrimSpace(actor.Role)) == "admin" }
-func round2(v float64) float64  { return float64(int(v*100+0.5)) / 100 }
+func round2(v float64) float64  { return math.Round(v*100) / 100 }
 func sha256Hex(v string) string { h := sha256.Sum256([]byte(v)); return hex.EncodeToString(h[:]) }
 func hashJSON(v any) string {
 	raw, _ := json.Marshal(v)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/ports"
@@ -17,6 +18,15 @@ func (s *Service) HandleCanonicalEvent(ctx context.Context, envelope contracts.E
 	if err := validateEnvelope(envelope); err != nil {
 		return err
 	}
+	if !domain.IsCanonicalInputEvent(envelope.EventType) {
+		return domain.ErrUnsupportedEventType
+	}
+	if strings.TrimSpace(envelope.EventClass) != "" && envelope.EventClass != domain.CanonicalEventClass(envelope.EventType) {
+		return domain.ErrUnsupportedEventClass
+	}
+	if envelope.PartitionKeyPath != domain.CanonicalPartitionKeyPath(envelope.EventType) {
+		return domain.ErrInvalidEnvelope
+	}
 	if s.eventDedup != nil {
 		dup, err := s.eventDedup.IsDuplicate(ctx, envelope.EventID, s.nowFn())
 		if err != nil {
@@ -25,16 +35,71 @@ func (s *Service) HandleCanonicalEvent(ctx context.Context, envelope contracts.E
 		if dup {
 			return nil
 		}
-		if err := s.eventDedup.MarkProcessed(ctx, envelope.EventID, envelope.EventType, s.nowFn().Add(s.cfg.EventDedupTTL)); err != nil {
-			return err
-		}
 	}
-	if !domain.IsCanonicalInputEvent(envelope.EventType) {
-		return domain.ErrUnsupportedEventType
+	if err := s.applyInboundEvent(ctx, envelope); err != nil {
+		return err
+	}
+	if s.eventDedup != nil {
+		return s.eventDedup.MarkProcessed(ctx, envelope.EventID, envelope.EventType, s.nowFn().Add(s.cfg.EventDedupTTL))
 	}
 	return nil
 }
 
+func (s *Service) applyInboundEvent(ctx context.Context, envelope contracts.EventEnvelope) error {
+	switch envelope.EventType {
+	case domain.EventTransactionSucceeded:
+		var p contracts.TransactionSucceededPayload
+		if err := json.Unmarshal(envelope.Data, &p); err != nil {
+			return domain.ErrInvalidEnvelope
+		}
+		if strings.TrimSpace(p.TransactionID) == "" || strings.TrimSpace(p.TransactionID) != strings.TrimSpace(envelope.PartitionKey) {
+			return domain.ErrInvalidEnvelope
+		}
+		convertedAt := envelope.OccurredAt
+		if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(p.OccurredAt)); err == nil {
+			convertedAt = t
+		}
+		_, err := s.AttributeConversion(ctx, ConversionInput{
+			ConversionID:  p.TransactionID,
+			OrderID:       p.TransactionID,
+			ProductID:     p.ProductID,
+			UserID:        p.UserID,
+			Amount:        p.Amount,
+			Currency:      p.Currency,
+			CookieID:      p.ReferralCookieID,
+			IPHash:        p.IPHash,
+			UserAgentHash: p.UserAgentHash,
+			ConvertedAt:   convertedAt.UTC(),
+			TraceID:       envelope.TraceID,
+		})
+		return err
+	case domain.EventTransactionRefunded, domain.EventTransactionChargeback:
+		var p contracts.TransactionReversalPayload
+		if err := json.Unmarshal(envelope.Data, &p); err != nil {
+			return domain.ErrInvalidEnvelope
+		}
+		if strings.TrimSpace(p.TransactionID) == "" || strings.TrimSpace(p.TransactionID) != strings.TrimSpace(envelope.PartitionKey) {
+			return domain.ErrInvalidEnvelope
+		}
+		kind, reversalID := "refund", p.RefundID
+		if envelope.EventType == domain.EventTransactionChargeback {
+			kind, reversalID = "chargeback", p.ChargebackID
+		}
+		_, err := s.ProcessReversal(ctx, ReversalInput{
+			Kind:          kind,
+			TransactionID: p.TransactionID,
+			ReversalID:    reversalID,
+			EventID:       envelope.EventID,
+			Amount:        p.Amount,
+			Reason:        p.Reason,
+			TraceID:       envelope.TraceID,
+		})
+		return err
+	default:
+		return domain.ErrUnsupportedEventType
+	}
+}
+
 func (s *Service) FlushOutbox(ctx context.Context) error {
 	if s.outbox == nil {
 		return nil
@@ -84,7 +149,7 @@ func (s *Service) enqueueEvent(ctx context.Context, eventType, traceID string, d
 	if strings.TrimSpace(traceID) == "" {
 		traceID = uuid.NewString()
 	}
-	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: eventType, EventClass: domain.CanonicalEventClass(eventType), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(eventType), PartitionKey: affiliateID, SourceService: s.cfg.ServiceName, TraceID: traceID, SchemaVersion: "v1", Data: b}
+	env := contracts.EventEnvelope{EventID: uuid.NewString(), EventType: eventType, EventClass: domain.CanonicalEventClass(eventType), OccurredAt: now, PartitionKeyPath: domain.CanonicalPartitionKeyPath(eventType), PartitionKey: affiliateID, SourceService: s.cfg.ServiceName, TraceID: observability.TraceParent(ctx, traceID), SchemaVersion: "v1", Data: b}
 	return s.outbox.Enqueue(ctx, ports.OutboxRecord{RecordID: uuid.NewString(), EventClass: env.EventClass, Envelope: env, CreatedAt: now})
 }
 
@@ -92,7 +157,7 @@ func (s *Service) enqueueAffiliateClickTracked(ctx context.Context, click domain
 	return s.enqueueEvent(ctx, domain.EventAffiliateClickTracked, traceID, contracts.AffiliateClickTrackedPayload{AffiliateID: click.AffiliateID, LinkID: click.LinkID, ReferrerURL: click.ReferrerURL, IPHash: click.IPHash, TrackedAt: click.ClickedAt.UTC().Format(time.RFC3339)}, click.AffiliateID, now)
 }
 func (s *Service) enqueueAffiliateAttributionCreated(ctx context.Context, attr domain.ReferralAttribution, traceID string, now time.Time) error {
-	return s.enqueueEvent(ctx, domain.EventAffiliateAttributionCreated, traceID, contracts.AffiliateAttributionCreatedPayload{AffiliateID: attr.AffiliateID, ConversionID: attr.ConversionID, OrderID: attr.OrderID, Amount: attr.Amount, Currency: attr.Currency, AttributedAt: attr.AttributedAt.UTC().Format(time.RFC3339)}, attr.AffiliateID, now)
+	return s.enqueueEvent(ctx, domain.EventAffiliateAttributionCreated, traceID, contracts.AffiliateAttributionCreatedPayload{AffiliateID: attr.AffiliateID, ConversionID: attr.ConversionID, OrderID: attr.OrderID, Amount: attr.Amount, Currency: attr.Currency, ClickID: attr.ClickID, Model: attr.Model, MatchType: attr.MatchType, Weight: attr.Weight, AttributedAt: attr.AttributedAt.UTC().Format(time.RFC3339)}, attr.AffiliateID, now)
 }
 func (s *Service) enqueueAffiliateLinkCreated(ctx context.Context, link domain.ReferralLink, traceID string, now time.Time) error {
 	return s.enqueueEvent(ctx, domain.EventAffiliateLinkCreated, traceID, contracts.AffiliateLinkCreatedPayload{AffiliateID: link.AffiliateID, LinkID: link.LinkID, Token: link.Token, Channel: link.Channel, CreatedAt: link.CreatedAt.UTC().Format(time.RFC3339)}, link.AffiliateID, now)
@@ -3,6 +3,7 @@ package application
 import (
 	"time"
 
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/ports"
 )
 
@@ -16,6 +17,13 @@ type Config struct {
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
 	OutboxFlushBatchSize int
+
+	AttributionModel    string
+	AttributionLookback time.Duration
+	FingerprintLookback time.Duration
+	TimeDecayHalfLife   time.Duration
+
+	EarningHoldPeriod time.Duration
 }
 
 type Actor struct {
@@ -54,6 +62,7 @@ type Dashboard struct {
 	TotalAttributions int
 	ConversionRate    float64
 	PendingEarnings   float64
+	PayableEarnings   float64
 	PaidEarnings      float64
 	TopLinks          []TopLinkMetric
 }
@@ -88,6 +97,75 @@ type RecordAttributionInput struct {
 	Reason       string
 }
 
+type ConversionInput struct {
+	ConversionID  string
+	OrderID       string
+	ProductID     string
+	UserID        string
+	Amount        float64
+	Currency      string
+	CookieID      string
+	ClientIP      string
+	UserAgent     string
+	IPHash        string
+	UserAgentHash string
+	ConvertedAt   time.Time
+	TraceID       string
+}
+
+type ConversionResult struct {
+	ConversionID string
+	OrderID      string
+	Outcome      string
+	MatchType    string
+	Model        string
+	Attributions []domain.ReferralAttribution
+}
+
+type UpsertCommissionPlanInput struct {
+	PlanID        string
+	Name          string
+	Period        string
+	BaseRate      float64
+	Tiers         []domain.CommissionTier
+	ProductRates  map[string]float64
+	CampaignRates map[string]float64
+	Bonuses       []domain.CommissionBonus
+	HoldPeriod    *time.Duration
+}
+
+type AssignCommissionPlanInput struct {
+	AffiliateID string
+	PlanID      string
+}
+
+// ReversalInput describes a refund or chargeback against a transaction. A
+// zero Amount reverses the whole transaction.
+// ReversalInput is one refund or chargeback. ReversalID identifies it across
+// redeliveries; without one the delivering event's EventID does.
+type ReversalInput struct {
+	Kind          string
+	TransactionID string
+	ReversalID    string
+	EventID       string
+	Amount        float64
+	Reason        string
+	TraceID       string
+}
+
+type ReversalResult struct {
+	TransactionID string
+	Attributions  int
+	Reversed      int
+	Clawbacks     int
+	ClawedBack    float64
+}
+
+type SettlementResult struct {
+	Released int
+	Payouts  []domain.AffiliatePayout
+}
+
 type Service struct {
 	cfg Config
 
@@ -97,6 +175,8 @@ type Service struct {
 	attributions ports.ReferralAttributionRepository
 	earnings     ports.AffiliateEarningRepository
 	payouts      ports.AffiliatePayoutRepository
+	plans        ports.CommissionPlanRepository
+	ledger       ports.AffiliateLedgerRepository
 	auditLogs    ports.AffiliateAuditLogRepository
 	idempotency  ports.IdempotencyRepository
 	eventDedup   ports.EventDedupRepository
@@ -118,6 +198,8 @@ type Dependencies struct {
 	Attributions ports.ReferralAttributionRepository
 	Earnings     ports.AffiliateEarningRepository
 	Payouts      ports.AffiliatePayoutRepository
+	Plans        ports.CommissionPlanRepository
+	Ledger       ports.AffiliateLedgerRepository
 	AuditLogs    ports.AffiliateAuditLogRepository
 	Idempotency  ports.IdempotencyRepository
 	EventDedup   ports.EventDedupRepository
@@ -157,5 +239,24 @@ func NewService(deps Dependencies) *Service {
 	if cfg.OutboxFlushBatchSize <= 0 {
 		cfg.OutboxFlushBatchSize = 100
 	}
-	return &Service{cfg: cfg, affiliates: deps.Affiliates, links: deps.Links, clicks: deps.Clicks, attributions: deps.Attributions, earnings: deps.Earnings, payouts: deps.Payouts, auditLogs: deps.AuditLogs, idempotency: deps.Idempotency, eventDedup: deps.EventDedup, outbox: deps.Outbox, domainEvents: deps.DomainEvents, analytics: deps.Analytics, dlq: deps.DLQ, nowFn: func() time.Time { return time.Now().UTC() }}
+	cfg.AttributionModel = domain.NormalizeAttributionModel(cfg.AttributionModel)
+	if !domain.IsAttributionModel(cfg.AttributionModel) {
+		cfg.AttributionModel = domain.AttributionModelLastClick
+	}
+	if cfg.AttributionLookback <= 0 {
+		cfg.AttributionLookback = cfg.ReferralCookieTTL
+	}
+	if cfg.FingerprintLookback <= 0 {
+		cfg.FingerprintLookback = 24 * time.Hour
+	}
+	if cfg.FingerprintLookback > cfg.AttributionLookback {
+		cfg.FingerprintLookback = cfg.AttributionLookback
+	}
+	if cfg.TimeDecayHalfLife <= 0 {
+		cfg.TimeDecayHalfLife = 7 * 24 * time.Hour
+	}
+	if cfg.EarningHoldPeriod < 0 {
+		cfg.EarningHoldPeriod = 0
+	}
+	return &Service{cfg: cfg, affiliates: deps.Affiliates, links: deps.Links, clicks: deps.Clicks, attributions: deps.Attributions, earnings: deps.Earnings, payouts: deps.Payouts, plans: deps.Plans, ledger: deps.Ledger, auditLogs: deps.AuditLogs, idempotency: deps.Idempotency, eventDedup: deps.EventDedup, outbox: deps.Outbox, domainEvents: deps.DomainEvents, analytics: deps.Analytics, dlq: deps.DLQ, nowFn: func() time.Time { return time.Now().UTC() }}
 }
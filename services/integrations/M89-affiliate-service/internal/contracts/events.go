@@ -43,9 +43,30 @@ type AffiliateAttributionCreatedPayload struct {
 	OrderID      string  `json:"order_id"`
 	Amount       float64 `json:"amount"`
 	Currency     string  `json:"currency,omitempty"`
+	ClickID      string  `json:"click_id,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	MatchType    string  `json:"match_type,omitempty"`
+	Weight       float64 `json:"weight,omitempty"`
 	AttributedAt string  `json:"attributed_at"`
 }
 
+// TransactionSucceededPayload is the checkout signal M89 attributes, as
+// M39 publishes it. The referral cookie and the SHA-256 hashes of the
+// buyer's IP and user agent are captured from the checkout request.
+type TransactionSucceededPayload struct {
+	TransactionID    string  `json:"transaction_id"`
+	UserID           string  `json:"user_id"`
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	Provider         string  `json:"provider"`
+	CampaignID       string  `json:"campaign_id,omitempty"`
+	ProductID        string  `json:"product_id,omitempty"`
+	ReferralCookieID string  `json:"referral_cookie_id,omitempty"`
+	IPHash           string  `json:"ip_hash,omitempty"`
+	UserAgentHash    string  `json:"user_agent_hash,omitempty"`
+	OccurredAt       string  `json:"occurred_at"`
+}
+
 type AffiliateLinkCreatedPayload struct {
 	AffiliateID string `json:"affiliate_id"`
 	LinkID      string `json:"link_id"`
@@ -72,3 +93,18 @@ type AffiliatePayoutQueuedPayload struct {
 	Status      string  `json:"status"`
 	QueuedAt    string  `json:"queued_at"`
 }
+
+// TransactionReversalPayload is shared by transaction.refunded and
+// transaction.charged_back, which differ only in carrying refund_id or
+// chargeback_id. A zero amount reverses the whole transaction.
+type TransactionReversalPayload struct {
+	TransactionID string  `json:"transaction_id"`
+	RefundID      string  `json:"refund_id,omitempty"`
+	ChargebackID  string  `json:"chargeback_id,omitempty"`
+	UserID        string  `json:"user_id"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Provider      string  `json:"provider"`
+	OccurredAt    string  `json:"occurred_at"`
+	Reason        string  `json:"reason"`
+}
@@ -31,6 +31,7 @@ type DashboardResponse struct {
 	TotalAttributions int       `json:"total_attributions"`
 	ConversionRate    float64   `json:"conversion_rate"`
 	PendingEarnings   float64   `json:"pending_earnings"`
+	PayableEarnings   float64   `json:"payable_earnings"`
 	PaidEarnings      float64   `json:"paid_earnings"`
 	TopLinks          []TopLink `json:"top_links"`
 }
@@ -42,11 +43,15 @@ type TopLink struct {
 }
 
 type EarningResponse struct {
-	EarningID string  `json:"earning_id"`
-	OrderID   string  `json:"order_id"`
-	Amount    float64 `json:"amount"`
-	Status    string  `json:"status"`
-	CreatedAt string  `json:"created_at"`
+	EarningID   string  `json:"earning_id"`
+	OrderID     string  `json:"order_id"`
+	Kind        string  `json:"kind"`
+	Amount      float64 `json:"amount"`
+	ClawedBack  float64 `json:"clawed_back"`
+	Rate        float64 `json:"rate,omitempty"`
+	Status      string  `json:"status"`
+	AvailableAt string  `json:"available_at"`
+	CreatedAt   string  `json:"created_at"`
 }
 
 type EarningsListResponse struct {
@@ -88,3 +93,80 @@ type ManualAttributionResponse struct {
 	Amount        float64 `json:"amount"`
 	AttributedAt  string  `json:"attributed_at"`
 }
+
+type CommissionPlanRequest struct {
+	PlanID        string                   `json:"plan_id"`
+	Name          string                   `json:"name"`
+	Period        string                   `json:"period,omitempty"`
+	BaseRate      float64                  `json:"base_rate"`
+	Tiers         []CommissionTierRequest  `json:"tiers,omitempty"`
+	ProductRates  map[string]float64       `json:"product_rates,omitempty"`
+	CampaignRates map[string]float64       `json:"campaign_rates,omitempty"`
+	Bonuses       []CommissionBonusRequest `json:"bonuses,omitempty"`
+	HoldDays      *int                     `json:"hold_days,omitempty"`
+}
+
+type CommissionTierRequest struct {
+	MinVolume float64 `json:"min_volume"`
+	Rate      float64 `json:"rate"`
+}
+
+type CommissionBonusRequest struct {
+	Name      string  `json:"name"`
+	MinVolume float64 `json:"min_volume"`
+	Amount    float64 `json:"amount"`
+}
+
+type CommissionPlanResponse struct {
+	PlanID        string                   `json:"plan_id"`
+	Name          string                   `json:"name"`
+	Period        string                   `json:"period"`
+	BaseRate      float64                  `json:"base_rate"`
+	Tiers         []CommissionTierRequest  `json:"tiers"`
+	ProductRates  map[string]float64       `json:"product_rates"`
+	CampaignRates map[string]float64       `json:"campaign_rates"`
+	Bonuses       []CommissionBonusRequest `json:"bonuses"`
+	HoldDays      int                      `json:"hold_days"`
+	UpdatedAt     string                   `json:"updated_at"`
+}
+
+type CommissionPlanListResponse struct {
+	Items []CommissionPlanResponse `json:"items"`
+}
+
+type AssignPlanRequest struct {
+	PlanID string `json:"plan_id"`
+}
+
+type AssignPlanResponse struct {
+	AffiliateID string `json:"affiliate_id"`
+	PlanID      string `json:"plan_id"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type LedgerEntryResponse struct {
+	EntryID        string  `json:"entry_id"`
+	Type           string  `json:"type"`
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+	EarningID      string  `json:"earning_id,omitempty"`
+	PayoutID       string  `json:"payout_id,omitempty"`
+	OrderID        string  `json:"order_id,omitempty"`
+	Reference      string  `json:"reference,omitempty"`
+	Reason         string  `json:"reason,omitempty"`
+	BalancePending float64 `json:"balance_pending"`
+	BalancePayable float64 `json:"balance_payable"`
+	BalancePaid    float64 `json:"balance_paid"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+type LedgerResponse struct {
+	AffiliateID string                `json:"affiliate_id,omitempty"`
+	Items       []LedgerEntryResponse `json:"items"`
+}
+
+type SettlementResponse struct {
+	Released      int     `json:"released"`
+	PayoutsQueued int     `json:"payouts_queued"`
+	PayoutTotal   float64 `json:"payout_total"`
+}
@@ -7,7 +7,9 @@ type Affiliate struct {
 	UserID         string    `json:"user_id"`
 	Status         string    `json:"status"`
 	DefaultRate    float64   `json:"default_rate"`
+	PlanID         string    `json:"plan_id,omitempty"`
 	BalancePending float64   `json:"balance_pending"`
+	BalancePayable float64   `json:"balance_payable"`
 	BalancePaid    float64   `json:"balance_paid"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
@@ -37,14 +39,21 @@ type ReferralClick struct {
 }
 
 type ReferralAttribution struct {
-	AttributionID string    `json:"attribution_id"`
-	AffiliateID   string    `json:"affiliate_id"`
-	ClickID       string    `json:"click_id"`
-	ConversionID  string    `json:"conversion_id"`
-	OrderID       string    `json:"order_id"`
-	Amount        float64   `json:"amount"`
-	Currency      string    `json:"currency"`
-	AttributedAt  time.Time `json:"attributed_at"`
+	AttributionID  string    `json:"attribution_id"`
+	AffiliateID    string    `json:"affiliate_id"`
+	ClickID        string    `json:"click_id"`
+	ConversionID   string    `json:"conversion_id"`
+	OrderID        string    `json:"order_id"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	ProductID      string    `json:"product_id,omitempty"`
+	RefundedAmount float64   `json:"refunded_amount"`
+	Reversals      []string  `json:"reversals,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	MatchType      string    `json:"match_type"`
+	Weight         float64   `json:"weight"`
+	Status         string    `json:"status"`
+	AttributedAt   time.Time `json:"attributed_at"`
 }
 
 type AffiliateEarning struct {
@@ -52,13 +61,24 @@ type AffiliateEarning struct {
 	AffiliateID   string    `json:"affiliate_id"`
 	AttributionID string    `json:"attribution_id"`
 	OrderID       string    `json:"order_id"`
+	Kind          string    `json:"kind"`
+	PlanID        string    `json:"plan_id,omitempty"`
+	Rate          float64   `json:"rate,omitempty"`
+	RateBasis     string    `json:"rate_basis,omitempty"`
+	Reference     string    `json:"reference,omitempty"`
 	Amount        float64   `json:"amount"`
+	ClawedBack    float64   `json:"clawed_back"`
 	Currency      string    `json:"currency"`
 	Status        string    `json:"status"`
+	AvailableAt   time.Time `json:"available_at"`
+	PayoutID      string    `json:"payout_id,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// Net is what the earning is still worth after clawbacks.
+func (e AffiliateEarning) Net() float64 { return e.Amount - e.ClawedBack }
+
 type AffiliatePayout struct {
 	PayoutID    string    `json:"payout_id"`
 	AffiliateID string    `json:"affiliate_id"`
@@ -78,3 +98,14 @@ type AffiliateAuditLog struct {
 	Metadata    map[string]string `json:"metadata"`
 	CreatedAt   time.Time         `json:"created_at"`
 }
+
+// HasReversal reports whether the refund or chargeback ref is among the
+// reversals already applied to the attribution.
+func (a ReferralAttribution) HasReversal(ref string) bool {
+	for _, seen := range a.Reversals {
+		if seen == ref {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AttributionModelLastClick  = "last_click"
+	AttributionModelFirstClick = "first_click"
+	AttributionModelLinear     = "linear"
+	AttributionModelTimeDecay  = "time_decay"
+)
+
+const (
+	MatchTypeCookie      = "cookie"
+	MatchTypeFingerprint = "fingerprint"
+	MatchTypeManual      = "manual"
+)
+
+const (
+	AttributionStatusActive     = "active"
+	AttributionStatusOverridden = "overridden"
+)
+
+// Outcomes of resolving a conversion to referral clicks.
+const (
+	ConversionOutcomeAttributed     = "attributed"
+	ConversionOutcomeUnmatched      = "unmatched"
+	ConversionOutcomeDuplicateOrder = "duplicate_order"
+	ConversionOutcomeSelfReferral   = "self_referral"
+)
+
+func IsAttributionModel(model string) bool {
+	switch model {
+	case AttributionModelLastClick, AttributionModelFirstClick, AttributionModelLinear, AttributionModelTimeDecay:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeAttributionModel accepts the hyphenated spellings used in config
+// ("last-click") as well as the canonical underscored ones.
+func NormalizeAttributionModel(model string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(model)), "-", "_")
+}
+
+// AttributionShare is the part of a conversion credited to one affiliate.
+// ClickID is that affiliate's latest click on the path to conversion.
+type AttributionShare struct {
+	AffiliateID string
+	ClickID     string
+	Weight      float64
+}
+
+// SplitAttribution divides credit for a conversion at convertedAt across the
+// clicks that led to it. Several clicks from the same affiliate add up to a
+// single share. Time decay halves a click's weight for every halfLife
+// between the click and the conversion. Shares are returned largest first.
+func SplitAttribution(model string, clicks []ReferralClick, convertedAt time.Time, halfLife time.Duration) []AttributionShare {
+	if len(clicks) == 0 {
+		return nil
+	}
+	path := append([]ReferralClick(nil), clicks...)
+	sort.SliceStable(path, func(i, j int) bool { return path[i].ClickedAt.Before(path[j].ClickedAt) })
+
+	weights := make([]float64, len(path))
+	switch model {
+	case AttributionModelFirstClick:
+		weights[0] = 1
+	case AttributionModelLinear:
+		for i := range weights {
+			weights[i] = 1 / float64(len(path))
+		}
+	case AttributionModelTimeDecay:
+		if halfLife <= 0 {
+			halfLife = 7 * 24 * time.Hour
+		}
+		total := 0.0
+		for i, c := range path {
+			age := convertedAt.Sub(c.ClickedAt)
+			if age < 0 {
+				age = 0
+			}
+			weights[i] = math.Exp2(-float64(age) / float64(halfLife))
+			total += weights[i]
+		}
+		for i := range weights {
+			weights[i] /= total
+		}
+	default:
+		weights[len(weights)-1] = 1
+	}
+
+	byAffiliate := map[string]*AttributionShare{}
+	order := []string{}
+	for i, c := range path {
+		if weights[i] == 0 {
+			continue
+		}
+		share, ok := byAffiliate[c.AffiliateID]
+		if !ok {
+			share = &AttributionShare{AffiliateID: c.AffiliateID}
+			byAffiliate[c.AffiliateID] = share
+			order = append(order, c.AffiliateID)
+		}
+		share.ClickID = c.ClickID
+		share.Weight += weights[i]
+	}
+	out := make([]AttributionShare, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byAffiliate[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight > out[j].Weight
+		}
+		return out[i].AffiliateID < out[j].AffiliateID
+	})
+	return out
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	CommissionPeriodMonth   = "month"
+	CommissionPeriodQuarter = "quarter"
+)
+
+const DefaultCommissionPlanID = "default"
+
+// Earning kinds. Commissions come from attributed orders, bonuses from
+// period volume, and clawbacks are negative earnings that offset a paid
+// commission against the next payout.
+const (
+	EarningKindCommission = "commission"
+	EarningKindBonus      = "bonus"
+	EarningKindClawback   = "clawback"
+)
+
+// Earnings are held as pending until their hold period ends, then become
+// payable and are marked paid once a payout includes them. Unpaid earnings
+// that are fully refunded end up reversed.
+const (
+	EarningStatusPending  = "pending"
+	EarningStatusPayable  = "payable"
+	EarningStatusPaid     = "paid"
+	EarningStatusReversed = "reversed"
+)
+
+const (
+	LedgerEntryAccrued  = "earning_accrued"
+	LedgerEntryBonus    = "bonus_accrued"
+	LedgerEntryReleased = "earning_released"
+	LedgerEntryReversed = "earning_reversed"
+	LedgerEntryClawback = "clawback"
+	LedgerEntryPayout   = "payout"
+)
+
+type CommissionTier struct {
+	MinVolume float64 `json:"min_volume"`
+	Rate      float64 `json:"rate"`
+}
+
+// CommissionBonus pays a fixed amount once per period when the affiliate's
+// attributed volume in that period reaches MinVolume.
+type CommissionBonus struct {
+	Name      string  `json:"name"`
+	MinVolume float64 `json:"min_volume"`
+	Amount    float64 `json:"amount"`
+}
+
+type CommissionPlan struct {
+	PlanID        string             `json:"plan_id"`
+	Name          string             `json:"name"`
+	Period        string             `json:"period"`
+	BaseRate      float64            `json:"base_rate"`
+	Tiers         []CommissionTier   `json:"tiers"`
+	ProductRates  map[string]float64 `json:"product_rates"`
+	CampaignRates map[string]float64 `json:"campaign_rates"`
+	Bonuses       []CommissionBonus  `json:"bonuses"`
+	HoldPeriod    time.Duration      `json:"hold_period"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+func (p CommissionPlan) Validate() error {
+	if strings.TrimSpace(p.PlanID) == "" || strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: plan id and name are required", ErrInvalidInput)
+	}
+	if p.Period != CommissionPeriodMonth && p.Period != CommissionPeriodQuarter {
+		return fmt.Errorf("%w: period must be month or quarter", ErrInvalidInput)
+	}
+	if !validRate(p.BaseRate) {
+		return fmt.Errorf("%w: base rate must be between 0 and 1", ErrInvalidInput)
+	}
+	if p.HoldPeriod < 0 {
+		return fmt.Errorf("%w: hold period must not be negative", ErrInvalidInput)
+	}
+	for i, tier := range p.Tiers {
+		if tier.MinVolume <= 0 || !validRate(tier.Rate) {
+			return fmt.Errorf("%w: tier %d needs a positive volume and a rate between 0 and 1", ErrInvalidInput, i+1)
+		}
+		if i > 0 && tier.MinVolume <= p.Tiers[i-1].MinVolume {
+			return fmt.Errorf("%w: tiers must be ordered by increasing volume", ErrInvalidInput)
+		}
+	}
+	for key, rate := range p.ProductRates {
+		if strings.TrimSpace(key) == "" || !validRate(rate) {
+			return fmt.Errorf("%w: product rate %q is invalid", ErrInvalidInput, key)
+		}
+	}
+	for key, rate := range p.CampaignRates {
+		if strings.TrimSpace(key) == "" || !validRate(rate) {
+			return fmt.Errorf("%w: campaign rate %q is invalid", ErrInvalidInput, key)
+		}
+	}
+	seen := map[string]bool{}
+	for _, bonus := range p.Bonuses {
+		if strings.TrimSpace(bonus.Name) == "" || bonus.MinVolume <= 0 || bonus.Amount <= 0 || seen[bonus.Name] {
+			return fmt.Errorf("%w: bonus %q needs a unique name, a volume and an amount", ErrInvalidInput, bonus.Name)
+		}
+		seen[bonus.Name] = true
+	}
+	return nil
+}
+
+// PeriodStart is the start of the commission period containing t, in UTC.
+func (p CommissionPlan) PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	month := t.Month()
+	if p.Period == CommissionPeriodQuarter {
+		month = time.Month((int(month)-1)/3*3 + 1)
+	}
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodKey names the period containing t, e.g. "2026-03" or "2026-Q1".
+func (p CommissionPlan) PeriodKey(t time.Time) string {
+	start := p.PeriodStart(t)
+	if p.Period == CommissionPeriodQuarter {
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	}
+	return start.Format("2006-01")
+}
+
+// Rate picks the commission rate for an order. A product rate wins over a
+// campaign rate, which wins over the volume tier reached in the period.
+// The second value names where the rate came from.
+func (p CommissionPlan) Rate(periodVolume float64, productID, campaign string) (float64, string) {
+	if rate, ok := p.ProductRates[productID]; ok && productID != "" {
+		return rate, "product:" + productID
+	}
+	if rate, ok := p.CampaignRates[campaign]; ok && campaign != "" {
+		return rate, "campaign:" + campaign
+	}
+	rate, basis := p.BaseRate, "base"
+	for i, tier := range p.Tiers {
+		if periodVolume >= tier.MinVolume {
+			rate, basis = tier.Rate, fmt.Sprintf("tier:%d", i+1)
+		}
+	}
+	return rate, basis
+}
+
+// BonusesReached lists the bonuses whose volume threshold periodVolume meets,
+// lowest threshold first.
+func (p CommissionPlan) BonusesReached(periodVolume float64) []CommissionBonus {
+	out := []CommissionBonus{}
+	for _, bonus := range p.Bonuses {
+		if periodVolume >= bonus.MinVolume {
+			out = append(out, bonus)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].MinVolume < out[j].MinVolume })
+	return out
+}
+
+// LedgerEntry records one movement of an affiliate's balances. Amount is
+// signed from the affiliate's point of view and the balances are the ones
+// after the movement.
+type LedgerEntry struct {
+	EntryID        string    `json:"entry_id"`
+	AffiliateID    string    `json:"affiliate_id"`
+	Type           string    `json:"type"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	EarningID      string    `json:"earning_id,omitempty"`
+	PayoutID       string    `json:"payout_id,omitempty"`
+	OrderID        string    `json:"order_id,omitempty"`
+	Reference      string    `json:"reference,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	BalancePending float64   `json:"balance_pending"`
+	BalancePayable float64   `json:"balance_payable"`
+	BalancePaid    float64   `json:"balance_paid"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func validRate(rate float64) bool { return rate >= 0 && rate <= 1 }
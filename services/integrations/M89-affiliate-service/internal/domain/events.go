@@ -14,7 +14,22 @@ const (
 	EventAffiliatePayoutQueued       = "affiliate.payout.queued"
 )
 
-func IsCanonicalInputEvent(string) bool { return false }
+// Inbound events: successful transactions are resolved to referral clicks,
+// refunds and chargebacks claw back the commission they earned.
+const (
+	EventTransactionSucceeded  = "transaction.succeeded"
+	EventTransactionRefunded   = "transaction.refunded"
+	EventTransactionChargeback = "transaction.charged_back"
+)
+
+func IsCanonicalInputEvent(eventType string) bool {
+	switch eventType {
+	case EventTransactionSucceeded, EventTransactionRefunded, EventTransactionChargeback:
+		return true
+	default:
+		return false
+	}
+}
 
 func IsCanonicalEmittedEvent(eventType string) bool {
 	switch eventType {
@@ -26,7 +41,7 @@ func IsCanonicalEmittedEvent(eventType string) bool {
 }
 
 func CanonicalEventClass(eventType string) string {
-	if IsCanonicalEmittedEvent(eventType) {
+	if IsCanonicalEmittedEvent(eventType) || IsCanonicalInputEvent(eventType) {
 		return CanonicalEventClassDomain
 	}
 	return ""
@@ -36,5 +51,8 @@ func CanonicalPartitionKeyPath(eventType string) string {
 	if IsCanonicalEmittedEvent(eventType) {
 		return "data.affiliate_id"
 	}
+	if IsCanonicalInputEvent(eventType) {
+		return "data.transaction_id"
+	}
 	return ""
 }
@@ -26,20 +26,39 @@ type ReferralClickRepository interface {
 	GetByID(ctx context.Context, clickID string) (domain.ReferralClick, error)
 	ListByAffiliateID(ctx context.Context, affiliateID string) ([]domain.ReferralClick, error)
 	CountByLinkID(ctx context.Context, linkID string) (int, error)
+	ListByCookieID(ctx context.Context, cookieID string, from, to time.Time) ([]domain.ReferralClick, error)
+	ListByFingerprint(ctx context.Context, ipHash, userAgentHash string, from, to time.Time) ([]domain.ReferralClick, error)
 }
 
 type ReferralAttributionRepository interface {
 	Create(ctx context.Context, row domain.ReferralAttribution) error
-	GetByOrderID(ctx context.Context, orderID string) (domain.ReferralAttribution, error)
+	Update(ctx context.Context, row domain.ReferralAttribution) error
+	ListByOrderID(ctx context.Context, orderID string) ([]domain.ReferralAttribution, error)
+	ListByConversionID(ctx context.Context, conversionID string) ([]domain.ReferralAttribution, error)
 	ListByAffiliateID(ctx context.Context, affiliateID string) ([]domain.ReferralAttribution, error)
 }
 
 type AffiliateEarningRepository interface {
 	Create(ctx context.Context, row domain.AffiliateEarning) error
+	Update(ctx context.Context, row domain.AffiliateEarning) error
+	ListByAttributionID(ctx context.Context, attributionID string) ([]domain.AffiliateEarning, error)
+	ListByStatus(ctx context.Context, status string) ([]domain.AffiliateEarning, error)
 	ListByAffiliateID(ctx context.Context, affiliateID string) ([]domain.AffiliateEarning, error)
 	SumByAffiliateAndStatus(ctx context.Context, affiliateID, status string) (float64, error)
 }
 
+type CommissionPlanRepository interface {
+	Upsert(ctx context.Context, row domain.CommissionPlan) error
+	GetByID(ctx context.Context, planID string) (domain.CommissionPlan, error)
+	List(ctx context.Context) ([]domain.CommissionPlan, error)
+}
+
+type AffiliateLedgerRepository interface {
+	Append(ctx context.Context, row domain.LedgerEntry) error
+	ListByAffiliateID(ctx context.Context, affiliateID string) ([]domain.LedgerEntry, error)
+	HasReference(ctx context.Context, affiliateID, reference string) (bool, error)
+}
+
 type AffiliatePayoutRepository interface {
 	Create(ctx context.Context, row domain.AffiliatePayout) error
 	ListByAffiliateID(ctx context.Context, affiliateID string) ([]domain.AffiliatePayout, error)
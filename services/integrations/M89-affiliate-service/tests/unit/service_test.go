@@ -2,21 +2,35 @@ package unit
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	eventadapter "github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/adapters/events"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/application"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/contracts"
 	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/domain"
+	"github.com/viralforge/mesh/services/integrations/M89-affiliate-service/internal/ports"
 )
 
 func newServiceWithPublisher() (*application.Service, *eventadapter.MemoryDomainPublisher) {
+	return newServiceWithConfig(application.Config{})
+}
+
+func newServiceWithConfig(cfg application.Config) (*application.Service, *eventadapter.MemoryDomainPublisher) {
 	repos := postgres.NewRepositories()
 	domainPub := eventadapter.NewMemoryDomainPublisher()
 	svc := application.NewService(application.Dependencies{
+		Config:     cfg,
 		Affiliates: repos.Affiliates, Links: repos.Links, Clicks: repos.Clicks, Attributions: repos.Attributions,
-		Earnings: repos.Earnings, Payouts: repos.Payouts, AuditLogs: repos.AuditLogs, Idempotency: repos.Idempotency,
-		EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub,
+		Earnings: repos.Earnings, Payouts: repos.Payouts, Plans: repos.Plans, Ledger: repos.Ledger,
+		AuditLogs: repos.AuditLogs, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox, DomainEvents: domainPub,
 	})
 	return svc, domainPub
 }
@@ -144,3 +158,511 @@ func TestRecordAttributionRequiresAdminActor(t *testing.T) {
 		t.Fatalf("expected forbidden error for non-admin actor, got %v", err)
 	}
 }
+
+func transactionEvent(t *testing.T, eventID string, payload contracts.TransactionSucceededPayload) contracts.EventEnvelope {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return contracts.EventEnvelope{EventID: eventID, EventType: domain.EventTransactionSucceeded, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.transaction_id", PartitionKey: payload.TransactionID, SourceService: "M39-Finance-Service", TraceID: "trace-" + eventID, SchemaVersion: "v1", Data: raw}
+}
+
+func TestTransactionEventAttributesByCookieThenFingerprint(t *testing.T) {
+	svc, _ := newServiceWithPublisher()
+	ctx := context.Background()
+	link, err := svc.CreateReferralLink(ctx, application.Actor{SubjectID: "user-5", Role: "affiliate", IdempotencyKey: "idem-link-5"}, application.CreateReferralLinkInput{Channel: "blog"})
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+	click, err := svc.TrackReferralClick(ctx, application.TrackClickInput{Token: link.Token, ClientIP: "203.0.113.20", UserAgent: "UA-5", CookieID: "cookie-5"})
+	if err != nil {
+		t.Fatalf("track click: %v", err)
+	}
+
+	if err := svc.HandleCanonicalEvent(ctx, transactionEvent(t, "evt-1", contracts.TransactionSucceededPayload{TransactionID: "txn-1", UserID: "buyer-1", Amount: 100, ReferralCookieID: "cookie-5", OccurredAt: time.Now().UTC().Format(time.RFC3339)})); err != nil {
+		t.Fatalf("handle transaction: %v", err)
+	}
+	dashboard, err := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-5"})
+	if err != nil {
+		t.Fatalf("dashboard: %v", err)
+	}
+	if dashboard.TotalAttributions != 1 || dashboard.PendingEarnings != 10 {
+		t.Fatalf("expected one cookie attribution earning 10, got %d / %.2f", dashboard.TotalAttributions, dashboard.PendingEarnings)
+	}
+
+	// Same order through a second transaction event must not pay twice.
+	dup, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-1b", OrderID: "txn-1", Amount: 100, CookieID: "cookie-5"})
+	if err != nil {
+		t.Fatalf("duplicate order: %v", err)
+	}
+	if dup.Outcome != domain.ConversionOutcomeDuplicateOrder {
+		t.Fatalf("expected duplicate order outcome, got %s", dup.Outcome)
+	}
+
+	// Without the cookie the IP and user agent still resolve the click.
+	fp, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-2", UserID: "buyer-2", Amount: 50, ClientIP: "203.0.113.20", UserAgent: "UA-5"})
+	if err != nil {
+		t.Fatalf("fingerprint conversion: %v", err)
+	}
+	if fp.Outcome != domain.ConversionOutcomeAttributed || fp.MatchType != domain.MatchTypeFingerprint || fp.Attributions[0].AffiliateID != click.AffiliateID {
+		t.Fatalf("expected fingerprint attribution to %s, got %+v", click.AffiliateID, fp)
+	}
+	miss, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-3", Amount: 50, ClientIP: "203.0.113.20", UserAgent: "other-UA"})
+	if err != nil {
+		t.Fatalf("unmatched conversion: %v", err)
+	}
+	if miss.Outcome != domain.ConversionOutcomeUnmatched {
+		t.Fatalf("expected unmatched outcome, got %s", miss.Outcome)
+	}
+
+	// M39 forwards only hashes of the buyer's IP and user agent.
+	ipHash, uaHash := sha256.Sum256([]byte("203.0.113.20")), sha256.Sum256([]byte("UA-5"))
+	hashed := contracts.TransactionSucceededPayload{TransactionID: "txn-5", UserID: "buyer-3", Amount: 20, Currency: "USD", Provider: "stripe", IPHash: hex.EncodeToString(ipHash[:]), UserAgentHash: hex.EncodeToString(uaHash[:]), OccurredAt: time.Now().UTC().Format(time.RFC3339)}
+	if err := svc.HandleCanonicalEvent(ctx, transactionEvent(t, "evt-5", hashed)); err != nil {
+		t.Fatalf("handle hashed transaction: %v", err)
+	}
+	if dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-5"}); dash.TotalAttributions != 3 {
+		t.Fatalf("expected the hashed fingerprint to attribute txn-5, got %d attributions", dash.TotalAttributions)
+	}
+
+	// The affiliate buying through their own link earns nothing.
+	self, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-4", UserID: "user-5", Amount: 80, CookieID: "cookie-5"})
+	if err != nil {
+		t.Fatalf("self referral: %v", err)
+	}
+	if self.Outcome != domain.ConversionOutcomeSelfReferral || len(self.Attributions) != 0 {
+		t.Fatalf("expected self referral to be blocked, got %+v", self)
+	}
+}
+
+func TestLinearAttributionSplitsAcrossAffiliates(t *testing.T) {
+	svc, _ := newServiceWithConfig(application.Config{AttributionModel: "linear"})
+	ctx := context.Background()
+	var affiliateIDs []string
+	for i, user := range []string{"user-6", "user-7", "user-6"} {
+		link, err := svc.CreateReferralLink(ctx, application.Actor{SubjectID: user, Role: "affiliate", IdempotencyKey: "idem-link-" + user}, application.CreateReferralLinkInput{Channel: "blog"})
+		if err != nil {
+			t.Fatalf("create link: %v", err)
+		}
+		click, err := svc.TrackReferralClick(ctx, application.TrackClickInput{Token: link.Token, ClientIP: "198.51.100.30", UserAgent: "UA", CookieID: "cookie-shared"})
+		if err != nil {
+			t.Fatalf("track click %d: %v", i, err)
+		}
+		affiliateIDs = append(affiliateIDs, click.AffiliateID)
+	}
+	res, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-6", Amount: 90, CookieID: "cookie-shared"})
+	if err != nil {
+		t.Fatalf("attribute: %v", err)
+	}
+	if len(res.Attributions) != 2 {
+		t.Fatalf("expected a share per affiliate, got %d", len(res.Attributions))
+	}
+	first, second := res.Attributions[0], res.Attributions[1]
+	if first.AffiliateID != affiliateIDs[0] || first.Amount != 60 || second.Amount != 30 {
+		t.Fatalf("expected 60/30 split favouring the repeat affiliate, got %+v", res.Attributions)
+	}
+	if first.Model != domain.AttributionModelLinear || first.MatchType != domain.MatchTypeCookie {
+		t.Fatalf("unexpected model or match type: %+v", first)
+	}
+}
+
+func TestSplitAttributionModels(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	clicks := []domain.ReferralClick{
+		{ClickID: "c1", AffiliateID: "a", ClickedAt: now.Add(-14 * 24 * time.Hour)},
+		{ClickID: "c2", AffiliateID: "b", ClickedAt: now.Add(-7 * 24 * time.Hour)},
+		{ClickID: "c3", AffiliateID: "c", ClickedAt: now},
+	}
+	if got := domain.SplitAttribution(domain.AttributionModelLastClick, clicks, now, 0); len(got) != 1 || got[0].AffiliateID != "c" {
+		t.Fatalf("last click: %+v", got)
+	}
+	if got := domain.SplitAttribution(domain.AttributionModelFirstClick, clicks, now, 0); len(got) != 1 || got[0].AffiliateID != "a" {
+		t.Fatalf("first click: %+v", got)
+	}
+	got := domain.SplitAttribution(domain.AttributionModelTimeDecay, clicks, now, 7*24*time.Hour)
+	if len(got) != 3 || got[0].AffiliateID != "c" || got[2].AffiliateID != "a" {
+		t.Fatalf("time decay order: %+v", got)
+	}
+	// Weights 4:2:1 for clicks zero, one and two half-lives old.
+	if math.Abs(got[0].Weight-4.0/7) > 1e-9 || math.Abs(got[2].Weight-1.0/7) > 1e-9 {
+		t.Fatalf("time decay weights: %+v", got)
+	}
+}
+
+func TestManualAttributionOverridesAutomatic(t *testing.T) {
+	svc, _ := newServiceWithPublisher()
+	ctx := context.Background()
+	link, err := svc.CreateReferralLink(ctx, application.Actor{SubjectID: "user-8", Role: "affiliate", IdempotencyKey: "idem-link-8"}, application.CreateReferralLinkInput{Channel: "blog"})
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+	if _, err := svc.TrackReferralClick(ctx, application.TrackClickInput{Token: link.Token, ClientIP: "198.51.100.40", UserAgent: "UA", CookieID: "cookie-8"}); err != nil {
+		t.Fatalf("track click: %v", err)
+	}
+	if _, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-8", OrderID: "ord-8", Amount: 100, CookieID: "cookie-8"}); err != nil {
+		t.Fatalf("attribute: %v", err)
+	}
+	other, err := svc.CreateReferralLink(ctx, application.Actor{SubjectID: "user-9", Role: "affiliate", IdempotencyKey: "idem-link-9"}, application.CreateReferralLinkInput{Channel: "blog"})
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+	otherDash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-9"})
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-attr-8"}
+	in := application.RecordAttributionInput{AffiliateID: otherDash.AffiliateID, OrderID: "ord-8", ConversionID: "txn-8", Amount: 100, Reason: "partner dispute " + other.LinkID}
+	if _, err := svc.RecordAttribution(ctx, admin, in); err != nil {
+		t.Fatalf("manual override: %v", err)
+	}
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-8"})
+	if dash.TotalAttributions != 0 || dash.PendingEarnings != 0 {
+		t.Fatalf("expected automatic attribution reversed, got %d / %.2f", dash.TotalAttributions, dash.PendingEarnings)
+	}
+	otherDash, _ = svc.GetDashboard(ctx, application.Actor{SubjectID: "user-9"})
+	if otherDash.TotalAttributions != 1 || otherDash.PendingEarnings != 10 {
+		t.Fatalf("expected manual attribution credited, got %d / %.2f", otherDash.TotalAttributions, otherDash.PendingEarnings)
+	}
+	admin.IdempotencyKey = "idem-attr-8b"
+	if _, err := svc.RecordAttribution(ctx, admin, in); err != domain.ErrConflict {
+		t.Fatalf("expected conflict for second manual attribution, got %v", err)
+	}
+}
+
+func reversalEvent(t *testing.T, eventID, eventType string, payload contracts.TransactionReversalPayload) contracts.EventEnvelope {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return contracts.EventEnvelope{EventID: eventID, EventType: eventType, EventClass: domain.CanonicalEventClassDomain, OccurredAt: time.Now().UTC(), PartitionKeyPath: "data.transaction_id", PartitionKey: payload.TransactionID, SourceService: "M39-Finance-Service", TraceID: "trace-" + eventID, SchemaVersion: "v1", Data: raw}
+}
+
+// referredAffiliate creates an affiliate for user with one click under
+// cookie and returns the affiliate id.
+func referredAffiliate(t *testing.T, svc *application.Service, user, cookie, campaign string) string {
+	t.Helper()
+	ctx := context.Background()
+	link, err := svc.CreateReferralLink(ctx, application.Actor{SubjectID: user, Role: "affiliate", IdempotencyKey: "idem-link-" + user}, application.CreateReferralLinkInput{Channel: "blog", UTMCampaign: campaign})
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+	click, err := svc.TrackReferralClick(ctx, application.TrackClickInput{Token: link.Token, ClientIP: "192.0.2.10", UserAgent: "UA-" + user, CookieID: cookie})
+	if err != nil {
+		t.Fatalf("track click: %v", err)
+	}
+	return click.AffiliateID
+}
+
+func TestCommissionPlanTiersProductRatesAndBonuses(t *testing.T) {
+	svc, _ := newServiceWithPublisher()
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-plan-1"}
+	noHold := time.Duration(0)
+	plan, err := svc.UpsertCommissionPlan(ctx, admin, application.UpsertCommissionPlanInput{
+		PlanID:        "growth",
+		Name:          "Growth",
+		BaseRate:      0.05,
+		Tiers:         []domain.CommissionTier{{MinVolume: 100, Rate: 0.08}, {MinVolume: 300, Rate: 0.12}},
+		ProductRates:  map[string]float64{"prod-pro": 0.3},
+		CampaignRates: map[string]float64{"spring": 0.2},
+		Bonuses:       []domain.CommissionBonus{{Name: "first-500", MinVolume: 500, Amount: 25}},
+		HoldPeriod:    &noHold,
+	})
+	if err != nil {
+		t.Fatalf("upsert plan: %v", err)
+	}
+	if plan.Period != domain.CommissionPeriodMonth {
+		t.Fatalf("expected monthly plan by default, got %s", plan.Period)
+	}
+	admin.IdempotencyKey = "idem-plan-bad"
+	if _, err := svc.UpsertCommissionPlan(ctx, admin, application.UpsertCommissionPlanInput{PlanID: "bad", Name: "Bad", BaseRate: 0.1, Tiers: []domain.CommissionTier{{MinVolume: 200, Rate: 0.1}, {MinVolume: 100, Rate: 0.2}}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected unordered tiers to be rejected, got %v", err)
+	}
+
+	affiliateID := referredAffiliate(t, svc, "user-20", "cookie-20", "")
+	admin.IdempotencyKey = "idem-assign-20"
+	if _, err := svc.AssignCommissionPlan(ctx, admin, application.AssignCommissionPlanInput{AffiliateID: affiliateID, PlanID: "growth"}); err != nil {
+		t.Fatalf("assign plan: %v", err)
+	}
+
+	// Period volume including each order picks the tier: 200 and 400 reach
+	// tier 1 and tier 2, 600 stays on tier 2 and unlocks the bonus.
+	expected := []float64{16, 24, 24}
+	for i, want := range expected {
+		res, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: fmt.Sprintf("txn-20-%d", i), Amount: 200, CookieID: "cookie-20"})
+		if err != nil {
+			t.Fatalf("attribute %d: %v", i, err)
+		}
+		earnings, _ := svc.ListEarnings(ctx, application.Actor{SubjectID: "user-20"})
+		got := 0.0
+		for _, earning := range earnings {
+			if earning.AttributionID == res.Attributions[0].AttributionID {
+				got = earning.Amount
+			}
+		}
+		if got != want {
+			t.Fatalf("order %d: expected commission %.2f, got %.2f", i, want, got)
+		}
+	}
+	product, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-20-p", ProductID: "prod-pro", Amount: 100, CookieID: "cookie-20"})
+	if err != nil {
+		t.Fatalf("attribute product: %v", err)
+	}
+	earnings, _ := svc.ListEarnings(ctx, application.Actor{SubjectID: "user-20"})
+	bonuses := 0
+	for _, earning := range earnings {
+		if earning.AttributionID == product.Attributions[0].AttributionID && (earning.Amount != 30 || earning.RateBasis != "product:prod-pro") {
+			t.Fatalf("expected product rate to win, got %+v", earning)
+		}
+		if earning.Kind == domain.EarningKindBonus {
+			bonuses++
+		}
+	}
+	if bonuses != 1 {
+		t.Fatalf("expected the volume bonus once per period, got %d", bonuses)
+	}
+
+	campaignAffiliate := referredAffiliate(t, svc, "user-21", "cookie-21", "spring")
+	admin.IdempotencyKey = "idem-assign-21"
+	if _, err := svc.AssignCommissionPlan(ctx, admin, application.AssignCommissionPlanInput{AffiliateID: campaignAffiliate, PlanID: "growth"}); err != nil {
+		t.Fatalf("assign plan: %v", err)
+	}
+	if _, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-21", Amount: 50, CookieID: "cookie-21"}); err != nil {
+		t.Fatalf("attribute campaign: %v", err)
+	}
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-21"})
+	if dash.PendingEarnings != 10 {
+		t.Fatalf("expected campaign rate commission of 10, got %.2f", dash.PendingEarnings)
+	}
+}
+
+func TestEarningsHoldThenSettleIntoPayout(t *testing.T) {
+	svc, _ := newServiceWithConfig(application.Config{EarningHoldPeriod: 30 * 24 * time.Hour, PayoutThreshold: 5})
+	ctx := context.Background()
+	referredAffiliate(t, svc, "user-22", "cookie-22", "")
+	if _, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-22", Amount: 100, CookieID: "cookie-22"}); err != nil {
+		t.Fatalf("attribute: %v", err)
+	}
+	out, err := svc.SettleEarnings(ctx)
+	if err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	if out.Released != 0 || len(out.Payouts) != 0 {
+		t.Fatalf("expected held earning to stay pending, got %+v", out)
+	}
+
+	// An admin moving the affiliate to a plan without a hold releases new
+	// earnings on the next settlement run.
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-22"})
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-plan-22"}
+	noHold := time.Duration(0)
+	if _, err := svc.UpsertCommissionPlan(ctx, admin, application.UpsertCommissionPlanInput{PlanID: "instant", Name: "Instant", BaseRate: 0.1, HoldPeriod: &noHold}); err != nil {
+		t.Fatalf("upsert plan: %v", err)
+	}
+	admin.IdempotencyKey = "idem-assign-22"
+	if _, err := svc.AssignCommissionPlan(ctx, admin, application.AssignCommissionPlanInput{AffiliateID: dash.AffiliateID, PlanID: "instant"}); err != nil {
+		t.Fatalf("assign plan: %v", err)
+	}
+	if _, err := svc.AttributeConversion(ctx, application.ConversionInput{ConversionID: "txn-22b", Amount: 80, CookieID: "cookie-22"}); err != nil {
+		t.Fatalf("attribute: %v", err)
+	}
+	out, err = svc.RunSettlement(ctx, application.Actor{SubjectID: "admin-1", Role: "admin"})
+	if err != nil {
+		t.Fatalf("run settlement: %v", err)
+	}
+	if out.Released != 1 || len(out.Payouts) != 1 || out.Payouts[0].Amount != 8 {
+		t.Fatalf("expected the unheld earning released and paid out, got %+v", out)
+	}
+	dash, _ = svc.GetDashboard(ctx, application.Actor{SubjectID: "user-22"})
+	if dash.PendingEarnings != 10 || dash.PayableEarnings != 0 || dash.PaidEarnings != 8 {
+		t.Fatalf("unexpected balances after settlement: %+v", dash)
+	}
+	if _, err := svc.RunSettlement(ctx, application.Actor{SubjectID: "user-22"}); err != domain.ErrForbidden {
+		t.Fatalf("expected settlement to be admin only, got %v", err)
+	}
+}
+
+func TestRefundAndChargebackClawBackEarnings(t *testing.T) {
+	svc, _ := newServiceWithConfig(application.Config{PayoutThreshold: 5})
+	ctx := context.Background()
+	referredAffiliate(t, svc, "user-23", "cookie-23", "")
+	succeeded := func(eventID, txnID string, amount float64) {
+		payload := contracts.TransactionSucceededPayload{TransactionID: txnID, UserID: "buyer-23", Amount: amount, ReferralCookieID: "cookie-23", OccurredAt: time.Now().UTC().Format(time.RFC3339)}
+		if err := svc.HandleCanonicalEvent(ctx, transactionEvent(t, eventID, payload)); err != nil {
+			t.Fatalf("handle transaction %s: %v", txnID, err)
+		}
+	}
+
+	// A partial refund before payout reduces the unpaid earning; the rest of
+	// the order reverses it.
+	succeeded("evt-23-1", "txn-23a", 100)
+	refund := contracts.TransactionReversalPayload{TransactionID: "txn-23a", RefundID: "rf-1", Amount: 40, Reason: "partial refund"}
+	if err := svc.HandleCanonicalEvent(ctx, reversalEvent(t, "evt-23-2", domain.EventTransactionRefunded, refund)); err != nil {
+		t.Fatalf("handle refund: %v", err)
+	}
+	if err := svc.HandleCanonicalEvent(ctx, reversalEvent(t, "evt-23-2b", domain.EventTransactionRefunded, refund)); err != nil {
+		t.Fatalf("replayed refund: %v", err)
+	}
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-23"})
+	if dash.PendingEarnings != 6 {
+		t.Fatalf("expected 4 clawed back once from a 10 commission, got %.2f pending", dash.PendingEarnings)
+	}
+	refund.RefundID, refund.Amount = "rf-2", 60
+	if err := svc.HandleCanonicalEvent(ctx, reversalEvent(t, "evt-23-3", domain.EventTransactionRefunded, refund)); err != nil {
+		t.Fatalf("handle second refund: %v", err)
+	}
+	earnings, _ := svc.ListEarnings(ctx, application.Actor{SubjectID: "user-23"})
+	if len(earnings) != 1 || earnings[0].Status != domain.EarningStatusReversed {
+		t.Fatalf("expected fully refunded earning to be reversed, got %+v", earnings)
+	}
+
+	// A chargeback after payout leaves a negative earning that the next
+	// payout absorbs.
+	succeeded("evt-23-4", "txn-23b", 100)
+	if out, err := svc.SettleEarnings(ctx); err != nil || len(out.Payouts) != 1 || out.Payouts[0].Amount != 10 {
+		t.Fatalf("expected first payout of 10, got %+v / %v", out, err)
+	}
+	chargeback := contracts.TransactionReversalPayload{TransactionID: "txn-23b", ChargebackID: "cb-1", Reason: "fraud"}
+	if err := svc.HandleCanonicalEvent(ctx, reversalEvent(t, "evt-23-5", domain.EventTransactionChargeback, chargeback)); err != nil {
+		t.Fatalf("handle chargeback: %v", err)
+	}
+	dash, _ = svc.GetDashboard(ctx, application.Actor{SubjectID: "user-23"})
+	if dash.PayableEarnings != -10 || dash.PaidEarnings != 10 {
+		t.Fatalf("expected a negative payable balance carried forward, got %+v", dash)
+	}
+	succeeded("evt-23-6", "txn-23c", 80)
+	if out, err := svc.SettleEarnings(ctx); err != nil || len(out.Payouts) != 0 {
+		t.Fatalf("expected no payout while the clawback outweighs new earnings, got %+v / %v", out, err)
+	}
+	succeeded("evt-23-7", "txn-23d", 150)
+	out, err := svc.SettleEarnings(ctx)
+	if err != nil || len(out.Payouts) != 1 || out.Payouts[0].Amount != 13 {
+		t.Fatalf("expected next payout net of the clawback (8+15-10), got %+v / %v", out, err)
+	}
+
+	ledger, err := svc.GetLedger(ctx, application.Actor{SubjectID: "user-23"})
+	if err != nil {
+		t.Fatalf("ledger: %v", err)
+	}
+	counts := map[string]int{}
+	for _, entry := range ledger {
+		counts[entry.Type]++
+	}
+	if counts[domain.LedgerEntryAccrued] != 4 || counts[domain.LedgerEntryReversed] != 2 || counts[domain.LedgerEntryClawback] != 1 || counts[domain.LedgerEntryPayout] != 2 {
+		t.Fatalf("unexpected ledger entries: %v", counts)
+	}
+	last := ledger[len(ledger)-1]
+	if last.Type != domain.LedgerEntryPayout || last.BalancePending != 0 || last.BalancePayable != 0 || last.BalancePaid != 23 {
+		t.Fatalf("expected ledger balances to end settled, got %+v", last)
+	}
+}
+
+func TestRefundClawsBackBonusNoLongerReached(t *testing.T) {
+	svc, _ := newServiceWithPublisher()
+	ctx := context.Background()
+	admin := application.Actor{SubjectID: "admin-1", Role: "admin", IdempotencyKey: "idem-plan-24"}
+	noHold := time.Duration(0)
+	if _, err := svc.UpsertCommissionPlan(ctx, admin, application.UpsertCommissionPlanInput{PlanID: "bonus", Name: "Bonus", BaseRate: 0.1, Bonuses: []domain.CommissionBonus{{Name: "first-500", MinVolume: 500, Amount: 25}}, HoldPeriod: &noHold}); err != nil {
+		t.Fatalf("upsert plan: %v", err)
+	}
+	affiliateID := referredAffiliate(t, svc, "user-24", "cookie-24", "")
+	admin.IdempotencyKey = "idem-assign-24"
+	if _, err := svc.AssignCommissionPlan(ctx, admin, application.AssignCommissionPlanInput{AffiliateID: affiliateID, PlanID: "bonus"}); err != nil {
+		t.Fatalf("assign plan: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		payload := contracts.TransactionSucceededPayload{TransactionID: fmt.Sprintf("txn-24-%d", i), UserID: "buyer-24", Amount: 200, Currency: "USD", Provider: "stripe", ReferralCookieID: "cookie-24", OccurredAt: time.Now().UTC().Format(time.RFC3339)}
+		if err := svc.HandleCanonicalEvent(ctx, transactionEvent(t, fmt.Sprintf("evt-24-%d", i), payload)); err != nil {
+			t.Fatalf("handle transaction %d: %v", i, err)
+		}
+	}
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-24"})
+	if dash.PendingEarnings != 85 {
+		t.Fatalf("expected 60 commission plus a 25 bonus, got %.2f", dash.PendingEarnings)
+	}
+
+	// Refunding one order drops the period volume to 400, under the bonus.
+	refund := contracts.TransactionReversalPayload{TransactionID: "txn-24-0", RefundID: "rf-24", UserID: "buyer-24", Currency: "USD", Provider: "stripe", Reason: "returned"}
+	if err := svc.HandleCanonicalEvent(ctx, reversalEvent(t, "evt-24-r", domain.EventTransactionRefunded, refund)); err != nil {
+		t.Fatalf("handle refund: %v", err)
+	}
+	dash, _ = svc.GetDashboard(ctx, application.Actor{SubjectID: "user-24"})
+	if dash.PendingEarnings != 40 {
+		t.Fatalf("expected the refunded commission and the bonus clawed back, got %.2f pending", dash.PendingEarnings)
+	}
+	earnings, _ := svc.ListEarnings(ctx, application.Actor{SubjectID: "user-24"})
+	for _, earning := range earnings {
+		if earning.Kind == domain.EarningKindBonus && earning.Status != domain.EarningStatusReversed {
+			t.Fatalf("expected the bonus reversed, got %+v", earning)
+		}
+	}
+}
+
+func TestReversalDedupWithoutLedger(t *testing.T) {
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{
+		Affiliates: repos.Affiliates, Links: repos.Links, Clicks: repos.Clicks, Attributions: repos.Attributions,
+		Earnings: repos.Earnings, Payouts: repos.Payouts, Plans: repos.Plans,
+		AuditLogs: repos.AuditLogs, Idempotency: repos.Idempotency, Outbox: repos.Outbox,
+	})
+	ctx := context.Background()
+	referredAffiliate(t, svc, "user-25", "cookie-25", "")
+	payload := contracts.TransactionSucceededPayload{TransactionID: "txn-25", UserID: "buyer-25", Amount: 100, Currency: "USD", Provider: "stripe", ReferralCookieID: "cookie-25", OccurredAt: time.Now().UTC().Format(time.RFC3339)}
+	if err := svc.HandleCanonicalEvent(ctx, transactionEvent(t, "evt-25", payload)); err != nil {
+		t.Fatalf("handle transaction: %v", err)
+	}
+	// Without event dedup or a ledger, a redelivered refund still applies once.
+	refund := contracts.TransactionReversalPayload{TransactionID: "txn-25", UserID: "buyer-25", Amount: 30, Currency: "USD", Provider: "stripe"}
+	for i := 0; i < 2; i++ {
+		if err := svc.HandleCanonicalEvent(ctx, reversalEvent(t, "evt-25-r", domain.EventTransactionRefunded, refund)); err != nil {
+			t.Fatalf("handle refund %d: %v", i, err)
+		}
+	}
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-25"})
+	if dash.PendingEarnings != 7 {
+		t.Fatalf("expected 3 clawed back once from a 10 commission, got %.2f pending", dash.PendingEarnings)
+	}
+}
+
+// firstEarningFails fails the first earning write once, as an outage between
+// storing the attribution and accruing it would.
+type firstEarningFails struct {
+	ports.AffiliateEarningRepository
+	failed bool
+}
+
+func (r *firstEarningFails) Create(ctx context.Context, row domain.AffiliateEarning) error {
+	if !r.failed {
+		r.failed = true
+		return errors.New("earnings store unavailable")
+	}
+	return r.AffiliateEarningRepository.Create(ctx, row)
+}
+
+func TestRetriedConversionAccruesCommissionTheFailedAttemptMissed(t *testing.T) {
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{
+		Affiliates: repos.Affiliates, Links: repos.Links, Clicks: repos.Clicks, Attributions: repos.Attributions,
+		Earnings: &firstEarningFails{AffiliateEarningRepository: repos.Earnings}, Payouts: repos.Payouts, Plans: repos.Plans, Ledger: repos.Ledger,
+		AuditLogs: repos.AuditLogs, Idempotency: repos.Idempotency, EventDedup: repos.EventDedup, Outbox: repos.Outbox,
+	})
+	ctx := context.Background()
+	referredAffiliate(t, svc, "user-26", "cookie-26", "")
+	in := application.ConversionInput{ConversionID: "txn-26", Amount: 100, CookieID: "cookie-26"}
+	if _, err := svc.AttributeConversion(ctx, in); err == nil {
+		t.Fatal("expected the failed accrual to fail the conversion")
+	}
+	retry, err := svc.AttributeConversion(ctx, in)
+	if err != nil {
+		t.Fatalf("retry conversion: %v", err)
+	}
+	if retry.Outcome != domain.ConversionOutcomeDuplicateOrder {
+		t.Fatalf("expected the stored attribution to be reused, got %s", retry.Outcome)
+	}
+	if _, err := svc.AttributeConversion(ctx, in); err != nil {
+		t.Fatalf("second retry: %v", err)
+	}
+	dash, _ := svc.GetDashboard(ctx, application.Actor{SubjectID: "user-26"})
+	if dash.TotalAttributions != 1 || dash.PendingEarnings != 10 {
+		t.Fatalf("expected one attribution earning 10 once, got %d / %.2f", dash.TotalAttributions, dash.PendingEarnings)
+	}
+}
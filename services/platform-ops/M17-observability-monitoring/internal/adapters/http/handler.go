@@ -92,6 +92,44 @@ func (h *Handler) upsertComponent(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) listProbes(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.service.ListProbes(r.Context())
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	items := make([]contracts.ProbeResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, probeToResponse(row))
+	}
+	writeSuccess(w, http.StatusOK, contracts.ProbesListResponse{Items: items})
+}
+
+func (h *Handler) listSLOs(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.service.ListSLOStatuses(r.Context())
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	items := make([]contracts.SLOResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, sloToResponse(row))
+	}
+	writeSuccess(w, http.StatusOK, contracts.SLOsListResponse{Items: items})
+}
+
+func (h *Handler) getSLO(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.GetSLOStatus(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		code, c := mapDomainError(err)
+		writeError(w, code, c, err.Error())
+		return
+	}
+	writeSuccess(w, http.StatusOK, sloToResponse(out))
+}
+
 func componentToResponse(row domain.ComponentCheck) contracts.ComponentResponse {
 	out := contracts.ComponentResponse{
 		Status:           row.Status,
@@ -116,3 +154,52 @@ func namedComponentToResponse(row domain.ComponentCheck) contracts.NamedComponen
 		Metadata:         row.Metadata,
 	}
 }
+
+func probeToResponse(row application.ProbeStatus) contracts.ProbeResponse {
+	out := contracts.ProbeResponse{
+		Name:                row.Target.Name,
+		Kind:                row.Target.Kind,
+		Target:              row.Target.Target,
+		Critical:            row.Target.Critical,
+		IntervalSeconds:     int(row.Target.Interval.Seconds()),
+		FailureThreshold:    row.Target.FailureThreshold,
+		Status:              row.Status,
+		ConsecutiveFailures: row.State.ConsecutiveFailures,
+		LatencyMS:           row.State.Last.LatencyMS,
+		Error:               row.State.Last.Error,
+	}
+	if !row.State.Last.CheckedAt.IsZero() {
+		out.LastChecked = row.State.Last.CheckedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func sloToResponse(row domain.SLOStatus) contracts.SLOResponse {
+	out := contracts.SLOResponse{
+		Name:                 row.SLO.Name,
+		Target:               row.SLO.Target,
+		Kind:                 row.SLO.Kind,
+		Objective:            row.SLO.Objective,
+		LatencyThresholdMS:   row.SLO.LatencyThresholdMS,
+		WindowDays:           int(row.SLO.Window / (24 * time.Hour)),
+		TotalEvents:          row.TotalEvents,
+		GoodEvents:           row.GoodEvents,
+		SLI:                  row.SLI,
+		ErrorBudgetRemaining: row.ErrorBudgetRemaining,
+		BurnRates:            make([]contracts.BurnRateResponse, 0, len(row.BurnRates)),
+		Alerting:             row.Alerting,
+		EvaluatedAt:          row.EvaluatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, rate := range row.BurnRates {
+		out.BurnRates = append(out.BurnRates, contracts.BurnRateResponse{
+			LongWindow:  rate.Window.Long.String(),
+			ShortWindow: rate.Window.Short.String(),
+			Threshold:   rate.Window.Threshold,
+			Severity:    rate.Window.Severity,
+			LongRate:    rate.LongRate,
+			ShortRate:   rate.ShortRate,
+			Firing:      rate.Firing,
+		})
+	}
+	return out
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/application"
@@ -54,34 +53,6 @@ func authMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func metricsMiddleware(svc *application.Service) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			start := time.Now()
-			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
-			next.ServeHTTP(rec, r)
-			if svc != nil {
-				svc.RecordHTTPMetric(r.Context(), application.MetricObservation{
-					Method:     r.Method,
-					Path:       r.URL.Path,
-					StatusCode: rec.status,
-					Duration:   time.Since(start),
-				})
-			}
-		})
-	}
-}
-
-type statusRecorder struct {
-	http.ResponseWriter
-	status int
-}
-
-func (r *statusRecorder) WriteHeader(code int) {
-	r.status = code
-	r.ResponseWriter.WriteHeader(code)
-}
-
 func actorFromContext(ctx context.Context) application.Actor {
 	if v := ctx.Value(actorKey); v != nil {
 		if a, ok := v.(application.Actor); ok {
@@ -10,7 +10,6 @@ import (
 func NewRouter(handler *Handler, service *application.Service) http.Handler {
 	r := chi.NewRouter()
 	r.Use(requestIDMiddleware)
-	r.Use(metricsMiddleware(service))
 
 	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		writeSuccess(w, http.StatusOK, map[string]string{"status": "ok"})
@@ -23,6 +22,9 @@ func NewRouter(handler *Handler, service *application.Service) http.Handler {
 
 	r.Route("/api/v1/observability", func(r chi.Router) {
 		r.Get("/components", handler.listComponents)
+		r.Get("/probes", handler.listProbes)
+		r.Get("/slos", handler.listSLOs)
+		r.Get("/slos/{name}", handler.getSLO)
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware)
 			r.Put("/components/{name}", handler.upsertComponent)
@@ -15,6 +15,7 @@ import (
 type Repositories struct {
 	Components  *ComponentCheckRepository
 	Metrics     *MetricsRepository
+	Probes      *ProbeResultRepository
 	Idempotency *IdempotencyRepository
 	EventDedup  *EventDedupRepository
 	Outbox      *OutboxRepository
@@ -23,7 +24,8 @@ type Repositories struct {
 func NewRepositories() *Repositories {
 	return &Repositories{
 		Components:  &ComponentCheckRepository{rows: map[string]domain.ComponentCheck{}},
-		Metrics:     &MetricsRepository{counters: map[string]ports.MetricCounterPoint{}, histograms: map[string]ports.MetricHistogramPoint{}},
+		Metrics:     &MetricsRepository{counters: map[string]ports.MetricCounterPoint{}, gauges: map[string]ports.MetricGaugePoint{}, histograms: map[string]ports.MetricHistogramPoint{}},
+		Probes:      &ProbeResultRepository{states: map[string]domain.ProbeState{}, results: map[string][]domain.ProbeResult{}},
 		Idempotency: &IdempotencyRepository{rows: map[string]ports.IdempotencyRecord{}},
 		EventDedup:  &EventDedupRepository{rows: map[string]time.Time{}},
 		Outbox:      &OutboxRepository{rows: map[string]ports.OutboxRecord{}, order: []string{}},
@@ -66,6 +68,7 @@ func (r *ComponentCheckRepository) List(_ context.Context) ([]domain.ComponentCh
 type MetricsRepository struct {
 	mu         sync.Mutex
 	counters   map[string]ports.MetricCounterPoint
+	gauges     map[string]ports.MetricGaugePoint
 	histograms map[string]ports.MetricHistogramPoint
 }
 
@@ -82,6 +85,13 @@ func (r *MetricsRepository) IncCounter(_ context.Context, name string, labels ma
 	return nil
 }
 
+func (r *MetricsRepository) SetGauge(_ context.Context, name string, labels map[string]string, value float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gauges[metricKey(name, labels)] = ports.MetricGaugePoint{Name: name, Labels: copyLabels(labels), Value: value}
+	return nil
+}
+
 func (r *MetricsRepository) ObserveHistogram(_ context.Context, name string, labels map[string]string, value float64, buckets []float64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -108,11 +118,15 @@ func (r *MetricsRepository) Snapshot(_ context.Context) (ports.MetricsSnapshot,
 	defer r.mu.Unlock()
 	out := ports.MetricsSnapshot{
 		Counters:   make([]ports.MetricCounterPoint, 0, len(r.counters)),
+		Gauges:     make([]ports.MetricGaugePoint, 0, len(r.gauges)),
 		Histograms: make([]ports.MetricHistogramPoint, 0, len(r.histograms)),
 	}
 	for _, c := range r.counters {
 		out.Counters = append(out.Counters, ports.MetricCounterPoint{Name: c.Name, Labels: copyLabels(c.Labels), Value: c.Value})
 	}
+	for _, g := range r.gauges {
+		out.Gauges = append(out.Gauges, ports.MetricGaugePoint{Name: g.Name, Labels: copyLabels(g.Labels), Value: g.Value})
+	}
 	for _, h := range r.histograms {
 		buckets := make(map[string]float64, len(h.Buckets))
 		for k, v := range h.Buckets {
@@ -123,6 +137,9 @@ func (r *MetricsRepository) Snapshot(_ context.Context) (ports.MetricsSnapshot,
 	sort.Slice(out.Counters, func(i, j int) bool {
 		return metricKey(out.Counters[i].Name, out.Counters[i].Labels) < metricKey(out.Counters[j].Name, out.Counters[j].Labels)
 	})
+	sort.Slice(out.Gauges, func(i, j int) bool {
+		return metricKey(out.Gauges[i].Name, out.Gauges[i].Labels) < metricKey(out.Gauges[j].Name, out.Gauges[j].Labels)
+	})
 	sort.Slice(out.Histograms, func(i, j int) bool {
 		return metricKey(out.Histograms[i].Name, out.Histograms[i].Labels) < metricKey(out.Histograms[j].Name, out.Histograms[j].Labels)
 	})
@@ -157,6 +174,51 @@ func copyLabels(in map[string]string) map[string]string {
 	return out
 }
 
+type ProbeResultRepository struct {
+	mu      sync.Mutex
+	states  map[string]domain.ProbeState
+	results map[string][]domain.ProbeResult
+}
+
+func (r *ProbeResultRepository) Record(_ context.Context, result domain.ProbeResult) (domain.ProbeState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.states[result.Target].Observe(result)
+	r.states[result.Target] = state
+	r.results[result.Target] = append(r.results[result.Target], result)
+	return state, nil
+}
+
+func (r *ProbeResultRepository) States(_ context.Context) ([]domain.ProbeState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]domain.ProbeState, 0, len(r.states))
+	for _, state := range r.states {
+		out = append(out, state)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	return out, nil
+}
+
+func (r *ProbeResultRepository) ListSince(_ context.Context, target string, since time.Time) ([]domain.ProbeResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rows := r.results[target]
+	i := sort.Search(len(rows), func(i int) bool { return !rows[i].CheckedAt.Before(since) })
+	return append([]domain.ProbeResult(nil), rows[i:]...), nil
+}
+
+func (r *ProbeResultRepository) Prune(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for target, rows := range r.results {
+		if i := sort.Search(len(rows), func(i int) bool { return !rows[i].CheckedAt.Before(before) }); i > 0 {
+			r.results[target] = append([]domain.ProbeResult(nil), rows[i:]...)
+		}
+	}
+	return nil
+}
+
 type IdempotencyRepository struct {
 	mu   sync.Mutex
 	rows map[string]ports.IdempotencyRecord
@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Prober runs HTTP, gRPC health, TCP and DNS checks. The caller bounds every
+// probe with the target timeout through ctx.
+type Prober struct {
+	client   *http.Client
+	resolver *net.Resolver
+	dialer   *net.Dialer
+}
+
+func NewProber() *Prober {
+	return &Prober{
+		client: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		resolver: net.DefaultResolver,
+		dialer:   &net.Dialer{},
+	}
+}
+
+func (p *Prober) Probe(ctx context.Context, target domain.ProbeTarget) error {
+	switch target.Kind {
+	case domain.ProbeKindHTTP:
+		return p.probeHTTP(ctx, target)
+	case domain.ProbeKindGRPCHealth:
+		return p.probeGRPCHealth(ctx, target)
+	case domain.ProbeKindTCP:
+		conn, err := p.dialer.DialContext(ctx, "tcp", target.Target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case domain.ProbeKindDNS:
+		addrs, err := p.resolver.LookupHost(ctx, target.Target)
+		if err != nil {
+			return err
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("no addresses for %s", target.Target)
+		}
+		return nil
+	default:
+		return domain.ErrInvalidInput
+	}
+}
+
+func (p *Prober) probeHTTP(ctx context.Context, target domain.ProbeTarget) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.Target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "mesh-m17-prober")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if target.ExpectStatus > 0 {
+		if resp.StatusCode != target.ExpectStatus {
+			return fmt.Errorf("unexpected status %d, want %d", resp.StatusCode, target.ExpectStatus)
+		}
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *Prober) probeGRPCHealth(ctx context.Context, target domain.ProbeTarget) error {
+	conn, err := grpc.NewClient(target.Target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: target.GRPCService})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health status %s", resp.GetStatus())
+	}
+	return nil
+}
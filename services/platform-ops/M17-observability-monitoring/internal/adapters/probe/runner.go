@@ -0,0 +1,42 @@
+package probe
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/application"
+)
+
+// Runner drives the service's probe cycle on a fixed tick. Each target is
+// still only probed once its own interval elapsed.
+type Runner struct {
+	logger  *slog.Logger
+	service *application.Service
+	tick    time.Duration
+}
+
+func NewRunner(logger *slog.Logger, service *application.Service, tick time.Duration) *Runner {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &Runner{logger: logger, service: service, tick: tick}
+}
+
+func (r *Runner) Run(ctx context.Context) error {
+	t := time.NewTicker(r.tick)
+	defer t.Stop()
+	for {
+		if err := r.service.RunProbeCycle(ctx); err != nil {
+			r.logger.ErrorContext(ctx, "probe cycle failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
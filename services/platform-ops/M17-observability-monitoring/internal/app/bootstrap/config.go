@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,6 +18,31 @@ type Config struct {
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
+	ProbeTick            time.Duration
+	ProbeTargets         []domain.ProbeTarget
+	SLOs                 []domain.SLO
+}
+
+type probeTargetFile struct {
+	Name              string `yaml:"name"`
+	Kind              string `yaml:"kind"`
+	Target            string `yaml:"target"`
+	GRPCService       string `yaml:"grpc_service"`
+	ExpectStatus      int    `yaml:"expect_status"`
+	IntervalSeconds   int    `yaml:"interval_seconds"`
+	TimeoutMS         int    `yaml:"timeout_ms"`
+	FailureThreshold  int    `yaml:"failure_threshold"`
+	DegradedLatencyMS int    `yaml:"degraded_latency_ms"`
+	Critical          bool   `yaml:"critical"`
+}
+
+type sloFile struct {
+	Name               string  `yaml:"name"`
+	Target             string  `yaml:"target"`
+	Kind               string  `yaml:"kind"`
+	Objective          float64 `yaml:"objective"`
+	LatencyThresholdMS int     `yaml:"latency_threshold_ms"`
+	WindowDays         int     `yaml:"window_days"`
 }
 
 type configFile struct {
@@ -31,6 +57,11 @@ type configFile struct {
 		EventDedupTTLHours  int `yaml:"event_dedup_ttl_hours"`
 		ConsumerPollSeconds int `yaml:"consumer_poll_seconds"`
 	} `yaml:"runtime"`
+	Probing struct {
+		TickSeconds int               `yaml:"tick_seconds"`
+		Targets     []probeTargetFile `yaml:"targets"`
+	} `yaml:"probing"`
+	SLOs []sloFile `yaml:"slos"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -42,6 +73,7 @@ func LoadConfig(path string) (Config, error) {
 		IdempotencyTTL:       7 * 24 * time.Hour,
 		EventDedupTTL:        7 * 24 * time.Hour,
 		ConsumerPollInterval: 2 * time.Second,
+		ProbeTick:            time.Second,
 	}
 	if raw, err := os.ReadFile(path); err == nil {
 		var f configFile
@@ -69,6 +101,51 @@ func LoadConfig(path string) (Config, error) {
 		if f.Runtime.ConsumerPollSeconds > 0 {
 			cfg.ConsumerPollInterval = time.Duration(f.Runtime.ConsumerPollSeconds) * time.Second
 		}
+		if f.Probing.TickSeconds > 0 {
+			cfg.ProbeTick = time.Duration(f.Probing.TickSeconds) * time.Second
+		}
+		targetNames := map[string]bool{}
+		for _, t := range f.Probing.Targets {
+			target, err := domain.NormalizeProbeTarget(domain.ProbeTarget{
+				Name:              t.Name,
+				Kind:              t.Kind,
+				Target:            t.Target,
+				GRPCService:       t.GRPCService,
+				ExpectStatus:      t.ExpectStatus,
+				Interval:          time.Duration(t.IntervalSeconds) * time.Second,
+				Timeout:           time.Duration(t.TimeoutMS) * time.Millisecond,
+				FailureThreshold:  t.FailureThreshold,
+				DegradedLatencyMS: t.DegradedLatencyMS,
+				Critical:          t.Critical,
+			})
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid probe target %q: %w", t.Name, err)
+			}
+			if targetNames[target.Name] {
+				return Config{}, fmt.Errorf("duplicate probe target %q", target.Name)
+			}
+			targetNames[target.Name] = true
+			cfg.ProbeTargets = append(cfg.ProbeTargets, target)
+		}
+		sloNames := map[string]bool{}
+		for _, o := range f.SLOs {
+			slo, err := domain.NormalizeSLO(domain.SLO{
+				Name:               o.Name,
+				Target:             o.Target,
+				Kind:               o.Kind,
+				Objective:          o.Objective,
+				LatencyThresholdMS: o.LatencyThresholdMS,
+				Window:             time.Duration(o.WindowDays) * 24 * time.Hour,
+			})
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid slo %q: %w", o.Name, err)
+			}
+			if sloNames[slo.Name] {
+				return Config{}, fmt.Errorf("duplicate slo %q", slo.Name)
+			}
+			sloNames[slo.Name] = true
+			cfg.SLOs = append(cfg.SLOs, slo)
+		}
 	}
 	cfg.HTTPPort = envInt("HTTP_PORT", cfg.HTTPPort)
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
@@ -12,10 +12,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	eventadapter "github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/adapters/events"
 	grpcadapter "github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/adapters/grpc"
 	httpadapter "github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/adapters/http"
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/adapters/postgres"
+	probeadapter "github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/adapters/probe"
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/application"
 	"google.golang.org/grpc"
 )
@@ -23,18 +25,24 @@ import (
 type Runtime struct {
 	cfg        Config
 	logger     *slog.Logger
+	telemetry  *observability.Telemetry
 	httpServer *http.Server
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
 	worker     *eventadapter.Worker
+	prober     *probeadapter.Runner
 }
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", cfg.ServiceID)
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, cfg.Version))
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))).With("service", cfg.ServiceID)
 	slog.SetDefault(logger)
 
 	repos := postgres.NewRepositories()
@@ -49,26 +57,30 @@ func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
 			IdempotencyTTL:       cfg.IdempotencyTTL,
 			EventDedupTTL:        cfg.EventDedupTTL,
 			ConsumerPollInterval: cfg.ConsumerPollInterval,
+			ProbeTargets:         cfg.ProbeTargets,
+			SLOs:                 cfg.SLOs,
 		},
 		Components:   repos.Components,
 		Metrics:      repos.Metrics,
+		Probes:       repos.Probes,
 		Idempotency:  repos.Idempotency,
 		EventDedup:   repos.EventDedup,
 		Outbox:       repos.Outbox,
 		DomainEvents: domainPub,
 		Analytics:    analyticsPub,
 		DLQ:          dlqPub,
+		Prober:       probeadapter.NewProber(),
 	})
 
 	handler := httpadapter.NewHandler(svc)
 	router := httpadapter.NewRouter(handler, svc)
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
-		Handler:           router,
+		Handler:           observability.HTTPMiddleware(cfg.ServiceID)(router),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(observability.ServerOptions()...)
 	grpcadapter.Register(grpcServer, grpcadapter.NewObservabilityInternalServer(svc))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
@@ -76,13 +88,17 @@ func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
 	}
 
 	worker := eventadapter.NewWorker(logger, consumer, dlqPub, svc, cfg.ConsumerPollInterval)
-	return &Runtime{cfg: cfg, logger: logger, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker}, nil
+	prober := probeadapter.NewRunner(logger, svc, cfg.ProbeTick)
+	return &Runtime{cfg: cfg, logger: logger, telemetry: telemetry, httpServer: httpServer, grpcServer: grpcServer, grpcLis: lis, worker: worker, prober: prober}, nil
 }
 
 func (r *Runtime) RunAPI(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 2)
+	// Probes run next to the API so /health and /metrics serve their results.
+	go func() { _ = r.prober.Run(ctx) }()
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
@@ -108,6 +124,7 @@ func (r *Runtime) RunAPI(ctx context.Context) error {
 func (r *Runtime) RunWorker(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	defer r.shutdownTelemetry()
 	errCh := make(chan error, 1)
 	go func() {
 		if err := r.worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
@@ -121,3 +138,11 @@ func (r *Runtime) RunWorker(ctx context.Context) error {
 		return err
 	}
 }
+
+func (r *Runtime) shutdownTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.telemetry.Shutdown(ctx); err != nil {
+		r.logger.Warn("telemetry shutdown failed", "error", err)
+	}
+}
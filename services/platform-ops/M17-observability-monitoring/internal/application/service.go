@@ -125,34 +125,17 @@ func (s *Service) UpsertComponent(ctx context.Context, actor Actor, in UpsertCom
 	return row, nil
 }
 
-func (s *Service) RecordHTTPMetric(ctx context.Context, in MetricObservation) {
-	if s.metrics == nil {
-		return
-	}
-	path := strings.TrimSpace(in.Path)
-	if path == "" {
-		path = "/unknown"
-	}
-	method := strings.ToUpper(strings.TrimSpace(in.Method))
-	if method == "" {
-		method = "GET"
-	}
-	status := "0"
-	if in.StatusCode > 0 {
-		status = strconvItoa(in.StatusCode)
-	}
-	labels := map[string]string{
-		"service": s.cfg.ServiceName,
-		"method":  method,
-		"path":    path,
-		"status":  status,
-	}
-	_ = s.metrics.IncCounter(ctx, "http_requests_total", labels, 1)
-	_ = s.metrics.ObserveHistogram(ctx, "http_request_duration_seconds", map[string]string{
-		"service": s.cfg.ServiceName,
-		"method":  method,
-		"path":    path,
-	}, in.Duration.Seconds(), []float64{0.1, 0.5, 1.0, 2.5, 5.0})
+var metricHelp = map[string]string{
+	"http_requests_total":           "Total HTTP requests",
+	"http_request_duration_seconds": "Request latency",
+	"probe_checks_total":            "Synthetic probe checks by result",
+	"probe_duration_seconds":        "Synthetic probe latency",
+	"probe_consecutive_failures":    "Consecutive failed probes per target",
+	"slo_sli":                       "Service level indicator over the SLO window",
+	"slo_objective":                 "Service level objective",
+	"slo_error_budget_remaining":    "Fraction of the error budget left in the SLO window",
+	"slo_burn_rate":                 "Error budget burn rate per alert window",
+	"slo_burn_rate_alert":           "Whether a multi-window burn-rate alert fires",
 }
 
 func (s *Service) RenderPrometheusMetrics(ctx context.Context) (string, error) {
@@ -161,30 +144,38 @@ func (s *Service) RenderPrometheusMetrics(ctx context.Context) (string, error) {
 		return "", err
 	}
 	var b strings.Builder
-	if len(snapshot.Counters) > 0 {
-		b.WriteString("# HELP http_requests_total Total HTTP requests\n")
-		b.WriteString("# TYPE http_requests_total counter\n")
-		for _, c := range snapshot.Counters {
-			if c.Name != "http_requests_total" {
-				continue
-			}
-			b.WriteString(c.Name)
-			b.WriteString(formatLabels(c.Labels))
-			b.WriteString(" ")
-			b.WriteString(trimFloat(c.Value))
+	writeHeader := func(name, kind string) {
+		b.WriteString("# HELP " + name + " " + metricHelp[name] + "\n")
+		b.WriteString("# TYPE " + name + " " + kind + "\n")
+	}
+	for i, c := range snapshot.Counters {
+		if i == 0 || snapshot.Counters[i-1].Name != c.Name {
+			writeHeader(c.Name, "counter")
+		}
+		b.WriteString(c.Name + formatLabels(c.Labels) + " " + trimFloat(c.Value) + "\n")
+		if i == len(snapshot.Counters)-1 || snapshot.Counters[i+1].Name != c.Name {
 			b.WriteString("\n")
 		}
-		b.WriteString("\n")
 	}
-	for _, h := range snapshot.Histograms {
-		if h.Name != "http_request_duration_seconds" {
-			continue
+	for i, g := range snapshot.Gauges {
+		if i == 0 || snapshot.Gauges[i-1].Name != g.Name {
+			writeHeader(g.Name, "gauge")
+		}
+		b.WriteString(g.Name + formatLabels(g.Labels) + " " + trimFloat(g.Value) + "\n")
+		if i == len(snapshot.Gauges)-1 || snapshot.Gauges[i+1].Name != g.Name {
+			b.WriteString("\n")
+		}
+	}
+	for i, h := range snapshot.Histograms {
+		if i == 0 || snapshot.Histograms[i-1].Name != h.Name {
+			writeHeader(h.Name, "histogram")
 		}
-		b.WriteString("# HELP http_request_duration_seconds Request latency\n")
-		b.WriteString("# TYPE http_request_duration_seconds histogram\n")
 		ordered := sortedBucketKeys(h.Buckets)
 		for _, le := range ordered {
 			lbl := copyMap(h.Labels)
+			if lbl == nil {
+				lbl = map[string]string{}
+			}
 			lbl["le"] = le
 			b.WriteString(h.Name + "_bucket" + formatLabels(lbl) + " " + trimFloat(h.Buckets[le]) + "\n")
 		}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
+)
+
+var probeLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// RunProbeCycle probes every target whose interval has elapsed, folds the
+// results into the component checks read by GetHealth and refreshes the SLO
+// metrics. Due targets are probed concurrently and the call returns once all
+// of them finished, which each target's timeout bounds. A target that fails
+// to record does not stop the others, pruning or the SLO refresh; every
+// error is joined into the result.
+func (s *Service) RunProbeCycle(ctx context.Context) error {
+	if s.prober == nil || s.probes == nil || len(s.cfg.ProbeTargets) == 0 {
+		return nil
+	}
+	now := s.nowFn()
+	var due []domain.ProbeTarget
+	s.probeMu.Lock()
+	for _, target := range s.cfg.ProbeTargets {
+		if next, ok := s.nextProbe[target.Name]; ok && now.Before(next) {
+			continue
+		}
+		s.nextProbe[target.Name] = now.Add(target.Interval)
+		due = append(due, target)
+	}
+	s.probeMu.Unlock()
+
+	errs := make([]error, len(due))
+	var wg sync.WaitGroup
+	for i, target := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.probeTarget(ctx, target)
+		}()
+	}
+	wg.Wait()
+	if len(due) == 0 {
+		return nil
+	}
+	errs = append(errs, s.probes.Prune(ctx, now.Add(-s.probeRetention())), s.refreshSLOMetrics(ctx))
+	return errors.Join(errs...)
+}
+
+func (s *Service) probeTarget(ctx context.Context, target domain.ProbeTarget) error {
+	checkedAt := s.nowFn()
+	probeCtx, cancel := context.WithTimeout(ctx, target.Timeout)
+	start := time.Now()
+	err := s.prober.Probe(probeCtx, target)
+	elapsed := time.Since(start)
+	cancel()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	result := domain.ProbeResult{Target: target.Name, Success: err == nil, LatencyMS: int(elapsed.Milliseconds()), CheckedAt: checkedAt}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	state, err := s.probes.Record(ctx, result)
+	if err != nil {
+		return err
+	}
+	if err := s.components.Upsert(ctx, domain.ComponentFromProbe(target, state)); err != nil {
+		return err
+	}
+
+	if s.metrics == nil {
+		return nil
+	}
+	outcome := "success"
+	if !result.Success {
+		outcome = "failure"
+	}
+	labels := map[string]string{"target": target.Name, "kind": target.Kind}
+	_ = s.metrics.ObserveHistogram(ctx, "probe_duration_seconds", labels, elapsed.Seconds(), probeLatencyBuckets)
+	_ = s.metrics.IncCounter(ctx, "probe_checks_total", map[string]string{"target": target.Name, "kind": target.Kind, "result": outcome}, 1)
+	_ = s.metrics.SetGauge(ctx, "probe_consecutive_failures", labels, float64(state.ConsecutiveFailures))
+	return nil
+}
+
+// ListProbes returns every configured target with its latest outcome.
+func (s *Service) ListProbes(ctx context.Context) ([]ProbeStatus, error) {
+	states := map[string]domain.ProbeState{}
+	if s.probes != nil {
+		rows, err := s.probes.States(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			states[row.Target] = row
+		}
+	}
+	out := make([]ProbeStatus, 0, len(s.cfg.ProbeTargets))
+	for _, target := range s.cfg.ProbeTargets {
+		state, ok := states[target.Name]
+		status := ""
+		if ok {
+			status = domain.ComponentFromProbe(target, state).Status
+		}
+		out = append(out, ProbeStatus{Target: target, State: state, Status: status})
+	}
+	return out, nil
+}
+
+// probeRetention covers the longest SLO and alert window.
+func (s *Service) probeRetention() time.Duration {
+	retention := 24 * time.Hour
+	for _, slo := range s.cfg.SLOs {
+		retention = max(retention, slo.Window)
+	}
+	for _, w := range s.cfg.BurnRateWindows {
+		retention = max(retention, w.Long)
+	}
+	return retention
+}
+
+// normalizeProbeTargets drops invalid targets and repeated names, logging
+// each one it discards; bootstrap rejects both before they get here.
+func normalizeProbeTargets(in []domain.ProbeTarget) []domain.ProbeTarget {
+	out := make([]domain.ProbeTarget, 0, len(in))
+	seen := map[string]bool{}
+	for _, row := range in {
+		target, err := domain.NormalizeProbeTarget(row)
+		switch {
+		case err != nil:
+			slog.Warn("discarding invalid probe target", "name", row.Name, "kind", row.Kind, "target", row.Target, "error", err)
+			continue
+		case seen[target.Name]:
+			slog.Warn("discarding probe target with a repeated name", "name", target.Name, "target", target.Target)
+			continue
+		}
+		seen[target.Name] = true
+		out = append(out, target)
+	}
+	return out
+}
+
+// normalizeSLOs drops invalid SLOs and repeated names, logging each one it
+// discards.
+func normalizeSLOs(in []domain.SLO) []domain.SLO {
+	out := make([]domain.SLO, 0, len(in))
+	seen := map[string]bool{}
+	for _, row := range in {
+		slo, err := domain.NormalizeSLO(row)
+		switch {
+		case err != nil:
+			slog.Warn("discarding invalid slo", "name", row.Name, "target", row.Target, "kind", row.Kind, "error", err)
+			continue
+		case seen[slo.Name]:
+			slog.Warn("discarding slo with a repeated name", "name", slo.Name, "target", slo.Target)
+			continue
+		}
+		seen[slo.Name] = true
+		out = append(out, slo)
+	}
+	return out
+}
+
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0 && d >= 24*time.Hour:
+		return strconvItoa(int(d/(24*time.Hour))) + "d"
+	case d%time.Hour == 0:
+		return strconvItoa(int(d/time.Hour)) + "h"
+	case d%time.Minute == 0:
+		return strconvItoa(int(d/time.Minute)) + "m"
+	default:
+		return strings.TrimSuffix(d.String(), "0s")
+	}
+}
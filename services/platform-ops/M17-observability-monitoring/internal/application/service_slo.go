@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
+)
+
+// ListSLOStatuses evaluates every configured SLO against the recorded probe
+// results.
+func (s *Service) ListSLOStatuses(ctx context.Context) ([]domain.SLOStatus, error) {
+	out := make([]domain.SLOStatus, 0, len(s.cfg.SLOs))
+	for _, slo := range s.cfg.SLOs {
+		status, err := s.evaluateSLO(ctx, slo)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+func (s *Service) GetSLOStatus(ctx context.Context, name string) (domain.SLOStatus, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, slo := range s.cfg.SLOs {
+		if slo.Name == name {
+			return s.evaluateSLO(ctx, slo)
+		}
+	}
+	return domain.SLOStatus{}, domain.ErrNotFound
+}
+
+func (s *Service) evaluateSLO(ctx context.Context, slo domain.SLO) (domain.SLOStatus, error) {
+	now := s.nowFn()
+	var results []domain.ProbeResult
+	if s.probes != nil {
+		var err error
+		results, err = s.probes.ListSince(ctx, slo.Target, now.Add(-slo.Window))
+		if err != nil {
+			return domain.SLOStatus{}, err
+		}
+	}
+	return domain.EvaluateSLO(slo, s.cfg.BurnRateWindows, results, now), nil
+}
+
+func (s *Service) refreshSLOMetrics(ctx context.Context) error {
+	if s.metrics == nil {
+		return nil
+	}
+	statuses, err := s.ListSLOStatuses(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		labels := map[string]string{"slo": status.SLO.Name, "target": status.SLO.Target}
+		_ = s.metrics.SetGauge(ctx, "slo_sli", labels, status.SLI)
+		_ = s.metrics.SetGauge(ctx, "slo_objective", labels, status.SLO.Objective)
+		_ = s.metrics.SetGauge(ctx, "slo_error_budget_remaining", labels, status.ErrorBudgetRemaining)
+		firing := map[string]float64{domain.SeverityPage: 0, domain.SeverityTicket: 0}
+		for _, rate := range status.BurnRates {
+			for window, value := range map[string]float64{
+				formatWindow(rate.Window.Long):  rate.LongRate,
+				formatWindow(rate.Window.Short): rate.ShortRate,
+			} {
+				_ = s.metrics.SetGauge(ctx, "slo_burn_rate", map[string]string{"slo": status.SLO.Name, "target": status.SLO.Target, "window": window}, value)
+			}
+			if rate.Firing {
+				firing[rate.Window.Severity] = 1
+			}
+		}
+		for severity, value := range firing {
+			_ = s.metrics.SetGauge(ctx, "slo_burn_rate_alert", map[string]string{"slo": status.SLO.Name, "target": status.SLO.Target, "severity": severity}, value)
+		}
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package application
 
 import (
+	"sync"
 	"time"
 
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
+
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/ports"
 )
 
@@ -12,6 +15,9 @@ type Config struct {
 	IdempotencyTTL       time.Duration
 	EventDedupTTL        time.Duration
 	ConsumerPollInterval time.Duration
+	ProbeTargets         []domain.ProbeTarget
+	SLOs                 []domain.SLO
+	BurnRateWindows      []domain.BurnRateWindow
 }
 
 type Actor struct {
@@ -31,11 +37,11 @@ type UpsertComponentInput struct {
 	Metadata         map[string]string
 }
 
-type MetricObservation struct {
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
+// ProbeStatus is the configured target together with its latest outcome.
+type ProbeStatus struct {
+	Target domain.ProbeTarget
+	State  domain.ProbeState
+	Status string
 }
 
 type Service struct {
@@ -43,6 +49,7 @@ type Service struct {
 
 	components  ports.ComponentCheckRepository
 	metrics     ports.MetricsRepository
+	probes      ports.ProbeResultRepository
 	idempotency ports.IdempotencyRepository
 	eventDedup  ports.EventDedupRepository
 	outbox      ports.OutboxRepository
@@ -50,6 +57,10 @@ type Service struct {
 	domainEvents ports.DomainPublisher
 	analytics    ports.AnalyticsPublisher
 	dlq          ports.DLQPublisher
+	prober       ports.Prober
+
+	probeMu   sync.Mutex
+	nextProbe map[string]time.Time
 
 	startedAt time.Time
 	nowFn     func() time.Time
@@ -60,6 +71,7 @@ type Dependencies struct {
 
 	Components  ports.ComponentCheckRepository
 	Metrics     ports.MetricsRepository
+	Probes      ports.ProbeResultRepository
 	Idempotency ports.IdempotencyRepository
 	EventDedup  ports.EventDedupRepository
 	Outbox      ports.OutboxRepository
@@ -67,6 +79,7 @@ type Dependencies struct {
 	DomainEvents ports.DomainPublisher
 	Analytics    ports.AnalyticsPublisher
 	DLQ          ports.DLQPublisher
+	Prober       ports.Prober
 }
 
 func NewService(deps Dependencies) *Service {
@@ -86,17 +99,25 @@ func NewService(deps Dependencies) *Service {
 	if cfg.ConsumerPollInterval <= 0 {
 		cfg.ConsumerPollInterval = 2 * time.Second
 	}
+	cfg.ProbeTargets = normalizeProbeTargets(cfg.ProbeTargets)
+	cfg.SLOs = normalizeSLOs(cfg.SLOs)
+	if len(cfg.BurnRateWindows) == 0 {
+		cfg.BurnRateWindows = domain.DefaultBurnRateWindows
+	}
 	now := time.Now().UTC()
 	return &Service{
 		cfg:          cfg,
 		components:   deps.Components,
 		metrics:      deps.Metrics,
+		probes:       deps.Probes,
 		idempotency:  deps.Idempotency,
 		eventDedup:   deps.EventDedup,
 		outbox:       deps.Outbox,
 		domainEvents: deps.DomainEvents,
 		analytics:    deps.Analytics,
 		dlq:          deps.DLQ,
+		prober:       deps.Prober,
+		nextProbe:    map[string]time.Time{},
 		startedAt:    now,
 		nowFn:        func() time.Time { return time.Now().UTC() },
 	}
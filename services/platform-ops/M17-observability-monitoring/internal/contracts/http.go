@@ -58,3 +58,51 @@ type NamedComponentResponse struct {
 	Error            string            `json:"error,omitempty"`
 	Metadata         map[string]string `json:"metadata,omitempty"`
 }
+
+type ProbeResponse struct {
+	Name                string `json:"name"`
+	Kind                string `json:"kind"`
+	Target              string `json:"target"`
+	Critical            bool   `json:"critical"`
+	IntervalSeconds     int    `json:"interval_seconds"`
+	FailureThreshold    int    `json:"failure_threshold"`
+	Status              string `json:"status,omitempty"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LatencyMS           int    `json:"latency_ms,omitempty"`
+	Error               string `json:"error,omitempty"`
+	LastChecked         string `json:"last_checked,omitempty"`
+}
+
+type ProbesListResponse struct {
+	Items []ProbeResponse `json:"items"`
+}
+
+type BurnRateResponse struct {
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	Threshold   float64 `json:"threshold"`
+	Severity    string  `json:"severity"`
+	LongRate    float64 `json:"long_rate"`
+	ShortRate   float64 `json:"short_rate"`
+	Firing      bool    `json:"firing"`
+}
+
+type SLOResponse struct {
+	Name                 string             `json:"name"`
+	Target               string             `json:"target"`
+	Kind                 string             `json:"kind"`
+	Objective            float64            `json:"objective"`
+	LatencyThresholdMS   int                `json:"latency_threshold_ms,omitempty"`
+	WindowDays           int                `json:"window_days"`
+	TotalEvents          int                `json:"total_events"`
+	GoodEvents           int                `json:"good_events"`
+	SLI                  float64            `json:"sli"`
+	ErrorBudgetRemaining float64            `json:"error_budget_remaining"`
+	BurnRates            []BurnRateResponse `json:"burn_rates"`
+	Alerting             string             `json:"alerting,omitempty"`
+	EvaluatedAt          string             `json:"evaluated_at"`
+}
+
+type SLOsListResponse struct {
+	Items []SLOResponse `json:"items"`
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ProbeKindHTTP       = "http"
+	ProbeKindGRPCHealth = "grpc_health"
+	ProbeKindTCP        = "tcp"
+	ProbeKindDNS        = "dns"
+)
+
+// ProbeTarget is an actively checked dependency. Target is a URL for http
+// probes, host:port for grpc_health and tcp probes and a hostname for dns
+// probes.
+type ProbeTarget struct {
+	Name              string        `json:"name"`
+	Kind              string        `json:"kind"`
+	Target            string        `json:"target"`
+	GRPCService       string        `json:"grpc_service,omitempty"`
+	ExpectStatus      int           `json:"expect_status,omitempty"`
+	Interval          time.Duration `json:"interval"`
+	Timeout           time.Duration `json:"timeout"`
+	FailureThreshold  int           `json:"failure_threshold"`
+	DegradedLatencyMS int           `json:"degraded_latency_ms,omitempty"`
+	Critical          bool          `json:"critical"`
+}
+
+type ProbeResult struct {
+	Target    string    `json:"target"`
+	Success   bool      `json:"success"`
+	LatencyMS int       `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ProbeState tracks the consecutive outcomes of a target.
+type ProbeState struct {
+	Target               string      `json:"target"`
+	ConsecutiveFailures  int         `json:"consecutive_failures"`
+	ConsecutiveSuccesses int         `json:"consecutive_successes"`
+	Last                 ProbeResult `json:"last"`
+}
+
+func NormalizeProbeKind(v string) string {
+	switch v = strings.ToLower(strings.TrimSpace(v)); v {
+	case ProbeKindHTTP, ProbeKindGRPCHealth, ProbeKindTCP, ProbeKindDNS:
+		return v
+	case "grpc":
+		return ProbeKindGRPCHealth
+	default:
+		return ""
+	}
+}
+
+// NormalizeProbeTarget validates t and fills in interval, timeout and
+// threshold defaults.
+func NormalizeProbeTarget(t ProbeTarget) (ProbeTarget, error) {
+	t.Name = strings.ToLower(strings.TrimSpace(t.Name))
+	t.Kind = NormalizeProbeKind(t.Kind)
+	t.Target = strings.TrimSpace(t.Target)
+	if t.Name == "" || t.Kind == "" || t.Target == "" {
+		return ProbeTarget{}, ErrInvalidInput
+	}
+	if t.Interval <= 0 {
+		t.Interval = 30 * time.Second
+	}
+	if t.Timeout <= 0 {
+		t.Timeout = 5 * time.Second
+	}
+	if t.Timeout > t.Interval {
+		t.Timeout = t.Interval
+	}
+	if t.FailureThreshold <= 0 {
+		t.FailureThreshold = 3
+	}
+	return t, nil
+}
+
+// Observe folds r into the state.
+func (s ProbeState) Observe(r ProbeResult) ProbeState {
+	s.Target = r.Target
+	s.Last = r
+	if r.Success {
+		s.ConsecutiveSuccesses++
+		s.ConsecutiveFailures = 0
+	} else {
+		s.ConsecutiveFailures++
+		s.ConsecutiveSuccesses = 0
+	}
+	return s
+}
+
+// ComponentFromProbe derives the component check of a target. A target is
+// unhealthy once FailureThreshold consecutive probes failed and degraded
+// while it answers slower than DegradedLatencyMS. Failures below the
+// threshold only surface in Error so a single lost probe does not flip the
+// rollup.
+func ComponentFromProbe(t ProbeTarget, s ProbeState) ComponentCheck {
+	status := StatusHealthy
+	switch {
+	case s.ConsecutiveFailures >= t.FailureThreshold:
+		status = StatusUnhealthy
+	case s.ConsecutiveFailures == 0 && t.DegradedLatencyMS > 0 && s.Last.LatencyMS > t.DegradedLatencyMS:
+		status = StatusDegraded
+	}
+	return ComponentCheck{
+		Name:        t.Name,
+		Status:      status,
+		Critical:    t.Critical,
+		LatencyMS:   s.Last.LatencyMS,
+		Error:       s.Last.Error,
+		LastChecked: s.Last.CheckedAt,
+		Metadata: map[string]string{
+			"source":               "probe",
+			"probe_kind":           t.Kind,
+			"probe_target":         t.Target,
+			"consecutive_failures": strconv.Itoa(s.ConsecutiveFailures),
+		},
+	}
+}
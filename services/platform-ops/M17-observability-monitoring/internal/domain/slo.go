@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	SLOKindAvailability = "availability"
+	SLOKindLatency      = "latency"
+
+	SeverityPage   = "page"
+	SeverityTicket = "ticket"
+)
+
+// SLO is an objective over the probe results of one target. Availability
+// SLOs count successful probes as good; latency SLOs additionally require the
+// probe to finish within LatencyThresholdMS.
+type SLO struct {
+	Name               string        `json:"name"`
+	Target             string        `json:"target"`
+	Kind               string        `json:"kind"`
+	Objective          float64       `json:"objective"`
+	LatencyThresholdMS int           `json:"latency_threshold_ms,omitempty"`
+	Window             time.Duration `json:"window"`
+}
+
+// BurnRateWindow is one multi-window burn-rate alert: it fires when both the
+// long and the short window burn the error budget faster than Threshold.
+type BurnRateWindow struct {
+	Long      time.Duration `json:"long"`
+	Short     time.Duration `json:"short"`
+	Threshold float64       `json:"threshold"`
+	Severity  string        `json:"severity"`
+}
+
+// DefaultBurnRateWindows are the multi-window, multi-burn-rate alerts
+// recommended for a 30 day window: 2% and 5% of the budget spent within
+// 1h and 6h page, 10% within 3d opens a ticket.
+var DefaultBurnRateWindows = []BurnRateWindow{
+	{Long: time.Hour, Short: 5 * time.Minute, Threshold: 14.4, Severity: SeverityPage},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, Threshold: 6, Severity: SeverityPage},
+	{Long: 72 * time.Hour, Short: 6 * time.Hour, Threshold: 1, Severity: SeverityTicket},
+}
+
+type BurnRate struct {
+	Window    BurnRateWindow `json:"window"`
+	LongRate  float64        `json:"long_rate"`
+	ShortRate float64        `json:"short_rate"`
+	Firing    bool           `json:"firing"`
+}
+
+type SLOStatus struct {
+	SLO                  SLO        `json:"slo"`
+	TotalEvents          int        `json:"total_events"`
+	GoodEvents           int        `json:"good_events"`
+	SLI                  float64    `json:"sli"`
+	ErrorBudgetRemaining float64    `json:"error_budget_remaining"`
+	BurnRates            []BurnRate `json:"burn_rates"`
+	Alerting             string     `json:"alerting,omitempty"`
+	EvaluatedAt          time.Time  `json:"evaluated_at"`
+}
+
+func NormalizeSLO(s SLO) (SLO, error) {
+	s.Name = strings.ToLower(strings.TrimSpace(s.Name))
+	s.Target = strings.ToLower(strings.TrimSpace(s.Target))
+	s.Kind = strings.ToLower(strings.TrimSpace(s.Kind))
+	if s.Kind == "" {
+		s.Kind = SLOKindAvailability
+	}
+	if s.Name == "" || s.Target == "" || s.Objective <= 0 || s.Objective >= 1 {
+		return SLO{}, ErrInvalidInput
+	}
+	switch s.Kind {
+	case SLOKindAvailability:
+	case SLOKindLatency:
+		if s.LatencyThresholdMS <= 0 {
+			return SLO{}, ErrInvalidInput
+		}
+	default:
+		return SLO{}, ErrInvalidInput
+	}
+	if s.Window <= 0 {
+		s.Window = 30 * 24 * time.Hour
+	}
+	return s, nil
+}
+
+// Good reports whether r counts towards the objective.
+func (s SLO) Good(r ProbeResult) bool {
+	if !r.Success {
+		return false
+	}
+	return s.Kind != SLOKindLatency || r.LatencyMS <= s.LatencyThresholdMS
+}
+
+// EvaluateSLO computes the SLI and remaining error budget over the SLO window
+// and the burn rate of every alert window. results may be in any order;
+// results outside the SLO window are ignored. A burn rate of 1 spends the
+// budget exactly over the window. The remaining budget turns negative once it
+// is overspent.
+func EvaluateSLO(s SLO, windows []BurnRateWindow, results []ProbeResult, now time.Time) SLOStatus {
+	out := SLOStatus{SLO: s, SLI: 1, ErrorBudgetRemaining: 1, EvaluatedAt: now}
+	budget := 1 - s.Objective
+	total, good := countGood(s, results, now.Add(-s.Window), now)
+	out.TotalEvents, out.GoodEvents = total, good
+	if total > 0 {
+		out.SLI = float64(good) / float64(total)
+		out.ErrorBudgetRemaining = 1 - (1-out.SLI)/budget
+	}
+	for _, w := range windows {
+		rate := BurnRate{
+			Window:    w,
+			LongRate:  burnRate(s, results, now.Add(-w.Long), now, budget),
+			ShortRate: burnRate(s, results, now.Add(-w.Short), now, budget),
+		}
+		rate.Firing = rate.LongRate >= w.Threshold && rate.ShortRate >= w.Threshold
+		if rate.Firing && out.Alerting != SeverityPage {
+			out.Alerting = w.Severity
+		}
+		out.BurnRates = append(out.BurnRates, rate)
+	}
+	return out
+}
+
+func burnRate(s SLO, results []ProbeResult, from, to time.Time, budget float64) float64 {
+	total, good := countGood(s, results, from, to)
+	if total == 0 {
+		return 0
+	}
+	return (float64(total-good) / float64(total)) / budget
+}
+
+func countGood(s SLO, results []ProbeResult, from, to time.Time) (total, good int) {
+	for _, r := range results {
+		if r.Target != s.Target || r.CheckedAt.Before(from) || r.CheckedAt.After(to) {
+			continue
+		}
+		total++
+		if s.Good(r) {
+			good++
+		}
+	}
+	return total, good
+}
@@ -1,3 +1,15 @@
 package ports
 
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
+)
+
 // M17 has no declared owner_api DBR dependencies in canonical dependencies.yaml.
+
+// Prober runs one active check against a target and returns nil when the
+// target answered as expected.
+type Prober interface {
+	Probe(ctx context.Context, target domain.ProbeTarget) error
+}
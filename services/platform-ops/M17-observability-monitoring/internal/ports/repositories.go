@@ -19,6 +19,12 @@ type MetricCounterPoint struct {
 	Value  float64
 }
 
+type MetricGaugePoint struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
 type MetricHistogramPoint struct {
 	Name     string
 	Labels   map[string]string
@@ -30,15 +36,26 @@ type MetricHistogramPoint struct {
 
 type MetricsSnapshot struct {
 	Counters   []MetricCounterPoint
+	Gauges     []MetricGaugePoint
 	Histograms []MetricHistogramPoint
 }
 
 type MetricsRepository interface {
 	IncCounter(ctx context.Context, name string, labels map[string]string, delta float64) error
+	SetGauge(ctx context.Context, name string, labels map[string]string, value float64) error
 	ObserveHistogram(ctx context.Context, name string, labels map[string]string, value float64, buckets []float64) error
 	Snapshot(ctx context.Context) (MetricsSnapshot, error)
 }
 
+// ProbeResultRepository keeps probe results for SLO evaluation and the
+// consecutive-outcome state of every target.
+type ProbeResultRepository interface {
+	Record(ctx context.Context, result domain.ProbeResult) (domain.ProbeState, error)
+	States(ctx context.Context) ([]domain.ProbeState, error)
+	ListSince(ctx context.Context, target string, since time.Time) ([]domain.ProbeResult, error)
+	Prune(ctx context.Context, before time.Time) error
+}
+
 type IdempotencyRecord struct {
 	Key          string
 	RequestHash  string
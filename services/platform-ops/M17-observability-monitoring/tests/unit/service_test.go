@@ -1,14 +1,21 @@
 package unit
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/adapters/postgres"
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/app/bootstrap"
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/application"
 	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/domain"
+	"github.com/viralforge/mesh/services/platform-ops/M17-observability-monitoring/internal/ports"
 )
 
 func newService() *application.Service {
@@ -77,19 +84,177 @@ func TestHealthUnhealthyWhenCriticalDegraded(t *testing.T) {
 	}
 }
 
-func TestMetricsRenderIncludesHttpSeries(t *testing.T) {
-	svc := newService()
-	svc.RecordHTTPMetric(context.Background(), application.MetricObservation{
-		Method: "GET", Path: "/health", StatusCode: 200, Duration: 35 * time.Millisecond,
+type scriptedProber struct{ fail bool }
+
+func (p *scriptedProber) Probe(context.Context, domain.ProbeTarget) error {
+	if p.fail {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestProbeFailuresRollUpAfterThreshold(t *testing.T) {
+	repos := postgres.NewRepositories()
+	prober := &scriptedProber{}
+	svc := application.NewService(application.Dependencies{
+		Config: application.Config{
+			ProbeTargets: []domain.ProbeTarget{{Name: "auth-api", Kind: "http", Target: "http://auth", Interval: time.Nanosecond, FailureThreshold: 2, Critical: true}},
+			SLOs:         []domain.SLO{{Name: "auth-availability", Target: "auth-api", Objective: 0.9}},
+		},
+		Components: repos.Components,
+		Metrics:    repos.Metrics,
+		Probes:     repos.Probes,
+		Prober:     prober,
+	})
+	ctx := context.Background()
+
+	cycle := func() domain.HealthReport {
+		time.Sleep(time.Millisecond)
+		if err := svc.RunProbeCycle(ctx); err != nil {
+			t.Fatalf("probe cycle: %v", err)
+		}
+		out, err := svc.GetHealth(ctx)
+		if err != nil {
+			t.Fatalf("get health: %v", err)
+		}
+		return out
+	}
+	if out := cycle(); out.Status != domain.StatusHealthy || out.Checks["auth-api"].Status != domain.StatusHealthy {
+		t.Fatalf("expected healthy probe, got %+v", out.Checks["auth-api"])
+	}
+	prober.fail = true
+	if out := cycle(); out.Status != domain.StatusHealthy || out.Checks["auth-api"].Error == "" {
+		t.Fatalf("expected single failure below threshold, got %s %+v", out.Status, out.Checks["auth-api"])
+	}
+	if out := cycle(); out.Status != domain.StatusUnhealthy {
+		t.Fatalf("expected unhealthy after threshold, got %s", out.Status)
+	}
+
+	slo, err := svc.GetSLOStatus(ctx, "auth-availability")
+	if err != nil {
+		t.Fatalf("get slo: %v", err)
+	}
+	if slo.TotalEvents != 3 || slo.GoodEvents != 1 || slo.ErrorBudgetRemaining >= 0 {
+		t.Fatalf("expected overspent budget after 2 of 3 failures, got %+v", slo)
+	}
+	metrics, err := svc.RenderPrometheusMetrics(ctx)
+	if err != nil {
+		t.Fatalf("render metrics: %v", err)
+	}
+	for _, series := range []string{"probe_duration_seconds_bucket", "probe_checks_total", "slo_error_budget_remaining", `slo_burn_rate{slo="auth-availability",target="auth-api",window="1h"}`} {
+		if !strings.Contains(metrics, series) {
+			t.Fatalf("expected %s in metrics output", series)
+		}
+	}
+}
+
+type flakyComponents struct {
+	ports.ComponentCheckRepository
+	failFor string
+}
+
+func (r flakyComponents) Upsert(ctx context.Context, row domain.ComponentCheck) error {
+	if row.Name == r.failFor {
+		return errors.New("component store unavailable")
+	}
+	return r.ComponentCheckRepository.Upsert(ctx, row)
+}
+
+func TestProbeCycleContinuesPastFailingTarget(t *testing.T) {
+	repos := postgres.NewRepositories()
+	svc := application.NewService(application.Dependencies{
+		Config: application.Config{
+			ProbeTargets: []domain.ProbeTarget{
+				{Name: "auth-api", Kind: "http", Target: "http://auth", Interval: time.Minute},
+				{Name: "billing-api", Kind: "http", Target: "http://billing", Interval: time.Minute},
+			},
+			SLOs: []domain.SLO{{Name: "billing-availability", Target: "billing-api", Objective: 0.9}},
+		},
+		Components: flakyComponents{ComponentCheckRepository: repos.Components, failFor: "auth-api"},
+		Metrics:    repos.Metrics,
+		Probes:     repos.Probes,
+		Prober:     &scriptedProber{},
 	})
-	out, err := svc.RenderPrometheusMetrics(context.Background())
+	ctx := context.Background()
+
+	if err := svc.RunProbeCycle(ctx); err == nil || !strings.Contains(err.Error(), "component store unavailable") {
+		t.Fatalf("expected joined component error, got %v", err)
+	}
+	out, err := svc.GetHealth(ctx)
+	if err != nil {
+		t.Fatalf("get health: %v", err)
+	}
+	if _, ok := out.Checks["billing-api"]; !ok {
+		t.Fatalf("expected billing-api probe to be recorded, got %+v", out.Checks)
+	}
+	metrics, err := svc.RenderPrometheusMetrics(ctx)
 	if err != nil {
 		t.Fatalf("render metrics: %v", err)
 	}
-	if !strings.Contains(out, "http_requests_total") {
-		t.Fatalf("expected http_requests_total in metrics output")
+	if !strings.Contains(metrics, `slo_error_budget_remaining{slo="billing-availability"`) {
+		t.Fatalf("expected SLO metrics refreshed despite the failing target")
+	}
+}
+
+func TestSLOMultiWindowBurnRate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	slo, err := domain.NormalizeSLO(domain.SLO{Name: "api-latency", Target: "api", Kind: domain.SLOKindLatency, Objective: 0.99, LatencyThresholdMS: 200})
+	if err != nil {
+		t.Fatalf("normalize slo: %v", err)
+	}
+	var results []domain.ProbeResult
+	// A healthy day followed by ten minutes of slow responses.
+	for at := now.Add(-24 * time.Hour); at.Before(now.Add(-10 * time.Minute)); at = at.Add(time.Minute) {
+		results = append(results, domain.ProbeResult{Target: "api", Success: true, LatencyMS: 50, CheckedAt: at})
 	}
-	if !strings.Contains(out, "http_request_duration_seconds_bucket") {
-		t.Fatalf("expected histogram bucket in metrics output")
+	for at := now.Add(-10 * time.Minute); !at.After(now); at = at.Add(time.Minute) {
+		results = append(results, domain.ProbeResult{Target: "api", Success: true, LatencyMS: 900, CheckedAt: at})
+	}
+
+	out := domain.EvaluateSLO(slo, domain.DefaultBurnRateWindows, results, now)
+	if out.Alerting != domain.SeverityPage {
+		t.Fatalf("expected fast burn to page, got %+v", out.BurnRates)
+	}
+	fast := out.BurnRates[0]
+	if !fast.Firing || fast.ShortRate < 99.9 {
+		t.Fatalf("expected 1h/5m window firing at 100x short burn, got %+v", fast)
+	}
+	if out.BurnRates[2].Firing {
+		t.Fatalf("expected 3d/6h ticket window to stay quiet, got %+v", out.BurnRates[2])
+	}
+	if out.ErrorBudgetRemaining >= 1 || out.ErrorBudgetRemaining <= 0 {
+		t.Fatalf("expected partially spent budget, got %v", out.ErrorBudgetRemaining)
+	}
+}
+
+func TestConfigRejectsRepeatedProbeTargetNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	raw := `probing:
+  targets:
+    - {name: auth-api, kind: http, target: "http://auth:8080/healthz"}
+    - {name: auth-api, kind: tcp, target: "auth:5432"}
+`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := bootstrap.LoadConfig(path); err == nil || !strings.Contains(err.Error(), `duplicate probe target "auth-api"`) {
+		t.Fatalf("expected the repeated target to be rejected, got %v", err)
+	}
+}
+
+func TestNewServiceLogsDiscardedProbeTargetsAndSLOs(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	application.NewService(application.Dependencies{Config: application.Config{
+		ProbeTargets: []domain.ProbeTarget{{Name: "auth-api", Kind: "http", Target: "http://auth:8080/healthz"}, {Name: "broken", Kind: "smoke-signal", Target: "x"}},
+		SLOs:         []domain.SLO{{Name: "auth-availability", Target: "auth-api", Kind: domain.SLOKindAvailability, Objective: 1.5, Window: 24 * time.Hour}},
+	}})
+	for _, want := range []string{`msg="discarding invalid probe target" name=broken`, `msg="discarding invalid slo" name=auth-availability`} {
+		if !strings.Contains(logs.String(), want) {
+			t.Fatalf("expected %q in the logs, got %s", want, logs.String())
+		}
 	}
 }
@@ -48,26 +48,6 @@ func authMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func metricsMiddleware(service *application.Service) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			start := time.Now()
-			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
-			next.ServeHTTP(rec, r)
-			if service != nil {
-				service.RecordHTTPMetric(r.Context(), r.Method, r.URL.Path, rec.status, time.Since(start))
-			}
-		})
-	}
-}
-
-type statusRecorder struct {
-	http.ResponseWriter
-	status int
-}
-
-func (r *statusRecorder) WriteHeader(code int) { r.status = code; r.ResponseWriter.WriteHeader(code) }
-
 func actorFromContext(ctx context.Context) application.Actor {
 	if v := ctx.Value(actorKey); v != nil {
 		if a, ok := v.(application.Actor); ok {
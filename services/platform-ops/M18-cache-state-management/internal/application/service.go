@@ -191,8 +191,6 @@ func (s *Service) GetHealth(context.Context) (domain.HealthReport, error) {
 	}, nil
 }
 
-func (s *Service) RecordHTTPMetric(context.Context, string, string, int, time.Duration) {}
-
 func (s *Service) getIdempotent(ctx context.Context, key, expectedHash string) ([]byte, bool, error) {
 	if s.idempotency == nil || strings.TrimSpace(key) == "" {
 		return nil, false, nil
@@ -58,13 +58,6 @@ type AuditQueryInput struct {
 	Limit      int
 }
 
-type MetricObservation struct {
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
-}
-
 type Service struct {
 	cfg Config
 
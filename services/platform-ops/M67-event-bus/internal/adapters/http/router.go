@@ -10,7 +10,6 @@ import (
 func NewRouter(handler *Handler, service *application.Service) http.Handler {
 	r := chi.NewRouter()
 	r.Use(requestIDMiddleware)
-	r.Use(metricsMiddleware(service))
 
 	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		writeSuccess(w, http.StatusOK, "", map[string]string{"status": "ok"})
@@ -82,13 +82,6 @@ type DLQListInput struct {
 	IncludeReplayed bool
 }
 
-type MetricObservation struct {
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
-}
-
 type Service struct {
 	cfg Config
 
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/platform-ops/M78-logging-service/internal/contracts"
 	"github.com/viralforge/mesh/services/platform-ops/M78-logging-service/internal/domain"
 )
@@ -365,7 +366,7 @@ func (s *Service) publishOpsAlert(ctx context.Context, row domain.LogEvent, rule
 		PartitionKeyPath: "envelope.source_service",
 		PartitionKey:     s.cfg.ServiceName,
 		SourceService:    s.cfg.ServiceName,
-		TraceID:          traceID,
+		TraceID:          observability.TraceParent(ctx, traceID),
 		SchemaVersion:    "v1",
 		Data:             data,
 	}
@@ -408,30 +409,6 @@ func (s *Service) GetCacheMetrics(ctx context.Context) (domain.MetricsSnapshot,
 	return domain.MetricsSnapshot{}, nil
 }
 
-func (s *Service) RecordHTTPMetric(ctx context.Context, in MetricObservation) {
-	if s.metrics == nil {
-		return
-	}
-	path := strings.TrimSpace(in.Path)
-	if path == "" {
-		path = "/unknown"
-	}
-	method := strings.ToUpper(strings.TrimSpace(in.Method))
-	if method == "" {
-		method = "GET"
-	}
-	status := strconv.Itoa(in.StatusCode)
-	_ = s.metrics.IncCounter(ctx, "http_requests_total", map[string]string{
-		"service": s.cfg.ServiceName,
-		"method":  method,
-		"path":    path,
-		"status":  status,
-	}, 1)
-	_ = s.metrics.ObserveHistogram(ctx, "http_request_duration_seconds",
-		map[string]string{"service": s.cfg.ServiceName, "method": method, "path": path},
-		in.Duration.Seconds(), []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5})
-}
-
 func (s *Service) RenderPrometheusMetrics(ctx context.Context) (string, error) {
 	if s.metrics == nil {
 		return "# no metrics\n", nil
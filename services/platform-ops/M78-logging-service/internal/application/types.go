@@ -23,13 +23,6 @@ type Actor struct {
 	UserAgent      string
 }
 
-type MetricObservation struct {
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
-}
-
 type IngestLogRecordInput struct {
 	Timestamp  time.Time
 	Level      string
@@ -23,13 +23,6 @@ type Actor struct {
 	UserAgent      string
 }
 
-type MetricObservation struct {
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
-}
-
 type CreateAlertRuleInput struct {
 	Name            string
 	Query           string
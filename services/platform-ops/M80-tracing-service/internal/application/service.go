@@ -283,23 +283,6 @@ func (s *Service) GetCacheMetrics(ctx context.Context) (domain.MetricsSnapshot,
 	return domain.MetricsSnapshot{Hits: 0, Misses: 0, Evictions: 0, MemoryUsedBytes: 0, StoredTraces: int64(traceCount)}, nil
 }
 
-func (s *Service) RecordHTTPMetric(ctx context.Context, in MetricObservation) {
-	if s.metrics == nil {
-		return
-	}
-	path := strings.TrimSpace(in.Path)
-	if path == "" {
-		path = "/unknown"
-	}
-	method := strings.ToUpper(strings.TrimSpace(in.Method))
-	if method == "" {
-		method = "GET"
-	}
-	status := strconv.Itoa(in.StatusCode)
-	_ = s.metrics.IncCounter(ctx, "http_requests_total", map[string]string{"service": s.cfg.ServiceName, "method": method, "path": path, "status": status}, 1)
-	_ = s.metrics.ObserveHistogram(ctx, "http_request_duration_seconds", map[string]string{"service": s.cfg.ServiceName, "method": method, "path": path}, in.Duration.Seconds(), []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5})
-}
-
 func (s *Service) RenderPrometheusMetrics(ctx context.Context) (string, error) {
 	snap, err := s.metrics.Snapshot(ctx)
 	if err != nil {
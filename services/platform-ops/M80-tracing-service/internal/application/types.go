@@ -54,13 +54,6 @@ type CreateExportInput struct {
 	Filters map[string]string
 }
 
-type MetricObservation struct {
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
-}
-
 type Service struct {
 	cfg Config
 
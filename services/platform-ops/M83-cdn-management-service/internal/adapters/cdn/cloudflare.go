@@ -0,0 +1,181 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+)
+
+// CloudflareProvider talks to the Cloudflare v4 API of one zone. Cloudflare
+// applies purges synchronously, so a successful purge_cache call is reported
+// as completed on the first poll. Certificates are renewed by ordering an
+// advanced certificate pack for the certificate's domain; the pack is issued
+// asynchronously and its status is polled until it is active.
+type CloudflareProvider struct {
+	baseURL      string
+	zoneID       string
+	apiToken     string
+	validityDays int
+	client       *http.Client
+}
+
+type CloudflareConfig struct {
+	BaseURL  string
+	ZoneID   string
+	APIToken string
+	// ValidityDays of ordered certificate packs; Cloudflare accepts 14, 30, 90
+	// and 365.
+	ValidityDays int
+}
+
+func NewCloudflareProvider(cfg CloudflareConfig) *CloudflareProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.cloudflare.com/client/v4"
+	}
+	if cfg.ValidityDays <= 0 {
+		cfg.ValidityDays = 90
+	}
+	return &CloudflareProvider{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		zoneID:       cfg.ZoneID,
+		apiToken:     cfg.APIToken,
+		validityDays: cfg.ValidityDays,
+		client:       observability.WrapClient(&http.Client{Timeout: 15 * time.Second}),
+	}
+}
+
+func (p *CloudflareProvider) Name() string { return "cloudflare" }
+
+type cloudflareEnvelope struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+type cloudflareResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *CloudflareProvider) SubmitPurge(ctx context.Context, batch domain.PurgeBatch) (string, error) {
+	var body map[string][]string
+	switch batch.Scope {
+	case "url":
+		body = map[string][]string{"files": batch.Targets}
+	case "prefix":
+		body = map[string][]string{"prefixes": batch.Targets}
+	case "tag":
+		body = map[string][]string{"tags": batch.Targets}
+	default:
+		return "", domain.ErrInvalidInput
+	}
+	var out cloudflareResult
+	if err := p.call(ctx, http.MethodPost, "/zones/"+p.zoneID+"/purge_cache", body, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+func (p *CloudflareProvider) PurgeStatus(context.Context, string) (domain.PurgeJobStatus, error) {
+	return domain.PurgeJobStatus{Status: domain.PurgeStatusCompleted}, nil
+}
+
+func (p *CloudflareProvider) OrderCertificate(ctx context.Context, cert domain.Certificate) (string, error) {
+	var out cloudflareResult
+	err := p.call(ctx, http.MethodPost, "/zones/"+p.zoneID+"/ssl/certificate_packs/order", map[string]any{
+		"type":                  "advanced",
+		"hosts":                 []string{cert.Domain},
+		"validation_method":     "txt",
+		"validity_days":         p.validityDays,
+		"certificate_authority": "lets_encrypt",
+	}, &out)
+	if err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+// CertificateOrderStatus reads the certificate pack. A pack is issued once
+// it is active, and its expiry is the latest expires_on of its certificates;
+// the *_timed_out and deleted states are terminal failures.
+func (p *CloudflareProvider) CertificateOrderStatus(ctx context.Context, orderID string) (domain.CertificateOrder, error) {
+	var out struct {
+		Status       string `json:"status"`
+		Certificates []struct {
+			ExpiresOn time.Time `json:"expires_on"`
+		} `json:"certificates"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/zones/"+p.zoneID+"/ssl/certificate_packs/"+url.PathEscape(orderID), nil, &out); err != nil {
+		return domain.CertificateOrder{}, err
+	}
+	switch {
+	case out.Status == "active":
+		order := domain.CertificateOrder{Status: domain.CertificateOrderIssued}
+		for _, c := range out.Certificates {
+			if c.ExpiresOn.After(order.ExpiresAt) {
+				order.ExpiresAt = c.ExpiresOn.UTC()
+			}
+		}
+		if order.ExpiresAt.IsZero() {
+			return domain.CertificateOrder{}, fmt.Errorf("cloudflare certificate pack %s is active without certificates", orderID)
+		}
+		return order, nil
+	case strings.HasSuffix(out.Status, "_timed_out"), out.Status == "deleted":
+		return domain.CertificateOrder{Status: domain.CertificateOrderFailed, Error: "certificate pack " + out.Status}, nil
+	default:
+		return domain.CertificateOrder{Status: domain.CertificateOrderPending}, nil
+	}
+}
+
+func (p *CloudflareProvider) call(ctx context.Context, method, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var out cloudflareEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("cloudflare %s: status %d: %w", path, resp.StatusCode, err)
+	}
+	if !out.Success || resp.StatusCode >= 300 {
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("cloudflare %s: %d %s", path, out.Errors[0].Code, out.Errors[0].Message)
+		}
+		return fmt.Errorf("cloudflare %s: status %d", path, resp.StatusCode)
+	}
+	if result == nil || len(out.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(out.Result, result); err != nil {
+		return fmt.Errorf("cloudflare %s: decode result: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+)
+
+var errMockPurgeRejected = errors.New("mock provider rejected purge")
+
+// MockProvider is an in-process CDN used for local runs and tests. Purge jobs
+// and certificate orders complete once they were polled PollsToComplete
+// times; issued certificates are valid for 90 days.
+type MockProvider struct {
+	PollsToComplete int
+	FailPurges      bool
+
+	mu      sync.Mutex
+	jobs    map[string]int
+	batches []domain.PurgeBatch
+	seq     uint64
+}
+
+func NewMockProvider() *MockProvider {
+	return &MockProvider{PollsToComplete: 1, jobs: map[string]int{}}
+}
+
+func (p *MockProvider) Name() string { return "mock" }
+
+func (p *MockProvider) SubmitPurge(_ context.Context, batch domain.PurgeBatch) (string, error) {
+	if p.FailPurges {
+		return "", errMockPurgeRejected
+	}
+	id := "mock-job-" + strconv.FormatUint(atomic.AddUint64(&p.seq, 1), 10)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.jobs[id] = 0
+	p.batches = append(p.batches, batch)
+	return id, nil
+}
+
+func (p *MockProvider) PurgeStatus(_ context.Context, jobID string) (domain.PurgeJobStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	polls, ok := p.jobs[jobID]
+	if !ok {
+		return domain.PurgeJobStatus{Status: domain.PurgeStatusFailed, Error: "unknown purge job"}, nil
+	}
+	polls++
+	p.jobs[jobID] = polls
+	if polls < p.PollsToComplete {
+		return domain.PurgeJobStatus{Status: domain.PurgeStatusInProgress}, nil
+	}
+	return domain.PurgeJobStatus{Status: domain.PurgeStatusCompleted}, nil
+}
+
+func (p *MockProvider) OrderCertificate(_ context.Context, _ domain.Certificate) (string, error) {
+	id := "mock-cert-" + strconv.FormatUint(atomic.AddUint64(&p.seq, 1), 10)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.jobs[id] = 0
+	return id, nil
+}
+
+// CertificateOrderStatus issues the order once it was polled PollsToComplete
+// times.
+func (p *MockProvider) CertificateOrderStatus(_ context.Context, orderID string) (domain.CertificateOrder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	polls, ok := p.jobs[orderID]
+	if !ok {
+		return domain.CertificateOrder{Status: domain.CertificateOrderFailed, Error: "unknown certificate order"}, nil
+	}
+	polls++
+	p.jobs[orderID] = polls
+	if polls < p.PollsToComplete {
+		return domain.CertificateOrder{Status: domain.CertificateOrderPending}, nil
+	}
+	return domain.CertificateOrder{Status: domain.CertificateOrderIssued, ExpiresAt: time.Now().UTC().Add(90 * 24 * time.Hour)}, nil
+}
+
+// Batches returns the purge batches submitted so far.
+func (p *MockProvider) Batches() []domain.PurgeBatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.PurgeBatch(nil), p.batches...)
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/application"
 	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/contracts"
@@ -60,6 +61,54 @@ func (h *Handler) purge(w http.ResponseWriter, r *http.Request) {
 	writeSuccess(w, http.StatusCreated, "", purge)
 }
 
+func (h *Handler) getPurge(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	purgeID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/purge/"), "/")
+	if purgeID == "" {
+		writeError(w, http.StatusNotFound, "not_found", "not found", requestIDFromContext(r.Context()))
+		return
+	}
+	purge, err := h.service.GetPurge(r.Context(), actor, purgeID)
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusOK, "", purge)
+}
+
+func (h *Handler) signURL(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	var req contracts.SignURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	signed, err := h.service.SignURL(r.Context(), actor, application.SignURLInput{URL: req.URL, TTLSeconds: req.TTLSeconds, Algorithm: req.Algorithm})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusCreated, "", signed)
+}
+
+func (h *Handler) signCookies(w http.ResponseWriter, r *http.Request) {
+	actor := actorFromContext(r.Context())
+	var req contracts.SignCookiesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid json body", requestIDFromContext(r.Context()))
+		return
+	}
+	signed, err := h.service.SignCookies(r.Context(), actor, application.SignCookiesInput{Resource: req.Resource, TTLSeconds: req.TTLSeconds, Algorithm: req.Algorithm})
+	if err != nil {
+		status, code := mapDomainError(err)
+		writeError(w, status, code, err.Error(), requestIDFromContext(r.Context()))
+		return
+	}
+	writeSuccess(w, http.StatusCreated, "", signed)
+}
+
 func (h *Handler) metrics(w http.ResponseWriter, r *http.Request) {
 	data, err := h.service.Metrics(r.Context())
 	if err != nil {
@@ -40,6 +40,10 @@ func mapDomainError(err error) (int, string) {
 		return http.StatusBadRequest, "idempotency_key_required"
 	case domain.ErrIdempotencyConflict:
 		return http.StatusConflict, "idempotency_conflict"
+	case domain.ErrSigningUnavailable:
+		return http.StatusServiceUnavailable, "signing_key_not_configured"
+	case domain.ErrProviderUnavailable:
+		return http.StatusServiceUnavailable, "cdn_provider_unavailable"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
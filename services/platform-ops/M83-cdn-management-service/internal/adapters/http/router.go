@@ -52,6 +52,28 @@ func NewRouter(handler *Handler) http.Handler {
 		}
 		handler.purge(w, r)
 	})))
+	mux.Handle("/purge/", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.getPurge(w, r)
+	})))
+
+	mux.Handle("/signed-urls", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.signURL(w, r)
+	})))
+	mux.Handle("/signed-cookies", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed", requestIDFromContext(r.Context()))
+			return
+		}
+		handler.signCookies(w, r)
+	})))
 
 	return requestIDMiddleware(mux)
 }
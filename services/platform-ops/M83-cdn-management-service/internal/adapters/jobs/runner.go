@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/application"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+)
+
+// Runner drives asynchronous purge processing and the certificate scheduler.
+type Runner struct {
+	service           *application.Service
+	purgeInterval     time.Duration
+	certCheckInterval time.Duration
+}
+
+func NewRunner(service *application.Service, purgeInterval, certCheckInterval time.Duration) *Runner {
+	if purgeInterval <= 0 {
+		purgeInterval = 5 * time.Second
+	}
+	if certCheckInterval <= 0 {
+		certCheckInterval = time.Hour
+	}
+	return &Runner{service: service, purgeInterval: purgeInterval, certCheckInterval: certCheckInterval}
+}
+
+func (r *Runner) Run(ctx context.Context) error {
+	purgeTicker := time.NewTicker(r.purgeInterval)
+	defer purgeTicker.Stop()
+	certTicker := time.NewTicker(r.certCheckInterval)
+	defer certTicker.Stop()
+	r.checkCertificates(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-purgeTicker.C:
+			if err := r.service.ProcessPurges(ctx); err != nil {
+				log.Printf("purge processing failed: %v", err)
+			}
+		case <-certTicker.C:
+			r.checkCertificates(ctx)
+		}
+	}
+}
+
+func (r *Runner) checkCertificates(ctx context.Context) {
+	checks, err := r.service.CheckCertificates(ctx)
+	if err != nil {
+		log.Printf("certificate check failed: %v", err)
+		return
+	}
+	for _, check := range checks {
+		switch check.Action {
+		case domain.CertificateActionWarn:
+			log.Printf("certificate %s for %s expires at %s", check.CertID, check.Domain, check.ExpiresAt.Format(time.RFC3339))
+		case domain.CertificateActionOrdered:
+			log.Printf("certificate %s for %s renewal ordered", check.CertID, check.Domain)
+		case domain.CertificateActionRenewed:
+			log.Printf("certificate %s for %s renewed until %s", check.CertID, check.Domain, check.ExpiresAt.Format(time.RFC3339))
+		case domain.CertificateActionFailed:
+			log.Printf("certificate %s for %s renewal failed: %s", check.CertID, check.Domain, check.Error)
+		}
+	}
+}
@@ -26,6 +26,7 @@ func NewRepositories() *Repositories {
 		ExpiresAt:  time.Now().UTC().Add(90 * 24 * time.Hour),
 		AutoRenew:  true,
 		TLSVersion: "TLS1.3",
+		Status:     domain.CertificateStatusValid,
 	}}
 	return &Repositories{
 		Configs:      &ConfigRepository{rows: []domain.CDNConfig{}},
@@ -83,6 +84,29 @@ func (r *PurgeRepository) Create(_ context.Context, request domain.PurgeRequest)
 	return nil
 }
 
+func (r *PurgeRepository) Get(_ context.Context, purgeID string) (domain.PurgeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rows {
+		if row.PurgeID == purgeID {
+			return row, nil
+		}
+	}
+	return domain.PurgeRequest{}, domain.ErrNotFound
+}
+
+func (r *PurgeRepository) Update(_ context.Context, request domain.PurgeRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, row := range r.rows {
+		if row.PurgeID == request.PurgeID {
+			r.rows[i] = request
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
 func (r *PurgeRepository) List(_ context.Context) ([]domain.PurgeRequest, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -91,6 +115,18 @@ func (r *PurgeRepository) List(_ context.Context) ([]domain.PurgeRequest, error)
 	return out, nil
 }
 
+func (r *PurgeRepository) ListByStatus(_ context.Context, status string) ([]domain.PurgeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []domain.PurgeRequest{}
+	for _, row := range r.rows {
+		if row.Status == status {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
 type MetricsRepository struct {
 	mu       sync.Mutex
 	snapshot domain.Metrics
@@ -122,6 +158,18 @@ func (r *CertificateRepository) List(_ context.Context) ([]domain.Certificate, e
 	return out, nil
 }
 
+func (r *CertificateRepository) Update(_ context.Context, cert domain.Certificate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, row := range r.rows {
+		if row.CertID == cert.CertID {
+			r.rows[i] = cert
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
 type IdempotencyRepository struct {
 	mu   sync.Mutex
 	rows map[string]domain.IdempotencyRecord
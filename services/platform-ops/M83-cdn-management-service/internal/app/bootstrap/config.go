@@ -2,6 +2,9 @@ package bootstrap
 
 import (
 	"bufio"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"strconv"
@@ -15,10 +18,33 @@ type Config struct {
 	HTTPPort       int
 	GRPCPort       int
 	IdempotencyTTL time.Duration
+
+	DefaultProvider   string
+	PurgeBatchSize    int
+	PurgeMaxAttempts  int
+	PurgePollInterval time.Duration
+	PurgePollTimeout  time.Duration
+
+	CertCheckInterval time.Duration
+	CertWarnBefore    time.Duration
+	CertRenewBefore   time.Duration
+
+	SigningHMACKeyID    string
+	SigningHMACSecret   string
+	SigningRSAKeyPairID string
+	SigningRSAKeyPath   string
+
+	CloudflareBaseURL  string
+	CloudflareZoneID   string
+	CloudflareAPIToken string
 }
 
 func LoadConfig(path string) (Config, error) {
-	cfg := Config{ServiceID: "M83-CDN-Management-Service", Version: "0.1.0", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour}
+	cfg := Config{
+		ServiceID: "M83-CDN-Management-Service", Version: "0.1.0", HTTPPort: 8080, GRPCPort: 9090, IdempotencyTTL: 7 * 24 * time.Hour,
+		DefaultProvider: "mock", PurgeBatchSize: 30, PurgeMaxAttempts: 5, PurgePollInterval: 5 * time.Second, PurgePollTimeout: 30 * time.Minute,
+		CertCheckInterval: time.Hour, CertWarnBefore: 30 * 24 * time.Hour, CertRenewBefore: 14 * 24 * time.Hour,
+	}
 	if path != "" {
 		if err := parseConfigFile(path, &cfg); err != nil {
 			return Config{}, err
@@ -28,6 +54,13 @@ func LoadConfig(path string) (Config, error) {
 	cfg.GRPCPort = envInt("GRPC_PORT", cfg.GRPCPort)
 	cfg.Version = envString("SERVICE_VERSION", cfg.Version)
 	cfg.IdempotencyTTL = time.Duration(envInt("IDEMPOTENCY_TTL_HOURS", int(cfg.IdempotencyTTL.Hours()))) * time.Hour
+	cfg.DefaultProvider = envString("CDN_DEFAULT_PROVIDER", cfg.DefaultProvider)
+	cfg.SigningHMACKeyID = envString("CDN_SIGNING_HMAC_KEY_ID", cfg.SigningHMACKeyID)
+	cfg.SigningHMACSecret = envString("CDN_SIGNING_HMAC_SECRET", cfg.SigningHMACSecret)
+	cfg.SigningRSAKeyPairID = envString("CDN_SIGNING_RSA_KEY_PAIR_ID", cfg.SigningRSAKeyPairID)
+	cfg.SigningRSAKeyPath = envString("CDN_SIGNING_RSA_PRIVATE_KEY_PATH", cfg.SigningRSAKeyPath)
+	cfg.CloudflareZoneID = envString("CLOUDFLARE_ZONE_ID", cfg.CloudflareZoneID)
+	cfg.CloudflareAPIToken = envString("CLOUDFLARE_API_TOKEN", cfg.CloudflareAPIToken)
 	return cfg, nil
 }
 
@@ -78,6 +111,48 @@ func parseConfigFile(path string, cfg *Config) error {
 			if v, err := strconv.Atoi(value); err == nil && v > 0 {
 				cfg.IdempotencyTTL = time.Duration(v) * time.Hour
 			}
+		case "cdn.default_provider":
+			if value != "" {
+				cfg.DefaultProvider = value
+			}
+		case "cdn.purge_batch_size":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.PurgeBatchSize = v
+			}
+		case "cdn.purge_max_attempts":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.PurgeMaxAttempts = v
+			}
+		case "cdn.purge_poll_seconds":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.PurgePollInterval = time.Duration(v) * time.Second
+			}
+		case "cdn.purge_poll_timeout_minutes":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.PurgePollTimeout = time.Duration(v) * time.Minute
+			}
+		case "certificates.check_interval_minutes":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.CertCheckInterval = time.Duration(v) * time.Minute
+			}
+		case "certificates.warn_before_days":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.CertWarnBefore = time.Duration(v) * 24 * time.Hour
+			}
+		case "certificates.renew_before_days":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.CertRenewBefore = time.Duration(v) * 24 * time.Hour
+			}
+		case "signing.hmac_key_id":
+			cfg.SigningHMACKeyID = value
+		case "signing.rsa_key_pair_id":
+			cfg.SigningRSAKeyPairID = value
+		case "signing.rsa_private_key_path":
+			cfg.SigningRSAKeyPath = value
+		case "cloudflare.api_base_url":
+			cfg.CloudflareBaseURL = value
+		case "cloudflare.zone_id":
+			cfg.CloudflareZoneID = value
 		}
 	}
 	if err := scanner.Err(); err != nil {
@@ -101,3 +176,28 @@ func envString(name, fallback string) string {
 	}
 	return fallback
 }
+
+// loadRSAPrivateKey reads a PEM encoded PKCS#1 or PKCS#8 RSA key used for
+// CloudFront-style signed URLs.
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read signing key: %w", err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s: no PEM block", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s: not an RSA key", path)
+	}
+	return key, nil
+}
@@ -2,30 +2,66 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	stdhttp "net/http"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/adapters/cdn"
 	httpadapter "github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/adapters/http"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/adapters/jobs"
 	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/application"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/ports"
 )
 
-type Runtime struct{ httpServer *stdhttp.Server }
+type Runtime struct {
+	httpServer *stdhttp.Server
+	jobs       *jobs.Runner
+	telemetry  *observability.Telemetry
+}
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, cfg.Version))
+	if err != nil {
+		return nil, err
+	}
+	signing := application.SigningKeys{HMACKeyID: cfg.SigningHMACKeyID, HMACSecret: []byte(cfg.SigningHMACSecret), RSAKeyPairID: cfg.SigningRSAKeyPairID}
+	if cfg.SigningRSAKeyPath != "" {
+		key, err := loadRSAPrivateKey(cfg.SigningRSAKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		signing.RSAPrivateKey = key
+	}
+	providers := []ports.CDNProvider{cdn.NewMockProvider()}
+	if cfg.CloudflareAPIToken != "" && cfg.CloudflareZoneID != "" {
+		providers = append(providers, cdn.NewCloudflareProvider(cdn.CloudflareConfig{BaseURL: cfg.CloudflareBaseURL, ZoneID: cfg.CloudflareZoneID, APIToken: cfg.CloudflareAPIToken}))
+	}
 	repos := postgres.NewRepositories()
-	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, Version: cfg.Version, IdempotencyTTL: cfg.IdempotencyTTL}, Configs: repos.Configs, Purges: repos.Purges, Metrics: repos.Metrics, Certificates: repos.Certificates, Idempotency: repos.Idempotency})
-	server := &stdhttp.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: httpadapter.NewRouter(httpadapter.NewHandler(svc)), ReadHeaderTimeout: 5 * time.Second}
-	return &Runtime{httpServer: server}, nil
+	svc := application.NewService(application.Dependencies{
+		Config: application.Config{
+			ServiceName: cfg.ServiceID, Version: cfg.Version, IdempotencyTTL: cfg.IdempotencyTTL,
+			DefaultProvider: cfg.DefaultProvider, PurgeBatchSize: cfg.PurgeBatchSize, PurgeMaxAttempts: cfg.PurgeMaxAttempts, PurgePollTimeout: cfg.PurgePollTimeout,
+			CertWarnBefore: cfg.CertWarnBefore, CertRenewBefore: cfg.CertRenewBefore, Signing: signing,
+		},
+		Configs: repos.Configs, Purges: repos.Purges, Metrics: repos.Metrics, Certificates: repos.Certificates, Idempotency: repos.Idempotency,
+		Providers: providers,
+	})
+	server := &stdhttp.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(httpadapter.NewRouter(httpadapter.NewHandler(svc))), ReadHeaderTimeout: 5 * time.Second}
+	return &Runtime{httpServer: server, jobs: jobs.NewRunner(svc, cfg.PurgePollInterval, cfg.CertCheckInterval), telemetry: telemetry}, nil
 }
 
 func (r *Runtime) Run(ctx context.Context) error {
 	errCh := make(chan error, 1)
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go func() { _ = r.jobs.Run(jobsCtx) }()
 	go func() {
 		if err := r.httpServer.ListenAndServe(); err != nil && err != stdhttp.ErrServerClosed {
 			errCh <- err
@@ -40,5 +76,5 @@ func (r *Runtime) Run(ctx context.Context) error {
 	}
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return r.httpServer.Shutdown(shutdownCtx)
+	return errors.Join(r.httpServer.Shutdown(shutdownCtx), r.telemetry.Shutdown(shutdownCtx))
 }
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+)
+
+// CheckCertificates walks every certificate once. AutoRenew certificates
+// within CertRenewBefore of ExpiresAt get a renewal ordered from their
+// provider; later checks poll that order and, once the certificate is
+// issued, record its real expiry and LastRenewedAt. Other certificates
+// within CertWarnBefore, or already expired, are reported with a warn
+// action.
+func (s *Service) CheckCertificates(ctx context.Context) ([]domain.CertificateCheck, error) {
+	certs, err := s.certificates.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.CertificateCheck, 0, len(certs))
+	for _, cert := range certs {
+		now := s.nowFn()
+		remaining := cert.ExpiresAt.Sub(now)
+		check := domain.CertificateCheck{CertID: cert.CertID, Domain: cert.Domain, Action: domain.CertificateActionNone}
+		switch {
+		case cert.RenewalOrderID != "":
+			s.pollCertificateOrder(ctx, &cert, &check, now)
+		case cert.AutoRenew && remaining <= s.cfg.CertRenewBefore:
+			orderID, err := s.orderCertificate(ctx, cert)
+			if err != nil {
+				renewalFailed(&cert, &check, err.Error())
+				break
+			}
+			cert.RenewalOrderID = orderID
+			cert.Status = domain.CertificateStatusRenewing
+			cert.RenewalError = ""
+			check.Action = domain.CertificateActionOrdered
+		case remaining <= 0:
+			cert.Status = domain.CertificateStatusExpired
+			check.Action = domain.CertificateActionWarn
+		case remaining <= s.cfg.CertWarnBefore:
+			cert.Status = domain.CertificateStatusExpiring
+			check.Action = domain.CertificateActionWarn
+		default:
+			cert.Status = domain.CertificateStatusValid
+		}
+		cert.LastCheckedAt = now
+		check.ExpiresAt = cert.ExpiresAt
+		if err := s.certificates.Update(ctx, cert); err != nil {
+			return nil, err
+		}
+		out = append(out, check)
+	}
+	return out, nil
+}
+
+func (s *Service) orderCertificate(ctx context.Context, cert domain.Certificate) (string, error) {
+	provider, err := s.provider(cert.Provider)
+	if err != nil {
+		return "", err
+	}
+	orderID, err := provider.OrderCertificate(ctx, cert)
+	if err == nil && orderID == "" {
+		err = errors.New("provider returned no certificate order id")
+	}
+	return orderID, err
+}
+
+// pollCertificateOrder advances a pending renewal. The certificate keeps its
+// old expiry until the provider reports the new one issued; a poll error
+// leaves the order pending for the next check.
+func (s *Service) pollCertificateOrder(ctx context.Context, cert *domain.Certificate, check *domain.CertificateCheck, now time.Time) {
+	check.Action = domain.CertificateActionPending
+	provider, err := s.provider(cert.Provider)
+	if err != nil {
+		renewalFailed(cert, check, err.Error())
+		return
+	}
+	order, err := provider.CertificateOrderStatus(ctx, cert.RenewalOrderID)
+	if err != nil {
+		cert.RenewalError = err.Error()
+		check.Error = err.Error()
+		return
+	}
+	switch order.Status {
+	case domain.CertificateOrderIssued:
+		if !order.ExpiresAt.After(cert.ExpiresAt) {
+			renewalFailed(cert, check, "provider issued a certificate that does not extend expiry")
+			return
+		}
+		cert.ExpiresAt = order.ExpiresAt
+		cert.LastRenewedAt = now
+		cert.Status = domain.CertificateStatusValid
+		cert.RenewalError = ""
+		cert.RenewalOrderID = ""
+		check.Action = domain.CertificateActionRenewed
+	case domain.CertificateOrderFailed:
+		renewalFailed(cert, check, order.Error)
+	default:
+		cert.Status = domain.CertificateStatusRenewing
+	}
+}
+
+func renewalFailed(cert *domain.Certificate, check *domain.CertificateCheck, reason string) {
+	if reason == "" {
+		reason = "certificate order failed"
+	}
+	cert.Status = domain.CertificateStatusRenewalFailed
+	cert.RenewalError = reason
+	cert.RenewalOrderID = ""
+	check.Action = domain.CertificateActionFailed
+	check.Error = reason
+}
@@ -0,0 +1,182 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/ports"
+)
+
+// ProcessPurges submits queued purge requests to their provider and polls
+// submitted batches. Queued requests are grouped by provider and scope and
+// sent in batches of at most PurgeBatchSize targets, so one provider call
+// covers many URLs, prefixes or tags. Failed submissions are retried on the
+// next run until PurgeMaxAttempts is reached, and submitted batches the
+// provider has not settled within PurgePollTimeout are marked failed.
+func (s *Service) ProcessPurges(ctx context.Context) error {
+	if err := s.submitQueuedPurges(ctx); err != nil {
+		return err
+	}
+	if err := s.pollSubmittedPurges(ctx); err != nil {
+		return err
+	}
+	return s.refreshPendingPurges(ctx)
+}
+
+func (s *Service) submitQueuedPurges(ctx context.Context) error {
+	queued, err := s.purges.ListByStatus(ctx, domain.PurgeStatusQueued)
+	if err != nil {
+		return err
+	}
+	groups := map[string][]domain.PurgeRequest{}
+	var order []string
+	for _, row := range queued {
+		key := row.Provider + "|" + row.Scope
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+	for _, key := range order {
+		rows := groups[key]
+		for start := 0; start < len(rows); start += s.cfg.PurgeBatchSize {
+			batch := rows[start:min(start+s.cfg.PurgeBatchSize, len(rows))]
+			if err := s.submitBatch(ctx, batch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) submitBatch(ctx context.Context, rows []domain.PurgeRequest) error {
+	now := s.nowFn()
+	batch := domain.PurgeBatch{BatchID: newID("batch", now), Scope: rows[0].Scope}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if !seen[row.Target] {
+			seen[row.Target] = true
+			batch.Targets = append(batch.Targets, row.Target)
+		}
+	}
+
+	var jobID string
+	provider, err := s.provider(rows[0].Provider)
+	if err == nil {
+		jobID, err = provider.SubmitPurge(ctx, batch)
+	}
+	for _, row := range rows {
+		row.Attempts++
+		row.UpdatedAt = now
+		if err != nil {
+			row.Error = err.Error()
+			if row.Attempts >= s.cfg.PurgeMaxAttempts || errors.Is(err, domain.ErrProviderUnavailable) {
+				row.Status = domain.PurgeStatusFailed
+			}
+		} else {
+			row.Status = domain.PurgeStatusInProgress
+			row.SubmittedAt = now
+			row.BatchID = batch.BatchID
+			row.ProviderJobID = jobID
+			row.Error = ""
+		}
+		if err := s.purges.Update(ctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) pollSubmittedPurges(ctx context.Context) error {
+	submitted, err := s.purges.ListByStatus(ctx, domain.PurgeStatusInProgress)
+	if err != nil {
+		return err
+	}
+	jobs := map[string][]domain.PurgeRequest{}
+	var order []string
+	for _, row := range submitted {
+		key := row.Provider + "|" + row.ProviderJobID
+		if _, ok := jobs[key]; !ok {
+			order = append(order, key)
+		}
+		jobs[key] = append(jobs[key], row)
+	}
+	for _, key := range order {
+		rows := jobs[key]
+		status := domain.PurgeJobStatus{Status: domain.PurgeStatusInProgress}
+		provider, err := s.provider(rows[0].Provider)
+		if err == nil {
+			status, err = provider.PurgeStatus(ctx, rows[0].ProviderJobID)
+		}
+		now := s.nowFn()
+		if err != nil || status.Status == domain.PurgeStatusInProgress {
+			if now.Sub(submittedAt(rows[0])) < s.cfg.PurgePollTimeout {
+				continue
+			}
+			status = domain.PurgeJobStatus{Status: domain.PurgeStatusFailed, Error: fmt.Sprintf("provider did not complete purge within %s", s.cfg.PurgePollTimeout)}
+			if err != nil {
+				status.Error += ": " + err.Error()
+			}
+		}
+		for _, row := range rows {
+			row.Status = status.Status
+			row.Error = status.Error
+			row.UpdatedAt = now
+			if status.Status == domain.PurgeStatusCompleted {
+				row.CompletedAt = now
+				row.CompletedIn = int(now.Sub(row.CreatedAt).Seconds())
+			}
+			if err := s.purges.Update(ctx, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// submittedAt falls back to UpdatedAt for purges submitted before
+// SubmittedAt was recorded.
+func submittedAt(row domain.PurgeRequest) time.Time {
+	if row.SubmittedAt.IsZero() {
+		return row.UpdatedAt
+	}
+	return row.SubmittedAt
+}
+
+func (s *Service) refreshPendingPurges(ctx context.Context) error {
+	queued, err := s.purges.ListByStatus(ctx, domain.PurgeStatusQueued)
+	if err != nil {
+		return err
+	}
+	submitted, err := s.purges.ListByStatus(ctx, domain.PurgeStatusInProgress)
+	if err != nil {
+		return err
+	}
+	metrics, _ := s.metrics.Snapshot(ctx)
+	metrics.PendingPurges = len(queued) + len(submitted)
+	return s.metrics.SetSnapshot(ctx, metrics)
+}
+
+// activeProvider names the provider of the latest CDN config when an adapter
+// is registered for it and the default provider otherwise.
+func (s *Service) activeProvider(ctx context.Context) string {
+	if latest, err := s.configs.Latest(ctx); err == nil {
+		if _, ok := s.providers[latest.Provider]; ok {
+			return latest.Provider
+		}
+	}
+	return s.cfg.DefaultProvider
+}
+
+func (s *Service) provider(name string) (ports.CDNProvider, error) {
+	if provider, ok := s.providers[name]; ok {
+		return provider, nil
+	}
+	if provider, ok := s.providers[s.cfg.DefaultProvider]; ok {
+		return provider, nil
+	}
+	return nil, domain.ErrProviderUnavailable
+}
@@ -20,6 +20,7 @@ type Service struct {
 	metrics      ports.MetricsRepository
 	certificates ports.CertificateRepository
 	idempotency  ports.IdempotencyRepository
+	providers    map[string]ports.CDNProvider
 	nowFn        func() time.Time
 }
 
@@ -30,6 +31,7 @@ type Dependencies struct {
 	Metrics      ports.MetricsRepository
 	Certificates ports.CertificateRepository
 	Idempotency  ports.IdempotencyRepository
+	Providers    []ports.CDNProvider
 }
 
 var idCounter uint64
@@ -42,11 +44,30 @@ func NewService(deps Dependencies) *Service {
 		metrics:      deps.Metrics,
 		certificates: deps.Certificates,
 		idempotency:  deps.Idempotency,
-		nowFn:        time.Now().UTC,
+		providers:    map[string]ports.CDNProvider{},
+		nowFn:        func() time.Time { return time.Now().UTC() },
 	}
 	if s.cfg.IdempotencyTTL == 0 {
 		s.cfg.IdempotencyTTL = 7 * 24 * time.Hour
 	}
+	if s.cfg.PurgeBatchSize <= 0 {
+		s.cfg.PurgeBatchSize = 30
+	}
+	if s.cfg.PurgeMaxAttempts <= 0 {
+		s.cfg.PurgeMaxAttempts = 5
+	}
+	if s.cfg.PurgePollTimeout <= 0 {
+		s.cfg.PurgePollTimeout = 30 * time.Minute
+	}
+	if s.cfg.CertWarnBefore <= 0 {
+		s.cfg.CertWarnBefore = 30 * 24 * time.Hour
+	}
+	if s.cfg.CertRenewBefore <= 0 {
+		s.cfg.CertRenewBefore = 14 * 24 * time.Hour
+	}
+	for _, provider := range deps.Providers {
+		s.providers[provider.Name()] = provider
+	}
 	return s
 }
 
@@ -133,23 +154,33 @@ func (s *Service) Purge(ctx context.Context, actor Actor, input PurgeInput) (dom
 	now := s.nowFn()
 	purge := domain.PurgeRequest{
 		PurgeID:     newID("purge", now),
+		Provider:    s.activeProvider(ctx),
 		Scope:       scope,
 		Target:      target,
-		Status:      "completed",
-		CompletedIn: 15,
+		Status:      domain.PurgeStatusQueued,
 		RequestedBy: actor.SubjectID,
 		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 	if err := s.purges.Create(ctx, purge); err != nil {
 		return domain.PurgeRequest{}, err
 	}
-	metrics, _ := s.metrics.Snapshot(ctx)
-	metrics.PendingPurges = 0
-	_ = s.metrics.SetSnapshot(ctx, metrics)
+	_ = s.refreshPendingPurges(ctx)
 	_ = s.completeIdempotent(ctx, actor.IdempotencyKey, requestHash, purge)
 	return purge, nil
 }
 
+func (s *Service) GetPurge(ctx context.Context, actor Actor, purgeID string) (domain.PurgeRequest, error) {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.PurgeRequest{}, domain.ErrUnauthorized
+	}
+	purgeID = strings.TrimSpace(purgeID)
+	if purgeID == "" {
+		return domain.PurgeRequest{}, domain.ErrInvalidInput
+	}
+	return s.purges.Get(ctx, purgeID)
+}
+
 func (s *Service) Metrics(ctx context.Context) (map[string]any, error) {
 	metrics, err := s.metrics.Snapshot(ctx)
 	if err != nil {
@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+)
+
+// HMAC-signed URLs carry expires, key_id and signature query parameters; the
+// signature is HMAC-SHA256 over host, escaped path and the sorted query
+// without the signature. RSA-signed URLs and cookies follow the CloudFront
+// canned and custom policy formats.
+const (
+	hmacCookiePolicy    = "CDN-Policy"
+	hmacCookieSignature = "CDN-Signature"
+	hmacCookieKeyID     = "CDN-Key-Id"
+)
+
+// SignURL issues a signed URL for one object. The TTL is capped by the
+// signed_url_ttl_seconds of the active CDN config.
+func (s *Service) SignURL(ctx context.Context, actor Actor, input SignURLInput) (domain.SignedURL, error) {
+	if err := authorizeSigner(actor); err != nil {
+		return domain.SignedURL{}, err
+	}
+	u, err := parseSignableURL(input.URL)
+	if err != nil {
+		return domain.SignedURL{}, err
+	}
+	algorithm, err := s.signingAlgorithm(input.Algorithm)
+	if err != nil {
+		return domain.SignedURL{}, err
+	}
+	expiresAt := s.nowFn().Add(s.signedTTL(ctx, input.TTLSeconds)).Truncate(time.Second)
+
+	out := domain.SignedURL{Algorithm: algorithm, ExpiresAt: expiresAt}
+	q := u.Query()
+	switch algorithm {
+	case domain.SigningHMAC:
+		q.Del("signature")
+		q.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+		q.Set("key_id", s.cfg.Signing.HMACKeyID)
+		u.RawQuery = q.Encode()
+		u.RawQuery += "&signature=" + s.hmacSign(u.Host+u.EscapedPath()+"?"+u.RawQuery)
+		out.KeyID = s.cfg.Signing.HMACKeyID
+	case domain.SigningRSA:
+		policy := cloudFrontPolicy(u.String(), expiresAt)
+		signature, err := s.rsaSign(policy)
+		if err != nil {
+			return domain.SignedURL{}, err
+		}
+		q.Set("Expires", strconv.FormatInt(expiresAt.Unix(), 10))
+		q.Set("Signature", signature)
+		q.Set("Key-Pair-Id", s.cfg.Signing.RSAKeyPairID)
+		u.RawQuery = q.Encode()
+		out.KeyID = s.cfg.Signing.RSAKeyPairID
+	}
+	out.URL = u.String()
+	return out, nil
+}
+
+// SignCookies issues cookies granting access to every object under resource,
+// which may end in "*".
+func (s *Service) SignCookies(ctx context.Context, actor Actor, input SignCookiesInput) (domain.SignedCookies, error) {
+	if err := authorizeSigner(actor); err != nil {
+		return domain.SignedCookies{}, err
+	}
+	u, err := parseSignableURL(input.Resource)
+	if err != nil {
+		return domain.SignedCookies{}, err
+	}
+	algorithm, err := s.signingAlgorithm(input.Algorithm)
+	if err != nil {
+		return domain.SignedCookies{}, err
+	}
+	expiresAt := s.nowFn().Add(s.signedTTL(ctx, input.TTLSeconds)).Truncate(time.Second)
+	resource := u.String()
+	policy := cloudFrontPolicy(resource, expiresAt)
+	encodedPolicy := cloudFrontEncode([]byte(policy))
+
+	out := domain.SignedCookies{Resource: resource, Algorithm: algorithm, ExpiresAt: expiresAt}
+	var values [][2]string
+	switch algorithm {
+	case domain.SigningHMAC:
+		out.KeyID = s.cfg.Signing.HMACKeyID
+		values = [][2]string{
+			{hmacCookiePolicy, encodedPolicy},
+			{hmacCookieSignature, s.hmacSign(encodedPolicy)},
+			{hmacCookieKeyID, out.KeyID},
+		}
+	case domain.SigningRSA:
+		signature, err := s.rsaSign(policy)
+		if err != nil {
+			return domain.SignedCookies{}, err
+		}
+		out.KeyID = s.cfg.Signing.RSAKeyPairID
+		values = [][2]string{
+			{"CloudFront-Policy", encodedPolicy},
+			{"CloudFront-Signature", signature},
+			{"CloudFront-Key-Pair-Id", out.KeyID},
+		}
+	}
+	path := u.Path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[:i+1]
+	}
+	for _, v := range values {
+		out.Cookies = append(out.Cookies, domain.SignedCookie{
+			Name: v[0], Value: v[1], Domain: u.Hostname(), Path: path, ExpiresAt: expiresAt, Secure: true, HTTPOnly: true,
+		})
+	}
+	return out, nil
+}
+
+// VerifySignedURL checks an HMAC-signed URL issued by SignURL and rejects
+// tampered and expired URLs.
+func (s *Service) VerifySignedURL(rawURL string) error {
+	if len(s.cfg.Signing.HMACSecret) == 0 {
+		return domain.ErrSigningUnavailable
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	q := u.Query()
+	signature := q.Get("signature")
+	expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if signature == "" || err != nil || q.Get("key_id") != s.cfg.Signing.HMACKeyID {
+		return domain.ErrUnauthorized
+	}
+	q.Del("signature")
+	expected := s.hmacSign(u.Host + u.EscapedPath() + "?" + q.Encode())
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return domain.ErrUnauthorized
+	}
+	if !s.nowFn().Before(time.Unix(expires, 0)) {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
+
+func (s *Service) signingAlgorithm(requested string) (string, error) {
+	hmacReady := len(s.cfg.Signing.HMACSecret) > 0 && s.cfg.Signing.HMACKeyID != ""
+	rsaReady := s.cfg.Signing.RSAPrivateKey != nil && s.cfg.Signing.RSAKeyPairID != ""
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "":
+		if hmacReady {
+			return domain.SigningHMAC, nil
+		}
+		if rsaReady {
+			return domain.SigningRSA, nil
+		}
+	case domain.SigningHMAC:
+		if hmacReady {
+			return domain.SigningHMAC, nil
+		}
+	case domain.SigningRSA:
+		if rsaReady {
+			return domain.SigningRSA, nil
+		}
+	default:
+		return "", domain.ErrInvalidInput
+	}
+	return "", domain.ErrSigningUnavailable
+}
+
+// signedTTL returns the requested TTL capped by the active config.
+func (s *Service) signedTTL(ctx context.Context, requested int) time.Duration {
+	limit := 3600
+	if latest, err := s.configs.Latest(ctx); err == nil && latest.SignedURLTTL > 0 {
+		limit = latest.SignedURLTTL
+	}
+	if requested <= 0 || requested > limit {
+		requested = limit
+	}
+	return time.Duration(requested) * time.Second
+}
+
+func (s *Service) hmacSign(payload string) string {
+	mac := hmac.New(sha256.New, s.cfg.Signing.HMACSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// rsaSign signs policy with SHA-1 RSA as CloudFront requires.
+func (s *Service) rsaSign(policy string) (string, error) {
+	digest := sha1.Sum([]byte(policy))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.cfg.Signing.RSAPrivateKey, crypto.SHA1, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return cloudFrontEncode(signature), nil
+}
+
+func cloudFrontPolicy(resource string, expiresAt time.Time) string {
+	return `{"Statement":[{"Resource":"` + strings.ReplaceAll(resource, `"`, `%22`) + `","Condition":{"DateLessThan":{"AWS:EpochTime":` + strconv.FormatInt(expiresAt.Unix(), 10) + `}}}]}`
+}
+
+// cloudFrontEncode is base64 with the URL-safe substitutions CloudFront
+// expects.
+func cloudFrontEncode(raw []byte) string {
+	return strings.NewReplacer("+", "-", "=", "_", "/", "~").Replace(base64.StdEncoding.EncodeToString(raw))
+}
+
+func parseSignableURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	return u, nil
+}
+
+func authorizeSigner(actor Actor) error {
+	if strings.TrimSpace(actor.SubjectID) == "" {
+		return domain.ErrUnauthorized
+	}
+	if !isAdmin(actor.Role) && strings.ToLower(strings.TrimSpace(actor.Role)) != "service" {
+		return domain.ErrForbidden
+	}
+	return nil
+}
@@ -1,11 +1,34 @@
 package application
 
-import "time"
+import (
+	"crypto/rsa"
+	"time"
+)
 
 type Config struct {
 	ServiceName    string
 	Version        string
 	IdempotencyTTL time.Duration
+	// DefaultProvider executes purges and renewals when the active CDN config
+	// or certificate names a provider without a registered adapter.
+	DefaultProvider  string
+	PurgeBatchSize   int
+	PurgeMaxAttempts int
+	// PurgePollTimeout is how long a submitted purge may stay in progress
+	// at its provider before it is marked failed.
+	PurgePollTimeout time.Duration
+	CertWarnBefore   time.Duration
+	CertRenewBefore  time.Duration
+	Signing          SigningKeys
+}
+
+// SigningKeys holds the key material for signed URLs and cookies. Either
+// scheme may be left unconfigured.
+type SigningKeys struct {
+	HMACKeyID     string
+	HMACSecret    []byte
+	RSAKeyPairID  string
+	RSAPrivateKey *rsa.PrivateKey
 }
 
 type Actor struct {
@@ -26,3 +49,15 @@ type PurgeInput struct {
 	Scope  string `json:"scope"`
 	Target string `json:"target"`
 }
+
+type SignURLInput struct {
+	URL        string `json:"url"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	Algorithm  string `json:"algorithm,omitempty"`
+}
+
+type SignCookiesInput struct {
+	Resource   string `json:"resource"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	Algorithm  string `json:"algorithm,omitempty"`
+}
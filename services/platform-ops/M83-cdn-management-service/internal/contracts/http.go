@@ -31,3 +31,15 @@ type PurgeRequest struct {
 	Scope  string `json:"scope"`
 	Target string `json:"target"`
 }
+
+type SignURLRequest struct {
+	URL        string `json:"url"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	Algorithm  string `json:"algorithm,omitempty"`
+}
+
+type SignCookiesRequest struct {
+	Resource   string `json:"resource"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	Algorithm  string `json:"algorithm,omitempty"`
+}
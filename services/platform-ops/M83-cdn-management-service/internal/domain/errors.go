@@ -10,4 +10,6 @@ var (
 	ErrConflict            = errors.New("conflict")
 	ErrIdempotencyRequired = errors.New("idempotency_key_required")
 	ErrIdempotencyConflict = errors.New("idempotency_conflict")
+	ErrSigningUnavailable  = errors.New("signing_key_not_configured")
+	ErrProviderUnavailable = errors.New("cdn_provider_unavailable")
 )
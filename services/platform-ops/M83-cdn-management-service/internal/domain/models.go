@@ -16,16 +16,55 @@ type CDNConfig struct {
 	UpdatedAt    time.Time         `json:"updated_at"`
 }
 
+const (
+	PurgeStatusQueued     = "queued"
+	PurgeStatusInProgress = "in_progress"
+	PurgeStatusCompleted  = "completed"
+	PurgeStatusFailed     = "failed"
+)
+
+// PurgeRequest is one requested invalidation. Queued requests of the same
+// provider and scope are submitted together as a batch; BatchID and
+// ProviderJobID identify that submission.
 type PurgeRequest struct {
-	PurgeID     string    `json:"purge_id"`
-	Scope       string    `json:"scope"`
-	Target      string    `json:"target"`
-	Status      string    `json:"status"`
-	CompletedIn int       `json:"completed_in_seconds"`
-	RequestedBy string    `json:"requested_by"`
-	CreatedAt   time.Time `json:"created_at"`
+	PurgeID       string    `json:"purge_id"`
+	Provider      string    `json:"provider"`
+	Scope         string    `json:"scope"`
+	Target        string    `json:"target"`
+	Status        string    `json:"status"`
+	BatchID       string    `json:"batch_id,omitempty"`
+	ProviderJobID string    `json:"provider_job_id,omitempty"`
+	Attempts      int       `json:"attempts"`
+	Error         string    `json:"error,omitempty"`
+	CompletedIn   int       `json:"completed_in_seconds"`
+	RequestedBy   string    `json:"requested_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	SubmittedAt   time.Time `json:"submitted_at,omitempty"`
+	CompletedAt   time.Time `json:"completed_at,omitempty"`
+}
+
+// PurgeBatch is the unit submitted to a CDN provider.
+type PurgeBatch struct {
+	BatchID string
+	Scope   string
+	Targets []string
+}
+
+// PurgeJobStatus is the provider-side state of a submitted batch.
+type PurgeJobStatus struct {
+	Status string
+	Error  string
 }
 
+const (
+	CertificateStatusValid         = "valid"
+	CertificateStatusExpiring      = "expiring"
+	CertificateStatusExpired       = "expired"
+	CertificateStatusRenewalFailed = "renewal_failed"
+	CertificateStatusRenewing      = "renewing"
+)
+
 type Certificate struct {
 	CertID        string    `json:"cert_id"`
 	Provider      string    `json:"provider"`
@@ -33,7 +72,77 @@ type Certificate struct {
 	ExpiresAt     time.Time `json:"expires_at"`
 	AutoRenew     bool      `json:"auto_renew"`
 	TLSVersion    string    `json:"tls_version"`
+	Status        string    `json:"status,omitempty"`
 	LastRenewedAt time.Time `json:"last_renewed_at,omitempty"`
+	LastCheckedAt time.Time `json:"last_checked_at,omitempty"`
+	RenewalError  string    `json:"renewal_error,omitempty"`
+	// RenewalOrderID is the provider order of a renewal still being issued.
+	RenewalOrderID string `json:"renewal_order_id,omitempty"`
+}
+
+const (
+	CertificateOrderPending = "pending"
+	CertificateOrderIssued  = "issued"
+	CertificateOrderFailed  = "failed"
+)
+
+// CertificateOrder is the provider-side state of a renewal order. ExpiresAt
+// is the issued certificate's expiry and is only set once Issued.
+type CertificateOrder struct {
+	Status    string
+	ExpiresAt time.Time
+	Error     string
+}
+
+// CertificateCheck reports what the certificate scheduler did with one
+// certificate.
+type CertificateCheck struct {
+	CertID    string    `json:"cert_id"`
+	Domain    string    `json:"domain"`
+	Action    string    `json:"action"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+const (
+	CertificateActionNone    = "none"
+	CertificateActionWarn    = "warn"
+	CertificateActionRenewed = "renewed"
+	CertificateActionFailed  = "renewal_failed"
+	CertificateActionOrdered = "renewal_ordered"
+	CertificateActionPending = "renewal_pending"
+)
+
+const (
+	SigningHMAC = "hmac"
+	SigningRSA  = "rsa"
+)
+
+// SignedURL is a time-limited URL for a single object.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	Algorithm string    `json:"algorithm"`
+	KeyID     string    `json:"key_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SignedCookie grants access to every object under Resource until ExpiresAt.
+type SignedCookie struct {
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	Domain    string    `json:"domain"`
+	Path      string    `json:"path"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Secure    bool      `json:"secure"`
+	HTTPOnly  bool      `json:"http_only"`
+}
+
+type SignedCookies struct {
+	Resource  string         `json:"resource"`
+	Algorithm string         `json:"algorithm"`
+	KeyID     string         `json:"key_id"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	Cookies   []SignedCookie `json:"cookies"`
 }
 
 type Metrics struct {
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+)
+
+// CDNProvider executes purges and certificate renewals against one CDN.
+// SubmitPurge returns a provider job ID that PurgeStatus polls until the
+// batch completed or failed; OrderCertificate likewise returns an order ID
+// that CertificateOrderStatus polls until the certificate is issued.
+type CDNProvider interface {
+	Name() string
+	SubmitPurge(ctx context.Context, batch domain.PurgeBatch) (string, error)
+	PurgeStatus(ctx context.Context, jobID string) (domain.PurgeJobStatus, error)
+	OrderCertificate(ctx context.Context, cert domain.Certificate) (string, error)
+	CertificateOrderStatus(ctx context.Context, orderID string) (domain.CertificateOrder, error)
+}
@@ -15,7 +15,10 @@ type ConfigRepository interface {
 
 type PurgeRepository interface {
 	Create(ctx context.Context, request domain.PurgeRequest) error
+	Get(ctx context.Context, purgeID string) (domain.PurgeRequest, error)
+	Update(ctx context.Context, request domain.PurgeRequest) error
 	List(ctx context.Context) ([]domain.PurgeRequest, error)
+	ListByStatus(ctx context.Context, status string) ([]domain.PurgeRequest, error)
 }
 
 type MetricsRepository interface {
@@ -25,6 +28,7 @@ type MetricsRepository interface {
 
 type CertificateRepository interface {
 	List(ctx context.Context) ([]domain.Certificate, error)
+	Update(ctx context.Context, cert domain.Certificate) error
 }
 
 type IdempotencyRepository interface {
@@ -2,10 +2,19 @@ package unit
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/adapters/cdn"
 	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/application"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/domain"
+	"github.com/viralforge/mesh/services/platform-ops/M83-cdn-management-service/internal/ports"
 )
 
 func newService() *application.Service {
@@ -43,3 +52,205 @@ func TestPurgeRequiresAdmin(t *testing.T) {
 		t.Fatalf("expected purge to reject non-admin actor")
 	}
 }
+
+func newServiceWithProvider(repos *postgres.Repositories, provider *cdn.MockProvider) *application.Service {
+	return application.NewService(application.Dependencies{
+		Config: application.Config{
+			DefaultProvider: provider.Name(),
+			Signing:         application.SigningKeys{HMACKeyID: "k1", HMACSecret: []byte("test-secret")},
+		},
+		Configs:      repos.Configs,
+		Purges:       repos.Purges,
+		Metrics:      repos.Metrics,
+		Certificates: repos.Certificates,
+		Idempotency:  repos.Idempotency,
+		Providers:    []ports.CDNProvider{provider},
+	})
+}
+
+func TestPurgesAreBatchedAndPolledToCompletion(t *testing.T) {
+	ctx := context.Background()
+	provider := cdn.NewMockProvider()
+	provider.PollsToComplete = 2
+	svc := newServiceWithProvider(postgres.NewRepositories(), provider)
+	var ids []string
+	for i, target := range []string{"https://cdn.example.com/a.jpg", "https://cdn.example.com/b.jpg", "https://cdn.example.com/a.jpg"} {
+		actor := application.Actor{SubjectID: "ops-1", Role: "ops_admin", IdempotencyKey: "idem-purge-" + string(rune('a'+i))}
+		purge, err := svc.Purge(ctx, actor, application.PurgeInput{Scope: "url", Target: target})
+		if err != nil {
+			t.Fatalf("purge: %v", err)
+		}
+		if purge.Status != domain.PurgeStatusQueued {
+			t.Fatalf("expected queued purge, got %s", purge.Status)
+		}
+		ids = append(ids, purge.PurgeID)
+	}
+	actor := application.Actor{SubjectID: "ops-1", Role: "ops_admin"}
+
+	if err := svc.ProcessPurges(ctx); err != nil {
+		t.Fatalf("process purges: %v", err)
+	}
+	batches := provider.Batches()
+	if len(batches) != 1 || len(batches[0].Targets) != 2 {
+		t.Fatalf("expected one deduplicated batch, got %+v", batches)
+	}
+	purge, err := svc.GetPurge(ctx, actor, ids[0])
+	if err != nil || purge.Status != domain.PurgeStatusInProgress || purge.ProviderJobID == "" {
+		t.Fatalf("expected in_progress purge with job id, got %+v err=%v", purge, err)
+	}
+
+	if err := svc.ProcessPurges(ctx); err != nil {
+		t.Fatalf("process purges: %v", err)
+	}
+	for _, id := range ids {
+		purge, err := svc.GetPurge(ctx, actor, id)
+		if err != nil || purge.Status != domain.PurgeStatusCompleted || purge.CompletedAt.IsZero() {
+			t.Fatalf("expected completed purge, got %+v err=%v", purge, err)
+		}
+	}
+}
+
+func TestSubmittedPurgeFailsAfterPollTimeout(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	provider := cdn.NewMockProvider()
+	provider.PollsToComplete = 1000
+	svc := application.NewService(application.Dependencies{
+		Config:       application.Config{DefaultProvider: provider.Name(), PurgePollTimeout: time.Millisecond},
+		Configs:      repos.Configs,
+		Purges:       repos.Purges,
+		Metrics:      repos.Metrics,
+		Certificates: repos.Certificates,
+		Idempotency:  repos.Idempotency,
+		Providers:    []ports.CDNProvider{provider},
+	})
+	actor := application.Actor{SubjectID: "ops-1", Role: "ops_admin", IdempotencyKey: "idem-purge-stuck"}
+	purge, err := svc.Purge(ctx, actor, application.PurgeInput{Scope: "url", Target: "https://cdn.example.com/stuck.jpg"})
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if err := svc.ProcessPurges(ctx); err != nil {
+		t.Fatalf("process purges: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := svc.ProcessPurges(ctx); err != nil {
+		t.Fatalf("process purges: %v", err)
+	}
+	got, err := svc.GetPurge(ctx, actor, purge.PurgeID)
+	if err != nil || got.Status != domain.PurgeStatusFailed || got.Error == "" {
+		t.Fatalf("expected purge failed after poll timeout, got %+v err=%v", got, err)
+	}
+}
+
+func TestCheckCertificatesRenewsExpiringAutoRenew(t *testing.T) {
+	ctx := context.Background()
+	repos := postgres.NewRepositories()
+	svc := newServiceWithProvider(repos, cdn.NewMockProvider())
+	certs, err := repos.Certificates.List(ctx)
+	if err != nil || len(certs) == 0 {
+		t.Fatalf("list certificates: %v", err)
+	}
+	cert := certs[0]
+	cert.ExpiresAt = time.Now().UTC().Add(3 * 24 * time.Hour)
+	if err := repos.Certificates.Update(ctx, cert); err != nil {
+		t.Fatalf("update certificate: %v", err)
+	}
+
+	checks, err := svc.CheckCertificates(ctx)
+	if err != nil {
+		t.Fatalf("check certificates: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Action != domain.CertificateActionOrdered {
+		t.Fatalf("expected a renewal order, got %+v", checks)
+	}
+	certs, _ = repos.Certificates.List(ctx)
+	if certs[0].Status != domain.CertificateStatusRenewing || !certs[0].ExpiresAt.Equal(cert.ExpiresAt) || !certs[0].LastRenewedAt.IsZero() {
+		t.Fatalf("expected the certificate to stay unrenewed until issued, got %+v", certs[0])
+	}
+	checks, err = svc.CheckCertificates(ctx)
+	if err != nil || len(checks) != 1 || checks[0].Action != domain.CertificateActionRenewed {
+		t.Fatalf("expected renewal once the order was issued, got %+v err=%v", checks, err)
+	}
+	certs, _ = repos.Certificates.List(ctx)
+	if certs[0].LastRenewedAt.IsZero() || !certs[0].ExpiresAt.After(cert.ExpiresAt) {
+		t.Fatalf("expected renewed certificate, got %+v", certs[0])
+	}
+}
+
+func TestCloudflareRenewalWaitsForTheIssuedPack(t *testing.T) {
+	ctx := context.Background()
+	issuedExpiry := time.Date(2027, 1, 15, 12, 0, 0, 0, time.UTC)
+	var status atomic.Value
+	status.Store("pending_validation")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/zones/z1/ssl/certificate_packs/order":
+			_, _ = w.Write([]byte(`{"success":true,"result":{"id":"pack-1","status":"initializing"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/zones/z1/ssl/certificate_packs/pack-1":
+			_, _ = fmt.Fprintf(w, `{"success":true,"result":{"id":"pack-1","status":%q,"certificates":[{"expires_on":%q}]}}`, status.Load(), issuedExpiry.Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":7003,"message":"not found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	repos := postgres.NewRepositories()
+	provider := cdn.NewCloudflareProvider(cdn.CloudflareConfig{BaseURL: server.URL, ZoneID: "z1", APIToken: "token"})
+	svc := application.NewService(application.Dependencies{
+		Config:       application.Config{DefaultProvider: provider.Name()},
+		Configs:      repos.Configs,
+		Purges:       repos.Purges,
+		Metrics:      repos.Metrics,
+		Certificates: repos.Certificates,
+		Idempotency:  repos.Idempotency,
+		Providers:    []ports.CDNProvider{provider},
+	})
+	certs, _ := repos.Certificates.List(ctx)
+	cert := certs[0]
+	cert.Provider = provider.Name()
+	cert.ExpiresAt = time.Now().UTC().Add(3 * 24 * time.Hour)
+	_ = repos.Certificates.Update(ctx, cert)
+
+	for i, want := range []string{domain.CertificateActionOrdered, domain.CertificateActionPending} {
+		checks, err := svc.CheckCertificates(ctx)
+		if err != nil || checks[0].Action != want {
+			t.Fatalf("check %d: expected %s, got %+v err=%v", i, want, checks, err)
+		}
+	}
+	certs, _ = repos.Certificates.List(ctx)
+	if certs[0].Status != domain.CertificateStatusRenewing || !certs[0].ExpiresAt.Equal(cert.ExpiresAt) {
+		t.Fatalf("expected a pending pack to leave the expiry alone, got %+v", certs[0])
+	}
+
+	status.Store("active")
+	checks, err := svc.CheckCertificates(ctx)
+	if err != nil || checks[0].Action != domain.CertificateActionRenewed {
+		t.Fatalf("expected renewal once the pack is active, got %+v err=%v", checks, err)
+	}
+	certs, _ = repos.Certificates.List(ctx)
+	if !certs[0].ExpiresAt.Equal(issuedExpiry) || certs[0].RenewalOrderID != "" {
+		t.Fatalf("expected the issued expiry to be stored, got %+v", certs[0])
+	}
+}
+
+func TestSignURLVerifiesAndRejectsTampering(t *testing.T) {
+	svc := newServiceWithProvider(postgres.NewRepositories(), cdn.NewMockProvider())
+	signed, err := svc.SignURL(context.Background(), application.Actor{SubjectID: "m52", Role: "service"}, application.SignURLInput{URL: "https://cdn.example.com/videos/a.mp4?quality=hd", TTLSeconds: 60})
+	if err != nil {
+		t.Fatalf("sign url: %v", err)
+	}
+	if signed.Algorithm != domain.SigningHMAC || signed.KeyID != "k1" {
+		t.Fatalf("unexpected signed url: %+v", signed)
+	}
+	if err := svc.VerifySignedURL(signed.URL); err != nil {
+		t.Fatalf("verify signed url: %v", err)
+	}
+	tampered := strings.Replace(signed.URL, "a.mp4", "b.mp4", 1)
+	if err := svc.VerifySignedURL(tampered); err != domain.ErrUnauthorized {
+		t.Fatalf("expected tampered url to be rejected, got %v", err)
+	}
+	if _, err := svc.SignURL(context.Background(), application.Actor{SubjectID: "user-1", Role: "user"}, application.SignURLInput{URL: "https://cdn.example.com/a.mp4"}); err != domain.ErrForbidden {
+		t.Fatalf("expected forbidden for non-admin, got %v", err)
+	}
+}
@@ -2,26 +2,35 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	stdhttp "net/http"
 	"time"
 
+	"github.com/viralforge/mesh/platform/observability"
 	httpadapter "github.com/viralforge/mesh/services/platform-ops/M84-data-migration-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/platform-ops/M84-data-migration-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/platform-ops/M84-data-migration-service/internal/application"
 )
 
-type Runtime struct{ httpServer *stdhttp.Server }
+type Runtime struct {
+	httpServer *stdhttp.Server
+	telemetry  *observability.Telemetry
+}
 
-func NewRuntime(_ context.Context, configPath string) (*Runtime, error) {
+func NewRuntime(ctx context.Context, configPath string) (*Runtime, error) {
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, err
 	}
+	telemetry, err := observability.Setup(ctx, observability.ConfigFromEnv(cfg.ServiceID, cfg.Version))
+	if err != nil {
+		return nil, err
+	}
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{Config: application.Config{ServiceName: cfg.ServiceID, Version: cfg.Version, IdempotencyTTL: cfg.IdempotencyTTL}, Plans: repos.Plans, Runs: repos.Runs, Registry: repos.Registry, Backfills: repos.Backfills, Metrics: repos.Metrics, Idempotency: repos.Idempotency})
-	server := &stdhttp.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: httpadapter.NewRouter(httpadapter.NewHandler(svc)), ReadHeaderTimeout: 5 * time.Second}
-	return &Runtime{httpServer: server}, nil
+	server := &stdhttp.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: observability.HTTPMiddleware(cfg.ServiceID)(httpadapter.NewRouter(httpadapter.NewHandler(svc))), ReadHeaderTimeout: 5 * time.Second}
+	return &Runtime{httpServer: server, telemetry: telemetry}, nil
 }
 
 func (r *Runtime) Run(ctx context.Context) error {
@@ -40,5 +49,5 @@ func (r *Runtime) Run(ctx context.Context) error {
 	}
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return r.httpServer.Shutdown(shutdownCtx)
+	return errors.Join(r.httpServer.Shutdown(shutdownCtx), r.telemetry.Shutdown(shutdownCtx))
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/trust-compliance/M36-risk-service/internal/contracts"
 	"github.com/viralforge/mesh/services/trust-compliance/M36-risk-service/internal/domain"
 )
@@ -104,7 +105,7 @@ func (s *Service) publishDLQIdempotencyConflict(ctx context.Context, key, traceI
 		return nil
 	}
 	now := s.nowFn()
-	return s.dlq.PublishDLQ(ctx, publishDLQIdempotencyConflictRecord(key, s.cfg.ServiceName, nonEmpty(traceID, uuid.NewString()), now))
+	return s.dlq.PublishDLQ(ctx, publishDLQIdempotencyConflictRecord(key, s.cfg.ServiceName, observability.TraceParent(ctx, nonEmpty(traceID, uuid.NewString())), now))
 }
 
 var _ = time.RFC3339
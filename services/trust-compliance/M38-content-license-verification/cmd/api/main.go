@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"os"
 
+	"github.com/viralforge/mesh/platform/observability"
 	httpadapter "github.com/viralforge/mesh/services/trust-compliance/M38-content-license-verification/internal/adapters/http"
 	"github.com/viralforge/mesh/services/trust-compliance/M38-content-license-verification/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/trust-compliance/M38-content-license-verification/internal/application"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv("M38-Content-License-Verification", ""))
+	if err != nil {
+		slog.Error("telemetry setup failed", "error", err)
+		os.Exit(1)
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
+
+	logger := slog.New(observability.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})))
 	slog.SetDefault(logger.With("service", "M38-Content-License-Verification"))
 
 	repos := postgres.NewRepositories()
@@ -26,7 +35,7 @@ func main() {
 	router := httpadapter.NewRouter(httpadapter.NewHandler(service))
 
 	slog.Info("starting api server", "addr", ":8080")
-	if err := http.ListenAndServe(":8080", router); err != nil {
+	if err := http.ListenAndServe(":8080", observability.HTTPMiddleware("M38-Content-License-Verification")(router)); err != nil {
 		slog.Error("api server exited", "error", err)
 		os.Exit(1)
 	}
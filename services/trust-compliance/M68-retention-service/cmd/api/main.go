@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"strings"
 
+	"github.com/viralforge/mesh/platform/observability"
 	httpadapter "github.com/viralforge/mesh/services/trust-compliance/M68-retention-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/trust-compliance/M68-retention-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/trust-compliance/M68-retention-service/internal/application"
 )
 
 func main() {
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv("M68-Retention-Service", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
+
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
 		Policies:     repos.Policies,
@@ -33,7 +41,7 @@ func main() {
 	}
 
 	log.Printf("M68-Retention-Service listening on %s", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := http.ListenAndServe(addr, observability.HTTPMiddleware("M68-Retention-Service")(router)); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"strings"
 
+	"github.com/viralforge/mesh/platform/observability"
 	httpadapter "github.com/viralforge/mesh/services/trust-compliance/M69-legal-service/internal/adapters/http"
 	"github.com/viralforge/mesh/services/trust-compliance/M69-legal-service/internal/adapters/postgres"
 	"github.com/viralforge/mesh/services/trust-compliance/M69-legal-service/internal/application"
 )
 
 func main() {
+	telemetry, err := observability.Setup(context.Background(), observability.ConfigFromEnv("M69-Legal-Service", ""))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = telemetry.Shutdown(context.Background()) }()
+
 	repos := postgres.NewRepositories()
 	svc := application.NewService(application.Dependencies{
 		Documents:   repos.Documents,
@@ -35,7 +43,7 @@ func main() {
 	}
 
 	log.Printf("M69-Legal-Service listening on %s", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
+	if err := http.ListenAndServe(addr, observability.HTTPMiddleware("M69-Legal-Service")(router)); err != nil {
 		log.Fatal(err)
 	}
 }
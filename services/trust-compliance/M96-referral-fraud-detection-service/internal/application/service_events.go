@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/viralforge/mesh/platform/observability"
 	"github.com/viralforge/mesh/services/trust-compliance/M96-referral-fraud-detection-service/internal/contracts"
 	"github.com/viralforge/mesh/services/trust-compliance/M96-referral-fraud-detection-service/internal/domain"
 )
@@ -148,7 +149,7 @@ func (s *Service) publishDLQIdempotencyConflict(ctx context.Context, key, traceI
 	if trace == "" {
 		trace = uuid.NewString()
 	}
-	return s.dlq.PublishDLQ(ctx, publishDLQIdempotencyConflictRecord(key, s.cfg.ServiceName, trace, now))
+	return s.dlq.PublishDLQ(ctx, publishDLQIdempotencyConflictRecord(key, s.cfg.ServiceName, observability.TraceParent(ctx, trace), now))
 }
 
 func validateEnvelope(event contracts.EventEnvelope) error {